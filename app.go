@@ -0,0 +1,202 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package main
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/gxui/themes/dark"
+	"github.com/nelsam/vidar/command"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/command/input"
+	"github.com/nelsam/vidar/commander"
+	"github.com/nelsam/vidar/commander/bind"
+	cinput "github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/controller"
+	"github.com/nelsam/vidar/editor"
+	"github.com/nelsam/vidar/navigator"
+	"github.com/nelsam/vidar/plugin"
+	"github.com/nelsam/vidar/setting"
+	"github.com/nelsam/vidar/theme"
+)
+
+// app holds the windows that are open.  Settings and plugins are
+// shared by every window, but each one has its own commander, splits,
+// and navigator.  It's only accessed on the UI goroutine.
+type app struct {
+	driver  gxui.Driver
+	windows []*window
+
+	// active is the window that was last used.  Shared bindables
+	// execute commands in it.
+	active *window
+
+	// shared holds the bindables that every window is given.  They
+	// are created with the first window.
+	shared []bind.Bindable
+}
+
+// Bindable returns the bindable named name from the active window's
+// commander.
+func (a *app) Bindable(name string) bind.Bindable {
+	return a.active.cmdr.Bindable(name)
+}
+
+// Execute executes b in the active window.
+func (a *app) Execute(b bind.Bindable) {
+	a.active.cmdr.Execute(b)
+}
+
+// sharedBindables returns the bindables that are shared by every
+// window, creating them with theme the first time it's called.
+func (a *app) sharedBindables(theme *basic.Theme) []bind.Bindable {
+	if a.shared == nil {
+		a.shared = append(a.shared, command.SharedBindables(a, a.driver, theme)...)
+		a.shared = append(a.shared, plugin.Bindables(a, a.driver, theme)...)
+	}
+	return a.shared
+}
+
+// openWindow opens a new window.  The primary window restores its
+// layout from the session and saves it again when it's closed; other
+// windows start out empty.
+func (a *app) openWindow(primary bool) *window {
+	driver := a.driver
+	gTheme := dark.CreateTheme(driver).(*basic.Theme)
+	font := setting.PrefFont(driver)
+	if font == nil {
+		font = gTheme.DefaultMonospaceFont()
+	}
+	gTheme.SetDefaultMonospaceFont(font)
+	gTheme.SetDefaultFont(font)
+	syntaxTheme := loadTheme()
+	gTheme.WindowBackground = background
+	if syntaxTheme.UI.Background != (theme.Color{}) {
+		gTheme.WindowBackground = gxui.Color(syntaxTheme.UI.Background)
+	}
+
+	// TODO: figure out a better way to get this resolution
+	window := newWindow(gTheme)
+	window.app = a
+	window.primary = primary
+	controller := controller.New(driver, gTheme)
+
+	// Bindings should be added immediately after creating the commander,
+	// since other types rely on the bindings having been bound.
+	cmdr := commander.New(driver, gTheme, window, controller)
+	window.child = cmdr
+	window.cmdr = cmdr
+	a.active = window
+	var handler cinput.Handler = input.New(driver, cmdr)
+	if setting.Modal() {
+		handler = cinput.NewModal(handler, cmdr, setting.ModalKeys())
+	}
+	projTree := navigator.NewProjectTree(cmdr, driver, window, gTheme)
+	bindings := []bind.Bindable{handler, navigator.DependencyTOC{Tree: projTree}}
+	bindings = append(bindings, command.Bindables(cmdr, driver, gTheme)...)
+	bindings = append(bindings, a.sharedBindables(gTheme)...)
+	cmdr.Push(bindings...)
+
+	nav := navigator.New(driver, gTheme)
+	controller.SetNavigator(nav)
+
+	newEditor := editor.New
+	if !primary {
+		newEditor = editor.NewWithoutSession
+	}
+	projects := newEditor(driver, window, cmdr, gTheme, syntaxTheme, gTheme.DefaultMonospaceFont())
+	controller.SetEditor(projects)
+	window.editor = projects
+	window.projects = projects
+	watchThemes(driver, window)
+	watchConfig(driver, cmdr, window)
+
+	projectsPane := navigator.NewProjectsPane(cmdr, driver, gTheme, projTree.Frame())
+
+	nav.Add(projectsPane)
+	nav.Add(projTree)
+	nav.Add(navigator.NewSearch(cmdr, driver, gTheme))
+	nav.Add(navigator.NewBookmarks(cmdr, driver, gTheme))
+	nav.Add(navigator.NewTodos(cmdr, driver, gTheme))
+
+	nav.Resize(window.Size().H)
+	window.OnResize(func() {
+		nav.Resize(window.Size().H)
+	})
+
+	window.UpdateScale()
+	watchScale(driver, window)
+
+	window.AddChild(cmdr)
+
+	window.OnMouseDown(func(gxui.MouseEvent) {
+		a.active = window
+	})
+	window.OnKeyDown(func(event gxui.KeyboardEvent) {
+		a.active = window
+		if window.Focus() == nil {
+			cmdr.KeyDown(event)
+		}
+	})
+	window.OnKeyUp(func(event gxui.KeyboardEvent) {
+		if window.Focus() == nil {
+			cmdr.KeyPress(event)
+		}
+	})
+
+	watchDrops(driver, window, cmdr, cmdr.Bindable("focus-location").(*focus.Location))
+
+	window.OnClose(func() {
+		if window.primary {
+			projects.SaveSession()
+		}
+		a.closed(window)
+	})
+	window.SetPadding(math.Spacing{L: 10, T: 10, R: 10, B: 10})
+	a.windows = append(a.windows, window)
+	return window
+}
+
+// closed forgets w after it has been closed.  Vidar exits once every
+// window is closed.
+func (a *app) closed(w *window) {
+	for i, open := range a.windows {
+		if open == w {
+			a.windows = append(a.windows[:i], a.windows[i+1:]...)
+			break
+		}
+	}
+	if a.active == w && len(a.windows) > 0 {
+		a.active = a.windows[0]
+	}
+	if len(a.windows) == 0 {
+		if q, ok := w.cmdr.Bindable("quit").(*command.Quit); ok {
+			q.Cleanup()
+		}
+		a.driver.Terminate()
+	}
+}
+
+// after returns the window that was opened after w, wrapping around to
+// the first window.  It returns nil if w is the only window.
+func (a *app) after(w *window) *window {
+	for i, open := range a.windows {
+		if open == w && len(a.windows) > 1 {
+			return a.windows[(i+1)%len(a.windows)]
+		}
+	}
+	return nil
+}
+
+// saveSession saves the session of the primary window, if it's still
+// open.
+func (a *app) saveSession() {
+	for _, w := range a.windows {
+		if w.primary {
+			w.projects.SaveSession()
+		}
+	}
+}
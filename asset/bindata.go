@@ -1,11 +1,14 @@
 // Code generated by go-bindata.
 // sources:
+// bookmark.png
 // folder.png
 // icon.png
 // icon.svg
 // logo.png
 // logo.svg
 // projects.png
+// search.png
+// todo.png
 // DO NOT EDIT!
 
 package asset
@@ -73,6 +76,26 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
+var _bookmarkPng = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xea\x0c\xf0\x73\xe7\xe5\x92\xe2\x62\x60\x60\xe0\xf5\xf4\x70\x09\x62\x60\x60\x70\x00\x61\x0e\x36\x06\x06\x86\x55\x99\x85\xf7\x18\x18\x18\x2e\x79\xba\x38\x86\x54\xcc\x79\x73\xcb\x71\x55\x8b\x01\x0f\xdb\x83\xef\x4d\xec\x9f\xfe\x2d\xff\xe0\x66\xac\x5d\xce\x75\xc9\x30\x77\x55\x7c\x98\xac\xdb\xa5\x3f\xf2\xac\x82\x52\xf5\xfa\xfa\x0c\x0c\x0c\x0c\x15\xa6\x07\x36\xe9\x47\x65\xdf\xde\x76\x7e\x9f\x5c\xe0\xeb\x5d\xff\xde\x67\xab\x2f\x78\xc8\xc2\x40\x1c\x78\x70\x93\xe1\xb4\x6f\x6d\xdd\xbe\x87\xa6\xaf\xb6\x45\x86\x70\xd4\x7f\xf9\x7d\x91\x21\x3d\xd1\x5d\xc1\x65\xea\x87\xf0\xff\xf3\xa5\x1e\x64\xed\x98\xc7\x11\xa8\x5b\xf3\xfa\xf3\x52\xc6\xa8\xc3\x5f\x1c\x22\xb4\xd8\xec\x5f\x4d\xfc\x3a\xab\xe7\xd4\x43\xe3\xdf\x4b\x99\x52\x78\x67\x5f\x34\x4f\x93\x30\xda\x5c\x1f\x22\xb7\xee\xb8\x5d\x60\xe1\x8a\xae\xdf\x73\xaf\xad\xe8\x97\x28\x0d\x76\x75\xf2\x67\x60\x60\x60\x98\xc1\xf6\xc0\x9a\x41\x73\x92\xcc\x4f\x7e\xee\xf6\x19\x0c\x0c\x0c\x0c\x9e\xae\x7e\x2e\xeb\x9c\x12\x9a\x00\x03\x00\x48\x37\xcb\x01\x0b\x01\x00\x00")
+
+func bookmarkPngBytes() ([]byte, error) {
+	return bindataRead(
+		_bookmarkPng,
+		"bookmark.png",
+	)
+}
+
+func bookmarkPng() (*asset, error) {
+	bytes, err := bookmarkPngBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "bookmark.png", size: 267, mode: os.FileMode(436), modTime: time.Unix(1792156000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _folderPng = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\xec\x58\x69\x5c\x53\xd7\xb6\xdf\x01\x1b\x1c\x0a\xa8\x5c\x0b\xca\x64\x9f\x15\x5f\xb5\x0c\x16\x21\x20\xc4\x68\xb5\x70\x7d\x08\x38\x90\x04\x08\x93\x03\x22\x4a\xc2\x20\x61\x48\x4c\x88\xa2\xe2\x00\xa4\x5e\x2b\x54\x19\xa2\x52\x45\x72\x20\x01\x84\x04\xc2\x14\x6a\x05\x15\x25\x52\x39\x12\x81\x90\x28\x52\x30\x01\x64\xf0\x30\x04\x43\xde\xc9\xed\xef\x7e\x7e\x9f\xde\x7b\x5f\x2e\xfc\xce\xef\xb0\xff\x6b\xef\xff\x5e\x9b\xac\xf5\x5f\x6b\xe7\xf2\xfe\x00\x5f\xd3\xe5\xeb\x96\x03\x00\x4c\xf7\xfe\x7d\xcf\x41\x00\x8c\x80\xe1\x59\x8a\x45\x5f\x9f\xd2\xba\xb5\xe8\x0b\x7b\xc4\xcf\x77\x0f\xd0\x1b\x7e\x6f\x37\x95\x5e\x07\xc0\xb2\x74\xef\x9e\x5d\x41\x69\x85\x63\x8a\xf4\x97\xd1\xe9\xe1\xca\xc9\x54\x73\xd7\xe7\x2e\xae\xdf\xe5\x0e\x5e\xe2\xfc\xe7\x82\xd9\x81\xa5\xcb\xef\xae\xff\xfe\xf0\xe9\xcb\x5b\xce\x5d\x78\xb0\xf7\xce\x81\xec\xbd\x35\x47\x42\x0e\xf8\xed\xee\x7f\x76\x85\xb3\x4e\x7c\xdb\xc7\xf4\x87\xdf\x57\x27\xee\xfa\x62\xd7\xb6\xb8\xbb\x89\xd1\xab\x7c\x8c\x2e\x65\x8e\xba\x58\xbb\x3e\xff\xe3\x3a\x21\x20\xd0\xea\x56\x29\xb3\x78\xbc\x79\x76\x71\x9c\xc5\x16\x92\x61\x71\xf1\xf8\xfc\x60\x6a\xf3\xf0\xf3\xb1\x85\x05\xef\x98\xf9\x22\x83\x73\xff\x2f\x3f\x56\xaa\x97\x23\x58\xb3\x5f\x1e\xf2\x29\xd9\xb4\x23\xab\x7b\xc8\xf6\xeb\x14\x4b\xa4\x82\xd5\x80\xd6\x5b\x4a\x9e\x2d\x1b\x3f\xa8\x10\x48\x58\xc8\x53\xb8\x1e\xc7\xef\x0b\x12\x97\x07\xd8\xbf\x7a\xd7\xed\x04\x39\x11\x0e\xe6\x93\xf2\x6d\xe6\xa7\xbd\xf8\xa9\x76\xf3\xf3\x17\x37\xd0\x87\x6e\xdb\x61\x44\xb9\x60\xa2\xca\xe9\xc1\x8e\x52\xc6\xdf\x91\x37\xb0\x31\x9f\x62\x3a\x76\x89\xe8\x19\xd6\x74\x4f\xfb\x4a\xed\x06\x43\x50\xcb\xf6\x29\x25\xe6\xbe\x2b\x13\xd4\x85\x20\xe9\xf0\x51\x68\x05\xc5\xe1\xf3\x1b\xee\xd3\x7d\x03\x91\x4d\xe5\x8c\xbd\x08\x05\x6e\x68\x17\x19\x57\x7a\xba\x06\xa4\x64\xf1\x65\xc2\xe2\x20\xd6\x5e\xfa\xa2\x3c\x45\xbe\x3a\x0b\x22\x93\x5a\x22\xf0\xa5\x1b\x6d\x40\x02\x38\x73\x04\xb1\x87\x70\xe2\xfe\x9d\xf7\xbb\xab\xd4\xfc\xbc\x95\x13\xf5\x98\xca\x87\xe3\x07\x75\xc7\x3a\x72\x0e\x97\x6c\xfa\xaa\x96\x89\xf4\xaa\xa1\x50\x27\x4b\x4c\xa5\xb1\xdd\x7e\x5d\x4a\x47\x99\xa2\xeb\xfa\xa5\x5e\x1d\x83\x89\x74\x7b\x7f\xc0\x54\xd6\x3a\x3d\x60\x4b\x24\x92\xc9\xeb\x17\xb7\xa7\x21\x12\x75\x22\xa6\xd2\x8d\x16\xc1\xf2\xed\x78\xe7\x52\xb2\x69\x19\x7d\x42\x33\x0b\x31\xbf\x90\xee\xe7\x9e\x4c\x6f\x63\x7e\xfa\xf5\xf6\x9f\xfc\x53\xfc\x98\xda\xef\xb0\x5f\x48\xb1\x45\xe1\xba\x3d\xf4\xf0\xc1\x4d\xc6\x6e\xf1\x4d\x65\x8c\x13\x20\xee\xf7\xdd\x7c\xb1\xfb\x62\x47\x64\x99\x86\x42\x73\x67\x91\xe8\x3e\xa0\xfc\xc5\x21\xca\x6b\xba\xbd\xee\x9e\xb0\xbb\x0b\xa9\xce\xb3\x3e\x80\x09\xbe\xf3\x10\x8a\xa4\x7e\xce\x6f\x3c\xd8\x0e\xcd\xd6\x94\xbb\x2f\xab\xac\x4d\x89\x4d\xbc\x3e\x0d\x59\x0e\xad\xfa\x4e\xa4\xf4\x84\x2d\x42\x9d\x0e\xe8\xc6\x54\x11\x60\xa6\x66\x0b\x46\x45\x83\x79\x41\xb3\xf6\x8a\xa5\xa3\xaf\x3e\xc0\xf9\xb5\x8d\xcc\x77\x43\x47\x29\x98\xb9\xb4\x86\x95\x60\x47\x09\x23\x96\x9e\xd5\x95\x23\x5d\xb6\x3d\x08\x7f\x77\x68\x14\xb6\xaf\x09\xb3\x03\xf2\xe5\x7d\x76\xc0\x29\x2e\x24\x61\xa8\x11\x35\x7d\x9b\x96\xd0\x11\x52\xae\x8c\x4d\x3f\xb6\x26\xaa\x8e\xca\xb6\x9d\x18\xe4\x0f\x8b\xfb\xe7\x4c\x4c\x5a\xe3\x6b\xfb\xad\x13\xe0\x11\x1f\x20\x72\x03\xde\x22\xc6\xe1\xe2\xb2\x3f\x55\xf3\x8f\xd4\x93\x8e\x1f\xf9\xc0\xea\xb8\xb5\x33\xc6\xbb\x66\x28\xe8\x43\x48\x89\xf5\x59\xb7\xba\xd3\x1d\x5b\xf8\x19\xae\xb3\x4b\x80\x31\x95\x02\xea\x62\x90\x77\xea\xd7\x06\x5c\x12\xd9\x51\x72\xce\x79\xad\x62\xa9\x8b\x90\xba\x1c\xf0\x42\x75\x89\xf4\x3e\x74\x4f\xac\x77\x8d\xe4\x2e\x03\xa5\xe6\x78\x52\xfd\x40\x5d\x74\x87\x62\x71\xd6\x4a\xb1\x34\xee\x65\x09\x1f\x47\x8a\x47\xff\x72\xe1\x51\xeb\x96\x4d\x1c\x87\x3f\x92\xb8\x76\x8a\xa5\x5f\x26\x10\x91\x09\x75\x6d\x89\x35\xe6\xcf\xa4\xde\x11\x2c\x5f\x5f\xf8\x94\x97\x5e\x04\x27\xed\x56\x8d\xf9\xe0\x8c\x9e\x47\x7f\x5a\xcb\xf1\x15\x97\x7f\x6c\x31\xdd\xb5\xc4\x07\x8d\x88\x5f\xf2\x7a\x96\x1b\x97\x2b\x9e\x30\xc1\xb8\x7c\x6a\x5f\xcd\x05\x76\x54\xfa\xb6\x09\x49\x5e\x6a\x04\x28\x57\x3c\x70\x05\x59\x7c\x65\x47\x59\x46\x5d\xd5\x29\x7e\xd6\x10\x1b\x44\x19\xfb\x14\x31\x32\x4e\x3a\x64\x78\xf4\xae\x85\x85\xc7\x56\x7d\x87\x02\x61\x46\x75\xac\x0e\xff\xc0\x18\x33\xb9\x7b\x5c\x43\x60\x5c\xfd\xdf\x26\xa6\x5a\x59\xc0\xa4\xff\x92\x1f\x90\x95\x15\x6f\x8b\x6a\xa8\xdc\x22\xe0\x8f\x53\x9a\xa2\x93\x37\x48\x5b\x6b\x3f\xf8\x01\xee\x93\xf7\xe6\xb8\xa1\x72\x75\x48\x49\x01\x30\x13\xf9\x47\x18\xa1\xa1\x7a\xee\x3b\x64\xc5\x42\xe2\xac\x5a\xc6\xbf\x8a\x3a\x69\xb3\xaf\xc0\xdb\x78\xe2\xbc\x1a\x63\xdd\x6f\x37\x2a\xb1\x3f\xf1\x39\xd4\x56\x8a\xa9\x2d\x73\x05\xb8\xf8\xf9\x31\xbd\xbb\x75\xaf\x9d\xcd\x12\x31\x35\x9d\x2c\xd5\xf8\xa2\x93\xbd\x0b\xb4\x63\xef\x34\xfc\xc8\xfe\x97\x22\xf7\xa1\x0d\x70\x1f\x24\x26\xe6\xdb\x94\x99\x93\xc9\x66\xa3\xb1\x0e\x59\xa1\x46\x43\x9e\x8e\x57\xf7\x2a\xee\x68\x6b\xd4\xe7\xae\xf9\x57\xb4\x6a\xa0\x85\x98\x0b\x3b\xa3\x4c\x6c\x4f\xce\xaf\x6e\x60\xb7\x6b\x9e\xe5\xb5\x5b\xb6\xd4\x4a\x64\xff\xc5\x5d\x09\x25\x6b\xd4\x57\xf9\x31\xa2\x30\x82\xbd\xae\x3e\xe4\x47\xd6\x24\x8a\x49\x2f\x7a\x8b\x26\x23\x6f\x97\x1e\x14\x0a\x8a\xff\xf8\xbe\xaa\x92\x69\x17\xa6\x4b\xa3\xa7\x0f\x12\xfc\x36\x51\x9c\x23\x91\x5d\x18\xf9\x79\x4b\xdc\xe2\x8a\x4f\xc7\x5a\x92\xed\xeb\xb5\x8f\x5a\xd7\x0f\x93\x03\x85\x4e\xa7\x3e\xc7\x68\x8e\xa9\xcf\x42\x84\x6f\x80\xe6\xb4\xb0\x2d\x23\xb1\xf8\x44\xd3\x1d\x46\xf7\x76\x33\x2c\xe5\x6d\x63\x5e\xcc\xa8\x90\xfa\x39\x66\x6c\x8f\xfa\x8d\xc6\xc3\x0a\x68\xa6\x32\x73\xf4\xef\xbc\xaf\x31\x68\xf4\xf1\xd0\xdc\x25\x7d\x7f\xae\x80\xcc\xb7\x2d\xba\x8a\x95\x92\x6d\x3c\xff\x28\xdf\x42\x52\x78\xff\x9c\x73\xa7\xae\xdb\xcd\x11\x37\xbb\x35\xdf\x8b\x9c\x01\xcf\xc9\x94\x6c\xcd\xb7\x1a\xcf\x2f\x01\x84\x5c\x6a\xcb\x38\x3c\x5e\xea\xf4\xb4\x76\xf1\x26\x9a\x3e\xb4\x41\x42\xd6\x42\x99\x65\xc5\x07\x2b\x50\xde\xed\x6a\xad\xbf\x90\x7e\x88\x1e\xdb\xb1\xd0\x73\xb3\xef\xa6\xe3\x0a\x62\xce\xa2\xf7\x40\x50\xf8\x8d\xd2\x03\x1c\xdb\x09\x11\xba\xce\x4e\xb8\xe3\x60\x78\x9d\xa0\x28\x66\xb8\x30\xa4\xa9\x7c\x92\xd7\x51\xc0\xdf\x54\xae\x01\xa0\xfc\x37\xfc\x3a\xfd\x8f\xe9\x8d\xdb\xc3\xf0\x89\x54\xa6\x4c\x20\xa1\x23\x3d\x39\xfa\x33\x76\x42\x36\x9b\xca\x34\xaf\x34\xd2\xa3\xff\x91\x9f\xdb\x21\xfb\x50\xa5\xe0\xe1\xe0\x78\x70\x93\x70\x92\xd7\x75\x14\x26\x08\xe2\x7b\x30\x01\x8a\xf8\x6d\x8b\x57\xa7\x82\x11\xbe\x3a\x1b\x19\xdb\x6e\x22\x25\x6f\x5b\x3c\xcd\x68\x94\x68\xb4\x29\xb6\xaa\xee\xc6\x86\xc9\xc8\x7f\x30\xe1\x1b\xb0\xe2\x75\x9d\xd2\xc6\x46\x76\xea\xf3\x30\x12\x8c\xfc\xec\x36\x8e\x93\xee\x6d\x38\x34\xd8\xb2\x9e\x4b\xd4\x47\xe7\x2c\xbc\x68\x4d\xea\x53\xef\xce\xd1\x3f\x1f\x27\x12\x1e\xb6\xe9\xb0\x1e\xb1\x7d\x0c\xed\xa7\xdd\x70\x85\x61\x95\x37\xa1\x7b\x4e\x36\x16\x81\x0c\x6b\xfa\x63\xa3\xcc\x4a\x2b\x0e\x3f\x5f\x40\x1f\xe2\xdc\x64\xde\xf0\xe2\xe1\x96\xe2\x03\xac\xb4\x64\x0b\x66\x5d\x20\xfd\x61\x97\x3f\x68\xa0\xda\x62\x54\x03\xa7\xac\xa6\x83\xc2\x29\xba\xee\x11\x07\xb9\xe7\xc7\xd8\x74\xc2\xef\x22\x4f\x48\x5b\xf3\x2f\x73\x84\xc1\xdc\xf4\x2b\xa3\x07\x35\xef\x6d\xf1\x96\x9c\x3d\x2a\x6f\xcb\x88\x91\x9a\x85\xf8\x82\xba\xc1\x75\x6d\xde\x44\xd9\x5a\x05\xf0\xd2\xac\x81\xbd\x7c\x70\x55\x26\x31\x80\x73\xa8\x5a\x0d\x12\x22\xe8\x1d\x6b\xb8\xe9\x7c\xb3\xe1\x8d\x9b\x0b\x1b\xa9\xba\xcf\x71\x47\x5f\x62\x53\x9e\xf0\x8d\xa5\x63\x24\xef\x81\xcd\x8d\x55\x5a\x15\x4a\x17\x2b\xb4\x51\x48\xed\xc6\x5d\x24\x98\x28\x71\xc5\x76\x10\x37\x78\x34\x45\xb8\x0e\x25\x8b\xb7\x26\xec\x1e\xe6\xd2\x80\x9c\x0a\x99\x81\x61\x41\x5a\x23\x71\xae\xa1\xcb\xb4\x72\x7a\xa0\x77\x6a\xf3\x00\x35\x69\x60\x04\xcb\x51\x88\xd0\x4d\x3a\xaf\xb4\x53\x2e\x88\xb3\x69\x9c\xa2\xd4\xba\x21\xe4\x75\xdf\x7b\x0b\x1f\x1c\x18\x15\xad\xed\x04\x61\xde\x12\x8c\xd9\x79\x2a\x73\x85\xd2\xf1\x2f\xc8\x48\x3a\x54\x62\xad\xda\x59\x17\x97\x6c\xec\x5b\x3f\x6f\x20\x70\x7f\xb4\x91\x43\x0d\x7b\x76\x43\x71\xfa\xf1\x08\x36\xf7\x3f\x00\x28\x3d\x06\x63\x54\xd9\x6b\xb8\xe2\xe5\x3d\x28\xa5\xf1\x5b\x75\x97\xa9\xcd\xe3\x06\xc3\x79\x72\x6f\x00\x0c\xc7\x71\x13\x00\xd2\xf3\x4b\x00\xf8\xe5\x27\xb4\x64\x59\xac\x06\x20\xea\x07\xb4\x62\xee\xfb\x3b\x00\x9c\x65\xcb\x00\x70\xb9\xf3\xca\x17\x07\x0a\x7f\x3e\xcd\x37\xcf\xa6\x19\x61\xeb\x9b\xb5\xfb\x3b\x56\x2b\xf2\xed\xcb\xfc\x20\xbf\xdc\xcf\xf3\xb6\xb0\x17\xf7\xf3\x99\xdd\x48\xeb\x56\x2a\x0d\x87\x01\x29\xaf\xee\x34\xae\xfa\xa9\xbe\xe8\xc9\x96\x2a\x67\x41\x04\x59\x17\x8b\xdc\xda\x40\x28\x35\x7f\x89\x32\x9a\x28\xc6\xb8\xdd\x25\xdc\xcf\xba\x04\x84\x0c\xfb\xe5\x76\x77\x40\x9a\x8b\xc9\xee\x3f\x31\xd1\x9d\x5f\xc4\xc2\x31\x24\x65\x88\xee\x70\xc7\xd0\x39\x3d\x89\x45\xeb\x78\x53\xfb\xed\x52\x00\x9e\x8f\x9f\x53\x17\x40\x84\x32\x27\x7e\xb3\x58\x32\xf8\x32\xd7\x7d\x27\xea\x13\x57\xe2\x87\xe8\x60\x0a\xa4\x14\xd8\xb9\x89\x8a\x90\x55\x00\xa8\x06\x8b\x48\xac\xc3\x1d\x78\x23\x67\xc1\x0e\x66\x9c\x17\x64\x38\xd0\x64\x78\x70\x93\x44\x5b\xa4\x6e\xce\x64\x86\xac\xc5\x00\x30\x33\x3c\x09\x2b\x83\x5a\xc2\x58\xcf\x54\x11\x01\x37\x01\x58\x38\x9d\x96\x6c\x2c\x25\x79\x04\xe1\xeb\xa3\xc3\xcf\x0a\xe3\xb3\x78\xeb\x56\x82\xa8\x0c\x81\x95\x02\x54\x12\x3b\x21\xf6\x1d\xb4\x26\x0c\xb7\x36\x15\xfe\x0d\x83\x2e\x9d\xea\x32\x05\x94\x0f\xd3\x94\x4e\xec\x99\xbe\x11\x9f\x80\x9f\xd1\xe5\xd3\x03\x06\x4c\xf3\x15\xec\x4a\xe9\x5c\xda\x7f\x24\xc1\x79\xc1\x01\x48\x17\xc3\xb6\xa1\x1f\xb9\x86\xc5\x42\xf2\xe1\x26\x1f\x5c\xb5\xbd\x11\x18\x95\xe9\x6d\x51\x4a\x8b\xf1\xae\xea\xbb\xaa\xe1\x1c\xda\xfb\xfb\x4b\x41\x25\x21\xfe\x59\xdf\x23\x8e\xce\xb3\x25\x1a\x73\x67\x53\x2e\xed\x7d\xda\x12\x20\xe7\x69\x20\x72\x0e\xcd\x48\xbe\x16\x27\x2c\x3e\xc4\x6a\x1f\xf1\xc9\xfd\x11\x70\x74\x0b\x25\xe8\x36\x5e\x43\xfb\x91\x12\x38\x95\x4b\x3b\xe6\x8c\x12\x2a\xf5\xcf\xce\xed\x94\x5e\x66\x32\x10\x0b\xea\x9b\xc2\x06\xe2\x0b\x69\x5e\x43\x1d\x63\x7e\xc4\x81\x53\xf4\xa9\x46\x5d\xda\x77\xf7\x86\xf1\x17\xc0\x63\xab\xcc\x70\x92\x87\xc5\xa1\xba\xf7\x23\x0e\xb4\xf5\x40\x7a\x39\xfd\x00\xd2\x86\xc6\xbe\x97\xa6\x00\xd2\x6f\x93\x5c\x5e\x0d\x54\x7b\x6c\x05\xcd\xf1\xc9\x16\xd2\x00\x94\xcb\xad\xa4\x60\x25\x50\xfd\xae\x0b\xed\x70\x6d\x06\x9b\x06\xf7\x23\xd3\xf0\x23\x5f\xf7\xaf\x81\xf4\x69\x7a\xe8\xc0\xca\xa5\x85\x8d\xae\x50\xa4\x70\x07\x2b\xc8\xd8\x08\x14\x16\xe2\xc8\x4a\x17\xc9\xb2\xa8\xd2\xa2\x48\x5d\x04\x1d\x5a\x65\x6d\x09\x54\xef\x75\xa1\x88\x2b\x2a\xec\x29\xbd\x78\xca\xeb\xa5\x72\x0f\x0c\x08\x78\x21\x0e\x92\xd9\x2b\x96\x54\xbe\x6a\x16\x31\x9e\xdd\xc7\x6d\x06\xd2\x32\xcf\xfe\x29\xf3\xd9\x40\x54\xe9\x47\x6b\x62\x48\x3c\xd1\x53\xd3\xe5\x20\x41\x2d\x28\x77\x8a\xab\xff\x06\x24\x7c\x4f\x3b\xa4\x3b\x68\x1d\xf6\xed\x7e\xc0\x81\xeb\x24\x43\xf5\x03\xfa\x1d\x92\x55\x51\x31\xf8\x46\xad\xc4\x9f\xba\x0f\x05\xeb\x45\xd1\x65\xb2\x4d\xe4\x7c\xd3\xf1\x2f\xe4\xfe\xfa\x5a\x49\x94\x85\xf5\x6a\x10\x85\x2c\xc6\x20\x6b\xe1\xab\x68\x45\x18\x2d\x0c\x7c\xc0\xfe\x0b\xc4\x0f\x1c\xc4\x4b\x18\xef\xae\x11\x8c\x47\x7b\x70\x41\xe6\x61\xb3\x3e\x4b\x40\xb0\x70\x98\x38\xfb\xa0\x38\xa8\x29\x25\xc1\x59\x4a\xf3\xa8\xd0\x76\xf9\xc7\x2d\x09\x16\x6a\xf8\xc3\x24\x1e\x51\xd1\xa4\x2d\x56\x73\x21\x73\x6e\xdc\x72\xf9\x49\x5e\x24\x3e\xa9\xc8\x3a\x18\x19\xc4\x2a\x89\x3a\xd5\x95\x76\x6f\xa2\x18\x5a\x8c\x4d\xb6\xa8\xcc\x72\xe5\x2f\x10\x53\x4f\x2d\xc3\xa6\x3e\xe8\xa4\x85\x6c\xb0\xf1\x10\xed\x76\xe4\xbe\x28\x7a\x35\x15\xff\xa7\x19\xbc\xc9\x6c\xec\x04\x3e\x22\x04\x1f\x17\x9e\xc9\x6b\x16\x9c\x3d\x5b\x90\xec\x8f\x58\xfa\x2e\xf7\x70\x1b\x16\x9d\xff\x20\x83\x9a\x6f\x0f\xe1\x46\x4e\x67\xa5\x24\xab\x77\x94\x46\x67\xa4\x1c\xb9\x53\xf5\x91\x2f\x50\x56\x6b\x7f\xae\x1d\xdf\x0a\xfa\x46\x46\xd5\x10\xa4\x8c\x0d\xa9\x3a\xfd\x29\x2c\xb9\x1a\xf9\x8d\x68\xff\x0b\x1d\x0d\x6d\xb9\xfb\x6c\x45\xf1\xab\x24\x42\xe7\x88\xe7\x87\x3d\x1a\x49\x9e\xdf\xf9\x2d\xc3\x0e\x3b\x81\x99\xa4\xa7\xb6\xbc\x3b\xfd\xa3\xda\x1c\x3b\x9b\x74\x37\x00\x6a\x4e\x0c\x37\x36\x96\x7e\x39\x10\x84\x17\x69\x45\x79\x43\x0d\xa9\x8f\xaf\x87\x3e\xc2\x33\xd1\xa4\x73\x45\xf3\x75\x32\x8f\x3e\xab\x4f\x44\x43\x94\xde\x97\xa7\xaa\x3e\x68\x40\x19\xfe\xc8\x6d\xb5\xb3\x2f\xae\x3a\x30\x30\x14\xe1\xa0\xc8\x43\x46\x2a\xf2\x20\xcf\xa5\x72\xcc\x2e\xb2\xa8\xf6\x19\xe6\x0b\x00\x8e\xcf\x9c\x55\x27\x3a\xe2\x96\xd3\x1e\x52\x48\x96\x82\xa4\x9d\x18\xc0\x79\xc3\xaa\x64\xec\x43\x72\xbb\x72\xfa\xcf\xfb\x0a\x93\x5c\xd0\xb4\x52\xbf\x53\xaf\x80\xb6\xf8\xe2\x92\x6c\x50\x8e\xf2\x56\x65\x90\xd2\x53\x72\x79\xb0\xee\x54\x07\xd3\x9c\xf4\x83\x21\x59\xb5\x97\xd5\xf7\x4a\x0a\x3c\x72\x6f\x34\xd9\x18\x04\x85\x8b\xc4\xf3\xb9\xd9\xb4\x77\x56\x76\xd5\xfc\xb3\xa8\xe6\xcc\x05\x5b\x12\xf5\x47\x93\x2d\xda\x27\x6e\x13\x4d\xbf\x41\xf3\xb6\x7d\xe1\xad\xba\xb7\xcb\x7f\xf4\xed\x22\x7c\x87\xf8\xe5\x3f\x11\x26\x05\x39\x67\x40\xfa\x49\xc9\x1b\x6e\xa3\x12\xc5\x5c\xb8\x87\x2a\xd8\x23\x3c\x79\xeb\xa5\x7f\x8e\x74\xea\xf0\x92\x02\xf9\xa3\x22\xd2\x5f\xe3\x06\x01\xe3\xe9\x88\xc3\x8c\x0a\x15\xb2\xf2\x37\x7a\x3b\x83\x10\xd7\x5d\xd9\x85\xee\xe5\x21\x22\x67\xd3\xa2\xcc\x87\x85\x47\x56\xa2\x9e\xe7\x8e\xdd\x55\xdf\xea\xf2\xb7\x19\x6a\x24\x7e\x89\x8a\xa4\xb4\x2a\x9c\xd2\xc4\x4a\xb6\xf0\x9d\x3f\xff\xd7\x78\x52\xf7\x08\x95\xcf\xa4\xce\xcd\x58\x83\xc2\xcc\x39\x59\x2b\xa4\x07\x3a\xc3\x1e\xaf\x47\xcf\x69\xa3\x44\x35\xd7\xe3\xd9\x8b\xbf\x1c\x68\x17\x53\xe3\xb1\xde\xac\x35\x99\xa8\x60\xb9\x7c\x8d\x32\x3b\x7c\xf3\x3f\x08\xea\xbf\x27\xfd\x7b\xd2\xbf\x27\xfd\xdf\x4e\x42\xdb\xb0\x30\xf1\xbb\x49\xd2\xae\x27\x18\x55\x50\xe1\x0e\xc9\xd9\x12\x9b\x88\xc7\xeb\xa1\x62\x1b\x61\xf0\x64\x1a\x94\xd0\x39\x82\x05\xf2\xd8\x3e\x77\x6e\x58\xd3\x95\x5d\x24\x5b\xe0\x14\x69\x40\xc7\xff\x85\xc6\x45\x03\x79\x9b\x2b\xb0\xcc\xa6\x01\x4e\xd1\xcc\x53\x43\xf6\x13\x21\xab\x63\x17\x5d\x41\x2a\x5a\xdd\xd1\x9a\xba\xbf\x0f\xc7\x3d\x93\xb0\x26\xf3\xf9\x4a\x30\x8e\xb6\x68\x20\xe5\xf5\x17\x54\xe5\x46\x80\x89\xe2\xd9\xfd\x35\x24\xd8\xa3\x42\xb4\xa3\xf2\x9f\xe4\x03\xf5\xa8\x23\x6f\x5b\xe1\x37\x68\xbd\x8c\xda\x84\x3f\x93\x6c\xcc\x4c\x6b\xdf\x8c\x0d\xa1\x83\xb4\xf8\x2d\x12\xcf\xc3\x17\x3a\x1f\xd8\x6d\x97\x2c\x03\x29\x7f\x6c\xf0\xc5\x55\x66\x65\xa0\xaa\x83\xf6\x8b\x3d\xe2\xbb\xc7\xaa\x8f\x04\xda\x18\xf6\x1b\xb6\x83\x63\x72\x69\x47\xcc\xe3\x23\x1f\xaf\xd7\x04\x6f\xfc\xf8\xc2\xd0\xcb\xb2\xd2\xe9\xaf\x47\x1c\x80\xfc\xdb\xc0\x75\x0a\xe9\xd6\xf2\x63\xc5\xe1\xbe\x20\x4d\x67\xe8\x64\xc5\x82\x45\xd8\x60\x59\x1b\x43\x56\xa2\xa5\x1f\x1f\xf6\xea\x2b\x13\x0e\x4e\x60\xe8\x9e\x53\xe1\x17\x3e\x38\x8c\x34\x60\xae\x57\x74\xfa\xeb\x7a\x71\xcd\x79\x4e\x45\xb2\x3e\x8f\x89\x11\x55\x35\xb3\x73\x16\x4c\xa5\xad\x09\x29\x88\xa8\x4d\x16\xc2\x84\x85\x82\x78\x71\xa0\x22\x16\xce\x5a\x97\xf1\xd3\x0c\x7f\x96\xcc\xab\x69\x0e\x37\x51\x81\xb9\x6b\x6d\x32\x72\x3b\x3f\x3e\x64\x90\x2d\xfc\xdd\xcf\x3a\xe3\xa7\x5c\xa2\x2c\x62\x90\x6d\x5c\xd9\x37\xfe\x6a\xca\x63\x61\xa4\x5e\x1d\x8c\xdc\x46\xc6\xb4\x27\xcf\xa4\x0e\x9a\xff\x31\xf9\xec\x1e\x2a\xbd\x29\x1a\xf5\xdd\x36\xd9\x81\x76\xfe\xac\xd8\x78\xb6\x1a\xff\xeb\xa4\x89\x3a\x8d\x1e\xe2\xf7\xcc\x98\x13\x1a\xa1\x9d\x86\x72\x78\xbc\x5c\xb2\x73\x70\xdf\x80\xf0\xe5\x07\x47\xe7\x2d\x56\xac\xca\x5c\xb2\x7e\x3f\x4b\x2d\x31\x43\x8f\x3e\x94\x65\x4d\x70\x56\x04\xb1\xa6\x7b\x67\xd8\x8f\x8b\x5f\xcd\x65\xd2\x0f\x21\x17\xfb\xff\xb6\xd4\xa5\x41\xff\x72\x6e\x7e\xe4\xbe\x3a\x81\xde\x4e\xb7\xe5\x10\x17\xee\xb6\xd9\x57\xea\xba\x29\x50\x83\x10\xbd\x14\x55\x36\x05\x40\xc5\xf0\x5c\xfa\x09\x02\x72\xc5\xcd\x36\xb3\xd6\x03\x92\xd4\x4c\x9a\x50\x09\xf4\xc4\xbe\xfa\x01\x57\xa0\x49\x3c\x8e\x9c\xce\xe1\x65\xcc\x50\xf4\x6c\x7c\xd6\x61\x16\x5b\x84\x1a\x5b\xe8\xfe\x08\xab\x22\x86\xbe\x07\x88\xda\x75\xa1\x4e\x18\x6e\x71\xea\x0c\x85\x2c\x2e\x82\x56\xdb\xf5\xec\xb6\xce\x50\x05\xd4\xb0\x85\x92\x69\xf4\x6a\x07\x76\x2b\xca\x19\xa1\xf4\xbd\x83\xc3\xb7\x2c\xca\x2c\x05\x83\x51\x71\xbf\x69\xac\x33\x26\x02\x6a\x76\x84\xb1\x02\xe9\x90\x9b\x8d\x39\x70\xff\xf8\x16\xe6\xb9\xb1\xd9\x9e\x37\xb5\xef\xdc\x6c\xb8\x39\x73\xf7\xdb\xec\x1b\xaf\x57\x98\x93\x02\x65\x83\xe7\x40\xc3\x48\x76\xde\xd3\xe9\x35\x39\xbc\xc0\x00\xd1\x62\xb3\x6d\xf0\xe7\xe1\xe0\x4e\xc1\x40\xdf\x5c\x66\xda\x66\x7a\x38\xd2\x0e\x8b\x49\xe6\x27\xb2\xf4\x31\xc0\x26\x81\x17\xc4\xda\x8b\xb8\xc2\x0b\xeb\x08\x19\x85\x27\xf1\xd4\xda\xac\xe1\x12\xb3\x8a\x53\xaa\x92\x33\x19\x48\x93\x75\xef\x6b\x87\xb0\x50\x1d\xbd\xc3\xeb\xa6\xf3\x49\xcf\x43\x09\x7b\x3b\x4e\xdf\x02\x71\x6d\x9e\x8e\x2d\xc7\x06\xca\x24\x14\xfa\xc7\x0d\x04\xf3\x2b\xb3\x44\x5e\x44\x78\x55\x6e\x2b\x6e\x0d\xa0\xc1\x53\x8d\xba\x74\xf4\x52\x0d\x46\x25\xf1\x42\x36\xda\xb6\xc4\xab\x83\x66\x7c\x73\xe3\x34\x2b\xdc\x6b\xf0\x40\x1e\x6d\x49\x0e\x14\x08\xc2\x7b\xb0\xee\xf3\xcf\x17\x02\x49\x1f\x7b\x55\x33\x20\x8a\xac\xdb\xdf\x71\xea\x72\xcf\xf1\xeb\xb8\x87\xe1\xe1\x4d\xa5\xda\x3f\x4c\x69\x16\x20\xe5\x05\x8f\xac\x94\x27\x59\xa8\x7a\xe3\xd4\x76\x1c\xeb\x42\x12\xda\xf6\xdf\x36\x8d\xb7\x04\x29\x8f\x4b\xf8\xf9\x02\xe6\x86\x82\x88\x99\x99\x04\xc4\xd4\x31\xc6\xd8\xd0\x02\xac\xa0\x46\x4d\x09\xb7\xca\x1e\xb6\x94\x46\x27\x5e\xcc\x32\x97\xbb\x83\x38\xe8\x2a\xe5\xb5\xcb\xdc\x13\x07\x77\xd1\x07\x3f\x80\xf3\x80\xb4\x04\x47\x59\x9c\x11\x40\x88\xf0\x34\xae\xb6\xea\x40\x7c\x73\x03\x5f\xfb\x4c\xfd\x6c\x57\x9f\x09\x87\x3a\x35\xea\xee\x31\xdf\x23\xdf\x48\x55\x52\xc2\x23\xf0\xc7\xa3\xcc\x81\x4b\x6f\x01\xdf\xf9\x54\xba\xa3\xae\x2d\x3b\xe2\xd5\xa2\x8c\x6f\x42\x4a\xb5\xef\x37\x01\xd9\x09\x44\xe4\x47\xb8\x0f\xd7\xf4\xf6\x40\x3e\xa0\x25\xd4\x31\x8e\x20\xe2\xcc\x05\xc0\xf1\x6f\x68\xd6\xde\x42\x6f\x8d\x38\x5a\x32\x78\x30\xf4\xbd\x94\x4c\xb2\xb7\x52\x2c\x05\x71\x2f\x5d\xf9\x4a\xab\xda\xc9\x03\x81\x98\xd1\xa1\x5d\xb0\x3d\x71\x38\x97\xb6\x04\xbc\x1e\xb5\xe4\x67\x28\x6e\x91\x17\xbc\x8a\xa9\xcb\x01\x91\x5b\x1e\x11\x54\xe4\x29\x59\x05\x82\xaf\x2b\xcb\x9b\xef\x4a\x68\xc7\x2c\x4a\xe2\x8d\xbc\x7b\x29\x50\x4b\x7c\xbd\x75\x52\xdf\x6f\xcb\x40\x21\x9f\x4c\xd2\x57\x3b\x06\xaf\x4a\xed\x33\xda\x09\x6b\xf8\xb3\x44\xae\x3d\x4a\x5f\xfe\xd2\x9a\xef\x4b\xce\xcf\xa5\xfd\x2a\x72\x05\xdd\x53\x69\xaa\x10\xf8\x11\xda\x09\x83\x2d\x13\xa2\xbc\xa7\xfa\xa2\xcc\x85\x39\xbd\xc8\x0d\x35\x7c\x7f\x7e\x96\xc4\x7d\x31\x04\x40\x4a\xcf\x29\xbe\x6f\xcd\x8f\xd7\x33\xa8\xa9\xa8\x9b\xfb\xa4\x4a\x51\xa3\xcd\xdb\x4f\xad\x4d\x68\x2f\xaf\xcd\xcc\xbb\xc0\x76\x5a\xab\x78\xd2\x25\x3a\x6c\xcb\x29\x98\x3a\xa7\x4e\xa4\x3e\x75\xb3\x00\xf2\xd2\x61\x62\x64\x7f\xf5\xc0\x8b\xd7\x6f\x2e\x80\xfd\x1d\x5a\xc0\x7d\x71\x64\x17\x18\xbd\xc8\x13\x16\xf7\x7f\xd5\x3f\x96\xf1\x5d\xc3\x6a\xc0\x8f\x20\xe2\x6f\x33\xc6\x46\x7c\x80\xca\x4a\x20\x68\xae\x8a\xbe\xc6\x3b\x65\x32\x9b\x14\xd6\xc7\x36\x97\x76\x39\x55\x14\x53\x14\xb5\x12\x99\x2a\x02\xa8\xda\x06\x28\xac\x58\x44\x53\x62\xfd\x86\x1b\xbe\xdd\x38\x21\x73\x0b\x7f\x53\x50\xe4\x0d\x3f\x23\x10\x97\xa5\x2c\x1f\x0f\x6f\x8a\x4b\xde\xb0\x50\x7b\x15\xa3\xc2\xca\x84\x3b\x20\x06\x82\x12\xfe\xf2\x3a\x0b\x8a\xa7\x86\xb4\xce\xb0\x32\x99\x9e\x5f\x37\xa4\x2c\x53\x9d\xb0\x3f\x91\x7e\xe7\x37\x2f\x4a\x71\xd5\x27\x33\x0c\x70\x1f\x12\xf3\x33\xba\xcc\x74\xaf\x33\x8e\x36\x9c\xc1\xaa\xb0\x3d\x64\x02\x19\x5f\x39\x94\xee\x07\x82\x4b\x67\x89\xca\xee\xea\xfb\x7a\x82\xe9\xf8\xd0\xaf\xb6\xe7\x41\x42\xe6\x55\x7e\x7b\x4d\xe3\x6e\xd5\x47\xec\xac\x91\xb4\xf5\xb4\x3f\x92\xad\xa6\xc1\x32\xf7\x09\x62\x33\x94\xb0\x3f\x5f\x8e\xaf\xd0\x4e\xa9\x3f\xc0\x78\x83\x95\xd6\xc0\x67\x90\x90\xa3\x30\x13\x3b\x5b\xfd\xbd\x89\x1b\xe8\x57\x65\xab\x4f\x41\xf1\xc2\x71\xb7\x3a\x34\x04\x3b\x88\xb0\x30\x88\xd0\x9b\x94\x37\xbf\xd3\x73\x04\xf5\x36\x2b\xd4\xc9\xf4\x8c\x1f\xfd\x70\x43\xc6\xf4\x06\xa0\x99\xce\x55\x5b\x42\x3c\xfe\x00\xbe\x2e\xbf\xf6\xe6\x46\xd0\x2f\x1f\xa3\xe4\xc5\x7c\xbe\x65\xb8\x37\x62\xa2\xbc\xc2\x48\x4d\x95\xda\x7b\x79\x0d\x5a\x73\x2e\x35\x46\x77\xfc\x4b\x80\x4b\xe1\xa9\x21\x4a\xa7\xd9\xe7\x87\x36\xe6\x9c\x9b\x8d\x2d\x43\xf0\xb4\x4c\xc8\x16\x4b\x92\xe2\xbc\x0a\xa2\x7f\x5b\x78\xb0\x82\xef\xfc\xc0\x8e\x84\xff\x55\x12\x19\xb1\xeb\xcb\x84\x9f\x1a\x21\x86\x7f\x87\x51\x92\x33\xb4\x78\x77\x19\xfb\x15\xe1\x2b\x8c\x4a\xde\x59\x36\x1e\xa2\xd0\x3d\x91\x79\x91\x53\xb7\x1f\xe4\xfd\xe3\xa8\x49\x65\x8e\xe0\xc1\x00\xa3\x84\x31\x7d\x8d\x71\x08\x31\x37\x5e\xe8\xdd\xee\x50\x75\x3d\x55\xa3\x76\xf7\x65\x17\xa7\xbf\xdf\x04\x0a\xa6\xaf\xe5\xdd\x4b\x73\xae\x70\x2a\xdb\xd1\xfd\x55\x0b\xdb\xf2\x7f\xf3\x0b\x6b\x8e\xf1\x07\x3d\x06\x17\xb6\xfb\x6d\x4c\x52\x46\x88\x01\xd8\xfb\x63\xc0\x1e\xc1\x0f\x51\xe7\xfe\x3b\x00\x00\xff\xff\xd5\x35\x1a\x6d\xf2\x17\x00\x00")
 
 func folderPngBytes() ([]byte, error) {
@@ -193,6 +216,46 @@ func projectsPng() (*asset, error) {
 	return a, nil
 }
 
+var _searchPng = []byte("\x1f\x8b\x08\x00\x00\x09\x6e\x88\x00\xff\x01\x6e\x05\x91\xfa\x89\x50\x4e\x47\x0d\x0a\x1a\x0a\x00\x00\x00\x0d\x49\x48\x44\x52\x00\x00\x00\x40\x00\x00\x00\x40\x08\x06\x00\x00\x00\xaa\x69\x71\xde\x00\x00\x05\x35\x49\x44\x41\x54\x78\x9c\xec\x9a\x5d\x53\x94\x37\x14\xc7\x7f\xb5\x68\x95\x82\x48\x11\x87\x82\x50\x90\xd6\xd2\x96\x62\xa6\xd2\xe1\x8b\x70\xdf\xe9\x17\xf0\x9a\xab\x5e\xf4\x8a\xeb\x7e\x8e\x7e\x90\x32\xb5\x4e\x10\xb4\x05\x0b\x58\x10\x74\x50\xde\x14\x91\x97\xc5\xe9\xa4\xf3\x4f\xe7\x21\x83\xc0\xee\x3e\x09\xec\xba\xc9\x64\x06\x76\xf3\x3c\xe7\xe4\x97\x73\x92\x93\x93\x3d\xc7\x7b\x5e\x6a\x00\x6a\x00\x6a\x00\x6a\x00\x6a\x00\xde\x67\x00\x75\xfe\x8f\xbc\x8a\xb5\xf6\x32\xd0\x02\x34\x01\x0d\xc0\xc5\x8c\x9c\x02\xb0\x0d\x6c\x02\x1b\xc0\x8a\x31\xe6\xa5\xbe\x3b\x95\xfa\x81\xff\xa3\x9c\x62\xad\xfd\x10\xe8\x02\x3a\x80\x36\xe0\x2a\x70\x45\x00\x2e\x05\x00\xde\x08\xc0\x3a\xf0\x02\x78\x06\x2c\x02\xf3\xc6\x98\x7d\xf5\xab\x1c\x00\xd6\xda\x1e\xa0\x17\xe8\x06\xae\x03\x9f\x02\xad\x40\x33\xd0\x28\x00\xe7\xd5\x7d\x4f\x00\x5e\x01\x6b\xc0\x73\xe0\x29\xf0\x04\x78\x0c\xcc\x18\x63\xe6\xd4\xf7\x6c\x03\x90\xa9\x7f\x0d\xf4\x01\x9f\x03\x37\x64\x05\xed\xc0\x35\xc0\x59\x85\x33\xef\x2d\xcd\xbc\x77\xb9\x7a\xc0\xb9\x89\x9b\xed\x65\x60\xc9\xcd\x3e\x30\x0b\xfc\x0d\xfc\x05\x3c\x4c\xe5\x1a\x25\x01\xb0\xd6\xba\x59\xbe\x05\xf4\x03\x5f\x01\x5f\xca\x0a\xae\x68\x36\x9d\x59\xaf\xc8\xcf\xb7\x34\xf3\xc8\x12\xea\xb5\x3e\xb4\xc8\x5d\xae\xcb\x1d\x66\x80\x29\xe0\x4f\x60\x12\x18\x37\xc6\x3c\x3d\x73\x00\xac\xb5\x9d\xc0\x6d\xc0\x00\x03\x82\xe0\xdc\xe0\x91\x66\x71\x41\x66\xfd\x5c\x03\x7b\x1d\x00\xf8\x58\xa0\x5a\xe5\x2e\x9d\xb2\x9e\x2f\x80\x39\x0d\xfe\xbe\xe3\x0c\xfc\x61\x8c\x59\xd0\xb3\xa7\x0f\x40\x33\x3f\x04\x0c\x66\x20\xb8\x58\xe2\x81\x66\xcf\x99\xf0\x3f\x5a\xd4\x96\xe5\xe7\x6e\xc5\xdf\xd5\x2b\x2e\x68\x61\x6c\x96\x9b\xb8\x45\xf3\x33\xb9\x90\xb3\xa2\x6f\x80\xb7\x7e\xf0\xc0\x5d\x60\x2c\xa6\x25\xd4\x1d\xfc\xf7\x58\x9f\xbf\xa5\x41\xdf\x16\x04\x37\xb8\x71\x60\xc2\xf9\x2d\x30\xed\x66\xd1\x18\xb3\xea\x9f\x0b\x8a\x03\xb1\xaa\x36\x63\xad\xfd\x44\xd6\xb3\xa4\x1d\xe1\xa5\x64\x0c\xaa\xff\x7f\xdb\xa6\xb5\xf6\x75\xac\x35\xe1\xc4\x00\xb4\xe0\xf5\xcb\xec\x8d\x06\x7f\x57\x33\xe5\x20\x4c\x16\x6b\xae\x02\xb5\x6a\xad\x5d\x0e\xdc\x65\x50\x32\xb2\x5b\xe6\x6f\xfe\xb9\xe4\x91\xa0\xb6\xba\x3e\x2d\x78\xfd\x7a\x6e\x3c\x30\xd3\x92\x7d\x55\xcf\x8e\x05\x40\xcf\x49\x96\x93\xd9\x27\x1d\xd2\x03\x50\x90\xd3\x9b\xf1\xd3\x1e\xf9\xfc\x84\x14\xbd\x77\x84\xc9\x17\x6b\x0d\xf7\xf4\xce\x09\xc9\xe8\x91\x4c\x27\xbb\x57\xba\xa4\x05\xa0\xbd\xbd\x5b\x2b\x75\xaf\x56\xfb\x29\xf9\xfc\x64\x1e\x83\x0f\x20\x4c\xea\xdd\x53\x92\xd5\x2b\xd9\xdd\xd2\x25\x39\x80\x0e\xed\xd5\x5d\xda\xbe\x7c\xc0\x32\x5d\x8e\xd9\x1f\xe3\x0e\xd3\x92\x31\x2b\x99\x5d\xd2\xa1\x23\xe8\x1e\x17\x80\x56\xfe\x36\xed\xd7\xed\x0a\x72\x16\xb4\xd5\xc5\x0c\x59\xe7\x24\x63\x41\x32\xdb\xa5\x43\x9b\x74\x4a\x03\x40\xd1\xda\x55\x05\x2d\xd7\x14\xe1\xb9\xe8\x6c\x31\x4f\xd3\x7f\x87\x2b\x2c\x4a\xd6\x33\xc9\x6e\x95\x2e\x2d\x41\xf7\xa8\x00\x9a\x64\x82\xcd\x8a\xed\x57\x14\xe1\x2d\x07\xfd\x62\x94\x65\xc9\x5a\x91\xec\x66\xe9\xd2\x14\xf4\x8b\x0a\xa0\x41\xad\x51\x41\xca\x86\xf6\xe4\xb5\x18\x7b\x72\x50\xd7\x24\x6b\x43\xb2\x1b\x33\xfa\x24\x03\x70\x51\xc7\xd9\x4b\x3a\xd4\x6c\x29\x58\xd9\x0c\xfa\xc5\x28\x9b\x92\xe5\xe5\x7a\x3d\x2e\x06\xfd\xa2\x02\xa8\x53\x3b\xaf\xb0\x74\x4f\x6d\xf7\x60\xb7\x28\x75\x37\x23\xaf\x20\x1d\xbc\x3e\xc9\x00\x54\x7d\x3d\x0e\x40\x41\x6d\x4f\xe4\xcf\xab\x5d\x08\xfa\xc5\x28\x17\x32\xf2\xea\x32\x96\x50\x38\xd8\x2d\x2e\x80\x6d\x1d\x48\xde\x28\x91\x51\xaf\xf3\x7c\xae\x0b\xd1\x3b\x6a\x83\x64\x79\xb9\x5e\x8f\xed\xa0\x5f\x54\x00\x9b\x6a\xaf\x94\xc6\xca\x6e\x8b\xb1\x4b\x76\xdb\xbb\x2c\x1d\xbc\x3e\xc9\x00\x64\xb7\xbd\x7d\x05\x21\x3e\x28\x8a\x5d\x7c\xf0\xd3\x22\xd9\xd9\x6d\x31\x19\x80\x15\x25\x2a\x7c\xf0\xe3\xc3\xe2\x0e\x25\x33\xa2\x54\xbd\xbb\xc3\x87\xbf\x99\xa0\xe8\x85\x74\x4a\x03\x40\x59\x18\x1f\xfe\x2e\xe9\x40\xd2\xa9\x34\x56\x94\xf3\xb9\x6a\x8f\x64\x74\x4a\xe6\x92\x0f\x8b\xf3\xce\x0c\x1d\x67\x01\x28\x26\x7f\xa2\xd4\xf5\xba\x8e\xa6\xee\x7c\x7e\x53\x09\xd2\x5c\xab\xb5\xd6\xe5\x05\x6f\x4a\xc6\x0d\xc9\x9c\x97\x0e\x8b\x41\xf7\x24\x29\xb1\x79\x5d\x5a\xcc\xea\x58\x3a\x24\xab\x70\xe6\xb8\xae\x7c\x5d\xd9\x07\x23\x6b\xad\x8b\xf0\xee\x00\xc3\x4a\xbb\xa1\x33\xc0\x98\x64\x3f\x96\x2e\x24\xb5\x00\x5d\x57\xcd\xe8\x7c\x3e\xa5\xa3\xaa\x9b\xa5\x6f\x95\xc0\xfc\x2e\xa7\xf5\xc0\x0d\xfe\x67\xe0\x7b\xe0\x23\xb5\x3a\xe5\x07\x0b\xba\x35\xda\x4f\x0e\x40\x10\xe6\x74\x63\xe3\x2f\x2d\xde\x6a\xf0\x3e\x3b\x3c\x54\x8e\x3b\xc8\xec\x87\x35\xe8\xb0\xb8\xcf\x46\x80\x1f\x64\x25\xc9\x5d\xc0\xd7\x87\xc1\x85\xe7\xa0\xda\xff\x97\x1d\xd6\xda\xe3\xd2\xe2\x87\xad\xf6\x3d\xf2\xf9\x81\x83\xdf\x1e\x0a\xc1\x3d\x33\x6a\x8c\xd9\xae\xc6\x8b\x91\x1f\x35\xd0\xa3\xea\x0e\x30\xea\x5a\x5e\x10\x8a\x02\x90\xe0\x6a\xec\x24\x16\x99\x2b\x84\xa2\x01\x44\xbe\x1c\x1d\x3c\x81\x15\xe4\x0a\xa1\x24\x00\x11\xaf\xc7\x0b\xf2\xf5\x64\x10\x4a\x06\x10\xdc\x1a\xe5\xf6\x03\x09\xb7\xda\xa7\x84\x50\x36\x80\xbc\x7f\x22\xa3\xad\x6e\x24\x15\x84\x5c\x00\x1c\xe2\x1a\x65\xfd\x48\x2a\x25\x84\xdc\x01\xe4\x55\x53\x41\x38\x51\x24\x78\x1a\x55\x83\x18\x55\xdb\x09\xbe\x3e\x2a\x58\x1a\x11\xbc\xca\x06\x90\x0a\xc2\x99\x06\x90\x02\xc2\x99\x07\x10\x1b\x42\x45\x00\x88\x09\xa1\x62\x00\xc4\x82\x50\x51\x00\x62\x40\xa8\x38\x00\x65\x42\xb8\x13\x7c\x5e\x99\x00\xca\x80\x30\xac\xec\x53\xe5\x03\x28\x11\xc2\x80\xf2\x0e\xd5\x01\xa0\x44\x08\x54\x15\x80\x22\x21\xdc\x57\xe6\xaa\xba\x00\x9c\x10\x82\x3b\x4f\xfc\x6a\x8c\x79\x50\x95\x00\x02\x08\x3f\x01\xbf\x6b\xd0\x3b\xfa\xdb\x7d\xf6\x8b\xef\x7b\xe6\x8f\xc3\xe5\x56\xad\xf6\x7e\xc1\x7b\x14\xce\x7c\xad\xa8\x9c\xab\xc2\x31\xd5\x00\x14\x03\xe0\xdf\x01\x00\x27\x8d\x45\x4e\xed\x56\x56\x4d\x00\x00\x00\x00\x49\x45\x4e\x44\xae\x42\x60\x82\x2e\x54\xb2\xcb\x6e\x05\x00\x00")
+
+func searchPngBytes() ([]byte, error) {
+	return bindataRead(
+		_searchPng,
+		"search.png",
+	)
+}
+
+func searchPng() (*asset, error) {
+	bytes, err := searchPngBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "search.png", size: 1390, mode: os.FileMode(436), modTime: time.Unix(1792155507, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _todoPng = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x00\x55\x01\xaa\xfe\x89\x50\x4e\x47\x0d\x0a\x1a\x0a\x00\x00\x00\x0d\x49\x48\x44\x52\x00\x00\x00\x40\x00\x00\x00\x40\x08\x06\x00\x00\x00\xaa\x69\x71\xde\x00\x00\x01\x1c\x49\x44\x41\x54\x78\x9c\xec\x96\x4d\xae\x83\x30\x0c\x06\x79\x28\x37\xe1\xfe\x47\xe2\x2c\xaf\xea\x22\x12\x8a\x30\x0e\x94\xaa\xf9\xf8\xc6\xdd\xa4\x52\xbb\xf0\x78\xfc\x33\x4f\xe6\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x60\x0b\xa0\xd4\xc7\x36\xd6\x75\xfd\xaf\xef\xde\x58\x96\xe5\x6f\xfb\xdf\xfa\x1d\x03\x14\x0d\xb8\x12\x57\xac\x91\x00\x90\xa9\xbc\x97\xb8\x8a\xfe\xb7\x1a\x70\x94\xfc\x08\x76\x44\x45\x61\x06\x64\x33\xa0\x56\xaf\x25\xd8\x56\x35\x22\xfc\x98\x2d\xf0\x4e\xb8\x26\xdd\x26\xaf\x1c\x29\x80\xac\xf2\x8f\x07\x60\x3f\x03\xaa\x05\x51\xcf\xf7\x18\xd1\x5a\x24\x05\x20\x4a\xb0\x27\x71\x5a\x40\xa0\x05\xec\x01\x4c\x18\x60\x6e\x00\x00\xdc\x01\x94\xbb\x56\xdc\xc8\xbb\x1e\x03\xce\x1a\x70\xa6\xb2\xea\x07\x11\x06\x64\x06\xdc\x11\x23\x58\x12\x99\x6c\x6f\x00\x00\x00\x60\x0e\xa0\xfc\x72\x00\x49\x00\x50\xdf\xf3\xb4\x40\xd2\x02\xf6\x00\x26\x0c\x30\x37\x80\x3b\x80\x3b\x60\xe7\x0e\xb8\xb2\xfa\x46\xde\xf5\x18\x70\x60\x00\x2d\xb0\xd7\x02\x67\xd4\x56\xbf\x14\x4b\xe7\xef\x3e\x8a\x11\x20\x45\x85\x64\x06\xb8\xcf\x00\xb6\x00\x5b\x20\xd9\x02\xdf\x1c\x40\x18\xa0\x60\x80\xfa\x9e\xc7\x80\xc4\x00\xfb\xcf\x0c\x00\x00\x00\x00\x00\xce\x00\x5e\x03\x00\xe4\x95\x5b\x5f\x72\xf5\x92\xd9\x00\x00\x00\x00\x49\x45\x4e\x44\xae\x42\x60\x82\x03\x00\x80\x64\x47\xc3\x55\x01\x00\x00")
+
+func todoPngBytes() ([]byte, error) {
+	return bindataRead(
+		_todoPng,
+		"todo.png",
+	)
+}
+
+func todoPng() (*asset, error) {
+	bytes, err := todoPngBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "todo.png", size: 341, mode: os.FileMode(436), modTime: time.Unix(1792157000, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -245,12 +308,15 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
+	"bookmark.png": bookmarkPng,
 	"folder.png": folderPng,
 	"icon.png": iconPng,
 	"icon.svg": iconSvg,
 	"logo.png": logoPng,
 	"logo.svg": logoSvg,
 	"projects.png": projectsPng,
+	"search.png": searchPng,
+	"todo.png": todoPng,
 }
 
 // AssetDir returns the file names below a certain
@@ -293,12 +359,15 @@ type bintree struct {
 	Children map[string]*bintree
 }
 var _bintree = &bintree{nil, map[string]*bintree{
+	"bookmark.png": &bintree{bookmarkPng, map[string]*bintree{}},
 	"folder.png": &bintree{folderPng, map[string]*bintree{}},
 	"icon.png": &bintree{iconPng, map[string]*bintree{}},
 	"icon.svg": &bintree{iconSvg, map[string]*bintree{}},
 	"logo.png": &bintree{logoPng, map[string]*bintree{}},
 	"logo.svg": &bintree{logoSvg, map[string]*bintree{}},
 	"projects.png": &bintree{projectsPng, map[string]*bintree{}},
+	"search.png": &bintree{searchPng, map[string]*bintree{}},
+	"todo.png": &bintree{todoPng, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory
@@ -0,0 +1,190 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package breadcrumb finds the declarations that surround a position
+// in a go file, from the package down to the function, along with
+// the declarations next to them that can be jumped to.
+package breadcrumb
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"unicode/utf8"
+)
+
+// Kind is the kind of declaration that a Crumb is.
+type Kind int
+
+const (
+	Package Kind = iota
+	Type
+	Func
+)
+
+// A Symbol is a declaration that can be jumped to.
+type Symbol struct {
+	Name string
+
+	// Offset is the rune offset of Name in the source.
+	Offset int
+}
+
+// A Crumb is a declaration that surrounds a position in a go file.
+type Crumb struct {
+	Symbol
+	Kind Kind
+
+	// Siblings are the declarations at the same level as the
+	// crumb, in the order that they're declared, including the
+	// crumb itself when it's declared in the file.  The siblings
+	// of a type are the other types in the file, and the siblings
+	// of a method are the other methods of its type.  A package
+	// has no siblings in a single file, so its siblings are the
+	// file's top-level types and functions instead.
+	Siblings []Symbol
+}
+
+// Path returns the crumbs for the declarations in src that contain
+// the rune at offset, starting with the package.  A method's type is
+// included even when offset isn't inside of the type's declaration.
+// Nil is returned if src doesn't have a package clause.
+func Path(src string, offset int) []Crumb {
+	fset := token.NewFileSet()
+	f, _ := parser.ParseFile(fset, "", src, 0)
+	if f == nil || f.Name == nil || f.Name.Name == "" {
+		return nil
+	}
+	byteOffset := len(src)
+	if runes := []rune(src); offset < len(runes) {
+		byteOffset = len(string(runes[:offset]))
+	}
+	d := decls{src: src, fset: fset, methods: make(map[string][]Symbol)}
+	d.collect(f)
+
+	path := []Crumb{{
+		Symbol:   d.symbol(f.Name),
+		Kind:     Package,
+		Siblings: d.all,
+	}}
+	for _, decl := range f.Decls {
+		if !d.contains(decl, byteOffset) {
+			continue
+		}
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			fn := Crumb{Symbol: d.symbol(decl.Name), Kind: Func, Siblings: d.funcs}
+			if recv := receiver(decl); recv != nil {
+				path = append(path, d.typeCrumb(recv))
+				fn.Siblings = d.methods[recv.Name]
+			}
+			path = append(path, fn)
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				t, ok := spec.(*ast.TypeSpec)
+				if ok && d.contains(t, byteOffset) {
+					path = append(path, Crumb{Symbol: d.symbol(t.Name), Kind: Type, Siblings: d.types})
+				}
+			}
+		}
+		break
+	}
+	return path
+}
+
+// decls holds the declarations in a file, grouped the way that they
+// are listed as siblings.
+type decls struct {
+	src  string
+	fset *token.FileSet
+
+	all     []Symbol
+	types   []Symbol
+	funcs   []Symbol
+	methods map[string][]Symbol
+}
+
+func (d *decls) collect(f *ast.File) {
+	for _, decl := range f.Decls {
+		switch src := decl.(type) {
+		case *ast.FuncDecl:
+			s := d.symbol(src.Name)
+			recv := receiver(src)
+			if recv == nil {
+				d.funcs = append(d.funcs, s)
+				d.all = append(d.all, s)
+				continue
+			}
+			d.methods[recv.Name] = append(d.methods[recv.Name], s)
+			d.all = append(d.all, Symbol{Name: recv.Name + "." + s.Name, Offset: s.Offset})
+		case *ast.GenDecl:
+			if src.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range src.Specs {
+				s := d.symbol(spec.(*ast.TypeSpec).Name)
+				d.types = append(d.types, s)
+				d.all = append(d.all, s)
+			}
+		}
+	}
+}
+
+// typeCrumb returns the crumb for the receiver type named by ident,
+// which points to the type's declaration if it's in the file.
+func (d *decls) typeCrumb(ident *ast.Ident) Crumb {
+	c := Crumb{Symbol: d.symbol(ident), Kind: Type, Siblings: d.types}
+	for _, t := range d.types {
+		if t.Name == ident.Name {
+			c.Offset = t.Offset
+			break
+		}
+	}
+	return c
+}
+
+func (d *decls) symbol(ident *ast.Ident) Symbol {
+	return Symbol{Name: ident.Name, Offset: d.runeOffset(ident.Pos())}
+}
+
+func (d *decls) runeOffset(pos token.Pos) int {
+	offset := d.fset.Position(pos).Offset
+	if offset > len(d.src) {
+		offset = len(d.src)
+	}
+	return utf8.RuneCountInString(d.src[:offset])
+}
+
+// contains returns whether or not byteOffset is inside of n.  The
+// offset just after the end of n is included, since that's where the
+// caret is after typing the last character of n.
+func (d *decls) contains(n ast.Node, byteOffset int) bool {
+	start, end := d.fset.Position(n.Pos()).Offset, d.fset.Position(n.End()).Offset
+	return byteOffset >= start && byteOffset <= end
+}
+
+// receiver returns the name of fn's receiver type, or nil if fn is
+// not a method.
+func receiver(fn *ast.FuncDecl) *ast.Ident {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return nil
+	}
+	expr := fn.Recv.List[0].Type
+	for {
+		switch src := expr.(type) {
+		case *ast.Ident:
+			return src
+		case *ast.StarExpr:
+			expr = src.X
+		case *ast.ParenExpr:
+			expr = src.X
+		case *ast.IndexExpr:
+			expr = src.X
+		case *ast.IndexListExpr:
+			expr = src.X
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package breadcrumb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/breadcrumb"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+const src = `package foo
+
+type Bar struct {
+	baz int
+}
+
+// Ünïcode is here to make byte and rune offsets differ.
+func (b *Bar) Ünïcode() {
+	b.baz++
+}
+
+func (b Bar) Get() int {
+	return b.baz
+}
+
+func New() *Bar {
+	return &Bar{}
+}
+
+func (q *Qux) Elsewhere() {}
+`
+
+var (
+	equal   = matchers.Equal
+	haveLen = matchers.HaveLen
+)
+
+// offsetOf returns the rune offset of the first occurrence of s in
+// src.
+func offsetOf(s string) int {
+	return len([]rune(src[:strings.Index(src, s)]))
+}
+
+func names(crumbs []breadcrumb.Crumb) []string {
+	var n []string
+	for _, c := range crumbs {
+		n = append(n, c.Name)
+	}
+	return n
+}
+
+func symbolNames(syms []breadcrumb.Symbol) []string {
+	var n []string
+	for _, s := range syms {
+		n = append(n, s.Name)
+	}
+	return n
+}
+
+func TestPath(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it returns only the package outside of declarations", func(expect expect.Expectation) {
+		path := breadcrumb.Path(src, offsetOf("// Ü"))
+		expect(names(path)).To(equal([]string{"foo"}))
+		expect(path[0].Kind).To(equal(breadcrumb.Package))
+		expect(path[0].Offset).To(equal(offsetOf("foo")))
+		expect(symbolNames(path[0].Siblings)).To(equal([]string{"Bar", "Bar.Ünïcode", "Bar.Get", "New", "Qux.Elsewhere"}))
+	})
+
+	o.Spec("it finds types", func(expect expect.Expectation) {
+		path := breadcrumb.Path(src, offsetOf("baz int"))
+		expect(names(path)).To(equal([]string{"foo", "Bar"}))
+		expect(path[1].Kind).To(equal(breadcrumb.Type))
+		expect(symbolNames(path[1].Siblings)).To(equal([]string{"Bar"}))
+	})
+
+	o.Spec("it finds methods along with their types", func(expect expect.Expectation) {
+		path := breadcrumb.Path(src, offsetOf("b.baz++"))
+		expect(path).To(haveLen(3))
+		expect(names(path)).To(equal([]string{"foo", "Bar", "Ünïcode"}))
+		expect(path[1].Offset).To(equal(offsetOf("Bar struct")))
+		expect(path[2].Kind).To(equal(breadcrumb.Func))
+		expect(path[2].Offset).To(equal(offsetOf("Ünïcode()")))
+		expect(symbolNames(path[2].Siblings)).To(equal([]string{"Ünïcode", "Get"}))
+	})
+
+	o.Spec("it uses rune offsets", func(expect expect.Expectation) {
+		path := breadcrumb.Path(src, offsetOf("return b.baz"))
+		expect(names(path)).To(equal([]string{"foo", "Bar", "Get"}))
+		expect(path[2].Offset).To(equal(offsetOf("Get()")))
+	})
+
+	o.Spec("it lists functions as the siblings of functions", func(expect expect.Expectation) {
+		path := breadcrumb.Path(src, offsetOf("return &Bar"))
+		expect(names(path)).To(equal([]string{"foo", "New"}))
+		expect(symbolNames(path[1].Siblings)).To(equal([]string{"New"}))
+	})
+
+	o.Spec("it handles methods of types from other files", func(expect expect.Expectation) {
+		path := breadcrumb.Path(src, offsetOf("Elsewhere")+2)
+		expect(names(path)).To(equal([]string{"foo", "Qux", "Elsewhere"}))
+		expect(path[1].Offset).To(equal(offsetOf("Qux")))
+	})
+
+	o.Spec("it returns nil without a package clause", func(expect expect.Expectation) {
+		expect(breadcrumb.Path("func main() {}", 3)).To(haveLen(0))
+	})
+}
@@ -0,0 +1,156 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package autosave contains a hook that saves files automatically,
+// either after a pause in editing or when a different file is
+// focused.
+package autosave
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(cmdr command.Commander, driver gxui.Driver, _ *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{New(cmdr, driver)}
+}
+
+// An EditorSaver is a bind.Bindable that can save a specific editor.
+// save-current-file implements EditorSaver.
+type EditorSaver interface {
+	ForEditor(input.Editor) bind.Bindable
+}
+
+// An Editor is an input.Editor that knows whether or not it has
+// unsaved changes.
+type Editor interface {
+	input.Editor
+	HasChanges() bool
+}
+
+// A Caller is a type that can call functions on the UI goroutine.
+type Caller interface {
+	Call(func()) bool
+}
+
+// dirty is an editor with unsaved changes, along with the save
+// command that was bound while it was being edited.
+type dirty struct {
+	editor Editor
+	saver  EditorSaver
+}
+
+// AutoSave is a hook that saves editors with unsaved changes.  It
+// binds to the input handler to keep track of edits and to
+// focus-location to know when a file loses focus.
+//
+// Files are saved using the save-current-file command that was bound
+// while they were edited, so any hooks on that command (formatting,
+// etc) will still run.
+type AutoSave struct {
+	cmdr   command.Commander
+	caller Caller
+
+	mu    sync.Mutex
+	timer *time.Timer
+	dirty map[string]dirty
+}
+
+// New returns an *AutoSave which will use cmdr to execute saves on
+// the UI goroutine using caller.
+func New(cmdr command.Commander, caller Caller) *AutoSave {
+	return &AutoSave{
+		cmdr:   cmdr,
+		caller: caller,
+		dirty:  make(map[string]dirty),
+	}
+}
+
+func (a *AutoSave) Name() string {
+	return "autosave"
+}
+
+// OpNames returns the names of the bind.Op types that a needs to
+// bind to.
+func (a *AutoSave) OpNames() []string {
+	return []string{"input-handler", "focus-location"}
+}
+
+// Applied records e as having unsaved changes and, if saving on idle
+// is enabled, restarts the idle timer.
+func (a *AutoSave) Applied(e input.Editor, _ []input.Edit) {
+	cfg := setting.AutoSaveConfig()
+	if !cfg.Enabled && !cfg.OnFocusLost {
+		return
+	}
+	editor, ok := e.(Editor)
+	if !ok {
+		return
+	}
+	saver, ok := a.cmdr.Bindable("save-current-file").(EditorSaver)
+	if !ok {
+		log.Printf("WARNING: autosave: save-current-file cannot save specific editors")
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.dirty[editor.Filepath()] = dirty{editor: editor, saver: saver}
+	if !cfg.Enabled {
+		return
+	}
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+	a.timer = time.AfterFunc(cfg.DelayDuration(), func() {
+		a.caller.Call(a.saveAll)
+	})
+}
+
+// FileChanged saves the file at oldPath if it has unsaved changes
+// and saving on focus loss is enabled.
+func (a *AutoSave) FileChanged(oldPath, newPath string) {
+	if oldPath == "" || oldPath == newPath || !setting.AutoSaveConfig().OnFocusLost {
+		return
+	}
+	a.mu.Lock()
+	d, ok := a.dirty[oldPath]
+	delete(a.dirty, oldPath)
+	a.mu.Unlock()
+	if !ok {
+		return
+	}
+	// focus-location calls FileChanged in the middle of swapping
+	// out bindings, so wait until it's finished.
+	a.caller.Call(func() {
+		a.save(d)
+	})
+}
+
+func (a *AutoSave) saveAll() {
+	a.mu.Lock()
+	all := a.dirty
+	a.dirty = make(map[string]dirty)
+	a.mu.Unlock()
+	for _, d := range all {
+		a.save(d)
+	}
+}
+
+func (a *AutoSave) save(d dirty) {
+	if !d.editor.HasChanges() {
+		return
+	}
+	a.cmdr.Execute(d.saver.ForEditor(d.editor))
+}
@@ -0,0 +1,67 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// BindingConflictsName is the name of the command that displays
+// conflicting key bindings.
+const BindingConflictsName = "show-binding-conflicts"
+
+// ConflictLister is a type that knows which key bindings are bound
+// to more than one command.
+type ConflictLister interface {
+	BindingConflicts() []setting.Conflict
+}
+
+// BindingConflicts is a command that displays the key bindings that
+// are bound to more than one command.
+type BindingConflicts struct {
+	status.General
+}
+
+func NewBindingConflicts(theme gxui.Theme) *BindingConflicts {
+	b := &BindingConflicts{}
+	b.Theme = theme
+	return b
+}
+
+func (b *BindingConflicts) Name() string {
+	return BindingConflictsName
+}
+
+func (b *BindingConflicts) Menu() string {
+	return "View"
+}
+
+func (b *BindingConflicts) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (b *BindingConflicts) Exec(target interface{}) bind.Status {
+	lister, ok := target.(ConflictLister)
+	if !ok {
+		return bind.Waiting
+	}
+	conflicts := lister.BindingConflicts()
+	if len(conflicts) == 0 {
+		b.Info = "No key bindings conflict"
+		return bind.Done
+	}
+	msgs := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		msgs = append(msgs, c.String())
+	}
+	b.Warn = fmt.Sprintf("%d conflicting key bindings: %s", len(conflicts), strings.Join(msgs, "; "))
+	return bind.Done
+}
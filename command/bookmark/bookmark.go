@@ -0,0 +1,105 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package bookmark contains commands for bookmarking lines and
+// jumping between bookmarks, along with a hook that keeps bookmarks
+// on the lines they were added to while files are edited.
+//
+// Bookmarks are stored per project, in the projects file.
+package bookmark
+
+import (
+	"sync"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, _ gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{
+		&Tracker{editors: make(map[string]Editor)},
+		NewToggle(theme),
+		NewNext(theme),
+		NewPrev(theme),
+	}
+}
+
+// An Editor is an input.Editor that can bookmark lines.
+type Editor interface {
+	input.Editor
+	Carets() []int
+	LineIndex(int) int
+	Bookmarks() []int
+	ToggleBookmark(line int) bool
+	ShiftBookmarks([]input.Edit)
+}
+
+// A Refresher is a type that displays bookmarks and needs to know
+// when they change.
+type Refresher interface {
+	RefreshBookmarks()
+}
+
+// An Executor is a type that can execute bindables.
+type Executor interface {
+	Execute(bind.Bindable)
+}
+
+// A Focuser is a type that can create a bindable which focuses a
+// file.
+type Focuser interface {
+	For(...focus.Opt) bind.Bindable
+}
+
+// save writes the bookmarks in e to the projects file.
+func save(e Editor) {
+	path := e.Filepath()
+	setting.SetFileBookmarks(setting.ProjectFor(path).Name, path, e.Bookmarks())
+}
+
+// Tracker is a hook which moves bookmarks along with the lines they
+// are on while a file is edited.  The new lines are written to the
+// projects file when the file is saved, so that bookmarks always
+// match the file on disk.
+type Tracker struct {
+	mu      sync.Mutex
+	editors map[string]Editor
+}
+
+func (t *Tracker) Name() string {
+	return "bookmark-tracker"
+}
+
+func (t *Tracker) OpNames() []string {
+	return []string{"input-handler", "save-current-file"}
+}
+
+func (t *Tracker) Applied(e input.Editor, edits []input.Edit) {
+	b, ok := e.(Editor)
+	if !ok || len(b.Bookmarks()) == 0 {
+		return
+	}
+	b.ShiftBookmarks(edits)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.editors[b.Filepath()] = b
+}
+
+func (t *Tracker) AfterSave(_ setting.Project, path, _ string) error {
+	t.mu.Lock()
+	e, ok := t.editors[path]
+	delete(t.editors, path)
+	t.mu.Unlock()
+	if ok {
+		save(e)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package bookmark
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// jump is the shared implementation of Next and Prev.  It moves the
+// caret to the closest bookmark in the project in one direction,
+// wrapping around at the first and last bookmark.
+type jump struct {
+	status.General
+
+	backward bool
+
+	editor  Editor
+	focuser Focuser
+	execer  Executor
+}
+
+func (j *jump) Reset() {
+	j.editor = nil
+	j.focuser = nil
+	j.execer = nil
+}
+
+func (j *jump) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Editor:
+		j.editor = src
+	case Focuser:
+		j.focuser = src
+	case Executor:
+		j.execer = src
+	}
+	if j.editor == nil || j.focuser == nil || j.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (j *jump) Exec() error {
+	path := j.editor.Filepath()
+	line := 0
+	if carets := j.editor.Carets(); len(carets) > 0 {
+		line = j.editor.LineIndex(carets[len(carets)-1])
+	}
+	marks := j.bookmarks(path)
+	if len(marks) == 0 {
+		j.Warn = "No bookmarks in this project"
+		return nil
+	}
+	target, ok := closest(marks, setting.Bookmark{Path: path, Line: line}, j.backward)
+	if !ok {
+		j.Warn = "No other bookmarks in this project"
+		return nil
+	}
+	j.execer.Execute(j.focuser.For(focus.Path(target.Path), focus.Line(target.Line)))
+	return nil
+}
+
+// bookmarks returns all bookmarks in the project that path belongs
+// to, in order.  The bookmarks for path are taken from the editor,
+// since they may have moved since the file was last saved.
+func (j *jump) bookmarks(path string) []setting.Bookmark {
+	var marks []setting.Bookmark
+	for _, b := range setting.Bookmarks(setting.ProjectFor(path).Name) {
+		if b.Path != path {
+			marks = append(marks, b)
+		}
+	}
+	for _, l := range j.editor.Bookmarks() {
+		marks = append(marks, setting.Bookmark{Path: path, Line: l})
+	}
+	sort.Slice(marks, func(i, k int) bool {
+		return less(marks[i], marks[k])
+	})
+	return marks
+}
+
+// closest returns the first bookmark in marks after from, or the last
+// one before it if backward is true.  marks must be sorted.
+func closest(marks []setting.Bookmark, from setting.Bookmark, backward bool) (setting.Bookmark, bool) {
+	if backward {
+		for i := len(marks) - 1; i >= 0; i-- {
+			if less(marks[i], from) {
+				return marks[i], true
+			}
+		}
+		last := marks[len(marks)-1]
+		return last, last != from
+	}
+	for _, b := range marks {
+		if less(from, b) {
+			return b, true
+		}
+	}
+	return marks[0], marks[0] != from
+}
+
+func less(a, b setting.Bookmark) bool {
+	if a.Path != b.Path {
+		return a.Path < b.Path
+	}
+	return a.Line < b.Line
+}
+
+// Next is a command which moves the caret to the next bookmark in the
+// project.
+type Next struct {
+	jump
+}
+
+func NewNext(theme gxui.Theme) *Next {
+	n := &Next{}
+	n.Theme = theme
+	return n
+}
+
+func (n *Next) Name() string {
+	return "next-bookmark"
+}
+
+func (n *Next) Menu() string {
+	return "Navigation"
+}
+
+func (n *Next) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModAlt,
+		Key:      gxui.KeyF2,
+	}}
+}
+
+// Prev is a command which moves the caret to the previous bookmark in
+// the project.
+type Prev struct {
+	jump
+}
+
+func NewPrev(theme gxui.Theme) *Prev {
+	p := &Prev{jump: jump{backward: true}}
+	p.Theme = theme
+	return p
+}
+
+func (p *Prev) Name() string {
+	return "prev-bookmark"
+}
+
+func (p *Prev) Menu() string {
+	return "Navigation"
+}
+
+func (p *Prev) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModAlt | gxui.ModShift,
+		Key:      gxui.KeyF2,
+	}}
+}
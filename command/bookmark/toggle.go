@@ -0,0 +1,79 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package bookmark
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// Toggle is a command which bookmarks the line that the caret is on,
+// or removes the bookmark if the line is already bookmarked.
+type Toggle struct {
+	status.General
+
+	editor     Editor
+	refreshers []Refresher
+}
+
+func NewToggle(theme gxui.Theme) *Toggle {
+	t := &Toggle{}
+	t.Theme = theme
+	return t
+}
+
+func (t *Toggle) Name() string {
+	return "toggle-bookmark"
+}
+
+func (t *Toggle) Menu() string {
+	return "Navigation"
+}
+
+func (t *Toggle) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl,
+		Key:      gxui.KeyF2,
+	}}
+}
+
+func (t *Toggle) Reset() {
+	t.editor = nil
+	t.refreshers = nil
+}
+
+func (t *Toggle) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Editor:
+		t.editor = src
+	case Refresher:
+		t.refreshers = append(t.refreshers, src)
+	}
+	if t.editor == nil {
+		return bind.Waiting
+	}
+	return bind.Executing
+}
+
+func (t *Toggle) Exec() error {
+	carets := t.editor.Carets()
+	if len(carets) == 0 {
+		t.Warn = "No caret to bookmark"
+		return nil
+	}
+	line := t.editor.LineIndex(carets[len(carets)-1])
+	t.Info = fmt.Sprintf("Removed bookmark on line %d", line+1)
+	if t.editor.ToggleBookmark(line) {
+		t.Info = fmt.Sprintf("Bookmarked line %d", line+1)
+	}
+	save(t.editor)
+	for _, r := range t.refreshers {
+		r.RefreshBookmarks()
+	}
+	return nil
+}
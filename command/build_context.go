@@ -0,0 +1,87 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/command/fs"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// SetBuildContext is a command which changes the GOOS, GOARCH, and
+// build tags that the current project's go files are matched against.
+// The context is entered as "GOOS/GOARCH tag1,tag2"; an empty context
+// goes back to the defaults.
+type SetBuildContext struct {
+	status.General
+
+	contextInput gxui.TextBox
+	input        gxui.Focusable
+
+	projecter Projecter
+}
+
+func NewSetBuildContext(theme gxui.Theme) *SetBuildContext {
+	s := &SetBuildContext{contextInput: theme.CreateTextBox()}
+	s.Theme = theme
+	return s
+}
+
+func (s *SetBuildContext) Name() string {
+	return "set-build-context"
+}
+
+func (s *SetBuildContext) Menu() string {
+	return "Golang"
+}
+
+func (s *SetBuildContext) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (s *SetBuildContext) Start(on gxui.Control) gxui.Control {
+	s.contextInput.SetText("")
+	if c := fs.CurrentProject(on).BuildContext(); !c.Default() {
+		s.contextInput.SetText(c.String())
+	}
+	s.input = s.contextInput
+	return nil
+}
+
+func (s *SetBuildContext) Next() gxui.Focusable {
+	input := s.input
+	s.input = nil
+	return input
+}
+
+func (s *SetBuildContext) Reset() {
+	s.Clear()
+	s.projecter = nil
+}
+
+func (s *SetBuildContext) Store(elem interface{}) bind.Status {
+	p, ok := elem.(Projecter)
+	if !ok {
+		return bind.Waiting
+	}
+	s.projecter = p
+	return bind.Done
+}
+
+func (s *SetBuildContext) Exec() error {
+	c, err := setting.ParseBuildContext(s.contextInput.Text())
+	if err != nil {
+		s.Err = fmt.Sprintf("Could not parse build context: %s", err)
+		return err
+	}
+	proj := s.projecter.Project()
+	setting.SetBuildContext(proj.Name, c)
+	s.Info = fmt.Sprintf("Build context for %s is now %s", proj.Name, c)
+	return nil
+}
@@ -0,0 +1,119 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/scoring"
+)
+
+// CancelTask is a command which cancels one of the background tasks
+// that are running.  Typing filters the tasks that can be canceled,
+// and the first match is canceled.
+type CancelTask struct {
+	status.General
+
+	theme gxui.Theme
+
+	filter  gxui.TextBox
+	matches gxui.LinearLayout
+	input   gxui.Focusable
+
+	tasks  map[string]*status.Task
+	names  []string
+	choice *status.Task
+}
+
+func NewCancelTask(theme gxui.Theme) *CancelTask {
+	c := &CancelTask{
+		theme:   theme,
+		filter:  theme.CreateTextBox(),
+		matches: theme.CreateLinearLayout(),
+	}
+	c.Theme = theme
+	c.filter.SetDesiredWidth(math.MaxSize.W)
+	c.filter.OnTextChanged(func([]gxui.TextBoxEdit) {
+		c.update()
+	})
+	c.matches.SetDirection(gxui.LeftToRight)
+	return c
+}
+
+func (c *CancelTask) Name() string {
+	return "cancel-task"
+}
+
+func (c *CancelTask) Menu() string {
+	return "View"
+}
+
+func (c *CancelTask) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (c *CancelTask) Start(gxui.Control) gxui.Control {
+	c.tasks = make(map[string]*status.Task)
+	c.names = nil
+	for _, t := range status.Running() {
+		if !t.Cancelable() {
+			continue
+		}
+		name := t.Name()
+		for i := 2; c.tasks[name] != nil; i++ {
+			name = fmt.Sprintf("%s #%d", t.Name(), i)
+		}
+		c.tasks[name] = t
+		c.names = append(c.names, name)
+	}
+	c.filter.SetText("")
+	c.update()
+	c.input = c.filter
+	return c.matches
+}
+
+func (c *CancelTask) Next() gxui.Focusable {
+	input := c.input
+	c.input = nil
+	return input
+}
+
+// update displays the tasks that match the current filter, in order
+// of how well they match.
+func (c *CancelTask) update() {
+	matches := c.names
+	if partial := c.filter.Text(); partial != "" {
+		matches = scoring.Sort(append([]string(nil), c.names...), partial)
+	}
+	c.choice = nil
+	c.matches.RemoveAll()
+	for i, m := range matches {
+		l := c.theme.CreateLabel()
+		l.SetMargin(math.Spacing{L: 5, R: 5})
+		l.SetText(m)
+		if i == 0 {
+			c.choice = c.tasks[m]
+			l.SetColor(themeMatchColor)
+		}
+		c.matches.AddChild(l)
+	}
+}
+
+func (c *CancelTask) Exec(interface{}) bind.Status {
+	if c.choice == nil {
+		c.Err = "no running tasks can be canceled"
+		if len(c.names) > 0 {
+			c.Err = "no running tasks match"
+		}
+		return bind.Done
+	}
+	c.choice.Cancel()
+	c.Info = fmt.Sprintf("canceled %s", c.choice.Name())
+	return bind.Done
+}
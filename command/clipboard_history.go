@@ -0,0 +1,226 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+const (
+	// maxPreview is the number of runes of each clipboard entry
+	// that are shown by paste-from-history.
+	maxPreview = 60
+
+	// maxClipboardShown is the number of clipboard entries that are
+	// shown by paste-from-history.
+	maxClipboardShown = 10
+)
+
+var clipboardMatchColor = gxui.Color{R: 0.4, G: 0.8, B: 0.3, A: 1}
+
+// ClipboardHistory is a ring of the text that was most recently
+// copied or cut.  Its size and whether or not it's saved across
+// restarts are read from the clipboard settings.
+type ClipboardHistory struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+// NewClipboardHistory returns a *ClipboardHistory, loading the
+// entries from the last session if clipboard history is persisted.
+func NewClipboardHistory() *ClipboardHistory {
+	h := &ClipboardHistory{}
+	if setting.ClipboardConfig().Persist {
+		h.entries = setting.ClipboardHistory()
+	}
+	return h
+}
+
+// Add moves text to the front of h, dropping the oldest entry if h
+// is full.
+func (h *ClipboardHistory) Add(text string) {
+	if text == "" {
+		return
+	}
+	cfg := setting.ClipboardConfig()
+	h.mu.Lock()
+	entries := []string{text}
+	for _, e := range h.entries {
+		if len(entries) == cfg.HistorySize {
+			break
+		}
+		if e != text {
+			entries = append(entries, e)
+		}
+	}
+	h.entries = entries
+	h.mu.Unlock()
+	if cfg.Persist {
+		setting.SetClipboardHistory(entries)
+	}
+}
+
+// Entries returns the text in h, most recent first.
+func (h *ClipboardHistory) Entries() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.entries...)
+}
+
+// PasteFromHistory is a command which pastes an entry from the
+// clipboard history over the current selections.  Entries are listed
+// most recent first; typing an entry's number selects it, and typing
+// anything else filters the entries by their text.  The pasted entry
+// becomes the current clipboard contents.
+type PasteFromHistory struct {
+	status.General
+
+	driver  gxui.Driver
+	theme   *basic.Theme
+	history *ClipboardHistory
+
+	filter  gxui.TextBox
+	matches gxui.LinearLayout
+	input   <-chan gxui.Focusable
+
+	entries []string
+	choice  string
+
+	editor  Editor
+	applier Applier
+}
+
+func NewPasteFromHistory(driver gxui.Driver, theme *basic.Theme, history *ClipboardHistory) *PasteFromHistory {
+	p := &PasteFromHistory{
+		driver:  driver,
+		theme:   theme,
+		history: history,
+		filter:  theme.CreateTextBox(),
+		matches: theme.CreateLinearLayout(),
+	}
+	p.Theme = theme
+	p.filter.SetDesiredWidth(math.MaxSize.W)
+	p.filter.OnTextChanged(func([]gxui.TextBoxEdit) {
+		p.update()
+	})
+	p.matches.SetDirection(gxui.TopToBottom)
+	return p
+}
+
+func (p *PasteFromHistory) Name() string {
+	return "paste-from-history"
+}
+
+func (p *PasteFromHistory) Menu() string {
+	return "Edit"
+}
+
+func (p *PasteFromHistory) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModShift,
+		Key:      gxui.KeyV,
+	}}
+}
+
+func (p *PasteFromHistory) Start(gxui.Control) gxui.Control {
+	p.entries = p.history.Entries()
+	p.filter.SetText("")
+	p.update()
+
+	input := make(chan gxui.Focusable, 1)
+	input <- p.filter
+	close(input)
+	p.input = input
+	return p.matches
+}
+
+func (p *PasteFromHistory) Next() gxui.Focusable {
+	return <-p.input
+}
+
+// update displays the entries that match the current filter.  The
+// first entry that is displayed is the one that will be pasted.
+func (p *PasteFromHistory) update() {
+	p.choice = ""
+	p.matches.RemoveAll()
+	filter := strings.ToLower(p.filter.Text())
+	first := 0
+	if n, err := strconv.Atoi(filter); err == nil && n > 0 && n <= len(p.entries) {
+		first = n - 1
+		filter = ""
+	}
+	for i := first; i < len(p.entries); i++ {
+		if len(p.matches.Children()) == maxClipboardShown {
+			break
+		}
+		e := p.entries[i]
+		if filter != "" && !strings.Contains(strings.ToLower(e), filter) {
+			continue
+		}
+		l := p.theme.CreateLabel()
+		l.SetMargin(math.Spacing{L: 5, R: 5})
+		l.SetText(fmt.Sprintf("%d: %s", i+1, preview(e)))
+		if p.choice == "" {
+			p.choice = e
+			l.SetColor(clipboardMatchColor)
+		}
+		p.matches.AddChild(l)
+	}
+}
+
+// preview returns the first line of text, shortened to maxPreview
+// runes.
+func preview(text string) string {
+	suffix := ""
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+		suffix = " …"
+	}
+	runes := []rune(text)
+	if len(runes) > maxPreview {
+		runes = runes[:maxPreview]
+		suffix = "…"
+	}
+	return string(runes) + suffix
+}
+
+func (p *PasteFromHistory) Reset() {
+	p.editor = nil
+	p.applier = nil
+}
+
+func (p *PasteFromHistory) Store(target interface{}) bind.Status {
+	switch src := target.(type) {
+	case Editor:
+		p.editor = src
+	case Applier:
+		p.applier = src
+	}
+	if p.editor != nil && p.applier != nil {
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (p *PasteFromHistory) Exec() error {
+	if p.choice == "" {
+		p.Warn = "Nothing in the clipboard history matches"
+		return nil
+	}
+	replaceSelections(p.editor, p.applier, []rune(p.choice))
+	p.driver.SetClipboard(p.choice)
+	p.history.Add(p.choice)
+	return nil
+}
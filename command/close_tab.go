@@ -10,6 +10,7 @@ import (
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/vidar/commander/bind"
 	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
 )
 
 type CurrentEditorCloser interface {
@@ -21,13 +22,31 @@ type BindPopper interface {
 	Pop() []bind.Bindable
 }
 
+// A Pinner is an editor whose tab may be pinned.
+type Pinner interface {
+	Pinned() bool
+}
+
+// A BeforeCloser is a hook that runs before the current tab is
+// closed.
+type BeforeCloser interface {
+	Name() string
+	BeforeClose(input.Editor)
+}
+
 type CloseTab struct {
+	status.General
+
 	closer CurrentEditorCloser
 	binder BindPopper
+
+	hooks []BeforeCloser
 }
 
-func NewCloseTab() *CloseTab {
-	return &CloseTab{}
+func NewCloseTab(theme gxui.Theme) *CloseTab {
+	s := &CloseTab{}
+	s.Theme = theme
+	return s
 }
 
 func (s *CloseTab) Name() string {
@@ -45,7 +64,18 @@ func (s *CloseTab) Defaults() []fmt.Stringer {
 	}}
 }
 
+func (s *CloseTab) Bind(h bind.Bindable) (bind.HookedMultiOp, error) {
+	closer, ok := h.(BeforeCloser)
+	if !ok {
+		return nil, fmt.Errorf("expected BeforeCloser; got %T", h)
+	}
+	newS := NewCloseTab(s.Theme)
+	newS.hooks = append(append(newS.hooks, s.hooks...), closer)
+	return newS, nil
+}
+
 func (s *CloseTab) Reset() {
+	s.Clear()
 	s.closer = nil
 	s.binder = nil
 }
@@ -64,6 +94,15 @@ func (s *CloseTab) Store(target interface{}) bind.Status {
 }
 
 func (s *CloseTab) Exec() error {
+	if e := s.closer.CurrentEditor(); e != nil {
+		if p, ok := e.(Pinner); ok && p.Pinned() {
+			s.Info = "This tab is pinned; unpin it to close it"
+			return nil
+		}
+		for _, h := range s.hooks {
+			h.BeforeClose(e)
+		}
+	}
 	s.closer.CloseCurrentEditor()
 	if s.closer.CurrentEditor() == nil {
 		s.binder.Pop()
@@ -7,32 +7,111 @@ package command
 import (
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/autosave"
+	"github.com/nelsam/vidar/command/bookmark"
 	"github.com/nelsam/vidar/command/caret"
+	"github.com/nelsam/vidar/command/diagnostic"
+	"github.com/nelsam/vidar/command/fileop"
 	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/command/fold"
+	"github.com/nelsam/vidar/command/gopkg"
 	"github.com/nelsam/vidar/command/history"
+	"github.com/nelsam/vidar/command/layout"
+	"github.com/nelsam/vidar/command/lines"
+	"github.com/nelsam/vidar/command/navigate"
 	"github.com/nelsam/vidar/command/project"
+	"github.com/nelsam/vidar/command/recent"
+	"github.com/nelsam/vidar/command/recovery"
+	"github.com/nelsam/vidar/command/scm"
+	"github.com/nelsam/vidar/command/scope"
 	"github.com/nelsam/vidar/command/scroll"
+	"github.com/nelsam/vidar/command/settings"
+	"github.com/nelsam/vidar/command/statusbar"
+	"github.com/nelsam/vidar/command/symbol"
+	"github.com/nelsam/vidar/command/tabs"
 	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/diffview"
 	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/plugin/gocode"
+	"github.com/nelsam/vidar/task"
+	"github.com/nelsam/vidar/terminal"
 )
 
-// Bindables returns all known bindables, in the order they should be
-// added to the menu.
+// Bindables returns the bindables that each window needs its own copy
+// of, in the order they should be added to the menu.
 func Bindables(cmdr command.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
 	var b []bind.Bindable
+	findOptions := NewFindOptions()
 	b = append(b, project.Bindables(driver, theme)...)
 	b = append(b,
 		NewFileOpener(driver, theme),
-		Quit{},
+		NewOpenSplitRight(driver, theme),
+		NewOpenSplitDown(driver, theme),
+		NewReplaceInProject(driver, theme),
+		NewFindInOpenFiles(driver, theme),
+		NewToggleFindCase(theme, findOptions),
+		NewToggleFindWholeWord(theme, findOptions),
+		NewToggleFindRegex(theme, findOptions),
+		NewTogglePreserveCase(theme, findOptions),
+		NewReloadFile(theme),
+		&Quit{},
 		Fullscreen{},
+		NewWindow{},
+		ToggleZenMode{},
+		ToggleLineNumbers{},
+		ToggleMinimap{},
+		NewToggleScrollLock(theme),
+		NewFocusProjectTree(theme),
+		NewToggleReadOnly(theme),
+		NewSetLineEndings(theme),
+		NewSwitchTheme(theme),
+		NewIncreaseFontSize(driver, theme),
+		NewDecreaseFontSize(driver, theme),
+		NewResetFontSize(driver, theme),
+		NewSetUIScale(theme),
+		NewBindingConflicts(theme),
+		NewRebindCommand(cmdr, theme),
+		NewSetBuildContext(theme),
+		NewCancelTask(theme),
+		terminal.NewToggle(driver, theme),
 		&caret.Mover{},
 		&scroll.Scroller{},
 		focus.NewLocation(driver),
 		FileHook{Theme: theme},
-		EditHook{Theme: theme, Driver: driver},
+		EditHook{Theme: theme, Driver: driver, Clipboard: NewClipboardHistory(), FindOptions: findOptions},
 		ViewHook{},
+		gocode.WordsHook{Theme: theme, Driver: driver},
 		NavHook{Commander: cmdr},
 	)
 	b = append(b, history.Bindables(cmdr, driver, theme)...)
+	b = append(b, bookmark.Bindables(cmdr, driver, theme)...)
+	b = append(b, diagnostic.Bindables(cmdr, driver, theme)...)
+	b = append(b, navigate.Bindables(cmdr, driver, theme)...)
+	b = append(b, lines.Bindables(cmdr, driver, theme)...)
+	b = append(b, fold.Bindables(cmdr, driver, theme)...)
+	b = append(b, scm.Bindables(cmdr, driver, theme)...)
+	b = append(b, gopkg.Bindables(cmdr, driver, theme)...)
+	b = append(b, fileop.Bindables(cmdr, driver, theme)...)
+	b = append(b, statusbar.Bindables(cmdr, driver, theme)...)
+	b = append(b, tabs.Bindables(cmdr, driver, theme)...)
+	b = append(b, layout.Bindables(cmdr, driver, theme)...)
+	b = append(b, scope.Bindables(cmdr, driver, theme)...)
+	b = append(b, diffview.Bindables(cmdr, driver, theme)...)
+	b = append(b, task.Bindables(cmdr, driver, theme)...)
+	b = append(b, settings.Bindables(cmdr, driver, theme)...)
+	return b
+}
+
+// SharedBindables returns the bindables that keep track of the whole
+// project (indexes, recent files, autosave, and recovery), in the order
+// they should be added to the menu.  They should only be created once
+// and shared by every window, so cmdr should execute commands in
+// whichever window is active.
+func SharedBindables(cmdr command.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	var b []bind.Bindable
+	b = append(b, autosave.Bindables(cmdr, driver, theme)...)
+	b = append(b, recent.Bindables(cmdr, driver, theme)...)
+	b = append(b, recovery.Bindables(cmdr, driver, theme)...)
+	b = append(b, symbol.Bindables(cmdr, driver, theme)...)
 	return b
 }
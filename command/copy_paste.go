@@ -21,12 +21,16 @@ type Editor interface {
 }
 
 type Copy struct {
-	driver gxui.Driver
+	driver  gxui.Driver
+	history *ClipboardHistory
 }
 
-func NewCopy(driver gxui.Driver) *Copy {
+// NewCopy returns a *Copy which adds the text it copies to history.
+// A nil history is allowed.
+func NewCopy(driver gxui.Driver, history *ClipboardHistory) *Copy {
 	return &Copy{
-		driver: driver,
+		driver:  driver,
+		history: history,
 	}
 }
 
@@ -58,6 +62,9 @@ func (c *Copy) Exec(target interface{}) bind.Status {
 	}
 
 	c.driver.SetClipboard(buffer.String())
+	if c.history != nil {
+		c.history.Add(buffer.String())
+	}
 	return bind.Done
 }
 
@@ -68,8 +75,8 @@ type Cut struct {
 	applier Applier
 }
 
-func NewCut(driver gxui.Driver) *Cut {
-	copy := NewCopy(driver)
+func NewCut(driver gxui.Driver, history *ClipboardHistory) *Cut {
+	copy := NewCopy(driver, history)
 	return &Cut{Copy: *copy}
 }
 
@@ -173,20 +180,21 @@ func (p *Paste) Store(target interface{}) bind.Status {
 }
 
 func (p *Paste) Exec() error {
-	p.replaceSelections()
-	return nil
-}
-
-func (p *Paste) replaceSelections() {
-	text := p.editor.Controller().TextRunes()
-	var edits []input.Edit
 	contents, err := p.driver.GetClipboard()
 	if err != nil {
 		p.Err = fmt.Sprintf("Error reading clipboard: %s", err)
-		return
+		return nil
 	}
-	replacement := []rune(contents)
-	for _, s := range p.editor.Controller().SelectionSlice() {
+	replaceSelections(p.editor, p.applier, []rune(contents))
+	return nil
+}
+
+// replaceSelections replaces each of editor's selections with
+// replacement.
+func replaceSelections(editor Editor, applier Applier, replacement []rune) {
+	text := editor.Controller().TextRunes()
+	var edits []input.Edit
+	for _, s := range editor.Controller().SelectionSlice() {
 		old := text[s.Start():s.End()]
 		edits = append(edits, input.Edit{
 			At:  s.Start(),
@@ -194,5 +202,5 @@ func (p *Paste) replaceSelections() {
 			New: replacement,
 		})
 	}
-	p.applier.Apply(p.editor, edits...)
+	applier.Apply(editor, edits...)
 }
@@ -0,0 +1,59 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package diagnostic contains commands for moving between the
+// diagnostics in a file and for listing the diagnostics in every open
+// file of the project in a panel below the editor.
+package diagnostic
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(cmdr command.Commander, _ gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{
+		NewNext(theme),
+		NewPrev(theme),
+		NewShowProblems(theme, NewProblems(cmdr, theme)),
+	}
+}
+
+// An Editor is an input.Editor with carets.
+type Editor interface {
+	input.Editor
+	Carets() []int
+}
+
+// A ProjectEditor is the editor for the current project.
+type ProjectEditor interface {
+	Project() setting.Project
+	OpenEditors() []input.Editor
+}
+
+// A Paneler is a type that can display panels below the editor.
+type Paneler interface {
+	ShowPanel(gxui.Control)
+	HidePanel(gxui.Control)
+	HasPanel(gxui.Control) bool
+}
+
+// An Executor is a type that can look up and execute bindables.
+type Executor interface {
+	Bindable(string) bind.Bindable
+	Execute(bind.Bindable)
+}
+
+// A Focuser is a type that can create a bindable which focuses a
+// file.
+type Focuser interface {
+	For(...focus.Opt) bind.Bindable
+}
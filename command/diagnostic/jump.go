@@ -0,0 +1,152 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package diagnostic
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// jump is the shared implementation of Next and Prev.  It moves the
+// caret to the closest diagnostic in the current file in one
+// direction, wrapping around at the first and last diagnostic, and
+// shows the diagnostic in the status bar.
+type jump struct {
+	status.General
+
+	backward bool
+
+	editor  Editor
+	focuser Focuser
+	execer  Executor
+}
+
+func (j *jump) Reset() {
+	j.editor = nil
+	j.focuser = nil
+	j.execer = nil
+}
+
+func (j *jump) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Editor:
+		j.editor = src
+	case Focuser:
+		j.focuser = src
+	case Executor:
+		j.execer = src
+	}
+	if j.editor == nil || j.focuser == nil || j.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (j *jump) Exec() error {
+	diags := j.editor.Diagnostics()
+	if len(diags) == 0 {
+		j.Info = "No diagnostics in this file"
+		return nil
+	}
+	caret := 0
+	if carets := j.editor.Carets(); len(carets) > 0 {
+		caret = carets[len(carets)-1]
+	}
+	target, ok := closest(diags, caret, j.backward)
+	if !ok {
+		j.Warn = fmt.Sprintf("No other diagnostics in this file; %s", target)
+		return nil
+	}
+	j.execer.Execute(j.focuser.For(focus.Path(j.editor.Filepath()), focus.Offset(target.Range.Start)))
+	switch target.Severity {
+	case input.SeverityError:
+		j.Err = target.String()
+	case input.SeverityWarning:
+		j.Warn = target.String()
+	default:
+		j.Info = target.String()
+	}
+	return nil
+}
+
+// closest returns the first diagnostic in diags that starts after
+// caret, or the last one that starts before it if backward is true.
+// diags must be sorted by where they start.  It returns false if the
+// only diagnostic to move to starts at caret.
+func closest(diags []input.Diagnostic, caret int, backward bool) (input.Diagnostic, bool) {
+	if backward {
+		for i := len(diags) - 1; i >= 0; i-- {
+			if diags[i].Range.Start < caret {
+				return diags[i], true
+			}
+		}
+		last := diags[len(diags)-1]
+		return last, last.Range.Start != caret
+	}
+	for _, d := range diags {
+		if d.Range.Start > caret {
+			return d, true
+		}
+	}
+	return diags[0], diags[0].Range.Start != caret
+}
+
+// Next is a command which moves the caret to the next diagnostic in
+// the current file.
+type Next struct {
+	jump
+}
+
+func NewNext(theme gxui.Theme) *Next {
+	n := &Next{}
+	n.Theme = theme
+	return n
+}
+
+func (n *Next) Name() string {
+	return "next-diagnostic"
+}
+
+func (n *Next) Menu() string {
+	return "Navigation"
+}
+
+func (n *Next) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Key: gxui.KeyF4,
+	}}
+}
+
+// Prev is a command which moves the caret to the previous diagnostic
+// in the current file.
+type Prev struct {
+	jump
+}
+
+func NewPrev(theme gxui.Theme) *Prev {
+	p := &Prev{jump: jump{backward: true}}
+	p.Theme = theme
+	return p
+}
+
+func (p *Prev) Name() string {
+	return "prev-diagnostic"
+}
+
+func (p *Prev) Menu() string {
+	return "Navigation"
+}
+
+func (p *Prev) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModShift,
+		Key:      gxui.KeyF4,
+	}}
+}
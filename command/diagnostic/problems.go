@@ -0,0 +1,241 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package diagnostic
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+var fileColor = gxui.Color{R: 0.6, G: 0.8, B: 1, A: 1}
+
+// Problems is a panel that lists the diagnostics in every open file
+// of the current project, like a quickfix list.  Clicking a
+// diagnostic moves the caret to it.
+type Problems struct {
+	mixins.LinearLayout
+
+	cmdr  Executor
+	theme gxui.Theme
+
+	status gxui.Label
+	list   gxui.LinearLayout
+
+	editor  ProjectEditor
+	paneler Paneler
+}
+
+// NewProblems creates a *Problems which will use cmdr to open
+// diagnostics.
+func NewProblems(cmdr Executor, theme gxui.Theme) *Problems {
+	p := &Problems{
+		cmdr:   cmdr,
+		theme:  theme,
+		status: theme.CreateLabel(),
+		list:   theme.CreateLinearLayout(),
+	}
+	p.Init(p, theme)
+	p.SetDirection(gxui.TopToBottom)
+
+	header := theme.CreateLinearLayout()
+	header.SetDirection(gxui.LeftToRight)
+	closer := theme.CreateButton()
+	closer.SetText("x")
+	closer.OnClick(func(gxui.MouseEvent) {
+		if p.paneler != nil {
+			p.paneler.HidePanel(p)
+		}
+	})
+	header.AddChild(closer)
+	refresh := theme.CreateButton()
+	refresh.SetText("refresh")
+	refresh.OnClick(func(gxui.MouseEvent) {
+		p.Refresh()
+	})
+	header.AddChild(refresh)
+	header.AddChild(p.status)
+	p.AddChild(header)
+
+	p.list.SetDirection(gxui.TopToBottom)
+	scrollable := theme.CreateScrollLayout()
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(p.list)
+	p.AddChild(scrollable)
+	return p
+}
+
+// Show lists the diagnostics in editor's files and shows p using
+// paneler, if it isn't already shown.  It must be called on the UI
+// goroutine.
+func (p *Problems) Show(paneler Paneler, editor ProjectEditor) {
+	p.paneler = paneler
+	p.editor = editor
+	p.Refresh()
+	if !paneler.HasPanel(p) {
+		paneler.ShowPanel(p)
+	}
+}
+
+// Refresh lists the diagnostics again, since they change as files are
+// edited and saved.  It must be called on the UI goroutine.
+func (p *Problems) Refresh() {
+	p.list.RemoveAll()
+	if p.editor == nil {
+		return
+	}
+	proj := p.editor.Project()
+	editors := p.editor.OpenEditors()
+	sort.Slice(editors, func(i, j int) bool {
+		return editors[i].Filepath() < editors[j].Filepath()
+	})
+	counts := make(map[input.Severity]int)
+	files := 0
+	for _, e := range editors {
+		diags := e.Diagnostics()
+		if len(diags) == 0 || !strings.HasPrefix(e.Filepath(), proj.Path) {
+			continue
+		}
+		files++
+		name, err := filepath.Rel(proj.Path, e.Filepath())
+		if err != nil {
+			name = e.Filepath()
+		}
+		file := p.theme.CreateLabel()
+		file.SetText(name)
+		file.SetColor(fileColor)
+		p.list.AddChild(file)
+
+		text := e.Runes()
+		for _, d := range diags {
+			counts[d.Severity]++
+			p.list.AddChild(p.problem(e.Filepath(), text, d))
+		}
+	}
+	if files == 0 {
+		p.status.SetText(fmt.Sprintf("No diagnostics in the open files of %s", proj.Name))
+		p.status.SetColor(status.ColorInfo)
+		return
+	}
+	p.status.SetText(fmt.Sprintf("%d errors, %d warnings, and %d other diagnostics in %d files",
+		counts[input.SeverityError], counts[input.SeverityWarning],
+		counts[input.SeverityInfo]+counts[input.SeverityHint], files))
+	p.status.SetColor(severityColor(worst(counts)))
+}
+
+// problem returns the label for d, which is in the file at path with
+// the contents text.
+func (p *Problems) problem(path string, text []rune, d input.Diagnostic) gxui.Label {
+	line, col := position(text, d.Range.Start)
+	l := p.theme.CreateLabel()
+	l.SetText(fmt.Sprintf("%d:%d: %s", line+1, col+1, d))
+	l.SetColor(severityColor(d.Severity))
+	l.SetMargin(math.Spacing{L: 10})
+	l.OnClick(func(gxui.MouseEvent) {
+		opener, ok := p.cmdr.Bindable("focus-location").(Focuser)
+		if !ok {
+			return
+		}
+		p.cmdr.Execute(opener.For(focus.Path(path), focus.Offset(d.Range.Start)))
+	})
+	return l
+}
+
+// position returns the zero-indexed line and column of offset in
+// text.
+func position(text []rune, offset int) (line, col int) {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	for _, r := range text[:offset] {
+		col++
+		if r == '\n' {
+			line++
+			col = 0
+		}
+	}
+	return line, col
+}
+
+func worst(counts map[input.Severity]int) input.Severity {
+	for s := input.SeverityError; s < input.SeverityHint; s++ {
+		if counts[s] > 0 {
+			return s
+		}
+	}
+	return input.SeverityHint
+}
+
+func severityColor(s input.Severity) gxui.Color {
+	switch s {
+	case input.SeverityError:
+		return status.ColorErr
+	case input.SeverityWarning:
+		return status.ColorWarn
+	}
+	return status.ColorInfo
+}
+
+// ShowProblems is a command which shows the Problems panel.
+type ShowProblems struct {
+	status.General
+
+	problems *Problems
+	paneler  Paneler
+	editor   ProjectEditor
+}
+
+func NewShowProblems(theme gxui.Theme, problems *Problems) *ShowProblems {
+	s := &ShowProblems{problems: problems}
+	s.Theme = theme
+	return s
+}
+
+func (s *ShowProblems) Name() string {
+	return "show-problems"
+}
+
+func (s *ShowProblems) Menu() string {
+	return "View"
+}
+
+func (s *ShowProblems) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModShift,
+		Key:      gxui.KeyM,
+	}}
+}
+
+func (s *ShowProblems) Reset() {
+	s.paneler = nil
+	s.editor = nil
+}
+
+func (s *ShowProblems) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Paneler:
+		s.paneler = src
+	case ProjectEditor:
+		s.editor = src
+	}
+	if s.paneler == nil || s.editor == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (s *ShowProblems) Exec() error {
+	s.problems.Show(s.paneler, s.editor)
+	return nil
+}
@@ -0,0 +1,39 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// DiagnosticEditor is an editor whose diagnostics can be moved to
+// follow edits.
+type DiagnosticEditor interface {
+	input.Editor
+	ShiftDiagnostics([]input.Edit)
+}
+
+// DiagnosticShift is a hook that keeps diagnostics on the text they
+// refer to while the text is being edited.  Hooks that publish
+// diagnostics usually need to parse or lint the new text first, so
+// without this, diagnostics would point at the wrong text until they
+// are published again.
+type DiagnosticShift struct{}
+
+func (DiagnosticShift) Name() string {
+	return "diagnostic-shift"
+}
+
+func (DiagnosticShift) OpName() string {
+	return "input-handler"
+}
+
+func (DiagnosticShift) Applied(e input.Editor, edits []input.Edit) {
+	d, ok := e.(DiagnosticEditor)
+	if !ok {
+		return
+	}
+	d.ShiftDiagnostics(edits)
+}
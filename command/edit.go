@@ -13,6 +13,14 @@ import (
 type EditHook struct {
 	Driver gxui.Driver
 	Theme  *basic.Theme
+
+	// Clipboard is the clipboard history shared by the commands
+	// for every file.
+	Clipboard *ClipboardHistory
+
+	// FindOptions are the find and replace options, which are also
+	// shared by every file.
+	FindOptions *FindOptions
 }
 
 func (h EditHook) Name() string {
@@ -24,14 +32,20 @@ func (h EditHook) OpName() string {
 }
 
 func (h EditHook) FileBindables(string) []bind.Bindable {
+	// The find commands for a file share their matches, so that
+	// find-next and find-prev repeat whichever search ran last.
+	matches := &Matches{}
 	return []bind.Bindable{
 		NewSelectAll(),
-		NewFind(h.Driver, h.Theme),
-		NewRegexFind(h.Driver, h.Theme),
-		NewReplace(h.Driver, h.Theme),
-		NewCopy(h.Driver),
-		NewCut(h.Driver),
+		NewFind(h.Driver, h.Theme, matches, h.FindOptions),
+		NewRegexFind(h.Driver, h.Theme, matches, h.FindOptions),
+		NewFindNext(h.Theme, matches),
+		NewFindPrev(h.Theme, matches),
+		NewReplace(h.Driver, h.Theme, h.FindOptions),
+		NewCopy(h.Driver, h.Clipboard),
+		NewCut(h.Driver, h.Clipboard),
 		NewPaste(h.Driver, h.Theme),
+		NewPasteFromHistory(h.Driver, h.Theme, h.Clipboard),
 		NewGotoLine(h.Theme),
 	}
 }
@@ -21,11 +21,14 @@ func (h FileHook) OpName() string {
 	return "focus-location"
 }
 
-func (h FileHook) FileBindables(string) []bind.Bindable {
+func (h FileHook) FileBindables(path string) []bind.Bindable {
 	return []bind.Bindable{
 		NewSave(h.Theme),
 		NewSaveAll(h.Theme),
-		NewCloseTab(),
+		NewCloseTab(h.Theme),
+		NewMoveToWindow(h.Theme),
+		DiagnosticShift{},
 		&EditorRedraw{},
+		NewSmartIndent(path),
 	}
 }
@@ -7,6 +7,7 @@ package command
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/themes/basic"
@@ -95,11 +96,41 @@ func (f *FileOpener) Exec() error {
 		return fmt.Errorf("command.FileOpener: %s", f.Err)
 	}
 
-	if finfo, err := os.Stat(path); err == nil && finfo.IsDir() {
+	finfo, err := os.Stat(path)
+	if err != nil && fs.HasGlob(filepath.Base(path)) {
+		return f.openMatches(path)
+	}
+	if err == nil && finfo.IsDir() {
 		f.Err = fmt.Sprintf("can't open directory %s as file", path)
 		return fmt.Errorf("command.FileOpener: %s", f.Err)
 	}
 
+	// Paths that don't exist yet are opened too; the file (and any
+	// missing directories) will be created when it's saved.
 	f.execer.Execute(f.focuser.For(focus.Path(path)))
 	return nil
 }
+
+// openMatches opens every file that matches pattern.
+func (f *FileOpener) openMatches(pattern string) error {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		f.Err = fmt.Sprintf("bad pattern %s: %s", pattern, err)
+		return fmt.Errorf("command.FileOpener: %s", f.Err)
+	}
+	var files []string
+	for _, m := range matches {
+		if finfo, err := os.Stat(m); err == nil && !finfo.IsDir() {
+			files = append(files, m)
+		}
+	}
+	if len(files) == 0 {
+		f.Err = fmt.Sprintf("no files match %s", pattern)
+		return fmt.Errorf("command.FileOpener: %s", f.Err)
+	}
+	for _, path := range files {
+		f.execer.Execute(f.focuser.For(focus.Path(path)))
+	}
+	f.Info = fmt.Sprintf("opened %d files", len(files))
+	return nil
+}
@@ -0,0 +1,79 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fileop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// Delete is a command which moves a file or directory to the trash,
+// after asking for confirmation.
+type Delete struct {
+	status.General
+	target
+
+	prompt gxui.Label
+	answer gxui.TextBox
+	input  gxui.Focusable
+}
+
+func NewDelete(theme gxui.Theme) *Delete {
+	d := &Delete{
+		prompt: theme.CreateLabel(),
+		answer: theme.CreateTextBox(),
+	}
+	d.Theme = theme
+	d.answer.SetDesiredWidth(math.MaxSize.W)
+	return d
+}
+
+func (d *Delete) Name() string {
+	return "delete-file"
+}
+
+func (d *Delete) Menu() string {
+	return "File"
+}
+
+func (d *Delete) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (d *Delete) Start(control gxui.Control) gxui.Control {
+	d.start(control)
+	d.prompt.SetText(fmt.Sprintf("Move %s to the trash? (y/n)", d.path))
+	d.answer.SetText("n")
+	d.input = d.answer
+	return d.prompt
+}
+
+func (d *Delete) Next() gxui.Focusable {
+	input := d.input
+	d.input = nil
+	return input
+}
+
+func (d *Delete) Exec(interface{}) bind.Status {
+	if d.path == "" {
+		d.Err = "No file to delete"
+		return bind.Done
+	}
+	if !strings.HasPrefix(strings.ToLower(strings.TrimSpace(d.answer.Text())), "y") {
+		d.Info = fmt.Sprintf("Kept %s", d.path)
+		return bind.Done
+	}
+	if err := Trash(d.path); err != nil {
+		d.Err = fmt.Sprintf("Could not move %s to the trash: %s", d.path, err)
+		return bind.Done
+	}
+	d.Info = fmt.Sprintf("Moved %s to the trash", d.path)
+	return bind.Done
+}
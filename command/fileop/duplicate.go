@@ -0,0 +1,158 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fileop
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/command/fs"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// Duplicate is a command which copies a file or directory to a new
+// path.  Copies of files are opened once they're written.
+type Duplicate struct {
+	status.General
+	target
+
+	file  *fs.Locator
+	input <-chan gxui.Focusable
+
+	focuser Focuser
+	execer  Executor
+}
+
+func NewDuplicate(driver gxui.Driver, theme *basic.Theme) *Duplicate {
+	d := &Duplicate{file: fs.NewLocator(driver, theme, fs.All)}
+	d.Theme = theme
+	return d
+}
+
+func (d *Duplicate) Name() string {
+	return "duplicate-file"
+}
+
+func (d *Duplicate) Menu() string {
+	return "File"
+}
+
+func (d *Duplicate) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyD,
+	}}
+}
+
+func (d *Duplicate) Start(control gxui.Control) gxui.Control {
+	d.start(control)
+	d.file.SetPath(CopyName(d.path))
+	d.input = focusOn(d.file)
+	return nil
+}
+
+func (d *Duplicate) Next() gxui.Focusable {
+	return <-d.input
+}
+
+func (d *Duplicate) Reset() {
+	d.focuser = nil
+	d.execer = nil
+}
+
+func (d *Duplicate) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Focuser:
+		d.focuser = src
+	case Executor:
+		d.execer = src
+	}
+	if d.focuser == nil || d.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (d *Duplicate) Exec() error {
+	if d.path == "" {
+		d.Err = "No file to duplicate"
+		return nil
+	}
+	newPath := d.file.Path()
+	if exists(newPath) {
+		d.Err = fmt.Sprintf("%s already exists", newPath)
+		return nil
+	}
+	if strings.HasPrefix(newPath, d.path+string(filepath.Separator)) {
+		d.Err = fmt.Sprintf("Can't copy %s into itself", d.path)
+		return nil
+	}
+	finfo, err := os.Stat(d.path)
+	if err != nil {
+		d.Err = fmt.Sprintf("Could not duplicate %s: %s", d.path, err)
+		return err
+	}
+	if err := Copy(d.path, newPath); err != nil {
+		d.Err = fmt.Sprintf("Could not duplicate %s: %s", d.path, err)
+		return err
+	}
+	if !finfo.IsDir() {
+		d.execer.Execute(d.focuser.For(focus.Path(newPath)))
+	}
+	d.Info = fmt.Sprintf("Copied %s to %s", d.path, newPath)
+	return nil
+}
+
+// CopyName returns the default path for a copy of path, which is in
+// the same directory with "_copy" added before the extension.
+func CopyName(path string) string {
+	if path == "" {
+		return ""
+	}
+	ext := filepath.Ext(path)
+	if finfo, err := os.Stat(path); err == nil && finfo.IsDir() {
+		ext = ""
+	}
+	return strings.TrimSuffix(path, ext) + "_copy" + ext
+}
+
+// Copy copies the file or directory at src to dst.  Directories are
+// copied recursively, and permissions are kept.
+func Copy(src, dst string) error {
+	return filepath.Walk(src, func(path string, finfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, strings.TrimPrefix(path, src))
+		if finfo.IsDir() {
+			return os.MkdirAll(target, finfo.Mode().Perm()|os.ModeDir)
+		}
+		return copyFile(path, target, finfo.Mode().Perm())
+	})
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
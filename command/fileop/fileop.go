@@ -0,0 +1,109 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package fileop contains commands for creating, renaming, deleting,
+// and duplicating files and directories.  Each command acts on the
+// current file unless it's given a path with SetPath first, which is
+// how the project tree's context menu runs them.
+package fileop
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/command/fs"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{
+		NewNewFile(driver, theme),
+		NewNewDirectory(driver, theme),
+		NewRename(driver, theme),
+		NewDuplicate(driver, theme),
+		NewDelete(theme),
+	}
+}
+
+// An Executor is a type that can execute bindables.
+type Executor interface {
+	Execute(bind.Bindable)
+}
+
+// A Focuser is a type that can create a bindable which focuses a
+// file.
+type Focuser interface {
+	For(...focus.Opt) bind.Bindable
+}
+
+// Editors is a type that knows which editors are open.
+type Editors interface {
+	OpenEditors() []input.Editor
+}
+
+// A Renamer is an editor that can follow its file to a new path.
+type Renamer interface {
+	Rename(newPath string)
+}
+
+// target is the path that a command acts on.
+type target struct {
+	next string
+	path string
+}
+
+// SetPath sets the path that the next run of the command acts on, in
+// place of the current file.
+func (t *target) SetPath(path string) {
+	t.next = path
+}
+
+// start chooses the path for a new run of the command, falling back
+// to the current file under control if SetPath wasn't called.
+func (t *target) start(control gxui.Control) {
+	t.path, t.next = t.next, ""
+	if t.path == "" {
+		t.path = fs.CurrentFile(control)
+	}
+	if t.path != "" {
+		t.path = filepath.Clean(t.path)
+	}
+}
+
+// dir returns t's path if it's a directory, or the directory that
+// contains it otherwise.
+func (t *target) dir() string {
+	if finfo, err := os.Stat(t.path); err == nil && finfo.IsDir() {
+		return t.path
+	}
+	return filepath.Dir(t.path)
+}
+
+// moved returns the path that path was moved to when oldPath was
+// moved to newPath, which may be a parent directory of path.  The
+// returned bool will be false if path wasn't moved.
+func moved(path, oldPath, newPath string) (string, bool) {
+	if path == oldPath {
+		return newPath, true
+	}
+	prefix := oldPath + string(filepath.Separator)
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+	return filepath.Join(newPath, strings.TrimPrefix(path, prefix)), true
+}
+
+// exists returns whether or not anything exists at path.
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
@@ -0,0 +1,69 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fileop_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nelsam/vidar/command/fileop"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal       = matchers.Equal
+	haveOccured = matchers.HaveOccurred
+	not         = matchers.Not
+)
+
+func TestCopy(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, string) {
+		dir, err := ioutil.TempDir("", "fileop")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return expect.New(t), dir
+	})
+
+	o.AfterEach(func(expect expect.Expectation, dir string) {
+		os.RemoveAll(dir)
+	})
+
+	o.Spec("it names copies after the original", func(expect expect.Expectation, dir string) {
+		expect(fileop.CopyName(filepath.Join(dir, "foo.go"))).To(equal(filepath.Join(dir, "foo_copy.go")))
+		expect(fileop.CopyName(dir)).To(equal(dir + "_copy"))
+	})
+
+	o.Spec("it copies directories recursively", func(expect expect.Expectation, dir string) {
+		src := filepath.Join(dir, "src")
+		expect(os.MkdirAll(filepath.Join(src, "sub"), 0750)).To(not(haveOccured()))
+		expect(ioutil.WriteFile(filepath.Join(src, "sub", "foo.txt"), []byte("foo"), 0600)).To(not(haveOccured()))
+
+		dst := filepath.Join(dir, "dst")
+		expect(fileop.Copy(src, dst)).To(not(haveOccured()))
+
+		b, err := ioutil.ReadFile(filepath.Join(dst, "sub", "foo.txt"))
+		expect(err).To(not(haveOccured()))
+		expect(string(b)).To(equal("foo"))
+
+		finfo, err := os.Stat(filepath.Join(dst, "sub", "foo.txt"))
+		expect(err).To(not(haveOccured()))
+		expect(finfo.Mode().Perm()).To(equal(os.FileMode(0600)))
+	})
+
+	o.Spec("it refuses to overwrite files", func(expect expect.Expectation, dir string) {
+		src, dst := filepath.Join(dir, "foo"), filepath.Join(dir, "bar")
+		expect(ioutil.WriteFile(src, []byte("foo"), 0600)).To(not(haveOccured()))
+		expect(ioutil.WriteFile(dst, []byte("bar"), 0600)).To(not(haveOccured()))
+		expect(fileop.Copy(src, dst)).To(haveOccured())
+	})
+}
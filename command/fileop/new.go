@@ -0,0 +1,187 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fileop
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/command/fs"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// NewFile is a command which creates a file and opens it.  The file is
+// created in the directory of the current file, or in the directory
+// given to SetPath, and starts with its project's license header.
+type NewFile struct {
+	status.General
+	target
+
+	file  *fs.Locator
+	input <-chan gxui.Focusable
+
+	focuser Focuser
+	execer  Executor
+}
+
+func NewNewFile(driver gxui.Driver, theme *basic.Theme) *NewFile {
+	n := &NewFile{file: fs.NewLocator(driver, theme, fs.All)}
+	n.Theme = theme
+	return n
+}
+
+func (n *NewFile) Name() string {
+	return "new-file"
+}
+
+func (n *NewFile) Menu() string {
+	return "File"
+}
+
+func (n *NewFile) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyN,
+	}}
+}
+
+func (n *NewFile) Start(control gxui.Control) gxui.Control {
+	n.start(control)
+	startLocator(n.file, &n.target, control)
+	n.input = focusOn(n.file)
+	return nil
+}
+
+func (n *NewFile) Next() gxui.Focusable {
+	return <-n.input
+}
+
+func (n *NewFile) Reset() {
+	n.focuser = nil
+	n.execer = nil
+}
+
+func (n *NewFile) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Focuser:
+		n.focuser = src
+	case Executor:
+		n.execer = src
+	}
+	if n.focuser == nil || n.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (n *NewFile) Exec() error {
+	path := n.file.Path()
+	if exists(path) {
+		n.Err = fmt.Sprintf("%s already exists", path)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750|os.ModeDir); err != nil {
+		n.Err = fmt.Sprintf("Could not create %s: %s", filepath.Dir(path), err)
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		n.Err = fmt.Sprintf("Could not create %s: %s", path, err)
+		return err
+	}
+	if _, err := f.WriteString(setting.ProjectFor(path).LicenseHeaderFor(path)); err != nil {
+		f.Close()
+		n.Err = fmt.Sprintf("Could not write the license header to %s: %s", path, err)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		n.Err = fmt.Sprintf("Could not create %s: %s", path, err)
+		return err
+	}
+	n.execer.Execute(n.focuser.For(focus.Path(path)))
+	n.Info = fmt.Sprintf("Created %s", path)
+	return nil
+}
+
+// NewDirectory is a command which creates a directory, along with any
+// missing parent directories.
+type NewDirectory struct {
+	status.General
+	target
+
+	dir   *fs.Locator
+	input <-chan gxui.Focusable
+}
+
+func NewNewDirectory(driver gxui.Driver, theme *basic.Theme) *NewDirectory {
+	n := &NewDirectory{dir: fs.NewLocator(driver, theme, fs.Dirs)}
+	n.Theme = theme
+	return n
+}
+
+func (n *NewDirectory) Name() string {
+	return "new-directory"
+}
+
+func (n *NewDirectory) Menu() string {
+	return "File"
+}
+
+func (n *NewDirectory) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt | gxui.ModShift,
+		Key:      gxui.KeyN,
+	}}
+}
+
+func (n *NewDirectory) Start(control gxui.Control) gxui.Control {
+	n.start(control)
+	startLocator(n.dir, &n.target, control)
+	n.input = focusOn(n.dir)
+	return nil
+}
+
+func (n *NewDirectory) Next() gxui.Focusable {
+	return <-n.input
+}
+
+func (n *NewDirectory) Exec(interface{}) bind.Status {
+	path := n.dir.Path()
+	if exists(path) {
+		n.Err = fmt.Sprintf("%s already exists", path)
+		return bind.Done
+	}
+	if err := os.MkdirAll(path, 0750|os.ModeDir); err != nil {
+		n.Err = fmt.Sprintf("Could not create %s: %s", path, err)
+		return bind.Done
+	}
+	n.Info = fmt.Sprintf("Created %s", path)
+	return bind.Done
+}
+
+// startLocator points l at the directory of t's path, or at the
+// directory of the current file under control if t has no path.
+func startLocator(l *fs.Locator, t *target, control gxui.Control) {
+	if t.path == "" {
+		l.LoadDir(control)
+		return
+	}
+	l.SetPath(t.dir() + string(filepath.Separator))
+}
+
+// focusOn returns a closed channel containing only f, for commands
+// that prompt for a single input.
+func focusOn(f gxui.Focusable) <-chan gxui.Focusable {
+	input := make(chan gxui.Focusable, 1)
+	input <- f
+	close(input)
+	return input
+}
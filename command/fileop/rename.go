@@ -0,0 +1,110 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fileop
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/fs"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// Rename is a command which moves a file or directory to a new path.
+// Open editors for anything that was moved are updated to point at
+// the new path, keeping any unsaved changes.
+type Rename struct {
+	status.General
+	target
+
+	file  *fs.Locator
+	input <-chan gxui.Focusable
+
+	editors Editors
+}
+
+func NewRename(driver gxui.Driver, theme *basic.Theme) *Rename {
+	r := &Rename{file: fs.NewLocator(driver, theme, fs.All)}
+	r.Theme = theme
+	return r
+}
+
+func (r *Rename) Name() string {
+	return "rename-file"
+}
+
+func (r *Rename) Menu() string {
+	return "File"
+}
+
+func (r *Rename) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyR,
+	}}
+}
+
+func (r *Rename) Start(control gxui.Control) gxui.Control {
+	r.start(control)
+	r.file.SetPath(r.path)
+	r.input = focusOn(r.file)
+	return nil
+}
+
+func (r *Rename) Next() gxui.Focusable {
+	return <-r.input
+}
+
+func (r *Rename) Reset() {
+	r.editors = nil
+}
+
+func (r *Rename) Store(elem interface{}) bind.Status {
+	editors, ok := elem.(Editors)
+	if !ok {
+		return bind.Waiting
+	}
+	r.editors = editors
+	return bind.Done
+}
+
+func (r *Rename) Exec() error {
+	if r.path == "" {
+		r.Err = "No file to rename"
+		return nil
+	}
+	newPath := r.file.Path()
+	if newPath == r.path {
+		r.Warn = "The new path is the same as the old one"
+		return nil
+	}
+	if exists(newPath) {
+		r.Err = fmt.Sprintf("%s already exists", newPath)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(newPath), 0750|os.ModeDir); err != nil {
+		r.Err = fmt.Sprintf("Could not create %s: %s", filepath.Dir(newPath), err)
+		return err
+	}
+	if err := os.Rename(r.path, newPath); err != nil {
+		r.Err = fmt.Sprintf("Could not rename %s: %s", r.path, err)
+		return err
+	}
+	for _, e := range r.editors.OpenEditors() {
+		renamer, ok := e.(Renamer)
+		if !ok {
+			continue
+		}
+		if path, ok := moved(e.Filepath(), r.path, newPath); ok {
+			renamer.Rename(path)
+		}
+	}
+	r.Info = fmt.Sprintf("Renamed %s to %s", r.path, newPath)
+	return nil
+}
@@ -0,0 +1,85 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+//go:build !windows && !darwin
+// +build !windows,!darwin
+
+package fileop
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const trashInfo = "[Trash Info]\nPath=%s\nDeletionDate=%s\n"
+
+// Trash moves path to the user's trash, following the freedesktop.org
+// trash specification so that file managers can restore it.  The
+// trash is in $XDG_DATA_HOME/Trash, or ~/.local/share/Trash if
+// XDG_DATA_HOME is unset.
+func Trash(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	dir, err := trashDir()
+	if err != nil {
+		return err
+	}
+	files, info := filepath.Join(dir, "files"), filepath.Join(dir, "info")
+	for _, d := range []string{files, info} {
+		if err := os.MkdirAll(d, 0700|os.ModeDir); err != nil {
+			return err
+		}
+	}
+	infoFile, name, err := createInfo(info, filepath.Base(abs))
+	if err != nil {
+		return err
+	}
+	escaped := (&url.URL{Path: abs}).EscapedPath()
+	_, err = fmt.Fprintf(infoFile, trashInfo, escaped, time.Now().Format("2006-01-02T15:04:05"))
+	if closeErr := infoFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(abs, filepath.Join(files, name))
+	}
+	if err != nil {
+		os.Remove(infoFile.Name())
+		return err
+	}
+	return nil
+}
+
+func trashDir() (string, error) {
+	if data := os.Getenv("XDG_DATA_HOME"); data != "" {
+		return filepath.Join(data, "Trash"), nil
+	}
+	home := os.Getenv("HOME")
+	if home == "" {
+		return "", fmt.Errorf("neither XDG_DATA_HOME nor HOME are set")
+	}
+	return filepath.Join(home, ".local", "share", "Trash"), nil
+}
+
+// createInfo creates the .trashinfo file for base in dir.  If another
+// file named base is already in the trash, a number is added to the
+// name.  The name that the trashed file should use is returned along
+// with the info file.
+func createInfo(dir, base string) (*os.File, string, error) {
+	name := base
+	for i := 2; ; i++ {
+		f, err := os.OpenFile(filepath.Join(dir, name+".trashinfo"), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			return f, name, nil
+		}
+		if !os.IsExist(err) {
+			return nil, "", err
+		}
+		name = fmt.Sprintf("%s.%d", base, i)
+	}
+}
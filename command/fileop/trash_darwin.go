@@ -0,0 +1,29 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fileop
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Trash moves path to ~/.Trash.  If another file with the same name
+// is already in the trash, a number is added to the name.
+func Trash(path string) error {
+	home := os.Getenv("HOME")
+	if home == "" {
+		return fmt.Errorf("HOME is not set")
+	}
+	dir := filepath.Join(home, ".Trash")
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	name := base
+	for i := 2; exists(filepath.Join(dir, name)); i++ {
+		name = fmt.Sprintf("%s %d%s", strings.TrimSuffix(base, ext), i, ext)
+	}
+	return os.Rename(path, filepath.Join(dir, name))
+}
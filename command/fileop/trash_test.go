@@ -0,0 +1,67 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+//go:build !windows && !darwin
+// +build !windows,!darwin
+
+package fileop_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/command/fileop"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+)
+
+func TestTrash(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, string) {
+		dir, err := ioutil.TempDir("", "fileop")
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.Setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+		return expect.New(t), dir
+	})
+
+	o.AfterEach(func(expect expect.Expectation, dir string) {
+		os.Unsetenv("XDG_DATA_HOME")
+		os.RemoveAll(dir)
+	})
+
+	o.Spec("it moves files to the trash with their original path", func(expect expect.Expectation, dir string) {
+		path := filepath.Join(dir, "foo bar.go")
+		expect(ioutil.WriteFile(path, []byte("foo"), 0600)).To(not(haveOccured()))
+		expect(fileop.Trash(path)).To(not(haveOccured()))
+
+		_, err := os.Stat(path)
+		expect(os.IsNotExist(err)).To(equal(true))
+
+		trash := filepath.Join(dir, "data", "Trash")
+		b, err := ioutil.ReadFile(filepath.Join(trash, "files", "foo bar.go"))
+		expect(err).To(not(haveOccured()))
+		expect(string(b)).To(equal("foo"))
+
+		info, err := ioutil.ReadFile(filepath.Join(trash, "info", "foo bar.go.trashinfo"))
+		expect(err).To(not(haveOccured()))
+		expect(strings.Contains(string(info), "Path="+filepath.ToSlash(dir)+"/foo%20bar.go\n")).To(equal(true))
+	})
+
+	o.Spec("it numbers files that are already in the trash", func(expect expect.Expectation, dir string) {
+		path := filepath.Join(dir, "foo")
+		for i := 0; i < 2; i++ {
+			expect(ioutil.WriteFile(path, []byte("foo"), 0600)).To(not(haveOccured()))
+			expect(fileop.Trash(path)).To(not(haveOccured()))
+		}
+		_, err := os.Stat(filepath.Join(dir, "data", "Trash", "files", "foo.2"))
+		expect(err).To(not(haveOccured()))
+	})
+}
@@ -0,0 +1,14 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fileop
+
+import "errors"
+
+// Trash would move path to the recycle bin, but that isn't supported
+// on windows yet.  Files are left alone rather than being deleted
+// permanently.
+func Trash(path string) error {
+	return errors.New("moving files to the recycle bin is not supported on windows")
+}
@@ -6,8 +6,6 @@ package command
 
 import (
 	"fmt"
-	"strings"
-	"unicode/utf8"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/math"
@@ -24,31 +22,46 @@ type SelectionEditor interface {
 	ScrollToRune(int)
 }
 
+// Find is a command which searches the current editor for text.
+// While the prompt is open, every match is highlighted and the caret
+// moves to the current match.  The search is kept in a *Matches so
+// that find-next and find-prev can cycle through it afterward.  How
+// the pattern is matched depends on the shared *FindOptions, which
+// can be toggled from the prompt.
 type Find struct {
 	mixins.LinearLayout
 
-	driver     gxui.Driver
-	theme      *basic.Theme
-	editor     SelectionEditor
-	display    gxui.Label
-	pattern    *findBox
-	prevS      gxui.Button
-	nextS      gxui.Button
-	selections []int
-	selection  int
+	driver  gxui.Driver
+	theme   *basic.Theme
+	editor  SelectionEditor
+	display gxui.Label
+	pattern *findBox
+	prevS   gxui.Button
+	nextS   gxui.Button
+	toggles *findToggles
+
+	// regex is whether or not patterns are always regular
+	// expressions, regardless of options.
+	regex   bool
+	options *FindOptions
+	matches *Matches
+	current int
+	from    int
 }
 
-func NewFind(driver gxui.Driver, theme *basic.Theme) *Find {
+func NewFind(driver gxui.Driver, theme *basic.Theme, matches *Matches, options *FindOptions) *Find {
 	finder := &Find{}
-	finder.Init(driver, theme)
+	finder.Init(driver, theme, matches, options)
 	return finder
 }
 
-func (f *Find) Init(driver gxui.Driver, theme *basic.Theme) {
+func (f *Find) Init(driver gxui.Driver, theme *basic.Theme, matches *Matches, options *FindOptions) {
 	f.LinearLayout.Init(f, theme)
 	f.SetDirection(gxui.RightToLeft)
 	f.driver = driver
 	f.theme = theme
+	f.matches = matches
+	f.options = options
 
 	f.display = f.theme.CreateLabel()
 	f.display.SetText("Start typing to search")
@@ -56,22 +69,48 @@ func (f *Find) Init(driver gxui.Driver, theme *basic.Theme) {
 	f.prevS = f.theme.CreateButton()
 	f.prevS.SetText("<")
 	f.prevS.OnClick(func(ev gxui.MouseEvent) {
-		if len(f.selections) != 0 {
-			f.selection = getNext(f.selection, len(f.selections), -1)
-			f.editor.ScrollToRune(f.selections[f.selection])
+		if len(f.matches.spans) != 0 {
+			f.show(getNext(f.current, len(f.matches.spans), -1))
 		}
 	})
 
 	f.nextS = f.theme.CreateButton()
 	f.nextS.SetText(">")
 	f.nextS.OnClick(func(ev gxui.MouseEvent) {
-		if len(f.selections) != 0 {
-			f.selection = getNext(f.selection, len(f.selections), 1)
-			f.editor.ScrollToRune(f.selections[f.selection])
+		if len(f.matches.spans) != 0 {
+			f.show(getNext(f.current, len(f.matches.spans), 1))
 		}
 	})
+	opts := []findOption{caseOption, wordOption}
+	if !f.regex {
+		opts = append(opts, regexOption)
+	}
+	f.toggles = newFindToggles(theme, options, opts...)
+	f.AddChild(f.toggles)
 	f.AddChild(f.nextS)
 	f.AddChild(f.prevS)
+
+	// f is attached to the command box while the prompt is open, so
+	// matches are only highlighted, and toggled options only search
+	// again, until the prompt is closed.
+	f.OnAttach(func() {
+		f.toggles.sync()
+		f.options.changed = func() {
+			f.toggles.sync()
+			if f.pattern != nil {
+				f.search()
+			}
+		}
+		if f.editor != nil && f.pattern.Text() != "" {
+			highlightMatches(f.editor, f.matches.spans)
+		}
+	})
+	f.OnDetach(func() {
+		f.options.changed = nil
+		if f.editor != nil {
+			highlightMatches(f.editor, nil)
+		}
+	})
 }
 
 func (f *Find) KeyPress(event gxui.KeyboardEvent) bool {
@@ -85,6 +124,22 @@ func (f *Find) KeyPress(event gxui.KeyboardEvent) bool {
 	return f.pattern.KeyPress(event)
 }
 
+// Text returns the pattern that f is searching for.
+func (f *Find) Text() string {
+	return f.pattern.Text()
+}
+
+// SetText replaces the pattern that f is searching for.
+func (f *Find) SetText(pattern string) {
+	f.pattern.SetText(pattern)
+	f.pattern.Controller().SetCaret(len([]rune(pattern)))
+}
+
+// SelectAll selects the whole pattern, so that typing replaces it.
+func (f *Find) SelectAll() {
+	f.pattern.SelectAll()
+}
+
 func (f *Find) KeyDown(event gxui.KeyboardEvent) {
 	f.pattern.KeyDown(event)
 }
@@ -140,40 +195,57 @@ func (f *Find) Start(control gxui.Control) gxui.Control {
 	if f.editor == nil {
 		return nil
 	}
+	f.from = 0
+	if carets := f.editor.Controller().Carets(); len(carets) > 0 {
+		f.from = carets[0]
+	}
+	if f.pattern != nil {
+		f.RemoveChild(f.pattern)
+	}
 	f.pattern = newFindBox(f.driver, f.theme)
 	f.AddChild(f.pattern)
-
 	f.pattern.OnTextChanged(func([]gxui.TextBoxEdit) {
-		f.editor.Controller().ClearSelections()
-		needle := f.pattern.Text()
-		if len(needle) == 0 {
-			f.display.SetText("Start typing to search")
-			return
-		}
-		f.selections = []int{}
-
-		haystack := f.editor.Text()
-		start := 0
-		var selections []gxui.TextSelection
-
-		count := utf8.RuneCountInString(needle)
-		length := len(needle)
-		pos := 0
-		for next := strings.Index(haystack, needle); next != -1; next = strings.Index(haystack[start:], needle) {
-			pos += utf8.RuneCountInString(haystack[start : start+next])
-			selection := gxui.CreateTextSelection(pos, pos+count, false)
-			selections = append(selections, selection)
-			f.selections = append(f.selections, pos)
-			pos += count
-			start += (next + length)
-		}
-		f.selection = len(f.selections) - 1
-		f.editor.SelectSlice(selections)
-		f.display.SetText(fmt.Sprintf("%s: %d results found", needle, len(selections)))
+		f.search()
 	})
+	f.display.SetText("Start typing to search")
 	return f.display
 }
 
+// search finds the matches for the current pattern, highlights them,
+// and selects the first one after the caret position that the search
+// started from.
+func (f *Find) search() {
+	f.matches.clear()
+	defer func() {
+		highlightMatches(f.editor, f.matches.spans)
+	}()
+	needle := f.pattern.Text()
+	if len(needle) == 0 {
+		f.display.SetText("Start typing to search")
+		return
+	}
+	_, search, err := f.options.compile(needle, f.regex)
+	if err != nil {
+		f.display.SetText(err.Error())
+		return
+	}
+	f.matches.search = search
+	spans := f.matches.Find(f.editor.Text())
+	if len(spans) == 0 {
+		f.editor.Controller().SetCaret(f.from)
+		f.display.SetText("Match not found")
+		return
+	}
+	f.show(nextMatch(spans, f.from, false))
+}
+
+// show selects the match at index i and scrolls to it.
+func (f *Find) show(i int) {
+	f.current = i
+	selectMatch(f.editor, f.matches.spans, i)
+	f.display.SetText(matchCount(i, len(f.matches.spans)))
+}
+
 func (f *Find) Name() string {
 	return "find"
 }
@@ -0,0 +1,83 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+// Matches is the last search that was run by find or regex-find in a
+// file.  It is shared with find-next and find-prev, which search
+// for the same pattern again after the prompt has been closed.
+type Matches struct {
+	search func(text string) []input.Span
+	spans  []input.Span
+}
+
+// Find runs the last search against text and returns the matches,
+// which are in runes.
+func (m *Matches) Find(text string) []input.Span {
+	m.spans = nil
+	if m.search != nil {
+		m.spans = m.search(text)
+	}
+	return m.spans
+}
+
+func (m *Matches) clear() {
+	m.search = nil
+	m.spans = nil
+}
+
+// highlightMatches replaces the layer of matches in e with spans.
+// Other syntax layers are left alone.
+func highlightMatches(e input.Editor, spans []input.Span) {
+	var layers []input.SyntaxLayer
+	for _, l := range e.SyntaxLayers() {
+		if l.Construct != theme.Match {
+			layers = append(layers, l)
+		}
+	}
+	if len(spans) > 0 {
+		layers = append(layers, input.SyntaxLayer{Construct: theme.Match, Spans: spans})
+	}
+	e.SetSyntaxLayers(layers)
+}
+
+// nextMatch returns the index of the first match in spans that starts
+// at or after pos, or the index of the last match that ends before
+// pos if backward is true.  It wraps around when there is no match in
+// that direction.
+func nextMatch(spans []input.Span, pos int, backward bool) int {
+	if backward {
+		for i := len(spans) - 1; i >= 0; i-- {
+			if spans[i].End < pos {
+				return i
+			}
+		}
+		return len(spans) - 1
+	}
+	for i, s := range spans {
+		if s.Start >= pos {
+			return i
+		}
+	}
+	return 0
+}
+
+// selectMatch selects the match at index i in e and scrolls to it.
+func selectMatch(e SelectionEditor, spans []input.Span, i int) {
+	s := spans[i]
+	e.SelectSlice([]gxui.TextSelection{gxui.CreateTextSelection(s.Start, s.End, false)})
+	e.ScrollToRune(s.Start)
+}
+
+func matchCount(i, count int) string {
+	return fmt.Sprintf("%d of %d", i+1, count)
+}
@@ -0,0 +1,108 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// findStep is the shared implementation of FindNext and FindPrev.  It
+// runs the last find or regex-find again and selects the closest
+// match to the caret in one direction, wrapping around at the start
+// and end of the file.
+type findStep struct {
+	status.General
+
+	backward bool
+	matches  *Matches
+	editor   SelectionEditor
+}
+
+func (f *findStep) Menu() string {
+	return "Edit"
+}
+
+func (f *findStep) Reset() {
+	f.Clear()
+	f.editor = nil
+}
+
+func (f *findStep) Store(elem interface{}) bind.Status {
+	editor, ok := elem.(SelectionEditor)
+	if !ok {
+		return bind.Waiting
+	}
+	f.editor = editor
+	return bind.Done
+}
+
+func (f *findStep) Exec() error {
+	if f.matches.search == nil {
+		f.Warn = "Nothing to search for; use find or regex-find first"
+		return nil
+	}
+	spans := f.matches.Find(f.editor.Text())
+	if len(spans) == 0 {
+		f.Warn = "Match not found"
+		return nil
+	}
+	pos := 0
+	if carets := f.editor.Controller().Carets(); len(carets) > 0 {
+		pos = carets[len(carets)-1]
+	}
+	i := nextMatch(spans, pos, f.backward)
+	selectMatch(f.editor, spans, i)
+	f.Info = matchCount(i, len(spans))
+	return nil
+}
+
+// FindNext is a command which selects the next match of the last
+// search in the current file.
+type FindNext struct {
+	findStep
+}
+
+func NewFindNext(theme gxui.Theme, matches *Matches) *FindNext {
+	f := &FindNext{findStep: findStep{matches: matches}}
+	f.Theme = theme
+	return f
+}
+
+func (f *FindNext) Name() string {
+	return "find-next"
+}
+
+func (f *FindNext) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Key: gxui.KeyF3,
+	}}
+}
+
+// FindPrev is a command which selects the previous match of the last
+// search in the current file.
+type FindPrev struct {
+	findStep
+}
+
+func NewFindPrev(theme gxui.Theme, matches *Matches) *FindPrev {
+	f := &FindPrev{findStep: findStep{backward: true, matches: matches}}
+	f.Theme = theme
+	return f
+}
+
+func (f *FindPrev) Name() string {
+	return "find-prev"
+}
+
+func (f *FindPrev) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModShift,
+		Key:      gxui.KeyF3,
+	}}
+}
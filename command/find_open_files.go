@@ -0,0 +1,112 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// An OpenSearcher is a pane that can search the text of open files and
+// list the matches.
+type OpenSearcher interface {
+	SearchOpen(pattern string, texts map[string]string) error
+}
+
+// FindInOpenFiles is a command which searches the open editors for a
+// regular expression.  Their current text is searched, so unsaved
+// changes are included, and the matches are listed in the search pane.
+type FindInOpenFiles struct {
+	status.General
+
+	pattern *findBox
+	prompt  gxui.Label
+	input   <-chan gxui.Focusable
+
+	editors  ProjectEditors
+	searcher OpenSearcher
+}
+
+func NewFindInOpenFiles(driver gxui.Driver, theme *basic.Theme) *FindInOpenFiles {
+	f := &FindInOpenFiles{
+		pattern: newFindBox(driver, theme),
+		prompt:  theme.CreateLabel(),
+	}
+	f.Theme = theme
+	f.prompt.SetText("Find in open files (regexp):")
+	return f
+}
+
+func (f *FindInOpenFiles) Name() string {
+	return "find-in-open-files"
+}
+
+func (f *FindInOpenFiles) Menu() string {
+	return "Edit"
+}
+
+func (f *FindInOpenFiles) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (f *FindInOpenFiles) Start(gxui.Control) gxui.Control {
+	f.pattern.SetText("")
+	input := make(chan gxui.Focusable, 1)
+	input <- f.pattern
+	close(input)
+	f.input = input
+	return f.prompt
+}
+
+func (f *FindInOpenFiles) Next() gxui.Focusable {
+	return <-f.input
+}
+
+func (f *FindInOpenFiles) Reset() {
+	f.editors = nil
+	f.searcher = nil
+}
+
+func (f *FindInOpenFiles) Store(target interface{}) bind.Status {
+	switch src := target.(type) {
+	case ProjectEditors:
+		f.editors = src
+	case OpenSearcher:
+		f.searcher = src
+	}
+	if f.editors != nil && f.searcher != nil {
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (f *FindInOpenFiles) Exec() error {
+	pattern := f.pattern.Text()
+	if pattern == "" {
+		f.Warn = "No pattern provided"
+		return nil
+	}
+	texts := make(map[string]string)
+	for _, e := range f.editors.OpenEditors() {
+		// Views of the same file share their text, so each file
+		// is only searched once.
+		if _, ok := texts[e.Filepath()]; !ok {
+			texts[e.Filepath()] = e.Text()
+		}
+	}
+	if len(texts) == 0 {
+		f.Warn = "No files are open"
+		return nil
+	}
+	if err := f.searcher.SearchOpen(pattern, texts); err != nil {
+		f.Err = fmt.Sprintf("Invalid pattern: %s", err)
+		return fmt.Errorf("command.FindInOpenFiles: %s", f.Err)
+	}
+	return nil
+}
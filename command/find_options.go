@@ -0,0 +1,211 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/search"
+)
+
+// FindOptions are the options that find, regex-find, and replace
+// match their patterns with.  They're shared by every file, and each
+// of them can be toggled by a command while one of those prompts is
+// open.
+type FindOptions struct {
+	search.Options
+
+	// changed is called after an option is toggled.  The prompt that
+	// is open sets it, so that it can search again.
+	changed func()
+}
+
+// NewFindOptions returns the default *FindOptions, which match
+// literal text case sensitively.
+func NewFindOptions() *FindOptions {
+	return &FindOptions{Options: search.Options{CaseSensitive: true}}
+}
+
+func (o *FindOptions) toggle(opt *bool) {
+	*opt = !*opt
+	if o.changed != nil {
+		o.changed()
+	}
+}
+
+// compile returns the function that finds the matches of pattern.
+// Patterns are always regular expressions if regex is true.
+func (o *FindOptions) compile(pattern string, regex bool) (*search.Pattern, func(string) []input.Span, error) {
+	opts := o.Options
+	opts.Regex = opts.Regex || regex
+	p, err := search.Compile(pattern, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, func(text string) []input.Span {
+		found := p.Find(text)
+		spans := make([]input.Span, 0, len(found))
+		for _, s := range found {
+			spans = append(spans, input.Span{Start: s.Start, End: s.End})
+		}
+		return spans
+	}, nil
+}
+
+// findOption is one of the FindOptions, as it's displayed and toggled.
+type findOption struct {
+	name  string
+	label string
+	desc  string
+	key   gxui.KeyboardKey
+	field func(*search.Options) *bool
+}
+
+var (
+	caseOption = findOption{
+		name:  "toggle-find-case-sensitive",
+		label: "Aa",
+		desc:  "Case sensitive",
+		key:   gxui.KeyC,
+		field: func(o *search.Options) *bool { return &o.CaseSensitive },
+	}
+	wordOption = findOption{
+		name:  "toggle-find-whole-word",
+		label: "W",
+		desc:  "Whole word",
+		key:   gxui.KeyW,
+		field: func(o *search.Options) *bool { return &o.WholeWord },
+	}
+	regexOption = findOption{
+		name:  "toggle-find-regex",
+		label: ".*",
+		desc:  "Regex",
+		key:   gxui.KeyR,
+		field: func(o *search.Options) *bool { return &o.Regex },
+	}
+	preserveCaseOption = findOption{
+		name:  "toggle-replace-preserve-case",
+		label: "AB",
+		desc:  "Preserve case",
+		key:   gxui.KeyP,
+		field: func(o *search.Options) *bool { return &o.PreserveCase },
+	}
+)
+
+// ToggleFindOption is a command that toggles one of the FindOptions.
+// It doesn't close the find or replace prompt when it runs, so its key
+// binding can be pressed while the prompt is open.
+type ToggleFindOption struct {
+	status.General
+
+	option  findOption
+	options *FindOptions
+}
+
+func newToggleFindOption(theme gxui.Theme, options *FindOptions, option findOption) *ToggleFindOption {
+	t := &ToggleFindOption{option: option, options: options}
+	t.Theme = theme
+	return t
+}
+
+// NewToggleFindCase returns a command that toggles case sensitive
+// matching.
+func NewToggleFindCase(theme gxui.Theme, options *FindOptions) *ToggleFindOption {
+	return newToggleFindOption(theme, options, caseOption)
+}
+
+// NewToggleFindWholeWord returns a command that toggles whole word
+// matching.
+func NewToggleFindWholeWord(theme gxui.Theme, options *FindOptions) *ToggleFindOption {
+	return newToggleFindOption(theme, options, wordOption)
+}
+
+// NewToggleFindRegex returns a command that toggles matching patterns
+// as regular expressions.
+func NewToggleFindRegex(theme gxui.Theme, options *FindOptions) *ToggleFindOption {
+	return newToggleFindOption(theme, options, regexOption)
+}
+
+// NewTogglePreserveCase returns a command that toggles case preserving
+// replacements.
+func NewTogglePreserveCase(theme gxui.Theme, options *FindOptions) *ToggleFindOption {
+	return newToggleFindOption(theme, options, preserveCaseOption)
+}
+
+func (t *ToggleFindOption) Name() string {
+	return t.option.name
+}
+
+func (t *ToggleFindOption) Menu() string {
+	return "Edit"
+}
+
+func (t *ToggleFindOption) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModAlt,
+		Key:      t.option.key,
+	}}
+}
+
+// KeepsPrompt tells the commander to run t without closing the prompt
+// that is open.
+func (t *ToggleFindOption) KeepsPrompt() bool {
+	return true
+}
+
+func (t *ToggleFindOption) Exec(interface{}) bind.Status {
+	opt := t.option.field(&t.options.Options)
+	t.options.toggle(opt)
+	state := "off"
+	if *opt {
+		state = "on"
+	}
+	t.Info = fmt.Sprintf("%s: %s", t.option.desc, state)
+	return bind.Done
+}
+
+// findToggles is a row of toggle buttons for the FindOptions, which
+// is displayed in the find and replace prompts.
+type findToggles struct {
+	gxui.LinearLayout
+
+	options *FindOptions
+	buttons map[gxui.Button]findOption
+}
+
+func newFindToggles(theme *basic.Theme, options *FindOptions, opts ...findOption) *findToggles {
+	t := &findToggles{
+		LinearLayout: theme.CreateLinearLayout(),
+		options:      options,
+		buttons:      make(map[gxui.Button]findOption),
+	}
+	t.SetDirection(gxui.LeftToRight)
+	for _, opt := range opts {
+		opt := opt
+		b := theme.CreateButton()
+		b.SetType(gxui.ToggleButton)
+		b.SetText(opt.label)
+		b.OnClick(func(gxui.MouseEvent) {
+			options.toggle(opt.field(&options.Options))
+			t.sync()
+		})
+		t.buttons[b] = opt
+		t.AddChild(b)
+	}
+	t.sync()
+	return t
+}
+
+// sync checks the buttons for the options that are on.
+func (t *findToggles) sync() {
+	for b, opt := range t.buttons {
+		b.SetChecked(*opt.field(&t.options.Options))
+	}
+}
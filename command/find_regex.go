@@ -6,66 +6,25 @@ package command
 
 import (
 	"fmt"
-	"regexp"
-	"unicode/utf8"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/themes/basic"
 )
 
+// RegexFind is like Find, but its pattern is always a regular
+// expression.
 type RegexFind struct {
 	finder *Find
 }
 
-func NewRegexFind(driver gxui.Driver, theme *basic.Theme) *RegexFind {
-	f := &RegexFind{}
-	f.finder = NewFind(driver, theme)
+func NewRegexFind(driver gxui.Driver, theme *basic.Theme, matches *Matches, options *FindOptions) *RegexFind {
+	f := &RegexFind{finder: &Find{regex: true}}
+	f.finder.Init(driver, theme, matches, options)
 	return f
 }
 
 func (f *RegexFind) Start(control gxui.Control) gxui.Control {
-	f.finder.editor = findEditor(control)
-	if f.finder.editor == nil {
-		return nil
-	}
-	f.finder.pattern = newFindBox(f.finder.driver, f.finder.theme)
-	f.finder.AddChild(f.finder.pattern)
-	f.finder.pattern.OnTextChanged(func([]gxui.TextBoxEdit) {
-		f.finder.editor.Controller().ClearSelections()
-		needle := f.finder.pattern.Text()
-		if len(needle) == 0 {
-			f.finder.display.SetText("Start typing to search")
-			return
-		}
-		exp, err := regexp.Compile(needle)
-		if err != nil {
-			f.finder.display.SetText("Incorrect regexp")
-			return
-		}
-		f.finder.selections = []int{}
-		haystack := f.finder.editor.Text()
-		arr := exp.FindAllStringIndex(haystack, -1)
-		if arr == nil {
-			f.finder.display.SetText("Match not found")
-			return
-		}
-
-		var selections []gxui.TextSelection
-
-		for _, indexes := range arr {
-			for i := 0; i < len(indexes); i += 2 {
-				start := utf8.RuneCountInString(haystack[0:indexes[i]])
-				end := start + utf8.RuneCountInString(haystack[indexes[i]:indexes[i+1]])
-				selection := gxui.CreateTextSelection(start, end, false)
-				selections = append(selections, selection)
-				f.finder.selections = append(f.finder.selections, start)
-			}
-		}
-		f.finder.selection = len(selections) - 1
-		f.finder.editor.SelectSlice(selections)
-		f.finder.display.SetText(fmt.Sprintf("%s: %d results found", needle, len(selections)))
-	})
-	return f.finder.display
+	return f.finder.Start(control)
 }
 
 func (f *RegexFind) Name() string {
@@ -58,6 +58,15 @@ type FileChanger interface {
 	FileChanged(oldPath, newPath string)
 }
 
+// A Jumper is a type that needs to be called before the focused
+// editor's carets jump somewhere else, either in the same file or in
+// another one.
+type Jumper interface {
+	// Jumping will be called with the focused editor before its
+	// carets are moved or another file is focused.
+	Jumping(from input.Editor)
+}
+
 // A Binder is a type which can bind bindables
 type Binder interface {
 	Push(...bind.Bindable)
@@ -150,6 +159,7 @@ type Location struct {
 
 	binders  []FileBinder
 	changers []FileChanger
+	jumpers  []Jumper
 }
 
 // NewLocation returns a *Location bound to the passed in driver.
@@ -170,6 +180,7 @@ func (l *Location) For(opts ...Opt) bind.Bindable {
 	}
 	newL.binders = append(newL.binders, l.binders...)
 	newL.changers = append(newL.changers, l.changers...)
+	newL.jumpers = append(newL.jumpers, l.jumpers...)
 	for _, o := range opts {
 		if err := o(newL); err != nil {
 			if len(newL.Warn) != 0 {
@@ -228,6 +239,11 @@ func (l *Location) moverReady() bool {
 func (l *Location) Exec() error {
 	var oldPath string
 	e := l.opener.CurrentEditor()
+	if e != nil && l.jumps(e) {
+		for _, j := range l.jumpers {
+			j.Jumping(e)
+		}
+	}
 	if !l.skipUnbind && e != nil {
 		oldPath = e.Filepath()
 		l.binder.Pop()
@@ -262,6 +278,15 @@ func (l *Location) Exec() error {
 	return nil
 }
 
+// jumps returns whether or not executing l will move away from the
+// carets' current position in e.
+func (l *Location) jumps(e input.Editor) bool {
+	if l.path != "" && l.path != e.Filepath() {
+		return true
+	}
+	return l.offset != nil || l.line != nil || l.col != nil
+}
+
 func (l *Location) moveCarets(s LineStarter) {
 	if l.offset == nil && l.line == nil && l.col == nil {
 		return
@@ -288,8 +313,10 @@ func (l *Location) Bind(h bind.Bindable) (bind.HookedMultiOp, error) {
 		newF.binders = append(newF.binders, src)
 	case FileChanger:
 		newF.changers = append(newF.changers, src)
+	case Jumper:
+		newF.jumpers = append(newF.jumpers, src)
 	default:
-		return nil, fmt.Errorf("expected hook to be FileBinder or FileChanger, was %T", h)
+		return nil, fmt.Errorf("expected hook to be FileBinder, FileChanger, or Jumper, was %T", h)
 	}
 	return newF, nil
 }
@@ -0,0 +1,227 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package fold contains commands for folding regions of code, along
+// with a hook that keeps folded regions in place while files are
+// edited.
+//
+// Folded regions are stored in the session, with the rest of the
+// state of each open file.
+package fold
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, _ gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{
+		Tracker{},
+		NewFold(theme),
+		NewUnfold(theme),
+		NewFoldAll(theme),
+	}
+}
+
+// An Editor is an input.Editor that can fold regions of its text.
+type Editor interface {
+	input.Editor
+	Carets() []int
+	LineIndex(int) int
+	Folds() []input.Fold
+	Fold(line int) bool
+	Unfold(line int) bool
+	FoldAll(input.FoldKind)
+	ShiftFolds([]input.Edit)
+}
+
+// Tracker is a hook which moves folded regions along with the lines
+// they are on while a file is edited.
+type Tracker struct{}
+
+func (Tracker) Name() string {
+	return "fold-tracker"
+}
+
+func (Tracker) OpName() string {
+	return "input-handler"
+}
+
+func (Tracker) Applied(e input.Editor, edits []input.Edit) {
+	if f, ok := e.(Editor); ok {
+		f.ShiftFolds(edits)
+	}
+}
+
+// caretLine returns the line that e's last caret is on.
+func caretLine(e Editor) (int, bool) {
+	carets := e.Carets()
+	if len(carets) == 0 {
+		return 0, false
+	}
+	return e.LineIndex(carets[len(carets)-1]), true
+}
+
+// Fold is a command which folds the innermost region around the
+// caret.
+type Fold struct {
+	status.General
+
+	editor Editor
+}
+
+func NewFold(theme gxui.Theme) *Fold {
+	f := &Fold{}
+	f.Theme = theme
+	return f
+}
+
+func (f *Fold) Name() string {
+	return "fold"
+}
+
+func (f *Fold) Menu() string {
+	return "View"
+}
+
+func (f *Fold) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyLeftBracket,
+	}}
+}
+
+func (f *Fold) Reset() {
+	f.Clear()
+	f.editor = nil
+}
+
+func (f *Fold) Store(elem interface{}) bind.Status {
+	if e, ok := elem.(Editor); ok {
+		f.editor = e
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (f *Fold) Exec() error {
+	line, ok := caretLine(f.editor)
+	if !ok {
+		f.Warn = "No caret to fold at"
+		return nil
+	}
+	if !f.editor.Fold(line) {
+		f.Warn = fmt.Sprintf("Nothing to fold on line %d", line+1)
+	}
+	return nil
+}
+
+// Unfold is a command which unfolds the innermost folded region
+// around the caret.
+type Unfold struct {
+	status.General
+
+	editor Editor
+}
+
+func NewUnfold(theme gxui.Theme) *Unfold {
+	u := &Unfold{}
+	u.Theme = theme
+	return u
+}
+
+func (u *Unfold) Name() string {
+	return "unfold"
+}
+
+func (u *Unfold) Menu() string {
+	return "View"
+}
+
+func (u *Unfold) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyRightBracket,
+	}}
+}
+
+func (u *Unfold) Reset() {
+	u.Clear()
+	u.editor = nil
+}
+
+func (u *Unfold) Store(elem interface{}) bind.Status {
+	if e, ok := elem.(Editor); ok {
+		u.editor = e
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (u *Unfold) Exec() error {
+	line, ok := caretLine(u.editor)
+	if !ok {
+		u.Warn = "No caret to unfold at"
+		return nil
+	}
+	if !u.editor.Unfold(line) {
+		u.Warn = fmt.Sprintf("Line %d is not folded", line+1)
+	}
+	return nil
+}
+
+// FoldAll is a command which folds the body of every function in the
+// current file.
+type FoldAll struct {
+	status.General
+
+	editor Editor
+}
+
+func NewFoldAll(theme gxui.Theme) *FoldAll {
+	f := &FoldAll{}
+	f.Theme = theme
+	return f
+}
+
+func (f *FoldAll) Name() string {
+	return "fold-all-functions"
+}
+
+func (f *FoldAll) Menu() string {
+	return "View"
+}
+
+func (f *FoldAll) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt | gxui.ModShift,
+		Key:      gxui.KeyLeftBracket,
+	}}
+}
+
+func (f *FoldAll) Reset() {
+	f.Clear()
+	f.editor = nil
+}
+
+func (f *FoldAll) Store(elem interface{}) bind.Status {
+	if e, ok := elem.(Editor); ok {
+		f.editor = e
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (f *FoldAll) Exec() error {
+	f.editor.FoldAll(input.FoldFunc)
+	return nil
+}
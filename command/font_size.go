@@ -0,0 +1,119 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+const (
+	// minFontSize and maxFontSize are the limits for changing the
+	// font size at runtime.
+	minFontSize = 6
+	maxFontSize = 72
+)
+
+// A Fonter is a type that can change the font of its controls.
+type Fonter interface {
+	SetFont(gxui.Font)
+}
+
+// FontSize is a command which changes the size of the font without
+// restarting the editor.  The new size is saved as the size to use on
+// startup.
+type FontSize struct {
+	status.General
+
+	name  string
+	key   gxui.KeyboardKey
+	delta int
+
+	driver gxui.Driver
+	theme  *basic.Theme
+
+	fonter Fonter
+}
+
+// NewIncreaseFontSize returns a *FontSize that makes the font one
+// size larger.
+func NewIncreaseFontSize(driver gxui.Driver, theme *basic.Theme) *FontSize {
+	return newFontSize(driver, theme, "increase-font-size", gxui.KeyEqual, 1)
+}
+
+// NewDecreaseFontSize returns a *FontSize that makes the font one
+// size smaller.
+func NewDecreaseFontSize(driver gxui.Driver, theme *basic.Theme) *FontSize {
+	return newFontSize(driver, theme, "decrease-font-size", gxui.KeyMinus, -1)
+}
+
+// NewResetFontSize returns a *FontSize that resets the font to the
+// size in the fonts setting.
+func NewResetFontSize(driver gxui.Driver, theme *basic.Theme) *FontSize {
+	return newFontSize(driver, theme, "reset-font-size", gxui.Key0, 0)
+}
+
+func newFontSize(driver gxui.Driver, theme *basic.Theme, name string, key gxui.KeyboardKey, delta int) *FontSize {
+	f := &FontSize{
+		name:   name,
+		key:    key,
+		delta:  delta,
+		driver: driver,
+		theme:  theme,
+	}
+	f.Theme = theme
+	return f
+}
+
+func (f *FontSize) Name() string {
+	return f.name
+}
+
+func (f *FontSize) Menu() string {
+	return "View"
+}
+
+func (f *FontSize) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl,
+		Key:      f.key,
+	}}
+}
+
+func (f *FontSize) Reset() {
+	f.fonter = nil
+}
+
+func (f *FontSize) Store(elem interface{}) bind.Status {
+	fonter, ok := elem.(Fonter)
+	if !ok {
+		return bind.Waiting
+	}
+	f.fonter = fonter
+	return bind.Done
+}
+
+func (f *FontSize) Exec() error {
+	size := 0
+	if f.delta != 0 {
+		size = f.theme.DefaultMonospaceFont().Size() + f.delta
+		if size < minFontSize || size > maxFontSize {
+			f.Warn = fmt.Sprintf("Font size must be between %d and %d", minFontSize, maxFontSize)
+			return nil
+		}
+	}
+	font := setting.PrefFontSize(f.driver, size)
+	// The size is saved first, since projects with their own fonts
+	// load them at the saved size.
+	setting.SetFontSize(size)
+	f.fonter.SetFont(font)
+	f.Info = fmt.Sprintf("Font size is now %d", font.Size())
+	return nil
+}
@@ -38,18 +38,6 @@ func newFileBox(driver gxui.Driver, theme *basic.Theme, l *Locator) *fileBox {
 	return file
 }
 
-func nonMetaCompletion(comps []valueLabel) string {
-	for _, c := range comps {
-		switch c.Text() {
-		case metaCurrDir, metaNewFile:
-			continue
-		default:
-			return c.Text()
-		}
-	}
-	return ""
-}
-
 func (f *fileBox) KeyPress(event gxui.KeyboardEvent) bool {
 	l := f.locator
 	if event.Modifier != 0 {
@@ -71,23 +59,15 @@ func (f *fileBox) KeyPress(event gxui.KeyboardEvent) bool {
 			defer l.lock.RLock()
 			l.updateCompletions()
 		}()
-		c := nonMetaCompletion(l.completions)
+		c, only := l.nextCandidate()
 		if c == "" {
 			return false
 		}
-		fullPath := filepath.Join(l.dir.Text(), c)
-		finfo, err := os.Stat(fullPath)
-		if os.IsNotExist(err) {
-			return false
-		}
-		if err != nil {
-			return false
-		}
-		if !finfo.IsDir() {
-			l.file.setFile(finfo.Name())
-			return false
+		if !only || c[len(c)-1] != filepath.Separator {
+			l.file.setFile(strings.TrimSuffix(c, string(filepath.Separator)))
+			return true
 		}
-		l.dir.SetText(fullPath)
+		l.dir.SetText(filepath.Join(l.dir.Text(), c))
 		l.file.setFile("")
 		go l.loadDirContents()
 		return true
@@ -248,7 +228,7 @@ type Elementer interface {
 }
 
 func findStart(control gxui.Control) string {
-	if startingPath := findCurrentFile(control); startingPath != "" {
+	if startingPath := CurrentFile(control); startingPath != "" {
 		return filepath.Dir(startingPath)
 	}
 	if project, ok := findProject(control); ok {
@@ -257,13 +237,16 @@ func findStart(control gxui.Control) string {
 	return setting.DefaultProject.Path
 }
 
-func findCurrentFile(control gxui.Control) string {
+// CurrentFile returns the path of the file that is open in the
+// current editor under control, or an empty string if no file is
+// open.
+func CurrentFile(control gxui.Control) string {
 	switch src := control.(type) {
 	case FileGetter:
 		return src.CurrentFile()
 	case gxui.Parent:
 		for _, child := range src.Children() {
-			if file := findCurrentFile(child.Control); file != "" {
+			if file := CurrentFile(child.Control); file != "" {
 				return file
 			}
 		}
@@ -271,6 +254,15 @@ func findCurrentFile(control gxui.Control) string {
 	return ""
 }
 
+// CurrentProject returns the project that is open under control, or
+// the default project if none is.
+func CurrentProject(control gxui.Control) setting.Project {
+	if project, ok := findProject(control); ok {
+		return project
+	}
+	return setting.DefaultProject
+}
+
 func findProject(e interface{}) (setting.Project, bool) {
 	switch src := e.(type) {
 	case Projecter:
@@ -24,6 +24,11 @@ const (
 	minInputChars = 10
 	metaNewFile   = "<new file>"
 	metaCurrDir   = "<current dir>"
+	metaGlob      = "<all matches>"
+
+	// globChars are the characters that make a file name a pattern
+	// for filepath.Match.
+	globChars = "*?["
 )
 
 var (
@@ -99,6 +104,12 @@ type Locator struct {
 	completions []valueLabel
 	files       []string
 	mod         Mod
+
+	// cycle holds the candidates that tab is cycling through, and
+	// cycled is the candidate that was last filled in from them.
+	cycle    []string
+	cycleIdx int
+	cycled   string
 }
 
 // NewLocator initializes and returns a *Locator.
@@ -146,6 +157,18 @@ func (f *Locator) SetPath(filePath string) {
 	f.file.SetText(file)
 }
 
+// Text returns the path that f points to.  Along with SetText, it lets
+// the commander keep a history of the paths that f was used for.
+func (f *Locator) Text() string {
+	return f.Path()
+}
+
+// SetText points f at path.
+func (f *Locator) SetText(path string) {
+	f.SetPath(path)
+	f.file.Controller().SetCaret(len([]rune(f.file.Text())))
+}
+
 func (f *Locator) KeyPress(event gxui.KeyboardEvent) bool {
 	return f.file.KeyPress(event)
 }
@@ -206,11 +229,15 @@ func (f *Locator) updateCompletions() {
 
 	f.clearCompletions(f.completions)
 
-	f.completions = []valueLabel{metaLabel(f.driver, f.theme, f.file.Text())}
-	newCompletions := scoring.Sort(f.files, f.file.Text())
+	text := f.file.Text()
+	f.completions = []valueLabel{metaLabel(f.driver, f.theme, text)}
+	newCompletions := scoring.Sort(f.files, text)
+	if HasGlob(text) {
+		newCompletions = globMatches(f.files, text)
+	}
 
 	for _, comp := range newCompletions {
-		if strings.TrimSuffix(comp, string(filepath.Separator)) == f.file.Text() {
+		if strings.TrimSuffix(comp, string(filepath.Separator)) == text {
 			// the meta entry will be incorrect
 			f.completions = f.completions[1:]
 		}
@@ -226,6 +253,31 @@ func (f *Locator) updateCompletions() {
 	f.addCompletions(f.completions)
 }
 
+// nextCandidate returns the next completion for tab to fill in, and
+// whether or not it's the only one.  Pressing tab again after a
+// candidate has been filled in moves on to the candidate after it,
+// until the text is edited.
+func (f *Locator) nextCandidate() (c string, only bool) {
+	if len(f.cycle) == 0 || f.file.Text() != f.cycled {
+		f.cycle = nil
+		for _, c := range f.completions {
+			switch c.Text() {
+			case metaCurrDir, metaNewFile, metaGlob:
+				continue
+			}
+			f.cycle = append(f.cycle, c.Text())
+		}
+		f.cycleIdx = -1
+	}
+	if len(f.cycle) == 0 {
+		return "", false
+	}
+	f.cycleIdx = (f.cycleIdx + 1) % len(f.cycle)
+	c = f.cycle[f.cycleIdx]
+	f.cycled = strings.TrimSuffix(c, string(filepath.Separator))
+	return c, len(f.cycle) == 1
+}
+
 func (f *Locator) clearCompletions(completions []valueLabel) {
 	cloned := append([]valueLabel{}, completions...)
 	f.driver.Call(func() {
@@ -265,7 +317,11 @@ func (f *Locator) loadDirContents() {
 		log.Printf("Unexpected error trying to read directory %s: %s", f.dir.Text(), err)
 		return
 	}
+	root := setting.ProjectRoot(dir)
 	for _, finfo := range contents {
+		if setting.Ignored(root, filepath.Join(dir, finfo.Name()), finfo.IsDir()) {
+			continue
+		}
 		if f.mod.match(finfo) {
 			name := finfo.Name()
 			if finfo.IsDir() {
@@ -294,5 +350,31 @@ func metaLabel(d gxui.Driver, t gxui.Theme, comp string) valueLabel {
 		return l
 	}
 	l.text = metaNewFile
+	if HasGlob(comp) {
+		l.text = metaGlob
+	}
 	return l
 }
+
+// HasGlob returns whether or not name is a pattern that should be
+// matched against file names rather than used as a file name itself.
+func HasGlob(name string) bool {
+	return strings.ContainsAny(name, globChars)
+}
+
+// globMatches returns the names in files that match pattern.
+// Directory names are matched without their trailing separator.
+func globMatches(files []string, pattern string) []string {
+	var matches []string
+	for _, name := range files {
+		ok, err := filepath.Match(pattern, strings.TrimSuffix(name, string(filepath.Separator)))
+		if err != nil {
+			// The pattern is still being typed (e.g. an unclosed '[').
+			return nil
+		}
+		if ok {
+			matches = append(matches, name)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,134 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package gopkg contains a command for opening go packages by their
+// import path, whether they're in the project, the standard library,
+// or the module cache.
+package gopkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{
+		NewOpen(driver, theme),
+	}
+}
+
+// A Focuser is a type that can create a bindable which focuses a
+// file.
+type Focuser interface {
+	For(...focus.Opt) bind.Bindable
+}
+
+// An Executor is a type that can execute bindables.
+type Executor interface {
+	Execute(bind.Bindable)
+}
+
+// Package is a go package that can be opened.
+type Package struct {
+	ImportPath string
+	Dir        string
+}
+
+// listFormat is the template that go list prints each package with,
+// which ParseList reads.
+const listFormat = "{{.ImportPath}}\t{{.Dir}}"
+
+// List returns the packages that proj can import: its own packages,
+// their dependencies from the module graph, and the standard library.
+func List(proj setting.Project) ([]Package, error) {
+	cmd := exec.Command("go", "list", "-e", "-deps", "-f", listFormat, "./...", "std")
+	cmd.Dir = proj.Path
+	cmd.Env = proj.GoEnviron()
+	out, err := cmd.Output()
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("could not list packages: %s", err)
+	}
+	return ParseList(string(out)), nil
+}
+
+// Resolve returns the package with importPath, as it would be imported
+// from proj.  Packages that proj doesn't depend on yet can still be
+// resolved if they're in the module cache.
+func Resolve(proj setting.Project, importPath string) (Package, error) {
+	cmd := exec.Command("go", "list", "-e", "-f", listFormat, importPath)
+	cmd.Dir = proj.Path
+	cmd.Env = proj.GoEnviron()
+	out, err := cmd.Output()
+	if err != nil {
+		return Package{}, fmt.Errorf("could not find package %s: %s", importPath, err)
+	}
+	pkgs := ParseList(string(out))
+	if len(pkgs) == 0 {
+		return Package{}, fmt.Errorf("could not find package %s", importPath)
+	}
+	return pkgs[0], nil
+}
+
+// ParseList parses the output of go list with listFormat, sorted by
+// import path.  Packages that go list couldn't find have no directory
+// and are left out.
+func ParseList(out string) []Package {
+	seen := make(map[string]bool)
+	var pkgs []Package
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) != 2 || fields[0] == "" || fields[1] == "" || seen[fields[0]] {
+			continue
+		}
+		seen[fields[0]] = true
+		pkgs = append(pkgs, Package{ImportPath: fields[0], Dir: fields[1]})
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].ImportPath < pkgs[j].ImportPath
+	})
+	return pkgs
+}
+
+// DocFile returns the file in p's directory that best documents p:
+// its doc.go if it has one, then the file named after the package,
+// and then its first non-test go file.
+func DocFile(p Package) (string, error) {
+	infos, err := ioutil.ReadDir(p.Dir)
+	if err != nil {
+		return "", err
+	}
+	var files []string
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		files = append(files, name)
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("there are no go files in %s", p.Dir)
+	}
+	for _, preferred := range []string{"doc.go", path.Base(p.ImportPath) + ".go"} {
+		for _, f := range files {
+			if f == preferred {
+				return filepath.Join(p.Dir, f), nil
+			}
+		}
+	}
+	return filepath.Join(p.Dir, files[0]), nil
+}
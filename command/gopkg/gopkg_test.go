@@ -0,0 +1,97 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gopkg_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nelsam/vidar/command/gopkg"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal        = matchers.Equal
+	not          = matchers.Not
+	haveOccurred = matchers.HaveOccurred
+)
+
+func TestParseList(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it sorts packages and skips duplicates", func(expect expect.Expectation) {
+		out := "strings\t/go/src/strings\n" +
+			"example.com/foo\t/src/foo\n" +
+			"strings\t/go/src/strings\n"
+		expect(gopkg.ParseList(out)).To(equal([]gopkg.Package{
+			{ImportPath: "example.com/foo", Dir: "/src/foo"},
+			{ImportPath: "strings", Dir: "/go/src/strings"},
+		}))
+	})
+
+	o.Spec("it skips packages that could not be found", func(expect expect.Expectation) {
+		expect(gopkg.ParseList("example.com/missing\t\n\n")).To(equal([]gopkg.Package(nil)))
+	})
+}
+
+func TestDocFile(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, string) {
+		dir, err := ioutil.TempDir("", "vidar-gopkg")
+		if err != nil {
+			t.Fatalf("Could not create temp dir: %s", err)
+		}
+		return expect.New(t), dir
+	})
+
+	o.AfterEach(func(expect expect.Expectation, dir string) {
+		os.RemoveAll(dir)
+	})
+
+	write := func(expect expect.Expectation, dir string, names ...string) {
+		for _, name := range names {
+			err := ioutil.WriteFile(filepath.Join(dir, name), []byte("package foo\n"), 0600)
+			expect(err).To(not(haveOccurred()))
+		}
+	}
+
+	o.Spec("it prefers doc.go", func(expect expect.Expectation, dir string) {
+		write(expect, dir, "a.go", "foo.go", "doc.go")
+		file, err := gopkg.DocFile(gopkg.Package{ImportPath: "example.com/foo", Dir: dir})
+		expect(err).To(not(haveOccurred()))
+		expect(file).To(equal(filepath.Join(dir, "doc.go")))
+	})
+
+	o.Spec("it falls back to the file named after the package", func(expect expect.Expectation, dir string) {
+		write(expect, dir, "a.go", "foo.go")
+		file, err := gopkg.DocFile(gopkg.Package{ImportPath: "example.com/foo", Dir: dir})
+		expect(err).To(not(haveOccurred()))
+		expect(file).To(equal(filepath.Join(dir, "foo.go")))
+	})
+
+	o.Spec("it skips tests", func(expect expect.Expectation, dir string) {
+		write(expect, dir, "a_test.go", "b.go")
+		file, err := gopkg.DocFile(gopkg.Package{ImportPath: "example.com/foo", Dir: dir})
+		expect(err).To(not(haveOccurred()))
+		expect(file).To(equal(filepath.Join(dir, "b.go")))
+	})
+
+	o.Spec("it errors for directories without go files", func(expect expect.Expectation, dir string) {
+		write(expect, dir, "foo_test.go", "README.md")
+		_, err := gopkg.DocFile(gopkg.Package{ImportPath: "example.com/foo", Dir: dir})
+		expect(err).To(haveOccurred())
+	})
+}
@@ -0,0 +1,212 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gopkg
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/command/fs"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/scoring"
+	"github.com/nelsam/vidar/setting"
+)
+
+// maxShown is the number of matching packages that are displayed
+// while typing.
+const maxShown = 8
+
+var matchColor = gxui.Color{R: 0.4, G: 0.8, B: 0.3, A: 1}
+
+// Open is a command which opens a go package by its import path.
+// Typing completes the path from the packages that the project can
+// import, and the package's documentation file is opened, which shows
+// its table of contents.  Packages outside of the project (e.g. in
+// GOROOT or the module cache) are opened read-only.
+type Open struct {
+	status.General
+
+	driver gxui.Driver
+	theme  *basic.Theme
+
+	pathInput gxui.TextBox
+	matches   gxui.LinearLayout
+	input     gxui.Focusable
+
+	// proj is the project that packages are listed for, and
+	// packages caches the packages listed for each project path.
+	// They're only accessed on the UI goroutine.
+	proj     setting.Project
+	packages map[string][]Package
+	choice   string
+
+	focuser Focuser
+	execer  Executor
+}
+
+func NewOpen(driver gxui.Driver, theme *basic.Theme) *Open {
+	o := &Open{
+		driver:    driver,
+		theme:     theme,
+		pathInput: theme.CreateTextBox(),
+		matches:   theme.CreateLinearLayout(),
+		packages:  make(map[string][]Package),
+	}
+	o.Theme = theme
+	o.pathInput.SetDesiredWidth(math.MaxSize.W)
+	o.pathInput.OnTextChanged(func([]gxui.TextBoxEdit) {
+		o.update()
+	})
+	o.matches.SetDirection(gxui.LeftToRight)
+	return o
+}
+
+func (o *Open) Name() string {
+	return "open-package"
+}
+
+func (o *Open) Menu() string {
+	return "Golang"
+}
+
+func (o *Open) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (o *Open) Start(on gxui.Control) gxui.Control {
+	o.proj = fs.CurrentProject(on)
+	o.pathInput.SetText("")
+	o.input = o.pathInput
+	o.update()
+	o.refresh(o.proj)
+	return o.matches
+}
+
+// refresh lists the packages for proj in the background, since go
+// list may have to load the whole module graph.  The packages that
+// were listed last time are used until it's done.
+func (o *Open) refresh(proj setting.Project) {
+	go func() {
+		defer status.StartTask("listing packages")()
+		pkgs, err := List(proj)
+		if err != nil {
+			log.Printf("WARNING: open-package: %s", err)
+			return
+		}
+		o.driver.Call(func() {
+			o.packages[proj.Path] = pkgs
+			if o.proj.Path == proj.Path {
+				o.update()
+			}
+		})
+	}()
+}
+
+func (o *Open) Next() gxui.Focusable {
+	input := o.input
+	o.input = nil
+	return input
+}
+
+// update displays the import paths that match what has been typed, in
+// order of how well they match.
+func (o *Open) update() {
+	o.choice = ""
+	o.matches.RemoveAll()
+	partial := o.pathInput.Text()
+	if partial == "" {
+		return
+	}
+	pkgs := o.packages[o.proj.Path]
+	paths := make([]string, 0, len(pkgs))
+	for _, p := range pkgs {
+		if p.ImportPath == partial {
+			// An exact match is always the best.
+			o.choice = partial
+			continue
+		}
+		paths = append(paths, p.ImportPath)
+	}
+	matches := scoring.Sort(paths, partial)
+	if o.choice != "" {
+		matches = append([]string{o.choice}, matches...)
+	}
+	for i, m := range matches {
+		if i == maxShown {
+			break
+		}
+		l := o.theme.CreateLabel()
+		l.SetMargin(math.Spacing{L: 5, R: 5})
+		l.SetText(m)
+		if i == 0 {
+			o.choice = m
+			l.SetColor(matchColor)
+		}
+		o.matches.AddChild(l)
+	}
+}
+
+func (o *Open) Reset() {
+	o.Clear()
+	o.focuser = nil
+	o.execer = nil
+}
+
+func (o *Open) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Focuser:
+		o.focuser = src
+	case Executor:
+		o.execer = src
+	}
+	if o.focuser == nil || o.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Executing
+}
+
+func (o *Open) Exec() error {
+	importPath := o.choice
+	if importPath == "" {
+		// The package may not have been listed yet, or the project
+		// may not depend on it; go list can still find it.
+		importPath = o.pathInput.Text()
+	}
+	if importPath == "" {
+		o.Err = "no import path was entered"
+		return fmt.Errorf("open-package: %s", o.Err)
+	}
+	pkg, ok := o.find(importPath)
+	if !ok {
+		var err error
+		pkg, err = Resolve(o.proj, importPath)
+		if err != nil {
+			o.Err = err.Error()
+			return err
+		}
+	}
+	file, err := DocFile(pkg)
+	if err != nil {
+		o.Err = fmt.Sprintf("could not open %s: %s", importPath, err)
+		return err
+	}
+	o.execer.Execute(o.focuser.For(focus.Path(file)))
+	o.Info = fmt.Sprintf("opened %s", importPath)
+	return nil
+}
+
+func (o *Open) find(importPath string) (Package, bool) {
+	for _, p := range o.packages[o.proj.Path] {
+		if p.ImportPath == importPath {
+			return p, true
+		}
+	}
+	return Package{}, false
+}
@@ -12,15 +12,11 @@ import (
 	"unicode"
 
 	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/command/focus"
 	"github.com/nelsam/vidar/commander/bind"
 	"github.com/nelsam/vidar/plugin/status"
 )
 
-type Scroller interface {
-	LineStart(int) int
-	ScrollToLine(int)
-}
-
 type LineControl interface {
 	LineCount() int
 	SetCaret(int)
@@ -32,8 +28,9 @@ type GotoLine struct {
 	lineNumInput gxui.TextBox
 	input        gxui.Focusable
 
-	editor Scroller
-	ctrl   LineControl
+	ctrl    LineControl
+	focuser Focuser
+	execer  Executor
 }
 
 func NewGotoLine(theme gxui.Theme) *GotoLine {
@@ -85,18 +82,21 @@ func (g *GotoLine) Next() gxui.Focusable {
 }
 
 func (g *GotoLine) Reset() {
-	g.editor = nil
 	g.ctrl = nil
+	g.focuser = nil
+	g.execer = nil
 }
 
 func (g *GotoLine) Store(elem interface{}) bind.Status {
 	switch src := elem.(type) {
 	case LineControl:
 		g.ctrl = src
-	case Scroller:
-		g.editor = src
+	case Focuser:
+		g.focuser = src
+	case Executor:
+		g.execer = src
 	}
-	if g.editor != nil && g.ctrl != nil {
+	if g.ctrl != nil && g.focuser != nil && g.execer != nil {
 		return bind.Done
 	}
 	return bind.Waiting
@@ -127,7 +127,8 @@ func (g *GotoLine) Exec() error {
 		g.Err = "Line 0 does not exist"
 		return errors.New("Invalid line")
 	}
-	g.ctrl.SetCaret(g.editor.LineStart(line))
-	g.editor.ScrollToLine(line)
+	// Moving through focus-location records the jump in the
+	// navigation history.
+	g.execer.Execute(g.focuser.For(focus.Line(line)))
 	return nil
 }
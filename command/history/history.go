@@ -5,6 +5,7 @@
 package history
 
 import (
+	"log"
 	"sync"
 
 	"github.com/nelsam/gxui"
@@ -12,6 +13,7 @@ import (
 	"github.com/nelsam/vidar/commander/bind"
 	"github.com/nelsam/vidar/commander/input"
 	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/setting"
 )
 
 // Bindables returns the slice of bind.Bindable types that is implemented
@@ -34,6 +36,13 @@ type History struct {
 	// because it will only be accessed when the open file is changed.
 	all   map[string]*branch
 	allMu sync.Mutex
+
+	// path and text are the file that h is tracking history for and
+	// its text as of the last edit that h has seen.  They're what
+	// saved history is matched against when a file is reopened.
+	path   string
+	text   []rune
+	textMu sync.Mutex
 }
 
 // Name returns the name of h
@@ -44,11 +53,37 @@ func (h *History) Name() string {
 // OpNames returns the name of bind.Op types that
 // h needs to bind to.
 func (h *History) OpNames() []string {
-	return []string{"input-handler", "focus-location"}
+	return []string{"input-handler", "focus-location", "save-current-file"}
 }
 
-// Init implements input.ChangeHook
-func (h *History) Init(input.Editor, []rune) {}
+// Init implements input.ChangeHook.  The first time it is called for
+// a file, it restores any history that was saved for the file the
+// last time it was closed.
+func (h *History) Init(e input.Editor, text []rune) {
+	h.textMu.Lock()
+	defer h.textMu.Unlock()
+	if e.Filepath() == h.path {
+		return
+	}
+	h.path = e.Filepath()
+	h.text = append([]rune(nil), text...)
+
+	curr := h.current.trunk()
+	if curr.prev() != nil || curr.next(0) != nil {
+		return
+	}
+	if !setting.HistoryConfig().Persist {
+		return
+	}
+	restored, err := load(setting.HistoryDir, h.path, h.text)
+	if err != nil {
+		log.Printf("Error loading history for %s: %s", h.path, err)
+		return
+	}
+	if restored != nil {
+		h.current.setTrunk(restored)
+	}
+}
 
 // resetCurrent resets h.current.trunk to a previous history (if one
 // exists for path) or a new empty branch.
@@ -87,7 +122,8 @@ func (h *History) shouldSkip(e input.Edit) bool {
 
 // TextChanged hooks into the input handler to trigger off of changes
 // in the editor so that h can track the history of those changes.
-func (h *History) TextChanged(_ input.Editor, e input.Edit) {
+func (h *History) TextChanged(editor input.Editor, e input.Edit) {
+	h.updateText(editor, e)
 	if h.shouldSkip(e) {
 		h.skip.setNext(h.skip.next().next())
 		return
@@ -95,6 +131,27 @@ func (h *History) TextChanged(_ input.Editor, e input.Edit) {
 	h.current.setTrunk(h.current.trunk().push(e))
 }
 
+// updateText applies e to h.text, if editor is the file that h is
+// tracking.
+func (h *History) updateText(editor input.Editor, e input.Edit) {
+	h.textMu.Lock()
+	defer h.textMu.Unlock()
+	if editor == nil || h.text == nil || editor.Filepath() != h.path {
+		return
+	}
+	end := e.At + len(e.Old)
+	if e.At < 0 || end > len(h.text) {
+		// We've lost track of the text, so anything we saved
+		// wouldn't match the file.
+		h.text = nil
+		return
+	}
+	text := make([]rune, 0, len(h.text)-len(e.Old)+len(e.New))
+	text = append(text, h.text[:e.At]...)
+	text = append(text, e.New...)
+	h.text = append(text, h.text[end:]...)
+}
+
 // Rewind tells h to rewind its current state and return the
 // input.Edit that needs to be applied in order to rewind the
 // text to its previous state.
@@ -149,7 +206,31 @@ func (h *History) FileChanged(oldPath, newPath string) {
 	defer h.allMu.Unlock()
 	if oldPath != "" {
 		h.all[oldPath] = h.current.trunk()
+		if err := h.persist(oldPath); err != nil {
+			log.Printf("Error saving history for %s: %s", oldPath, err)
+		}
 	}
 	h.resetCurrent(newPath)
 	h.skip.setNext(nil)
 }
+
+// AfterSave saves the current history when the file is saved, so
+// that it isn't lost if the editor exits.
+func (h *History) AfterSave(_ setting.Project, path, _ string) error {
+	return h.persist(path)
+}
+
+// persist saves the current history for path, if path is the file
+// that h is tracking.
+func (h *History) persist(path string) error {
+	cfg := setting.HistoryConfig()
+	if !cfg.Persist {
+		return nil
+	}
+	h.textMu.Lock()
+	defer h.textMu.Unlock()
+	if path != h.path || h.text == nil {
+		return nil
+	}
+	return save(setting.HistoryDir, path, h.current.trunk(), h.text, cfg.MaxEdits)
+}
@@ -0,0 +1,170 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// saved is the format that history is written to disk in.  Edits
+// are stored in depth-first order, so every edit's parent comes
+// before it.
+type saved struct {
+	// Hash is the hash of the file's text at Current.  History is
+	// only restored if the file still matches it.
+	Hash string
+
+	// Current is the index of the current edit in Edits, or -1 if
+	// the current state is the root of the history.
+	Current int
+
+	Edits []savedEdit
+}
+
+type savedEdit struct {
+	// Parent is the index of the parent edit in Edits, or -1 if the
+	// parent is the root of the history.
+	Parent int
+
+	At       int
+	Old, New string
+}
+
+// hash returns the hash that saved history uses to match the text of
+// a file.
+func hash(text []rune) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(string(text))))
+}
+
+// historyFile returns the file in dir that history for path is
+// stored in.
+func historyFile(dir, path string) string {
+	return filepath.Join(dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(path))))
+}
+
+// encode stores the history that current is part of, with current as
+// the current state.  If the history has more than max edits, only
+// the max edits leading up to current are stored.
+func encode(current *branch, text []rune, max int) saved {
+	s := saved{Hash: hash(text), Current: -1}
+	var chain []*branch
+	for b := current; b.prev() != nil; b = b.prev() {
+		chain = append(chain, b)
+	}
+	root := current
+	if len(chain) > 0 {
+		root = chain[len(chain)-1].prev()
+	}
+	if len(chain) > max || count(root) > max {
+		if len(chain) > max {
+			chain = chain[:max]
+		}
+		for i := len(chain) - 1; i >= 0; i-- {
+			s.Edits = append(s.Edits, newSavedEdit(len(s.Edits)-1, chain[i].edit))
+		}
+		s.Current = len(s.Edits) - 1
+		return s
+	}
+	s.add(root, -1, current)
+	return s
+}
+
+// add adds the children of b to s, recursively.  parent is the index
+// of b in s.Edits.
+func (s *saved) add(b *branch, parent int, current *branch) {
+	for _, child := range children(b) {
+		idx := len(s.Edits)
+		s.Edits = append(s.Edits, newSavedEdit(parent, child.edit))
+		if child == current {
+			s.Current = idx
+		}
+		s.add(child, idx, current)
+	}
+}
+
+func newSavedEdit(parent int, e input.Edit) savedEdit {
+	return savedEdit{Parent: parent, At: e.At, Old: string(e.Old), New: string(e.New)}
+}
+
+// decode rebuilds the history in s, returning the current branch.
+func (s saved) decode() (*branch, error) {
+	root := &branch{}
+	branches := make([]*branch, 0, len(s.Edits))
+	for i, e := range s.Edits {
+		parent := root
+		if e.Parent >= 0 {
+			if e.Parent >= i {
+				return nil, fmt.Errorf("edit %d has parent %d, which comes after it", i, e.Parent)
+			}
+			parent = branches[e.Parent]
+		}
+		branches = append(branches, parent.push(input.Edit{At: e.At, Old: []rune(e.Old), New: []rune(e.New)}))
+	}
+	if s.Current < 0 {
+		return root, nil
+	}
+	if s.Current >= len(branches) {
+		return nil, fmt.Errorf("current edit %d is out of range", s.Current)
+	}
+	return branches[s.Current], nil
+}
+
+// children returns all child branches of b.
+func children(b *branch) []*branch {
+	first := b.next(0)
+	if first == nil {
+		return nil
+	}
+	return append([]*branch{first}, first.siblings()...)
+}
+
+// count returns the number of edits below b.
+func count(b *branch) int {
+	n := 0
+	for _, child := range children(b) {
+		n += 1 + count(child)
+	}
+	return n
+}
+
+// save writes the history that current is part of to dir.
+func save(dir, path string, current *branch, text []rune, max int) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(encode(current, text, max))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(historyFile(dir, path), b, 0600)
+}
+
+// load reads the history for path from dir, returning the current
+// branch.  If there is no saved history for path, or text doesn't
+// match the text that the history was saved with, it returns nil.
+func load(dir, path string, text []rune) (*branch, error) {
+	b, err := ioutil.ReadFile(historyFile(dir, path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s saved
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	if s.Hash != hash(text) {
+		return nil, nil
+	}
+	return s.decode()
+}
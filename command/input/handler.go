@@ -45,6 +45,7 @@ type Handler struct {
 	applied    []AppliedChangeHook
 	cancellers []Canceler
 	confirmers []Confirmer
+	indenters  []Indenter
 }
 
 func New(d gxui.Driver, b Binder) *Handler {
@@ -65,6 +66,7 @@ func (e *Handler) Bind(b bind.Bindable) (input.Handler, error) {
 	newH.applied = append(newH.applied, e.applied...)
 	newH.cancellers = append(newH.cancellers, e.cancellers...)
 	newH.confirmers = append(newH.confirmers, e.confirmers...)
+	newH.indenters = append(newH.indenters, e.indenters...)
 
 	didBind := false
 	if c, isCanceler := b.(Canceler); isCanceler {
@@ -77,6 +79,11 @@ func (e *Handler) Bind(b bind.Bindable) (input.Handler, error) {
 		didBind = true
 	}
 
+	if i, ok := b.(Indenter); ok {
+		newH.indenters = append(newH.indenters, i)
+		didBind = true
+	}
+
 	if a, ok := b.(AppliedChangeHook); ok {
 		didBind = true
 		newH.applied = append(newH.applied, a)
@@ -107,6 +114,12 @@ func (e *Handler) Init(newEditor input.Editor, contents []rune) {
 
 func (h *Handler) Apply(e input.Editor, edits ...input.Edit) {
 	editor := e.(*editor.CodeEditor)
+	if editor.ReadOnly() {
+		// Every edit, whether it's typed or comes from a command,
+		// goes through Apply, so this is where read-only editors
+		// are protected.
+		return
+	}
 	c := editor.Controller()
 	text := c.TextRunes()
 	delta := 0
@@ -141,6 +154,8 @@ func (h *Handler) Apply(e input.Editor, edits ...input.Edit) {
 	c.Deselect(false)
 	h.driver.CallSync(func() {
 		c.SetTextRunesNoEvent(text)
+		editor.Edited()
+		editor.Mirror(edits)
 		h.textEdited(e, edits)
 	})
 }
@@ -167,7 +182,7 @@ func (e *Handler) HandleEvent(focused input.Editor, ev gxui.KeyboardEvent) {
 			edits = append(edits, input.Edit{
 				At:  s.Start(),
 				Old: ctrl.TextRunes()[s.Start():s.End()],
-				New: []rune{'\n'},
+				New: append([]rune{'\n'}, []rune(e.indent(focused, s.Start()))...),
 			})
 		}
 		e.Apply(focused, edits...)
@@ -226,6 +241,17 @@ func (e *Handler) HandleInput(focused input.Editor, ev gxui.KeyStrokeEvent) {
 	e.Apply(focused, edits...)
 }
 
+// indent returns the indentation for a newline typed at pos in
+// focused.
+func (e *Handler) indent(focused input.Editor, pos int) string {
+	for _, i := range e.indenters {
+		if indent, ok := i.Indent(focused, pos); ok {
+			return indent
+		}
+	}
+	return ""
+}
+
 func (e *Handler) textEdited(focused input.Editor, edits []input.Edit) {
 	for _, a := range e.applied {
 		a.Applied(focused, edits)
@@ -0,0 +1,16 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package input
+
+import "github.com/nelsam/vidar/commander/input"
+
+// An Indenter is a hook that chooses the indentation for new lines.
+// When a newline is typed, the indentation from the first Indenter
+// that returns true is inserted after it.
+type Indenter interface {
+	// Indent returns the indentation for a line that is started
+	// by typing a newline at pos in e.
+	Indent(e input.Editor, pos int) (indent string, ok bool)
+}
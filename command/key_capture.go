@@ -0,0 +1,93 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/mixins/parts"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/setting"
+)
+
+// maxCaptureLen is the number of keys that a keyCapture holds before
+// it starts over, matching the longest chords in the key bindings
+// file.
+const maxCaptureLen = 2
+
+// keyCapture is an input that records the keys that are pressed
+// while it's focused, rather than typing them.  Enter finishes the
+// capture, so it can't be captured itself without a modifier.
+type keyCapture struct {
+	parts.Focusable
+	mixins.Label
+
+	theme *basic.Theme
+	chord setting.Chord
+}
+
+func newKeyCapture(theme *basic.Theme) *keyCapture {
+	k := &keyCapture{theme: theme}
+	k.Focusable.Init(k)
+	k.Label.Init(k, theme, theme.DefaultMonospaceFont(), theme.TextBoxDefaultStyle.FontColor)
+	k.OnGainedFocus(k.Redraw)
+	k.OnLostFocus(k.Redraw)
+	return k
+}
+
+// Reset clears the captured keys.
+func (k *keyCapture) Reset() {
+	k.chord = nil
+	k.SetText("")
+}
+
+// Chord returns the keys that have been captured.
+func (k *keyCapture) Chord() setting.Chord {
+	return k.chord
+}
+
+// Complete records event, finishing the capture when enter is pressed
+// after at least one key has been captured.
+func (k *keyCapture) Complete(event gxui.KeyboardEvent) bool {
+	if event.Modifier == 0 && event.Key == gxui.KeyEnter {
+		return len(k.chord) > 0
+	}
+	if isModifierKey(event.Key) {
+		return false
+	}
+	if event.Modifier&gxui.ModSuper != 0 {
+		// Bindings are written with ctrl, which is mirrored to
+		// super when they're loaded.
+		event.Modifier &^= gxui.ModSuper
+		event.Modifier |= gxui.ModControl
+	}
+	if len(k.chord) == maxCaptureLen {
+		k.chord = nil
+	}
+	k.chord = append(k.chord, event)
+	k.SetText(k.chord.String())
+	return false
+}
+
+func (k *keyCapture) Paint(c gxui.Canvas) {
+	k.Label.Paint(c)
+
+	if k.HasFocus() {
+		r := k.Size().Rect()
+		s := k.theme.FocusedStyle
+		c.DrawRoundedRect(r, 3, 3, 3, 3, s.Pen, s.Brush)
+	}
+}
+
+func isModifierKey(key gxui.KeyboardKey) bool {
+	switch key {
+	case gxui.KeyLeftShift, gxui.KeyRightShift,
+		gxui.KeyLeftControl, gxui.KeyRightControl,
+		gxui.KeyLeftAlt, gxui.KeyRightAlt,
+		gxui.KeyLeftSuper, gxui.KeyRightSuper:
+		return true
+	}
+	return false
+}
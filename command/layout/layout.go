@@ -0,0 +1,54 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package layout contains commands that save the layout of a window
+// under a name and load it again later.
+package layout
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, _ gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{NewSave(theme), NewLoad(theme)}
+}
+
+// A Layouter is a type whose splits and open files can be saved as a
+// layout and replaced by a saved layout.
+type Layouter interface {
+	Layout() setting.Layout
+	LoadLayout(setting.Layout)
+	OpenEditors() []input.Editor
+	CurrentFile() string
+}
+
+// A Navigator is a type that shows one pane at a time, by name.
+type Navigator interface {
+	ShownPane() string
+	ShowPane(name string) bool
+}
+
+// A Changer is an editor which tracks unsaved changes.
+type Changer interface {
+	HasChanges() bool
+}
+
+// An Executor is a type that can execute bindables.
+type Executor interface {
+	Execute(bind.Bindable)
+}
+
+// A Focuser is a type that can create a bindable which focuses a
+// file.
+type Focuser interface {
+	For(...focus.Opt) bind.Bindable
+}
@@ -0,0 +1,180 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package layout
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/scoring"
+	"github.com/nelsam/vidar/setting"
+)
+
+var nameMatchColor = gxui.Color{R: 0.4, G: 0.8, B: 0.3, A: 1}
+
+// Load is a command which replaces the current project's splits and
+// open files with a layout that was saved by Save, and shows the
+// navigator pane that was shown when it was saved.  Typing filters the
+// saved layouts, and the first match is loaded.  Files with unsaved
+// changes would be closed by loading a layout, so nothing is loaded
+// until they're saved.
+type Load struct {
+	status.General
+
+	theme *basic.Theme
+
+	display gxui.LinearLayout
+	filter  gxui.TextBox
+	input   gxui.Focusable
+
+	names  []string
+	choice string
+
+	layouter Layouter
+	nav      Navigator
+	focuser  Focuser
+	execer   Executor
+}
+
+func NewLoad(theme *basic.Theme) *Load {
+	l := &Load{
+		theme:   theme,
+		display: theme.CreateLinearLayout(),
+		filter:  theme.CreateTextBox(),
+	}
+	l.Theme = theme
+	l.display.SetDirection(gxui.LeftToRight)
+	l.filter.SetDesiredWidth(math.MaxSize.W)
+	l.filter.OnTextChanged(func([]gxui.TextBoxEdit) {
+		l.update()
+	})
+	return l
+}
+
+func (l *Load) Name() string {
+	return "load-layout"
+}
+
+func (l *Load) Menu() string {
+	return "View"
+}
+
+func (l *Load) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (l *Load) Start(gxui.Control) gxui.Control {
+	l.names = setting.LayoutNames()
+	l.filter.SetText("")
+	l.update()
+	l.input = nil
+	if len(l.names) > 0 {
+		l.input = l.filter
+	}
+	return l.display
+}
+
+func (l *Load) Next() gxui.Focusable {
+	input := l.input
+	l.input = nil
+	return input
+}
+
+// update displays the saved layouts that match the current filter, in
+// order of how well they match.
+func (l *Load) update() {
+	names := append([]string(nil), l.names...)
+	if partial := l.filter.Text(); partial != "" {
+		names = scoring.Sort(names, partial)
+	}
+	l.choice = ""
+	l.display.RemoveAll()
+	for i, n := range names {
+		label := l.theme.CreateLabel()
+		label.SetMargin(math.Spacing{L: 5, R: 5})
+		label.SetText(n)
+		if i == 0 {
+			l.choice = n
+			label.SetColor(nameMatchColor)
+		}
+		l.display.AddChild(label)
+	}
+}
+
+func (l *Load) Reset() {
+	l.Clear()
+	l.layouter = nil
+	l.nav = nil
+	l.focuser = nil
+	l.execer = nil
+}
+
+func (l *Load) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Layouter:
+		l.layouter = src
+	case Navigator:
+		l.nav = src
+	case Focuser:
+		l.focuser = src
+	case Executor:
+		l.execer = src
+	}
+	if l.layouter == nil || l.nav == nil || l.focuser == nil || l.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (l *Load) Exec() error {
+	if len(l.names) == 0 {
+		l.Warn = "There are no saved layouts; use save-layout to save one"
+		return nil
+	}
+	if l.choice == "" {
+		l.Err = "no layouts match"
+		return fmt.Errorf("load-layout: %s", l.Err)
+	}
+	saved, ok := setting.LoadLayout(l.choice)
+	if !ok {
+		l.Err = fmt.Sprintf("layout %s was not found", l.choice)
+		return fmt.Errorf("load-layout: %s", l.Err)
+	}
+	if dirty := l.dirtyFiles(); len(dirty) > 0 {
+		l.Warn = fmt.Sprintf("Not loading layout %s; save the changes in %s first", l.choice, strings.Join(dirty, ", "))
+		return nil
+	}
+	l.layouter.LoadLayout(saved.Editors)
+	if !l.nav.ShowPane(saved.NavPane) {
+		l.Warn = fmt.Sprintf("Loaded layout %s, but its navigator pane (%s) no longer exists", l.choice, saved.NavPane)
+	}
+	if file := l.layouter.CurrentFile(); file != "" {
+		l.execer.Execute(l.focuser.For(focus.Path(file)))
+	}
+	if l.Warn == "" {
+		l.Info = fmt.Sprintf("Loaded layout %s", l.choice)
+	}
+	return nil
+}
+
+// dirtyFiles returns the names of the open files that have unsaved
+// changes.
+func (l *Load) dirtyFiles() []string {
+	var dirty []string
+	for _, e := range l.layouter.OpenEditors() {
+		c, ok := e.(Changer)
+		if ok && c.HasChanges() {
+			dirty = append(dirty, filepath.Base(e.Filepath()))
+		}
+	}
+	return dirty
+}
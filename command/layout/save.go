@@ -0,0 +1,94 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package layout
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Save is a command which saves the current project's splits, the
+// files open in them, and the navigator pane that is shown as a named
+// layout.  Saving a layout with the name of one that was already saved
+// replaces it.
+type Save struct {
+	status.General
+
+	name  gxui.TextBox
+	input gxui.Focusable
+
+	layouter Layouter
+	nav      Navigator
+}
+
+func NewSave(theme gxui.Theme) *Save {
+	s := &Save{name: theme.CreateTextBox()}
+	s.Theme = theme
+	s.name.SetDesiredWidth(math.MaxSize.W)
+	return s
+}
+
+func (s *Save) Name() string {
+	return "save-layout"
+}
+
+func (s *Save) Menu() string {
+	return "View"
+}
+
+func (s *Save) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (s *Save) Start(gxui.Control) gxui.Control {
+	s.name.SetText("")
+	s.input = s.name
+	return nil
+}
+
+func (s *Save) Next() gxui.Focusable {
+	input := s.input
+	s.input = nil
+	return input
+}
+
+func (s *Save) Reset() {
+	s.Clear()
+	s.layouter = nil
+	s.nav = nil
+}
+
+func (s *Save) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Layouter:
+		s.layouter = src
+	case Navigator:
+		s.nav = src
+	}
+	if s.layouter == nil || s.nav == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (s *Save) Exec() error {
+	name := strings.TrimSpace(s.name.Text())
+	if name == "" {
+		s.Err = "save-layout: a name is required"
+		return nil
+	}
+	setting.SaveLayout(name, setting.NamedLayout{
+		Editors: s.layouter.Layout(),
+		NavPane: s.nav.ShownPane(),
+	})
+	s.Info = fmt.Sprintf("Saved layout %s", name)
+	return nil
+}
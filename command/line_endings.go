@@ -0,0 +1,91 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/eol"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// A LineEndingSetter is an editor whose file's line endings can be
+// changed.
+type LineEndingSetter interface {
+	Filepath() string
+	LineEnding() eol.Ending
+	SetLineEnding(eol.Ending)
+}
+
+// SetLineEndings is a command which changes the line endings that the
+// current file is saved with.  The new ending is entered as "lf" or
+// "crlf", and every line is converted when the file is saved,
+// including the lines of files with mixed line endings.
+type SetLineEndings struct {
+	status.General
+
+	endingInput gxui.TextBox
+	input       gxui.Focusable
+
+	editor LineEndingSetter
+}
+
+func NewSetLineEndings(theme gxui.Theme) *SetLineEndings {
+	s := &SetLineEndings{endingInput: theme.CreateTextBox()}
+	s.Theme = theme
+	return s
+}
+
+func (s *SetLineEndings) Name() string {
+	return "set-line-endings"
+}
+
+func (s *SetLineEndings) Menu() string {
+	return "Edit"
+}
+
+func (s *SetLineEndings) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (s *SetLineEndings) Start(gxui.Control) gxui.Control {
+	s.endingInput.SetText("")
+	s.input = s.endingInput
+	return nil
+}
+
+func (s *SetLineEndings) Next() gxui.Focusable {
+	input := s.input
+	s.input = nil
+	return input
+}
+
+func (s *SetLineEndings) Reset() {
+	s.Clear()
+	s.editor = nil
+}
+
+func (s *SetLineEndings) Store(elem interface{}) bind.Status {
+	editor, ok := elem.(LineEndingSetter)
+	if !ok {
+		return bind.Waiting
+	}
+	s.editor = editor
+	return bind.Done
+}
+
+func (s *SetLineEndings) Exec() error {
+	ending, err := eol.Parse(s.endingInput.Text())
+	if err != nil {
+		s.Err = err.Error()
+		return err
+	}
+	s.editor.SetLineEnding(ending)
+	s.Info = fmt.Sprintf("%s will be saved with %s line endings", filepath.Base(s.editor.Filepath()), ending)
+	return nil
+}
@@ -0,0 +1,50 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/setting"
+)
+
+// LineNumberer is a type that can show or hide a line number gutter.
+type LineNumberer interface {
+	LineNumbers() bool
+	SetLineNumbers(bool)
+}
+
+// ToggleLineNumbers is a command that toggles the line number gutter
+// in the current editor.  The new state is also saved as the default
+// for editors opened later.
+type ToggleLineNumbers struct{}
+
+func (t ToggleLineNumbers) Name() string {
+	return "toggle-line-numbers"
+}
+
+func (t ToggleLineNumbers) Menu() string {
+	return "View"
+}
+
+func (t ToggleLineNumbers) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModAlt,
+		Key:      gxui.KeyL,
+	}}
+}
+
+func (t ToggleLineNumbers) Exec(e interface{}) bind.Status {
+	l, ok := e.(LineNumberer)
+	if !ok {
+		return bind.Waiting
+	}
+	show := !l.LineNumbers()
+	l.SetLineNumbers(show)
+	setting.SetLineNumbers(show)
+	return bind.Done
+}
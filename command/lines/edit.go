@@ -0,0 +1,266 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lines
+
+import (
+	"sort"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// text is a file's text split into lines.  A newline is added to the
+// end of the file's text, so that every line (including an empty last
+// line) ends with a newline.
+type text struct {
+	runes  []rune
+	starts []int
+}
+
+func split(runes []rune) text {
+	t := text{runes: append(append([]rune(nil), runes...), '\n'), starts: []int{0}}
+	for i, r := range t.runes[:len(t.runes)-1] {
+		if r == '\n' {
+			t.starts = append(t.starts, i+1)
+		}
+	}
+	return t
+}
+
+func (t text) count() int {
+	return len(t.starts)
+}
+
+func (t text) start(line int) int {
+	return t.starts[line]
+}
+
+// end returns the index just past the newline at the end of line.
+func (t text) end(line int) int {
+	if line+1 < len(t.starts) {
+		return t.starts[line+1]
+	}
+	return len(t.runes)
+}
+
+func (t text) line(pos int) int {
+	return sort.Search(len(t.starts), func(i int) bool { return t.starts[i] > pos }) - 1
+}
+
+// A block is a range of lines that is edited as a unit, along with
+// the selections that touch it.
+type block struct {
+	first, last int
+	selections  []int
+}
+
+// blocks returns the blocks of lines touched by selections, ordered
+// by line.  Blocks that overlap are merged, and so are blocks that
+// are next to each other unless join is set.  When join is set, a
+// selection that only touches one line also touches the line below
+// it.
+func (t text) blocks(selections []input.Span, join bool) []block {
+	order := make([]int, len(selections))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return selections[order[i]].Start < selections[order[j]].Start
+	})
+
+	var blocks []block
+	for _, i := range order {
+		s := selections[i]
+		end := s.End
+		if end > s.Start && t.runes[end-1] == '\n' {
+			// A selection ending just after a newline doesn't
+			// touch the next line.
+			end--
+		}
+		b := block{first: t.line(s.Start), last: t.line(end), selections: []int{i}}
+		if join && b.first == b.last && b.last+1 < t.count() {
+			b.last++
+		}
+		gap := 1
+		if join {
+			gap = 0
+		}
+		if n := len(blocks); n > 0 && b.first <= blocks[n-1].last+gap {
+			prev := &blocks[n-1]
+			if b.last > prev.last {
+				prev.last = b.last
+			}
+			prev.selections = append(prev.selections, i)
+			continue
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// edit returns the edit that changes t to result, which must also end
+// with the newline that split added.  It returns false if they are the
+// same.
+func (t text) edit(result []rune) (input.Edit, bool) {
+	old, result := t.runes[:len(t.runes)-1], result[:len(result)-1]
+	prefix := 0
+	for prefix < len(old) && prefix < len(result) && old[prefix] == result[prefix] {
+		prefix++
+	}
+	if prefix == len(old) && prefix == len(result) {
+		return input.Edit{}, false
+	}
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(result)-prefix && old[len(old)-1-suffix] == result[len(result)-1-suffix] {
+		suffix++
+	}
+	return input.Edit{
+		At:  prefix,
+		Old: append([]rune(nil), old[prefix:len(old)-suffix]...),
+		New: append([]rune(nil), result[prefix:len(result)-suffix]...),
+	}, true
+}
+
+// shift moves the selections in b by delta, storing them in moved.
+func (b block) shift(selections, moved []input.Span, delta int) {
+	for _, i := range b.selections {
+		moved[i] = input.Span{Start: selections[i].Start + delta, End: selections[i].End + delta}
+	}
+}
+
+// clamp keeps spans within text of length n.
+func clamp(spans []input.Span, n int) []input.Span {
+	for i, s := range spans {
+		if s.Start > n {
+			spans[i].Start = n
+		}
+		if s.End > n {
+			spans[i].End = n
+		}
+	}
+	return spans
+}
+
+// Duplicate returns the edit that inserts a copy of the lines touched
+// by each selection below them.  The selections are moved to the
+// copies.
+func Duplicate(runes []rune, selections []input.Span) (input.Edit, []input.Span, bool) {
+	t := split(runes)
+	moved := make([]input.Span, len(selections))
+	var result []rune
+	prev := 0
+	for _, b := range t.blocks(selections, false) {
+		start, end := t.start(b.first), t.end(b.last)
+		result = append(result, t.runes[prev:end]...)
+		b.shift(selections, moved, len(result)-start)
+		result = append(result, t.runes[start:end]...)
+		prev = end
+	}
+	result = append(result, t.runes[prev:]...)
+	edit, ok := t.edit(result)
+	return edit, clamp(moved, len(result)-1), ok
+}
+
+// MoveUp returns the edit that swaps the lines touched by each
+// selection with the line above them.  It returns false if the
+// selections touch the first line.
+func MoveUp(runes []rune, selections []input.Span) (input.Edit, []input.Span, bool) {
+	t := split(runes)
+	moved := make([]input.Span, len(selections))
+	var result []rune
+	prev := 0
+	for _, b := range t.blocks(selections, false) {
+		if b.first == 0 {
+			return input.Edit{}, nil, false
+		}
+		above, start, end := t.start(b.first-1), t.start(b.first), t.end(b.last)
+		result = append(result, t.runes[prev:above]...)
+		b.shift(selections, moved, len(result)-start)
+		result = append(result, t.runes[start:end]...)
+		result = append(result, t.runes[above:start]...)
+		prev = end
+	}
+	result = append(result, t.runes[prev:]...)
+	edit, ok := t.edit(result)
+	return edit, clamp(moved, len(result)-1), ok
+}
+
+// MoveDown returns the edit that swaps the lines touched by each
+// selection with the line below them.  It returns false if the
+// selections touch the last line.
+func MoveDown(runes []rune, selections []input.Span) (input.Edit, []input.Span, bool) {
+	t := split(runes)
+	moved := make([]input.Span, len(selections))
+	var result []rune
+	prev := 0
+	for _, b := range t.blocks(selections, false) {
+		if b.last+1 == t.count() {
+			return input.Edit{}, nil, false
+		}
+		start, end, below := t.start(b.first), t.end(b.last), t.end(b.last+1)
+		result = append(result, t.runes[prev:start]...)
+		result = append(result, t.runes[end:below]...)
+		b.shift(selections, moved, len(result)-start)
+		result = append(result, t.runes[start:end]...)
+		prev = below
+	}
+	result = append(result, t.runes[prev:]...)
+	edit, ok := t.edit(result)
+	return edit, clamp(moved, len(result)-1), ok
+}
+
+// Join returns the edit that joins the lines touched by each
+// selection, or each caret's line and the line below it.  Indentation
+// is removed from the joined lines, and they are separated by a
+// single space.  Each selection becomes a caret where the last two
+// lines were joined.
+func Join(runes []rune, selections []input.Span) (input.Edit, []input.Span, bool) {
+	t := split(runes)
+	moved := make([]input.Span, len(selections))
+	var result []rune
+	prev := 0
+	for _, b := range t.blocks(selections, true) {
+		start, end := t.start(b.first), t.end(b.last)
+		result = append(result, t.runes[prev:start]...)
+		joined := append([]rune(nil), t.runes[start:t.end(b.first)-1]...)
+		point := len(joined)
+		for l := b.first + 1; l <= b.last; l++ {
+			joined = trimRight(joined)
+			point = len(joined)
+			next := trimLeft(t.runes[t.start(l) : t.end(l)-1])
+			if len(joined) > 0 && len(next) > 0 {
+				joined = append(joined, ' ')
+			}
+			joined = append(joined, next...)
+		}
+		caret := len(result) + point
+		for _, i := range b.selections {
+			moved[i] = input.Span{Start: caret, End: caret}
+		}
+		result = append(result, joined...)
+		result = append(result, '\n')
+		prev = end
+	}
+	result = append(result, t.runes[prev:]...)
+	edit, ok := t.edit(result)
+	return edit, clamp(moved, len(result)-1), ok
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
+
+func trimLeft(runes []rune) []rune {
+	for len(runes) > 0 && isSpace(runes[0]) {
+		runes = runes[1:]
+	}
+	return runes
+}
+
+func trimRight(runes []rune) []rune {
+	for len(runes) > 0 && isSpace(runes[len(runes)-1]) {
+		runes = runes[:len(runes)-1]
+	}
+	return runes
+}
@@ -0,0 +1,152 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lines_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/command/lines"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal   = matchers.Equal
+	beFalse = matchers.BeFalse
+)
+
+func caret(pos int) input.Span {
+	return input.Span{Start: pos, End: pos}
+}
+
+// apply returns text with edit applied.
+func apply(text string, edit input.Edit) string {
+	runes := []rune(text)
+	result := append([]rune(nil), runes[:edit.At]...)
+	result = append(result, edit.New...)
+	return string(append(result, runes[edit.At+len(edit.Old):]...))
+}
+
+func TestLines(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Group("Duplicate", func() {
+		o.Spec("it copies the caret's line below it", func(expect expect.Expectation) {
+			text := "foo\nbar\nbaz"
+			edit, moved, ok := lines.Duplicate([]rune(text), []input.Span{caret(5)})
+			expect(ok).To(equal(true))
+			expect(apply(text, edit)).To(equal("foo\nbar\nbar\nbaz"))
+			expect(moved).To(equal([]input.Span{caret(9)}))
+		})
+
+		o.Spec("it copies the last line without a trailing newline", func(expect expect.Expectation) {
+			text := "foo\nbar"
+			edit, moved, ok := lines.Duplicate([]rune(text), []input.Span{caret(6)})
+			expect(ok).To(equal(true))
+			expect(apply(text, edit)).To(equal("foo\nbar\nbar"))
+			expect(moved).To(equal([]input.Span{caret(10)}))
+		})
+
+		o.Spec("it copies every line a selection touches", func(expect expect.Expectation) {
+			text := "foo\nbar\nbaz\n"
+			edit, moved, ok := lines.Duplicate([]rune(text), []input.Span{{Start: 1, End: 8}})
+			expect(ok).To(equal(true))
+			expect(apply(text, edit)).To(equal("foo\nbar\nfoo\nbar\nbaz\n"))
+			expect(moved).To(equal([]input.Span{{Start: 9, End: 16}}))
+		})
+
+		o.Spec("it copies the lines of each caret", func(expect expect.Expectation) {
+			text := "foo\nbar\nbaz"
+			edit, moved, ok := lines.Duplicate([]rune(text), []input.Span{caret(9), caret(1)})
+			expect(ok).To(equal(true))
+			expect(apply(text, edit)).To(equal("foo\nfoo\nbar\nbaz\nbaz"))
+			expect(moved).To(equal([]input.Span{caret(17), caret(5)}))
+		})
+	})
+
+	o.Group("MoveUp", func() {
+		o.Spec("it swaps the caret's line with the line above", func(expect expect.Expectation) {
+			text := "foo\nbar\nbaz"
+			edit, moved, ok := lines.MoveUp([]rune(text), []input.Span{caret(10)})
+			expect(ok).To(equal(true))
+			expect(apply(text, edit)).To(equal("foo\nbaz\nbar"))
+			expect(edit.At).To(equal(6))
+			expect(moved).To(equal([]input.Span{caret(6)}))
+		})
+
+		o.Spec("it moves adjacent lines together", func(expect expect.Expectation) {
+			text := "foo\nbar\nbaz\n"
+			edit, moved, ok := lines.MoveUp([]rune(text), []input.Span{caret(5), caret(9)})
+			expect(ok).To(equal(true))
+			expect(apply(text, edit)).To(equal("bar\nbaz\nfoo\n"))
+			expect(moved).To(equal([]input.Span{caret(1), caret(5)}))
+		})
+
+		o.Spec("it does nothing on the first line", func(expect expect.Expectation) {
+			_, _, ok := lines.MoveUp([]rune("foo\nbar"), []input.Span{caret(5), caret(2)})
+			expect(ok).To(beFalse())
+		})
+	})
+
+	o.Group("MoveDown", func() {
+		o.Spec("it swaps the selected lines with the line below", func(expect expect.Expectation) {
+			text := "foo\nbar\nbaz"
+			edit, moved, ok := lines.MoveDown([]rune(text), []input.Span{{Start: 1, End: 5}})
+			expect(ok).To(equal(true))
+			expect(apply(text, edit)).To(equal("baz\nfoo\nbar"))
+			expect(moved).To(equal([]input.Span{{Start: 5, End: 9}}))
+		})
+
+		o.Spec("it doesn't move the line after a selection ending in a newline", func(expect expect.Expectation) {
+			text := "foo\nbar\nbaz"
+			edit, moved, ok := lines.MoveDown([]rune(text), []input.Span{{Start: 0, End: 4}})
+			expect(ok).To(equal(true))
+			expect(apply(text, edit)).To(equal("bar\nfoo\nbaz"))
+			expect(moved).To(equal([]input.Span{{Start: 4, End: 8}}))
+		})
+
+		o.Spec("it does nothing on the last line", func(expect expect.Expectation) {
+			_, _, ok := lines.MoveDown([]rune("foo\nbar"), []input.Span{caret(5)})
+			expect(ok).To(beFalse())
+		})
+	})
+
+	o.Group("Join", func() {
+		o.Spec("it joins the caret's line with the next line", func(expect expect.Expectation) {
+			text := "foo(a,  \n\tb)\nbar"
+			edit, moved, ok := lines.Join([]rune(text), []input.Span{caret(1)})
+			expect(ok).To(equal(true))
+			expect(apply(text, edit)).To(equal("foo(a, b)\nbar"))
+			expect(moved).To(equal([]input.Span{caret(6)}))
+		})
+
+		o.Spec("it joins every line a selection touches", func(expect expect.Expectation) {
+			text := "a\n  b\n  c\nd"
+			edit, moved, ok := lines.Join([]rune(text), []input.Span{{Start: 0, End: 8}})
+			expect(ok).To(equal(true))
+			expect(apply(text, edit)).To(equal("a b c\nd"))
+			expect(moved).To(equal([]input.Span{caret(3)}))
+		})
+
+		o.Spec("it doesn't add a space next to an empty line", func(expect expect.Expectation) {
+			text := "foo\n\nbar"
+			edit, _, ok := lines.Join([]rune(text), []input.Span{caret(0)})
+			expect(ok).To(equal(true))
+			expect(apply(text, edit)).To(equal("foo\nbar"))
+		})
+
+		o.Spec("it does nothing on the last line", func(expect expect.Expectation) {
+			_, _, ok := lines.Join([]rune("foo\nbar"), []input.Span{caret(5)})
+			expect(ok).To(beFalse())
+		})
+	})
+}
@@ -0,0 +1,196 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package lines contains commands that edit whole lines: duplicating
+// them, moving them up or down, and joining them together.
+//
+// Every command works on the lines touched by each selection (or
+// caret), and applies its changes as a single edit so that it can be
+// undone in one step.
+package lines
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, _ gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{
+		NewDuplicateLine(theme),
+		NewMoveLineUp(theme),
+		NewMoveLineDown(theme),
+		NewJoinLines(theme),
+	}
+}
+
+// Editor is the editor that line commands act on.
+type Editor interface {
+	input.Editor
+	Controller() *gxui.TextBoxController
+	SelectSlice([]gxui.TextSelection)
+}
+
+type Applier interface {
+	Apply(input.Editor, ...input.Edit)
+}
+
+// An op computes the edit for a line command, along with where the
+// selections should be after the edit.  It returns false if there is
+// nothing to change.
+type op func(text []rune, selections []input.Span) (input.Edit, []input.Span, bool)
+
+// lineEdit is the implementation shared by the commands in this
+// package.
+type lineEdit struct {
+	status.General
+
+	op   op
+	warn string
+
+	editor  Editor
+	applier Applier
+}
+
+func (l *lineEdit) Menu() string {
+	return "Edit"
+}
+
+func (l *lineEdit) Reset() {
+	l.Clear()
+	l.editor = nil
+	l.applier = nil
+}
+
+func (l *lineEdit) Store(target interface{}) bind.Status {
+	switch src := target.(type) {
+	case Editor:
+		l.editor = src
+	case Applier:
+		l.applier = src
+	}
+	if l.editor != nil && l.applier != nil {
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (l *lineEdit) Exec() error {
+	var selections []input.Span
+	for _, s := range l.editor.Controller().SelectionSlice() {
+		selections = append(selections, input.Span{Start: s.Start(), End: s.End()})
+	}
+	edit, moved, ok := l.op(l.editor.Runes(), selections)
+	if !ok {
+		l.Warn = l.warn
+		return nil
+	}
+	l.applier.Apply(l.editor, edit)
+	var sels []gxui.TextSelection
+	for _, s := range moved {
+		sels = append(sels, gxui.CreateTextSelection(s.Start, s.End, false))
+	}
+	l.editor.SelectSlice(sels)
+	return nil
+}
+
+// DuplicateLine is a command which inserts a copy of the lines
+// touched by each selection below them.
+type DuplicateLine struct {
+	lineEdit
+}
+
+func NewDuplicateLine(theme gxui.Theme) *DuplicateLine {
+	d := &DuplicateLine{lineEdit: lineEdit{op: Duplicate}}
+	d.Theme = theme
+	return d
+}
+
+func (d *DuplicateLine) Name() string {
+	return "duplicate-line"
+}
+
+func (d *DuplicateLine) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModShift,
+		Key:      gxui.KeyD,
+	}}
+}
+
+// MoveLineUp is a command which swaps the lines touched by each
+// selection with the line above them.
+type MoveLineUp struct {
+	lineEdit
+}
+
+func NewMoveLineUp(theme gxui.Theme) *MoveLineUp {
+	m := &MoveLineUp{lineEdit: lineEdit{op: MoveUp, warn: "Already at the first line"}}
+	m.Theme = theme
+	return m
+}
+
+func (m *MoveLineUp) Name() string {
+	return "move-line-up"
+}
+
+func (m *MoveLineUp) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModShift,
+		Key:      gxui.KeyUp,
+	}}
+}
+
+// MoveLineDown is a command which swaps the lines touched by each
+// selection with the line below them.
+type MoveLineDown struct {
+	lineEdit
+}
+
+func NewMoveLineDown(theme gxui.Theme) *MoveLineDown {
+	m := &MoveLineDown{lineEdit: lineEdit{op: MoveDown, warn: "Already at the last line"}}
+	m.Theme = theme
+	return m
+}
+
+func (m *MoveLineDown) Name() string {
+	return "move-line-down"
+}
+
+func (m *MoveLineDown) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModShift,
+		Key:      gxui.KeyDown,
+	}}
+}
+
+// JoinLines is a command which joins the lines touched by each
+// selection into one line.  A selection that only touches one line
+// joins it with the line below it.
+type JoinLines struct {
+	lineEdit
+}
+
+func NewJoinLines(theme gxui.Theme) *JoinLines {
+	j := &JoinLines{lineEdit: lineEdit{op: Join, warn: "No lines to join"}}
+	j.Theme = theme
+	return j
+}
+
+func (j *JoinLines) Name() string {
+	return "join-lines"
+}
+
+func (j *JoinLines) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl,
+		Key:      gxui.KeyJ,
+	}}
+}
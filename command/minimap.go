@@ -0,0 +1,50 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Minimapper is a type that can show or hide a minimap.
+type Minimapper interface {
+	Minimap() bool
+	SetMinimap(bool)
+}
+
+// ToggleMinimap is a command that toggles the minimap in the current
+// editor.  Like ToggleLineNumbers, the new state is saved as the
+// default for editors opened later.
+type ToggleMinimap struct{}
+
+func (t ToggleMinimap) Name() string {
+	return "toggle-minimap"
+}
+
+func (t ToggleMinimap) Menu() string {
+	return "View"
+}
+
+func (t ToggleMinimap) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModAlt,
+		Key:      gxui.KeyM,
+	}}
+}
+
+func (t ToggleMinimap) Exec(e interface{}) bind.Status {
+	m, ok := e.(Minimapper)
+	if !ok {
+		return bind.Waiting
+	}
+	show := !m.Minimap()
+	m.SetMinimap(show)
+	setting.SetMinimap(show)
+	return bind.Done
+}
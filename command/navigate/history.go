@@ -0,0 +1,139 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigate
+
+import "sync"
+
+const (
+	// maxHistory is the number of positions that a History
+	// remembers in each direction.
+	maxHistory = 100
+
+	// nearbyLines is how close two positions in the same file have
+	// to be for History to treat them as the same place.
+	nearbyLines = 10
+)
+
+// Position is a caret position in a file.  Line and Column are
+// zero-indexed.
+type Position struct {
+	Path   string
+	Line   int
+	Column int
+}
+
+// near returns whether or not p and o are close enough together that
+// going from one to the other isn't worth remembering.
+func (p Position) near(o Position) bool {
+	if p.Path != o.Path {
+		return false
+	}
+	diff := p.Line - o.Line
+	return diff >= -nearbyLines && diff <= nearbyLines
+}
+
+// History is a stack of the positions that jumps have left.  Positions
+// that are near each other are merged, so that moving around within
+// a few lines of a position (e.g. while typing) and then jumping away
+// only leaves one entry.
+type History struct {
+	mu      sync.Mutex
+	back    []Position
+	forward []Position
+	paused  bool
+}
+
+// NewHistory returns an empty *History.
+func NewHistory() *History {
+	return &History{}
+}
+
+// Record pushes from, the position that a jump is leaving, onto h.
+// Any positions that could be returned to with Forward are
+// forgotten.  Jumps are not recorded while h is traveling.
+func (h *History) Record(from Position) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.paused {
+		return
+	}
+	h.back = push(h.back, from)
+	h.forward = nil
+}
+
+// Back returns the most recent position that was left, skipping any
+// that are near current, and moves current onto the forward stack.
+// The returned bool is false if there is nowhere to go back to.
+func (h *History) Back(current Position) (Position, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var (
+		p  Position
+		ok bool
+	)
+	h.back, p, ok = pop(h.back, current)
+	if ok {
+		h.forward = push(h.forward, current)
+	}
+	return p, ok
+}
+
+// Forward undoes the last call to Back, moving current back onto the
+// back stack.  The returned bool is false if there is nowhere to go
+// forward to.
+func (h *History) Forward(current Position) (Position, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var (
+		p  Position
+		ok bool
+	)
+	h.forward, p, ok = pop(h.forward, current)
+	if ok {
+		h.back = push(h.back, current)
+	}
+	return p, ok
+}
+
+// travel calls jump without recording it, since jumps made while
+// going back or forward are already on the stacks.
+func (h *History) travel(jump func()) {
+	h.mu.Lock()
+	h.paused = true
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		h.paused = false
+		h.mu.Unlock()
+	}()
+	jump()
+}
+
+// push adds p to the top of stack, replacing the top if it's near p.
+func push(stack []Position, p Position) []Position {
+	if last := len(stack) - 1; last >= 0 && stack[last].near(p) {
+		stack[last] = p
+		return stack
+	}
+	stack = append(stack, p)
+	if len(stack) > maxHistory {
+		stack = stack[len(stack)-maxHistory:]
+	}
+	return stack
+}
+
+// pop removes and returns the top of stack, discarding any positions
+// near current on the way.
+func pop(stack []Position, current Position) ([]Position, Position, bool) {
+	for len(stack) > 0 {
+		last := len(stack) - 1
+		p := stack[last]
+		stack = stack[:last]
+		if !p.near(current) {
+			return stack, p, true
+		}
+	}
+	return stack, Position{}, false
+}
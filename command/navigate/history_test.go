@@ -0,0 +1,95 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigate_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/command/navigate"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal        = matchers.Equal
+	beTrue       = matchers.BeTrue
+	beFalse      = matchers.BeFalse
+	not          = matchers.Not
+	haveOccurred = matchers.HaveOccurred
+)
+
+func TestHistory(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	var (
+		a = navigate.Position{Path: "/tmp/a.go", Line: 1}
+		b = navigate.Position{Path: "/tmp/b.go", Line: 2}
+		c = navigate.Position{Path: "/tmp/c.go", Line: 3}
+	)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *navigate.History) {
+		return expect.New(t), navigate.NewHistory()
+	})
+
+	o.Spec("it has nowhere to go when empty", func(expect expect.Expectation, h *navigate.History) {
+		_, ok := h.Back(a)
+		expect(ok).To(beFalse())
+		_, ok = h.Forward(a)
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it goes back and forward between jumps", func(expect expect.Expectation, h *navigate.History) {
+		h.Record(a)
+		h.Record(b)
+
+		p, ok := h.Back(c)
+		expect(ok).To(beTrue())
+		expect(p).To(equal(b))
+		p, ok = h.Back(b)
+		expect(ok).To(beTrue())
+		expect(p).To(equal(a))
+
+		p, ok = h.Forward(a)
+		expect(ok).To(beTrue())
+		expect(p).To(equal(b))
+		p, ok = h.Forward(b)
+		expect(ok).To(beTrue())
+		expect(p).To(equal(c))
+		_, ok = h.Forward(c)
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it forgets the forward stack on a new jump", func(expect expect.Expectation, h *navigate.History) {
+		h.Record(a)
+		_, ok := h.Back(b)
+		expect(ok).To(beTrue())
+
+		h.Record(a)
+		_, ok = h.Forward(c)
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it merges nearby positions", func(expect expect.Expectation, h *navigate.History) {
+		h.Record(a)
+		h.Record(navigate.Position{Path: a.Path, Line: a.Line + 5})
+
+		p, ok := h.Back(c)
+		expect(ok).To(beTrue())
+		expect(p).To(equal(navigate.Position{Path: a.Path, Line: a.Line + 5}))
+		_, ok = h.Back(p)
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it skips positions near the current one", func(expect expect.Expectation, h *navigate.History) {
+		h.Record(a)
+		h.Record(b)
+
+		p, ok := h.Back(navigate.Position{Path: b.Path, Line: b.Line + 3})
+		expect(ok).To(beTrue())
+		expect(p).To(equal(a))
+	})
+}
@@ -0,0 +1,86 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package navigate contains a navigation history, which records the
+// positions that jumps (goto-definition, goto-line, opening files,
+// clicking symbols in the TOC, etc) leave, and commands to go back
+// and forward through it.
+package navigate
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.  Each call has its own History, so
+// every window keeps its own navigation history.
+func Bindables(_ command.Commander, _ gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	h := NewHistory()
+	return []bind.Bindable{
+		Recorder{History: h},
+		NewBack(theme, h),
+		NewForward(theme, h),
+		NewGoBack(theme, h),
+		NewGoForward(theme, h),
+	}
+}
+
+// An Editor is an editor that knows where its carets are.
+type Editor interface {
+	Filepath() string
+	Carets() []int
+	LineIndex(int) int
+	LineStart(int) int
+}
+
+// An Executor is a type that can execute bindables.
+type Executor interface {
+	Execute(bind.Bindable)
+}
+
+// A Focuser is a type that can create a bindable which focuses a
+// file.
+type Focuser interface {
+	For(...focus.Opt) bind.Bindable
+}
+
+// position returns the position of e's last caret.
+func position(e Editor) Position {
+	p := Position{Path: e.Filepath()}
+	carets := e.Carets()
+	if len(carets) == 0 {
+		return p
+	}
+	caret := carets[len(carets)-1]
+	p.Line = e.LineIndex(caret)
+	p.Column = caret - e.LineStart(p.Line)
+	return p
+}
+
+// Recorder is a hook which records the position that each jump made
+// by focus-location leaves in a History.
+type Recorder struct {
+	History *History
+}
+
+func (r Recorder) Name() string {
+	return "navigation-history"
+}
+
+func (r Recorder) OpName() string {
+	return "focus-location"
+}
+
+func (r Recorder) Jumping(from input.Editor) {
+	e, ok := from.(Editor)
+	if !ok || e.Filepath() == "" {
+		return
+	}
+	r.History.Record(position(e))
+}
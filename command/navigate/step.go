@@ -0,0 +1,158 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigate
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// step is the shared implementation of Back and Forward.  It focuses
+// the previous or next position in a History.
+type step struct {
+	status.General
+
+	history *History
+	forward bool
+
+	editor  Editor
+	focuser Focuser
+	execer  Executor
+}
+
+func (s *step) Reset() {
+	s.Clear()
+	s.editor = nil
+	s.focuser = nil
+	s.execer = nil
+}
+
+func (s *step) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Editor:
+		s.editor = src
+	case Focuser:
+		s.focuser = src
+	case Executor:
+		s.execer = src
+	}
+	if s.editor == nil || s.focuser == nil || s.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (s *step) Exec() error {
+	current := position(s.editor)
+	move, direction := s.history.Back, "back"
+	if s.forward {
+		move, direction = s.history.Forward, "forward"
+	}
+	p, ok := move(current)
+	if !ok {
+		s.Info = fmt.Sprintf("Nowhere to go %s to", direction)
+		return nil
+	}
+	s.history.travel(func() {
+		s.execer.Execute(s.focuser.For(focus.Path(p.Path), focus.Line(p.Line), focus.Column(p.Column)))
+	})
+	return nil
+}
+
+// Back is a command which returns to the position that the last jump
+// left.
+type Back struct {
+	step
+}
+
+func NewBack(theme gxui.Theme, history *History) *Back {
+	b := &Back{step: step{history: history}}
+	b.Theme = theme
+	return b
+}
+
+func (b *Back) Name() string {
+	return "navigate-back"
+}
+
+func (b *Back) Menu() string {
+	return "Navigation"
+}
+
+func (b *Back) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyLeft,
+	}}
+}
+
+// Forward is a command which returns to the position that the last
+// navigate-back left.
+type Forward struct {
+	step
+}
+
+func NewForward(theme gxui.Theme, history *History) *Forward {
+	f := &Forward{step: step{history: history, forward: true}}
+	f.Theme = theme
+	return f
+}
+
+func (f *Forward) Name() string {
+	return "navigate-forward"
+}
+
+func (f *Forward) Menu() string {
+	return "Navigation"
+}
+
+func (f *Forward) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyRight,
+	}}
+}
+
+// GoBack is navigate-back under the go-back name that goto-definition
+// used before every jump was recorded, so that bindings to go-back
+// keep working.  It has no default binding of its own.
+type GoBack struct {
+	*Back
+}
+
+func NewGoBack(theme gxui.Theme, history *History) GoBack {
+	return GoBack{Back: NewBack(theme, history)}
+}
+
+func (GoBack) Name() string {
+	return "go-back"
+}
+
+func (GoBack) Defaults() []fmt.Stringer {
+	return nil
+}
+
+// GoForward is navigate-forward under the go-forward name that
+// goto-definition used before every jump was recorded.  It has no
+// default binding of its own.
+type GoForward struct {
+	*Forward
+}
+
+func NewGoForward(theme gxui.Theme, history *History) GoForward {
+	return GoForward{Forward: NewForward(theme, history)}
+}
+
+func (GoForward) Name() string {
+	return "go-forward"
+}
+
+func (GoForward) Defaults() []fmt.Stringer {
+	return nil
+}
@@ -0,0 +1,79 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigate_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/command/navigate"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+)
+
+type fakeEditor struct {
+	path  string
+	caret int
+}
+
+func (e fakeEditor) Filepath() string       { return e.path }
+func (e fakeEditor) Carets() []int          { return []int{e.caret} }
+func (e fakeEditor) LineIndex(c int) int    { return c }
+func (e fakeEditor) LineStart(line int) int { return line }
+
+type fakeFocuser struct {
+	calls int
+}
+
+func (f *fakeFocuser) For(...focus.Opt) bind.Bindable {
+	f.calls++
+	return nil
+}
+
+type fakeExecutor struct {
+	executed int
+}
+
+func (e *fakeExecutor) Execute(bind.Bindable) {
+	e.executed++
+}
+
+func TestAliases(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *navigate.History) {
+		return expect.New(t), navigate.NewHistory()
+	})
+
+	o.Spec("it keeps the go-back and go-forward names without default bindings", func(expect expect.Expectation, h *navigate.History) {
+		back, forward := navigate.NewGoBack(nil, h), navigate.NewGoForward(nil, h)
+		expect(back.Name()).To(equal("go-back"))
+		expect(forward.Name()).To(equal("go-forward"))
+		expect(len(back.Defaults())).To(equal(0))
+		expect(len(forward.Defaults())).To(equal(0))
+	})
+
+	o.Spec("it steps through the same history as navigate-back and navigate-forward", func(expect expect.Expectation, h *navigate.History) {
+		a := navigate.Position{Path: "/tmp/a.go", Line: 1}
+		h.Record(a)
+
+		back := navigate.NewGoBack(nil, h)
+		focuser, execer := &fakeFocuser{}, &fakeExecutor{}
+		back.Reset()
+		back.Store(fakeEditor{path: "/tmp/b.go", caret: 40})
+		back.Store(focuser)
+		expect(back.Store(execer)).To(equal(bind.Done))
+		expect(back.Exec()).To(not(haveOccurred()))
+		expect(execer.executed).To(equal(1))
+
+		p, ok := h.Forward(a)
+		expect(ok).To(beTrue())
+		expect(p.Path).To(equal("/tmp/b.go"))
+		_, ok = h.Back(p)
+		expect(ok).To(beTrue())
+	})
+}
@@ -0,0 +1,122 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/fs"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// A SplitOpener can open a file in a new split.  If the file is
+// already open, the new split should share its text.
+type SplitOpener interface {
+	OpenSplit(orientation gxui.Orientation, path string)
+}
+
+// OpenSplit is a command which prompts for a file, starting with the
+// current file, and opens it in a new split to the right of or below
+// the current one.
+type OpenSplit struct {
+	status.General
+
+	driver      gxui.Driver
+	orientation gxui.Orientation
+
+	file  *fs.Locator
+	input <-chan gxui.Focusable
+
+	opener SplitOpener
+}
+
+// NewOpenSplitRight returns an OpenSplit which opens files to the
+// right of the current split.
+func NewOpenSplitRight(driver gxui.Driver, theme *basic.Theme) *OpenSplit {
+	return newOpenSplit(driver, theme, gxui.Horizontal)
+}
+
+// NewOpenSplitDown returns an OpenSplit which opens files below the
+// current split.
+func NewOpenSplitDown(driver gxui.Driver, theme *basic.Theme) *OpenSplit {
+	return newOpenSplit(driver, theme, gxui.Vertical)
+}
+
+func newOpenSplit(driver gxui.Driver, theme *basic.Theme, orientation gxui.Orientation) *OpenSplit {
+	return &OpenSplit{
+		General:     status.General{Theme: theme},
+		driver:      driver,
+		orientation: orientation,
+		file:        fs.NewLocator(driver, theme, fs.All),
+	}
+}
+
+func (s *OpenSplit) Name() string {
+	switch s.orientation {
+	case gxui.Horizontal:
+		return "open-in-split-right"
+	case gxui.Vertical:
+		return "open-in-split-down"
+	default:
+		panic(fmt.Errorf("Orientation %d is invalid", s.orientation))
+	}
+}
+
+func (s *OpenSplit) Menu() string {
+	return "View"
+}
+
+func (s *OpenSplit) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (s *OpenSplit) Start(control gxui.Control) gxui.Control {
+	s.file.LoadDir(control)
+	if current := fs.CurrentFile(control); current != "" {
+		// LoadDir clears the file name on the UI goroutine, so the
+		// current file has to be filled in after it.
+		s.driver.Call(func() { s.file.SetPath(current) })
+	}
+	input := make(chan gxui.Focusable, 1)
+	s.input = input
+	input <- s.file
+	close(input)
+	return nil
+}
+
+func (s *OpenSplit) Next() gxui.Focusable {
+	return <-s.input
+}
+
+func (s *OpenSplit) Reset() {
+	s.opener = nil
+}
+
+func (s *OpenSplit) Store(elem interface{}) bind.Status {
+	opener, ok := elem.(SplitOpener)
+	if !ok {
+		return bind.Waiting
+	}
+	s.opener = opener
+	return bind.Done
+}
+
+func (s *OpenSplit) Exec() error {
+	path := s.file.Path()
+	if path == "" {
+		s.Err = "no file path provided"
+		return fmt.Errorf("command.OpenSplit: %s", s.Err)
+	}
+	if finfo, err := os.Stat(path); err == nil && finfo.IsDir() {
+		s.Err = fmt.Sprintf("can't open directory %s as file", path)
+		return fmt.Errorf("command.OpenSplit: %s", s.Err)
+	}
+	s.opener.OpenSplit(s.orientation, path)
+	return nil
+}
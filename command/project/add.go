@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/nelsam/gxui"
@@ -16,6 +17,7 @@ import (
 	"github.com/nelsam/vidar/command/fs"
 	"github.com/nelsam/vidar/commander/bind"
 	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/scaffold"
 	"github.com/nelsam/vidar/setting"
 )
 
@@ -32,10 +34,20 @@ type Add struct {
 
 	path          *fs.Locator
 	pathRequested bool
+	initialPath   string
 	name          gxui.TextBox
 	nextEnv       gxui.TextBox
 	env           map[string]string
 
+	// template is the name of the template to create the project's
+	// files from, which may be empty, and module is the module path
+	// that the template's files are created with.
+	template          gxui.TextBox
+	templateRequested bool
+	module            gxui.TextBox
+	moduleRequested   bool
+	templates         map[string]scaffold.Template
+
 	exec   Executor
 	open   *Open
 	adders []Adder
@@ -53,6 +65,10 @@ func (p *Add) Init(driver gxui.Driver, theme *basic.Theme) {
 	p.path = fs.NewLocator(driver, theme, fs.Dirs)
 	p.name = theme.CreateTextBox()
 	p.name.SetDesiredWidth(math.MaxSize.W)
+	p.template = theme.CreateTextBox()
+	p.template.SetDesiredWidth(math.MaxSize.W)
+	p.module = theme.CreateTextBox()
+	p.module.SetDesiredWidth(math.MaxSize.W)
 	p.nextEnv = theme.CreateTextBox()
 	p.nextEnv.SetDesiredWidth(math.MaxSize.W)
 }
@@ -72,13 +88,37 @@ func (p *Add) Defaults() []fmt.Stringer {
 	}}
 }
 
+// ForPath sets up p to add the directory at path as a project the
+// next time it is run, so that only the project's name and
+// environment are prompted for.
+func (p *Add) ForPath(path string) *Add {
+	p.initialPath = path
+	return p
+}
+
 func (p *Add) Start(control gxui.Control) gxui.Control {
-	p.path.LoadDir(control)
 	p.pathRequested = false
+	if p.initialPath != "" {
+		p.path.SetPath(p.initialPath)
+		p.pathRequested = true
+		p.initialPath = ""
+	} else {
+		p.path.LoadDir(control)
+	}
 	p.name.SetText("")
+	p.template.SetText("")
+	p.templateRequested = false
+	p.module.SetText("")
+	p.moduleRequested = false
 	p.nextEnv.SetText("")
 	p.env = nil
 
+	var err error
+	p.templates, err = scaffold.Load(setting.TemplatesDir())
+	if err != nil {
+		status.Warn(fmt.Sprintf("could not load project templates: %s", err))
+	}
+
 	return p.status
 }
 
@@ -97,6 +137,15 @@ func (p *Add) currEnvs() string {
 	return fmt.Sprintf(" [%s]", strings.Join(envs, ", "))
 }
 
+func (p *Add) templateNames() []string {
+	names := make([]string, 0, len(p.templates))
+	for name := range p.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (p *Add) Next() gxui.Focusable {
 	if !p.pathRequested {
 		p.pathRequested = true
@@ -109,6 +158,23 @@ func (p *Add) Next() gxui.Focusable {
 		return p.name
 	}
 
+	if !p.templateRequested {
+		p.templateRequested = true
+		p.status.SetText(fmt.Sprintf("Template for %s (one of: %s; leave empty to skip):", p.name.Text(), strings.Join(p.templateNames(), ", ")))
+		return p.template
+	}
+
+	if tmpl := p.template.Text(); tmpl != "" && !p.moduleRequested {
+		if _, ok := p.templates[tmpl]; !ok {
+			p.status.SetText(fmt.Sprintf("Template for %s (one of: %s; leave empty to skip): ERR: there is no template named %s", p.name.Text(), strings.Join(p.templateNames(), ", "), tmpl))
+			return p.template
+		}
+		p.moduleRequested = true
+		p.module.SetText(filepath.Base(p.path.Path()))
+		p.status.SetText("Module path:")
+		return p.module
+	}
+
 	msg := "Environment variables (override environment with VAR==someValue, append with VAR=someValue):"
 	defer func() {
 		p.status.SetText(msg)
@@ -178,6 +244,10 @@ func (p *Add) Exec() error {
 			return fmt.Errorf("There is already a project named %s", proj.Name)
 		}
 	}
+	if err := p.scaffold(proj); err != nil {
+		p.Err = err.Error()
+		return err
+	}
 	setting.AddProject(proj)
 	for _, adder := range p.adders {
 		adder.Add(proj)
@@ -186,6 +256,30 @@ func (p *Add) Exec() error {
 	return nil
 }
 
+// scaffold creates proj's files from the template that was chosen, if
+// any.  Go files get proj's license header, which may come from the
+// template itself.
+func (p *Add) scaffold(proj setting.Project) error {
+	name := p.template.Text()
+	if name == "" {
+		return nil
+	}
+	tmpl, ok := p.templates[name]
+	if !ok {
+		return fmt.Errorf("there is no template named %s", name)
+	}
+	module := p.module.Text()
+	if module == "" {
+		module = filepath.Base(proj.Path)
+	}
+	created, err := scaffold.Create(proj.Path, tmpl, scaffold.NewData(proj.Name, module), proj.LicenseHeaderFor)
+	if err != nil {
+		return fmt.Errorf("could not create %s from template %s: %s", proj.Name, name, err)
+	}
+	p.Info = fmt.Sprintf("created %d files from template %s", len(created), name)
+	return nil
+}
+
 func (p *Add) isCorrectPath(path string) bool {
 	finfo, err := os.Stat(path)
 	if err == nil {
@@ -0,0 +1,117 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/fs"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// AddRoot is a command which adds a directory to the current
+// project's roots, so that it is shown, searched, and indexed along
+// with the project's path.
+type AddRoot struct {
+	status.General
+
+	status gxui.Label
+	path   *fs.Locator
+	input  gxui.Focusable
+
+	projecter fs.Projecter
+	exec      Executor
+	open      *Open
+}
+
+func NewAddRoot(driver gxui.Driver, theme *basic.Theme) *AddRoot {
+	a := &AddRoot{}
+	a.Theme = theme
+	a.status = theme.CreateLabel()
+	a.path = fs.NewLocator(driver, theme, fs.Dirs)
+	return a
+}
+
+func (a *AddRoot) Name() string {
+	return "add-project-root"
+}
+
+func (a *AddRoot) Menu() string {
+	return "File"
+}
+
+func (a *AddRoot) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (a *AddRoot) Start(control gxui.Control) gxui.Control {
+	a.path.LoadDir(control)
+	a.status.SetText("Root directory:")
+	a.input = a.path
+	return a.status
+}
+
+func (a *AddRoot) Next() gxui.Focusable {
+	input := a.input
+	a.input = nil
+	return input
+}
+
+func (a *AddRoot) Reset() {
+	a.Clear()
+	a.projecter = nil
+	a.exec = nil
+	a.open = nil
+}
+
+func (a *AddRoot) Store(e interface{}) bind.Status {
+	switch src := e.(type) {
+	case *Open:
+		a.open = src
+	case Executor:
+		a.exec = src
+	case fs.Projecter:
+		a.projecter = src
+	}
+	if a.open != nil && a.exec != nil && a.projecter != nil {
+		return bind.Executing
+	}
+	return bind.Waiting
+}
+
+func (a *AddRoot) Exec() error {
+	root := filepath.Clean(a.path.Path())
+	finfo, err := os.Stat(root)
+	if err != nil {
+		a.Err = fmt.Sprintf("Could not add root %s: %s", root, err)
+		return err
+	}
+	if !finfo.IsDir() {
+		a.Err = fmt.Sprintf("%s is not a directory", root)
+		return fmt.Errorf("%s is not a directory", root)
+	}
+	proj := a.projecter.Project()
+	if proj.Name == setting.DefaultProject.Name {
+		a.Err = "Roots can't be added to the default project"
+		return fmt.Errorf("roots can't be added to %s", proj.Name)
+	}
+	for _, d := range proj.Dirs() {
+		if d == root {
+			a.Warn = fmt.Sprintf("%s is already a root of %s", root, proj.Name)
+			return nil
+		}
+	}
+	proj.Roots = append(proj.Roots, root)
+	setting.UpdateProject(proj)
+	a.exec.Execute(a.open.For(Project(proj)))
+	a.Info = fmt.Sprintf("Added %s to %s", root, proj.Name)
+	return nil
+}
@@ -16,5 +16,6 @@ func Bindables(driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
 		&Open{},
 		NewAdd(driver, theme),
 		NewFind(theme),
+		NewAddRoot(driver, theme),
 	}
 }
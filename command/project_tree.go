@@ -0,0 +1,68 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// A TreeFocuser is a navigator pane which can take keyboard focus.
+type TreeFocuser interface {
+	FocusTree() bool
+}
+
+// FocusProjectTree is a command which shows the project tree and
+// focuses it, so that it can be navigated with the keyboard.
+type FocusProjectTree struct {
+	status.General
+
+	tree TreeFocuser
+}
+
+func NewFocusProjectTree(theme gxui.Theme) *FocusProjectTree {
+	f := &FocusProjectTree{}
+	f.Theme = theme
+	return f
+}
+
+func (f *FocusProjectTree) Name() string {
+	return "focus-project-tree"
+}
+
+func (f *FocusProjectTree) Menu() string {
+	return "View"
+}
+
+func (f *FocusProjectTree) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModShift,
+		Key:      gxui.KeyE,
+	}}
+}
+
+func (f *FocusProjectTree) Reset() {
+	f.Clear()
+	f.tree = nil
+}
+
+func (f *FocusProjectTree) Store(elem interface{}) bind.Status {
+	t, ok := elem.(TreeFocuser)
+	if !ok {
+		return bind.Waiting
+	}
+	f.tree = t
+	return bind.Done
+}
+
+func (f *FocusProjectTree) Exec() error {
+	if !f.tree.FocusTree() {
+		f.Warn = "The project tree is still loading"
+	}
+	return nil
+}
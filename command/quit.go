@@ -12,26 +12,78 @@ import (
 	"github.com/nelsam/vidar/commander/bind"
 )
 
+// A SessionSaver is a type that can save the current session before
+// vidar exits.
+type SessionSaver interface {
+	SaveSession()
+}
+
+// A BeforeQuitter is a hook that runs before vidar exits.
+type BeforeQuitter interface {
+	Name() string
+	BeforeQuit()
+}
+
 type Quit struct {
+	saver SessionSaver
+
+	hooks []BeforeQuitter
 }
 
-func (q Quit) Name() string {
+func (q *Quit) Name() string {
 	return "quit"
 }
 
-func (q Quit) Menu() string {
+func (q *Quit) Menu() string {
 	return "File"
 }
 
-func (q Quit) Defaults() []fmt.Stringer {
+func (q *Quit) Defaults() []fmt.Stringer {
 	return []fmt.Stringer{gxui.KeyboardEvent{
 		Modifier: gxui.ModControl,
 		Key:      gxui.KeyQ,
 	}}
 }
 
-func (q Quit) Exec(interface{}) bind.Status {
+func (q *Quit) Bind(h bind.Bindable) (bind.HookedMultiOp, error) {
+	quitter, ok := h.(BeforeQuitter)
+	if !ok {
+		return nil, fmt.Errorf("expected BeforeQuitter; got %T", h)
+	}
+	newQ := &Quit{}
+	newQ.hooks = append(append(newQ.hooks, q.hooks...), quitter)
+	return newQ, nil
+}
+
+func (q *Quit) Reset() {
+	q.saver = nil
+}
+
+func (q *Quit) Store(elem interface{}) bind.Status {
+	if saver, ok := elem.(SessionSaver); ok {
+		q.saver = saver
+		return bind.Done
+	}
+	// We can quit without a SessionSaver, so we're always ready to
+	// execute.
+	return bind.Executing
+}
+
+func (q *Quit) Exec() error {
 	// TODO: ask for confirmation if there are changes
+	if q.saver != nil {
+		q.saver.SaveSession()
+	}
+	q.Cleanup()
 	os.Exit(0)
-	return bind.Done
+	return nil
+}
+
+// Cleanup runs q's hooks.  It's called by Exec, and should also be
+// called when vidar exits some other way, like closing its last
+// window.
+func (q *Quit) Cleanup() {
+	for _, h := range q.hooks {
+		h.BeforeQuit()
+	}
 }
@@ -0,0 +1,73 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// A ReadOnlyer is an editor whose edits can be blocked.
+type ReadOnlyer interface {
+	Filepath() string
+	ReadOnly() bool
+	SetReadOnly(bool)
+}
+
+// ToggleReadOnly is a command which makes the current editor
+// read-only, or editable again if it's already read-only.
+type ToggleReadOnly struct {
+	status.General
+
+	editor ReadOnlyer
+}
+
+func NewToggleReadOnly(theme gxui.Theme) *ToggleReadOnly {
+	t := &ToggleReadOnly{}
+	t.Theme = theme
+	return t
+}
+
+func (t *ToggleReadOnly) Name() string {
+	return "toggle-read-only"
+}
+
+func (t *ToggleReadOnly) Menu() string {
+	return "Edit"
+}
+
+func (t *ToggleReadOnly) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (t *ToggleReadOnly) Reset() {
+	t.Clear()
+	t.editor = nil
+}
+
+func (t *ToggleReadOnly) Store(elem interface{}) bind.Status {
+	editor, ok := elem.(ReadOnlyer)
+	if !ok {
+		return bind.Waiting
+	}
+	t.editor = editor
+	return bind.Done
+}
+
+func (t *ToggleReadOnly) Exec() error {
+	readOnly := !t.editor.ReadOnly()
+	t.editor.SetReadOnly(readOnly)
+	name := filepath.Base(t.editor.Filepath())
+	if readOnly {
+		t.Info = fmt.Sprintf("%s is now read-only", name)
+		return nil
+	}
+	t.Info = fmt.Sprintf("%s is now editable", name)
+	return nil
+}
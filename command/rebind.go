@@ -0,0 +1,212 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/scoring"
+	"github.com/nelsam/vidar/setting"
+)
+
+// maxRebindShown is the number of matching commands that are
+// displayed while choosing a command to rebind.
+const maxRebindShown = 8
+
+var rebindMatchColor = gxui.Color{R: 0.4, G: 0.8, B: 0.3, A: 1}
+
+// A Remapper is a type that binds commands to keys, and can bind them
+// again after the key bindings have changed.
+type Remapper interface {
+	ConflictLister
+	CommandNames() []string
+	Remap()
+}
+
+// RebindCommand is a command which changes the key binding of another
+// command.  Typing filters the command names, and after the first
+// match is chosen, the next keys that are pressed (up to a two-key
+// chord) become its binding.  The binding is saved to the key
+// bindings file and used right away.
+type RebindCommand struct {
+	status.General
+
+	theme *basic.Theme
+	cmdr  command.Commander
+
+	filter  gxui.TextBox
+	display gxui.LinearLayout
+	prompt  gxui.Label
+	matches gxui.LinearLayout
+	capture *keyCapture
+
+	names  []string
+	choice string
+	step   int
+
+	remapper Remapper
+}
+
+func NewRebindCommand(cmdr command.Commander, theme *basic.Theme) *RebindCommand {
+	r := &RebindCommand{
+		theme:   theme,
+		cmdr:    cmdr,
+		filter:  theme.CreateTextBox(),
+		display: theme.CreateLinearLayout(),
+		prompt:  theme.CreateLabel(),
+		matches: theme.CreateLinearLayout(),
+		capture: newKeyCapture(theme),
+	}
+	r.Theme = theme
+	r.filter.SetDesiredWidth(math.MaxSize.W)
+	r.filter.OnTextChanged(func([]gxui.TextBoxEdit) {
+		r.update()
+	})
+	r.matches.SetDirection(gxui.LeftToRight)
+	r.capture.SetMargin(math.Spacing{L: 5, R: 5})
+	r.display.SetDirection(gxui.LeftToRight)
+	r.display.AddChild(r.prompt)
+	r.display.AddChild(r.matches)
+	return r
+}
+
+func (r *RebindCommand) Name() string {
+	return "rebind-command"
+}
+
+func (r *RebindCommand) Menu() string {
+	return "View"
+}
+
+func (r *RebindCommand) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (r *RebindCommand) Start(gxui.Control) gxui.Control {
+	r.names = nil
+	if remapper, ok := r.cmdr.(Remapper); ok {
+		r.names = remapper.CommandNames()
+	}
+	r.step = 0
+	r.capture.Reset()
+	r.prompt.SetText("")
+	r.filter.SetText("")
+	r.update()
+	return r.display
+}
+
+func (r *RebindCommand) Next() gxui.Focusable {
+	r.step++
+	switch {
+	case r.step == 1:
+		return r.filter
+	case r.step == 2 && r.choice != "":
+		r.matches.RemoveAll()
+		r.prompt.SetText(fmt.Sprintf("Press the new keys for %s%s, then enter:", r.choice, r.current()))
+		return r.capture
+	default:
+		return nil
+	}
+}
+
+// current returns a description of the bindings that r.choice has
+// before it's rebound.
+func (r *RebindCommand) current() string {
+	chords := setting.Bindings(r.choice)
+	if len(chords) == 0 {
+		return ""
+	}
+	bound := make([]string, 0, len(chords))
+	for _, c := range chords {
+		if c[0].Modifier&gxui.ModSuper == 0 {
+			// The super chords only mirror the ctrl chords.
+			bound = append(bound, c.String())
+		}
+	}
+	return fmt.Sprintf(" (currently %s)", strings.Join(bound, ", "))
+}
+
+// update displays the commands that match the current filter, in
+// order of how well they match.
+func (r *RebindCommand) update() {
+	matches := r.names
+	if partial := r.filter.Text(); partial != "" {
+		matches = scoring.Sort(append([]string(nil), r.names...), partial)
+	}
+	r.choice = ""
+	r.matches.RemoveAll()
+	for i, m := range matches {
+		if i == maxRebindShown {
+			break
+		}
+		l := r.theme.CreateLabel()
+		l.SetMargin(math.Spacing{L: 5, R: 5})
+		l.SetText(m)
+		if i == 0 {
+			r.choice = m
+			l.SetColor(rebindMatchColor)
+		}
+		r.matches.AddChild(l)
+	}
+}
+
+func (r *RebindCommand) Reset() {
+	r.Clear()
+	r.remapper = nil
+}
+
+func (r *RebindCommand) Store(elem interface{}) bind.Status {
+	remapper, ok := elem.(Remapper)
+	if !ok {
+		return bind.Waiting
+	}
+	r.remapper = remapper
+	return bind.Done
+}
+
+func (r *RebindCommand) Exec() error {
+	if r.choice == "" {
+		r.Err = "no commands match"
+		return fmt.Errorf("rebind-command: %s", r.Err)
+	}
+	chord := r.capture.Chord()
+	if len(chord) == 0 {
+		r.Err = fmt.Sprintf("no keys were pressed for %s", r.choice)
+		return fmt.Errorf("rebind-command: %s", r.Err)
+	}
+	pattern := strings.ToLower(chord.String())
+	prev := setting.BoundCommand(pattern)
+	if err := setting.SetBindings(r.choice, pattern); err != nil {
+		r.Err = fmt.Sprintf("could not bind %s to %s: %s", pattern, r.choice, err)
+		return err
+	}
+	r.remapper.Remap()
+
+	var warnings []string
+	if prev != "" && prev != r.choice {
+		warnings = append(warnings, fmt.Sprintf("%s is no longer bound to %s", pattern, prev))
+	}
+	for _, c := range r.remapper.BindingConflicts() {
+		for _, name := range c.Commands {
+			if name == r.choice {
+				warnings = append(warnings, c.String())
+				break
+			}
+		}
+	}
+	if len(warnings) > 0 {
+		r.Warn = fmt.Sprintf("%s is now bound to %s, but %s", r.choice, pattern, strings.Join(warnings, "; "))
+		return nil
+	}
+	r.Info = fmt.Sprintf("%s is now bound to %s", r.choice, pattern)
+	return nil
+}
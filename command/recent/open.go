@@ -0,0 +1,157 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package recent
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/scoring"
+	"github.com/nelsam/vidar/setting"
+)
+
+// maxShown is the number of matching files that are displayed while
+// filtering.
+const maxShown = 8
+
+var matchColor = gxui.Color{R: 0.4, G: 0.8, B: 0.3, A: 1}
+
+// Open is a command which opens one of the recently opened files.
+// Files are listed in the order they were last opened in, and typing
+// filters them.  The first match is opened.
+type Open struct {
+	status.General
+
+	driver  gxui.Driver
+	theme   *basic.Theme
+	tracker *Tracker
+
+	filter  gxui.TextBox
+	matches gxui.LinearLayout
+	input   <-chan gxui.Focusable
+
+	files  []string
+	choice string
+
+	focuser Focuser
+	execer  Executor
+}
+
+func NewOpen(driver gxui.Driver, theme *basic.Theme, t *Tracker) *Open {
+	o := &Open{
+		driver:  driver,
+		theme:   theme,
+		tracker: t,
+		filter:  theme.CreateTextBox(),
+		matches: theme.CreateLinearLayout(),
+	}
+	o.Theme = theme
+	o.filter.SetDesiredWidth(math.MaxSize.W)
+	o.filter.OnTextChanged(func([]gxui.TextBoxEdit) {
+		o.update()
+	})
+	o.matches.SetDirection(gxui.LeftToRight)
+	return o
+}
+
+func (o *Open) Name() string {
+	return "open-recent"
+}
+
+func (o *Open) Menu() string {
+	return "File"
+}
+
+func (o *Open) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl,
+		Key:      gxui.KeyE,
+	}}
+}
+
+func (o *Open) Start(gxui.Control) gxui.Control {
+	current := o.tracker.Current()
+	o.files = nil
+	for _, f := range setting.RecentFiles() {
+		// The focused file is always the most recent, but
+		// switching to it would do nothing.
+		if f != current {
+			o.files = append(o.files, f)
+		}
+	}
+	o.filter.SetText("")
+	o.update()
+
+	input := make(chan gxui.Focusable, 1)
+	input <- o.filter
+	close(input)
+	o.input = input
+	return o.matches
+}
+
+func (o *Open) Next() gxui.Focusable {
+	return <-o.input
+}
+
+// update displays the files that match the current filter, in order
+// of how well they match.
+func (o *Open) update() {
+	matches := o.files
+	if partial := o.filter.Text(); partial != "" {
+		matches = scoring.Sort(append([]string(nil), o.files...), partial)
+	}
+	o.choice = ""
+	o.matches.RemoveAll()
+	for i, m := range matches {
+		if i == maxShown {
+			break
+		}
+		l := o.theme.CreateLabel()
+		l.SetMargin(math.Spacing{L: 5, R: 5})
+		if i == 0 {
+			// The full path of the file that will be opened is
+			// shown, so that it's clear which one it is.
+			o.choice = m
+			l.SetText(m)
+			l.SetColor(matchColor)
+		} else {
+			l.SetText(filepath.Base(m))
+		}
+		o.matches.AddChild(l)
+	}
+}
+
+func (o *Open) Reset() {
+	o.focuser = nil
+	o.execer = nil
+}
+
+func (o *Open) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Focuser:
+		o.focuser = src
+	case Executor:
+		o.execer = src
+	}
+	if o.focuser == nil || o.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Executing
+}
+
+func (o *Open) Exec() error {
+	if o.choice == "" {
+		o.Err = "no recent files match"
+		return fmt.Errorf("recent: %s", o.Err)
+	}
+	o.execer.Execute(o.focuser.For(focus.Path(o.choice)))
+	return nil
+}
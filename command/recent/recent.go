@@ -0,0 +1,111 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package recent contains commands for getting back to files that
+// were recently open: a quick switcher for recently opened files and
+// a command to reopen closed tabs.
+package recent
+
+import (
+	"sync"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/setting"
+)
+
+// maxClosed is the number of closed tabs that can be reopened.
+const maxClosed = 20
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	t := &Tracker{}
+	return []bind.Bindable{t, NewOpen(driver, theme, t), NewReopen(theme, t)}
+}
+
+// An Executor is a type that can execute bindables.
+type Executor interface {
+	Execute(bind.Bindable)
+}
+
+// A Focuser is a type that can create a bindable which focuses a
+// file.
+type Focuser interface {
+	For(...focus.Opt) bind.Bindable
+}
+
+// Careter is an editor that knows where its carets are.
+type Careter interface {
+	Carets() []int
+}
+
+// closedTab is a tab that was closed and can be reopened.
+type closedTab struct {
+	path   string
+	carets []int
+}
+
+// Tracker is a hook which keeps track of recently opened files and
+// closed tabs.
+type Tracker struct {
+	mu      sync.Mutex
+	current string
+	closed  []closedTab
+}
+
+func (t *Tracker) Name() string {
+	return "recent-files"
+}
+
+func (t *Tracker) OpNames() []string {
+	return []string{"focus-location", "close-current-tab"}
+}
+
+// FileChanged records newPath as the most recently opened file.
+func (t *Tracker) FileChanged(_, newPath string) {
+	t.mu.Lock()
+	t.current = newPath
+	t.mu.Unlock()
+	if newPath != "" {
+		setting.AddRecentFile(newPath)
+	}
+}
+
+// BeforeClose records e so that it can be reopened.
+func (t *Tracker) BeforeClose(e input.Editor) {
+	tab := closedTab{path: e.Filepath()}
+	if c, ok := e.(Careter); ok {
+		tab.carets = c.Carets()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = append(t.closed, tab)
+	if len(t.closed) > maxClosed {
+		t.closed = t.closed[len(t.closed)-maxClosed:]
+	}
+}
+
+// Current returns the path of the focused file.
+func (t *Tracker) Current() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.current
+}
+
+// popClosed removes and returns the most recently closed tab.
+func (t *Tracker) popClosed() (closedTab, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.closed) == 0 {
+		return closedTab{}, false
+	}
+	last := t.closed[len(t.closed)-1]
+	t.closed = t.closed[:len(t.closed)-1]
+	return last, true
+}
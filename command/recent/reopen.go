@@ -0,0 +1,78 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package recent
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// Reopen is a command which reopens the most recently closed tab,
+// putting its caret back where it was.
+type Reopen struct {
+	status.General
+
+	tracker *Tracker
+
+	focuser Focuser
+	execer  Executor
+}
+
+func NewReopen(theme gxui.Theme, t *Tracker) *Reopen {
+	r := &Reopen{tracker: t}
+	r.Theme = theme
+	return r
+}
+
+func (r *Reopen) Name() string {
+	return "reopen-closed-tab"
+}
+
+func (r *Reopen) Menu() string {
+	return "File"
+}
+
+func (r *Reopen) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyT,
+	}}
+}
+
+func (r *Reopen) Reset() {
+	r.focuser = nil
+	r.execer = nil
+}
+
+func (r *Reopen) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Focuser:
+		r.focuser = src
+	case Executor:
+		r.execer = src
+	}
+	if r.focuser == nil || r.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Executing
+}
+
+func (r *Reopen) Exec() error {
+	tab, ok := r.tracker.popClosed()
+	if !ok {
+		r.Warn = "No closed tabs to reopen"
+		return nil
+	}
+	opts := []focus.Opt{focus.Path(tab.path)}
+	if len(tab.carets) > 0 {
+		opts = append(opts, focus.Offset(tab.carets[len(tab.carets)-1]))
+	}
+	r.execer.Execute(r.focuser.For(opts...))
+	return nil
+}
@@ -0,0 +1,247 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package recovery contains a hook that keeps snapshots of files with
+// unsaved changes, so that they can be recovered if the editor
+// crashes, and a command that restores them.
+//
+// Snapshots are written shortly after each edit and whenever a panic
+// is caught by FlushOnPanic.  They're removed when a file is saved or
+// closed.
+package recovery
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/setting"
+)
+
+// snapshotDelay is the idle time after an edit before snapshots are
+// written.
+const snapshotDelay = time.Second
+
+var (
+	journalsMu sync.Mutex
+	journals   []*Journal
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{New(driver, setting.RecoveryDir), NewRestore(theme, setting.RecoveryDir)}
+}
+
+// An Editor is an input.Editor that knows whether or not it has
+// unsaved changes.
+type Editor interface {
+	input.Editor
+	HasChanges() bool
+}
+
+// A Caller is a type that can call functions on the UI goroutine.
+type Caller interface {
+	Call(func()) bool
+}
+
+// Snapshot is the unsaved text of a file.
+type Snapshot struct {
+	Path string
+	Text string
+	Time time.Time
+}
+
+// Journal is a hook which writes snapshots of editors with unsaved
+// changes to a recovery directory.
+type Journal struct {
+	caller Caller
+	dir    string
+
+	mu    sync.Mutex
+	timer *time.Timer
+	dirty map[string]Editor
+}
+
+// New returns a *Journal that writes snapshots to dir, using caller
+// to read editors on the UI goroutine.
+func New(caller Caller, dir string) *Journal {
+	j := &Journal{
+		caller: caller,
+		dir:    dir,
+		dirty:  make(map[string]Editor),
+	}
+	journalsMu.Lock()
+	journals = append(journals, j)
+	journalsMu.Unlock()
+	return j
+}
+
+func (j *Journal) Name() string {
+	return "recovery-journal"
+}
+
+// OpNames returns the names of the bind.Op types that j needs to
+// bind to.
+func (j *Journal) OpNames() []string {
+	return []string{"input-handler", "save-current-file", "close-current-tab"}
+}
+
+// Applied records e as having unsaved changes and restarts the timer
+// for writing snapshots.
+func (j *Journal) Applied(e input.Editor, _ []input.Edit) {
+	editor, ok := e.(Editor)
+	if !ok {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.dirty[editor.Filepath()] = editor
+	if j.timer != nil {
+		j.timer.Stop()
+	}
+	j.timer = time.AfterFunc(snapshotDelay, func() {
+		j.caller.Call(j.Flush)
+	})
+}
+
+// AfterSave removes the snapshot for path, since it no longer has
+// unsaved changes.
+func (j *Journal) AfterSave(_ setting.Project, path, _ string) error {
+	return j.forget(path)
+}
+
+// BeforeClose removes the snapshot for e, since any unsaved changes
+// are being discarded.
+func (j *Journal) BeforeClose(e input.Editor) {
+	if err := j.forget(e.Filepath()); err != nil {
+		log.Printf("WARNING: recovery: could not remove snapshot for %s: %s", e.Filepath(), err)
+	}
+}
+
+func (j *Journal) forget(path string) error {
+	j.mu.Lock()
+	delete(j.dirty, path)
+	j.mu.Unlock()
+	return Remove(j.dir, path)
+}
+
+// Flush writes snapshots for every editor that has been edited since
+// the last flush.  Editors that no longer have unsaved changes have
+// their snapshots removed.  Flush should be called on the UI
+// goroutine.
+func (j *Journal) Flush() {
+	j.mu.Lock()
+	dirty := j.dirty
+	j.dirty = make(map[string]Editor)
+	j.mu.Unlock()
+	for path, e := range dirty {
+		if !e.HasChanges() {
+			if err := Remove(j.dir, path); err != nil {
+				log.Printf("WARNING: recovery: could not remove snapshot for %s: %s", path, err)
+			}
+			continue
+		}
+		if err := write(j.dir, Snapshot{Path: path, Text: e.Text(), Time: time.Now()}); err != nil {
+			log.Printf("WARNING: recovery: could not write snapshot for %s: %s", path, err)
+		}
+	}
+}
+
+// FlushOnPanic flushes every Journal if the goroutine that it is
+// deferred in panics, then continues panicking.
+func FlushOnPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	log.Printf("ERR: panic: %v\n%s", r, debug.Stack())
+	journalsMu.Lock()
+	all := append([]*Journal(nil), journals...)
+	journalsMu.Unlock()
+	for _, j := range all {
+		flushRecovering(j)
+	}
+	panic(r)
+}
+
+// flushRecovering flushes j, logging (rather than propagating) any
+// panic, since it's only called while the editor is already crashing.
+func flushRecovering(j *Journal) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("ERR: recovery: panic while writing snapshots: %v", r)
+		}
+	}()
+	j.Flush()
+}
+
+// snapshotFile returns the file in dir that the snapshot for path is
+// stored in.
+func snapshotFile(dir, path string) string {
+	return filepath.Join(dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(path))))
+}
+
+func write(dir string, s Snapshot) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(snapshotFile(dir, s.Path), b, 0600)
+}
+
+// Remove removes the snapshot for path from dir, if there is one.
+func Remove(dir, path string) error {
+	err := os.Remove(snapshotFile(dir, path))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Snapshots returns all of the snapshots in dir, sorted by path.
+// Snapshots that can't be read are skipped.
+func Snapshots(dir string) []Snapshot {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var snapshots []Snapshot
+	for _, i := range infos {
+		if i.IsDir() || filepath.Ext(i.Name()) != ".json" {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, i.Name()))
+		if err != nil {
+			log.Printf("WARNING: recovery: could not read snapshot %s: %s", i.Name(), err)
+			continue
+		}
+		var s Snapshot
+		if err := json.Unmarshal(b, &s); err != nil {
+			log.Printf("WARNING: recovery: could not parse snapshot %s: %s", i.Name(), err)
+			continue
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Path < snapshots[j].Path
+	})
+	return snapshots
+}
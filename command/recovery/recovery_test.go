@@ -0,0 +1,119 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package recovery_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/nelsam/vidar/command/recovery"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/setting"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+type Expectation = expect.Expectation
+
+var (
+	Not          = matchers.Not
+	HaveOccurred = matchers.HaveOccurred
+	Equal        = matchers.Equal
+	HaveLen      = matchers.HaveLen
+)
+
+type testEditor struct {
+	path    string
+	text    string
+	changed bool
+}
+
+func (e *testEditor) Filepath() string                          { return e.path }
+func (e *testEditor) Text() string                              { return e.text }
+func (e *testEditor) Runes() []rune                             { return []rune(e.text) }
+func (e *testEditor) SetText(t string)                          { e.text = t }
+func (e *testEditor) SyntaxLayers() []input.SyntaxLayer         { return nil }
+func (e *testEditor) SetSyntaxLayers([]input.SyntaxLayer)       {}
+func (e *testEditor) Diagnostics() []input.Diagnostic           { return nil }
+func (e *testEditor) SetDiagnostics(string, []input.Diagnostic) {}
+func (e *testEditor) HasChanges() bool                          { return e.changed }
+
+type testCaller struct{}
+
+func (testCaller) Call(f func()) bool {
+	f()
+	return true
+}
+
+func TestJournal(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (Expectation, string, *recovery.Journal) {
+		dir, err := ioutil.TempDir("", "vidar-recovery")
+		if err != nil {
+			t.Fatalf("Could not create temp dir: %s", err)
+		}
+		return expect.New(t), dir, recovery.New(testCaller{}, dir)
+	})
+
+	o.AfterEach(func(expect Expectation, dir string, j *recovery.Journal) {
+		os.RemoveAll(dir)
+	})
+
+	o.Spec("it writes snapshots of edited files with unsaved changes", func(expect Expectation, dir string, j *recovery.Journal) {
+		e := &testEditor{path: "/foo/bar.go", text: "package bar", changed: true}
+		j.Applied(e, nil)
+		j.Flush()
+
+		snapshots := recovery.Snapshots(dir)
+		expect(snapshots).To(HaveLen(1))
+		expect(snapshots[0].Path).To(Equal("/foo/bar.go"))
+		expect(snapshots[0].Text).To(Equal("package bar"))
+	})
+
+	o.Spec("it removes snapshots for files without unsaved changes", func(expect Expectation, dir string, j *recovery.Journal) {
+		e := &testEditor{path: "/foo/bar.go", text: "package bar", changed: true}
+		j.Applied(e, nil)
+		j.Flush()
+
+		e.changed = false
+		j.Applied(e, nil)
+		j.Flush()
+		expect(recovery.Snapshots(dir)).To(HaveLen(0))
+	})
+
+	o.Spec("it removes snapshots when files are saved", func(expect Expectation, dir string, j *recovery.Journal) {
+		e := &testEditor{path: "/foo/bar.go", text: "package bar", changed: true}
+		j.Applied(e, nil)
+		j.Flush()
+
+		err := j.AfterSave(setting.Project{}, "/foo/bar.go", "package bar")
+		expect(err).To(Not(HaveOccurred()))
+		expect(recovery.Snapshots(dir)).To(HaveLen(0))
+	})
+
+	o.Spec("it removes snapshots when files are closed", func(expect Expectation, dir string, j *recovery.Journal) {
+		e := &testEditor{path: "/foo/bar.go", text: "package bar", changed: true}
+		j.Applied(e, nil)
+		j.Flush()
+
+		j.BeforeClose(e)
+		expect(recovery.Snapshots(dir)).To(HaveLen(0))
+	})
+
+	o.Spec("it sorts snapshots by path", func(expect Expectation, dir string, j *recovery.Journal) {
+		j.Applied(&testEditor{path: "/foo/z.go", text: "package z", changed: true}, nil)
+		j.Applied(&testEditor{path: "/foo/a.go", text: "package a", changed: true}, nil)
+		j.Flush()
+
+		snapshots := recovery.Snapshots(dir)
+		expect(snapshots).To(HaveLen(2))
+		expect(snapshots[0].Path).To(Equal("/foo/a.go"))
+		expect(snapshots[1].Path).To(Equal("/foo/z.go"))
+	})
+}
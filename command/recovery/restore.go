@@ -0,0 +1,177 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package recovery
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// RestoreName is the name of the command that restores recovered
+// files.
+const RestoreName = "restore-recovered-files"
+
+// An Executor is a type that can execute bindables.
+type Executor interface {
+	Execute(bind.Bindable)
+}
+
+// A Focuser is a type that can create a bindable which focuses a
+// file.
+type Focuser interface {
+	For(...focus.Opt) bind.Bindable
+}
+
+// An EditorFinder is a type that knows which editor is focused.
+type EditorFinder interface {
+	CurrentEditor() input.Editor
+}
+
+// Applier is a type that can apply edits to an editor.
+type Applier interface {
+	Apply(input.Editor, ...input.Edit)
+}
+
+// Restore is a command which offers to restore the snapshots that
+// were left behind when the editor last exited.  Restored files are
+// opened with the recovered text as an unsaved change; declining
+// discards the snapshots.
+type Restore struct {
+	status.General
+
+	dir string
+
+	prompt gxui.Label
+	answer gxui.TextBox
+	input  <-chan gxui.Focusable
+
+	snapshots []Snapshot
+
+	finder  EditorFinder
+	applier Applier
+	focuser Focuser
+	execer  Executor
+}
+
+func NewRestore(theme gxui.Theme, dir string) *Restore {
+	r := &Restore{
+		dir:    dir,
+		prompt: theme.CreateLabel(),
+		answer: theme.CreateTextBox(),
+	}
+	r.Theme = theme
+	r.answer.SetDesiredWidth(math.MaxSize.W)
+	return r
+}
+
+func (r *Restore) Name() string {
+	return RestoreName
+}
+
+func (r *Restore) Menu() string {
+	return "File"
+}
+
+func (r *Restore) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (r *Restore) Start(gxui.Control) gxui.Control {
+	r.snapshots = Snapshots(r.dir)
+	input := make(chan gxui.Focusable, 1)
+	if len(r.snapshots) > 0 {
+		names := make([]string, 0, len(r.snapshots))
+		for _, s := range r.snapshots {
+			names = append(names, filepath.Base(s.Path))
+		}
+		r.prompt.SetText(fmt.Sprintf("Recovered unsaved changes to %d files (%s).  Restore them? (y/n)",
+			len(r.snapshots), strings.Join(names, ", ")))
+		r.answer.SetText("y")
+		input <- r.answer
+	}
+	close(input)
+	r.input = input
+	return r.prompt
+}
+
+func (r *Restore) Next() gxui.Focusable {
+	return <-r.input
+}
+
+func (r *Restore) Reset() {
+	r.finder = nil
+	r.applier = nil
+	r.focuser = nil
+	r.execer = nil
+}
+
+func (r *Restore) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case EditorFinder:
+		if r.finder == nil {
+			r.finder = src
+		}
+	case Applier:
+		r.applier = src
+	case Focuser:
+		r.focuser = src
+	case Executor:
+		r.execer = src
+	}
+	if r.finder == nil || r.applier == nil || r.focuser == nil || r.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (r *Restore) Exec() error {
+	if len(r.snapshots) == 0 {
+		r.Info = "No unsaved files to recover"
+		return nil
+	}
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(r.answer.Text())), "n") {
+		for _, s := range r.snapshots {
+			if err := Remove(r.dir, s.Path); err != nil {
+				r.Warn += fmt.Sprintf("could not discard %s: %s  ", filepath.Base(s.Path), err)
+			}
+		}
+		r.Info = fmt.Sprintf("Discarded %d recovered files", len(r.snapshots))
+		return nil
+	}
+
+	var failed []string
+	for _, s := range r.snapshots {
+		r.execer.Execute(r.focuser.For(focus.Path(s.Path)))
+		e := r.finder.CurrentEditor()
+		if e == nil || e.Filepath() != s.Path {
+			failed = append(failed, filepath.Base(s.Path))
+			continue
+		}
+		old := e.Runes()
+		if string(old) == s.Text {
+			// Nothing was lost, so the snapshot is no longer
+			// needed.
+			Remove(r.dir, s.Path)
+			continue
+		}
+		// The journal will write a new snapshot for this edit, so
+		// the old one is left in place until then.
+		r.applier.Apply(e, input.Edit{At: 0, Old: old, New: []rune(s.Text)})
+	}
+	if len(failed) > 0 {
+		r.Err = fmt.Sprintf("Could not open %s; their snapshots were kept", strings.Join(failed, ", "))
+		return fmt.Errorf("recovery: could not open %d files", len(failed))
+	}
+	r.Info = fmt.Sprintf("Restored %d files; save them to keep the changes", len(r.snapshots))
+	return nil
+}
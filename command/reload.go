@@ -0,0 +1,104 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// A Reloader is an editor whose file may have changed on disk while
+// it had unsaved changes.
+type Reloader interface {
+	input.Editor
+	HasConflict() bool
+	Reload()
+	KeepChanges()
+}
+
+// ReloadFile is a command which resolves a conflict between an
+// editor's unsaved changes and changes made to its file on disk.
+// Answering yes reloads the file, discarding the unsaved changes;
+// anything else keeps them, so that saving overwrites the file.
+type ReloadFile struct {
+	status.General
+
+	prompt gxui.Label
+	answer gxui.TextBox
+	input  gxui.Focusable
+
+	editor Reloader
+}
+
+func NewReloadFile(theme gxui.Theme) *ReloadFile {
+	r := &ReloadFile{
+		prompt: theme.CreateLabel(),
+		answer: theme.CreateTextBox(),
+	}
+	r.Theme = theme
+	r.answer.SetDesiredWidth(math.MaxSize.W)
+	return r
+}
+
+func (r *ReloadFile) Name() string {
+	return "reload-current-file"
+}
+
+func (r *ReloadFile) Menu() string {
+	return "File"
+}
+
+func (r *ReloadFile) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (r *ReloadFile) Start(gxui.Control) gxui.Control {
+	r.prompt.SetText("The current file has changed on disk.  Reload it and discard your unsaved changes? (y/n)")
+	r.answer.SetText("n")
+	r.input = r.answer
+	return r.prompt
+}
+
+func (r *ReloadFile) Next() gxui.Focusable {
+	input := r.input
+	r.input = nil
+	return input
+}
+
+func (r *ReloadFile) Reset() {
+	r.editor = nil
+}
+
+func (r *ReloadFile) Store(elem interface{}) bind.Status {
+	editor, ok := elem.(Reloader)
+	if !ok {
+		return bind.Waiting
+	}
+	r.editor = editor
+	return bind.Done
+}
+
+func (r *ReloadFile) Exec() error {
+	name := filepath.Base(r.editor.Filepath())
+	if !r.editor.HasConflict() {
+		r.Info = fmt.Sprintf("%s has not changed on disk", name)
+		return nil
+	}
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(r.answer.Text())), "y") {
+		r.editor.Reload()
+		r.Info = fmt.Sprintf("Reloaded %s", name)
+		return nil
+	}
+	r.editor.KeepChanges()
+	r.Info = fmt.Sprintf("Kept unsaved changes to %s; saving will overwrite the file on disk", name)
+	return nil
+}
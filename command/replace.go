@@ -6,15 +6,19 @@ package command
 
 import (
 	"fmt"
-	"strings"
-	"unicode/utf8"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/themes/basic"
 	"github.com/nelsam/vidar/commander/bind"
 	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/search"
 )
 
+// Replace is a command which replaces the matches of a pattern in the
+// current editor.  Matches are selected as the pattern is typed, using
+// the shared *FindOptions, and every selection is replaced when the
+// replacement is entered.  With the preserve-case option, each
+// replacement's case follows the case of the match it replaces.
 type Replace struct {
 	driver gxui.Driver
 	theme  *basic.Theme
@@ -22,24 +26,28 @@ type Replace struct {
 	find      *findBox
 	replace   *findBox
 	status    gxui.Label
+	display   *replaceDisplay
 	editor    SelectionEditor
 	applier   Applier
 	edits     []input.Edit
 	is_select bool
 
+	options *FindOptions
+	matcher *search.Pattern
+
 	input <-chan gxui.Focusable
 }
 
-func NewReplace(driver gxui.Driver, theme *basic.Theme) *Replace {
+func NewReplace(driver gxui.Driver, theme *basic.Theme, options *FindOptions) *Replace {
 	replacer := &Replace{}
-	replacer.Init(driver, theme)
+	replacer.Init(driver, theme, options)
 	return replacer
 }
 
-func (f *Replace) Init(driver gxui.Driver, theme *basic.Theme) {
+func (f *Replace) Init(driver gxui.Driver, theme *basic.Theme, options *FindOptions) {
 	f.driver = driver
 	f.theme = theme
-
+	f.options = options
 }
 
 func (f *Replace) Start(control gxui.Control) gxui.Control {
@@ -48,33 +56,11 @@ func (f *Replace) Start(control gxui.Control) gxui.Control {
 		return nil
 	}
 	f.is_select = false
+	f.matcher = nil
 
 	f.find = newFindBox(f.driver, f.theme)
 	f.find.OnTextChanged(func([]gxui.TextBoxEdit) {
-		f.editor.Controller().ClearSelections()
-
-		f.is_select = true
-		needle := f.find.Text()
-		if len(needle) == 0 {
-			f.status.SetText("Start typing to search")
-			return
-		}
-		haystack := f.editor.Text()
-		start := 0
-		var selections []gxui.TextSelection
-
-		count := utf8.RuneCountInString(needle)
-		length := len(needle)
-		pos := 0
-		for next := strings.Index(haystack, needle); next != -1; next = strings.Index(haystack[start:], needle) {
-			pos += utf8.RuneCountInString(haystack[start : start+next])
-			selection := gxui.CreateTextSelection(pos, pos+count, false)
-			selections = append(selections, selection)
-			pos += count
-			start += (next + length)
-		}
-		f.editor.SelectSlice(selections)
-		f.status.SetText(fmt.Sprintf("%s: %d results found", needle, len(selections)))
+		f.search()
 	})
 	f.find.OnKeyPress(func(ev gxui.KeyboardEvent) {
 		switch ev.Key {
@@ -95,10 +81,14 @@ func (f *Replace) Start(control gxui.Control) gxui.Control {
 			for i := len(selections) - 1; i >= 0; i-- {
 				begin, end := selections[i].Start(), selections[i].End()
 				str := []rune(f.editor.Text())[begin:end]
+				replacement := f.replace.Text()
+				if f.matcher != nil {
+					replacement = f.matcher.Replacement(string(str), replacement)
+				}
 				f.edits = append(f.edits, input.Edit{
 					At:  int(begin),
 					Old: str,
-					New: []rune(f.replace.Text()),
+					New: []rune(replacement),
 				})
 			}
 			f.editor.Controller().ClearSelections()
@@ -107,6 +97,17 @@ func (f *Replace) Start(control gxui.Control) gxui.Control {
 		}
 	})
 	f.status = f.theme.CreateLabel()
+	f.display = newReplaceDisplay(f.theme, f.status, f.options)
+	f.display.OnAttach(func() {
+		f.display.toggles.sync()
+		f.options.changed = func() {
+			f.display.toggles.sync()
+			f.search()
+		}
+	})
+	f.display.OnDetach(func() {
+		f.options.changed = nil
+	})
 
 	input := make(chan gxui.Focusable, 3)
 	f.input = input
@@ -114,7 +115,32 @@ func (f *Replace) Start(control gxui.Control) gxui.Control {
 	input <- f.replace
 	close(input)
 
-	return f.status
+	return f.display
+}
+
+// search selects the matches of the pattern in the find box.
+func (f *Replace) search() {
+	f.editor.Controller().ClearSelections()
+
+	f.is_select = true
+	f.matcher = nil
+	needle := f.find.Text()
+	if len(needle) == 0 {
+		f.status.SetText("Start typing to search")
+		return
+	}
+	matcher, find, err := f.options.compile(needle, false)
+	if err != nil {
+		f.status.SetText(err.Error())
+		return
+	}
+	f.matcher = matcher
+	var selections []gxui.TextSelection
+	for _, s := range find(f.editor.Text()) {
+		selections = append(selections, gxui.CreateTextSelection(s.Start, s.End, false))
+	}
+	f.editor.SelectSlice(selections)
+	f.status.SetText(fmt.Sprintf("%s: %d results found", needle, len(selections)))
 }
 
 func (f *Replace) Name() string {
@@ -168,3 +194,30 @@ func (f *Replace) Next() gxui.Focusable {
 	}
 	return next
 }
+
+// replaceDisplay is the replace prompt's status, followed by the
+// toggles for its options.
+type replaceDisplay struct {
+	gxui.LinearLayout
+
+	status  gxui.Label
+	toggles *findToggles
+}
+
+func newReplaceDisplay(theme *basic.Theme, status gxui.Label, options *FindOptions) *replaceDisplay {
+	d := &replaceDisplay{
+		LinearLayout: theme.CreateLinearLayout(),
+		status:       status,
+		toggles:      newFindToggles(theme, options, caseOption, wordOption, regexOption, preserveCaseOption),
+	}
+	d.SetDirection(gxui.LeftToRight)
+	d.AddChild(d.status)
+	d.AddChild(d.toggles)
+	return d
+}
+
+// SetColor sets the color of d's status, so that the command box can
+// color it like other prompts' displays.
+func (d *replaceDisplay) SetColor(c gxui.Color) {
+	d.status.SetColor(c)
+}
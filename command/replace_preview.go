@@ -0,0 +1,382 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/setting"
+)
+
+const (
+	// maxReplaceFileSize is the largest file that will be searched
+	// for a project-wide replace.  Anything larger is most likely
+	// generated.
+	maxReplaceFileSize = 1 << 20
+
+	// maxMatchLineLen is the longest line that will be displayed
+	// for a match.
+	maxMatchLineLen = 80
+
+	includedText = "[x]"
+	excludedText = "[ ]"
+)
+
+var (
+	replaceMatchColor = gxui.Color{R: 0.4, G: 0.6, B: 1, A: 1}
+
+	errReplaceStopped = errors.New("replace stopped")
+)
+
+// ReplacePreview is a panel that lists every match of a project-wide
+// replace, grouped by file.  Each match can be excluded, and nothing
+// is changed until the replace is applied.
+type ReplacePreview struct {
+	mixins.LinearLayout
+
+	driver gxui.Driver
+	theme  gxui.Theme
+
+	status  gxui.Label
+	list    gxui.LinearLayout
+	paneler Paneler
+
+	// job and files are only accessed on the UI goroutine.
+	job   *replaceJob
+	files []*fileMatches
+
+	lock sync.Mutex
+	stop chan struct{}
+}
+
+// NewReplacePreview creates a *ReplacePreview.
+func NewReplacePreview(driver gxui.Driver, theme gxui.Theme) *ReplacePreview {
+	p := &ReplacePreview{
+		driver: driver,
+		theme:  theme,
+		status: theme.CreateLabel(),
+		list:   theme.CreateLinearLayout(),
+	}
+	p.Init(p, theme)
+	p.SetDirection(gxui.TopToBottom)
+
+	header := theme.CreateLinearLayout()
+	header.SetDirection(gxui.LeftToRight)
+	closer := theme.CreateButton()
+	closer.SetText("x")
+	closer.OnClick(func(gxui.MouseEvent) {
+		p.Cancel()
+		p.job = nil
+		p.files = nil
+		if p.paneler != nil {
+			p.paneler.HidePanel(p)
+		}
+	})
+	header.AddChild(closer)
+	apply := theme.CreateButton()
+	apply.SetText("Apply")
+	apply.OnClick(func(gxui.MouseEvent) {
+		p.apply()
+	})
+	header.AddChild(apply)
+	header.AddChild(p.status)
+	p.AddChild(header)
+
+	p.list.SetDirection(gxui.TopToBottom)
+	scrollable := theme.CreateScrollLayout()
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(p.list)
+	p.AddChild(scrollable)
+	return p
+}
+
+// Start cancels any running search, shows p using paneler, and starts
+// searching for the matches of job in the background.  It must be
+// called on the UI goroutine.
+func (p *ReplacePreview) Start(paneler Paneler, job replaceJob) {
+	p.Cancel()
+	p.paneler = paneler
+	p.job = &job
+	p.files = nil
+	p.list.RemoveAll()
+
+	// Open editors can only be read on the UI goroutine, so their
+	// text is copied before searching.
+	texts := make(map[string]string, len(job.open))
+	for path, e := range job.open {
+		texts[path] = e.Text()
+	}
+
+	p.lock.Lock()
+	stop := make(chan struct{})
+	p.stop = stop
+	p.lock.Unlock()
+
+	p.status.SetText(fmt.Sprintf("Searching %s...", job.root))
+	if !paneler.HasPanel(p) {
+		paneler.ShowPanel(p)
+	}
+	go p.search(stop, job, texts)
+}
+
+// Cancel stops any running search.
+func (p *ReplacePreview) Cancel() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	p.stop = nil
+}
+
+func (p *ReplacePreview) searching() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.stop != nil
+}
+
+func (p *ReplacePreview) current(stop chan struct{}) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.stop != nil && p.stop == stop
+}
+
+func (p *ReplacePreview) search(stop chan struct{}, job replaceJob, texts map[string]string) {
+	open := make([]string, 0, len(texts))
+	for path := range texts {
+		open = append(open, path)
+	}
+	sort.Strings(open)
+	for _, path := range open {
+		p.match(stop, job, path, texts[path])
+	}
+
+	filepath.Walk(job.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != job.root && (strings.HasPrefix(info.Name(), ".") || setting.IgnoredDir(job.root, path)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, ok := texts[path]; ok {
+			return nil
+		}
+		if !info.Mode().IsRegular() || info.Size() > maxReplaceFileSize || setting.Ignored(job.root, path, false) {
+			return nil
+		}
+		select {
+		case <-stop:
+			return errReplaceStopped
+		default:
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil || bytes.IndexByte(b, 0) != -1 {
+			return nil
+		}
+		p.match(stop, job, path, string(b))
+		return nil
+	})
+
+	p.driver.Call(func() {
+		if !p.current(stop) {
+			return
+		}
+		p.lock.Lock()
+		p.stop = nil
+		p.lock.Unlock()
+		p.updateStatus()
+	})
+}
+
+func (p *ReplacePreview) match(stop chan struct{}, job replaceJob, path, text string) {
+	matches := findMatches(text, job.re, job.replacement)
+	if len(matches) == 0 {
+		return
+	}
+	f := &fileMatches{path: path, text: []rune(text), matches: matches}
+	p.driver.Call(func() {
+		p.addFile(stop, f)
+	})
+}
+
+func (p *ReplacePreview) addFile(stop chan struct{}, f *fileMatches) {
+	if !p.current(stop) {
+		return
+	}
+	p.files = append(p.files, f)
+	name, err := filepath.Rel(p.job.root, f.path)
+	if err != nil {
+		name = f.path
+	}
+	header := p.theme.CreateLabel()
+	header.SetText(name)
+	p.list.AddChild(header)
+	for _, m := range f.matches {
+		p.list.AddChild(p.row(f.path, m))
+	}
+	p.updateStatus()
+}
+
+// row returns a row for m, with a toggle to exclude it and a label
+// that opens it when clicked.
+func (p *ReplacePreview) row(path string, m *replaceMatch) gxui.Control {
+	row := p.theme.CreateLinearLayout()
+	row.SetDirection(gxui.LeftToRight)
+	row.SetMargin(math.Spacing{L: 10})
+
+	toggle := p.theme.CreateButton()
+	toggle.SetText(includedText)
+	toggle.OnClick(func(gxui.MouseEvent) {
+		m.excluded = !m.excluded
+		toggle.SetText(includedText)
+		if m.excluded {
+			toggle.SetText(excludedText)
+		}
+		p.updateStatus()
+	})
+	row.AddChild(toggle)
+
+	text := strings.Replace(strings.TrimSpace(m.lineText), "\t", "    ", -1)
+	if utf8.RuneCountInString(text) > maxMatchLineLen {
+		text = string([]rune(text)[:maxMatchLineLen]) + "…"
+	}
+	l := p.theme.CreateLabel()
+	l.SetText(fmt.Sprintf("%d: %s  →  %s", m.line+1, text, string(m.edit.New)))
+	l.SetColor(replaceMatchColor)
+	job := p.job
+	l.OnClick(func(gxui.MouseEvent) {
+		job.execer.Execute(job.focuser.For(focus.Path(path), focus.Line(m.line), focus.Column(m.col)))
+	})
+	row.AddChild(l)
+	return row
+}
+
+func (p *ReplacePreview) updateStatus() {
+	total, included, files := 0, 0, 0
+	for _, f := range p.files {
+		fileIncluded := 0
+		for _, m := range f.matches {
+			total++
+			if !m.excluded {
+				fileIncluded++
+			}
+		}
+		if fileIncluded > 0 {
+			files++
+		}
+		included += fileIncluded
+	}
+	msg := fmt.Sprintf("Replace %d of %d matches in %d files", included, total, files)
+	if p.searching() {
+		msg = "Searching... " + msg
+	}
+	p.status.SetText(msg)
+}
+
+// read returns the current text of path, using the open editor for it
+// if there is one.
+func (p *ReplacePreview) read(path string) ([]rune, error) {
+	if e, ok := p.job.open[path]; ok {
+		return e.Runes(), nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return []rune(string(b)), nil
+}
+
+// apply replaces every match that hasn't been excluded.  Nothing is
+// changed if any of the files have changed since they were searched.
+func (p *ReplacePreview) apply() {
+	if p.job == nil {
+		return
+	}
+	if p.searching() {
+		p.status.SetText("Still searching; wait for the search to finish before applying")
+		return
+	}
+	for _, f := range p.files {
+		text, err := p.read(f.path)
+		if err != nil {
+			p.status.SetText(fmt.Sprintf("Could not read %s: %s", f.path, err))
+			return
+		}
+		if string(text) != string(f.text) {
+			p.status.SetText(fmt.Sprintf("%s has changed since it was searched; run the replace again", filepath.Base(f.path)))
+			return
+		}
+	}
+
+	var (
+		failed        []string
+		count, edited int
+	)
+	for _, f := range p.files {
+		var edits []input.Edit
+		for _, m := range f.matches {
+			if !m.excluded {
+				edits = append(edits, m.edit)
+			}
+		}
+		if len(edits) == 0 {
+			continue
+		}
+		if e, ok := p.job.open[f.path]; ok {
+			p.job.applier.Apply(e, edits...)
+		} else if err := writeEdits(f.path, f.text, edits); err != nil {
+			log.Printf("Error writing replacements to %s: %s", f.path, err)
+			failed = append(failed, filepath.Base(f.path))
+			continue
+		}
+		count += len(edits)
+		edited++
+	}
+	p.job = nil
+	p.files = nil
+	p.list.RemoveAll()
+	if len(failed) > 0 {
+		p.status.SetText(fmt.Sprintf("Replaced %d matches in %d files, but could not write %s", count, edited, strings.Join(failed, ", ")))
+		return
+	}
+	p.status.SetText(fmt.Sprintf("Replaced %d matches in %d files", count, edited))
+}
+
+// writeEdits applies edits to text and writes the result to path.
+// Edits must be sorted and relative to text.
+func writeEdits(path string, text []rune, edits []input.Edit) error {
+	finfo, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	var result []rune
+	last := 0
+	for _, e := range edits {
+		result = append(result, text[last:e.At]...)
+		result = append(result, e.New...)
+		last = e.At + len(e.Old)
+	}
+	result = append(result, text[last:]...)
+	return ioutil.WriteFile(path, []byte(string(result)), finfo.Mode())
+}
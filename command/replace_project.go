@@ -0,0 +1,236 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// ProjectEditors is a type that knows the current project and the
+// editors that are open in it.
+type ProjectEditors interface {
+	Project() setting.Project
+	OpenEditors() []input.Editor
+}
+
+// A Paneler is a type that can display panels below the editor.
+type Paneler interface {
+	ShowPanel(gxui.Control)
+	HidePanel(gxui.Control)
+	HasPanel(gxui.Control) bool
+}
+
+// ReplaceInProject is a command which replaces matches of a regular
+// expression in every file in the current project.  The replacement
+// may refer to capture groups (e.g. $1 or ${name}).  Matches are
+// listed in a *ReplacePreview, where they can be excluded before the
+// replacement is applied.
+type ReplaceInProject struct {
+	status.General
+
+	driver gxui.Driver
+	theme  *basic.Theme
+
+	pattern     *findBox
+	replacement *findBox
+	prompt      gxui.Label
+	input       <-chan gxui.Focusable
+
+	preview *ReplacePreview
+
+	editors ProjectEditors
+	applier Applier
+	paneler Paneler
+	focuser Focuser
+	execer  Executor
+}
+
+func NewReplaceInProject(driver gxui.Driver, theme *basic.Theme) *ReplaceInProject {
+	r := &ReplaceInProject{
+		driver:      driver,
+		theme:       theme,
+		pattern:     newFindBox(driver, theme),
+		replacement: newFindBox(driver, theme),
+		prompt:      theme.CreateLabel(),
+		preview:     NewReplacePreview(driver, theme),
+	}
+	r.Theme = theme
+	return r
+}
+
+func (r *ReplaceInProject) Name() string {
+	return "replace-all-in-project"
+}
+
+func (r *ReplaceInProject) Menu() string {
+	return "Edit"
+}
+
+func (r *ReplaceInProject) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModShift,
+		Key:      gxui.KeyR,
+	}}
+}
+
+func (r *ReplaceInProject) Start(gxui.Control) gxui.Control {
+	r.pattern.SetText("")
+	r.replacement.SetText("")
+
+	input := make(chan gxui.Focusable, 2)
+	input <- r.pattern
+	input <- r.replacement
+	close(input)
+	r.input = input
+	return r.prompt
+}
+
+func (r *ReplaceInProject) Next() gxui.Focusable {
+	next := <-r.input
+	switch next {
+	case r.pattern:
+		r.prompt.SetText("Find (regexp):")
+	case r.replacement:
+		r.prompt.SetText("Replace with:")
+	}
+	return next
+}
+
+func (r *ReplaceInProject) Reset() {
+	r.editors = nil
+	r.applier = nil
+	r.paneler = nil
+	r.focuser = nil
+	r.execer = nil
+}
+
+func (r *ReplaceInProject) Store(target interface{}) bind.Status {
+	switch src := target.(type) {
+	case ProjectEditors:
+		r.editors = src
+	case Applier:
+		r.applier = src
+	case Paneler:
+		r.paneler = src
+	case Focuser:
+		r.focuser = src
+	case Executor:
+		r.execer = src
+	}
+	if r.editors != nil && r.applier != nil && r.paneler != nil && r.focuser != nil && r.execer != nil {
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (r *ReplaceInProject) Exec() error {
+	pattern := r.pattern.Text()
+	if pattern == "" {
+		r.Warn = "No pattern provided"
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		r.Err = fmt.Sprintf("Invalid pattern: %s", err)
+		return err
+	}
+	proj := r.editors.Project()
+	job := replaceJob{
+		root:        proj.Path,
+		re:          re,
+		replacement: r.replacement.Text(),
+		open:        make(map[string]input.Editor),
+		applier:     r.applier,
+		focuser:     r.focuser,
+		execer:      r.execer,
+	}
+	for _, e := range r.editors.OpenEditors() {
+		if strings.HasPrefix(e.Filepath(), proj.Path) {
+			job.open[e.Filepath()] = e
+		}
+	}
+	r.preview.Start(r.paneler, job)
+	return nil
+}
+
+// replaceJob is a project-wide replace that is being previewed.
+type replaceJob struct {
+	root        string
+	re          *regexp.Regexp
+	replacement string
+
+	// open holds the editors for files in the project that are
+	// open.  Those files are searched and edited in place; all
+	// others are read from and written to disk.
+	open map[string]input.Editor
+
+	applier Applier
+	focuser Focuser
+	execer  Executor
+}
+
+// replaceMatch is a single match in a project-wide replace.
+type replaceMatch struct {
+	edit input.Edit
+
+	// line and col are the zero-indexed position of the match,
+	// for display and for opening the match.
+	line, col int
+	lineText  string
+
+	excluded bool
+}
+
+// fileMatches holds the matches in a single file.
+type fileMatches struct {
+	path    string
+	text    []rune
+	matches []*replaceMatch
+}
+
+// findMatches returns each match of re in text, with replacement
+// expanded for each of them.
+func findMatches(text string, re *regexp.Regexp, replacement string) []*replaceMatch {
+	var (
+		matches                []*replaceMatch
+		prev, runes, line, bol int
+	)
+	for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		skipped := text[prev:start]
+		runes += utf8.RuneCountInString(skipped)
+		if n := strings.Count(skipped, "\n"); n > 0 {
+			line += n
+			bol = prev + strings.LastIndex(skipped, "\n") + 1
+		}
+		prev = start
+
+		eol := len(text)
+		if i := strings.IndexByte(text[bol:], '\n'); i >= 0 {
+			eol = bol + i
+		}
+		matches = append(matches, &replaceMatch{
+			edit: input.Edit{
+				At:  runes,
+				Old: []rune(text[start:end]),
+				New: []rune(string(re.ExpandString(nil, replacement, text, loc))),
+			},
+			line:     line,
+			col:      utf8.RuneCountInString(text[bol:start]),
+			lineText: text[bol:eol],
+		})
+	}
+	return matches
+}
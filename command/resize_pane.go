@@ -0,0 +1,130 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+)
+
+// resizeStep is the fraction of a split that grow-pane and
+// shrink-pane resize the focused pane by.
+const resizeStep = 0.1
+
+// A PaneResizer is a split view that can resize its focused pane.
+type PaneResizer interface {
+	ResizeCurrent(delta float64) bool
+}
+
+// An Equalizer is a split view that can make all of its panes the
+// same size.
+type Equalizer interface {
+	EqualizePanes()
+}
+
+// ResizePane is a command which grows or shrinks the focused pane of
+// a split view, as an alternative to dragging the splitter bars.
+type ResizePane struct {
+	name  string
+	key   gxui.KeyboardKey
+	delta float64
+}
+
+func NewGrowPane() *ResizePane {
+	return &ResizePane{name: "grow-pane", key: gxui.KeyEqual, delta: resizeStep}
+}
+
+func NewShrinkPane() *ResizePane {
+	return &ResizePane{name: "shrink-pane", key: gxui.KeyMinus, delta: -resizeStep}
+}
+
+func (r *ResizePane) Name() string {
+	return r.name
+}
+
+func (r *ResizePane) Menu() string {
+	return "View"
+}
+
+func (r *ResizePane) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModAlt,
+		Key:      r.key,
+	}}
+}
+
+func (r *ResizePane) Exec(target interface{}) bind.Status {
+	resizer, ok := target.(PaneResizer)
+	if !ok {
+		return bind.Waiting
+	}
+	resizer.ResizeCurrent(r.delta)
+	return bind.Done
+}
+
+// EqualizePanes is a command which resets every pane in the split
+// view to the same size.
+type EqualizePanes struct{}
+
+func (EqualizePanes) Name() string {
+	return "equalize-panes"
+}
+
+func (EqualizePanes) Menu() string {
+	return "View"
+}
+
+func (EqualizePanes) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModAlt,
+		Key:      gxui.Key0,
+	}}
+}
+
+func (EqualizePanes) Exec(target interface{}) bind.Status {
+	equalizer, ok := target.(Equalizer)
+	if !ok {
+		return bind.Waiting
+	}
+	equalizer.EqualizePanes()
+	return bind.Done
+}
+
+// A PaneMaximizer is a split view that can expand its focused pane to
+// fill the view, and restore its previous layout afterward.
+type PaneMaximizer interface {
+	ToggleMaximized() bool
+}
+
+// MaximizePane is a command which expands the focused pane of a split
+// view to fill the view.  Running it again puts the panes back the
+// way they were.
+type MaximizePane struct{}
+
+func (MaximizePane) Name() string {
+	return "maximize-pane"
+}
+
+func (MaximizePane) Menu() string {
+	return "View"
+}
+
+func (MaximizePane) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModAlt,
+		Key:      gxui.KeyZ,
+	}}
+}
+
+func (MaximizePane) Exec(target interface{}) bind.Status {
+	maximizer, ok := target.(PaneMaximizer)
+	if !ok {
+		return bind.Waiting
+	}
+	maximizer.ToggleMaximized()
+	return bind.Done
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/vidar/commander/bind"
 	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/editor"
+	"github.com/nelsam/vidar/eol"
 	"github.com/nelsam/vidar/plugin/status"
 	"github.com/nelsam/vidar/setting"
 )
@@ -27,6 +29,18 @@ type SaveEditor interface {
 	LastKnownMTime() time.Time
 }
 
+// A LineEnder is an editor whose file may use line endings other than
+// LF.  Its text always uses LF, and is converted when it's written.
+type LineEnder interface {
+	LineEnding() eol.Ending
+}
+
+// A BinaryEditor is an editor that may have a binary file open, which
+// would be changed by writing it as text.
+type BinaryEditor interface {
+	Binary() bool
+}
+
 type Projecter interface {
 	Project() setting.Project
 }
@@ -36,11 +50,31 @@ type BeforeSaver interface {
 	BeforeSave(proj setting.Project, path, contents string) (newContents string, err error)
 }
 
+// A DiagnosticError is an error from a BeforeSaver that can be
+// shown in the editor.  The diagnostics are published using the
+// hook's name as their source, and are cleared the next time that
+// the hook succeeds.
+type DiagnosticError interface {
+	error
+	Diagnostics() []input.Diagnostic
+}
+
 type AfterSaver interface {
 	Name() string
 	AfterSave(proj setting.Project, path, contents string) error
 }
 
+// A BackgroundSaver is a hook that starts work on a file after it has
+// been saved, for work that is too slow to hold up the save (e.g. a
+// formatter that has to load the module cache).  BackgroundSave is
+// called on the UI goroutine and must return right away, doing its
+// work in another goroutine.  It's passed the saved editor so that it
+// can update the editor when the work is done.
+type BackgroundSaver interface {
+	Name() string
+	BackgroundSave(proj setting.Project, editor input.Editor, contents string)
+}
+
 type SaveCurrent struct {
 	status.General
 
@@ -48,8 +82,14 @@ type SaveCurrent struct {
 	applier Applier
 	editor  SaveEditor
 
-	before []BeforeSaver
-	after  []AfterSaver
+	// target is the editor to save, if it was chosen with
+	// ForEditor instead of using the focused editor.
+	target    SaveEditor
+	targetErr error
+
+	before     []BeforeSaver
+	after      []AfterSaver
+	background []BackgroundSaver
 }
 
 func NewSave(theme gxui.Theme) *SaveCurrent {
@@ -77,24 +117,46 @@ func (s *SaveCurrent) Bind(h bind.Bindable) (bind.HookedMultiOp, error) {
 	newS := NewSave(s.Theme)
 	newS.before = append(newS.before, s.before...)
 	newS.after = append(newS.after, s.after...)
+	newS.background = append(newS.background, s.background...)
 	switch src := h.(type) {
 	case BeforeSaver:
 		newS.before = append(newS.before, src)
 	case AfterSaver:
 		newS.after = append(newS.after, src)
+	case BackgroundSaver:
+		newS.background = append(newS.background, src)
 	default:
-		return nil, fmt.Errorf("expected BeforeSaver or AfterSaver; got %T", h)
+		return nil, fmt.Errorf("expected BeforeSaver, AfterSaver, or BackgroundSaver; got %T", h)
 	}
 	return newS, nil
 }
 
+// ForEditor returns a copy of s that will save e instead of the
+// focused editor.  Any hooks bound to s will still run.
+func (s *SaveCurrent) ForEditor(e input.Editor) bind.Bindable {
+	newS := NewSave(s.Theme)
+	newS.before = s.before
+	newS.after = s.after
+	newS.background = s.background
+	target, ok := e.(SaveEditor)
+	if !ok {
+		newS.targetErr = fmt.Errorf("editor of type %T cannot be saved", e)
+	}
+	newS.target = target
+	return newS
+}
+
 func (s *SaveCurrent) Reset() {
 	s.proj = nil
 	s.applier = nil
-	s.editor = nil
+	s.editor = s.target
 }
 
 func (s *SaveCurrent) Store(target interface{}) bind.Status {
+	if s.targetErr != nil {
+		s.Err = s.targetErr.Error()
+		return bind.Errored
+	}
 	switch src := target.(type) {
 	case Projecter:
 		proj := src.Project()
@@ -102,7 +164,9 @@ func (s *SaveCurrent) Store(target interface{}) bind.Status {
 	case Applier:
 		s.applier = src
 	case SaveEditor:
-		s.editor = src
+		if s.target == nil {
+			s.editor = src
+		}
 	}
 	if s.editor != nil && s.proj != nil && s.applier != nil {
 		return bind.Done
@@ -125,6 +189,11 @@ func (s *SaveCurrent) Exec() error {
 		}
 	}
 
+	if b, ok := s.editor.(BinaryEditor); ok && b.Binary() {
+		s.Err = fmt.Sprintf("%s is a binary file.  Refusing to overwrite it.", filepath)
+		return fmt.Errorf("save-current-file: %s", s.Err)
+	}
+
 	text := s.editor.Text()
 	formatted := text
 	if !strings.HasSuffix(formatted, "\n") {
@@ -134,6 +203,11 @@ func (s *SaveCurrent) Exec() error {
 	proj := *s.proj
 	for _, b := range s.before {
 		newText, err := b.BeforeSave(proj, filepath, text)
+		var diags []input.Diagnostic
+		if dErr, ok := err.(DiagnosticError); ok {
+			diags = dErr.Diagnostics()
+		}
+		s.editor.SetDiagnostics(b.Name(), diags)
 		if err != nil {
 			s.Warn += fmt.Sprintf("%s: %s  ", b.Name(), err)
 			continue
@@ -150,25 +224,23 @@ func (s *SaveCurrent) Exec() error {
 		text = formatted
 	}
 
-	f, err := os.Create(filepath)
-	if err != nil {
-		s.Err = fmt.Sprintf("Could not open %s for writing: %s", filepath, err)
+	written := text
+	if l, ok := s.editor.(LineEnder); ok {
+		written = eol.Convert(text, l.LineEnding())
+	}
+	if err := editor.WriteFile(filepath, written); err != nil {
+		s.Err = fmt.Sprintf("Could not save %s: %s", filepath, err)
 		return err
 	}
-	defer func() {
-		f.Close()
-		for _, a := range s.after {
-			if err := a.AfterSave(proj, filepath, text); err != nil {
-				s.Warn += fmt.Sprintf("%s: %s  ", a.Name(), err)
-			}
+	for _, a := range s.after {
+		if err := a.AfterSave(proj, filepath, text); err != nil {
+			s.Warn += fmt.Sprintf("%s: %s  ", a.Name(), err)
 		}
-	}()
-
-	if _, err := f.WriteString(text); err != nil {
-		s.Err = fmt.Sprintf("Could not write to file %s: %s", filepath, err)
-		return err
 	}
 	s.Info = fmt.Sprintf("Successfully saved %s", filepath)
 	s.editor.FlushedChanges()
+	for _, b := range s.background {
+		b.BackgroundSave(proj, s.editor, text)
+	}
 	return nil
 }
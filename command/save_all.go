@@ -6,19 +6,30 @@ package command
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
 )
 
+// An AllSaver is a type that can save all of its editors, returning
+// an error for each one that could not be saved.
 type AllSaver interface {
-	SaveAll()
+	SaveAll() []error
 }
 
-type SaveAll struct{}
+// SaveAll is a command which saves every open file with unsaved
+// changes.  Files are written atomically, and any that fail are
+// listed in the status.
+type SaveAll struct {
+	status.General
+}
 
 func NewSaveAll(theme gxui.Theme) *SaveAll {
-	return &SaveAll{}
+	s := &SaveAll{}
+	s.Theme = theme
+	return s
 }
 
 func (s *SaveAll) Name() string {
@@ -41,6 +52,15 @@ func (s *SaveAll) Exec(target interface{}) bind.Status {
 	if !ok {
 		return bind.Waiting
 	}
-	saver.SaveAll()
+	errs := saver.SaveAll()
+	if len(errs) == 0 {
+		s.Info = "Saved all files"
+		return bind.Done
+	}
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	s.Err = fmt.Sprintf("Could not save %d files: %s", len(errs), strings.Join(msgs, "; "))
 	return bind.Done
 }
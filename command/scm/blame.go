@@ -0,0 +1,169 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// uncommitted is the commit that git blame reports for lines which
+// haven't been committed yet.
+const uncommitted = "0000000000000000000000000000000000000000"
+
+// BlameLine is the commit that last changed a line.
+type BlameLine struct {
+	Commit  string
+	Author  string
+	Time    time.Time
+	Summary string
+}
+
+// Committed returns whether l has been committed.
+func (l BlameLine) Committed() bool {
+	return l.Commit != "" && l.Commit != uncommitted
+}
+
+// blames holds the blame for each file that blame annotations are
+// shown for, so that the commit for a line can be looked up.  It's
+// only accessed on the UI goroutine.
+type blames map[string][]BlameLine
+
+// Blame returns the commit that last changed each line of the file at
+// path, using the version control system that tracks it.  Contents is
+// used as the file's current contents, so unsaved lines are reported
+// as uncommitted.  The returned messages map each commit to its full
+// commit message.
+func Blame(path, contents string, environ []string) (lines []BlameLine, messages map[string]string, err error) {
+	v, _, err := For(filepath.Dir(path), environ)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v.Blame(path, contents, environ)
+}
+
+// commitMessages looks up the full message of each commit in lines.
+func commitMessages(dir string, environ []string, lines []BlameLine) (map[string]string, error) {
+	args := []string{"show", "-s", "--format=%H%n%B%x00"}
+	seen := make(map[string]bool)
+	for _, l := range lines {
+		if !l.Committed() || seen[l.Commit] {
+			continue
+		}
+		seen[l.Commit] = true
+		args = append(args, l.Commit)
+	}
+	messages := make(map[string]string, len(seen))
+	if len(seen) == 0 {
+		return messages, nil
+	}
+	out, err := git(dir, environ, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range strings.Split(string(out), "\x00") {
+		parts := strings.SplitN(strings.TrimLeft(entry, "\n"), "\n", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		messages[parts[0]] = strings.TrimSpace(parts[1])
+	}
+	return messages, nil
+}
+
+// ParseBlame parses the output of git blame --line-porcelain,
+// returning one BlameLine for each line of the file.
+func ParseBlame(r io.Reader) []BlameLine {
+	var (
+		lines   []BlameLine
+		current BlameLine
+		header  = true
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		l := scanner.Text()
+		if strings.HasPrefix(l, "\t") {
+			// The line's contents end each entry.
+			lines = append(lines, current)
+			current = BlameLine{}
+			header = true
+			continue
+		}
+		if header {
+			fields := strings.Fields(l)
+			if len(fields) > 0 {
+				current.Commit = fields[0]
+			}
+			header = false
+			continue
+		}
+		key, value := l, ""
+		if i := strings.IndexByte(l, ' '); i >= 0 {
+			key, value = l[:i], l[i+1:]
+		}
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-time":
+			if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+				current.Time = time.Unix(secs, 0)
+			}
+		case "summary":
+			current.Summary = value
+		}
+	}
+	return lines
+}
+
+// blameAnnotations returns the annotation to display for each of
+// lines, relative to now.
+func blameAnnotations(lines []BlameLine, messages map[string]string, now time.Time) []input.Annotation {
+	notes := make([]input.Annotation, 0, len(lines))
+	for _, l := range lines {
+		if !l.Committed() {
+			notes = append(notes, input.Annotation{Text: "uncommitted"})
+			continue
+		}
+		msg, ok := messages[l.Commit]
+		if !ok {
+			msg = l.Summary
+		}
+		notes = append(notes, input.Annotation{
+			Text:   fmt.Sprintf("%.8s %-12.12s %3s", l.Commit, l.Author, age(now.Sub(l.Time))),
+			Detail: fmt.Sprintf("%s\n%s, %s\n\n%s", l.Commit, l.Author, l.Time.Format("2006-01-02 15:04"), msg),
+		})
+	}
+	return notes
+}
+
+// age returns a short, rounded-down description of d, such as "3d"
+// or "2mo".
+func age(d time.Duration) string {
+	const (
+		day   = 24 * time.Hour
+		month = 30 * day
+		year  = 365 * day
+	)
+	switch {
+	case d < time.Hour:
+		return "now"
+	case d < day:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d < month:
+		return fmt.Sprintf("%dd", d/day)
+	case d < year:
+		return fmt.Sprintf("%dmo", d/month)
+	default:
+		return fmt.Sprintf("%dy", d/year)
+	}
+}
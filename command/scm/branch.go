@@ -0,0 +1,107 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A Brancher is a VCS with named branches that can be switched
+// between.
+type Brancher interface {
+	VCS
+
+	// Branch returns the branch that is checked out in the
+	// repository at root, along with how far it is from its
+	// upstream.
+	Branch(root string, environ []string) (BranchStatus, error)
+
+	// Branches returns the names of the local branches in the
+	// repository at root.
+	Branches(root string, environ []string) ([]string, error)
+
+	// Checkout switches the repository at root to branch.
+	Checkout(root, branch string, environ []string) error
+
+	// Stash puts every uncommitted change in the repository at root
+	// aside, leaving a clean working tree.
+	Stash(root string, environ []string) error
+}
+
+// BranchStatus is the state of the branch that is checked out in a
+// repository.
+type BranchStatus struct {
+	// Name is the name of the branch, or the abbreviated commit
+	// for a detached head.
+	Name string
+
+	// Detached is true when no branch is checked out.
+	Detached bool
+
+	// Upstream is the name of the branch that Name tracks, if any.
+	Upstream string
+
+	// Ahead and Behind are the number of commits that Name has and
+	// Upstream doesn't, and vice versa.
+	Ahead, Behind int
+}
+
+// String returns a short description of b, suitable for the status
+// bar.
+func (b BranchStatus) String() string {
+	s := b.Name
+	if b.Detached {
+		s = "detached at " + s
+	}
+	if b.Ahead > 0 {
+		s += fmt.Sprintf(", %d ahead", b.Ahead)
+	}
+	if b.Behind > 0 {
+		s += fmt.Sprintf(", %d behind", b.Behind)
+	}
+	return s
+}
+
+// ParseBranchStatus parses the header lines of git status
+// --porcelain=v2 --branch.  Other lines are ignored.
+func ParseBranchStatus(r io.Reader) BranchStatus {
+	var (
+		b   BranchStatus
+		oid string
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[0] != "#" {
+			continue
+		}
+		switch fields[1] {
+		case "branch.oid":
+			oid = fields[2]
+		case "branch.head":
+			b.Name = fields[2]
+		case "branch.upstream":
+			b.Upstream = fields[2]
+		case "branch.ab":
+			if len(fields) < 4 {
+				continue
+			}
+			b.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+			b.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+		}
+	}
+	if b.Name == "(detached)" {
+		b.Detached = true
+		b.Name = oid
+		if len(b.Name) > 7 {
+			b.Name = b.Name[:7]
+		}
+	}
+	return b
+}
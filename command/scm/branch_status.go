@@ -0,0 +1,98 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// branchInterval is how long BranchSegment shows a branch before it
+// loads it again.
+const branchInterval = 5 * time.Second
+
+// A Filepather is an editor for a file.
+type Filepather interface {
+	Filepath() string
+}
+
+// BranchSegment is a status segment which displays the branch that is
+// checked out in the repository of the focused file, and how far that
+// branch is ahead of or behind its upstream.  Branches are loaded in
+// the background and reloaded every few seconds, so changes made
+// outside of vidar show up after a short delay.
+type BranchSegment struct {
+	mu   sync.Mutex
+	dirs map[string]*branchText
+}
+
+// branchText is the text that a BranchSegment displays for one
+// directory.
+type branchText struct {
+	text    string
+	loaded  time.Time
+	loading bool
+}
+
+func NewBranchSegment() *BranchSegment {
+	return &BranchSegment{dirs: make(map[string]*branchText)}
+}
+
+func (s *BranchSegment) Name() string {
+	return "status-branch"
+}
+
+func (s *BranchSegment) Text(editor interface{}) string {
+	f, ok := editor.(Filepather)
+	if !ok || f.Filepath() == "" {
+		return ""
+	}
+	dir := filepath.Dir(f.Filepath())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.dirs[dir]
+	if !ok {
+		t = &branchText{}
+		s.dirs[dir] = t
+	}
+	if !t.loading && time.Since(t.loaded) > branchInterval {
+		t.loading = true
+		go s.load(dir, t)
+	}
+	return t.text
+}
+
+// Expire makes s load every branch again the next time that it's
+// displayed, e.g. after a checkout.
+func (s *BranchSegment) Expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.dirs {
+		t.loaded = time.Time{}
+	}
+}
+
+func (s *BranchSegment) load(dir string, t *branchText) {
+	text := ""
+	if v, root, err := For(dir, nil); err == nil {
+		if b, ok := v.(Brancher); ok {
+			status, err := b.Branch(root, nil)
+			if err != nil {
+				log.Printf("Could not load the %s branch of %s: %s", v.Name(), root, err)
+			} else {
+				text = v.Name() + ": " + status.String()
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.text = text
+	t.loaded = time.Now()
+	t.loading = false
+}
@@ -0,0 +1,236 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// panel holds the Pane that the source control commands share, so
+// that a message typed into it is still there when it's committed.
+// The pane is created the first time it's needed.
+type panel struct {
+	driver gxui.Driver
+	theme  *basic.Theme
+	pane   *Pane
+}
+
+func (p *panel) get() *Pane {
+	if p.pane == nil {
+		p.pane = NewPane(p.driver, p.theme)
+	}
+	return p.pane
+}
+
+// sourceControl is embedded by the commands that use the source
+// control pane.
+type sourceControl struct {
+	status.General
+
+	panel *panel
+
+	paneler   Paneler
+	projecter Projecter
+	focuser   Focuser
+	execer    Executor
+}
+
+func (s *sourceControl) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (s *sourceControl) Reset() {
+	s.Clear()
+	s.paneler = nil
+	s.projecter = nil
+	s.focuser = nil
+	s.execer = nil
+}
+
+func (s *sourceControl) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Paneler:
+		s.paneler = src
+	case Projecter:
+		s.projecter = src
+	case Focuser:
+		s.focuser = src
+	case Executor:
+		s.execer = src
+	}
+	if s.paneler == nil || s.projecter == nil || s.focuser == nil || s.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+// show shows the pane for the repository of the current project and
+// returns it.
+func (s *sourceControl) show() (*Pane, error) {
+	proj := s.projecter.Project()
+	v, root, err := For(proj.Path, proj.Environ())
+	if err != nil {
+		s.Err = fmt.Sprintf("Could not open source control for %s: %s", proj.Name, err)
+		return nil, err
+	}
+	stager, ok := v.(Stager)
+	if !ok {
+		err := fmt.Errorf("%s has no staging area", v.Name())
+		s.Err = fmt.Sprintf("Could not open source control for %s: %s", proj.Name, err)
+		return nil, err
+	}
+	p := s.panel.get()
+	p.Show(s.paneler, stager, root, proj, paneOpener(s.focuser, s.execer))
+	return p, nil
+}
+
+// ShowSourceControl is a command which shows or hides the source
+// control pane for the current project's repository.
+type ShowSourceControl struct {
+	sourceControl
+}
+
+func NewShowSourceControl(theme gxui.Theme, p *panel) *ShowSourceControl {
+	s := &ShowSourceControl{}
+	s.Theme = theme
+	s.panel = p
+	return s
+}
+
+func (s *ShowSourceControl) Name() string {
+	return "show-source-control"
+}
+
+func (s *ShowSourceControl) Menu() string {
+	return "View"
+}
+
+func (s *ShowSourceControl) Exec() error {
+	if p := s.panel.pane; p != nil && p.Shown() {
+		p.Hide()
+		return nil
+	}
+	_, err := s.show()
+	return err
+}
+
+// CommitChanges is a command which commits the staged changes with
+// the message in the source control pane.
+type CommitChanges struct {
+	sourceControl
+}
+
+func NewCommitChanges(theme gxui.Theme, p *panel) *CommitChanges {
+	c := &CommitChanges{}
+	c.Theme = theme
+	c.panel = p
+	return c
+}
+
+func (c *CommitChanges) Name() string {
+	return "commit"
+}
+
+func (c *CommitChanges) Menu() string {
+	return "File"
+}
+
+func (c *CommitChanges) Exec() error {
+	p, err := c.show()
+	if err != nil {
+		return err
+	}
+	if err := p.Commit(false); err != nil {
+		p.FocusMessage()
+		c.Warn = "Write a commit message in the source control pane, then commit again"
+		return nil
+	}
+	c.Info = "Committing"
+	return nil
+}
+
+// AmendCommit is a command which replaces the last commit with the
+// staged changes and the message in the source control pane.  If the
+// message is empty, it's filled with the last commit's message so
+// that it can be edited before amending.
+type AmendCommit struct {
+	sourceControl
+}
+
+func NewAmendCommit(theme gxui.Theme, p *panel) *AmendCommit {
+	a := &AmendCommit{}
+	a.Theme = theme
+	a.panel = p
+	return a
+}
+
+func (a *AmendCommit) Name() string {
+	return "amend-commit"
+}
+
+func (a *AmendCommit) Menu() string {
+	return "File"
+}
+
+func (a *AmendCommit) Exec() error {
+	p, err := a.show()
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(p.Message()) == "" {
+		msg, err := p.LastMessage()
+		if err != nil {
+			a.Err = fmt.Sprintf("Could not load the last commit message: %s", err)
+			return err
+		}
+		p.SetMessage(msg)
+		p.FocusMessage()
+		a.Info = "Edit the last commit's message, then amend again"
+		return nil
+	}
+	if err := p.Commit(true); err != nil {
+		a.Err = fmt.Sprintf("Could not amend: %s", err)
+		return err
+	}
+	a.Info = "Amending the last commit"
+	return nil
+}
+
+// Push is a command which pushes the current branch of the current
+// project's repository to its upstream.
+type Push struct {
+	sourceControl
+}
+
+func NewPush(theme gxui.Theme, p *panel) *Push {
+	u := &Push{}
+	u.Theme = theme
+	u.panel = p
+	return u
+}
+
+func (u *Push) Name() string {
+	return "push"
+}
+
+func (u *Push) Menu() string {
+	return "File"
+}
+
+func (u *Push) Exec() error {
+	p, err := u.show()
+	if err != nil {
+		return err
+	}
+	p.Push()
+	u.Info = "Pushing"
+	return nil
+}
@@ -0,0 +1,230 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Git is the VCS for git repositories.  It is also a Stager and a
+// Brancher.
+type Git struct{}
+
+func (Git) Name() string {
+	return "git"
+}
+
+func (Git) Root(dir string, environ []string) (string, bool) {
+	out, err := git(dir, environ, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", false
+	}
+	return filepath.FromSlash(strings.TrimSpace(string(out))), true
+}
+
+func (Git) Status(dir string, environ []string) ([]Change, error) {
+	diff, err := git(dir, environ, "diff", "--relative", "--no-color", "--no-ext-diff", "-U0", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	changes := ParseDiff(bytes.NewReader(diff))
+	untracked, err := git(dir, environ, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range strings.Split(string(untracked), "\n") {
+		if p != "" {
+			changes = append(changes, Change{Path: p})
+		}
+	}
+	for i, c := range changes {
+		changes[i].Path = filepath.Join(dir, filepath.FromSlash(c.Path))
+	}
+	return changes, nil
+}
+
+func (g Git) Head(path string, environ []string) (string, error) {
+	return g.Revision(path, "HEAD", environ)
+}
+
+func (Git) Revision(path, commit string, environ []string) (string, error) {
+	out, err := git(filepath.Dir(path), environ, "show", commit+":./"+filepath.Base(path))
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func (Git) Blame(path, contents string, environ []string) (lines []BlameLine, messages map[string]string, err error) {
+	dir := filepath.Dir(path)
+	out, err := gitInput(dir, environ, strings.NewReader(contents), "blame", "--line-porcelain", "--contents", "-", "--", filepath.Base(path))
+	if err != nil {
+		return nil, nil, err
+	}
+	lines = ParseBlame(bytes.NewReader(out))
+	messages, err = commitMessages(dir, environ, lines)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lines, messages, nil
+}
+
+func (Git) Diff(dir, commit string, environ []string) ([]byte, error) {
+	return git(dir, environ, "show", "--no-color", "--no-ext-diff", commit)
+}
+
+func (Git) Log(path string, limit int, environ []string) ([]Commit, error) {
+	out, err := git(filepath.Dir(path), environ, "log", "-n", strconv.Itoa(limit), "--format=%H%x00%an%x00%at%x00%s", "--", filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+	return ParseLog(bytes.NewReader(out)), nil
+}
+
+func (Git) Changes(root string, environ []string) (staged, unstaged []FileDiff, err error) {
+	out, err := git(root, environ, "diff", "--cached", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return nil, nil, err
+	}
+	staged = ParseFileDiffs(bytes.NewReader(out))
+	out, err = git(root, environ, "diff", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return nil, nil, err
+	}
+	unstaged = ParseFileDiffs(bytes.NewReader(out))
+	untracked, err := git(root, environ, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range strings.Split(string(untracked), "\n") {
+		if p != "" {
+			unstaged = append(unstaged, FileDiff{Path: p, Untracked: true})
+		}
+	}
+	return staged, unstaged, nil
+}
+
+func (Git) Stage(root, path string, environ []string) error {
+	_, err := git(root, environ, "add", "--", path)
+	return err
+}
+
+func (Git) Unstage(root, path string, environ []string) error {
+	_, err := git(root, environ, "reset", "-q", "--", path)
+	return err
+}
+
+func (Git) StageHunk(root string, f FileDiff, h Hunk, environ []string) error {
+	_, err := gitInput(root, environ, strings.NewReader(f.Patch(h)), "apply", "--cached", "-")
+	return err
+}
+
+func (Git) UnstageHunk(root string, f FileDiff, h Hunk, environ []string) error {
+	_, err := gitInput(root, environ, strings.NewReader(f.Patch(h)), "apply", "--cached", "--reverse", "-")
+	return err
+}
+
+func (Git) Commit(root, message string, amend bool, environ []string) error {
+	args := []string{"commit", "-q", "-F", "-"}
+	if amend {
+		args = append(args, "--amend")
+	}
+	_, err := gitInput(root, environ, strings.NewReader(message), args...)
+	return err
+}
+
+func (Git) LastMessage(root string, environ []string) (string, error) {
+	out, err := git(root, environ, "log", "-1", "--format=%B")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (Git) Push(root string, environ []string) error {
+	_, err := git(root, environ, "push", "-q")
+	return err
+}
+
+func (Git) Branch(root string, environ []string) (BranchStatus, error) {
+	out, err := git(root, environ, "status", "--porcelain=v2", "--branch", "--untracked-files=no")
+	if err != nil {
+		return BranchStatus{}, err
+	}
+	return ParseBranchStatus(bytes.NewReader(out)), nil
+}
+
+func (Git) Branches(root string, environ []string) ([]string, error) {
+	out, err := git(root, environ, "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	var branches []string
+	for _, b := range strings.Split(string(out), "\n") {
+		if b != "" {
+			branches = append(branches, b)
+		}
+	}
+	return branches, nil
+}
+
+func (Git) Checkout(root, branch string, environ []string) error {
+	_, err := git(root, environ, "checkout", "-q", branch, "--")
+	return err
+}
+
+func (Git) Stash(root string, environ []string) error {
+	_, err := git(root, environ, "stash", "push", "-q", "--include-untracked")
+	return err
+}
+
+// ParseLog parses the output of git log with the format
+// "%H%x00%an%x00%at%x00%s", returning one Commit per line.
+func ParseLog(r io.Reader) []Commit {
+	var commits []Commit
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		c := Commit{ID: fields[0], Author: fields[1], Summary: fields[3]}
+		if secs, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			c.Time = time.Unix(secs, 0)
+		}
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+func git(dir string, environ []string, args ...string) ([]byte, error) {
+	return gitInput(dir, environ, nil, args...)
+}
+
+// gitInput runs git with args, passing stdin as its standard input.
+func gitInput(dir string, environ []string, stdin io.Reader, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-c", "core.quotePath=false"}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = environ
+	cmd.Stdin = stdin
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %s", args[0], err)
+	}
+	return out, nil
+}
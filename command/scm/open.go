@@ -0,0 +1,100 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// OpenModified is a command which opens every file in the current
+// project that has changed since the last commit.  If the
+// jumptofirsthunk setting is on, each file is opened at its first
+// change.
+type OpenModified struct {
+	status.General
+
+	projecter Projecter
+	focuser   Focuser
+	execer    Executor
+}
+
+func NewOpenModified(theme gxui.Theme) *OpenModified {
+	o := &OpenModified{}
+	o.Theme = theme
+	return o
+}
+
+func (o *OpenModified) Name() string {
+	return "open-modified-files"
+}
+
+func (o *OpenModified) Menu() string {
+	return "File"
+}
+
+func (o *OpenModified) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyM,
+	}}
+}
+
+func (o *OpenModified) Reset() {
+	o.projecter = nil
+	o.focuser = nil
+	o.execer = nil
+}
+
+func (o *OpenModified) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Projecter:
+		o.projecter = src
+	case Focuser:
+		o.focuser = src
+	case Executor:
+		o.execer = src
+	}
+	if o.projecter == nil || o.focuser == nil || o.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (o *OpenModified) Exec() error {
+	proj := o.projecter.Project()
+	changes, err := Modified(proj.Path, proj.Environ())
+	if err != nil {
+		o.Err = fmt.Sprintf("Could not list modified files: %s", err)
+		return err
+	}
+	if len(changes) == 0 {
+		o.Info = fmt.Sprintf("No modified files in %s", proj.Name)
+		return nil
+	}
+	jump := setting.JumpToFirstHunk()
+	open := func(c Change) {
+		opts := []focus.Opt{focus.Path(c.Path)}
+		if jump {
+			opts = append(opts, focus.Line(c.Line))
+		}
+		o.execer.Execute(o.focuser.For(opts...))
+	}
+	for _, c := range changes {
+		open(c)
+	}
+	if len(changes) > 1 {
+		// Opening each file focuses it, so go back to the first
+		// one once they're all open.
+		open(changes[0])
+	}
+	o.Info = fmt.Sprintf("Opened %d modified files", len(changes))
+	return nil
+}
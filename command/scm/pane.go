@@ -0,0 +1,399 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+var (
+	stagedColor = gxui.Color{R: 0.15, G: 0.5, B: 0.15, A: 1}
+	hunkColor   = gxui.Color{R: 0.4, G: 0.6, B: 1, A: 1}
+	guideColor  = gxui.Color{R: 0.5, G: 0.5, B: 0.5, A: 0.3}
+)
+
+// errEmptyMessage is returned when a commit is requested without a
+// message.
+var errEmptyMessage = errors.New("the commit message is empty")
+
+// A Paneler is a type that can display panels below the editor.
+type Paneler interface {
+	ShowPanel(gxui.Control)
+	HidePanel(gxui.Control)
+	HasPanel(gxui.Control) bool
+}
+
+// Pane is a panel that lists the changes in a repository, split into
+// staged changes and changes that haven't been staged.  Files and
+// single hunks can be staged and unstaged with the buttons next to
+// them, and the staged changes can be committed with the message
+// typed into the pane's message editor.
+type Pane struct {
+	mixins.LinearLayout
+
+	driver gxui.Driver
+	theme  *basic.Theme
+
+	status    gxui.Label
+	list      gxui.LinearLayout
+	templates gxui.LinearLayout
+	message   *messageEditor
+
+	paneler Paneler
+	stager  Stager
+	root    string
+	environ []string
+	open    func(path string, line int)
+}
+
+// NewPane creates an empty *Pane.
+func NewPane(driver gxui.Driver, theme *basic.Theme) *Pane {
+	p := &Pane{
+		driver:    driver,
+		theme:     theme,
+		status:    theme.CreateLabel(),
+		list:      theme.CreateLinearLayout(),
+		templates: theme.CreateLinearLayout(),
+	}
+	p.Init(p, theme)
+	p.SetDirection(gxui.TopToBottom)
+
+	header := theme.CreateLinearLayout()
+	header.SetDirection(gxui.LeftToRight)
+	header.AddChild(p.button("Close", func() {
+		if p.paneler != nil {
+			p.paneler.HidePanel(p)
+		}
+	}))
+	header.AddChild(p.button("Refresh", p.Refresh))
+	header.AddChild(p.button("Commit", func() { p.report(p.Commit(false)) }))
+	header.AddChild(p.button("Amend", func() { p.report(p.Commit(true)) }))
+	header.AddChild(p.button("Push", p.Push))
+	p.status.SetMargin(math.Spacing{L: 10, T: 2, R: 10, B: 2})
+	header.AddChild(p.status)
+	p.AddChild(header)
+
+	changes := theme.CreateScrollLayout()
+	changes.SetScrollAxis(false, true)
+	p.list.SetDirection(gxui.TopToBottom)
+	changes.SetChild(p.list)
+
+	commit := theme.CreateLinearLayout()
+	commit.SetDirection(gxui.TopToBottom)
+	p.templates.SetDirection(gxui.LeftToRight)
+	commit.AddChild(p.templates)
+	p.message = newMessageEditor(driver, theme)
+	commit.AddChild(p.message)
+
+	panes := theme.CreateSplitterLayout()
+	panes.SetOrientation(gxui.Horizontal)
+	panes.AddChild(changes)
+	panes.AddChild(commit)
+	p.AddChild(panes)
+	return p
+}
+
+func (p *Pane) button(text string, onClick func()) gxui.Button {
+	b := p.theme.CreateButton()
+	b.SetText(text)
+	b.SetMargin(math.Spacing{L: 2, R: 2})
+	b.OnClick(func(gxui.MouseEvent) { onClick() })
+	return b
+}
+
+// Show displays p using paneler, listing the changes in the
+// repository at root.  Clicking a change calls open with the path to
+// its file and the line it starts at.  It must be called on the UI
+// goroutine.
+func (p *Pane) Show(paneler Paneler, s Stager, root string, proj setting.Project, open func(path string, line int)) {
+	p.paneler = paneler
+	p.stager = s
+	p.root = root
+	p.environ = proj.Environ()
+	p.open = open
+
+	commit := proj.CommitConfig()
+	p.message.guides = commit.Guides
+	p.templates.RemoveAll()
+	names := make([]string, 0, len(commit.Templates))
+	for name := range commit.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tmpl := commit.Templates[name]
+		p.templates.AddChild(p.button(name, func() {
+			p.message.SetText(tmpl)
+			gxui.SetFocus(p.message)
+		}))
+	}
+
+	p.Refresh()
+	if !paneler.HasPanel(p) {
+		paneler.ShowPanel(p)
+	}
+}
+
+// Shown returns whether or not p is displayed.
+func (p *Pane) Shown() bool {
+	return p.paneler != nil && p.paneler.HasPanel(p)
+}
+
+// Hide hides p, if it is displayed.
+func (p *Pane) Hide() {
+	if p.Shown() {
+		p.paneler.HidePanel(p)
+	}
+}
+
+// Message returns the commit message that has been typed into p.
+func (p *Pane) Message() string {
+	return p.message.Text()
+}
+
+// SetMessage replaces the commit message in p.
+func (p *Pane) SetMessage(msg string) {
+	p.message.SetText(msg)
+}
+
+// FocusMessage focuses p's commit message editor.
+func (p *Pane) FocusMessage() {
+	gxui.SetFocus(p.message)
+}
+
+// Refresh reloads the list of changes in the background.
+func (p *Pane) Refresh() {
+	s, root, environ := p.stager, p.root, p.environ
+	if s == nil {
+		return
+	}
+	p.status.SetText("Loading changes...")
+	go func() {
+		done := status.StartTask(s.Name() + " status")
+		staged, unstaged, err := s.Changes(root, environ)
+		done()
+		p.driver.Call(func() {
+			if p.root != root {
+				// p has been shown for another repository since.
+				return
+			}
+			if err != nil {
+				p.status.SetText(fmt.Sprintf("Could not load changes: %s", err))
+				return
+			}
+			p.render(staged, unstaged)
+		})
+	}()
+}
+
+func (p *Pane) render(staged, unstaged []FileDiff) {
+	p.list.RemoveAll()
+	p.section(fmt.Sprintf("Staged (%d)", len(staged)), staged, true)
+	p.section(fmt.Sprintf("Changes (%d)", len(unstaged)), unstaged, false)
+	p.status.SetText(fmt.Sprintf("%s: %d staged, %d changed", filepath.Base(p.root), len(staged), len(unstaged)))
+}
+
+func (p *Pane) section(title string, files []FileDiff, staged bool) {
+	l := p.theme.CreateLabel()
+	l.SetText(title)
+	l.SetMargin(math.Spacing{T: 4, B: 2})
+	p.list.AddChild(l)
+	for _, f := range files {
+		p.list.AddChild(p.fileRow(f, staged))
+		for _, h := range f.Hunks {
+			p.list.AddChild(p.hunkRow(f, h, staged))
+		}
+	}
+}
+
+// fileRow returns a row that shows f's path, with a button that
+// stages or unstages the whole file.
+func (p *Pane) fileRow(f FileDiff, staged bool) gxui.Control {
+	s, root, environ := p.stager, p.root, p.environ
+	var b gxui.Button
+	if staged {
+		b = p.button("-", func() {
+			p.run("Unstaged "+f.Path, func() error { return s.Unstage(root, f.Path, environ) })
+		})
+	} else {
+		b = p.button("+", func() {
+			p.run("Staged "+f.Path, func() error { return s.Stage(root, f.Path, environ) })
+		})
+	}
+	name := f.Path
+	if f.Untracked {
+		name += " (untracked)"
+	}
+	l := p.theme.CreateLabel()
+	l.SetText(name)
+	if staged {
+		l.SetColor(stagedColor)
+	}
+	l.OnClick(func(gxui.MouseEvent) { p.open(filepath.Join(root, f.Path), 0) })
+	return p.row(b, l, 0)
+}
+
+// hunkRow returns a row that shows h's header, with a button that
+// stages or unstages only h.
+func (p *Pane) hunkRow(f FileDiff, h Hunk, staged bool) gxui.Control {
+	s, root, environ := p.stager, p.root, p.environ
+	var b gxui.Button
+	if staged {
+		b = p.button("-", func() {
+			p.run("Unstaged a hunk of "+f.Path, func() error { return s.UnstageHunk(root, f, h, environ) })
+		})
+	} else {
+		b = p.button("+", func() {
+			p.run("Staged a hunk of "+f.Path, func() error { return s.StageHunk(root, f, h, environ) })
+		})
+	}
+	l := p.theme.CreateLabel()
+	l.SetText(h.Header)
+	l.SetColor(hunkColor)
+	l.OnClick(func(gxui.MouseEvent) { p.open(filepath.Join(root, f.Path), h.Line) })
+	return p.row(b, l, 20)
+}
+
+func (p *Pane) row(b gxui.Button, l gxui.Label, indent int) gxui.Control {
+	row := p.theme.CreateLinearLayout()
+	row.SetDirection(gxui.LeftToRight)
+	row.SetMargin(math.Spacing{L: indent})
+	row.AddChild(b)
+	l.SetMargin(math.Spacing{L: 4, T: 2})
+	row.AddChild(l)
+	return row
+}
+
+// run runs op in the background, then shows done in p's status and
+// reloads the list of changes.
+func (p *Pane) run(done string, op func() error) {
+	name := p.stager.Name()
+	p.status.SetText(done + "...")
+	go func() {
+		finished := status.StartTask(name)
+		err := op()
+		finished()
+		p.driver.Call(func() {
+			p.Refresh()
+			if err != nil {
+				log.Printf("%s failed: %s", name, err)
+				p.status.SetText(fmt.Sprintf("%s: %s", name, err))
+				return
+			}
+			p.status.SetText(done)
+		})
+	}()
+}
+
+// Commit commits the staged changes with p's message in the
+// background, clearing the message if it succeeds.  If amend is true,
+// the last commit is replaced.  An error is returned if the message
+// is empty.
+func (p *Pane) Commit(amend bool) error {
+	msg := p.message.Text()
+	if strings.TrimSpace(msg) == "" {
+		return errEmptyMessage
+	}
+	s, root, environ := p.stager, p.root, p.environ
+	done := "Committed"
+	if amend {
+		done = "Amended the last commit"
+	}
+	p.run(done, func() error {
+		if err := s.Commit(root, msg, amend, environ); err != nil {
+			return err
+		}
+		p.driver.Call(func() {
+			if p.message.Text() == msg {
+				p.message.SetText("")
+			}
+		})
+		return nil
+	})
+	return nil
+}
+
+// Push pushes the current branch in the background.
+func (p *Pane) Push() {
+	s, root, environ := p.stager, p.root, p.environ
+	p.run("Pushed", func() error { return s.Push(root, environ) })
+}
+
+// LastMessage returns the message of the last commit in p's
+// repository.
+func (p *Pane) LastMessage() (string, error) {
+	return p.stager.LastMessage(p.root, p.environ)
+}
+
+// report shows err in p's status, if it isn't nil.
+func (p *Pane) report(err error) {
+	if err != nil {
+		p.status.SetText(err.Error())
+	}
+}
+
+// Elements returns nil, since none of p's children are useful to
+// commands.
+func (p *Pane) Elements() []interface{} {
+	return nil
+}
+
+// messageEditor is the commit message editor in a Pane.  It draws
+// vertical guide lines at the configured columns, so that it's easy to
+// keep lines short.
+type messageEditor struct {
+	mixins.CodeEditor
+
+	font   gxui.Font
+	guides []int
+}
+
+func newMessageEditor(driver gxui.Driver, theme *basic.Theme) *messageEditor {
+	m := &messageEditor{font: theme.DefaultMonospaceFont()}
+	m.CodeEditor.Init(m, driver, theme, m.font)
+	m.SetDesiredWidth(math.MaxSize.W)
+	m.SetTextColor(theme.TextBoxDefaultStyle.FontColor)
+	m.SetBackgroundBrush(theme.TextBoxDefaultStyle.Brush)
+	m.SetMargin(math.Spacing{L: 3, T: 3, R: 3, B: 3})
+	m.SetPadding(math.Spacing{L: 3, T: 3, R: 3, B: 3})
+	m.SetBorderPen(gxui.TransparentPen)
+	return m
+}
+
+func (m *messageEditor) Paint(c gxui.Canvas) {
+	m.CodeEditor.Paint(c)
+
+	width := m.font.GlyphMaxSize().W
+	size := m.Size()
+	brush := gxui.CreateBrush(guideColor)
+	for _, col := range m.guides {
+		x := m.Padding().L + col*width - m.HorizOffset()
+		if x < 0 || x >= size.W {
+			continue
+		}
+		c.DrawRect(math.CreateRect(x, 0, x+1, size.H), brush)
+	}
+}
+
+// paneOpener returns a function that focuses path at line using f and
+// e.
+func paneOpener(f Focuser, e Executor) func(path string, line int) {
+	return func(path string, line int) {
+		e.Execute(f.For(focus.Path(path), focus.Line(line)))
+	}
+}
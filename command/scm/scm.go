@@ -0,0 +1,168 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package scm contains commands that use a project's source control
+// to find files, annotate them, and commit changes to them.  Git is
+// supported out of the box, and other version control systems can be
+// added with Register.
+package scm
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/setting"
+)
+
+const (
+	diffPrefix    = "diff --git "
+	newFilePrefix = "+++ "
+	hunkPrefix    = "@@ "
+	devNull       = "/dev/null"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	b := make(blames)
+	p := &panel{driver: driver, theme: theme}
+	seg := NewBranchSegment()
+	return []bind.Bindable{
+		NewOpenModified(theme),
+		NewToggleBlame(driver, theme, b),
+		NewShowBlameCommit(theme, b),
+		NewShowSourceControl(theme, p),
+		NewCommitChanges(theme, p),
+		NewAmendCommit(theme, p),
+		NewPush(theme, p),
+		seg,
+		NewSwitchBranch(theme, seg),
+	}
+}
+
+// An Executor is a type that can execute bindables.
+type Executor interface {
+	Execute(bind.Bindable)
+}
+
+// A Focuser is a type that can create a bindable which focuses a
+// file.
+type Focuser interface {
+	For(...focus.Opt) bind.Bindable
+}
+
+// A Projecter is a type that knows the current project.
+type Projecter interface {
+	Project() setting.Project
+}
+
+// Change is a file that has changed since the last commit.
+type Change struct {
+	// Path is the path to the file, relative to the directory
+	// that the diff was run in.
+	Path string
+
+	// Line is the zero-indexed line of the first hunk in the
+	// file.
+	Line int
+}
+
+// Modified returns the files in dir which have been changed or added
+// since the last commit, according to the version control system that
+// tracks dir.  Untracked files which aren't ignored are included.  The
+// returned paths are absolute.  Deleted files are not included.
+func Modified(dir string, environ []string) ([]Change, error) {
+	v, _, err := For(dir, environ)
+	if err != nil {
+		return nil, err
+	}
+	return v.Status(dir, environ)
+}
+
+// Head returns the contents of the file at path as of the last
+// commit.
+func Head(path string, environ []string) (string, error) {
+	v, _, err := For(filepath.Dir(path), environ)
+	if err != nil {
+		return "", err
+	}
+	return v.Head(path, environ)
+}
+
+// Revision returns the contents of the file at path as of commit.
+func Revision(path, commit string, environ []string) (string, error) {
+	v, _, err := For(filepath.Dir(path), environ)
+	if err != nil {
+		return "", err
+	}
+	return v.Revision(path, commit, environ)
+}
+
+// History returns up to limit of the latest commits that changed the
+// file at path, newest first.
+func History(path string, limit int, environ []string) ([]Commit, error) {
+	v, _, err := For(filepath.Dir(path), environ)
+	if err != nil {
+		return nil, err
+	}
+	return v.Log(path, limit, environ)
+}
+
+// ParseDiff parses the output of git diff, returning each file that
+// still exists along with the line of its first hunk.  Paths are
+// returned as they are in the diff, without the "b/" prefix.
+func ParseDiff(r io.Reader) []Change {
+	var changes []Change
+	inHeader := false
+	current := -1
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		l := scanner.Text()
+		switch {
+		case strings.HasPrefix(l, diffPrefix):
+			inHeader = true
+			current = -1
+		case !inHeader:
+			continue
+		case strings.HasPrefix(l, newFilePrefix):
+			path := strings.TrimRight(strings.TrimPrefix(l, newFilePrefix), "\t")
+			if path == devNull {
+				continue
+			}
+			changes = append(changes, Change{Path: strings.TrimPrefix(path, "b/")})
+			current = len(changes) - 1
+		case strings.HasPrefix(l, hunkPrefix):
+			inHeader = false
+			if current >= 0 {
+				changes[current].Line = hunkLine(l)
+			}
+		}
+	}
+	return changes
+}
+
+// hunkLine returns the zero-indexed line in the new file that a hunk
+// header (e.g. "@@ -1,2 +3,4 @@") refers to.
+func hunkLine(header string) int {
+	for _, f := range strings.Fields(header) {
+		if !strings.HasPrefix(f, "+") {
+			continue
+		}
+		start := strings.SplitN(f[1:], ",", 2)[0]
+		line, err := strconv.Atoi(start)
+		if err != nil || line < 1 {
+			return 0
+		}
+		return line - 1
+	}
+	return 0
+}
@@ -0,0 +1,244 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/command/scm"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+type Expectation = expect.Expectation
+
+var (
+	Equal   = matchers.Equal
+	HaveLen = matchers.HaveLen
+)
+
+func TestParseDiff(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it returns nothing for an empty diff", func(expect Expectation) {
+		expect(scm.ParseDiff(strings.NewReader(""))).To(HaveLen(0))
+	})
+
+	o.Spec("it returns the first hunk of each file", func(expect Expectation) {
+		diff := `diff --git a/foo.go b/foo.go
+index 1234567..89abcde 100644
+--- a/foo.go
++++ b/foo.go
+@@ -3,0 +4,2 @@ package foo
++++ this looks like a header, but it's an added line
++func bar() {}
+@@ -20 +22 @@ func baz() {
+-	return
++	return nil
+diff --git a/sub/bar.go b/sub/bar.go
+new file mode 100644
+index 0000000..1234567
+--- /dev/null
++++ b/sub/bar.go
+@@ -0,0 +1,3 @@
++package sub
+`
+		expect(scm.ParseDiff(strings.NewReader(diff))).To(Equal([]scm.Change{
+			{Path: "foo.go", Line: 3},
+			{Path: "sub/bar.go", Line: 0},
+		}))
+	})
+
+	o.Spec("it skips deleted files", func(expect Expectation) {
+		diff := `diff --git a/gone.go b/gone.go
+deleted file mode 100644
+index 1234567..0000000
+--- a/gone.go
++++ /dev/null
+@@ -1,3 +0,0 @@
+-package gone
+`
+		expect(scm.ParseDiff(strings.NewReader(diff))).To(HaveLen(0))
+	})
+
+	o.Spec("it handles file names that contain spaces", func(expect Expectation) {
+		diff := "diff --git a/a b.go b/a b.go\n--- a/a b.go\t\n+++ b/a b.go\t\n@@ -1 +1 @@\n-x\n+y\n"
+		expect(scm.ParseDiff(strings.NewReader(diff))).To(Equal([]scm.Change{
+			{Path: "a b.go", Line: 0},
+		}))
+	})
+}
+
+func TestParseBlame(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it returns the commit for each line", func(expect Expectation) {
+		blame := "1234567890123456789012345678901234567890 1 1 2\n" +
+			"author Jane Doe\n" +
+			"author-mail <jane@example.com>\n" +
+			"author-time 1500000000\n" +
+			"author-tz +0000\n" +
+			"summary Add foo\n" +
+			"filename foo.go\n" +
+			"\tpackage foo\n" +
+			"0000000000000000000000000000000000000000 2 2\n" +
+			"author Not Committed Yet\n" +
+			"author-time 1600000000\n" +
+			"summary Version of foo.go from -\n" +
+			"filename foo.go\n" +
+			"\t// author is a comment\n"
+		lines := scm.ParseBlame(strings.NewReader(blame))
+		expect(lines).To(HaveLen(2))
+		expect(lines[0].Commit).To(Equal("1234567890123456789012345678901234567890"))
+		expect(lines[0].Author).To(Equal("Jane Doe"))
+		expect(lines[0].Time.Unix()).To(Equal(int64(1500000000)))
+		expect(lines[0].Summary).To(Equal("Add foo"))
+		expect(lines[0].Committed()).To(Equal(true))
+		expect(lines[1].Author).To(Equal("Not Committed Yet"))
+		expect(lines[1].Committed()).To(Equal(false))
+	})
+}
+
+func TestParseLog(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it returns each commit", func(expect Expectation) {
+		log := "1234567890123456789012345678901234567890\x00Jane Doe\x001500000000\x00Add foo\n" +
+			"abcdefabcdefabcdefabcdefabcdefabcdefabcd\x00John Doe\x001400000000\x00Fix: a\x00b\n"
+		commits := scm.ParseLog(strings.NewReader(log))
+		expect(commits).To(HaveLen(2))
+		expect(commits[0].ID).To(Equal("1234567890123456789012345678901234567890"))
+		expect(commits[0].Author).To(Equal("Jane Doe"))
+		expect(commits[0].Time.Unix()).To(Equal(int64(1500000000)))
+		expect(commits[1].Summary).To(Equal("Fix: a\x00b"))
+	})
+}
+
+// fakeVCS is a VCS whose repositories are the directories under root.
+type fakeVCS struct {
+	scm.Git
+	root string
+}
+
+func (f fakeVCS) Name() string {
+	return "fake"
+}
+
+func (f fakeVCS) Root(dir string, _ []string) (string, bool) {
+	if !strings.HasPrefix(dir, f.root) {
+		return "", false
+	}
+	return f.root, true
+}
+
+func TestFor(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it returns the innermost repository", func(expect Expectation) {
+		scm.Register(fakeVCS{root: "/vidar-fake-outer"})
+		scm.Register(fakeVCS{root: "/vidar-fake-outer/inner"})
+		v, root, err := scm.For("/vidar-fake-outer/inner/pkg", nil)
+		expect(err).To(Equal(nil))
+		expect(v.Name()).To(Equal("fake"))
+		expect(root).To(Equal("/vidar-fake-outer/inner"))
+
+		_, root, err = scm.For("/vidar-fake-outer/pkg", nil)
+		expect(err).To(Equal(nil))
+		expect(root).To(Equal("/vidar-fake-outer"))
+	})
+
+	o.Spec("it fails outside of repositories", func(expect Expectation) {
+		_, _, err := scm.For("/vidar-fake-nowhere", nil)
+		expect(err).To(Equal(scm.ErrNoRepository))
+	})
+}
+
+func TestParseFileDiffs(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it splits each file into hunks", func(expect Expectation) {
+		diff := "diff --git a/foo.go b/foo.go\n" +
+			"index 1234567..89abcde 100644\n" +
+			"--- a/foo.go\n" +
+			"+++ b/foo.go\n" +
+			"@@ -1,2 +1,3 @@ package foo\n" +
+			" package foo\n" +
+			"+\n" +
+			" func foo() {}\n" +
+			"@@ -10 +11 @@ func bar() {\n" +
+			"-\treturn\n" +
+			"+\treturn nil\n" +
+			"diff --git a/gone.go b/gone.go\n" +
+			"deleted file mode 100644\n" +
+			"--- a/gone.go\n" +
+			"+++ /dev/null\n" +
+			"@@ -1 +0,0 @@\n" +
+			"-package gone\n"
+		diffs := scm.ParseFileDiffs(strings.NewReader(diff))
+		expect(diffs).To(HaveLen(2))
+		expect(diffs[0].Path).To(Equal("foo.go"))
+		expect(diffs[0].Hunks).To(HaveLen(2))
+		expect(diffs[0].Hunks[1].Line).To(Equal(10))
+		expect(diffs[0].Patch(diffs[0].Hunks[1])).To(Equal("diff --git a/foo.go b/foo.go\n" +
+			"index 1234567..89abcde 100644\n" +
+			"--- a/foo.go\n" +
+			"+++ b/foo.go\n" +
+			"@@ -10 +11 @@ func bar() {\n" +
+			"-\treturn\n" +
+			"+\treturn nil\n"))
+		expect(diffs[1].Path).To(Equal("gone.go"))
+		expect(diffs[1].Hunks).To(HaveLen(1))
+	})
+}
+
+func TestParseBranchStatus(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it returns the branch and how far it is from its upstream", func(expect Expectation) {
+		out := "# branch.oid 0123456789abcdef\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +2 -1\n1 .M N... 100644 100644 100644 abc def foo.go\n"
+		b := scm.ParseBranchStatus(strings.NewReader(out))
+		expect(b).To(Equal(scm.BranchStatus{Name: "main", Upstream: "origin/main", Ahead: 2, Behind: 1}))
+		expect(b.String()).To(Equal("main, 2 ahead, 1 behind"))
+	})
+
+	o.Spec("it handles detached heads", func(expect Expectation) {
+		out := "# branch.oid 0123456789abcdef\n# branch.head (detached)\n"
+		b := scm.ParseBranchStatus(strings.NewReader(out))
+		expect(b).To(Equal(scm.BranchStatus{Name: "0123456", Detached: true}))
+		expect(b.String()).To(Equal("detached at 0123456"))
+	})
+}
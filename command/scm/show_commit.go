@@ -0,0 +1,177 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+const (
+	// commitDir is the directory, under os.TempDir(), that commit
+	// diffs are written to so that they can be opened in an editor.
+	commitDir = "vidar-commits"
+
+	// revisionDir is the directory, under os.TempDir(), that old
+	// revisions of files are written to.
+	revisionDir = "vidar-revisions"
+)
+
+// A LineEditor is an editor which can report the line that its caret
+// is on.
+type LineEditor interface {
+	Filepath() string
+	Carets() []int
+	LineIndex(caret int) int
+}
+
+// ShowBlameCommit is a command which opens the diff of the commit that
+// last changed the line the caret is on.  Blame has to be shown (see
+// ToggleBlame) for the current file first.
+type ShowBlameCommit struct {
+	status.General
+
+	blames blames
+
+	editor    LineEditor
+	projecter Projecter
+	focuser   Focuser
+	execer    Executor
+}
+
+func NewShowBlameCommit(theme gxui.Theme, b blames) *ShowBlameCommit {
+	s := &ShowBlameCommit{blames: b}
+	s.Theme = theme
+	return s
+}
+
+func (s *ShowBlameCommit) Name() string {
+	return "show-blame-commit"
+}
+
+func (s *ShowBlameCommit) Menu() string {
+	return "View"
+}
+
+func (s *ShowBlameCommit) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (s *ShowBlameCommit) Reset() {
+	s.Clear()
+	s.editor = nil
+	s.projecter = nil
+	s.focuser = nil
+	s.execer = nil
+}
+
+func (s *ShowBlameCommit) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case LineEditor:
+		s.editor = src
+	case Projecter:
+		s.projecter = src
+	case Focuser:
+		s.focuser = src
+	case Executor:
+		s.execer = src
+	}
+	if s.editor == nil || s.projecter == nil || s.focuser == nil || s.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (s *ShowBlameCommit) Exec() error {
+	path := s.editor.Filepath()
+	lines, ok := s.blames[path]
+	if !ok {
+		s.Warn = "Blame is not shown for this file; run toggle-blame first"
+		return nil
+	}
+	carets := s.editor.Carets()
+	line := s.editor.LineIndex(carets[len(carets)-1])
+	if line >= len(lines) {
+		s.Warn = "No blame for this line; run toggle-blame again"
+		return nil
+	}
+	commit := lines[line]
+	if !commit.Committed() {
+		s.Info = "This line has not been committed"
+		return nil
+	}
+	diffPath, err := writeCommit(filepath.Dir(path), s.projecter.Project().Environ(), commit.Commit)
+	if err != nil {
+		s.Err = fmt.Sprintf("Could not show commit %.8s: %s", commit.Commit, err)
+		return err
+	}
+	s.execer.Execute(s.focuser.For(focus.Path(diffPath)))
+	return nil
+}
+
+// writeCommit writes the diff of commit to a file and returns the
+// file's path.  The file is read-only, so it's opened in a read-only
+// editor.
+func writeCommit(dir string, environ []string, commit string) (string, error) {
+	path := filepath.Join(os.TempDir(), commitDir, commit+".diff")
+	if _, err := os.Stat(path); err == nil {
+		// Commits don't change, so an earlier diff is still
+		// correct.
+		return path, nil
+	}
+	v, _, err := For(dir, environ)
+	if err != nil {
+		return "", err
+	}
+	diff, err := v.Diff(dir, commit, environ)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(path, diff, 0444); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// WriteRevision writes the contents of the file at path as of commit
+// to a read-only temporary file and returns the temporary file's path,
+// so that the revision can be opened in a read-only editor.  The file
+// keeps path's name, so that it's highlighted the same way.
+func WriteRevision(path, commit string, environ []string) (string, error) {
+	v, root, err := For(filepath.Dir(path), environ)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return "", err
+	}
+	revPath := filepath.Join(os.TempDir(), revisionDir, commit, rel)
+	if _, err := os.Stat(revPath); err == nil {
+		// Like commits, revisions don't change.
+		return revPath, nil
+	}
+	text, err := v.Revision(path, commit, environ)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(revPath), 0755); err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(revPath, []byte(text), 0444); err != nil {
+		return "", err
+	}
+	return revPath, nil
+}
@@ -0,0 +1,136 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+const oldFilePrefix = "--- "
+
+// A Stager is a VCS with a staging area (e.g. git's index), so that
+// part of the changes in a repository can be committed.  The source
+// control pane only works in repositories whose VCS is a Stager.
+type Stager interface {
+	VCS
+
+	// Changes returns the files that have changed in the repository
+	// at root, split into the changes that are staged and the ones
+	// that aren't.  Untracked files are included in unstaged.
+	Changes(root string, environ []string) (staged, unstaged []FileDiff, err error)
+
+	// Stage stages every change to path, which is relative to
+	// root.  Untracked files are added.
+	Stage(root, path string, environ []string) error
+
+	// Unstage moves every staged change to path, which is relative
+	// to root, back out of the staging area.
+	Unstage(root, path string, environ []string) error
+
+	// StageHunk stages h, which must be one of the unstaged hunks
+	// in f.
+	StageHunk(root string, f FileDiff, h Hunk, environ []string) error
+
+	// UnstageHunk moves h, which must be one of the staged hunks in
+	// f, back out of the staging area.
+	UnstageHunk(root string, f FileDiff, h Hunk, environ []string) error
+
+	// Commit commits the staged changes with message.  If amend is
+	// true, the last commit is replaced instead.
+	Commit(root, message string, amend bool, environ []string) error
+
+	// LastMessage returns the message of the last commit.
+	LastMessage(root string, environ []string) (string, error)
+
+	// Push pushes the current branch to its upstream.
+	Push(root string, environ []string) error
+}
+
+// FileDiff is the diff of a single file.
+type FileDiff struct {
+	// Path is the path to the file, relative to the root of its
+	// repository and separated by slashes.
+	Path string
+
+	// Header is every line of the diff before the first hunk.
+	Header []string
+
+	Hunks []Hunk
+
+	// Untracked is true for files that aren't tracked yet.  They
+	// have no header or hunks.
+	Untracked bool
+}
+
+// Hunk is one of the hunks in a FileDiff.
+type Hunk struct {
+	// Header is the hunk's "@@" line.
+	Header string
+
+	// Lines are the lines after the header, with their "+", "-",
+	// or " " prefix.
+	Lines []string
+
+	// Line is the zero-indexed line in the new file that the hunk
+	// starts at.
+	Line int
+}
+
+// Patch returns a patch of f that only contains h.
+func (f FileDiff) Patch(h Hunk) string {
+	lines := append(append(append([]string(nil), f.Header...), h.Header), h.Lines...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// ParseFileDiffs parses the output of git diff, returning the diff of
+// each file in it.
+func ParseFileDiffs(r io.Reader) []FileDiff {
+	var (
+		diffs []FileDiff
+		hunk  *Hunk
+	)
+	file := func() *FileDiff { return &diffs[len(diffs)-1] }
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		l := scanner.Text()
+		switch {
+		case strings.HasPrefix(l, diffPrefix):
+			diffs = append(diffs, FileDiff{Header: []string{l}})
+			hunk = nil
+		case len(diffs) == 0:
+			continue
+		case strings.HasPrefix(l, hunkPrefix):
+			f := file()
+			f.Hunks = append(f.Hunks, Hunk{Header: l, Line: hunkLine(l)})
+			hunk = &f.Hunks[len(f.Hunks)-1]
+		case hunk != nil:
+			hunk.Lines = append(hunk.Lines, l)
+		default:
+			f := file()
+			f.Header = append(f.Header, l)
+			switch {
+			case strings.HasPrefix(l, newFilePrefix):
+				if path := diffPath(l, newFilePrefix, "b/"); path != devNull {
+					f.Path = path
+				}
+			case strings.HasPrefix(l, oldFilePrefix) && f.Path == "":
+				// Deleted files only have their old path.
+				if path := diffPath(l, oldFilePrefix, "a/"); path != devNull {
+					f.Path = path
+				}
+			}
+		}
+	}
+	return diffs
+}
+
+// diffPath returns the path from a "---" or "+++" line of a diff.
+func diffPath(line, prefix, side string) string {
+	path := strings.TrimRight(strings.TrimPrefix(line, prefix), "\t")
+	return strings.TrimPrefix(path, side)
+}
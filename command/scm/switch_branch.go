@@ -0,0 +1,293 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/fs"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/scoring"
+)
+
+var branchMatchColor = gxui.Color{R: 0.4, G: 0.8, B: 0.3, A: 1}
+
+// An Elementer is a type that has child elements.
+type Elementer interface {
+	Elements() []interface{}
+}
+
+// Editors is a type that knows about every open editor and can save
+// them.
+type Editors interface {
+	OpenEditors() []input.Editor
+	SaveAll() []error
+}
+
+// A Changer is an editor which tracks unsaved changes.
+type Changer interface {
+	HasChanges() bool
+}
+
+// A DiskReloader is an editor that can load its file again if it was
+// changed by something else.
+type DiskReloader interface {
+	ReloadIfChanged()
+}
+
+// SwitchBranch is a command which checks out one of the branches of
+// the current project's repository.  Typing filters the branches, and
+// the first match is checked out.  If open files in the repository
+// have unsaved changes, it asks whether to save them first, to save
+// them and stash every uncommitted change, or to cancel.
+type SwitchBranch struct {
+	status.General
+
+	theme   *basic.Theme
+	segment *BranchSegment
+
+	display gxui.LinearLayout
+	filter  gxui.TextBox
+	answer  gxui.TextBox
+	inputs  int
+
+	startErr error
+	brancher Brancher
+	root     string
+	environ  []string
+	branches []string
+	choice   string
+	dirty    []string
+
+	editors Editors
+}
+
+func NewSwitchBranch(theme *basic.Theme, segment *BranchSegment) *SwitchBranch {
+	s := &SwitchBranch{
+		theme:   theme,
+		segment: segment,
+		display: theme.CreateLinearLayout(),
+		filter:  theme.CreateTextBox(),
+		answer:  theme.CreateTextBox(),
+	}
+	s.Theme = theme
+	s.display.SetDirection(gxui.LeftToRight)
+	s.filter.SetDesiredWidth(math.MaxSize.W)
+	s.filter.OnTextChanged(func([]gxui.TextBoxEdit) {
+		s.update()
+	})
+	s.answer.SetDesiredWidth(math.MaxSize.W)
+	return s
+}
+
+func (s *SwitchBranch) Name() string {
+	return "switch-branch"
+}
+
+func (s *SwitchBranch) Menu() string {
+	return "File"
+}
+
+func (s *SwitchBranch) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (s *SwitchBranch) Start(control gxui.Control) gxui.Control {
+	s.inputs = 0
+	s.startErr = nil
+	s.brancher = nil
+	s.branches = nil
+	s.dirty = nil
+
+	proj := fs.CurrentProject(control)
+	s.environ = proj.Environ()
+	s.startErr = s.load(proj.Path)
+	if s.startErr == nil {
+		s.dirty = dirtyFiles(findEditors(control), s.root)
+	}
+	s.filter.SetText("")
+	s.update()
+	return s.display
+}
+
+// load loads the branches of the repository at dir, leaving out the
+// one that is checked out.
+func (s *SwitchBranch) load(dir string) error {
+	v, root, err := For(dir, s.environ)
+	if err != nil {
+		return err
+	}
+	b, ok := v.(Brancher)
+	if !ok {
+		return fmt.Errorf("%s has no branches", v.Name())
+	}
+	current, err := b.Branch(root, s.environ)
+	if err != nil {
+		return err
+	}
+	branches, err := b.Branches(root, s.environ)
+	if err != nil {
+		return err
+	}
+	for _, name := range branches {
+		if current.Detached || name != current.Name {
+			s.branches = append(s.branches, name)
+		}
+	}
+	s.brancher, s.root = b, root
+	return nil
+}
+
+func (s *SwitchBranch) Next() gxui.Focusable {
+	if s.brancher == nil || len(s.branches) == 0 {
+		return nil
+	}
+	s.inputs++
+	switch s.inputs {
+	case 1:
+		return s.filter
+	case 2:
+		if len(s.dirty) == 0 || s.choice == "" {
+			return nil
+		}
+		s.display.RemoveAll()
+		l := s.theme.CreateLabel()
+		l.SetText(fmt.Sprintf("Unsaved changes in %s.  Save them (s), save them and stash all changes (t), or cancel (c)?",
+			strings.Join(s.dirty, ", ")))
+		s.display.AddChild(l)
+		s.answer.SetText("s")
+		return s.answer
+	}
+	return nil
+}
+
+// update displays the branches that match the current filter, in
+// order of how well they match.
+func (s *SwitchBranch) update() {
+	names := append([]string(nil), s.branches...)
+	if partial := s.filter.Text(); partial != "" {
+		names = scoring.Sort(names, partial)
+	}
+	s.choice = ""
+	s.display.RemoveAll()
+	for i, n := range names {
+		l := s.theme.CreateLabel()
+		l.SetMargin(math.Spacing{L: 5, R: 5})
+		l.SetText(n)
+		if i == 0 {
+			s.choice = n
+			l.SetColor(branchMatchColor)
+		}
+		s.display.AddChild(l)
+	}
+}
+
+func (s *SwitchBranch) Reset() {
+	s.Clear()
+	s.editors = nil
+}
+
+func (s *SwitchBranch) Store(elem interface{}) bind.Status {
+	editors, ok := elem.(Editors)
+	if !ok {
+		return bind.Waiting
+	}
+	s.editors = editors
+	return bind.Done
+}
+
+func (s *SwitchBranch) Exec() error {
+	if s.startErr != nil {
+		s.Err = fmt.Sprintf("Could not list branches: %s", s.startErr)
+		return s.startErr
+	}
+	if len(s.branches) == 0 {
+		s.Warn = "There are no other branches to switch to"
+		return nil
+	}
+	if s.choice == "" {
+		s.Err = "no branches match"
+		return fmt.Errorf("switch-branch: %s", s.Err)
+	}
+	if len(s.dirty) > 0 {
+		answer := strings.ToLower(strings.TrimSpace(s.answer.Text()))
+		if !strings.HasPrefix(answer, "s") && !strings.HasPrefix(answer, "t") {
+			s.Info = fmt.Sprintf("Stayed on the current branch, with unsaved changes in %s", strings.Join(s.dirty, ", "))
+			return nil
+		}
+		if errs := s.editors.SaveAll(); len(errs) > 0 {
+			s.Err = fmt.Sprintf("Not switching branches; could not save %d files: %s", len(errs), errs[0])
+			return errs[0]
+		}
+		if strings.HasPrefix(answer, "t") {
+			if err := s.brancher.Stash(s.root, s.environ); err != nil {
+				s.Err = fmt.Sprintf("Not switching branches; could not stash changes: %s", err)
+				return err
+			}
+		}
+	}
+	if err := s.brancher.Checkout(s.root, s.choice, s.environ); err != nil {
+		s.Err = fmt.Sprintf("Could not switch to %s: %s", s.choice, err)
+		return err
+	}
+	s.reload()
+	s.segment.Expire()
+	s.Info = fmt.Sprintf("Switched to %s", s.choice)
+	return nil
+}
+
+// reload loads the open files in the repository again, in case their
+// watchers miss the checkout.
+func (s *SwitchBranch) reload() {
+	prefix := s.root + string(filepath.Separator)
+	for _, e := range s.editors.OpenEditors() {
+		r, ok := e.(DiskReloader)
+		if !ok || !strings.HasPrefix(e.Filepath(), prefix) {
+			continue
+		}
+		// Reading files shouldn't block the UI.
+		go r.ReloadIfChanged()
+	}
+}
+
+// findEditors returns the first Editors in e or its elements, or nil
+// if there isn't one.
+func findEditors(e interface{}) Editors {
+	switch src := e.(type) {
+	case Editors:
+		return src
+	case Elementer:
+		for _, elem := range src.Elements() {
+			if editors := findEditors(elem); editors != nil {
+				return editors
+			}
+		}
+	}
+	return nil
+}
+
+// dirtyFiles returns the names of the files in root that are open in
+// editors and have unsaved changes.
+func dirtyFiles(editors Editors, root string) []string {
+	if editors == nil {
+		return nil
+	}
+	prefix := root + string(filepath.Separator)
+	var dirty []string
+	for _, e := range editors.OpenEditors() {
+		c, ok := e.(Changer)
+		if ok && c.HasChanges() && strings.HasPrefix(e.Filepath(), prefix) {
+			dirty = append(dirty, filepath.Base(e.Filepath()))
+		}
+	}
+	return dirty
+}
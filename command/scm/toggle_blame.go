@@ -0,0 +1,111 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// An Annotator is an editor which can display annotations next to
+// its lines.
+type Annotator interface {
+	Filepath() string
+	Text() string
+	Annotations() []input.Annotation
+	SetAnnotations([]input.Annotation)
+}
+
+// ToggleBlame is a command which shows the commit that last changed
+// each line of the current file in the editor's gutter, or hides the
+// blame if it's already shown.  Blame runs in the background, so large
+// files don't block the UI.
+type ToggleBlame struct {
+	status.General
+
+	driver gxui.Driver
+	blames blames
+
+	editor    Annotator
+	projecter Projecter
+}
+
+func NewToggleBlame(driver gxui.Driver, theme gxui.Theme, b blames) *ToggleBlame {
+	t := &ToggleBlame{driver: driver, blames: b}
+	t.Theme = theme
+	return t
+}
+
+func (t *ToggleBlame) Name() string {
+	return "toggle-blame"
+}
+
+func (t *ToggleBlame) Menu() string {
+	return "View"
+}
+
+func (t *ToggleBlame) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (t *ToggleBlame) Reset() {
+	t.Clear()
+	t.editor = nil
+	t.projecter = nil
+}
+
+func (t *ToggleBlame) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Annotator:
+		t.editor = src
+	case Projecter:
+		t.projecter = src
+	}
+	if t.editor == nil || t.projecter == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (t *ToggleBlame) Exec() error {
+	e := t.editor
+	path := e.Filepath()
+	if e.Annotations() != nil {
+		e.SetAnnotations(nil)
+		delete(t.blames, path)
+		t.Info = "Blame hidden"
+		return nil
+	}
+	text := e.Text()
+	environ := t.projecter.Project().Environ()
+	v, _, err := For(filepath.Dir(path), environ)
+	if err != nil {
+		t.Err = fmt.Sprintf("Could not blame %s: %s", filepath.Base(path), err)
+		return err
+	}
+	go func() {
+		done := status.StartTask(v.Name() + " blame")
+		lines, messages, err := v.Blame(path, text, environ)
+		done()
+		if err != nil {
+			log.Printf("Could not blame %s: %s", path, err)
+			return
+		}
+		notes := blameAnnotations(lines, messages, time.Now())
+		t.driver.Call(func() {
+			t.blames[path] = lines
+			e.SetAnnotations(notes)
+		})
+	}()
+	t.Info = fmt.Sprintf("Running %s blame on %s", v.Name(), filepath.Base(path))
+	return nil
+}
@@ -0,0 +1,101 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scm
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoRepository is returned when a path isn't tracked by any of the
+// registered version control systems.
+var ErrNoRepository = errors.New("not in a repository of any known version control system")
+
+// A VCS is a version control system.  Git is always available, and
+// plugins can add others (e.g. hg or svn) with Register.  Every method
+// is passed the environment of the project that the paths are in.
+type VCS interface {
+	// Name returns the name of the system, e.g. "git".
+	Name() string
+
+	// Root returns the root of the repository that contains dir,
+	// or false if dir isn't in a repository.
+	Root(dir string, environ []string) (root string, ok bool)
+
+	// Status returns the files in dir which have been changed or
+	// added since the last commit, including untracked files which
+	// aren't ignored.  Paths are absolute, and deleted files are
+	// not included.
+	Status(dir string, environ []string) ([]Change, error)
+
+	// Head returns the contents of the file at path as of the
+	// last commit.
+	Head(path string, environ []string) (string, error)
+
+	// Revision returns the contents of the file at path as of
+	// commit.
+	Revision(path, commit string, environ []string) (string, error)
+
+	// Blame returns the commit that last changed each line of the
+	// file at path, using contents as the file's current contents.
+	// Lines that haven't been committed should have an empty
+	// Commit.  The returned messages map each commit to its full
+	// message.
+	Blame(path, contents string, environ []string) (lines []BlameLine, messages map[string]string, err error)
+
+	// Diff returns a description of commit, followed by the diff
+	// of the changes that it made.
+	Diff(dir, commit string, environ []string) ([]byte, error)
+
+	// Log returns up to limit of the latest commits that changed
+	// path, newest first.
+	Log(path string, limit int, environ []string) ([]Commit, error)
+}
+
+// Commit is a commit in a repository's history.
+type Commit struct {
+	ID      string
+	Author  string
+	Time    time.Time
+	Summary string
+}
+
+var (
+	systemsMu sync.RWMutex
+	systems   = []VCS{Git{}}
+)
+
+// Register adds v to the version control systems that paths are
+// checked against.  Systems that are registered later take precedence
+// when two of them have the same root.
+func Register(v VCS) {
+	systemsMu.Lock()
+	defer systemsMu.Unlock()
+	systems = append([]VCS{v}, systems...)
+}
+
+// For returns the version control system that tracks dir, along with
+// the root of its repository.  If dir is in more than one repository
+// (e.g. a git checkout inside of an hg repository), the innermost one
+// is returned.
+func For(dir string, environ []string) (VCS, string, error) {
+	systemsMu.RLock()
+	defer systemsMu.RUnlock()
+	var (
+		found VCS
+		root  string
+	)
+	for _, v := range systems {
+		r, ok := v.Root(dir, environ)
+		if ok && len(r) > len(root) {
+			found, root = v, r
+		}
+	}
+	if found == nil {
+		return nil, "", ErrNoRepository
+	}
+	return found, root, nil
+}
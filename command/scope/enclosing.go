@@ -0,0 +1,75 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scope
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// SelectEnclosing is a command which selects the contents of the
+// scope around the selection.  If the contents are already selected,
+// the brackets are selected too, and running it again moves out to
+// the next scope.
+type SelectEnclosing struct {
+	status.General
+
+	editor Editor
+}
+
+func NewSelectEnclosing(theme gxui.Theme) *SelectEnclosing {
+	s := &SelectEnclosing{}
+	s.Theme = theme
+	return s
+}
+
+func (s *SelectEnclosing) Name() string {
+	return "select-enclosing-scope"
+}
+
+func (s *SelectEnclosing) Menu() string {
+	return "Edit"
+}
+
+func (s *SelectEnclosing) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModShift,
+		Key:      gxui.KeyRightBracket,
+	}}
+}
+
+func (s *SelectEnclosing) Reset() {
+	s.Clear()
+	s.editor = nil
+}
+
+func (s *SelectEnclosing) Store(elem interface{}) bind.Status {
+	editor, ok := elem.(Editor)
+	if !ok {
+		return bind.Waiting
+	}
+	s.editor = editor
+	return bind.Done
+}
+
+func (s *SelectEnclosing) Exec() error {
+	selections := s.editor.Controller().SelectionSlice()
+	if len(selections) == 0 {
+		return nil
+	}
+	sel := selections[0]
+	pairs := Pairs(s.editor.SyntaxLayers(), s.editor.Runes())
+	start, end, ok := Enclosing(pairs, sel.Start(), sel.End())
+	if !ok {
+		s.Warn = "No enclosing scope"
+		return nil
+	}
+	s.editor.SelectSlice([]gxui.TextSelection{gxui.CreateTextSelection(start, end, false)})
+	s.editor.ScrollToRune(start)
+	return nil
+}
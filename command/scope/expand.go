@@ -0,0 +1,177 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scope
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// expansions remembers the selections that ExpandSelection has grown
+// from, so that ShrinkSelection can go back to them.
+type expansions struct {
+	editor input.Editor
+	from   []input.Span
+
+	// to is the last selection that ExpandSelection chose.  If the
+	// selection has changed since then, the history is stale.
+	to input.Span
+}
+
+func (x *expansions) push(e input.Editor, from, to input.Span) {
+	if x.editor != e || x.to != from {
+		x.from = x.from[:0]
+	}
+	x.editor = e
+	x.from = append(x.from, from)
+	x.to = to
+}
+
+func (x *expansions) pop(e input.Editor, current input.Span) (input.Span, bool) {
+	if x.editor != e || x.to != current || len(x.from) == 0 {
+		x.from = x.from[:0]
+		return input.Span{}, false
+	}
+	prev := x.from[len(x.from)-1]
+	x.from = x.from[:len(x.from)-1]
+	x.to = prev
+	return prev, true
+}
+
+func selection(e Editor) (input.Span, bool) {
+	selections := e.Controller().SelectionSlice()
+	if len(selections) == 0 {
+		return input.Span{}, false
+	}
+	return input.Span{Start: selections[0].Start(), End: selections[0].End()}, true
+}
+
+func selectSpan(e Editor, s input.Span) {
+	e.SelectSlice([]gxui.TextSelection{gxui.CreateTextSelection(s.Start, s.End, false)})
+	e.ScrollToRune(s.Start)
+}
+
+// ExpandSelection is a command which grows the selection to the
+// syntactic node around it.  Running it again moves out to the next
+// node.
+type ExpandSelection struct {
+	status.General
+
+	history *expansions
+	editor  Editor
+}
+
+func NewExpandSelection(theme gxui.Theme, history *expansions) *ExpandSelection {
+	s := &ExpandSelection{history: history}
+	s.Theme = theme
+	return s
+}
+
+func (s *ExpandSelection) Name() string {
+	return "expand-selection"
+}
+
+func (s *ExpandSelection) Menu() string {
+	return "Edit"
+}
+
+func (s *ExpandSelection) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModAlt | gxui.ModShift,
+		Key:      gxui.KeyUp,
+	}}
+}
+
+func (s *ExpandSelection) Reset() {
+	s.Clear()
+	s.editor = nil
+}
+
+func (s *ExpandSelection) Store(elem interface{}) bind.Status {
+	editor, ok := elem.(Editor)
+	if !ok {
+		return bind.Waiting
+	}
+	s.editor = editor
+	return bind.Done
+}
+
+func (s *ExpandSelection) Exec() error {
+	from, ok := selection(s.editor)
+	if !ok {
+		return nil
+	}
+	start, end, ok := Expand(s.editor.Filepath(), s.editor.Runes(), s.editor.SyntaxLayers(), from.Start, from.End)
+	if !ok {
+		s.Warn = "Nothing encloses the selection"
+		return nil
+	}
+	to := input.Span{Start: start, End: end}
+	s.history.push(s.editor, from, to)
+	selectSpan(s.editor, to)
+	return nil
+}
+
+// ShrinkSelection is a command which undoes ExpandSelection, going
+// back to the selection it grew from.
+type ShrinkSelection struct {
+	status.General
+
+	history *expansions
+	editor  Editor
+}
+
+func NewShrinkSelection(theme gxui.Theme, history *expansions) *ShrinkSelection {
+	s := &ShrinkSelection{history: history}
+	s.Theme = theme
+	return s
+}
+
+func (s *ShrinkSelection) Name() string {
+	return "shrink-selection"
+}
+
+func (s *ShrinkSelection) Menu() string {
+	return "Edit"
+}
+
+func (s *ShrinkSelection) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModAlt | gxui.ModShift,
+		Key:      gxui.KeyDown,
+	}}
+}
+
+func (s *ShrinkSelection) Reset() {
+	s.Clear()
+	s.editor = nil
+}
+
+func (s *ShrinkSelection) Store(elem interface{}) bind.Status {
+	editor, ok := elem.(Editor)
+	if !ok {
+		return bind.Waiting
+	}
+	s.editor = editor
+	return bind.Done
+}
+
+func (s *ShrinkSelection) Exec() error {
+	current, ok := selection(s.editor)
+	if !ok {
+		return nil
+	}
+	prev, ok := s.history.pop(s.editor, current)
+	if !ok {
+		s.Warn = "The selection wasn't expanded"
+		return nil
+	}
+	selectSpan(s.editor, prev)
+	return nil
+}
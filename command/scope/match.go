@@ -0,0 +1,72 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scope
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// GotoMatch is a command which moves the caret to the bracket that
+// matches the bracket next to it.
+type GotoMatch struct {
+	status.General
+
+	editor Editor
+}
+
+func NewGotoMatch(theme gxui.Theme) *GotoMatch {
+	g := &GotoMatch{}
+	g.Theme = theme
+	return g
+}
+
+func (g *GotoMatch) Name() string {
+	return "goto-matching-bracket"
+}
+
+func (g *GotoMatch) Menu() string {
+	return "Navigation"
+}
+
+func (g *GotoMatch) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl,
+		Key:      gxui.KeyRightBracket,
+	}}
+}
+
+func (g *GotoMatch) Reset() {
+	g.Clear()
+	g.editor = nil
+}
+
+func (g *GotoMatch) Store(elem interface{}) bind.Status {
+	editor, ok := elem.(Editor)
+	if !ok {
+		return bind.Waiting
+	}
+	g.editor = editor
+	return bind.Done
+}
+
+func (g *GotoMatch) Exec() error {
+	carets := g.editor.Controller().Carets()
+	if len(carets) == 0 {
+		return nil
+	}
+	pairs := Pairs(g.editor.SyntaxLayers(), g.editor.Runes())
+	pos, ok := Match(pairs, carets[0])
+	if !ok {
+		g.Warn = "No bracket at the caret"
+		return nil
+	}
+	g.editor.Controller().SetCaret(pos)
+	g.editor.ScrollToRune(pos)
+	return nil
+}
@@ -0,0 +1,169 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scope
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"unicode"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// Expand returns the smallest syntactic range around the text from
+// start to end that is larger than it.  Go files use the nodes of
+// their syntax tree (e.g. identifier, expression, statement, block,
+// function); other files, and Go that can't be parsed, use the word
+// around an empty selection and then bracket and quote pairs.
+func Expand(path string, text []rune, layers []input.SyntaxLayer, start, end int) (int, int, bool) {
+	if filepath.Ext(path) == ".go" {
+		if s, e, ok := GoEnclosing(text, start, end); ok {
+			return s, e, true
+		}
+	}
+	if s, e, ok := word(text, start, end); ok {
+		return s, e, true
+	}
+	pairs := append(Pairs(layers, text), QuotePairs(text)...)
+	return Enclosing(pairs, start, end)
+}
+
+// GoEnclosing returns the smallest node in the syntax tree of the Go
+// source in text that contains start to end and is larger than it.
+// The contents of blocks, calls, composite literals, and string
+// literals count as nodes too, so that they can be selected without
+// their brackets or quotes.
+func GoEnclosing(text []rune, start, end int) (int, int, bool) {
+	src := []byte(string(text))
+	fset := token.NewFileSet()
+	// Syntax errors still leave a partial tree, which is better than
+	// nothing while the code is being written.
+	f, _ := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if f == nil {
+		return 0, 0, false
+	}
+	tf := fset.File(f.Pos())
+	if tf == nil {
+		return 0, 0, false
+	}
+	runes := runeOffsets(src)
+	offset := func(p token.Pos) (int, bool) {
+		if !p.IsValid() || int(p) < tf.Base() || int(p) > tf.Base()+tf.Size() {
+			return 0, false
+		}
+		return runes[tf.Offset(p)], true
+	}
+	r := smallest{start: start, end: end}
+	try := func(from, to token.Pos) {
+		s, ok := offset(from)
+		if !ok {
+			return
+		}
+		e, ok := offset(to)
+		if !ok || e < s {
+			return
+		}
+		r.try(s, e)
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		s, sOK := offset(n.Pos())
+		e, eOK := offset(n.End())
+		if !sOK || !eOK || s > start || e < end {
+			return false
+		}
+		r.try(s, e)
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			try(n.Lbrace+1, n.Rbrace)
+		case *ast.CompositeLit:
+			try(n.Lbrace+1, n.Rbrace)
+		case *ast.CallExpr:
+			try(n.Lparen+1, n.Rparen)
+		case *ast.FieldList:
+			if n.Opening.IsValid() && n.Closing.IsValid() {
+				try(n.Opening+1, n.Closing)
+			}
+		case *ast.BasicLit:
+			if n.Kind == token.STRING || n.Kind == token.CHAR {
+				try(n.Pos()+1, n.End()-1)
+			}
+		}
+		return true
+	})
+	return r.found.Start, r.found.End, r.ok
+}
+
+// runeOffsets returns the rune offset of each byte offset in src that
+// starts a rune, plus the offset of the end of src.
+func runeOffsets(src []byte) []int {
+	offsets := make([]int, len(src)+1)
+	r := 0
+	for i := range string(src) {
+		offsets[i] = r
+		r++
+	}
+	offsets[len(src)] = r
+	return offsets
+}
+
+// word returns the word around an empty selection at start, if there
+// is one.
+func word(text []rune, start, end int) (int, int, bool) {
+	if start != end || start < 0 || start > len(text) {
+		return 0, 0, false
+	}
+	s, e := start, end
+	for s > 0 && isWordRune(text[s-1]) {
+		s--
+	}
+	for e < len(text) && isWordRune(text[e]) {
+		e++
+	}
+	return s, e, s != e
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// QuotePairs returns the pairs of matching quotes in text, ordered by
+// where they open.  Double and single quotes end at the end of their
+// line and may be escaped with a backslash; backquotes may span
+// lines.
+func QuotePairs(text []rune) []Pair {
+	var (
+		pairs []Pair
+		open  = -1
+	)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if open == -1 {
+			switch c {
+			case '"', '\'', '`':
+				open = i
+			}
+			continue
+		}
+		q := text[open]
+		switch {
+		case c == '\n' && q != '`':
+			open = -1
+		case c == '\\' && q != '`':
+			i++
+		case c == q:
+			pairs = append(pairs, Pair{
+				Open:  input.Span{Start: open, End: open + 1},
+				Close: input.Span{Start: i, End: i + 1},
+			})
+			open = -1
+		}
+	}
+	return pairs
+}
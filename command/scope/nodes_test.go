@@ -0,0 +1,96 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scope_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/command/scope"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+const goSrc = `package foo
+
+func Foo(a int) int {
+	if a > 0 {
+		return bar(a + 1)
+	}
+	return 0
+}
+`
+
+func TestExpand(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it grows a Go selection through the syntax tree", func(expect expect.Expectation) {
+		text := []rune(goSrc)
+		pos := strings.Index(goSrc, "a + 1")
+		var got []string
+		start, end := pos, pos
+		for {
+			s, e, ok := scope.Expand("foo.go", text, nil, start, end)
+			if !ok {
+				break
+			}
+			start, end = s, e
+			got = append(got, string(text[start:end]))
+		}
+		expect(got[:4]).To(equal([]string{"a", "a + 1", "bar(a + 1)", "return bar(a + 1)"}))
+		expect(got).To(matchers.Contain("{\n\t\treturn bar(a + 1)\n\t}"))
+		expect(got).To(matchers.Contain("if a > 0 {\n\t\treturn bar(a + 1)\n\t}"))
+		expect(got).To(matchers.Contain(strings.TrimPrefix(strings.TrimSuffix(goSrc, "\n"), "package foo\n\n")))
+	})
+
+	o.Spec("it selects the contents of Go strings before the quotes", func(expect expect.Expectation) {
+		text := []rune(`package foo
+
+var s = "héllo"
+`)
+		pos := strings.Index(string(text), "llo")
+		pos = len([]rune(string(text)[:pos]))
+		start, end, ok := scope.GoEnclosing(text, pos, pos)
+		expect(ok).To(equal(true))
+		expect(string(text[start:end])).To(equal("héllo"))
+
+		start, end, ok = scope.GoEnclosing(text, start, end)
+		expect(ok).To(equal(true))
+		expect(string(text[start:end])).To(equal(`"héllo"`))
+	})
+
+	o.Spec("it falls back to words, quotes, and brackets in other files", func(expect expect.Expectation) {
+		text := []rune(`echo "hi there" (x)`)
+		start, end, ok := scope.Expand("run.sh", text, nil, 7, 7)
+		expect(ok).To(equal(true))
+		expect(string(text[start:end])).To(equal("hi"))
+
+		start, end, ok = scope.Expand("run.sh", text, nil, start, end)
+		expect(ok).To(equal(true))
+		expect(string(text[start:end])).To(equal("hi there"))
+
+		start, end, ok = scope.Expand("run.sh", text, nil, start, end)
+		expect(ok).To(equal(true))
+		expect(string(text[start:end])).To(equal(`"hi there"`))
+
+		_, _, ok = scope.Expand("run.sh", text, nil, start, end)
+		expect(ok).To(equal(false))
+	})
+
+	o.Spec("it pairs quotes on a line, skipping escaped quotes", func(expect expect.Expectation) {
+		text := []rune("a \"b\\\"c\" 'd\n` e\n`")
+		expect(scope.QuotePairs(text)).To(equal([]scope.Pair{
+			{Open: input.Span{Start: 2, End: 3}, Close: input.Span{Start: 7, End: 8}},
+			{Open: input.Span{Start: 12, End: 13}, Close: input.Span{Start: 16, End: 17}},
+		}))
+	})
+}
@@ -0,0 +1,139 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package scope contains commands that use the scope pairs (i.e.
+// brackets) that syntax highlighting plugins have already found in
+// a file, or the syntax tree of Go files.
+package scope
+
+import (
+	"sort"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/theme"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, _ gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	x := &expansions{}
+	return []bind.Bindable{
+		NewGotoMatch(theme),
+		NewSelectEnclosing(theme),
+		NewExpandSelection(theme, x),
+		NewShrinkSelection(theme, x),
+	}
+}
+
+// Editor is the editor that scope commands act on.
+type Editor interface {
+	input.Editor
+	Controller() *gxui.TextBoxController
+	SelectSlice([]gxui.TextSelection)
+	ScrollToRune(int)
+}
+
+// Pair is a matching pair of brackets.  Open and Close are the spans
+// of the brackets themselves.
+type Pair struct {
+	Open, Close input.Span
+}
+
+// Pairs returns the scope pairs in layers, ordered by where they
+// open.  Each layer with a construct of at least theme.ScopePair
+// holds the brackets at one nesting depth, so the brackets are
+// paired up within each layer.  text is only used to tell opening
+// brackets from closing brackets.
+func Pairs(layers []input.SyntaxLayer, text []rune) []Pair {
+	var pairs []Pair
+	for _, l := range layers {
+		if l.Construct < theme.ScopePair {
+			continue
+		}
+		spans := append([]input.Span(nil), l.Spans...)
+		sort.Slice(spans, func(i, j int) bool {
+			return spans[i].Start < spans[j].Start
+		})
+		var open []input.Span
+		for _, s := range spans {
+			if s.Start < 0 || s.Start >= len(text) {
+				continue
+			}
+			switch text[s.Start] {
+			case '(', '[', '{':
+				open = append(open, s)
+			case ')', ']', '}':
+				if len(open) == 0 {
+					continue
+				}
+				pairs = append(pairs, Pair{Open: open[len(open)-1], Close: s})
+				open = open[:len(open)-1]
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Open.Start < pairs[j].Open.Start
+	})
+	return pairs
+}
+
+// Match returns the start of the bracket that matches the bracket at
+// pos.  A bracket that starts at pos is preferred over one that ends
+// there, so that the caret can be on either side of a bracket.
+func Match(pairs []Pair, pos int) (int, bool) {
+	for _, p := range pairs {
+		switch pos {
+		case p.Open.Start:
+			return p.Close.Start, true
+		case p.Close.Start:
+			return p.Open.Start, true
+		}
+	}
+	for _, p := range pairs {
+		switch pos {
+		case p.Open.End:
+			return p.Close.Start, true
+		case p.Close.End:
+			return p.Open.Start, true
+		}
+	}
+	return 0, false
+}
+
+// Enclosing returns the smallest range around the text from start to
+// end that is either the contents of a pair or a whole pair,
+// including its brackets.  The range is always larger than start to
+// end, so that calling Enclosing with its own result selects the
+// next scope out.
+func Enclosing(pairs []Pair, start, end int) (int, int, bool) {
+	r := smallest{start: start, end: end}
+	for _, p := range pairs {
+		r.try(p.Open.End, p.Close.Start)
+		r.try(p.Open.Start, p.Close.End)
+	}
+	return r.found.Start, r.found.End, r.ok
+}
+
+// smallest keeps track of the smallest range that is larger than
+// start to end and contains it.
+type smallest struct {
+	start, end int
+
+	found input.Span
+	ok    bool
+}
+
+func (r *smallest) try(s, e int) {
+	if s > r.start || e < r.end || (s == r.start && e == r.end) {
+		return
+	}
+	if r.ok && e-s >= r.found.End-r.found.Start {
+		return
+	}
+	r.found, r.ok = input.Span{Start: s, End: e}, true
+}
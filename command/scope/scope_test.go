@@ -0,0 +1,84 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scope_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/command/scope"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var equal = matchers.Equal
+
+func TestScope(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, []rune, []scope.Pair) {
+		text := []rune("f(a[1], {b})")
+		layers := []input.SyntaxLayer{
+			{Construct: theme.ScopePair, Spans: []input.Span{{Start: 11, End: 12}, {Start: 1, End: 2}}},
+			{Construct: theme.ScopePair + 1, Spans: []input.Span{{Start: 3, End: 4}, {Start: 5, End: 6}, {Start: 8, End: 9}, {Start: 10, End: 11}}},
+			{Construct: theme.Ident, Spans: []input.Span{{Start: 0, End: 1}}},
+		}
+		return expect.New(t), text, scope.Pairs(layers, text)
+	})
+
+	o.Spec("it pairs brackets at each depth", func(expect expect.Expectation, _ []rune, pairs []scope.Pair) {
+		expect(pairs).To(equal([]scope.Pair{
+			{Open: input.Span{Start: 1, End: 2}, Close: input.Span{Start: 11, End: 12}},
+			{Open: input.Span{Start: 3, End: 4}, Close: input.Span{Start: 5, End: 6}},
+			{Open: input.Span{Start: 8, End: 9}, Close: input.Span{Start: 10, End: 11}},
+		}))
+	})
+
+	o.Spec("it skips spans that aren't brackets", func(expect expect.Expectation, text []rune, _ []scope.Pair) {
+		layers := []input.SyntaxLayer{
+			{Construct: theme.ScopePair, Spans: []input.Span{{Start: 0, End: 1}, {Start: 11, End: 12}, {Start: 1, End: 2}}},
+		}
+		expect(scope.Pairs(layers, text)).To(equal([]scope.Pair{
+			{Open: input.Span{Start: 1, End: 2}, Close: input.Span{Start: 11, End: 12}},
+		}))
+	})
+
+	o.Spec("it finds the matching bracket on either side of the caret", func(expect expect.Expectation, _ []rune, pairs []scope.Pair) {
+		pos, ok := scope.Match(pairs, 1)
+		expect(ok).To(equal(true))
+		expect(pos).To(equal(11))
+
+		pos, ok = scope.Match(pairs, 6)
+		expect(ok).To(equal(true))
+		expect(pos).To(equal(3))
+
+		_, ok = scope.Match(pairs, 7)
+		expect(ok).To(equal(false))
+	})
+
+	o.Spec("it selects the enclosing scope, then its brackets", func(expect expect.Expectation, _ []rune, pairs []scope.Pair) {
+		start, end, ok := scope.Enclosing(pairs, 9, 9)
+		expect(ok).To(equal(true))
+		expect([]int{start, end}).To(equal([]int{9, 10}))
+
+		start, end, ok = scope.Enclosing(pairs, start, end)
+		expect(ok).To(equal(true))
+		expect([]int{start, end}).To(equal([]int{8, 11}))
+
+		start, end, ok = scope.Enclosing(pairs, start, end)
+		expect(ok).To(equal(true))
+		expect([]int{start, end}).To(equal([]int{2, 11}))
+
+		start, end, ok = scope.Enclosing(pairs, start, end)
+		expect(ok).To(equal(true))
+		expect([]int{start, end}).To(equal([]int{1, 12}))
+
+		_, _, ok = scope.Enclosing(pairs, start, end)
+		expect(ok).To(equal(false))
+	})
+}
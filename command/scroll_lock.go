@@ -0,0 +1,75 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// A ScrollLocker is an editor whose file can be open in more than one
+// split, and which can scroll those splits together.
+type ScrollLocker interface {
+	ScrollLocked() bool
+	SetScrollLocked(bool)
+}
+
+// ToggleScrollLock is a command which locks the scroll positions of
+// the splits showing the current file together, so that scrolling one
+// of them scrolls the rest by the same amount.
+type ToggleScrollLock struct {
+	status.General
+
+	locker ScrollLocker
+}
+
+func NewToggleScrollLock(theme gxui.Theme) *ToggleScrollLock {
+	t := &ToggleScrollLock{}
+	t.Theme = theme
+	return t
+}
+
+func (t *ToggleScrollLock) Name() string {
+	return "toggle-scroll-lock"
+}
+
+func (t *ToggleScrollLock) Menu() string {
+	return "View"
+}
+
+func (t *ToggleScrollLock) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (t *ToggleScrollLock) Reset() {
+	t.Clear()
+	t.locker = nil
+}
+
+func (t *ToggleScrollLock) Store(elem interface{}) bind.Status {
+	l, ok := elem.(ScrollLocker)
+	if !ok {
+		return bind.Waiting
+	}
+	t.locker = l
+	return bind.Done
+}
+
+func (t *ToggleScrollLock) Exec() error {
+	locked := !t.locker.ScrollLocked()
+	t.locker.SetScrollLocked(locked)
+	if locked != t.locker.ScrollLocked() {
+		t.Warn = "The current file is only open in one split"
+		return nil
+	}
+	t.Info = "Scroll lock off"
+	if locked {
+		t.Info = "Scroll lock on"
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/setting"
+	"github.com/nelsam/vidar/theme"
+)
+
+// valueWidth is the width of the text boxes for values.
+const valueWidth = 400
+
+var (
+	helpColor    = gxui.Color{R: 0.6, G: 0.6, B: 0.6, A: 1}
+	invalidColor = gxui.Color{R: 1, G: 0.4, B: 0.4, A: 1}
+)
+
+// appliers are the types that settings are applied to as soon as
+// they're changed.
+type appliers struct {
+	driver   gxui.Driver
+	remapper Remapper
+	themer   SyntaxThemer
+	fonter   Fonter
+}
+
+// apply applies the new value of e to the running editor.  It
+// returns a message for anything that couldn't be applied.
+func (a appliers) apply(e setting.Entry) string {
+	switch {
+	case e.Restart:
+		return fmt.Sprintf("%s will be used after vidar is restarted", e.Key)
+	case e.Section == setting.BindingsSection:
+		a.remapper.Remap()
+	case e.Section == setting.FontsSection, e.Key == "fontsize":
+		a.fonter.SetFont(setting.PrefFont(a.driver))
+	case e.Key == "theme":
+		t, err := theme.Load(setting.ThemesDir(), e.Value())
+		if err != nil {
+			return fmt.Sprintf("Could not load theme %s: %s", e.Value(), err)
+		}
+		a.themer.SetSyntaxTheme(t)
+	}
+	return ""
+}
+
+// Pane is a panel that lists every setting that can be changed.  Each
+// value can be edited in place; pressing enter checks the new value,
+// saves it, and applies it to the running editor.
+type Pane struct {
+	mixins.LinearLayout
+
+	theme *basic.Theme
+
+	filter gxui.TextBox
+	status gxui.Label
+	list   gxui.LinearLayout
+
+	paneler  Paneler
+	appliers appliers
+}
+
+// NewPane creates a *Pane.
+func NewPane(theme *basic.Theme) *Pane {
+	p := &Pane{
+		theme:  theme,
+		filter: theme.CreateTextBox(),
+		status: theme.CreateLabel(),
+		list:   theme.CreateLinearLayout(),
+	}
+	p.Init(p, theme)
+	p.SetDirection(gxui.TopToBottom)
+
+	header := theme.CreateLinearLayout()
+	header.SetDirection(gxui.LeftToRight)
+	closer := theme.CreateButton()
+	closer.SetText("x")
+	closer.OnClick(func(gxui.MouseEvent) {
+		if p.paneler != nil {
+			p.paneler.HidePanel(p)
+		}
+	})
+	header.AddChild(closer)
+	filterLabel := theme.CreateLabel()
+	filterLabel.SetText("Filter:")
+	header.AddChild(filterLabel)
+	p.filter.SetDesiredWidth(valueWidth / 2)
+	p.filter.OnTextChanged(func([]gxui.TextBoxEdit) {
+		p.update()
+	})
+	header.AddChild(p.filter)
+	header.AddChild(p.status)
+	p.AddChild(header)
+
+	p.list.SetDirection(gxui.TopToBottom)
+	scrollable := theme.CreateScrollLayout()
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(p.list)
+	p.AddChild(scrollable)
+	return p
+}
+
+// Show lists the current settings and shows p using paneler.
+// Changed settings are applied using a.  It must be called on the
+// UI goroutine.
+func (p *Pane) Show(paneler Paneler, a appliers) {
+	p.paneler = paneler
+	p.appliers = a
+	p.status.SetText("Press enter to save a value")
+	p.update()
+	if !paneler.HasPanel(p) {
+		paneler.ShowPanel(p)
+	}
+	gxui.SetFocus(p.filter)
+}
+
+// update lists the entries that match the filter, grouped by
+// section.
+func (p *Pane) update() {
+	p.list.RemoveAll()
+	filter := strings.ToLower(p.filter.Text())
+	section := ""
+	for _, e := range setting.Entries(p.appliers.remapper.CommandNames()) {
+		if filter != "" && !strings.Contains(strings.ToLower(e.Section+" "+e.Key), filter) {
+			continue
+		}
+		if e.Section != section {
+			section = e.Section
+			l := p.theme.CreateLabel()
+			l.SetText(section)
+			p.list.AddChild(l)
+		}
+		p.list.AddChild(p.row(e))
+	}
+}
+
+// row returns a row for e, with a text box to edit its value.
+func (p *Pane) row(e setting.Entry) gxui.Control {
+	row := p.theme.CreateLinearLayout()
+	row.SetDirection(gxui.LeftToRight)
+	row.SetMargin(math.Spacing{L: 10})
+
+	key := p.theme.CreateLabel()
+	key.SetText(e.Key + ":")
+	row.AddChild(key)
+
+	value := p.theme.CreateTextBox()
+	value.SetDesiredWidth(valueWidth)
+	value.SetText(e.Value())
+	row.AddChild(value)
+
+	help := p.theme.CreateLabel()
+	help.SetText(e.Help)
+	help.SetColor(helpColor)
+	row.AddChild(help)
+
+	value.OnKeyPress(func(ev gxui.KeyboardEvent) {
+		switch ev.Key {
+		case gxui.KeyEnter:
+			p.set(e, value, help)
+		case gxui.KeyEscape:
+			value.SetText(e.Value())
+			help.SetText(e.Help)
+			help.SetColor(helpColor)
+		}
+	})
+	return row
+}
+
+// set saves the value in value to e, showing any validation errors in
+// help.
+func (p *Pane) set(e setting.Entry, value gxui.TextBox, help gxui.Label) {
+	if err := e.Set(value.Text()); err != nil {
+		help.SetText(err.Error())
+		help.SetColor(invalidColor)
+		return
+	}
+	help.SetText(e.Help)
+	help.SetColor(helpColor)
+	value.SetText(e.Value())
+	msg := p.appliers.apply(e)
+	if msg == "" {
+		msg = fmt.Sprintf("Saved %s", e.Key)
+	}
+	p.status.SetText(msg)
+}
@@ -0,0 +1,126 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package settings contains a pane for viewing and changing vidar's
+// settings while it's running.
+package settings
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/theme"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{NewOpen(driver, theme)}
+}
+
+// A Paneler is a type that can display panels below the editor.
+type Paneler interface {
+	ShowPanel(gxui.Control)
+	HidePanel(gxui.Control)
+	HasPanel(gxui.Control) bool
+}
+
+// A Remapper is a type that binds commands to keys, and can bind them
+// again after the key bindings have changed.
+type Remapper interface {
+	CommandNames() []string
+	Remap()
+}
+
+// A SyntaxThemer is a type that can change the theme used to
+// highlight code.
+type SyntaxThemer interface {
+	SetSyntaxTheme(theme.Theme)
+}
+
+// A Fonter is a type that can change the font of its controls.
+type Fonter interface {
+	SetFont(gxui.Font)
+}
+
+// Open is a command which opens the settings pane.
+type Open struct {
+	status.General
+
+	driver gxui.Driver
+	theme  *basic.Theme
+	pane   *Pane
+
+	paneler  Paneler
+	remapper Remapper
+	themer   SyntaxThemer
+	fonter   Fonter
+}
+
+func NewOpen(driver gxui.Driver, theme *basic.Theme) *Open {
+	o := &Open{driver: driver, theme: theme}
+	o.Theme = theme
+	return o
+}
+
+func (o *Open) Name() string {
+	return "open-settings"
+}
+
+func (o *Open) Menu() string {
+	return "File"
+}
+
+func (o *Open) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl,
+		Key:      gxui.KeyComma,
+	}}
+}
+
+func (o *Open) Reset() {
+	o.Clear()
+	o.paneler = nil
+	o.remapper = nil
+	o.themer = nil
+	o.fonter = nil
+}
+
+func (o *Open) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Paneler:
+		o.paneler = src
+	case Remapper:
+		o.remapper = src
+	}
+	// The editor is both a SyntaxThemer and a Fonter, so these
+	// aren't part of the switch.
+	if t, ok := elem.(SyntaxThemer); ok && o.themer == nil {
+		o.themer = t
+	}
+	if f, ok := elem.(Fonter); ok && o.fonter == nil {
+		o.fonter = f
+	}
+	if o.paneler == nil || o.remapper == nil || o.themer == nil || o.fonter == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (o *Open) Exec() error {
+	if o.pane == nil {
+		o.pane = NewPane(o.theme)
+	}
+	o.pane.Show(o.paneler, appliers{
+		driver:   o.driver,
+		remapper: o.remapper,
+		themer:   o.themer,
+		fonter:   o.fonter,
+	})
+	return nil
+}
@@ -0,0 +1,76 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/setting"
+)
+
+// colonIndented are the file extensions of languages that start an
+// indented block after a colon.
+var colonIndented = map[string]bool{
+	".py":   true,
+	".yaml": true,
+	".yml":  true,
+}
+
+// SmartIndent is a hook that indents new lines to match the line
+// that they're started from, adding a level when that line ends by
+// opening a block.
+type SmartIndent struct {
+	indent setting.Indent
+	colon  bool
+}
+
+func NewSmartIndent(path string) *SmartIndent {
+	return &SmartIndent{
+		indent: setting.IndentFor(path),
+		colon:  colonIndented[strings.ToLower(filepath.Ext(path))],
+	}
+}
+
+func (s *SmartIndent) Name() string {
+	return "smart-indent"
+}
+
+func (s *SmartIndent) OpName() string {
+	return "input-handler"
+}
+
+func (s *SmartIndent) Indent(e input.Editor, pos int) (string, bool) {
+	text := e.Runes()
+	if pos > len(text) {
+		return "", false
+	}
+	start := pos
+	for start > 0 && text[start-1] != '\n' {
+		start--
+	}
+	end := start
+	for end < pos && (text[end] == ' ' || text[end] == '\t') {
+		end++
+	}
+	indent := string(text[start:end])
+	last := pos - 1
+	for last >= end && (text[last] == ' ' || text[last] == '\t') {
+		last--
+	}
+	if last < end {
+		return indent, true
+	}
+	switch text[last] {
+	case '{', '(', '[':
+		indent += s.indent.Level()
+	case ':':
+		if s.colon {
+			indent += s.indent.Level()
+		}
+	}
+	return indent, true
+}
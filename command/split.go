@@ -71,3 +71,65 @@ func (s *Split) Exec(target interface{}) bind.Status {
 	splitter.Split(s.orientation)
 	return bind.Done
 }
+
+type FileSplitter interface {
+	SplitFile(gxui.Orientation)
+}
+
+// SplitFile is a command which opens the current file in a new split,
+// keeping it open in the current one.  Both splits edit the same text.
+type SplitFile struct {
+	orientation gxui.Orientation
+}
+
+func NewHorizontalFileSplit() *SplitFile {
+	return &SplitFile{
+		orientation: gxui.Horizontal,
+	}
+}
+
+func NewVerticalFileSplit() *SplitFile {
+	return &SplitFile{
+		orientation: gxui.Vertical,
+	}
+}
+
+func (s *SplitFile) Name() string {
+	switch s.orientation {
+	case gxui.Horizontal:
+		return "split-file-horizontally"
+	case gxui.Vertical:
+		return "split-file-vertically"
+	default:
+		panic(fmt.Errorf("Orientation %d is invalid", s.orientation))
+	}
+}
+
+func (s *SplitFile) Menu() string {
+	return "View"
+}
+
+func (s *SplitFile) Defaults() []fmt.Stringer {
+	var key gxui.KeyboardKey
+	switch s.orientation {
+	case gxui.Horizontal:
+		key = gxui.KeyH
+	case gxui.Vertical:
+		key = gxui.KeyV
+	default:
+		panic(fmt.Errorf("Orientation %d is invalid", s.orientation))
+	}
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModAlt | gxui.ModShift,
+		Key:      key,
+	}}
+}
+
+func (s *SplitFile) Exec(target interface{}) bind.Status {
+	splitter, ok := target.(FileSplitter)
+	if !ok {
+		return bind.Waiting
+	}
+	splitter.SplitFile(s.orientation)
+	return bind.Done
+}
@@ -0,0 +1,221 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package statusbar contains the segments that vidar displays in its
+// status bar by default.  Plugins can add their own segments by
+// implementing status.Segment.
+package statusbar
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/eol"
+	"github.com/nelsam/vidar/plugin/command"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, _ gxui.Driver, _ *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{
+		Position{},
+		Selection{},
+		FileType{},
+		Encoding{},
+		Dirty{},
+	}
+}
+
+// Controllable is an editor with a text controller, which knows
+// about carets and selections.
+type Controllable interface {
+	Controller() *gxui.TextBoxController
+}
+
+// Position is a status segment which displays the line and column of
+// the last caret in the focused editor.
+type Position struct{}
+
+func (Position) Name() string {
+	return "status-position"
+}
+
+func (Position) Text(editor interface{}) string {
+	c, ok := editor.(Controllable)
+	if !ok {
+		return ""
+	}
+	ctrl := c.Controller()
+	caret := ctrl.LastCaret()
+	line := ctrl.LineIndex(caret)
+	return fmt.Sprintf("%d:%d", line+1, caret-ctrl.LineStart(line)+1)
+}
+
+// Selection is a status segment which displays the number of selected
+// characters in the focused editor, and the number of carets when
+// there is more than one.
+type Selection struct{}
+
+func (Selection) Name() string {
+	return "status-selection"
+}
+
+func (Selection) Text(editor interface{}) string {
+	c, ok := editor.(Controllable)
+	if !ok {
+		return ""
+	}
+	selections := c.Controller().SelectionSlice()
+	selected := 0
+	for _, s := range selections {
+		selected += s.Length()
+	}
+	var parts []string
+	if selected > 0 {
+		parts = append(parts, fmt.Sprintf("%d selected", selected))
+	}
+	if len(selections) > 1 {
+		parts = append(parts, fmt.Sprintf("%d carets", len(selections)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// A Filepather is an editor for a file.
+type Filepather interface {
+	Filepath() string
+}
+
+// fileTypes holds the names of file types that are more readable than
+// their extensions.
+var fileTypes = map[string]string{
+	".go":       "Go",
+	".mod":      "Go module",
+	".md":       "Markdown",
+	".markdown": "Markdown",
+	".json":     "JSON",
+	".yml":      "YAML",
+	".yaml":     "YAML",
+	".toml":     "TOML",
+	".sh":       "Shell",
+	".py":       "Python",
+	".js":       "JavaScript",
+	".html":     "HTML",
+	".css":      "CSS",
+	".txt":      "Text",
+}
+
+// FileType is a status segment which displays the type of the file in
+// the focused editor, based on its extension.
+type FileType struct{}
+
+func (FileType) Name() string {
+	return "status-file-type"
+}
+
+func (FileType) Text(editor interface{}) string {
+	f, ok := editor.(Filepather)
+	if !ok {
+		return ""
+	}
+	ext := strings.ToLower(filepath.Ext(f.Filepath()))
+	if name, ok := fileTypes[ext]; ok {
+		return name
+	}
+	if ext == "" {
+		return "Text"
+	}
+	return strings.TrimPrefix(ext, ".")
+}
+
+// A Runer is an editor which can return its text as runes.
+type Runer interface {
+	Runes() []rune
+}
+
+// A LineEnder is an editor which knows the line endings of its file.
+type LineEnder interface {
+	LineEnding() eol.Ending
+	MixedLineEndings() bool
+}
+
+// A BinaryEditor is an editor which may have a binary file open.
+type BinaryEditor interface {
+	Binary() bool
+}
+
+// Encoding is a status segment which displays the encoding and line
+// endings of the file in the focused editor.  Files are always read
+// and written as UTF-8.  Files that had more than one kind of line
+// ending when they were opened are marked as mixed until they're
+// saved.  For editors that don't know their file's line endings, they
+// are detected from the first line.
+type Encoding struct{}
+
+func (Encoding) Name() string {
+	return "status-encoding"
+}
+
+func (Encoding) Text(editor interface{}) string {
+	if b, ok := editor.(BinaryEditor); ok && b.Binary() {
+		return "binary"
+	}
+	if l, ok := editor.(LineEnder); ok {
+		text := "UTF-8 " + l.LineEnding().String()
+		if l.MixedLineEndings() {
+			text += " (mixed)"
+		}
+		return text
+	}
+	r, ok := editor.(Runer)
+	if !ok {
+		return ""
+	}
+	return "UTF-8 " + lineEnding(r.Runes())
+}
+
+func lineEnding(text []rune) string {
+	for i, r := range text {
+		if r != '\n' {
+			continue
+		}
+		if i > 0 && text[i-1] == '\r' {
+			return "CRLF"
+		}
+		return "LF"
+	}
+	return "LF"
+}
+
+// A Changer is an editor which tracks unsaved changes.
+type Changer interface {
+	HasChanges() bool
+}
+
+// A Conflicter is an editor whose file may have changed on disk while
+// it had unsaved changes.
+type Conflicter interface {
+	HasConflict() bool
+}
+
+// Dirty is a status segment which is displayed when the focused
+// editor has unsaved changes.
+type Dirty struct{}
+
+func (Dirty) Name() string {
+	return "status-dirty"
+}
+
+func (Dirty) Text(editor interface{}) string {
+	if c, ok := editor.(Conflicter); ok && c.HasConflict() {
+		return "modified (changed on disk)"
+	}
+	if c, ok := editor.(Changer); ok && c.HasChanges() {
+		return "modified"
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package statusbar_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/command/statusbar"
+	"github.com/nelsam/vidar/eol"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var equal = matchers.Equal
+
+type fakeEditor struct {
+	path     string
+	text     string
+	changed  bool
+	conflict bool
+}
+
+func (e fakeEditor) Filepath() string  { return e.path }
+func (e fakeEditor) Runes() []rune     { return []rune(e.text) }
+func (e fakeEditor) HasChanges() bool  { return e.changed }
+func (e fakeEditor) HasConflict() bool { return e.conflict }
+
+type fakeFileEditor struct {
+	fakeEditor
+	ending eol.Ending
+	mixed  bool
+	binary bool
+}
+
+func (e fakeFileEditor) LineEnding() eol.Ending { return e.ending }
+func (e fakeFileEditor) MixedLineEndings() bool { return e.mixed }
+func (e fakeFileEditor) Binary() bool           { return e.binary }
+
+func TestSegments(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it hides every segment when no editor is focused", func(expect expect.Expectation) {
+		expect(statusbar.Position{}.Text(nil)).To(equal(""))
+		expect(statusbar.Selection{}.Text(nil)).To(equal(""))
+		expect(statusbar.FileType{}.Text(nil)).To(equal(""))
+		expect(statusbar.Encoding{}.Text(nil)).To(equal(""))
+		expect(statusbar.Dirty{}.Text(nil)).To(equal(""))
+	})
+
+	o.Spec("it names file types by extension", func(expect expect.Expectation) {
+		expect(statusbar.FileType{}.Text(fakeEditor{path: "/tmp/foo.go"})).To(equal("Go"))
+		expect(statusbar.FileType{}.Text(fakeEditor{path: "/tmp/README.MD"})).To(equal("Markdown"))
+		expect(statusbar.FileType{}.Text(fakeEditor{path: "/tmp/foo.rs"})).To(equal("rs"))
+		expect(statusbar.FileType{}.Text(fakeEditor{path: "/tmp/Makefile"})).To(equal("Text"))
+	})
+
+	o.Spec("it detects line endings", func(expect expect.Expectation) {
+		expect(statusbar.Encoding{}.Text(fakeEditor{text: "foo\nbar\r\n"})).To(equal("UTF-8 LF"))
+		expect(statusbar.Encoding{}.Text(fakeEditor{text: "foo\r\nbar\n"})).To(equal("UTF-8 CRLF"))
+		expect(statusbar.Encoding{}.Text(fakeEditor{text: "foo"})).To(equal("UTF-8 LF"))
+	})
+
+	o.Spec("it shows the line endings that editors track", func(expect expect.Expectation) {
+		expect(statusbar.Encoding{}.Text(fakeFileEditor{ending: eol.CRLF})).To(equal("UTF-8 CRLF"))
+		expect(statusbar.Encoding{}.Text(fakeFileEditor{ending: eol.LF, mixed: true})).To(equal("UTF-8 LF (mixed)"))
+		expect(statusbar.Encoding{}.Text(fakeFileEditor{binary: true})).To(equal("binary"))
+	})
+
+	o.Spec("it shows unsaved changes", func(expect expect.Expectation) {
+		expect(statusbar.Dirty{}.Text(fakeEditor{})).To(equal(""))
+		expect(statusbar.Dirty{}.Text(fakeEditor{changed: true})).To(equal("modified"))
+		expect(statusbar.Dirty{}.Text(fakeEditor{changed: true, conflict: true})).To(equal("modified (changed on disk)"))
+	})
+}
@@ -0,0 +1,139 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/scoring"
+	"github.com/nelsam/vidar/setting"
+	"github.com/nelsam/vidar/theme"
+)
+
+var themeMatchColor = gxui.Color{R: 0.4, G: 0.8, B: 0.3, A: 1}
+
+// SyntaxThemer is a type that can change its syntax theme.
+type SyntaxThemer interface {
+	SetSyntaxTheme(theme.Theme)
+}
+
+// SwitchTheme is a command which switches to one of the themes in
+// the themes directory.  Typing filters the theme names, and the
+// first match is loaded and saved as the theme to use on startup.
+type SwitchTheme struct {
+	status.General
+
+	gTheme *basic.Theme
+
+	filter  gxui.TextBox
+	matches gxui.LinearLayout
+	input   <-chan gxui.Focusable
+
+	names  []string
+	choice string
+
+	themer SyntaxThemer
+}
+
+func NewSwitchTheme(gTheme *basic.Theme) *SwitchTheme {
+	s := &SwitchTheme{
+		gTheme:  gTheme,
+		filter:  gTheme.CreateTextBox(),
+		matches: gTheme.CreateLinearLayout(),
+	}
+	s.Theme = gTheme
+	s.filter.SetDesiredWidth(math.MaxSize.W)
+	s.filter.OnTextChanged(func([]gxui.TextBoxEdit) {
+		s.update()
+	})
+	s.matches.SetDirection(gxui.LeftToRight)
+	return s
+}
+
+func (s *SwitchTheme) Name() string {
+	return "switch-theme"
+}
+
+func (s *SwitchTheme) Menu() string {
+	return "View"
+}
+
+func (s *SwitchTheme) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyK,
+	}}
+}
+
+func (s *SwitchTheme) Start(gxui.Control) gxui.Control {
+	s.names = theme.Names(setting.ThemesDir())
+	s.filter.SetText("")
+	s.update()
+
+	input := make(chan gxui.Focusable, 1)
+	input <- s.filter
+	close(input)
+	s.input = input
+	return s.matches
+}
+
+func (s *SwitchTheme) Next() gxui.Focusable {
+	return <-s.input
+}
+
+// update displays the themes that match the current filter, in order
+// of how well they match.
+func (s *SwitchTheme) update() {
+	matches := s.names
+	if partial := s.filter.Text(); partial != "" {
+		matches = scoring.Sort(append([]string(nil), s.names...), partial)
+	}
+	s.choice = ""
+	s.matches.RemoveAll()
+	for i, m := range matches {
+		l := s.gTheme.CreateLabel()
+		l.SetMargin(math.Spacing{L: 5, R: 5})
+		l.SetText(m)
+		if i == 0 {
+			s.choice = m
+			l.SetColor(themeMatchColor)
+		}
+		s.matches.AddChild(l)
+	}
+}
+
+func (s *SwitchTheme) Reset() {
+	s.themer = nil
+}
+
+func (s *SwitchTheme) Store(elem interface{}) bind.Status {
+	themer, ok := elem.(SyntaxThemer)
+	if !ok {
+		return bind.Waiting
+	}
+	s.themer = themer
+	return bind.Done
+}
+
+func (s *SwitchTheme) Exec() error {
+	if s.choice == "" {
+		s.Err = "no themes match"
+		return fmt.Errorf("switch-theme: %s", s.Err)
+	}
+	t, err := theme.Load(setting.ThemesDir(), s.choice)
+	if err != nil {
+		s.Err = fmt.Sprintf("could not load theme %s: %s", s.choice, err)
+		return err
+	}
+	s.themer.SetSyntaxTheme(t)
+	setting.SetTheme(s.choice)
+	s.Info = fmt.Sprintf("switched to theme %s", s.choice)
+	return nil
+}
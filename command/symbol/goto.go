@@ -0,0 +1,205 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package symbol
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/navigator"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// maxShown is the number of matching symbols that are displayed
+// while filtering.
+const maxShown = 8
+
+var matchColor = gxui.Color{R: 0.4, G: 0.8, B: 0.3, A: 1}
+
+// Goto is a command which jumps to the definition of a top-level
+// symbol anywhere in the current project.  Typing filters the
+// symbols with fuzzy matching, and the best match is opened.
+type Goto struct {
+	status.General
+
+	driver gxui.Driver
+	theme  *basic.Theme
+	index  *Index
+
+	filter  gxui.TextBox
+	matches gxui.LinearLayout
+	input   <-chan gxui.Focusable
+
+	symbols []navigator.Symbol
+	choice  *navigator.Symbol
+
+	focuser Focuser
+	execer  Executor
+}
+
+func NewGoto(driver gxui.Driver, theme *basic.Theme, index *Index) *Goto {
+	g := &Goto{
+		driver:  driver,
+		theme:   theme,
+		index:   index,
+		filter:  theme.CreateTextBox(),
+		matches: theme.CreateLinearLayout(),
+	}
+	g.Theme = theme
+	g.filter.SetDesiredWidth(math.MaxSize.W)
+	g.filter.OnTextChanged(func([]gxui.TextBoxEdit) {
+		g.update()
+	})
+	g.matches.SetDirection(gxui.LeftToRight)
+	return g
+}
+
+func (g *Goto) Name() string {
+	return "goto-symbol"
+}
+
+func (g *Goto) Menu() string {
+	return "Edit"
+}
+
+func (g *Goto) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl,
+		Key:      gxui.KeyT,
+	}}
+}
+
+// SetProject changes the directories that g's index is built from.
+// It is called when the project changes.
+func (g *Goto) SetProject(p setting.Project) {
+	dirs := p.Dirs()
+	if sameDirs(dirs, g.index.Roots()) {
+		return
+	}
+	g.index.SetRoots(dirs...)
+}
+
+func sameDirs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Goto) Start(gxui.Control) gxui.Control {
+	g.symbols = g.index.Symbols()
+	g.filter.SetText("")
+	g.update()
+
+	input := make(chan gxui.Focusable, 1)
+	input <- g.filter
+	close(input)
+	g.input = input
+	return g.matches
+}
+
+func (g *Goto) Next() gxui.Focusable {
+	return <-g.input
+}
+
+// update displays the symbols that match the current filter, in
+// order of how well they match.
+func (g *Goto) update() {
+	g.choice = nil
+	g.matches.RemoveAll()
+	partial := []rune(g.filter.Text())
+	if len(partial) == 0 {
+		return
+	}
+	matches := Match(g.symbols, partial)
+	for i, m := range matches {
+		if i == maxShown {
+			break
+		}
+		l := g.theme.CreateLabel()
+		l.SetMargin(math.Spacing{L: 5, R: 5})
+		if i == 0 {
+			// The location of the symbol that will be opened is
+			// shown, so that it's clear which one it is.
+			g.choice = &matches[i]
+			l.SetText(fmt.Sprintf("%s (%s:%d)", m.Name, g.relative(m.File()), m.Position().Line))
+			l.SetColor(matchColor)
+		} else {
+			l.SetText(m.Name)
+		}
+		g.matches.AddChild(l)
+	}
+}
+
+// Suggest returns the names of the symbols that match partial, for tab
+// completion.
+func (g *Goto) Suggest(partial string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range Match(g.symbols, []rune(partial)) {
+		if len(names) == maxShown {
+			break
+		}
+		if seen[m.Name] {
+			continue
+		}
+		seen[m.Name] = true
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+func (g *Goto) relative(path string) string {
+	root := g.index.Root(path)
+	if root == "" {
+		return path
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	if len(g.index.Roots()) > 1 {
+		rel = filepath.Join(filepath.Base(root), rel)
+	}
+	return rel
+}
+
+func (g *Goto) Reset() {
+	g.focuser = nil
+	g.execer = nil
+}
+
+func (g *Goto) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Focuser:
+		g.focuser = src
+	case Executor:
+		g.execer = src
+	}
+	if g.focuser == nil || g.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Executing
+}
+
+func (g *Goto) Exec() error {
+	if g.choice == nil {
+		g.Err = "no symbols match"
+		return fmt.Errorf("symbol: %s", g.Err)
+	}
+	g.execer.Execute(g.focuser.For(focus.Path(g.choice.File()), focus.Offset(g.choice.Position().Offset)))
+	return nil
+}
@@ -0,0 +1,267 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package symbol
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/nelsam/vidar/fsw"
+	"github.com/nelsam/vidar/navigator"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Index keeps track of the top-level symbols declared in every go
+// file under a set of root directories.  Directories are watched for changes,
+// so that the index stays up to date without rescanning the project
+// each time it's searched.
+type Index struct {
+	mu    sync.RWMutex
+	roots []string
+	names Trie
+
+	// files holds the names of the symbols declared in each file, so
+	// that they can be removed from names when the file changes.
+	files map[string][]string
+
+	watchMu sync.Mutex
+	watcher fsw.Watcher
+}
+
+// NewIndex returns an *Index with no roots.  It won't contain any
+// symbols until SetRoot or SetRoots is called.
+func NewIndex() *Index {
+	i := &Index{files: make(map[string][]string)}
+	w, err := fsw.New()
+	if err != nil {
+		log.Printf("WARNING: symbol index: could not create watcher: %s", err)
+		return i
+	}
+	i.watcher = w
+	go i.watch(w)
+	return i
+}
+
+// SetRoot replaces the contents of i with the symbols under root.
+// The scan runs in the background, so the index fills in over time.
+func (i *Index) SetRoot(root string) {
+	if root == "" {
+		i.SetRoots()
+		return
+	}
+	i.SetRoots(root)
+}
+
+// SetRoots is like SetRoot, but indexes the symbols under each of
+// roots.
+func (i *Index) SetRoots(roots ...string) {
+	i.mu.Lock()
+	i.roots = roots
+	i.names = Trie{}
+	i.files = make(map[string][]string)
+	i.mu.Unlock()
+
+	i.watchMu.Lock()
+	if i.watcher != nil {
+		if err := i.watcher.RemoveAll(); err != nil {
+			log.Printf("WARNING: symbol index: could not remove watches: %s", err)
+		}
+	}
+	i.watchMu.Unlock()
+
+	for _, root := range roots {
+		go i.Scan(root)
+	}
+}
+
+// Scan adds the symbols from every go file under dir to i.  It is
+// safe to call on any goroutine, and stops early if i's roots change
+// to directories that don't contain dir or the scan is canceled with
+// cancel-task.
+func (i *Index) Scan(dir string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer status.Start("indexing symbols", cancel).Done()
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !i.within(path) {
+			return filepath.SkipDir
+		}
+		if info.IsDir() {
+			if path != dir && i.skip(path) {
+				return filepath.SkipDir
+			}
+			i.add(path)
+			return nil
+		}
+		i.parse(path)
+		return nil
+	})
+}
+
+// Symbols returns every symbol in i, sorted by name.
+func (i *Index) Symbols() []navigator.Symbol {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.names.Symbols()
+}
+
+// Roots returns the directories that i is indexing.
+func (i *Index) Roots() []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.roots
+}
+
+// Root returns the root directory that contains path, or an empty
+// string if none of i's roots do.
+func (i *Index) Root(path string) string {
+	var root string
+	for _, r := range i.Roots() {
+		if len(r) > len(root) && (path == r || strings.HasPrefix(path, r+string(filepath.Separator))) {
+			root = r
+		}
+	}
+	return root
+}
+
+func (i *Index) within(path string) bool {
+	return i.Root(path) != ""
+}
+
+// parse replaces the symbols for path.  Files that can't be parsed
+// keep the symbols they had when they last parsed, since they're
+// usually in the middle of being edited.
+func (i *Index) parse(path string) {
+	if !strings.HasSuffix(path, ".go") {
+		return
+	}
+	syms, err := navigator.ParseSymbols(path)
+	if err != nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(path)
+	names := make([]string, 0, len(syms))
+	for _, s := range syms {
+		i.names.Insert(s)
+		names = append(names, s.Name)
+	}
+	i.files[path] = names
+}
+
+// remove removes the symbols declared in path from i.  i.mu must be
+// held while calling remove.
+func (i *Index) remove(path string) {
+	for _, name := range i.files[path] {
+		i.names.Remove(name, path)
+	}
+	delete(i.files, path)
+}
+
+// forget removes path from i.  If path is a directory, every file
+// under it is removed.
+func (i *Index) forget(path string) {
+	navigator.ForgetSymbols(path)
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	prefix := path + string(filepath.Separator)
+	for f := range i.files {
+		if f == path || strings.HasPrefix(f, prefix) {
+			i.remove(f)
+		}
+	}
+}
+
+func (i *Index) add(dir string) {
+	i.watchMu.Lock()
+	defer i.watchMu.Unlock()
+	if i.watcher == nil {
+		return
+	}
+	err := i.watcher.Add(dir)
+	if fsw.IsWatchLimit(err) {
+		log.Printf("WARNING: symbol index: watch limit reached; falling back to polling")
+		i.startPolling()
+		err = i.watcher.Add(dir)
+	}
+	if err != nil {
+		log.Printf("WARNING: symbol index: could not watch %s: %s", dir, err)
+	}
+}
+
+// startPolling replaces i.watcher with a polling watcher.  Since a
+// poller can't take over the old watcher's directories, i is
+// rescanned.  i.watchMu must be held while calling startPolling.
+func (i *Index) startPolling() {
+	if err := i.watcher.Close(); err != nil {
+		log.Printf("WARNING: symbol index: error closing watcher: %s", err)
+	}
+	poller := fsw.NewPoller(setting.PollInterval())
+	i.watcher = poller
+	go i.watch(poller)
+	for _, root := range i.Roots() {
+		go i.Scan(root)
+	}
+}
+
+// watch updates i for each event from w, until w is closed.
+func (i *Index) watch(w fsw.Watcher) {
+	for {
+		e, err := w.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.Printf("WARNING: symbol index: error from watcher: %s", err)
+			continue
+		}
+		if !i.within(e.Path) {
+			continue
+		}
+		switch e.Op {
+		case fsw.Write:
+			i.parse(e.Path)
+		case fsw.Create:
+			info, err := os.Stat(e.Path)
+			if err != nil {
+				continue
+			}
+			if info.IsDir() {
+				if !i.skip(e.Path) {
+					go i.Scan(e.Path)
+				}
+				continue
+			}
+			i.parse(e.Path)
+		case fsw.Remove, fsw.Rename:
+			i.forget(e.Path)
+		}
+	}
+}
+
+// skip returns whether or not the directory at path should be left
+// out of the index.
+func (i *Index) skip(path string) bool {
+	return skipDir(filepath.Base(path)) || setting.IgnoredDir(i.Root(path), path)
+}
+
+// skipDir returns whether or not directories named name should be
+// left out of the index, regardless of the project's settings.
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata"
+}
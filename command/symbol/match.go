@@ -0,0 +1,38 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package symbol
+
+import (
+	"math"
+	"sort"
+
+	"github.com/nelsam/vidar/navigator"
+	"github.com/nelsam/vidar/scoring"
+)
+
+// Match returns the symbols in syms with names that fuzzy match
+// partial, best match first.
+func Match(syms []navigator.Symbol, partial []rune) []navigator.Symbol {
+	type scored struct {
+		sym   navigator.Symbol
+		score float64
+	}
+	var found []scored
+	for _, s := range syms {
+		score := scoring.Score([]rune(s.Name), partial)
+		if score == math.MaxFloat64 {
+			continue
+		}
+		found = append(found, scored{sym: s, score: score})
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].score < found[j].score
+	})
+	matches := make([]navigator.Symbol, 0, len(found))
+	for _, f := range found {
+		matches = append(matches, f.sym)
+	}
+	return matches
+}
@@ -0,0 +1,134 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package symbol_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nelsam/vidar/command/symbol"
+	"github.com/nelsam/vidar/navigator"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	not          = matchers.Not
+	equal        = matchers.Equal
+	haveLen      = matchers.HaveLen
+	haveOccurred = matchers.HaveOccurred
+)
+
+func names(syms []navigator.Symbol) []string {
+	var n []string
+	for _, s := range syms {
+		n = append(n, s.Name)
+	}
+	return n
+}
+
+func TestIndex(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, string) {
+		dir, err := ioutil.TempDir("", "symbol-index")
+		if err != nil {
+			t.Fatal(err)
+		}
+		write := func(name, src string) {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		write("foo.go", "package foo\n\nconst Bar = 1\n\ntype Baz struct{}\n\nfunc (b *Baz) Qux() {}\n")
+		write("sub/sub.go", "package sub\n\nvar unexported int\n\nfunc init() {}\n")
+		write(".hidden/hidden.go", "package hidden\n\nfunc Hidden() {}\n")
+		write("vendor/v/v.go", "package v\n\nfunc Vendored() {}\n")
+		write("README.md", "# foo\n")
+		return expect.New(t), dir
+	})
+
+	o.AfterEach(func(_ expect.Expectation, dir string) {
+		os.RemoveAll(dir)
+	})
+
+	o.Spec("it indexes top-level symbols under its root", func(expect expect.Expectation, dir string) {
+		i := symbol.NewIndex()
+		i.SetRoot(dir)
+		i.Scan(dir)
+		expect(names(i.Symbols())).To(equal([]string{"(*Baz) Qux", "Bar", "Baz", "unexported"}))
+	})
+
+	o.Spec("it replaces the symbols of files that change", func(expect expect.Expectation, dir string) {
+		i := symbol.NewIndex()
+		i.SetRoot(dir)
+		i.Scan(dir)
+		path := filepath.Join(dir, "foo.go")
+		err := ioutil.WriteFile(path, []byte("package foo\n\nfunc Quux() {}\n"), 0644)
+		expect(err).To(not(haveOccurred()))
+		i.Scan(dir)
+		expect(names(i.Symbols())).To(equal([]string{"Quux", "unexported"}))
+	})
+
+	o.Spec("it ignores directories outside of its root", func(expect expect.Expectation, dir string) {
+		i := symbol.NewIndex()
+		i.Scan(dir)
+		expect(i.Symbols()).To(haveLen(0))
+	})
+}
+
+func TestMatch(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it sorts fuzzy matches by score", func(expect expect.Expectation) {
+		syms := []navigator.Symbol{{Name: "NewProjectTree"}, {Name: "Unrelated"}, {Name: "ProjectTree"}}
+		expect(names(symbol.Match(syms, []rune("projtree")))).To(equal([]string{"ProjectTree", "NewProjectTree"}))
+	})
+}
+
+func TestTrie(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *symbol.Trie) {
+		tr := &symbol.Trie{}
+		for _, n := range []string{"NewProjectTree", "ProjectTree", "New", "NewProject", "Project", "(*T) M"} {
+			tr.Insert(navigator.Symbol{Name: n})
+		}
+		return expect.New(t), tr
+	})
+
+	o.Spec("it keeps symbols sorted by name", func(expect expect.Expectation, tr *symbol.Trie) {
+		expect(names(tr.Symbols())).To(equal([]string{"(*T) M", "New", "NewProject", "NewProjectTree", "Project", "ProjectTree"}))
+	})
+
+	o.Spec("it finds symbols by prefix", func(expect expect.Expectation, tr *symbol.Trie) {
+		expect(names(tr.Prefix("NewProj"))).To(equal([]string{"NewProject", "NewProjectTree"}))
+		expect(names(tr.Prefix("Pro"))).To(equal([]string{"Project", "ProjectTree"}))
+		expect(names(tr.Prefix("Projects"))).To(haveLen(0))
+		expect(tr.Prefix("x")).To(haveLen(0))
+	})
+
+	o.Spec("it removes symbols without losing the ones that share a prefix", func(expect expect.Expectation, tr *symbol.Trie) {
+		tr.Remove("NewProject", "")
+		tr.Remove("Project", "")
+		tr.Remove("Missing", "")
+		expect(names(tr.Symbols())).To(equal([]string{"(*T) M", "New", "NewProjectTree", "ProjectTree"}))
+		expect(names(tr.Prefix("NewP"))).To(equal([]string{"NewProjectTree"}))
+	})
+}
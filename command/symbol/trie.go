@@ -0,0 +1,165 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package symbol
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nelsam/vidar/navigator"
+)
+
+// Trie holds symbols keyed by name.  Names that share a prefix share
+// the nodes for it, so symbols are always kept in name order without
+// sorting the whole set, and looking up the symbols with a given prefix
+// doesn't visit any of the others.
+//
+// The zero value is an empty Trie.  A Trie is not safe for concurrent
+// use.
+type Trie struct {
+	root trieNode
+}
+
+type trieNode struct {
+	// edge is the part of the name between the parent node and this
+	// one.  Only the root has an empty edge.
+	edge string
+
+	// children are sorted by the first byte of their edge, which is
+	// unique among siblings.
+	children []*trieNode
+
+	// syms are the symbols whose name ends at this node, sorted by
+	// file.
+	syms []navigator.Symbol
+}
+
+// child returns the index of n's child with an edge starting with b,
+// or the index that such a child should be inserted at.
+func (n *trieNode) child(b byte) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].edge[0] >= b
+	})
+	return i, i < len(n.children) && n.children[i].edge[0] == b
+}
+
+// Insert adds s to t.
+func (t *Trie) Insert(s navigator.Symbol) {
+	n := &t.root
+	key := s.Name
+	for key != "" {
+		i, ok := n.child(key[0])
+		if !ok {
+			leaf := &trieNode{edge: key}
+			n.children = append(n.children, nil)
+			copy(n.children[i+1:], n.children[i:])
+			n.children[i] = leaf
+			n = leaf
+			break
+		}
+		c := n.children[i]
+		l := commonPrefix(c.edge, key)
+		if l < len(c.edge) {
+			split := &trieNode{edge: c.edge[:l], children: []*trieNode{c}}
+			c.edge = c.edge[l:]
+			n.children[i] = split
+			c = split
+		}
+		n, key = c, key[l:]
+	}
+	i := sort.Search(len(n.syms), func(i int) bool {
+		return n.syms[i].File() > s.File()
+	})
+	n.syms = append(n.syms, navigator.Symbol{})
+	copy(n.syms[i+1:], n.syms[i:])
+	n.syms[i] = s
+}
+
+// Remove removes the symbols named name that were declared in file.
+func (t *Trie) Remove(name, file string) {
+	t.root.remove(name, file)
+}
+
+func (n *trieNode) remove(key, file string) {
+	if key == "" {
+		kept := n.syms[:0]
+		for _, s := range n.syms {
+			if s.File() != file {
+				kept = append(kept, s)
+			}
+		}
+		for i := len(kept); i < len(n.syms); i++ {
+			n.syms[i] = navigator.Symbol{}
+		}
+		n.syms = kept
+		return
+	}
+	i, ok := n.child(key[0])
+	if !ok || !strings.HasPrefix(key, n.children[i].edge) {
+		return
+	}
+	c := n.children[i]
+	c.remove(key[len(c.edge):], file)
+	if len(c.syms) > 0 {
+		return
+	}
+	switch len(c.children) {
+	case 0:
+		n.children = append(n.children[:i], n.children[i+1:]...)
+	case 1:
+		// c no longer marks the end of a name, so it can be merged
+		// with its only child.
+		gc := c.children[0]
+		gc.edge = c.edge + gc.edge
+		n.children[i] = gc
+	}
+}
+
+// Symbols returns every symbol in t, sorted by name and then by file.
+func (t *Trie) Symbols() []navigator.Symbol {
+	var syms []navigator.Symbol
+	t.root.collect(&syms)
+	return syms
+}
+
+// Prefix returns the symbols in t with names starting with prefix,
+// sorted by name and then by file.
+func (t *Trie) Prefix(prefix string) []navigator.Symbol {
+	n := &t.root
+	for prefix != "" {
+		i, ok := n.child(prefix[0])
+		if !ok {
+			return nil
+		}
+		c := n.children[i]
+		switch {
+		case strings.HasPrefix(prefix, c.edge):
+			prefix = prefix[len(c.edge):]
+		case strings.HasPrefix(c.edge, prefix):
+			prefix = ""
+		default:
+			return nil
+		}
+		n = c
+	}
+	var syms []navigator.Symbol
+	n.collect(&syms)
+	return syms
+}
+
+func (n *trieNode) collect(syms *[]navigator.Symbol) {
+	*syms = append(*syms, n.syms...)
+	for _, c := range n.children {
+		c.collect(syms)
+	}
+}
+
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,171 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package tabs
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// closer is embedded by the commands which close a group of tabs.
+type closer struct {
+	status.General
+
+	tabs   Tabs
+	execer Executor
+}
+
+func (c *closer) Menu() string {
+	return "File"
+}
+
+func (c *closer) Reset() {
+	c.Clear()
+	c.tabs = nil
+	c.execer = nil
+}
+
+func (c *closer) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Tabs:
+		c.tabs = src
+	case Executor:
+		c.execer = src
+	}
+	if c.tabs == nil || c.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+// close closes editors, focusing keep afterward, and reports how many
+// tabs were closed.
+func (c *closer) close(editors []input.Editor, keep input.Editor) {
+	switch closed := closeTabs(c.tabs, c.execer, editors, keep); closed {
+	case 0:
+		c.Info = "No tabs to close"
+	case 1:
+		c.Info = "Closed 1 tab"
+	default:
+		c.Info = fmt.Sprintf("Closed %d tabs", closed)
+	}
+}
+
+// CloseOthers is a command which closes every unpinned tab except the
+// current one.
+type CloseOthers struct {
+	closer
+}
+
+func NewCloseOthers(theme gxui.Theme) *CloseOthers {
+	c := &CloseOthers{}
+	c.Theme = theme
+	return c
+}
+
+func (c *CloseOthers) Name() string {
+	return "close-other-tabs"
+}
+
+func (c *CloseOthers) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyW,
+	}}
+}
+
+func (c *CloseOthers) Exec() error {
+	current := c.tabs.CurrentEditor()
+	var others []input.Editor
+	for _, e := range c.tabs.TabEditors() {
+		if e != current && !pinned(e) {
+			others = append(others, e)
+		}
+	}
+	c.close(others, current)
+	return nil
+}
+
+// CloseRight is a command which closes every unpinned tab after the
+// current one.
+type CloseRight struct {
+	closer
+}
+
+func NewCloseRight(theme gxui.Theme) *CloseRight {
+	c := &CloseRight{}
+	c.Theme = theme
+	return c
+}
+
+func (c *CloseRight) Name() string {
+	return "close-tabs-to-right"
+}
+
+func (c *CloseRight) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (c *CloseRight) Exec() error {
+	current := c.tabs.CurrentEditor()
+	var (
+		right []input.Editor
+		after bool
+	)
+	for _, e := range c.tabs.TabEditors() {
+		if e == current {
+			after = true
+			continue
+		}
+		if after && !pinned(e) {
+			right = append(right, e)
+		}
+	}
+	c.close(right, current)
+	return nil
+}
+
+// CloseSaved is a command which closes every unpinned tab that has no
+// unsaved changes, including the current one.
+type CloseSaved struct {
+	closer
+}
+
+func NewCloseSaved(theme gxui.Theme) *CloseSaved {
+	c := &CloseSaved{}
+	c.Theme = theme
+	return c
+}
+
+func (c *CloseSaved) Name() string {
+	return "close-saved-tabs"
+}
+
+func (c *CloseSaved) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (c *CloseSaved) Exec() error {
+	current := c.tabs.CurrentEditor()
+	var saved []input.Editor
+	for _, e := range c.tabs.TabEditors() {
+		if pinned(e) {
+			continue
+		}
+		if changer, ok := e.(Changer); ok && changer.HasChanges() {
+			continue
+		}
+		if e == current {
+			current = nil
+		}
+		saved = append(saved, e)
+	}
+	c.close(saved, current)
+	return nil
+}
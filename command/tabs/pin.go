@@ -0,0 +1,81 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package tabs
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// TogglePin is a command which pins the current tab, or unpins it if
+// it's already pinned.  Pinned tabs are kept before all other tabs,
+// and close-current-tab won't close them.
+type TogglePin struct {
+	status.General
+
+	pinner Pinner
+	tabs   Tabs
+}
+
+func NewTogglePin(theme gxui.Theme) *TogglePin {
+	p := &TogglePin{}
+	p.Theme = theme
+	return p
+}
+
+func (p *TogglePin) Name() string {
+	return "toggle-pin-tab"
+}
+
+func (p *TogglePin) Menu() string {
+	return "File"
+}
+
+func (p *TogglePin) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModAlt,
+		Key:      gxui.KeyP,
+	}}
+}
+
+func (p *TogglePin) Reset() {
+	p.Clear()
+	p.pinner = nil
+	p.tabs = nil
+}
+
+func (p *TogglePin) Store(elem interface{}) bind.Status {
+	if pinner, ok := elem.(Pinner); ok {
+		p.pinner = pinner
+	}
+	if tabs, ok := elem.(Tabs); ok {
+		p.tabs = tabs
+	}
+	if p.pinner == nil || p.tabs == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (p *TogglePin) Exec() error {
+	e := p.tabs.CurrentEditor()
+	if e == nil {
+		p.Warn = "No tab to pin"
+		return nil
+	}
+	name := filepath.Base(e.Filepath())
+	if pinned(e) {
+		p.pinner.SetPinned(e, false)
+		p.Info = fmt.Sprintf("Unpinned %s", name)
+		return nil
+	}
+	p.pinner.SetPinned(e, true)
+	p.Info = fmt.Sprintf("Pinned %s", name)
+	return nil
+}
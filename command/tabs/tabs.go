@@ -0,0 +1,91 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package tabs contains commands that act on the tabs around the
+// current editor: closing groups of them and pinning them.
+package tabs
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, _ gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	return []bind.Bindable{
+		NewCloseOthers(theme),
+		NewCloseRight(theme),
+		NewCloseSaved(theme),
+		NewTogglePin(theme),
+	}
+}
+
+// Tabs is the group of tabs that the current editor is in.
+type Tabs interface {
+	TabEditors() []input.Editor
+	SelectEditor(input.Editor)
+	CurrentEditor() input.Editor
+}
+
+// A Pinner is a group of tabs which can pin them.
+type Pinner interface {
+	SetPinned(input.Editor, bool)
+}
+
+// A Pinnable is an editor whose tab may be pinned.
+type Pinnable interface {
+	Pinned() bool
+}
+
+// A Changer is an editor which tracks unsaved changes.
+type Changer interface {
+	HasChanges() bool
+}
+
+// An Executor is a type that can look up and execute bindables.
+type Executor interface {
+	Bindable(string) bind.Bindable
+	Execute(bind.Bindable)
+}
+
+// A Focuser is a type that can create a bindable which focuses a
+// file.
+type Focuser interface {
+	For(...focus.Opt) bind.Bindable
+}
+
+func pinned(e input.Editor) bool {
+	p, ok := e.(Pinnable)
+	return ok && p.Pinned()
+}
+
+// closeTabs closes each of editors by selecting it and executing
+// close-current-tab, so that the hooks on close-current-tab run for
+// each of them.  If keep is still open afterward, it is focused
+// again.  closeTabs returns the number of tabs that were closed.
+func closeTabs(tabs Tabs, execer Executor, editors []input.Editor, keep input.Editor) int {
+	closer := execer.Bindable("close-current-tab")
+	if closer == nil {
+		return 0
+	}
+	closed := 0
+	for _, e := range editors {
+		tabs.SelectEditor(e)
+		execer.Execute(closer)
+		closed++
+	}
+	if keep == nil || tabs.CurrentEditor() == nil {
+		return closed
+	}
+	tabs.SelectEditor(keep)
+	if focuser, ok := execer.Bindable("focus-location").(Focuser); ok {
+		execer.Execute(focuser.For(focus.Path(keep.Filepath())))
+	}
+	return closed
+}
@@ -0,0 +1,109 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/dpi"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// minUIScale is the smallest scale that can be set at runtime.
+const minUIScale = 0.5
+
+// A UIScaler is a window that can update its scale from the uiscale
+// setting.
+type UIScaler interface {
+	UpdateScale() float32
+}
+
+// SetUIScale is a command which sets the scale that the UI is drawn
+// at, overriding the scale detected from the monitor's DPI.  Entering
+// "auto" (or 0) goes back to the detected scale.  The scale is saved
+// to the settings file, so every window uses it.
+type SetUIScale struct {
+	status.General
+
+	scaleInput gxui.TextBox
+	input      gxui.Focusable
+
+	scaler UIScaler
+}
+
+func NewSetUIScale(theme gxui.Theme) *SetUIScale {
+	s := &SetUIScale{scaleInput: theme.CreateTextBox()}
+	s.Theme = theme
+	return s
+}
+
+func (s *SetUIScale) Name() string {
+	return "set-ui-scale"
+}
+
+func (s *SetUIScale) Menu() string {
+	return "View"
+}
+
+func (s *SetUIScale) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (s *SetUIScale) Start(gxui.Control) gxui.Control {
+	current := "auto"
+	if scale := setting.UIScale(); scale > 0 {
+		current = strconv.FormatFloat(float64(scale), 'g', -1, 32)
+	}
+	s.scaleInput.SetText(current)
+	s.scaleInput.SelectAll()
+	s.input = s.scaleInput
+	return nil
+}
+
+func (s *SetUIScale) Next() gxui.Focusable {
+	input := s.input
+	s.input = nil
+	return input
+}
+
+func (s *SetUIScale) Reset() {
+	s.Clear()
+	s.scaler = nil
+}
+
+func (s *SetUIScale) Store(elem interface{}) bind.Status {
+	scaler, ok := elem.(UIScaler)
+	if !ok {
+		return bind.Waiting
+	}
+	s.scaler = scaler
+	return bind.Done
+}
+
+func (s *SetUIScale) Exec() error {
+	text := strings.ToLower(strings.TrimSpace(s.scaleInput.Text()))
+	var scale float64
+	if text != "auto" && text != "" {
+		var err error
+		scale, err = strconv.ParseFloat(text, 32)
+		if err != nil || scale != 0 && (scale < minUIScale || scale > dpi.MaxScale) {
+			s.Warn = fmt.Sprintf("The UI scale must be auto or between %g and %d", minUIScale, dpi.MaxScale)
+			return nil
+		}
+	}
+	setting.SetUIScale(float32(scale))
+	applied := s.scaler.UpdateScale()
+	if scale == 0 {
+		s.Info = fmt.Sprintf("UI scale is now detected (%g)", applied)
+		return nil
+	}
+	s.Info = fmt.Sprintf("UI scale is now %g", applied)
+	return nil
+}
@@ -20,6 +20,12 @@ func (h ViewHook) FileBindables(string) []bind.Bindable {
 	return []bind.Bindable{
 		NewHorizontalSplit(),
 		NewVerticalSplit(),
+		NewHorizontalFileSplit(),
+		NewVerticalFileSplit(),
+		NewGrowPane(),
+		NewShrinkPane(),
+		EqualizePanes{},
+		MaximizePane{},
 		NewNextTab(),
 		NewPrevTab(),
 		NewFocusUp(),
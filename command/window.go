@@ -0,0 +1,126 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// A WindowOpener is a window that can open more windows.
+type WindowOpener interface {
+	OpenWindow()
+}
+
+// NewWindow is a command which opens another window.  The new window
+// has its own commander, splits, and navigator, but shares settings
+// and plugins with the rest.
+type NewWindow struct{}
+
+func (NewWindow) Name() string {
+	return "new-window"
+}
+
+func (NewWindow) Menu() string {
+	return "File"
+}
+
+func (NewWindow) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (NewWindow) Exec(e interface{}) bind.Status {
+	w, ok := e.(WindowOpener)
+	if !ok {
+		return bind.Waiting
+	}
+	w.OpenWindow()
+	return bind.Done
+}
+
+// A WindowMover is a window that can open files in another window.
+type WindowMover interface {
+	OpenInOtherWindow(path string, offset int)
+}
+
+// MoveToWindow is a command which moves the current tab to the next
+// window, opening a new window if there is only one.  Tabs with unsaved
+// changes have to be saved first, since windows don't share buffers.
+type MoveToWindow struct {
+	status.General
+
+	closer CurrentEditorCloser
+	binder BindPopper
+	mover  WindowMover
+}
+
+func NewMoveToWindow(theme gxui.Theme) *MoveToWindow {
+	m := &MoveToWindow{}
+	m.Theme = theme
+	return m
+}
+
+func (m *MoveToWindow) Name() string {
+	return "move-tab-to-window"
+}
+
+func (m *MoveToWindow) Menu() string {
+	return "File"
+}
+
+func (m *MoveToWindow) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (m *MoveToWindow) Reset() {
+	m.Clear()
+	m.closer = nil
+	m.binder = nil
+	m.mover = nil
+}
+
+func (m *MoveToWindow) Store(target interface{}) bind.Status {
+	switch src := target.(type) {
+	case WindowMover:
+		m.mover = src
+	case CurrentEditorCloser:
+		m.closer = src
+	case BindPopper:
+		m.binder = src
+	}
+	if m.mover != nil && m.closer != nil && m.binder != nil {
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (m *MoveToWindow) Exec() error {
+	e := m.closer.CurrentEditor()
+	if e == nil {
+		m.Err = "there is no tab to move"
+		return fmt.Errorf("move-tab-to-window: %s", m.Err)
+	}
+	path := e.Filepath()
+	if c, ok := e.(interface{ HasChanges() bool }); ok && c.HasChanges() {
+		m.Err = fmt.Sprintf("save %s before moving it to another window", filepath.Base(path))
+		return fmt.Errorf("move-tab-to-window: %s", m.Err)
+	}
+	offset := 0
+	if c, ok := e.(interface{ Carets() []int }); ok {
+		if carets := c.Carets(); len(carets) > 0 {
+			offset = carets[0]
+		}
+	}
+	m.closer.CloseCurrentEditor()
+	if m.closer.CurrentEditor() == nil {
+		m.binder.Pop()
+	}
+	m.mover.OpenInOtherWindow(path, offset)
+	return nil
+}
@@ -0,0 +1,47 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+)
+
+// A Zener is a type that can hide everything but the editor.
+type Zener interface {
+	Zen() bool
+	SetZen(bool)
+}
+
+// ToggleZenMode is a command which toggles zen mode, a distraction
+// free view that hides the navigator, tabs, menu, and status bar and
+// centers the editor.
+type ToggleZenMode struct{}
+
+func (ToggleZenMode) Name() string {
+	return "toggle-zen-mode"
+}
+
+func (ToggleZenMode) Menu() string {
+	return "View"
+}
+
+func (ToggleZenMode) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModShift,
+		Key:      gxui.KeyF11,
+	}}
+}
+
+func (ToggleZenMode) Exec(target interface{}) bind.Status {
+	z, ok := target.(Zener)
+	if !ok {
+		return bind.Waiting
+	}
+	z.SetZen(!z.Zen())
+	return bind.Done
+}
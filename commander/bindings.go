@@ -45,13 +45,34 @@ type InputQueue interface {
 // This allows them to consume enter events as newlines or trigger
 // completeness off of key presses other than enter.
 type Completer interface {
-	bind.Command
-
 	// Complete returns whether or not the event signals a completion
 	// of the input.
 	Complete(gxui.KeyboardEvent) bool
 }
 
+// A TextInput is a type that may optionally be implemented by types
+// returned from InputQueue.Next() which hold a line of text.  The
+// commander keeps a history of the text that each of a command's
+// inputs was completed with, which up and down step through, and the
+// last value is filled in when the input starts out empty.  Inputs
+// that implement Completer handle their own keys and are left alone.
+type TextInput interface {
+	gxui.Focusable
+
+	Text() string
+	SetText(string)
+}
+
+// A Suggester is a type of Command which can suggest completions for
+// the text of its inputs.  When tab is pressed in an input that
+// implements TextInput, the text is completed from the suggestions.
+type Suggester interface {
+	bind.Command
+
+	// Suggest returns completions for text, best first.
+	Suggest(text string) []string
+}
+
 // A Statuser is a Bindable that needs to display its status after being
 // run.  The bindings should use their discretion for status colors,
 // but colors for some common message types are exported by this
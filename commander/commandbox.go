@@ -10,6 +10,7 @@ import (
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/mixins"
 	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/prompt"
 )
 
 const maxStatusAge = 5 * time.Second
@@ -41,13 +42,27 @@ type commandBox struct {
 	input   gxui.Focusable
 	status  gxui.Control
 
+	// shown is the control that input is displayed with, which wraps
+	// it when it's a TextInput.  inputIndex is the index of input in
+	// the inputs of current, and histories holds the history of each
+	// command's inputs.
+	shown      gxui.Focusable
+	inputIndex int
+	histories  map[historyKey]*prompt.History
+
 	statusTimer *time.Timer
+
+	// keeps reports whether or not an event is bound to a command
+	// that runs without closing the prompt, so that the event can
+	// reach the commander while the prompt has focus.
+	keeps func(gxui.KeyboardEvent) bool
 }
 
 func newCommandBox(driver gxui.Driver, theme gxui.Theme, controller Controller) *commandBox {
 	box := &commandBox{
 		driver:     driver,
 		controller: controller,
+		histories:  make(map[historyKey]*prompt.History),
 	}
 
 	box.label = theme.CreateLabel()
@@ -94,12 +109,23 @@ func (b *commandBox) Clear() {
 	b.current = nil
 }
 
+// Chord displays first as the start of a chord that is waiting for
+// its next key.
+func (b *commandBox) Chord(first gxui.KeyboardEvent) {
+	b.Clear()
+	if b.statusTimer != nil {
+		b.statusTimer.Stop()
+	}
+	b.label.SetText(first.String() + " ...")
+}
+
 func (b *commandBox) Run(command bind.Command) (needsInput bool) {
 	b.Clear()
 	if b.statusTimer != nil {
 		b.statusTimer.Stop()
 	}
 	b.current = command
+	b.inputIndex = -1
 
 	b.label.SetText(b.current.Name())
 	b.startCurrent()
@@ -133,12 +159,16 @@ func (b *commandBox) KeyPress(event gxui.KeyboardEvent) (consume bool) {
 	if event.Modifier == 0 && event.Key == gxui.KeyEscape {
 		return false
 	}
+	if b.keeps != nil && b.keeps(event) {
+		return false
+	}
 	isEnter := event.Modifier == 0 && event.Key == gxui.KeyEnter
 	complete := isEnter
 	if completer, ok := b.input.(Completer); ok {
 		complete = completer.Complete(event)
 	}
 	if complete {
+		b.remember()
 		hasMore := b.nextInput()
 		complete = !hasMore
 	}
@@ -164,8 +194,9 @@ func (b *commandBox) clearInput() {
 	if b.input == nil {
 		return
 	}
-	b.RemoveChild(b.input)
+	b.RemoveChild(b.shown)
 	b.input = nil
+	b.shown = nil
 }
 
 func (b *commandBox) clearStatus() {
@@ -186,8 +217,44 @@ func (b *commandBox) nextInput() (more bool) {
 		return false
 	}
 	b.clearInput()
+	b.inputIndex++
 	b.input = next
-	b.AddChild(b.input)
-	gxui.SetFocus(b.input)
+	b.shown = b.wrap(next)
+	b.AddChild(b.shown)
+	gxui.SetFocus(b.shown)
 	return true
 }
+
+// wrap returns the control to display input with.  Text inputs are
+// wrapped to give them a history and tab completion.
+func (b *commandBox) wrap(input gxui.Focusable) gxui.Focusable {
+	text, ok := input.(TextInput)
+	if !ok {
+		return input
+	}
+	if _, ok := input.(Completer); ok {
+		return input
+	}
+	if m, ok := input.(interface{ Multiline() bool }); ok && m.Multiline() {
+		return input
+	}
+	return newPromptInput(text, b.history(), b.current)
+}
+
+// history returns the history of the current input.
+func (b *commandBox) history() *prompt.History {
+	key := historyKey{cmd: b.current.Name(), input: b.inputIndex}
+	h, ok := b.histories[key]
+	if !ok {
+		h = prompt.NewHistory(prompt.DefaultHistorySize)
+		b.histories[key] = h
+	}
+	return h
+}
+
+// remember adds the text of the current input to its history.
+func (b *commandBox) remember() {
+	if p, ok := b.shown.(*promptInput); ok {
+		p.history.Add(p.Text())
+	}
+}
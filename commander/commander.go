@@ -9,6 +9,7 @@ import (
 	"log"
 	"runtime/debug"
 	"sync"
+	"time"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/math"
@@ -18,6 +19,7 @@ import (
 	"github.com/nelsam/vidar/commander/bind"
 	"github.com/nelsam/vidar/commander/input"
 	"github.com/nelsam/vidar/controller"
+	"github.com/nelsam/vidar/plugin/status"
 	"github.com/nelsam/vidar/setting"
 )
 
@@ -28,6 +30,12 @@ type Controller interface {
 	Editor() controller.MultiEditor
 }
 
+// A Zener is a controller that has parts of its own to hide in zen
+// mode.
+type Zener interface {
+	SetZen(bool)
+}
+
 type Controllable interface {
 	Controller() *gxui.TextBoxController
 }
@@ -49,10 +57,27 @@ type Commander struct {
 
 	lock sync.RWMutex
 
-	stack    [][]bind.Bindable
-	bound    map[string]bind.Bindable
-	commands map[gxui.KeyboardEvent]bind.Command
-	menuBar  *menuBar
+	stack     [][]bind.Bindable
+	bound     map[string]bind.Bindable
+	commands  map[gxui.KeyboardEvent]bind.Command
+	chords    map[gxui.KeyboardEvent]map[gxui.KeyboardEvent]bind.Command
+	conflicts []setting.Conflict
+	menuBar   *menuBar
+	statusBar *statusBar
+
+	// mainLayout holds the menu bar above subLayout, which holds the
+	// status bar and command box below the controller.
+	mainLayout gxui.LinearLayout
+	subLayout  gxui.LinearLayout
+	zen        bool
+
+	// chordStart is the first key of a chord that is waiting for
+	// its second key.
+	chordStart *gxui.KeyboardEvent
+
+	// skipStroke is set when a key press finishes a chord, so that
+	// the key stroke that follows it isn't typed in to the editor.
+	skipStroke bool
 }
 
 // New creates and initializes a *Commander, then returns it.
@@ -68,24 +93,89 @@ func New(driver gxui.Driver, theme *basic.Theme, root gxui.Window, controller Co
 	commander.SetBackgroundBrush(gxui.TransparentBrush)
 	commander.SetBorderPen(gxui.TransparentPen)
 
-	mainLayout := theme.CreateLinearLayout()
-
-	mainLayout.SetDirection(gxui.TopToBottom)
-	mainLayout.SetSize(math.MaxSize)
+	commander.mainLayout = theme.CreateLinearLayout()
+	commander.mainLayout.SetDirection(gxui.TopToBottom)
+	commander.mainLayout.SetSize(math.MaxSize)
 
 	commander.controller = controller
 	commander.menuBar = newMenuBar(commander, theme)
 	commander.box = newCommandBox(driver, theme, commander.controller)
+	commander.box.keeps = func(event gxui.KeyboardEvent) bool {
+		command := commander.Binding(event)
+		return command != nil && commander.keepsPrompt(command)
+	}
+	commander.statusBar = newStatusBar(theme)
+
+	commander.mainLayout.AddChild(commander.menuBar)
+
+	commander.subLayout = theme.CreateLinearLayout()
+	commander.subLayout.SetDirection(gxui.BottomToTop)
+	commander.subLayout.AddChild(commander.statusBar)
+	commander.subLayout.AddChild(commander.box)
+	commander.subLayout.AddChild(commander.controller)
+	commander.mainLayout.AddChild(commander.subLayout)
+	commander.AddChild(commander.mainLayout)
+	go commander.refreshStatus()
+	return commander
+}
 
-	mainLayout.AddChild(commander.menuBar)
+// refreshStatus refreshes the status bar every statusInterval.
+func (c *Commander) refreshStatus() {
+	ticker := time.NewTicker(statusInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.driver.Call(func() {
+			c.lock.RLock()
+			handler := c.inputHandler
+			c.lock.RUnlock()
+			var editor interface{}
+			if e := c.editor(c.controller.Editor()); e != nil {
+				editor = e
+			}
+			c.statusBar.refresh(editor, handler)
+		})
+	}
+}
 
-	subLayout := theme.CreateLinearLayout()
-	subLayout.SetDirection(gxui.BottomToTop)
-	subLayout.AddChild(commander.box)
-	subLayout.AddChild(commander.controller)
-	mainLayout.AddChild(subLayout)
-	commander.AddChild(mainLayout)
-	return commander
+// Zen returns whether or not c is in zen mode.
+func (c *Commander) Zen() bool {
+	return c.zen
+}
+
+// SetZen turns zen mode on or off.  Zen mode hides the menu and
+// status bars, and the controller's parts if it is a Zener.  Editor
+// tabs check Zen when they are laid out, so everything in the
+// controller is laid out again.  The command box is left alone, so
+// that commands can still be run.
+func (c *Commander) SetZen(zen bool) {
+	if zen == c.zen {
+		return
+	}
+	c.zen = zen
+	if zen {
+		c.mainLayout.RemoveChild(c.menuBar)
+		c.subLayout.RemoveChild(c.statusBar)
+	} else {
+		c.mainLayout.AddChildAt(0, c.menuBar)
+		c.subLayout.AddChildAt(0, c.statusBar)
+	}
+	if z, ok := c.controller.(Zener); ok {
+		z.SetZen(zen)
+	}
+	relayoutAll(c.controller)
+}
+
+// relayoutAll requests a new layout of c and every control inside of
+// it.
+func relayoutAll(c gxui.Control) {
+	c.Relayout()
+	p, ok := c.(gxui.Parent)
+	if !ok {
+		return
+	}
+	for _, child := range p.Children() {
+		relayoutAll(child.Control)
+	}
 }
 
 func (c *Commander) InputHandler() input.Handler {
@@ -139,6 +229,7 @@ func (c *Commander) Push(bindables ...bind.Bindable) {
 
 	c.stack = append(c.stack, append(c.cloneTop(), bindables...))
 	c.commands = make(map[gxui.KeyboardEvent]bind.Command)
+	c.chords = make(map[gxui.KeyboardEvent]map[gxui.KeyboardEvent]bind.Command)
 	defer c.mapBindings()
 
 	c.bindStack()
@@ -149,6 +240,7 @@ func (c *Commander) bindStack() {
 	var (
 		hooks      []bind.OpHook
 		multiHooks []bind.MultiOpHook
+		segments   []status.Segment
 	)
 	for _, b := range c.stack[len(c.stack)-1] {
 		c.bound[b.Name()] = b
@@ -158,7 +250,13 @@ func (c *Commander) bindStack() {
 		case bind.MultiOpHook:
 			multiHooks = append(multiHooks, h)
 		}
+		if seg, ok := b.(status.Segment); ok {
+			segments = append(segments, seg)
+		}
 	}
+	c.driver.Call(func() {
+		c.statusBar.SetSegments(segments)
+	})
 
 	if e := c.editor(c.controller.Editor()); e != nil {
 		defer c.driver.Call(func() {
@@ -201,6 +299,10 @@ func (c *Commander) mapBindings() {
 	for _, cmd := range cmds {
 		c.bind(cmd, setting.Bindings(cmd.Name())...)
 	}
+	c.conflicts = setting.Conflicts(cmds...)
+	for _, conflict := range c.conflicts {
+		log.Printf("Warning: conflicting key bindings: %s", conflict)
+	}
 	if handler == nil {
 		log.Fatal("There is no input handler available!  This should never happen.  Please create an issue in github stating that you saw this message.")
 	}
@@ -208,9 +310,14 @@ func (c *Commander) mapBindings() {
 }
 
 func (c *Commander) mapMenu() {
-	keys := make(map[string][]gxui.KeyboardEvent)
+	keys := make(map[string][]setting.Chord)
 	for key, bound := range c.commands {
-		keys[bound.Name()] = append(keys[bound.Name()], key)
+		keys[bound.Name()] = append(keys[bound.Name()], setting.Chord{key})
+	}
+	for first, seconds := range c.chords {
+		for second, bound := range seconds {
+			keys[bound.Name()] = append(keys[bound.Name()], setting.Chord{first, second})
+		}
 	}
 	// As usual, use the stack slice to preserve order
 	for _, b := range c.stack[len(c.stack)-1] {
@@ -232,6 +339,7 @@ func (c *Commander) Pop() []bind.Bindable {
 	defer c.mapMenu()
 
 	c.commands = make(map[gxui.KeyboardEvent]bind.Command)
+	c.chords = make(map[gxui.KeyboardEvent]map[gxui.KeyboardEvent]bind.Command)
 	defer c.mapBindings()
 
 	end := len(c.stack) - 1
@@ -242,12 +350,21 @@ func (c *Commander) Pop() []bind.Bindable {
 	return top
 }
 
-func (c *Commander) bind(command bind.Command, bindings ...gxui.KeyboardEvent) {
-	for _, binding := range bindings {
-		if old, ok := c.commands[binding]; ok {
-			log.Printf("Warning: command %s is overriding command %s at binding %v", command.Name(), old.Name(), binding)
+func (c *Commander) bind(command bind.Command, chords ...setting.Chord) {
+	for _, chord := range chords {
+		bound := c.commands
+		if len(chord) > 1 {
+			bound = c.chords[chord[0]]
+			if bound == nil {
+				bound = make(map[gxui.KeyboardEvent]bind.Command)
+				c.chords[chord[0]] = bound
+			}
+		}
+		binding := chord[len(chord)-1]
+		if old, ok := bound[binding]; ok {
+			log.Printf("Warning: command %s is overriding command %s at binding %v", command.Name(), old.Name(), chord)
 		}
-		c.commands[binding] = command
+		bound[binding] = command
 	}
 }
 
@@ -258,6 +375,80 @@ func (c *Commander) Binding(binding gxui.KeyboardEvent) bind.Command {
 	return c.commands[binding]
 }
 
+// ChordBinding finds and returns the Command associated with the
+// chord of first followed by second.
+func (c *Commander) ChordBinding(first, second gxui.KeyboardEvent) bind.Command {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.chords[first][second]
+}
+
+// BindingConflicts returns the key bindings that are bound to more
+// than one command.
+func (c *Commander) BindingConflicts() []setting.Conflict {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.conflicts
+}
+
+// CommandNames returns the names of the commands that are currently
+// bound, in menu order.
+func (c *Commander) CommandNames() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	var names []string
+	for _, b := range c.stack[len(c.stack)-1] {
+		if _, ok := c.bound[b.Name()].(bind.Command); ok {
+			names = append(names, b.Name())
+		}
+	}
+	return names
+}
+
+// Remap binds c's commands to keys again, e.g. after the key bindings
+// file has been changed.
+func (c *Commander) Remap() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.menuBar.Clear()
+	defer c.mapMenu()
+
+	c.commands = make(map[gxui.KeyboardEvent]bind.Command)
+	c.chords = make(map[gxui.KeyboardEvent]map[gxui.KeyboardEvent]bind.Command)
+	c.mapBindings()
+}
+
+func (c *Commander) startsChord(event gxui.KeyboardEvent) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	_, ok := c.chords[event]
+	return ok
+}
+
+// finishChord runs the command bound to the pending chord followed
+// by event, if there is one.  The chord is cancelled either way.
+func (c *Commander) finishChord(event gxui.KeyboardEvent) {
+	first := *c.chordStart
+	c.chordStart = nil
+	c.skipStroke = true
+	c.box.Clear()
+	if command := c.ChordBinding(first, event); command != nil {
+		c.Run(command)
+	}
+}
+
+func isModifier(key gxui.KeyboardKey) bool {
+	switch key {
+	case gxui.KeyLeftShift, gxui.KeyRightShift,
+		gxui.KeyLeftControl, gxui.KeyRightControl,
+		gxui.KeyLeftAlt, gxui.KeyRightAlt,
+		gxui.KeyLeftSuper, gxui.KeyRightSuper:
+		return true
+	}
+	return false
+}
+
 // Bindable looks up a bind.Bindable by name
 func (c *Commander) Bindable(name string) bind.Bindable {
 	c.lock.RLock()
@@ -278,6 +469,20 @@ func (c *Commander) KeyPress(event gxui.KeyboardEvent) (consume bool) {
 			log.Printf("Stack trace:\n%s", debug.Stack())
 		}
 	}()
+	c.skipStroke = false
+	if c.chordStart != nil {
+		// Modifiers are pressed and released between the keys
+		// of a chord, so they shouldn't cancel it.
+		if !isModifier(event.Key) {
+			c.finishChord(event)
+		}
+		return true
+	}
+	if c.startsChord(event) {
+		c.chordStart = &event
+		c.box.Chord(event)
+		return true
+	}
 	editor := c.controller.Editor()
 	if event.Modifier == 0 && event.Key == gxui.KeyEscape {
 		c.box.Clear()
@@ -290,12 +495,11 @@ func (c *Commander) KeyPress(event gxui.KeyboardEvent) (consume bool) {
 		c.inputHandler.HandleEvent(codeEditor, event)
 	}
 	if command := c.Binding(event); command != nil {
-		c.box.Clear()
-		if c.box.Run(command) {
+		if c.keepsPrompt(command) {
+			c.Execute(command)
 			return true
 		}
-		c.Execute(c.box.Current())
-		c.box.Finish()
+		c.Run(command)
 		return true
 	}
 	if !c.box.HasFocus() {
@@ -308,6 +512,16 @@ func (c *Commander) KeyPress(event gxui.KeyboardEvent) (consume bool) {
 	return true
 }
 
+// keepsPrompt returns whether or not command should run without
+// closing the prompt that is open.
+func (c *Commander) keepsPrompt(command bind.Command) bool {
+	if !c.box.HasFocus() {
+		return false
+	}
+	keeper, ok := command.(PromptKeeper)
+	return ok && keeper.KeepsPrompt()
+}
+
 func (c *Commander) KeyStroke(event gxui.KeyStrokeEvent) (consume bool) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -316,6 +530,10 @@ func (c *Commander) KeyStroke(event gxui.KeyStrokeEvent) (consume bool) {
 			log.Printf("Stack trace:\n%s", debug.Stack())
 		}
 	}()
+	if c.chordStart != nil || c.skipStroke {
+		c.skipStroke = false
+		return true
+	}
 	if event.Modifier&^gxui.ModShift != 0 {
 		return false
 	}
@@ -327,6 +545,18 @@ func (c *Commander) KeyStroke(event gxui.KeyStrokeEvent) (consume bool) {
 	return true
 }
 
+// Run runs command the same way as if its key binding had been
+// pressed, prompting for any input that it needs and displaying its
+// status when it's done.
+func (c *Commander) Run(command bind.Command) {
+	c.box.Clear()
+	if c.box.Run(command) {
+		return
+	}
+	c.Execute(c.box.Current())
+	c.box.Finish()
+}
+
 func (c *Commander) Execute(e bind.Bindable) {
 	defer func() {
 		// Mitigate the potential for plugins to cause the editor to panic
@@ -10,6 +10,12 @@ type BeforeExecutor interface {
 	BeforeExec(interface{})
 }
 
+// A PromptKeeper is a command which can run while another command's
+// prompt is open, without closing it.
+type PromptKeeper interface {
+	KeepsPrompt() bool
+}
+
 // Elementer is a type which contains elements of its own.
 type Elementer interface {
 	Elements() []interface{}
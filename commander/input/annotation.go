@@ -0,0 +1,17 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package input
+
+// Annotation is a short note that is displayed in the gutter next to
+// a line, such as the commit that last changed it.
+type Annotation struct {
+	// Text is displayed next to the line.  Editors give every
+	// annotation the same width, so Text should be short.
+	Text string
+
+	// Detail is displayed when the mouse hovers over the
+	// annotation.
+	Detail string
+}
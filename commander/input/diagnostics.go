@@ -0,0 +1,62 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package input
+
+import "fmt"
+
+// Severity is the severity of a Diagnostic.  Lower values are more
+// severe.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+	SeverityHint
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	case SeverityHint:
+		return "hint"
+	}
+	return "unknown"
+}
+
+// Diagnostic is a problem found in an editor's text, such as a parse
+// error or a warning from a linter.
+type Diagnostic struct {
+	Severity Severity
+
+	// Range is the span of runes that the diagnostic applies to.
+	// An empty Range refers to the position at Range.Start.
+	Range Span
+
+	Message string
+
+	// Source is the tool that reported the diagnostic, e.g. vet or a
+	// language server.  Editors fill it in with the source that the
+	// diagnostic was published by when it's empty.
+	Source string
+
+	// Unnecessary marks code that can be removed, such as an unused
+	// import.  Editors dim it instead of underlining it.
+	Unnecessary bool
+}
+
+// String returns d's severity, message, and source, as it's shown to
+// users.
+func (d Diagnostic) String() string {
+	if d.Source == "" {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s (%s)", d.Severity, d.Message, d.Source)
+}
@@ -21,4 +21,14 @@ type Editor interface {
 	SetText(string)
 	SyntaxLayers() []SyntaxLayer
 	SetSyntaxLayers([]SyntaxLayer)
+
+	// Diagnostics returns the diagnostics from all sources, in
+	// order of where they start.
+	Diagnostics() []Diagnostic
+
+	// SetDiagnostics replaces the diagnostics that were published
+	// by source.  Each hook that publishes diagnostics should use
+	// its own source, so that it doesn't replace diagnostics from
+	// other hooks.
+	SetDiagnostics(source string, diags []Diagnostic)
 }
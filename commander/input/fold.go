@@ -0,0 +1,25 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package input
+
+// FoldKind is the kind of code that a Fold covers.
+type FoldKind int
+
+const (
+	FoldBlock FoldKind = iota
+	FoldFunc
+	FoldComposite
+	FoldImports
+	FoldComment
+)
+
+// Fold is a region of text that can be folded.  The lines that Start
+// and End are on stay visible when the region is folded, and every
+// line between them is hidden.  A region that should hide its last
+// line (e.g. a comment) can end at the start of the next line.
+type Fold struct {
+	Span
+	Kind FoldKind
+}
@@ -0,0 +1,748 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package input
+
+import (
+	"log"
+	"sort"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+)
+
+// Mode is the editing mode of a Modal handler.
+type Mode int
+
+const (
+	// InsertMode passes all input through to the wrapped Handler.
+	InsertMode Mode = iota
+
+	// NormalMode treats input as commands.
+	NormalMode
+
+	// VisualMode treats input as commands that act on a selection.
+	VisualMode
+)
+
+func (m Mode) String() string {
+	switch m {
+	case InsertMode:
+		return "insert"
+	case NormalMode:
+		return "normal"
+	case VisualMode:
+		return "visual"
+	}
+	return "unknown"
+}
+
+const (
+	opDelete = "delete"
+	opChange = "change"
+	opYank   = "yank"
+
+	objInner  = "inner"
+	objAround = "around"
+
+	// lineMotion is used as the motion when an operator is
+	// repeated (e.g. dd), meaning that it applies to whole lines.
+	lineMotion = "line"
+)
+
+var operators = map[string]bool{
+	opDelete: true,
+	opChange: true,
+	opYank:   true,
+}
+
+// CaretEditor is an Editor with carets that a Modal can move.
+type CaretEditor interface {
+	Editor
+	Carets() []int
+	SetCarets(...int)
+}
+
+// Binder is a type that can look up and execute other commands.
+type Binder interface {
+	Bindable(name string) bind.Bindable
+	Execute(bind.Bindable)
+}
+
+type caretMover interface {
+	To(...int) bind.Bindable
+}
+
+type selecter interface {
+	Controller() *gxui.TextBoxController
+}
+
+// keymap maps sequences of runes to action names.
+type keymap struct {
+	actions  map[string]string
+	prefixes map[string]bool
+}
+
+func newKeymap() keymap {
+	return keymap{
+		actions:  make(map[string]string),
+		prefixes: make(map[string]bool),
+	}
+}
+
+func (k keymap) add(seq, action string) {
+	if old, ok := k.actions[seq]; ok {
+		log.Printf("Warning: modal action %s is overriding action %s at keys %s", action, old, seq)
+	}
+	k.actions[seq] = action
+	runes := []rune(seq)
+	for i := 1; i < len(runes); i++ {
+		k.prefixes[string(runes[:i])] = true
+	}
+}
+
+// match returns the action at the start of keys and the number of
+// runes that it used.  If keys is the start of a longer sequence,
+// more will be true.
+func (k keymap) match(keys []rune) (action string, n int, more bool) {
+	for i := 1; i <= len(keys); i++ {
+		seq := string(keys[:i])
+		if action, ok := k.actions[seq]; ok {
+			return action, i, false
+		}
+		if !k.prefixes[seq] {
+			return "", 0, false
+		}
+	}
+	return "", 0, true
+}
+
+type modalState struct {
+	mode    Mode
+	pending []rune
+
+	// anchors and carets are the two ends of each selection in
+	// visual mode.
+	anchors, carets []int
+
+	register []rune
+	linewise bool
+}
+
+// Modal is a Handler which adds vim-style modes on top of another
+// Handler.  In insert mode, input is passed through to the wrapped
+// Handler unchanged; in normal and visual mode, input is parsed as
+// commands using a keymap of action names to key sequences.
+//
+// Edits are made using the wrapped Handler's Apply method, so hooks
+// bound to the Handler see them exactly like typed text.
+type Modal struct {
+	inner   Handler
+	binder  Binder
+	keys    keymap
+	objects keymap
+	state   *modalState
+}
+
+// NewModal returns a Modal that wraps inner, starting out in normal
+// mode.  keys maps action names to the key sequences that trigger
+// them.  Commands like undo are run using binder.
+func NewModal(inner Handler, binder Binder, keys map[string][]string) *Modal {
+	m := &Modal{
+		inner:   inner,
+		binder:  binder,
+		keys:    newKeymap(),
+		objects: newKeymap(),
+		state:   &modalState{mode: NormalMode},
+	}
+	for action, seqs := range keys {
+		km := m.keys
+		if action == objInner || action == objAround {
+			// Text objects are only used after an operator or in
+			// visual mode, so they can share keys with commands
+			// like insert.
+			km = m.objects
+		}
+		for _, seq := range seqs {
+			km.add(seq, action)
+		}
+	}
+	return m
+}
+
+func (m *Modal) wrap(inner Handler) *Modal {
+	return &Modal{
+		inner:   inner,
+		binder:  m.binder,
+		keys:    m.keys,
+		objects: m.objects,
+		state:   m.state,
+	}
+}
+
+func (m *Modal) Name() string {
+	return m.inner.Name()
+}
+
+// Mode returns the current mode of m.  The mode is shared by every
+// Handler created from m using New or Bind.
+func (m *Modal) Mode() Mode {
+	return m.state.mode
+}
+
+func (m *Modal) New() Handler {
+	return m.wrap(m.inner.New())
+}
+
+func (m *Modal) Bind(b bind.Bindable) (Handler, error) {
+	inner, err := m.inner.Bind(b)
+	if err != nil {
+		return nil, err
+	}
+	return m.wrap(inner), nil
+}
+
+func (m *Modal) Init(e Editor, text []rune) {
+	m.state.pending = nil
+	if m.state.mode == VisualMode {
+		m.state.mode = NormalMode
+	}
+	m.inner.Init(e, text)
+}
+
+func (m *Modal) Apply(e Editor, edits ...Edit) {
+	m.inner.Apply(e, edits...)
+}
+
+func (m *Modal) HandleEvent(e Editor, ev gxui.KeyboardEvent) {
+	if ev.Modifier == 0 && ev.Key == gxui.KeyEscape {
+		// The wrapped handler may have cancellers waiting for
+		// escape, so it always needs to see it.
+		m.inner.HandleEvent(e, ev)
+		m.escape(e)
+		return
+	}
+	if m.state.mode == InsertMode {
+		m.inner.HandleEvent(e, ev)
+	}
+}
+
+func (m *Modal) HandleInput(e Editor, stroke gxui.KeyStrokeEvent) {
+	if m.state.mode == InsertMode {
+		m.inner.HandleInput(e, stroke)
+		return
+	}
+	if stroke.Modifier&^gxui.ModShift != 0 {
+		return
+	}
+	m.state.pending = append(m.state.pending, stroke.Character)
+	cmd, status := m.parse(m.state.pending)
+	switch status {
+	case parseMore:
+		return
+	case parseInvalid:
+		m.state.pending = nil
+		return
+	}
+	m.state.pending = nil
+	ce, ok := e.(CaretEditor)
+	if !ok {
+		log.Printf("Warning: modal input requires an editor with carets; %T does not have carets", e)
+		return
+	}
+	if m.state.mode == VisualMode {
+		m.runVisual(ce, cmd)
+		return
+	}
+	m.runNormal(ce, cmd)
+}
+
+func (m *Modal) escape(e Editor) {
+	m.state.pending = nil
+	ce, ok := e.(CaretEditor)
+	switch m.state.mode {
+	case InsertMode:
+		m.state.mode = NormalMode
+		if !ok {
+			return
+		}
+		text := ce.Runes()
+		carets := ce.Carets()
+		for i, c := range carets {
+			if c > lineStart(text, c) {
+				carets[i] = c - 1
+			}
+		}
+		m.moveCarets(ce, carets)
+	case VisualMode:
+		m.state.mode = NormalMode
+		if ok {
+			m.moveCarets(ce, m.state.carets)
+		}
+	}
+}
+
+type parseStatus int
+
+const (
+	parseDone parseStatus = iota
+	parseMore
+	parseInvalid
+)
+
+// command is a parsed sequence of keys.  Counts are 0 when they were
+// not typed.
+type command struct {
+	count  int
+	action string
+
+	motionCount int
+	motion      string
+	object      rune
+}
+
+func parseCount(keys []rune) (int, []rune) {
+	count := 0
+	for i, r := range keys {
+		if r < '0' || r > '9' || (i == 0 && r == '0') {
+			return count, keys[i:]
+		}
+		count = count*10 + int(r-'0')
+	}
+	return count, nil
+}
+
+func (m *Modal) parse(keys []rune) (command, parseStatus) {
+	var cmd command
+	cmd.count, keys = parseCount(keys)
+	if len(keys) == 0 {
+		return cmd, parseMore
+	}
+	if m.state.mode == VisualMode {
+		action, n, more := m.objects.match(keys)
+		if more {
+			return cmd, parseMore
+		}
+		if action != "" {
+			return m.parseObject(cmd, action, keys[n:])
+		}
+	}
+	action, n, more := m.keys.match(keys)
+	if more {
+		return cmd, parseMore
+	}
+	if action == "" {
+		return cmd, parseInvalid
+	}
+	cmd.action = action
+	if !operators[action] || m.state.mode == VisualMode {
+		return cmd, parseDone
+	}
+
+	opKeys := keys[:n]
+	cmd.motionCount, keys = parseCount(keys[n:])
+	if len(keys) == 0 {
+		return cmd, parseMore
+	}
+	if hasPrefix(keys, opKeys) {
+		cmd.motion = lineMotion
+		return cmd, parseDone
+	}
+	if hasPrefix(opKeys, keys) {
+		return cmd, parseMore
+	}
+	object, n, objMore := m.objects.match(keys)
+	if object != "" {
+		return m.parseObject(cmd, object, keys[n:])
+	}
+	motion, _, more := m.keys.match(keys)
+	if more || objMore {
+		return cmd, parseMore
+	}
+	if _, ok := motions[motion]; !ok {
+		return cmd, parseInvalid
+	}
+	cmd.motion = motion
+	return cmd, parseDone
+}
+
+func (m *Modal) parseObject(cmd command, object string, keys []rune) (command, parseStatus) {
+	if len(keys) == 0 {
+		return cmd, parseMore
+	}
+	if cmd.action == "" {
+		cmd.action = object
+	}
+	cmd.motion = object
+	cmd.object = keys[0]
+	return cmd, parseDone
+}
+
+func hasPrefix(s, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Modal) runNormal(e CaretEditor, cmd command) {
+	text := e.Runes()
+	count := atLeastOne(cmd.count)
+	if mv, ok := motions[cmd.action]; ok {
+		carets := e.Carets()
+		for i, c := range carets {
+			carets[i] = normalPos(text, mv.move(text, c, cmd.count))
+		}
+		m.moveCarets(e, carets)
+		return
+	}
+	if operators[cmd.action] {
+		var spans []Span
+		linewise := cmd.motion == lineMotion || motions[cmd.motion].linewise
+		for _, c := range e.Carets() {
+			s, ok := operatorSpan(text, c, cmd)
+			if ok {
+				spans = append(spans, s)
+			}
+		}
+		m.operate(e, cmd.action, spans, linewise)
+		return
+	}
+	switch cmd.action {
+	case "insert":
+		m.state.mode = InsertMode
+	case "append":
+		carets := e.Carets()
+		for i, c := range carets {
+			if c < lineEnd(text, c) {
+				carets[i] = c + 1
+			}
+		}
+		m.moveCarets(e, carets)
+		m.state.mode = InsertMode
+	case "insert-line-start":
+		m.moveCarets(e, mapCarets(e.Carets(), func(c int) int { return firstNonBlank(text, c) }))
+		m.state.mode = InsertMode
+	case "append-line-end":
+		m.moveCarets(e, mapCarets(e.Carets(), func(c int) int { return lineEnd(text, c) }))
+		m.state.mode = InsertMode
+	case "open-below", "open-above":
+		m.openLine(e, text, cmd.action == "open-above")
+		m.state.mode = InsertMode
+	case "visual":
+		m.state.mode = VisualMode
+		m.state.carets = e.Carets()
+		m.state.anchors = append([]int(nil), m.state.carets...)
+		m.selectVisual(e)
+	case "delete-char":
+		var spans []Span
+		for _, c := range e.Carets() {
+			end := c + count
+			if lineEnd := lineEnd(text, c); end > lineEnd {
+				end = lineEnd
+			}
+			if end > c {
+				spans = append(spans, Span{Start: c, End: end})
+			}
+		}
+		m.operate(e, opDelete, spans, false)
+	case "paste-after", "paste-before":
+		m.paste(e, text, count, cmd.action == "paste-before")
+	case "undo":
+		undo := m.bindable("undo-last-edit")
+		if undo == nil {
+			return
+		}
+		for i := 0; i < count; i++ {
+			m.binder.Execute(undo)
+		}
+	}
+}
+
+func (m *Modal) runVisual(e CaretEditor, cmd command) {
+	text := e.Runes()
+	if mv, ok := motions[cmd.action]; ok {
+		for i, c := range m.state.carets {
+			m.state.carets[i] = normalPos(text, mv.move(text, c, cmd.count))
+		}
+		m.selectVisual(e)
+		return
+	}
+	switch {
+	case cmd.action == objInner || cmd.action == objAround:
+		for i, c := range m.state.carets {
+			start, end, ok := objectSpan(text, c, cmd.object, cmd.action == objAround)
+			if !ok || end <= start {
+				continue
+			}
+			m.state.anchors[i], m.state.carets[i] = start, end-1
+		}
+		m.selectVisual(e)
+	case operators[cmd.action] || cmd.action == "delete-char":
+		op := cmd.action
+		if op == "delete-char" {
+			op = opDelete
+		}
+		var spans []Span
+		for i, c := range m.state.carets {
+			start, end := order(m.state.anchors[i], c)
+			if end < len(text) {
+				end++
+			}
+			spans = append(spans, Span{Start: start, End: end})
+		}
+		m.state.mode = NormalMode
+		m.operate(e, op, spans, false)
+	case cmd.action == "visual":
+		m.state.mode = NormalMode
+		m.moveCarets(e, m.state.carets)
+	}
+}
+
+// endsLine returns whether or not the rune before end is a newline.
+func endsLine(text []rune, end int) bool {
+	return end > 0 && text[end-1] == '\n'
+}
+
+func order(a, b int) (int, int) {
+	if a > b {
+		return b, a
+	}
+	return a, b
+}
+
+func mapCarets(carets []int, f func(int) int) []int {
+	for i, c := range carets {
+		carets[i] = f(c)
+	}
+	return carets
+}
+
+// operatorSpan returns the span of text that an operator typed with
+// the caret at pos should act on.
+func operatorSpan(text []rune, pos int, cmd command) (Span, bool) {
+	count := 0
+	if cmd.count > 0 || cmd.motionCount > 0 {
+		count = atLeastOne(cmd.count) * atLeastOne(cmd.motionCount)
+	}
+	switch cmd.motion {
+	case lineMotion:
+		end := vertical(text, pos, atLeastOne(count)-1)
+		if cmd.action == opChange {
+			// Changing lines keeps their indentation and trailing
+			// newline, like vim's autoindent.
+			return Span{Start: firstNonBlank(text, pos), End: lineEnd(text, end)}, true
+		}
+		start, end := lineSpan(text, pos, end)
+		return Span{Start: start, End: end}, true
+	case objInner, objAround:
+		start, end, ok := objectSpan(text, pos, cmd.object, cmd.motion == objAround)
+		return Span{Start: start, End: end}, ok
+	}
+	mv := motions[cmd.motion]
+	if cmd.motion == "word-forward" && cmd.action == opChange && pos < len(text) && classOf(text[pos]) != classSpace {
+		// Like vim, cw acts like ce when the caret is on a word.
+		mv = motions["word-end"]
+	}
+	target := mv.move(text, pos, count)
+	start, end := order(pos, target)
+	switch {
+	case mv.linewise:
+		start, end = lineSpan(text, start, end)
+	case mv.inclusive:
+		if end < len(text) && text[end] != '\n' {
+			end++
+		}
+	case cmd.motion == "word-forward":
+		// Deleting the last word on a line shouldn't join the next
+		// line on to it.
+		if lineEnd := lineEnd(text, pos); end > lineEnd && lineEnd > pos {
+			end = lineEnd
+		}
+	}
+	return Span{Start: start, End: end}, end > start
+}
+
+// operate runs op against spans of e's text.
+func (m *Modal) operate(e CaretEditor, op string, spans []Span, linewise bool) {
+	if len(spans) == 0 {
+		return
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Start < spans[j].Start
+	})
+	text := e.Runes()
+	m.state.register = append([]rune(nil), text[spans[0].Start:spans[0].End]...)
+	m.state.linewise = linewise
+	if linewise && !endsLine(text, spans[0].End) {
+		m.state.register = append(m.state.register, '\n')
+	}
+
+	if op == opYank {
+		if linewise {
+			// Like vim, yanking lines leaves the carets where they
+			// are.
+			return
+		}
+		carets := make([]int, 0, len(spans))
+		for _, s := range spans {
+			carets = append(carets, s.Start)
+		}
+		m.moveCarets(e, carets)
+		return
+	}
+
+	var (
+		edits  []Edit
+		carets []int
+		delta  int
+		last   = -1
+	)
+	for _, s := range spans {
+		if s.Start < last {
+			// Overlapping spans, e.g. from two carets on the same
+			// line with dd.
+			continue
+		}
+		last = s.End
+		if linewise && op == opDelete && !endsLine(text, s.End) && s.Start > 0 {
+			// The last line in the file has no newline after it, so
+			// the newline before it needs to be removed instead.
+			s.Start--
+		}
+		edits = append(edits, Edit{At: s.Start, Old: text[s.Start:s.End]})
+		carets = append(carets, s.Start+delta)
+		delta -= s.End - s.Start
+	}
+	m.inner.Apply(e, edits...)
+
+	if op == opChange {
+		m.state.mode = InsertMode
+		m.moveCarets(e, carets)
+		return
+	}
+	text = e.Runes()
+	for i, c := range carets {
+		if linewise {
+			carets[i] = firstNonBlank(text, c)
+			continue
+		}
+		carets[i] = normalPos(text, c)
+	}
+	m.moveCarets(e, carets)
+}
+
+func (m *Modal) openLine(e CaretEditor, text []rune, above bool) {
+	var (
+		edits  []Edit
+		carets []int
+		delta  int
+	)
+	sorted := append([]int(nil), e.Carets()...)
+	sort.Ints(sorted)
+	for _, c := range sorted {
+		start := lineStart(text, c)
+		indent := append([]rune(nil), text[start:firstNonBlank(text, c)]...)
+		edit := Edit{At: lineEnd(text, c), New: append([]rune{'\n'}, indent...)}
+		caret := edit.At + len(edit.New)
+		if above {
+			edit = Edit{At: start, New: append(indent, '\n')}
+			caret = start + len(indent)
+		}
+		if len(edits) > 0 && edits[len(edits)-1].At == edit.At {
+			continue
+		}
+		edits = append(edits, edit)
+		carets = append(carets, caret+delta)
+		delta += len(edit.New)
+	}
+	m.inner.Apply(e, edits...)
+	m.moveCarets(e, carets)
+}
+
+func (m *Modal) paste(e CaretEditor, text []rune, count int, before bool) {
+	if len(m.state.register) == 0 {
+		return
+	}
+	var paste []rune
+	for i := 0; i < count; i++ {
+		paste = append(paste, m.state.register...)
+	}
+	var (
+		edits  []Edit
+		carets []int
+		delta  int
+	)
+	sorted := append([]int(nil), e.Carets()...)
+	sort.Ints(sorted)
+	for _, c := range sorted {
+		edit := Edit{At: c, New: paste}
+		caret := c + len(paste) - 1
+		switch {
+		case m.state.linewise && before:
+			edit.At = lineStart(text, c)
+			caret = edit.At
+		case m.state.linewise:
+			edit.At = lineEnd(text, c) + 1
+			if edit.At > len(text) {
+				// There's no newline at the end of the file, so the
+				// pasted lines need to start with one instead.
+				edit.At = len(text)
+				edit.New = append([]rune{'\n'}, paste[:len(paste)-1]...)
+			}
+			caret = edit.At
+			if edit.New[0] == '\n' {
+				caret++
+			}
+		case !before && c < lineEnd(text, c):
+			edit.At++
+			caret++
+		}
+		edits = append(edits, edit)
+		carets = append(carets, caret+delta)
+		delta += len(edit.New)
+	}
+	m.inner.Apply(e, edits...)
+	m.moveCarets(e, carets)
+}
+
+func (m *Modal) bindable(name string) bind.Bindable {
+	if m.binder == nil {
+		return nil
+	}
+	return m.binder.Bindable(name)
+}
+
+// moveCarets moves e's carets, using caret-movement if it is
+// available so that hooks like scrolling still run.
+func (m *Modal) moveCarets(e CaretEditor, carets []int) {
+	if mover, ok := m.bindable("caret-movement").(caretMover); ok {
+		m.binder.Execute(mover.To(carets...))
+		return
+	}
+	e.SetCarets(carets...)
+}
+
+func (m *Modal) selectVisual(e CaretEditor) {
+	s, ok := e.(selecter)
+	if !ok {
+		e.SetCarets(m.state.carets...)
+		return
+	}
+	text := e.Runes()
+	var sel []gxui.TextSelection
+	for i, c := range m.state.carets {
+		start, end := order(m.state.anchors[i], c)
+		if end < len(text) {
+			end++
+		}
+		sel = append(sel, gxui.CreateTextSelection(start, end, c < m.state.anchors[i]))
+	}
+	s.Controller().SetSelections(sel)
+}
@@ -0,0 +1,216 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package input_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var testKeys = map[string][]string{
+	"left":            {"h"},
+	"down":            {"j"},
+	"up":              {"k"},
+	"right":           {"l"},
+	"word-forward":    {"w"},
+	"word-end":        {"e"},
+	"line-start":      {"0"},
+	"line-end":        {"$"},
+	"file-start":      {"gg"},
+	"file-end":        {"G"},
+	"delete":          {"d"},
+	"change":          {"c"},
+	"yank":            {"y"},
+	"inner":           {"i"},
+	"around":          {"a"},
+	"insert":          {"i"},
+	"append":          {"a"},
+	"append-line-end": {"A"},
+	"open-below":      {"o"},
+	"visual":          {"v"},
+	"delete-char":     {"x"},
+	"paste-after":     {"p"},
+}
+
+func TestModal(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *testEditor, *input.Modal) {
+		e := &testEditor{text: []rune("foo bar baz\n\tfunc(a, b)\nlast")}
+		m := input.NewModal(&testHandler{}, nil, testKeys)
+		return expect.New(t), e, m
+	})
+
+	o.Spec("it starts in normal mode", func(expect expect.Expectation, e *testEditor, m *input.Modal) {
+		expect(m.Mode()).To(equal(input.NormalMode))
+		typeKeys(m, e, "q")
+		expect(e.Text()).To(equal("foo bar baz\n\tfunc(a, b)\nlast"))
+	})
+
+	o.Spec("it moves carets", func(expect expect.Expectation, e *testEditor, m *input.Modal) {
+		typeKeys(m, e, "l")
+		expect(e.Carets()).To(equal([]int{1}))
+		typeKeys(m, e, "j")
+		expect(e.Carets()).To(equal([]int{13}))
+		typeKeys(m, e, "$")
+		expect(e.Carets()).To(equal([]int{22}))
+		typeKeys(m, e, "0")
+		expect(e.Carets()).To(equal([]int{12}))
+		typeKeys(m, e, "G")
+		expect(e.Carets()).To(equal([]int{24}))
+		typeKeys(m, e, "gg")
+		expect(e.Carets()).To(equal([]int{0}))
+	})
+
+	o.Spec("it supports counts", func(expect expect.Expectation, e *testEditor, m *input.Modal) {
+		typeKeys(m, e, "2w")
+		expect(e.Carets()).To(equal([]int{8}))
+		typeKeys(m, e, "20l")
+		expect(e.Carets()).To(equal([]int{10}))
+	})
+
+	o.Spec("it deletes words", func(expect expect.Expectation, e *testEditor, m *input.Modal) {
+		typeKeys(m, e, "dw")
+		expect(e.Text()).To(equal("bar baz\n\tfunc(a, b)\nlast"))
+		typeKeys(m, e, "2dw")
+		expect(e.Text()).To(equal("\n\tfunc(a, b)\nlast"))
+	})
+
+	o.Spec("it deletes lines", func(expect expect.Expectation, e *testEditor, m *input.Modal) {
+		typeKeys(m, e, "jdd")
+		expect(e.Text()).To(equal("foo bar baz\nlast"))
+		expect(e.Carets()).To(equal([]int{12}))
+		typeKeys(m, e, "dd")
+		expect(e.Text()).To(equal("foo bar baz"))
+	})
+
+	o.Spec("it changes text objects", func(expect expect.Expectation, e *testEditor, m *input.Modal) {
+		e.carets = []int{19}
+		typeKeys(m, e, "ci(")
+		expect(e.Text()).To(equal("foo bar baz\n\tfunc()\nlast"))
+		expect(m.Mode()).To(equal(input.InsertMode))
+		expect(e.Carets()).To(equal([]int{18}))
+
+		typeKeys(m, e, "x")
+		expect(e.Text()).To(equal("foo bar baz\n\tfunc(x)\nlast"))
+	})
+
+	o.Spec("it returns to normal mode on escape", func(expect expect.Expectation, e *testEditor, m *input.Modal) {
+		typeKeys(m, e, "A!")
+		expect(e.Text()).To(equal("foo bar baz!\n\tfunc(a, b)\nlast"))
+		m.HandleEvent(e, gxui.KeyboardEvent{Key: gxui.KeyEscape})
+		expect(m.Mode()).To(equal(input.NormalMode))
+		expect(e.Carets()).To(equal([]int{11}))
+	})
+
+	o.Spec("it yanks and pastes lines", func(expect expect.Expectation, e *testEditor, m *input.Modal) {
+		typeKeys(m, e, "Gyyp")
+		expect(e.Text()).To(equal("foo bar baz\n\tfunc(a, b)\nlast\nlast"))
+		expect(e.Carets()).To(equal([]int{29}))
+	})
+
+	o.Spec("it opens lines with the current indentation", func(expect expect.Expectation, e *testEditor, m *input.Modal) {
+		typeKeys(m, e, "jo")
+		expect(e.Text()).To(equal("foo bar baz\n\tfunc(a, b)\n\t\nlast"))
+		expect(m.Mode()).To(equal(input.InsertMode))
+		expect(e.Carets()).To(equal([]int{25}))
+	})
+
+	o.Spec("it operates on visual selections", func(expect expect.Expectation, e *testEditor, m *input.Modal) {
+		typeKeys(m, e, "wvel")
+		expect(m.Mode()).To(equal(input.VisualMode))
+		typeKeys(m, e, "d")
+		expect(m.Mode()).To(equal(input.NormalMode))
+		expect(e.Text()).To(equal("foo baz\n\tfunc(a, b)\nlast"))
+	})
+
+	o.Spec("it ignores unknown keys", func(expect expect.Expectation, e *testEditor, m *input.Modal) {
+		typeKeys(m, e, "dqx")
+		expect(e.Text()).To(equal("oo bar baz\n\tfunc(a, b)\nlast"))
+	})
+}
+
+func typeKeys(m *input.Modal, e input.Editor, keys string) {
+	for _, r := range keys {
+		m.HandleInput(e, gxui.KeyStrokeEvent{Character: r})
+	}
+}
+
+func equal(v interface{}) matchers.EqualMatcher {
+	return matchers.Equal(v)
+}
+
+type testEditor struct {
+	text   []rune
+	carets []int
+}
+
+func (e *testEditor) Filepath() string                          { return "test.go" }
+func (e *testEditor) Text() string                              { return string(e.text) }
+func (e *testEditor) Runes() []rune                             { return e.text }
+func (e *testEditor) SetText(t string)                          { e.text = []rune(t) }
+func (e *testEditor) SyntaxLayers() []input.SyntaxLayer         { return nil }
+func (e *testEditor) SetSyntaxLayers(l []input.SyntaxLayer)     {}
+func (e *testEditor) Diagnostics() []input.Diagnostic           { return nil }
+func (e *testEditor) SetDiagnostics(string, []input.Diagnostic) {}
+
+func (e *testEditor) Carets() []int {
+	if len(e.carets) == 0 {
+		return []int{0}
+	}
+	return append([]int(nil), e.carets...)
+}
+
+func (e *testEditor) SetCarets(carets ...int) {
+	e.carets = carets
+}
+
+// testHandler is a very simple input.Handler that inserts typed
+// runes at each caret.
+type testHandler struct{}
+
+func (h *testHandler) Name() string                                 { return "input-handler" }
+func (h *testHandler) New() input.Handler                           { return h }
+func (h *testHandler) Init(input.Editor, []rune)                    {}
+func (h *testHandler) Bind(bind.Bindable) (input.Handler, error)    { return h, nil }
+func (h *testHandler) HandleEvent(input.Editor, gxui.KeyboardEvent) {}
+
+func (h *testHandler) Apply(e input.Editor, edits ...input.Edit) {
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].At < edits[j].At
+	})
+	text := e.Runes()
+	var result []rune
+	last := 0
+	for _, edit := range edits {
+		result = append(result, text[last:edit.At]...)
+		result = append(result, edit.New...)
+		last = edit.At + len(edit.Old)
+	}
+	result = append(result, text[last:]...)
+	e.SetText(string(result))
+}
+
+func (h *testHandler) HandleInput(e input.Editor, stroke gxui.KeyStrokeEvent) {
+	ce := e.(*testEditor)
+	var edits []input.Edit
+	for _, c := range ce.Carets() {
+		edits = append(edits, input.Edit{At: c, New: []rune{stroke.Character}})
+	}
+	h.Apply(e, edits...)
+	carets := ce.Carets()
+	for i := range carets {
+		carets[i] += i + 1
+	}
+	ce.SetCarets(carets...)
+}
@@ -0,0 +1,372 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package input
+
+import "unicode"
+
+// motion is a caret movement in normal or visual mode.  move is
+// passed a count of 0 if the user didn't type a count.
+type motion struct {
+	move func(text []rune, pos, count int) int
+
+	// inclusive motions include the rune under the target when
+	// used with an operator.
+	inclusive bool
+
+	// linewise motions operate on whole lines when used with an
+	// operator.
+	linewise bool
+}
+
+var motions = map[string]motion{
+	"left":            {move: left},
+	"right":           {move: right},
+	"up":              {move: up, linewise: true},
+	"down":            {move: down, linewise: true},
+	"word-forward":    {move: wordForward},
+	"word-backward":   {move: wordBackward},
+	"word-end":        {move: wordEnd, inclusive: true},
+	"line-start":      {move: func(text []rune, pos, _ int) int { return lineStart(text, pos) }},
+	"line-first-char": {move: func(text []rune, pos, _ int) int { return firstNonBlank(text, pos) }},
+	"line-end":        {move: lineLast, inclusive: true},
+	"file-start":      {move: fileStart, linewise: true},
+	"file-end":        {move: fileEnd, linewise: true},
+}
+
+func atLeastOne(count int) int {
+	if count < 1 {
+		return 1
+	}
+	return count
+}
+
+func clampPos(text []rune, pos int) int {
+	if pos < 0 {
+		return 0
+	}
+	if pos > len(text) {
+		return len(text)
+	}
+	return pos
+}
+
+func lineStart(text []rune, pos int) int {
+	pos = clampPos(text, pos)
+	for pos > 0 && text[pos-1] != '\n' {
+		pos--
+	}
+	return pos
+}
+
+func lineEnd(text []rune, pos int) int {
+	pos = clampPos(text, pos)
+	for pos < len(text) && text[pos] != '\n' {
+		pos++
+	}
+	return pos
+}
+
+func lineNumber(text []rune, pos int) int {
+	pos = clampPos(text, pos)
+	line := 0
+	for _, r := range text[:pos] {
+		if r == '\n' {
+			line++
+		}
+	}
+	return line
+}
+
+// lineOffset returns the offset of the first rune in line.  If there
+// are not that many lines, the start of the last line is returned.
+func lineOffset(text []rune, line int) int {
+	start := 0
+	for i, r := range text {
+		if line <= 0 {
+			break
+		}
+		if r == '\n' {
+			start = i + 1
+			line--
+		}
+	}
+	return start
+}
+
+func firstNonBlank(text []rune, pos int) int {
+	start, end := lineStart(text, pos), lineEnd(text, pos)
+	for start < end && (text[start] == ' ' || text[start] == '\t') {
+		start++
+	}
+	return start
+}
+
+// normalPos moves pos back on to the text of its line, since the
+// caret in normal mode sits on a rune rather than between runes.
+func normalPos(text []rune, pos int) int {
+	pos = clampPos(text, pos)
+	if pos > lineStart(text, pos) && (pos == len(text) || text[pos] == '\n') {
+		pos--
+	}
+	return pos
+}
+
+func left(text []rune, pos, count int) int {
+	start := lineStart(text, pos)
+	pos -= atLeastOne(count)
+	if pos < start {
+		return start
+	}
+	return pos
+}
+
+func right(text []rune, pos, count int) int {
+	end := lineEnd(text, pos)
+	pos += atLeastOne(count)
+	if pos > end {
+		return end
+	}
+	return pos
+}
+
+func vertical(text []rune, pos, lines int) int {
+	col := pos - lineStart(text, pos)
+	line := lineNumber(text, pos) + lines
+	if line < 0 {
+		line = 0
+	}
+	start := lineOffset(text, line)
+	if end := lineEnd(text, start); start+col > end {
+		return end
+	}
+	return start + col
+}
+
+func up(text []rune, pos, count int) int {
+	return vertical(text, pos, -atLeastOne(count))
+}
+
+func down(text []rune, pos, count int) int {
+	return vertical(text, pos, atLeastOne(count))
+}
+
+type runeClass int
+
+const (
+	classSpace runeClass = iota
+	classWord
+	classPunct
+)
+
+func classOf(r rune) runeClass {
+	switch {
+	case unicode.IsSpace(r):
+		return classSpace
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return classWord
+	default:
+		return classPunct
+	}
+}
+
+func wordForward(text []rune, pos, count int) int {
+	pos = clampPos(text, pos)
+	for i := 0; i < atLeastOne(count) && pos < len(text); i++ {
+		if c := classOf(text[pos]); c != classSpace {
+			for pos < len(text) && classOf(text[pos]) == c {
+				pos++
+			}
+		}
+		for pos < len(text) && classOf(text[pos]) == classSpace {
+			pos++
+		}
+	}
+	return pos
+}
+
+func wordBackward(text []rune, pos, count int) int {
+	pos = clampPos(text, pos)
+	for i := 0; i < atLeastOne(count) && pos > 0; i++ {
+		pos--
+		for pos > 0 && classOf(text[pos]) == classSpace {
+			pos--
+		}
+		c := classOf(text[pos])
+		for pos > 0 && classOf(text[pos-1]) == c {
+			pos--
+		}
+	}
+	return pos
+}
+
+func wordEnd(text []rune, pos, count int) int {
+	pos = clampPos(text, pos)
+	for i := 0; i < atLeastOne(count) && pos < len(text)-1; i++ {
+		pos++
+		for pos < len(text)-1 && classOf(text[pos]) == classSpace {
+			pos++
+		}
+		c := classOf(text[pos])
+		for pos < len(text)-1 && classOf(text[pos+1]) == c {
+			pos++
+		}
+	}
+	return pos
+}
+
+func lineLast(text []rune, pos, _ int) int {
+	start, end := lineStart(text, pos), lineEnd(text, pos)
+	if end > start {
+		return end - 1
+	}
+	return start
+}
+
+func fileStart(text []rune, _, count int) int {
+	line := 0
+	if count > 0 {
+		line = count - 1
+	}
+	return firstNonBlank(text, lineOffset(text, line))
+}
+
+func fileEnd(text []rune, _, count int) int {
+	if count > 0 {
+		return firstNonBlank(text, lineOffset(text, count-1))
+	}
+	return firstNonBlank(text, lineStart(text, len(text)))
+}
+
+// lineSpan returns the span covering the lines from start to end,
+// including the trailing newline if there is one.
+func lineSpan(text []rune, start, end int) (int, int) {
+	start, end = lineStart(text, start), lineEnd(text, end)
+	if end < len(text) {
+		end++
+	}
+	return start, end
+}
+
+// objectSpan returns the span of the text object identified by obj
+// surrounding pos.  The returned bool will be false if there is no
+// such object around pos.
+func objectSpan(text []rune, pos int, obj rune, around bool) (int, int, bool) {
+	if pos < 0 || pos >= len(text) {
+		return 0, 0, false
+	}
+	switch obj {
+	case '(', ')', 'b':
+		return pairSpan(text, pos, '(', ')', around)
+	case '{', '}', 'B':
+		return pairSpan(text, pos, '{', '}', around)
+	case '[', ']':
+		return pairSpan(text, pos, '[', ']', around)
+	case '<', '>':
+		return pairSpan(text, pos, '<', '>', around)
+	case '"', '\'', '`':
+		return quoteSpan(text, pos, obj, around)
+	case 'w':
+		return wordSpan(text, pos, around)
+	}
+	return 0, 0, false
+}
+
+func pairSpan(text []rune, pos int, open, close rune, around bool) (int, int, bool) {
+	start := -1
+	depth := 0
+	for i := pos; i >= 0; i-- {
+		switch {
+		case text[i] == close && i != pos:
+			depth++
+		case text[i] == open:
+			if depth == 0 {
+				start = i
+			}
+			depth--
+		}
+		if start >= 0 {
+			break
+		}
+	}
+	if start < 0 {
+		return 0, 0, false
+	}
+	depth = 0
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case open:
+			depth++
+		case close:
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if around {
+				return start, i + 1, true
+			}
+			return start + 1, i, true
+		}
+	}
+	return 0, 0, false
+}
+
+func quoteSpan(text []rune, pos int, quote rune, around bool) (int, int, bool) {
+	var quotes []int
+	for i := lineStart(text, pos); i < lineEnd(text, pos); i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+		if text[i] == quote {
+			quotes = append(quotes, i)
+		}
+	}
+	for i := 0; i+1 < len(quotes); i += 2 {
+		start, end := quotes[i], quotes[i+1]
+		// Like vim, use the next quoted string on the line if pos
+		// isn't inside of one.
+		if pos > end {
+			continue
+		}
+		if around {
+			return start, end + 1, true
+		}
+		return start + 1, end, true
+	}
+	return 0, 0, false
+}
+
+func wordSpan(text []rune, pos int, around bool) (int, int, bool) {
+	if text[pos] == '\n' {
+		return 0, 0, false
+	}
+	same := func(i int) bool {
+		return text[i] != '\n' && classOf(text[i]) == classOf(text[pos])
+	}
+	start, end := pos, pos+1
+	for start > 0 && same(start-1) {
+		start--
+	}
+	for end < len(text) && same(end) {
+		end++
+	}
+	if !around || classOf(text[pos]) == classSpace {
+		return start, end, true
+	}
+	blank := func(i int) bool {
+		return text[i] == ' ' || text[i] == '\t'
+	}
+	trailing := end
+	for trailing < len(text) && blank(trailing) {
+		trailing++
+	}
+	if trailing > end {
+		return start, trailing, true
+	}
+	for start > 0 && blank(start-1) {
+		start--
+	}
+	return start, end, true
+}
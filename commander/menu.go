@@ -11,6 +11,7 @@ import (
 	"github.com/nelsam/gxui/mixins/parts"
 	"github.com/nelsam/gxui/themes/basic"
 	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/setting"
 )
 
 type Boundser interface {
@@ -37,7 +38,7 @@ func newMenuBar(commander *Commander, theme *basic.Theme) *menuBar {
 	return m
 }
 
-func (m *menuBar) Add(command bind.Command, bindings ...gxui.KeyboardEvent) {
+func (m *menuBar) Add(command bind.Command, bindings ...setting.Chord) {
 	menu, ok := m.menus[command.Menu()]
 	if !ok {
 		menu = newMenu(m.commander, m.theme)
@@ -94,7 +95,7 @@ func newMenu(commander *Commander, theme *basic.Theme) *menu {
 	return m
 }
 
-func (m *menu) Add(command bind.Command, bindings ...gxui.KeyboardEvent) {
+func (m *menu) Add(command bind.Command, bindings ...setting.Chord) {
 	item := newMenuItem(m.theme, command.Name(), bindings...)
 	m.AddChild(item)
 	item.OnClick(func(gxui.MouseEvent) {
@@ -113,7 +114,7 @@ type menuItem struct {
 	theme *basic.Theme
 }
 
-func newMenuItem(theme *basic.Theme, name string, bindings ...gxui.KeyboardEvent) *menuItem {
+func newMenuItem(theme *basic.Theme, name string, bindings ...setting.Chord) *menuItem {
 	b := &menuItem{
 		theme: theme,
 	}
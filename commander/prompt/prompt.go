@@ -0,0 +1,148 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package prompt contains the history and tab completion that the
+// commander gives to the text inputs of commands.
+package prompt
+
+import "unicode/utf8"
+
+// DefaultHistorySize is the number of values that a History remembers
+// if it isn't given a size.
+const DefaultHistorySize = 50
+
+// History is the list of values that have been entered in a prompt.
+// Prev and Next step through them, starting from the newest, like a
+// shell's history.
+type History struct {
+	size   int
+	values []string
+
+	// pos is the index of the value that was last returned by Prev
+	// or Next, or len(values) when the history isn't being stepped
+	// through.  draft is the text that was in the prompt before
+	// stepping through the history started.
+	pos   int
+	draft string
+}
+
+// NewHistory returns a History which remembers up to size values.
+func NewHistory(size int) *History {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &History{size: size}
+}
+
+// Add adds value as the newest value in h and resets h to stop
+// stepping through its values.  Empty values are ignored, and a value
+// that was already in h is moved to the end rather than repeated.
+func (h *History) Add(value string) {
+	defer h.Reset()
+	if value == "" {
+		return
+	}
+	for i, v := range h.values {
+		if v == value {
+			h.values = append(h.values[:i], h.values[i+1:]...)
+			break
+		}
+	}
+	h.values = append(h.values, value)
+	if len(h.values) > h.size {
+		h.values = h.values[len(h.values)-h.size:]
+	}
+}
+
+// Last returns the newest value in h, or an empty string if h is
+// empty.
+func (h *History) Last() string {
+	if len(h.values) == 0 {
+		return ""
+	}
+	return h.values[len(h.values)-1]
+}
+
+// Reset stops stepping through h's values, so that the next call to
+// Prev returns the newest value.
+func (h *History) Reset() {
+	h.pos = len(h.values)
+	h.draft = ""
+}
+
+// Prev returns the value before the one that was last returned.
+// current is the prompt's text, which Next returns to after the
+// newest value.  Prev returns false if there are no older values.
+func (h *History) Prev(current string) (string, bool) {
+	if h.pos == 0 {
+		return "", false
+	}
+	if h.pos == len(h.values) {
+		h.draft = current
+	}
+	h.pos--
+	return h.values[h.pos], true
+}
+
+// Next returns the value after the one that was last returned, or
+// the text that the prompt had before Prev was first called.  Next
+// returns false if h isn't being stepped through.
+func (h *History) Next() (string, bool) {
+	if h.pos >= len(h.values) {
+		return "", false
+	}
+	h.pos++
+	if h.pos == len(h.values) {
+		return h.draft, true
+	}
+	return h.values[h.pos], true
+}
+
+// Tabs completes a prompt's text when tab is pressed.  The first press
+// completes the text to the longest prefix that every suggestion
+// shares, or to the first suggestion if that wouldn't add anything;
+// pressing tab again cycles through the suggestions.
+type Tabs struct {
+	suggestions []string
+	i           int
+	last        string
+}
+
+// Complete returns text completed from the suggestions that suggest
+// returns for it.  suggest isn't called again while text is still the
+// completion that Complete last returned.
+func (t *Tabs) Complete(text string, suggest func(string) []string) string {
+	if len(t.suggestions) == 0 || text != t.last {
+		t.suggestions = suggest(text)
+		t.i = -1
+		if prefix := commonPrefix(t.suggestions); len(t.suggestions) > 1 && len(prefix) > len(text) && prefix[:len(text)] == text {
+			t.last = prefix
+			return prefix
+		}
+	}
+	if len(t.suggestions) == 0 {
+		return text
+	}
+	t.i = (t.i + 1) % len(t.suggestions)
+	t.last = t.suggestions[t.i]
+	return t.last
+}
+
+func commonPrefix(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	prefix := values[0]
+	for _, v := range values[1:] {
+		n := 0
+		for n < len(prefix) && n < len(v) && prefix[n] == v[n] {
+			n++
+		}
+		for n > 0 && n < len(prefix) && !utf8.RuneStart(prefix[n]) {
+			n--
+		}
+		prefix = prefix[:n]
+	}
+	return prefix
+}
@@ -0,0 +1,124 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package prompt_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/commander/prompt"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal   = matchers.Equal
+	beTrue  = matchers.BeTrue
+	beFalse = matchers.BeFalse
+)
+
+func TestHistory(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *prompt.History) {
+		h := prompt.NewHistory(3)
+		h.Add("foo")
+		h.Add("bar")
+		return expect.New(t), h
+	})
+
+	o.Spec("it steps back through values from the newest", func(expect expect.Expectation, h *prompt.History) {
+		v, ok := h.Prev("")
+		expect(ok).To(beTrue())
+		expect(v).To(equal("bar"))
+		v, ok = h.Prev(v)
+		expect(ok).To(beTrue())
+		expect(v).To(equal("foo"))
+		_, ok = h.Prev(v)
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it returns to the draft after the newest value", func(expect expect.Expectation, h *prompt.History) {
+		h.Prev("draft")
+		h.Prev("bar")
+		v, _ := h.Next()
+		expect(v).To(equal("bar"))
+		v, ok := h.Next()
+		expect(ok).To(beTrue())
+		expect(v).To(equal("draft"))
+		_, ok = h.Next()
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it moves repeated values to the end", func(expect expect.Expectation, h *prompt.History) {
+		h.Add("foo")
+		expect(h.Last()).To(equal("foo"))
+		h.Prev("")
+		v, _ := h.Prev("")
+		expect(v).To(equal("bar"))
+		_, ok := h.Prev("")
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it drops the oldest values", func(expect expect.Expectation, h *prompt.History) {
+		h.Add("baz")
+		h.Add("qux")
+		var values []string
+		for v, ok := h.Prev(""); ok; v, ok = h.Prev("") {
+			values = append(values, v)
+		}
+		expect(values).To(equal([]string{"qux", "baz", "bar"}))
+	})
+
+	o.Spec("it ignores empty values", func(expect expect.Expectation, h *prompt.History) {
+		h.Add("")
+		expect(h.Last()).To(equal("bar"))
+	})
+}
+
+func TestTabs(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *prompt.Tabs) {
+		return expect.New(t), &prompt.Tabs{}
+	})
+
+	suggest := func(values ...string) func(string) []string {
+		return func(string) []string { return values }
+	}
+
+	o.Spec("it completes to the common prefix first", func(expect expect.Expectation, tabs *prompt.Tabs) {
+		s := suggest("NewFoo", "NewFooBar")
+		text := tabs.Complete("Ne", s)
+		expect(text).To(equal("NewFoo"))
+		text = tabs.Complete(text, s)
+		expect(text).To(equal("NewFoo"))
+		text = tabs.Complete(text, s)
+		expect(text).To(equal("NewFooBar"))
+		expect(tabs.Complete(text, s)).To(equal("NewFoo"))
+	})
+
+	o.Spec("it cycles when there is no common prefix", func(expect expect.Expectation, tabs *prompt.Tabs) {
+		s := suggest("fooBar", "barFoo")
+		text := tabs.Complete("fb", s)
+		expect(text).To(equal("fooBar"))
+		expect(tabs.Complete(text, s)).To(equal("barFoo"))
+	})
+
+	o.Spec("it suggests again after the text changes", func(expect expect.Expectation, tabs *prompt.Tabs) {
+		tabs.Complete("f", suggest("foo"))
+		expect(tabs.Complete("fo", suggest("four"))).To(equal("four"))
+	})
+
+	o.Spec("it leaves text alone without suggestions", func(expect expect.Expectation, tabs *prompt.Tabs) {
+		expect(tabs.Complete("x", suggest())).To(equal("x"))
+	})
+
+	o.Spec("it does not split runes", func(expect expect.Expectation, tabs *prompt.Tabs) {
+		expect(tabs.Complete("", suggest("aé", "aè"))).To(equal("a"))
+	})
+}
@@ -0,0 +1,80 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package commander
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/prompt"
+)
+
+// A selectAller is an input whose text can be selected, so that typing
+// replaces a value that was filled in from the history.
+type selectAller interface {
+	SelectAll()
+}
+
+// promptInput wraps a TextInput in the command box, handling the keys
+// for its history and tab completion before the input sees them.
+type promptInput struct {
+	TextInput
+
+	history   *prompt.History
+	suggester Suggester
+	tabs      prompt.Tabs
+}
+
+func newPromptInput(input TextInput, history *prompt.History, cmd interface{}) *promptInput {
+	p := &promptInput{TextInput: input, history: history}
+	p.suggester, _ = cmd.(Suggester)
+	history.Reset()
+	if input.Text() == "" && history.Last() != "" {
+		p.setText(history.Last())
+		if s, ok := input.(selectAller); ok {
+			s.SelectAll()
+		}
+	}
+	return p
+}
+
+func (p *promptInput) KeyPress(event gxui.KeyboardEvent) bool {
+	if event.Modifier != 0 {
+		return p.TextInput.KeyPress(event)
+	}
+	switch event.Key {
+	case gxui.KeyUp:
+		if text, ok := p.history.Prev(p.Text()); ok {
+			p.setText(text)
+		}
+		return true
+	case gxui.KeyDown:
+		if text, ok := p.history.Next(); ok {
+			p.setText(text)
+		}
+		return true
+	case gxui.KeyTab:
+		if p.suggester == nil {
+			break
+		}
+		p.setText(p.tabs.Complete(p.Text(), p.suggester.Suggest))
+		return true
+	}
+	return p.TextInput.KeyPress(event)
+}
+
+// setText replaces p's text, moving the caret to the end of it.
+func (p *promptInput) setText(text string) {
+	p.SetText(text)
+	if c, ok := p.TextInput.(Controllable); ok {
+		c.Controller().SetCaret(len([]rune(text)))
+	}
+}
+
+// historyKey is the key that the history of a command's input is kept
+// under.  Commands with more than one input have a history for each of
+// them.
+type historyKey struct {
+	cmd   string
+	input int
+}
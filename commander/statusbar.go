@@ -0,0 +1,163 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package commander
+
+import (
+	"strings"
+	"time"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// statusInterval is the time between refreshes of the status bar.
+const statusInterval = 250 * time.Millisecond
+
+var (
+	segmentColor = gxui.Gray80
+	modeColor    = gxui.Color{
+		R: 0.6,
+		G: 0.8,
+		B: 1,
+		A: 1,
+	}
+	taskColor    = status.ColorWarn
+	warningColor = status.ColorWarn
+
+	// spinnerFrames are displayed in turn, one per refresh, next to
+	// the background tasks that are running.
+	spinnerFrames = []string{"|", "/", "-", "\\"}
+)
+
+// A Moder is an input handler with editing modes, e.g. a modal
+// handler.
+type Moder interface {
+	Mode() input.Mode
+}
+
+// statusBar is the bar at the bottom of the window.  It displays the
+// text of each bound status.Segment, followed by the current input
+// mode (if the input handler has modes), a spinner and the progress
+// of any background tasks that are running, and any recent warnings
+// from background tasks.
+type statusBar struct {
+	mixins.LinearLayout
+
+	theme    *basic.Theme
+	segments []status.Segment
+	labels   []gxui.Label
+	mode     gxui.Label
+	tasks    gxui.Label
+	warnings gxui.Label
+	frame    int
+}
+
+func newStatusBar(theme *basic.Theme) *statusBar {
+	s := &statusBar{theme: theme}
+	s.Init(s, theme)
+	s.SetDirection(gxui.LeftToRight)
+	s.SetBorderPen(theme.ButtonDefaultStyle.Pen)
+	s.mode = s.newLabel(modeColor)
+	s.tasks = s.newLabel(taskColor)
+	s.warnings = s.newLabel(warningColor)
+	return s
+}
+
+func (s *statusBar) newLabel(color gxui.Color) gxui.Label {
+	l := s.theme.CreateLabel()
+	l.SetColor(color)
+	l.SetMargin(math.Spacing{L: 5, T: 1, R: 10, B: 1})
+	return l
+}
+
+// SetSegments replaces the segments that s displays.  It must be
+// called on the UI goroutine.
+func (s *statusBar) SetSegments(segments []status.Segment) {
+	s.segments = segments
+	s.labels = make([]gxui.Label, 0, len(segments))
+	for range segments {
+		s.labels = append(s.labels, s.newLabel(segmentColor))
+	}
+}
+
+// refresh updates the text of s for the focused editor, which may be
+// nil, and handler.  s is only laid out again if its text changed.
+// It must be called on the UI goroutine.
+func (s *statusBar) refresh(editor interface{}, handler input.Handler) {
+	var (
+		labels []gxui.Label
+		texts  []string
+	)
+	for i, seg := range s.segments {
+		if text := seg.Text(editor); text != "" {
+			labels = append(labels, s.labels[i])
+			texts = append(texts, text)
+		}
+	}
+	if moder, ok := handler.(Moder); ok {
+		labels = append(labels, s.mode)
+		texts = append(texts, strings.ToUpper(moder.Mode().String()))
+	}
+	if tasks := status.Tasks(); len(tasks) > 0 {
+		labels = append(labels, s.tasks)
+		s.frame = (s.frame + 1) % len(spinnerFrames)
+		texts = append(texts, spinnerFrames[s.frame]+" running: "+strings.Join(tasks, ", "))
+	}
+	if warnings := status.Warnings(); len(warnings) > 0 {
+		labels = append(labels, s.warnings)
+		texts = append(texts, strings.Join(warnings, "; "))
+	}
+	if s.unchanged(labels, texts) {
+		return
+	}
+	s.RemoveAll()
+	for i, l := range labels {
+		l.SetText(texts[i])
+		s.AddChild(l)
+	}
+}
+
+// unchanged returns whether or not s is already displaying texts in
+// labels.
+func (s *statusBar) unchanged(labels []gxui.Label, texts []string) bool {
+	children := s.Children()
+	if len(children) != len(labels) {
+		return false
+	}
+	for i, child := range children {
+		if child.Control != labels[i] || labels[i].Text() != texts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *statusBar) Paint(canvas gxui.Canvas) {
+	rect := s.Size().Rect()
+	s.BackgroundBorderPainter.PaintBackground(canvas, rect)
+	s.PaintChildren.Paint(canvas)
+	top := math.Rect{
+		Min: rect.Min,
+		Max: math.Point{
+			X: rect.Max.X,
+			Y: rect.Min.Y + 1,
+		},
+	}
+	s.BackgroundBorderPainter.PaintBorder(canvas, top)
+}
+
+func (s *statusBar) DesiredSize(min, max math.Size) math.Size {
+	size := s.LinearLayout.DesiredSize(min, max)
+	size.W = max.W
+	if h := s.theme.DefaultFont().GlyphMaxSize().H + 2; size.H < h {
+		// Keep the bar's height when every segment is hidden.
+		size.H = h
+	}
+	return size
+}
@@ -0,0 +1,31 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package main
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander"
+	"github.com/nelsam/vidar/setting"
+)
+
+// watchConfig applies changes to the settings and key bindings files
+// to w as soon as they're saved: cmdr is bound to keys again, and w's
+// theme and fonts are reloaded.  The files are only watched once, by
+// setting.WatchConfig, for every window.
+func watchConfig(driver gxui.Driver, cmdr *commander.Commander, w *window) {
+	setting.OnBindingsChange(func() {
+		driver.Call(cmdr.Remap)
+	})
+	setting.OnSettingsChange(func() {
+		t := loadTheme()
+		driver.Call(func() {
+			w.SetSyntaxTheme(t)
+			w.UpdateScale()
+			if font := setting.PrefFont(driver); font != nil {
+				w.SetFont(font)
+			}
+		})
+	})
+}
@@ -6,9 +6,18 @@ package controller
 
 import (
 	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
 	"github.com/nelsam/gxui/mixins"
 	"github.com/nelsam/gxui/themes/basic"
 	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/setting"
+)
+
+const (
+	// editorWeight and panelWeight are the relative sizes of the
+	// editor and the panels below it.
+	editorWeight = 3
+	panelWeight  = 1
 )
 
 type Navigator interface {
@@ -30,6 +39,15 @@ type Controller struct {
 	font      gxui.Font
 	navigator Navigator
 	editor    MultiEditor
+
+	// main holds the editor and any panels that are shown below
+	// it.
+	main   *mixins.SplitterLayout
+	panels []gxui.Control
+
+	// zen is set while the navigator is hidden and main is
+	// centered in c.
+	zen bool
 }
 
 func New(driver gxui.Driver, theme *basic.Theme) *Controller {
@@ -44,6 +62,11 @@ func (c *Controller) Init(driver gxui.Driver, theme *basic.Theme) {
 	c.theme = theme
 
 	c.SetDirection(gxui.LeftToRight)
+
+	c.main = &mixins.SplitterLayout{}
+	c.main.Init(c.main, theme)
+	c.main.SetOrientation(gxui.Vertical)
+	c.AddChild(c.main)
 }
 
 // Navigator returns c's Navigator instance.
@@ -52,10 +75,14 @@ func (c *Controller) Navigator() Navigator {
 }
 
 func (c *Controller) Elements() []interface{} {
-	return []interface{}{
+	elements := []interface{}{
 		c.navigator,
 		c.editor,
 	}
+	for _, p := range c.panels {
+		elements = append(elements, p)
+	}
+	return elements
 }
 
 // SetNavigator sets c's Navigator instance.
@@ -64,11 +91,9 @@ func (c *Controller) SetNavigator(navigator Navigator) {
 		c.RemoveChild(c.navigator)
 	}
 	c.navigator = navigator
-	if c.navigator != nil {
-		c.AddChild(c.navigator)
-
-		// Reset the editor, since it should come after the navigator.
-		c.SetEditor(c.editor)
+	if c.navigator != nil && !c.zen {
+		// The navigator should always be to the left of the editor.
+		c.AddChildAt(0, c.navigator)
 	}
 }
 
@@ -80,10 +105,105 @@ func (c *Controller) Editor() MultiEditor {
 // SetEditor sets c's Editor instance.
 func (c *Controller) SetEditor(editor MultiEditor) {
 	if c.editor != nil {
-		c.RemoveChild(c.editor)
+		c.main.RemoveChild(c.editor)
 	}
 	c.editor = editor
 	if c.editor != nil {
-		c.AddChild(c.editor)
+		c.main.AddChildAt(0, c.editor)
+		c.main.SetChildWeight(c.editor, editorWeight)
+	}
+}
+
+// ShowPanel shows p below the editor.  Nothing happens if p is
+// already shown.  Panels from shared bindables may be shown in another
+// window, in which case p is moved to this one.
+func (c *Controller) ShowPanel(p gxui.Control) {
+	if c.HasPanel(p) {
+		return
+	}
+	c.forget(p)
+	if parent, ok := p.Parent().(gxui.Container); ok {
+		parent.RemoveChild(p)
+	}
+	c.panels = append(c.panels, p)
+	c.main.AddChild(p)
+	c.main.SetChildWeight(p, panelWeight)
+}
+
+// HidePanel removes p from below the editor.
+func (c *Controller) HidePanel(p gxui.Control) {
+	if c.HasPanel(p) {
+		c.main.RemoveChild(p)
+	}
+	c.forget(p)
+}
+
+// HasPanel returns whether or not p is currently shown below the
+// editor.
+func (c *Controller) HasPanel(p gxui.Control) bool {
+	if p.Parent() != gxui.Parent(c.main) {
+		// p was moved to another window.
+		return false
+	}
+	for _, panel := range c.panels {
+		if panel == p {
+			return true
+		}
+	}
+	return false
+}
+
+// forget removes p from c's panels without touching c.main.
+func (c *Controller) forget(p gxui.Control) {
+	for i, panel := range c.panels {
+		if panel == p {
+			c.panels = append(c.panels[:i], c.panels[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetZen turns zen mode on or off.  In zen mode, the navigator is
+// hidden and the editor is centered, no wider than the zenwidth
+// setting.
+func (c *Controller) SetZen(zen bool) {
+	if zen == c.zen {
+		return
+	}
+	c.zen = zen
+	if c.navigator != nil {
+		if zen {
+			c.RemoveChild(c.navigator)
+		} else {
+			c.AddChildAt(0, c.navigator)
+		}
+	}
+	c.Relayout()
+}
+
+func (c *Controller) LayoutChildren() {
+	if !c.zen {
+		c.LinearLayout.LayoutChildren()
+		return
+	}
+	s := c.Size().Contract(c.Padding())
+	o := c.Padding().LT()
+	width := s.W
+	if max := setting.ZenWidth() * c.columnWidth(); max < width {
+		width = max
+	}
+	left := (s.W - width) / 2
+	for _, child := range c.Children() {
+		child.Layout(math.CreateRect(left, 0, left+width, s.H).Offset(o))
+	}
+}
+
+// columnWidth returns the width of a single column of text in c's
+// editor.
+func (c *Controller) columnWidth() int {
+	font := c.font
+	if font == nil {
+		font = c.theme.DefaultMonospaceFont()
 	}
+	return font.GlyphMaxSize().W
 }
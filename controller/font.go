@@ -0,0 +1,43 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package controller
+
+import "github.com/nelsam/gxui"
+
+// A Fonter is a type that can change its font.  Fonters that contain
+// other controls are expected to update the font of their children.
+type Fonter interface {
+	SetFont(gxui.Font)
+}
+
+// SetFont changes the font of c.  If c is not a Fonter, the font of
+// each of its children is changed instead.
+func SetFont(c gxui.Control, font gxui.Font) {
+	if f, ok := c.(Fonter); ok {
+		f.SetFont(font)
+		return
+	}
+	parent, ok := c.(gxui.Parent)
+	if !ok {
+		return
+	}
+	for _, child := range parent.Children() {
+		SetFont(child.Control, font)
+	}
+}
+
+// SetFont changes the font of c's navigator, editor, and panels.
+func (c *Controller) SetFont(font gxui.Font) {
+	c.font = font
+	if c.navigator != nil {
+		SetFont(c.navigator, font)
+	}
+	if c.editor != nil {
+		SetFont(c.editor, font)
+	}
+	for _, p := range c.panels {
+		SetFont(p, font)
+	}
+}
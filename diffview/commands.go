@@ -0,0 +1,203 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package diffview contains a side-by-side diff viewer and the
+// commands that open it.
+package diffview
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/scm"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/eol"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	p := &panel{driver: driver, theme: theme}
+	return []bind.Bindable{
+		NewAgainstSaved(theme, p),
+		NewAgainstHead(theme, p),
+		NewFileHistory(theme, p),
+	}
+}
+
+// A Paneler is a type that can display panels below the editor.
+type Paneler interface {
+	ShowPanel(gxui.Control)
+	HidePanel(gxui.Control)
+}
+
+// A Projecter is a type that knows which project is currently open.
+type Projecter interface {
+	Project() setting.Project
+}
+
+// panel holds the View that both diff commands display, so that
+// running either of them replaces the diff that is shown, along with
+// the History that file-history displays.
+type panel struct {
+	driver  gxui.Driver
+	theme   *basic.Theme
+	view    *View
+	history *History
+}
+
+func (p *panel) show(paneler Paneler, title string, d Diff) {
+	if p.view == nil {
+		p.view = NewView(p.driver, p.theme)
+	}
+	v := p.view
+	v.OnClose(func() { paneler.HidePanel(v) })
+	v.SetDiff(title, d)
+	paneler.ShowPanel(v)
+}
+
+func (p *panel) showHistory(paneler Paneler, path string, environ []string, text string, commits []scm.Commit, i int, open func(string)) {
+	if p.history == nil {
+		p.history = NewHistory(p.driver, p.theme)
+	}
+	h := p.history
+	h.OnClose(func() { paneler.HidePanel(h) })
+	h.SetHistory(path, environ, text, commits, i, open)
+	paneler.ShowPanel(h)
+}
+
+// differ is embedded by the commands which diff the current editor's
+// text against another version of its file.
+type differ struct {
+	status.General
+
+	panel *panel
+
+	editor  input.Editor
+	paneler Paneler
+}
+
+func (d *differ) Menu() string {
+	return "View"
+}
+
+func (d *differ) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (d *differ) Reset() {
+	d.Clear()
+	d.editor = nil
+	d.paneler = nil
+}
+
+func (d *differ) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case input.Editor:
+		d.editor = src
+	case Paneler:
+		d.paneler = src
+	}
+	if d.editor == nil || d.paneler == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+// show displays the differences between old and the editor's text.
+func (d *differ) show(title, old string) {
+	// Editors hold their text with LF line endings, whatever the
+	// file uses.
+	diff := Compute(eol.Normalize(old), d.editor.Text())
+	d.panel.show(d.paneler, title, diff)
+	switch n := len(diff.Hunks); n {
+	case 0:
+		d.Info = "No changes"
+	case 1:
+		d.Info = "1 change"
+	default:
+		d.Info = fmt.Sprintf("%d changes", n)
+	}
+}
+
+// AgainstSaved is a command which shows the differences between the
+// current editor's text and its file on disk.
+type AgainstSaved struct {
+	differ
+}
+
+func NewAgainstSaved(theme gxui.Theme, p *panel) *AgainstSaved {
+	a := &AgainstSaved{}
+	a.Theme = theme
+	a.panel = p
+	return a
+}
+
+func (a *AgainstSaved) Name() string {
+	return "diff-against-saved"
+}
+
+func (a *AgainstSaved) Exec() error {
+	path := a.editor.Filepath()
+	saved, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		a.Err = fmt.Sprintf("Could not read %s: %s", path, err)
+		return err
+	}
+	a.show(fmt.Sprintf("%s: saved / unsaved", filepath.Base(path)), string(saved))
+	return nil
+}
+
+// AgainstHead is a command which shows the differences between the
+// current editor's text and its file as of the last git commit.
+type AgainstHead struct {
+	differ
+
+	projecter Projecter
+}
+
+func NewAgainstHead(theme gxui.Theme, p *panel) *AgainstHead {
+	a := &AgainstHead{}
+	a.Theme = theme
+	a.panel = p
+	return a
+}
+
+func (a *AgainstHead) Name() string {
+	return "diff-against-head"
+}
+
+func (a *AgainstHead) Reset() {
+	a.differ.Reset()
+	a.projecter = nil
+}
+
+func (a *AgainstHead) Store(elem interface{}) bind.Status {
+	if p, ok := elem.(Projecter); ok {
+		a.projecter = p
+	}
+	if a.differ.Store(elem) == bind.Waiting || a.projecter == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (a *AgainstHead) Exec() error {
+	path := a.editor.Filepath()
+	head, err := scm.Head(path, a.projecter.Project().Environ())
+	if err != nil {
+		a.Err = fmt.Sprintf("Could not read %s from HEAD: %s", filepath.Base(path), err)
+		return err
+	}
+	a.show(fmt.Sprintf("%s: HEAD / current", filepath.Base(path)), head)
+	return nil
+}
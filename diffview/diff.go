@@ -0,0 +1,213 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package diffview
+
+import (
+	"strings"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// op is a single step in an edit script.
+type op int
+
+const (
+	opEqual op = iota
+	opDelete
+	opInsert
+)
+
+// Row is one line of a side-by-side diff.  Old and New are the
+// indexes of the lines displayed on each side, or -1 if the side is
+// blank because the other side's line was added or removed.
+type Row struct {
+	Old, New int
+
+	// Changed is set for rows that are part of a hunk.
+	Changed bool
+}
+
+// Diff is a line-by-line comparison of two texts.
+type Diff struct {
+	Old, New []string
+
+	// Rows line up the two texts, so that unchanged lines are
+	// displayed side by side.
+	Rows []Row
+
+	// Hunks are the indexes of the first row of each group of
+	// changed rows.
+	Hunks []int
+}
+
+// Compute returns the differences between old and new.
+func Compute(old, new string) Diff {
+	d := Diff{
+		Old: strings.Split(old, "\n"),
+		New: strings.Split(new, "\n"),
+	}
+	d.Rows = align(editScript(d.Old, d.New))
+	for i, r := range d.Rows {
+		if r.Changed && (i == 0 || !d.Rows[i-1].Changed) {
+			d.Hunks = append(d.Hunks, i)
+		}
+	}
+	return d
+}
+
+// Intraline returns the runes that differ between old and new, as a
+// span in each of them.  Everything outside of the spans is the same
+// in both lines.
+func Intraline(old, new string) (oldSpan, newSpan input.Span) {
+	a, b := []rune(old), []rune(new)
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return input.Span{Start: prefix, End: len(a) - suffix}, input.Span{Start: prefix, End: len(b) - suffix}
+}
+
+// align turns an edit script into rows.  Lines that were removed are
+// lined up with the lines that were added in their place.
+func align(ops []op) []Row {
+	var (
+		rows     []Row
+		old, new int
+		dels     []int
+		ins      []int
+	)
+	flush := func() {
+		for i := 0; i < len(dels) || i < len(ins); i++ {
+			r := Row{Old: -1, New: -1, Changed: true}
+			if i < len(dels) {
+				r.Old = dels[i]
+			}
+			if i < len(ins) {
+				r.New = ins[i]
+			}
+			rows = append(rows, r)
+		}
+		dels, ins = dels[:0], ins[:0]
+	}
+	for _, o := range ops {
+		switch o {
+		case opDelete:
+			dels = append(dels, old)
+			old++
+		case opInsert:
+			ins = append(ins, new)
+			new++
+		default:
+			flush()
+			rows = append(rows, Row{Old: old, New: new})
+			old++
+			new++
+		}
+	}
+	flush()
+	return rows
+}
+
+// editScript returns the shortest edit script that turns a into b,
+// using Myers' algorithm.  Lines that a and b start and end with are
+// trimmed first, since they're common and the algorithm is quadratic
+// in the number of differences.
+func editScript(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ops := make([]op, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, opEqual)
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for i := 0; i < suffix; i++ {
+		ops = append(ops, opEqual)
+	}
+	return ops
+}
+
+func myers(a, b []string) []op {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+	// v holds the furthest x reached on each diagonal k, at
+	// v[off+k].  trace holds the part of v that each round
+	// started with, which is all that's needed to walk back
+	// through the rounds.
+	off := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[off-d-1:off+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, n, m)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks back through the rounds in trace, from the end of
+// both texts to the start, returning the edits that were made.
+func backtrack(trace [][]int, n, m int) []op {
+	var ops []op
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, opEqual)
+			x--
+			y--
+		}
+		if prevK == k+1 {
+			ops = append(ops, opInsert)
+		} else {
+			ops = append(ops, opDelete)
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, opEqual)
+		x--
+		y--
+	}
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
@@ -0,0 +1,113 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package diffview_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/diffview"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+type Expectation = expect.Expectation
+
+var (
+	Equal   = matchers.Equal
+	HaveLen = matchers.HaveLen
+)
+
+// sides returns the text displayed on each side of d, with blank
+// sides shown as "-".
+func sides(d diffview.Diff) (old, new []string) {
+	for _, r := range d.Rows {
+		o, n := "-", "-"
+		if r.Old >= 0 {
+			o = d.Old[r.Old]
+		}
+		if r.New >= 0 {
+			n = d.New[r.New]
+		}
+		old = append(old, o)
+		new = append(new, n)
+	}
+	return old, new
+}
+
+func TestCompute(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it has no hunks for equal texts", func(expect Expectation) {
+		d := diffview.Compute("a\nb\nc", "a\nb\nc")
+		expect(d.Rows).To(HaveLen(3))
+		expect(d.Hunks).To(HaveLen(0))
+	})
+
+	o.Spec("it lines up added and removed lines", func(expect Expectation) {
+		d := diffview.Compute("a\nb\nc\nd", "a\nc\nx\nd\ne")
+		old, new := sides(d)
+		expect(old).To(Equal([]string{"a", "b", "c", "-", "d", "-"}))
+		expect(new).To(Equal([]string{"a", "-", "c", "x", "d", "e"}))
+		expect(d.Hunks).To(Equal([]int{1, 3, 5}))
+	})
+
+	o.Spec("it pairs changed lines side by side", func(expect Expectation) {
+		d := diffview.Compute("a\nfoo\nbar\nz", "a\nfoo2\nz")
+		old, new := sides(d)
+		expect(old).To(Equal([]string{"a", "foo", "bar", "z"}))
+		expect(new).To(Equal([]string{"a", "foo2", "-", "z"}))
+		expect(d.Hunks).To(Equal([]int{1}))
+	})
+
+	o.Spec("it handles texts that are empty", func(expect Expectation) {
+		d := diffview.Compute("", "a\nb")
+		old, new := sides(d)
+		expect(old).To(Equal([]string{"", "-"}))
+		expect(new).To(Equal([]string{"a", "b"}))
+	})
+
+	o.Spec("it finds the shortest diff of larger texts", func(expect Expectation) {
+		old := strings.Repeat("x\ny\n", 50)
+		new := strings.Replace(old, "y\n", "z\n", 3)
+		d := diffview.Compute(old, new)
+		changed := 0
+		for _, r := range d.Rows {
+			if r.Changed {
+				changed++
+			}
+		}
+		expect(changed).To(Equal(3))
+		expect(d.Hunks).To(HaveLen(3))
+	})
+}
+
+func TestIntraline(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it returns the runes between the common prefix and suffix", func(expect Expectation) {
+		old, new := diffview.Intraline("return foo(a, b)", "return foobar(a, b)")
+		expect(old).To(Equal(input.Span{Start: 10, End: 10}))
+		expect(new).To(Equal(input.Span{Start: 10, End: 13}))
+	})
+
+	o.Spec("it doesn't let the prefix and suffix overlap", func(expect Expectation) {
+		old, new := diffview.Intraline("aa", "aaa")
+		expect(old).To(Equal(input.Span{Start: 2, End: 2}))
+		expect(new).To(Equal(input.Span{Start: 2, End: 3}))
+	})
+}
@@ -0,0 +1,307 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package diffview
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/command/fs"
+	"github.com/nelsam/vidar/command/scm"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/eol"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/scoring"
+)
+
+const (
+	// historyLimit is the number of commits that file-history
+	// lists.
+	historyLimit = 200
+
+	// shownCommits is the number of matching commits that are
+	// displayed while file-history's filter is being typed.
+	shownCommits = 8
+)
+
+var commitMatchColor = gxui.Color{R: 0.4, G: 0.8, B: 0.3, A: 1}
+
+// History is a gxui control that steps through the commits that
+// changed a file.  Each revision is diffed against the file's current
+// text, and can be opened in a read-only editor.
+type History struct {
+	mixins.LinearLayout
+
+	commit gxui.Label
+	diff   *View
+
+	path    string
+	environ []string
+	text    string
+	commits []scm.Commit
+	current int
+	open    func(path string)
+	onClose func()
+}
+
+// NewHistory creates an empty History.
+func NewHistory(driver gxui.Driver, theme *basic.Theme) *History {
+	h := &History{}
+	h.LinearLayout.Init(h, theme)
+	h.SetDirection(gxui.TopToBottom)
+
+	header := theme.CreateLinearLayout()
+	header.SetDirection(gxui.LeftToRight)
+	header.AddChild(h.button(theme, "Older", h.Older))
+	header.AddChild(h.button(theme, "Newer", h.Newer))
+	header.AddChild(h.button(theme, "Open", h.openCurrent))
+	h.commit = theme.CreateLabel()
+	h.commit.SetMargin(math.Spacing{L: 10, T: 2, R: 10, B: 2})
+	header.AddChild(h.commit)
+	h.AddChild(header)
+
+	h.diff = NewView(driver, theme)
+	h.diff.OnClose(func() {
+		if h.onClose != nil {
+			h.onClose()
+		}
+	})
+	h.AddChild(h.diff)
+	return h
+}
+
+func (h *History) button(theme *basic.Theme, text string, onClick func()) gxui.Button {
+	b := theme.CreateButton()
+	b.SetText(text)
+	b.SetMargin(math.Spacing{L: 2, R: 2})
+	b.OnClick(func(gxui.MouseEvent) { onClick() })
+	return b
+}
+
+// OnClose sets the function that is called when h's close button is
+// clicked.
+func (h *History) OnClose(f func()) {
+	h.onClose = f
+}
+
+// SetHistory makes h step through commits, which changed the file at
+// path, and shows the commit at index i.  text is the current text of
+// the file, which each revision is diffed against.  Opening a revision
+// calls open with the path to a read-only copy of it.
+func (h *History) SetHistory(path string, environ []string, text string, commits []scm.Commit, i int, open func(path string)) {
+	h.path = path
+	h.environ = environ
+	h.text = text
+	h.commits = commits
+	h.open = open
+	h.show(i)
+}
+
+// Older shows the commit before the current one.
+func (h *History) Older() {
+	if h.current+1 < len(h.commits) {
+		h.show(h.current + 1)
+	}
+}
+
+// Newer shows the commit after the current one.
+func (h *History) Newer() {
+	if h.current > 0 {
+		h.show(h.current - 1)
+	}
+}
+
+func (h *History) show(i int) {
+	h.current = i
+	c := h.commits[i]
+	h.commit.SetText(fmt.Sprintf("%d of %d: %.8s by %s on %s: %s",
+		i+1, len(h.commits), c.ID, c.Author, c.Time.Format("2006-01-02"), c.Summary))
+	old, err := scm.Revision(h.path, c.ID, h.environ)
+	if err != nil {
+		h.diff.SetDiff(fmt.Sprintf("Could not load %s at %.8s: %s", filepath.Base(h.path), c.ID, err), Diff{})
+		return
+	}
+	// The current text comes from an editor, which always uses LF
+	// line endings.
+	h.diff.SetDiff(fmt.Sprintf("%s: %.8s / current", filepath.Base(h.path), c.ID), Compute(eol.Normalize(old), h.text))
+}
+
+// openCurrent opens the revision that h is showing in a read-only
+// editor.
+func (h *History) openCurrent() {
+	if len(h.commits) == 0 {
+		return
+	}
+	c := h.commits[h.current]
+	path, err := scm.WriteRevision(h.path, c.ID, h.environ)
+	if err != nil {
+		h.commit.SetText(fmt.Sprintf("Could not open %s at %.8s: %s", filepath.Base(h.path), c.ID, err))
+		return
+	}
+	h.open(path)
+}
+
+// FileHistory is a command which lists the commits that changed the
+// current file.  Typing filters the commits by their ID and summary,
+// and the first match is diffed against the file's current text in a
+// History below the editor.
+type FileHistory struct {
+	status.General
+
+	theme *basic.Theme
+	panel *panel
+
+	filter  gxui.TextBox
+	matches gxui.LinearLayout
+	input   <-chan gxui.Focusable
+
+	path    string
+	environ []string
+	commits []scm.Commit
+	loadErr error
+	choice  int
+
+	editor  input.Editor
+	paneler Paneler
+	focuser scm.Focuser
+	execer  scm.Executor
+}
+
+func NewFileHistory(theme *basic.Theme, p *panel) *FileHistory {
+	f := &FileHistory{
+		theme:   theme,
+		panel:   p,
+		filter:  theme.CreateTextBox(),
+		matches: theme.CreateLinearLayout(),
+	}
+	f.Theme = theme
+	f.filter.SetDesiredWidth(math.MaxSize.W)
+	f.filter.OnTextChanged(func([]gxui.TextBoxEdit) {
+		f.update()
+	})
+	f.matches.SetDirection(gxui.LeftToRight)
+	return f
+}
+
+func (f *FileHistory) Name() string {
+	return "file-history"
+}
+
+func (f *FileHistory) Menu() string {
+	return "View"
+}
+
+func (f *FileHistory) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (f *FileHistory) Start(control gxui.Control) gxui.Control {
+	f.path = fs.CurrentFile(control)
+	f.environ = fs.CurrentProject(control).Environ()
+	f.commits, f.loadErr = nil, nil
+	if f.path != "" {
+		f.commits, f.loadErr = scm.History(f.path, historyLimit, f.environ)
+	}
+	f.filter.SetText("")
+	f.update()
+
+	input := make(chan gxui.Focusable, 1)
+	if len(f.commits) > 0 {
+		input <- f.filter
+	}
+	close(input)
+	f.input = input
+	return f.matches
+}
+
+func (f *FileHistory) Next() gxui.Focusable {
+	return <-f.input
+}
+
+// update displays the commits that match the current filter, in order
+// of how well they match.
+func (f *FileHistory) update() {
+	names := make([]string, 0, len(f.commits))
+	index := make(map[string]int, len(f.commits))
+	for i, c := range f.commits {
+		name := fmt.Sprintf("%.8s %s", c.ID, c.Summary)
+		names = append(names, name)
+		index[name] = i
+	}
+	if partial := f.filter.Text(); partial != "" {
+		names = scoring.Sort(names, partial)
+	}
+	f.choice = -1
+	f.matches.RemoveAll()
+	for i, n := range names {
+		if i == shownCommits {
+			break
+		}
+		l := f.theme.CreateLabel()
+		l.SetMargin(math.Spacing{L: 5, R: 5})
+		l.SetText(n)
+		if i == 0 {
+			f.choice = index[n]
+			l.SetColor(commitMatchColor)
+		}
+		f.matches.AddChild(l)
+	}
+}
+
+func (f *FileHistory) Reset() {
+	f.Clear()
+	f.editor = nil
+	f.paneler = nil
+	f.focuser = nil
+	f.execer = nil
+}
+
+func (f *FileHistory) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case input.Editor:
+		f.editor = src
+	case Paneler:
+		f.paneler = src
+	case scm.Focuser:
+		f.focuser = src
+	case scm.Executor:
+		f.execer = src
+	}
+	if f.editor == nil || f.paneler == nil || f.focuser == nil || f.execer == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (f *FileHistory) Exec() error {
+	name := filepath.Base(f.path)
+	switch {
+	case f.path == "":
+		f.Warn = "No file is open"
+		return nil
+	case f.loadErr != nil:
+		f.Err = fmt.Sprintf("Could not load the history of %s: %s", name, f.loadErr)
+		return f.loadErr
+	case len(f.commits) == 0:
+		f.Info = fmt.Sprintf("%s has not been committed", name)
+		return nil
+	case f.choice < 0:
+		f.Err = "no commits match"
+		return fmt.Errorf("file-history: %s", f.Err)
+	}
+	focuser, execer := f.focuser, f.execer
+	open := func(path string) {
+		execer.Execute(focuser.For(focus.Path(path)))
+	}
+	f.panel.showHistory(f.paneler, f.path, f.environ, f.editor.Text(), f.commits, f.choice, open)
+	f.Info = fmt.Sprintf("%d commits changed %s", len(f.commits), name)
+	return nil
+}
@@ -0,0 +1,235 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package diffview
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/themes/basic"
+)
+
+var (
+	removedLine = gxui.Color{R: 0.35, G: 0.1, B: 0.1, A: 1}
+	removedText = gxui.Color{R: 0.6, G: 0.15, B: 0.15, A: 1}
+	addedLine   = gxui.Color{R: 0.1, G: 0.3, B: 0.1, A: 1}
+	addedText   = gxui.Color{R: 0.15, G: 0.5, B: 0.15, A: 1}
+	blankLine   = gxui.Color{R: 0.15, G: 0.15, B: 0.15, A: 1}
+)
+
+// View is a gxui control that displays a Diff in two panes, with the
+// old text on the left and the new text on the right.  Changed lines
+// are highlighted, along with the part of each changed line that is
+// different.
+type View struct {
+	mixins.LinearLayout
+
+	title   gxui.Label
+	hunkPos gxui.Label
+	old     *pane
+	new     *pane
+
+	diff    Diff
+	hunk    int
+	onClose func()
+}
+
+// NewView creates an empty View.
+func NewView(driver gxui.Driver, theme *basic.Theme) *View {
+	v := &View{}
+	v.LinearLayout.Init(v, theme)
+	v.SetDirection(gxui.TopToBottom)
+
+	header := theme.CreateLinearLayout()
+	header.SetDirection(gxui.LeftToRight)
+	v.title = theme.CreateLabel()
+	v.title.SetMargin(math.Spacing{L: 3, T: 2, R: 10, B: 2})
+	header.AddChild(v.title)
+	header.AddChild(v.button(theme, "Previous", v.PrevHunk))
+	header.AddChild(v.button(theme, "Next", v.NextHunk))
+	v.hunkPos = theme.CreateLabel()
+	v.hunkPos.SetMargin(math.Spacing{L: 10, T: 2, R: 10, B: 2})
+	header.AddChild(v.hunkPos)
+	header.AddChild(v.button(theme, "Close", func() {
+		if v.onClose != nil {
+			v.onClose()
+		}
+	}))
+	v.AddChild(header)
+
+	panes := theme.CreateSplitterLayout()
+	panes.SetOrientation(gxui.Horizontal)
+	v.old = newPane(v, driver, theme)
+	v.new = newPane(v, driver, theme)
+	panes.AddChild(v.old)
+	panes.AddChild(v.new)
+	v.AddChild(panes)
+	return v
+}
+
+func (v *View) button(theme *basic.Theme, text string, onClick func()) gxui.Button {
+	b := theme.CreateButton()
+	b.SetText(text)
+	b.SetMargin(math.Spacing{L: 2, R: 2})
+	b.OnClick(func(gxui.MouseEvent) { onClick() })
+	return b
+}
+
+// OnClose sets the function that is called when v's close button is
+// clicked.
+func (v *View) OnClose(f func()) {
+	v.onClose = f
+}
+
+// SetDiff displays d in v, under title, and shows its first hunk.
+func (v *View) SetDiff(title string, d Diff) {
+	v.diff = d
+	v.title.SetText(title)
+	v.old.show(d, oldSide)
+	v.new.show(d, newSide)
+	v.hunk = 0
+	if len(d.Hunks) == 0 {
+		v.hunkPos.SetText("No changes")
+		return
+	}
+	v.showHunk()
+}
+
+// NextHunk shows the hunk after the current one, wrapping around to
+// the first.
+func (v *View) NextHunk() {
+	if len(v.diff.Hunks) == 0 {
+		return
+	}
+	v.hunk = (v.hunk + 1) % len(v.diff.Hunks)
+	v.showHunk()
+}
+
+// PrevHunk shows the hunk before the current one, wrapping around to
+// the last.
+func (v *View) PrevHunk() {
+	if len(v.diff.Hunks) == 0 {
+		return
+	}
+	v.hunk = (v.hunk + len(v.diff.Hunks) - 1) % len(v.diff.Hunks)
+	v.showHunk()
+}
+
+func (v *View) showHunk() {
+	row := v.diff.Hunks[v.hunk]
+	v.old.showRow(row)
+	v.new.showRow(row)
+	v.hunkPos.SetText(fmt.Sprintf("Change %d of %d", v.hunk+1, len(v.diff.Hunks)))
+}
+
+// side is which of a diff's texts a pane displays.
+type side int
+
+const (
+	oldSide side = iota
+	newSide
+)
+
+// pane is one side of a View.  Its text can be navigated and selected,
+// but not edited.
+type pane struct {
+	mixins.CodeEditor
+
+	view *View
+}
+
+func newPane(v *View, driver gxui.Driver, theme *basic.Theme) *pane {
+	p := &pane{view: v}
+	p.CodeEditor.Init(p, driver, theme, theme.DefaultMonospaceFont())
+	p.SetScrollBarEnabled(true)
+	p.SetDesiredWidth(math.MaxSize.W)
+	p.SetTextColor(theme.TextBoxDefaultStyle.FontColor)
+	p.SetBackgroundBrush(theme.TextBoxDefaultStyle.Brush)
+	p.SetMargin(math.Spacing{L: 3, T: 3, R: 3, B: 3})
+	p.SetPadding(math.Spacing{L: 3, T: 3, R: 3, B: 3})
+	p.SetBorderPen(gxui.TransparentPen)
+	return p
+}
+
+// show displays one side of d in p.  Rows that are blank on this side
+// are displayed as empty lines, so that both panes line up.
+func (p *pane) show(d Diff, s side) {
+	lines, other := d.Old, d.New
+	line := func(r Row) (int, int) { return r.Old, r.New }
+	lineColor, textColor := removedLine, removedText
+	if s == newSide {
+		lines, other = d.New, d.Old
+		line = func(r Row) (int, int) { return r.New, r.Old }
+		lineColor, textColor = addedLine, addedText
+	}
+
+	changed := gxui.CreateCodeSyntaxLayer()
+	changed.SetBackgroundColor(lineColor)
+	intraline := gxui.CreateCodeSyntaxLayer()
+	intraline.SetBackgroundColor(textColor)
+	blank := gxui.CreateCodeSyntaxLayer()
+	blank.SetBackgroundColor(blankLine)
+
+	var text strings.Builder
+	start := 0
+	for i, r := range d.Rows {
+		if i > 0 {
+			text.WriteRune('\n')
+			start++
+		}
+		this, that := line(r)
+		if this < 0 {
+			blank.Add(start, 1)
+			continue
+		}
+		l := lines[this]
+		length := utf8.RuneCountInString(l)
+		text.WriteString(l)
+		if r.Changed {
+			changed.Add(start, length+1)
+			if that >= 0 {
+				span, _ := Intraline(l, other[that])
+				intraline.Add(start+span.Start, span.End-span.Start)
+			}
+		}
+		start += length
+	}
+	p.SetText(text.String())
+	p.SetSyntaxLayers(gxui.CodeSyntaxLayers{changed, intraline, blank})
+}
+
+// showRow scrolls p to row and puts the caret at its start.
+func (p *pane) showRow(row int) {
+	p.Controller().SetCaret(p.LineStart(row))
+	p.ScrollToLine(row)
+}
+
+// KeyStroke ignores typed text, since p can't be edited.
+func (p *pane) KeyStroke(gxui.KeyStrokeEvent) bool {
+	return true
+}
+
+// KeyPress handles the keys that move around in p, along with F7 and
+// shift-F7 to move between hunks.  Other keys are ignored so that
+// they can't edit p's text.
+func (p *pane) KeyPress(event gxui.KeyboardEvent) bool {
+	switch event.Key {
+	case gxui.KeyF7:
+		if event.Modifier.Shift() {
+			p.view.PrevHunk()
+			return true
+		}
+		p.view.NextHunk()
+		return true
+	case gxui.KeyLeft, gxui.KeyRight, gxui.KeyUp, gxui.KeyDown,
+		gxui.KeyHome, gxui.KeyEnd, gxui.KeyPageUp, gxui.KeyPageDown:
+		return p.CodeEditor.KeyPress(event)
+	}
+	return event.Modifier == 0 || event.Modifier == gxui.ModShift
+}
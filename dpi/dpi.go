@@ -0,0 +1,72 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package dpi works out how much vidar's UI should be scaled by on a
+// monitor, from the monitor's resolution and physical size.
+package dpi
+
+import "math"
+
+const (
+	// Base is the DPI that a scale of 1 is meant for.
+	Base = 96
+
+	// MaxScale is the largest scale that is detected.  Monitors that
+	// report larger sizes are usually reporting them wrong.
+	MaxScale = 4
+
+	// step is the increment that scales are rounded to, so that
+	// small differences in reported sizes don't change the scale.
+	step = 0.25
+)
+
+// Monitor is the position, resolution, and physical size of a
+// monitor.  Positions and resolutions are in screen pixels; physical
+// sizes are in millimeters, and are 0 if the monitor didn't report
+// them.
+type Monitor struct {
+	X, Y          int
+	Width, Height int
+
+	WidthMM, HeightMM int
+}
+
+// Contains returns whether or not the screen pixel at x, y is on m.
+func (m Monitor) Contains(x, y int) bool {
+	return x >= m.X && x < m.X+m.Width && y >= m.Y && y < m.Y+m.Height
+}
+
+// DPI returns m's horizontal resolution in dots per inch, or 0 if
+// m's physical size is unknown.
+func (m Monitor) DPI() float64 {
+	if m.WidthMM <= 0 || m.Width <= 0 {
+		return 0
+	}
+	return float64(m.Width) / (float64(m.WidthMM) / 25.4)
+}
+
+// At returns the monitor in monitors that the screen pixel at x, y
+// is on.  The returned bool will be false if it's not on any of them.
+func At(monitors []Monitor, x, y int) (Monitor, bool) {
+	for _, m := range monitors {
+		if m.Contains(x, y) {
+			return m, true
+		}
+	}
+	return Monitor{}, false
+}
+
+// Scale returns the scale for a monitor with dpi dots per inch,
+// rounded to the nearest quarter.  Scales are never smaller than 1
+// or larger than MaxScale, and an unknown dpi of 0 has a scale of 1.
+func Scale(dpi float64) float32 {
+	s := math.Round(dpi/Base/step) * step
+	switch {
+	case s < 1:
+		return 1
+	case s > MaxScale:
+		return MaxScale
+	}
+	return float32(s)
+}
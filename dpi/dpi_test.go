@@ -0,0 +1,83 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package dpi_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/dpi"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal   = matchers.Equal
+	beTrue  = matchers.BeTrue
+	beFalse = matchers.BeFalse
+)
+
+func TestScale(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it computes DPI from the physical width", func(expect expect.Expectation) {
+		m := dpi.Monitor{Width: 3840, Height: 2160, WidthMM: 610, HeightMM: 343}
+		expect(m.DPI() > 159 && m.DPI() < 160).To(beTrue())
+		expect(dpi.Scale(m.DPI())).To(equal(float32(1.75)))
+	})
+
+	o.Spec("it doesn't scale monitors without a physical size", func(expect expect.Expectation) {
+		m := dpi.Monitor{Width: 1920, Height: 1080}
+		expect(m.DPI()).To(equal(0.0))
+		expect(dpi.Scale(m.DPI())).To(equal(float32(1)))
+	})
+
+	o.Spec("it rounds to the nearest quarter", func(expect expect.Expectation) {
+		expect(dpi.Scale(96)).To(equal(float32(1)))
+		expect(dpi.Scale(120)).To(equal(float32(1.25)))
+		expect(dpi.Scale(192)).To(equal(float32(2)))
+		expect(dpi.Scale(200)).To(equal(float32(2)))
+	})
+
+	o.Spec("it limits the scale", func(expect expect.Expectation) {
+		expect(dpi.Scale(72)).To(equal(float32(1)))
+		expect(dpi.Scale(1000)).To(equal(float32(dpi.MaxScale)))
+	})
+}
+
+func TestAt(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	monitors := []dpi.Monitor{
+		{Width: 1920, Height: 1080, WidthMM: 527},
+		{X: 1920, Width: 3840, Height: 2160, WidthMM: 610},
+	}
+
+	o.Spec("it finds the monitor that a point is on", func(expect expect.Expectation) {
+		m, ok := dpi.At(monitors, 1919, 500)
+		expect(ok).To(beTrue())
+		expect(m).To(equal(monitors[0]))
+		m, ok = dpi.At(monitors, 1920, 1500)
+		expect(ok).To(beTrue())
+		expect(m).To(equal(monitors[1]))
+	})
+
+	o.Spec("it finds nothing off screen", func(expect expect.Expectation) {
+		_, ok := dpi.At(monitors, 100, 1500)
+		expect(ok).To(beFalse())
+		_, ok = dpi.At(monitors, -1, 0)
+		expect(ok).To(beFalse())
+	})
+}
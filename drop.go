@@ -0,0 +1,87 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/command/project"
+	"github.com/nelsam/vidar/commander"
+	"github.com/nelsam/vidar/setting"
+)
+
+// watchDrops opens the files that are dropped onto w from other
+// programs.  gxui doesn't expose w's GLFW window, so it is looked up as
+// the current context on the UI goroutine right after w is created, and
+// drops are ignored if that context turns out to belong to some other
+// window.
+func watchDrops(driver gxui.Driver, w *window, cmdr *commander.Commander, opener *focus.Location) {
+	driver.Call(func() {
+		glw := glfw.GetCurrentContext()
+		if glw == nil || !sameBounds(glw, w.Viewport()) {
+			log.Printf("Could not find the GLFW window; dropped files will be ignored")
+			return
+		}
+		glw.SetDropCallback(func(_ *glfw.Window, paths []string) {
+			driver.Call(func() {
+				openDropped(cmdr, opener, paths)
+			})
+		})
+	})
+}
+
+// sameBounds reports whether glw has the same position and size as v.
+func sameBounds(glw *glfw.Window, v gxui.Viewport) bool {
+	x, y := glw.GetPos()
+	width, height := glw.GetFramebufferSize()
+	pos, size := v.Position(), v.SizePixels()
+	return pos.X == x && pos.Y == y && size.W == width && size.H == height
+}
+
+// openDropped opens each file in paths in the focused split.  If any
+// directories were dropped, the first one is opened as a project, or
+// the user is prompted to add it as a project if it isn't one yet.
+func openDropped(cmdr *commander.Commander, opener *focus.Location, paths []string) {
+	var dir string
+	for _, path := range paths {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			log.Printf("Failed to get path: %s", err)
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			log.Printf("Could not open dropped file %s: %s", path, err)
+			continue
+		}
+		if info.IsDir() {
+			if dir == "" {
+				dir = path
+			}
+			continue
+		}
+		cmdr.Execute(opener.For(focus.Path(path)))
+	}
+	if dir == "" {
+		return
+	}
+	for _, p := range setting.Projects() {
+		if filepath.Clean(p.Path) != dir {
+			continue
+		}
+		if changer, ok := cmdr.Bindable("project-change").(*project.Open); ok {
+			cmdr.Execute(changer.For(project.Project(p)))
+		}
+		return
+	}
+	if adder, ok := cmdr.Bindable("add-project").(*project.Add); ok {
+		cmdr.Run(adder.ForPath(dir))
+	}
+}
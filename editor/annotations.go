@@ -0,0 +1,75 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+// annotationTips holds the overlay that annotation details are shown
+// in for each window.  It's only accessed on the UI goroutine.
+var annotationTips = make(map[gxui.Window]gxui.BubbleOverlay)
+
+// Annotations returns the annotation for each line in e, or nil if e
+// isn't showing annotations.
+func (e *CodeEditor) Annotations() []input.Annotation {
+	return e.annotations
+}
+
+// SetAnnotations replaces e's annotations, which are indexed by line.
+// Passing nil hides the annotation column.  Since annotations are
+// part of each line's gutter, the lines are recreated.
+func (e *CodeEditor) SetAnnotations(notes []input.Annotation) {
+	e.annotations = notes
+	e.annotationWidth = 0
+	for _, n := range notes {
+		if w := utf8.RuneCountInString(n.Text); w > e.annotationWidth {
+			e.annotationWidth = w
+		}
+	}
+	e.DataChanged(true)
+}
+
+// annotationLabel returns the label for line's annotation.  Lines
+// without annotations get a blank label, so that the text stays
+// lined up.
+func (e *CodeEditor) annotationLabel(line int) gxui.Label {
+	var note input.Annotation
+	if line < len(e.annotations) {
+		note = e.annotations[line]
+	}
+	label := e.theme.CreateLabel()
+	text := note.Text + strings.Repeat(" ", e.annotationWidth-utf8.RuneCountInString(note.Text))
+	label.SetText(text)
+	label.SetColor(gxui.Color(e.syntaxTheme.Constructs[theme.Comment].Foreground))
+	label.SetMargin(math.Spacing{R: 6})
+	if note.Detail == "" {
+		return label
+	}
+	label.OnMouseEnter(func(ev gxui.MouseEvent) {
+		tip, ok := annotationTips[ev.Window]
+		if !ok {
+			tip = e.theme.CreateBubbleOverlay()
+			ev.Window.AddChild(tip)
+			annotationTips[ev.Window] = tip
+		}
+		detail := e.theme.CreateLabel()
+		detail.SetMultiline(true)
+		detail.SetText(note.Detail)
+		tip.Show(detail, ev.WindowPoint)
+	})
+	label.OnMouseExit(func(ev gxui.MouseEvent) {
+		if tip, ok := annotationTips[ev.Window]; ok {
+			tip.Hide()
+		}
+	})
+	return label
+}
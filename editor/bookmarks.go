@@ -0,0 +1,117 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"sort"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/setting"
+)
+
+var bookmarkColor = gxui.Color{R: 0.3, G: 0.5, B: 0.9, A: 0.4}
+
+// Bookmarks returns the bookmarked lines in e, in order.
+func (e *CodeEditor) Bookmarks() []int {
+	lines := make([]int, 0, len(e.bookmarks))
+	for _, b := range e.bookmarks {
+		lines = append(lines, e.lineAt(b))
+	}
+	return lines
+}
+
+// SetBookmarks replaces the bookmarked lines in e.  Lines that are
+// past the end of e's text are ignored.
+func (e *CodeEditor) SetBookmarks(lines ...int) {
+	ctrl := e.Controller()
+	e.bookmarks = e.bookmarks[:0]
+	for _, l := range lines {
+		if l < 0 || l >= ctrl.LineCount() {
+			continue
+		}
+		e.bookmarks = append(e.bookmarks, ctrl.LineStart(l))
+	}
+	e.bookmarks = dedupe(e.bookmarks)
+	e.Redraw()
+}
+
+// ToggleBookmark bookmarks line if it isn't bookmarked and removes
+// its bookmark if it is.  It returns whether or not line is
+// bookmarked afterward.
+func (e *CodeEditor) ToggleBookmark(line int) bool {
+	lines := e.Bookmarks()
+	for i, l := range lines {
+		if l == line {
+			e.SetBookmarks(append(lines[:i], lines[i+1:]...)...)
+			return false
+		}
+	}
+	e.SetBookmarks(append(lines, line)...)
+	return true
+}
+
+// ShiftBookmarks moves e's bookmarks to follow edits, so that they
+// stay on the lines they were added to.
+func (e *CodeEditor) ShiftBookmarks(edits []input.Edit) {
+	if len(e.bookmarks) == 0 {
+		return
+	}
+	ctrl := e.Controller()
+	for i, b := range e.bookmarks {
+		e.bookmarks[i] = ctrl.LineStart(e.lineAt(shiftOffset(b, edits)))
+	}
+	e.bookmarks = dedupe(e.bookmarks)
+}
+
+// restoreBookmarks loads the bookmarks that were saved for e's file.
+func (e *CodeEditor) restoreBookmarks() {
+	e.SetBookmarks(setting.FileBookmarks(setting.ProjectFor(e.filepath).Name, e.filepath)...)
+}
+
+// lineAt returns the line that offset is on, clamping offset to the
+// end of e's text.
+func (e *CodeEditor) lineAt(offset int) int {
+	ctrl := e.Controller()
+	if max := len(ctrl.TextRunes()); offset > max {
+		offset = max
+	}
+	return ctrl.LineIndex(offset)
+}
+
+// bookmarked returns whether or not line is bookmarked in e.
+func (e *CodeEditor) bookmarked(line int) bool {
+	start := e.Controller().LineStart(line)
+	i := sort.SearchInts(e.bookmarks, start)
+	return i < len(e.bookmarks) && e.bookmarks[i] == start
+}
+
+// shiftOffset returns the position of offset after edits have been
+// applied.  Like the edits passed to input-handler hooks, each edit
+// is relative to the text after the edits before it.  If an edit
+// replaces the text at offset, the result is the start of that edit.
+func shiftOffset(offset int, edits []input.Edit) int {
+	for _, e := range edits {
+		if e.At > offset {
+			return offset
+		}
+		if e.At+len(e.Old) > offset {
+			offset = e.At
+			continue
+		}
+		offset += len(e.New) - len(e.Old)
+	}
+	return offset
+}
+
+// paintBookmark highlights the line number in g.
+func (g *gutter) paintBookmark(c gxui.Canvas) {
+	right := g.Size().W
+	if children := g.Children(); len(children) > 0 {
+		right = children[0].Bounds().Max.X
+	}
+	c.DrawRect(math.CreateRect(0, 0, right, g.Size().H), gxui.CreateBrush(bookmarkColor))
+}
@@ -0,0 +1,238 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/mixins/parts"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/breadcrumb"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/setting"
+)
+
+const (
+	// crumbDelay is how long an editor has to go without its text or
+	// carets changing before its breadcrumbs are updated, so that
+	// the file isn't parsed on every key press.
+	crumbDelay = 150 * time.Millisecond
+
+	// maxCrumbItems is the number of symbols that a breadcrumb's
+	// menu lists at once.  Files with more symbols than that only
+	// list the ones around the breadcrumb.
+	maxCrumbItems = 20
+
+	crumbSeparator = "›"
+)
+
+// breadcrumbs is the bar above an editor that shows the package,
+// type, and function that the editor's last caret is in.  Clicking a
+// breadcrumb shows a menu of the symbols next to it, which jumps to
+// the symbol that is chosen.
+type breadcrumbs struct {
+	mixins.LinearLayout
+
+	driver gxui.Driver
+	theme  *basic.Theme
+	cmdr   Commander
+
+	// editor is the editor that the breadcrumbs are for, and subs
+	// are the subscriptions to its changes.  They, along with
+	// timer and crumbs, are only accessed on the UI goroutine.
+	editor *CodeEditor
+	subs   []gxui.EventSubscription
+	timer  *time.Timer
+	crumbs []breadcrumb.Crumb
+}
+
+func newBreadcrumbs(driver gxui.Driver, theme *basic.Theme, cmdr Commander) *breadcrumbs {
+	b := &breadcrumbs{
+		driver: driver,
+		theme:  theme,
+		cmdr:   cmdr,
+	}
+	b.Init(b, theme)
+	b.SetDirection(gxui.LeftToRight)
+	return b
+}
+
+// empty returns whether or not there are no breadcrumbs to show.
+func (b *breadcrumbs) empty() bool {
+	return len(b.crumbs) == 0
+}
+
+// follows returns whether or not b is following ed.
+func (b *breadcrumbs) follows(ed input.Editor) bool {
+	ce, _ := ed.(*CodeEditor)
+	return ce == b.editor
+}
+
+// setEditor makes b follow the last caret of ed, which may be nil.
+func (b *breadcrumbs) setEditor(ed input.Editor) {
+	if b.follows(ed) {
+		return
+	}
+	ce, _ := ed.(*CodeEditor)
+	for _, s := range b.subs {
+		s.Unlisten()
+	}
+	b.subs = nil
+	b.editor = ce
+	if ce != nil {
+		b.subs = append(b.subs,
+			ce.OnTextChanged(func([]gxui.TextBoxEdit) { b.queue() }),
+			ce.Controller().OnSelectionChanged(b.queue),
+		)
+	}
+	b.update()
+}
+
+// queue updates b once its editor has stopped changing for
+// crumbDelay.
+func (b *breadcrumbs) queue() {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.timer = time.AfterFunc(crumbDelay, func() {
+		b.driver.Call(b.update)
+	})
+}
+
+// update parses b's editor and shows the breadcrumbs for its last
+// caret.  The buttons are only replaced when the breadcrumbs have
+// changed, since most caret movements stay inside of the same
+// function.
+func (b *breadcrumbs) update() {
+	var crumbs []breadcrumb.Crumb
+	if b.editor != nil && setting.Breadcrumbs() && filepath.Ext(b.editor.Filepath()) == ".go" {
+		crumbs = breadcrumb.Path(b.editor.Text(), b.editor.Controller().LastCaret())
+	}
+	same := len(crumbs) == len(b.crumbs)
+	for i := 0; same && i < len(crumbs); i++ {
+		same = crumbs[i].Kind == b.crumbs[i].Kind && crumbs[i].Name == b.crumbs[i].Name
+	}
+	b.crumbs = crumbs
+	if same {
+		return
+	}
+	b.RemoveAll()
+	for i, c := range crumbs {
+		if i > 0 {
+			sep := b.theme.CreateLabel()
+			sep.SetText(crumbSeparator)
+			sep.SetMargin(math.Spacing{L: 2, R: 2})
+			b.AddChild(sep)
+		}
+		idx := i
+		btn := b.theme.CreateButton()
+		btn.SetText(c.Name)
+		btn.SetMargin(math.Spacing{L: 2, R: 2})
+		btn.OnClick(func(ev gxui.MouseEvent) {
+			if idx < len(b.crumbs) {
+				crumbMenuFor(ev.Window, b.theme).show(b, b.crumbs[idx], ev.WindowPoint)
+			}
+		})
+		b.AddChild(btn)
+	}
+}
+
+// jump moves the caret in b's editor to s.
+func (b *breadcrumbs) jump(s breadcrumb.Symbol) {
+	if b.editor == nil {
+		return
+	}
+	opener := b.cmdr.Bindable("focus-location").(Opener)
+	b.cmdr.Execute(opener.For(focus.Path(b.editor.Filepath()), focus.Offset(s.Offset)))
+}
+
+// crumbMenus holds the breadcrumb menu for each window, which is
+// shared by every breadcrumb bar in the window.  It's only accessed on
+// the UI goroutine.
+var crumbMenus = make(map[gxui.Window]*crumbMenu)
+
+// crumbMenu is the menu of symbols that is shown when a breadcrumb is
+// clicked.
+type crumbMenu struct {
+	parts.Focusable
+	mixins.LinearLayout
+
+	theme   *basic.Theme
+	overlay gxui.BubbleOverlay
+}
+
+// crumbMenuFor returns the breadcrumb menu for window, creating it if
+// it doesn't exist yet.
+func crumbMenuFor(window gxui.Window, theme *basic.Theme) *crumbMenu {
+	if m, ok := crumbMenus[window]; ok {
+		return m
+	}
+	m := &crumbMenu{
+		theme:   theme,
+		overlay: theme.CreateBubbleOverlay(),
+	}
+	m.Focusable.Init(m)
+	m.LinearLayout.Init(m, theme)
+	m.SetDirection(gxui.TopToBottom)
+	m.SetBackgroundBrush(theme.ButtonDefaultStyle.Brush)
+	m.SetBorderPen(theme.ButtonDefaultStyle.Pen)
+	m.OnLostFocus(m.hide)
+	window.AddChild(m.overlay)
+	crumbMenus[window] = m
+	return m
+}
+
+// show displays the symbols next to c at point, which is in window
+// coordinates.  Choosing one jumps to it in b's editor.
+func (m *crumbMenu) show(b *breadcrumbs, c breadcrumb.Crumb, point math.Point) {
+	m.RemoveAll()
+	for _, s := range nearby(c.Siblings, c.Symbol) {
+		sym := s
+		btn := m.theme.CreateButton()
+		btn.SetText(sym.Name)
+		btn.SetMargin(math.Spacing{L: 2, R: 2})
+		btn.OnClick(func(gxui.MouseEvent) {
+			m.hide()
+			b.jump(sym)
+		})
+		m.AddChild(btn)
+	}
+	if len(m.Children()) == 0 {
+		return
+	}
+	m.overlay.Show(m, point)
+	gxui.SetFocus(m)
+}
+
+func (m *crumbMenu) hide() {
+	m.overlay.Hide()
+}
+
+// nearby returns at most maxCrumbItems of syms, centered on current
+// if it's one of them.
+func nearby(syms []breadcrumb.Symbol, current breadcrumb.Symbol) []breadcrumb.Symbol {
+	if len(syms) <= maxCrumbItems {
+		return syms
+	}
+	start := 0
+	for i, s := range syms {
+		if s == current {
+			start = i - maxCrumbItems/2
+			break
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	if max := len(syms) - maxCrumbItems; start > max {
+		start = max
+	}
+	return syms[start : start+maxCrumbItems]
+}
@@ -0,0 +1,121 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"time"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/vidar/setting"
+	"github.com/nelsam/vidar/theme"
+)
+
+const (
+	// barWidth is the width of bar carets.
+	barWidth = 2
+
+	// underlineHeight is the height of underline carets.
+	underlineHeight = 2
+)
+
+// resetBlink shows e's carets and restarts their blinking, so that
+// they're never hidden right after they move.  Carets only blink
+// while e has focus.
+func (e *CodeEditor) resetBlink() {
+	e.blinkGen++
+	if e.caretHidden {
+		e.caretHidden = false
+		e.Redraw()
+	}
+	if e.blinkTimer != nil {
+		e.blinkTimer.Stop()
+	}
+	rate := setting.CaretBlink()
+	if rate <= 0 || !e.HasFocus() {
+		return
+	}
+	gen := e.blinkGen
+	e.blinkTimer = time.AfterFunc(rate, func() {
+		e.driver.Call(func() { e.blink(gen, rate) })
+	})
+}
+
+// blink toggles whether or not e's carets are hidden, unless they've
+// been reset since gen.
+func (e *CodeEditor) blink(gen int, rate time.Duration) {
+	if gen != e.blinkGen {
+		return
+	}
+	if !e.HasFocus() {
+		e.resetBlink()
+		return
+	}
+	e.caretHidden = !e.caretHidden
+	e.Redraw()
+	e.blinkTimer = time.AfterFunc(rate, func() {
+		e.driver.Call(func() { e.blink(gen, rate) })
+	})
+}
+
+// hasCaret returns whether or not one of e's carets is on line.
+func (e *CodeEditor) hasCaret(line int) bool {
+	ctrl := e.Controller()
+	for _, c := range ctrl.Carets() {
+		if ctrl.LineIndex(c) == line {
+			return true
+		}
+	}
+	return false
+}
+
+// paintCurrentLine highlights the background of l if one of its
+// editor's carets is on it.
+func (l *diagnosticLine) paintCurrentLine(c gxui.Canvas) {
+	bg := l.editor.syntaxTheme.UI.CurrentLine
+	if bg == (theme.Color{}) || !setting.HighlightCurrentLine() || !l.editor.hasCaret(l.index) {
+		return
+	}
+	c.DrawRect(l.Size().Rect(), gxui.CreateBrush(gxui.Color(bg)))
+}
+
+// PaintCaret draws a caret in the configured style.  Carets in an
+// editor without focus are drawn as an outline in a dimmer color, so
+// that it's clear which editor keys will go to.
+func (l *diagnosticLine) PaintCaret(c gxui.Canvas, top, bottom math.Point) {
+	e := l.editor
+	focused := e.HasFocus()
+	if focused && e.caretHidden {
+		return
+	}
+	ui := e.syntaxTheme.UI
+	color := ui.Caret
+	if !focused {
+		color = ui.InactiveCaret
+	}
+	if color == (theme.Color{}) {
+		l.CodeEditorLine.PaintCaret(c, top, bottom)
+		return
+	}
+
+	width := e.font.GlyphMaxSize().W
+	r := math.CreateRect(top.X-barWidth/2, top.Y, top.X+barWidth/2, bottom.Y)
+	switch setting.Caret() {
+	case setting.CaretBlock:
+		r = math.CreateRect(top.X, top.Y, top.X+width, bottom.Y)
+		if focused {
+			// Text is painted before carets, so block carets are
+			// translucent to keep the text under them readable.
+			color.A /= 2
+		}
+	case setting.CaretUnderline:
+		r = math.CreateRect(top.X, bottom.Y-underlineHeight, top.X+width, bottom.Y)
+	}
+	if !focused {
+		c.DrawRoundedRect(r, 0, 0, 0, 0, gxui.CreatePen(1, gxui.Color(color)), gxui.TransparentBrush)
+		return
+	}
+	c.DrawRect(r, gxui.CreateBrush(gxui.Color(color)))
+}
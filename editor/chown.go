@@ -0,0 +1,32 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package editor
+
+import (
+	"os"
+	"syscall"
+)
+
+// chown gives f the owner and group from info, as far as the user is
+// allowed to.  Only root can give files to other users, and other
+// users can only use groups that they're in, so permission errors are
+// ignored; the file is still saved, owned by the user instead.
+func chown(f *os.File, info os.FileInfo) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	err := f.Chown(int(stat.Uid), int(stat.Gid))
+	if err == nil || !os.IsPermission(err) {
+		return err
+	}
+	if err := f.Chown(-1, int(stat.Gid)); err != nil && !os.IsPermission(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+const (
+	// gutterIconSize is the diameter of the icons drawn in the line
+	// number gutter for lines with diagnostics.
+	gutterIconSize = 8
+
+	// squiggleHeight is the height of the wavy underline drawn
+	// under diagnostics.
+	squiggleHeight = 3
+
+	// unnecessaryDim is the opacity of the background that is drawn
+	// over unnecessary code to dim it.
+	unnecessaryDim = 0.5
+)
+
+// diagnosticTips holds the overlay that diagnostic tooltips are shown
+// in for each window.  It's only accessed on the UI goroutine.
+var diagnosticTips = make(map[gxui.Window]gxui.BubbleOverlay)
+
+func (e *CodeEditor) Diagnostics() []input.Diagnostic {
+	return e.diagnostics
+}
+
+func (e *CodeEditor) SetDiagnostics(source string, diags []input.Diagnostic) {
+	e.setDiagnostics(source, diags)
+	for _, v := range e.Views() {
+		v.setDiagnostics(source, diags)
+	}
+}
+
+func (e *CodeEditor) setDiagnostics(source string, diags []input.Diagnostic) {
+	if e.diagSources == nil {
+		e.diagSources = make(map[string][]input.Diagnostic)
+	}
+	if len(diags) == 0 {
+		delete(e.diagSources, source)
+	} else {
+		diags = append([]input.Diagnostic(nil), diags...)
+		for i, d := range diags {
+			if d.Source == "" {
+				diags[i].Source = source
+			}
+		}
+		e.diagSources[source] = diags
+	}
+	e.collectDiagnostics()
+	e.Redraw()
+	e.DataChanged(false)
+}
+
+// ShiftDiagnostics moves e's diagnostics to follow edits, so that
+// they stay with the text they refer to until their source
+// publishes new diagnostics.
+func (e *CodeEditor) ShiftDiagnostics(edits []input.Edit) {
+	if len(e.diagnostics) == 0 {
+		return
+	}
+	for _, diags := range e.diagSources {
+		for i, d := range diags {
+			diags[i].Range = shiftSpan(d.Range, edits)
+		}
+	}
+	e.collectDiagnostics()
+}
+
+func (e *CodeEditor) collectDiagnostics() {
+	var all []input.Diagnostic
+	for _, diags := range e.diagSources {
+		all = append(all, diags...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Range.Start < all[j].Range.Start
+	})
+	e.diagnostics = all
+}
+
+func shiftSpan(s input.Span, edits []input.Edit) input.Span {
+	for _, e := range edits {
+		if e.At > s.End {
+			return s
+		}
+		delta := len(e.New) - len(e.Old)
+		s.End += delta
+		if s.End < e.At {
+			s.End = e.At
+		}
+		if e.At > s.Start {
+			continue
+		}
+		s.Start += delta
+		if s.Start < e.At {
+			s.Start = e.At
+		}
+	}
+	return s
+}
+
+// lineDiagnostics returns the diagnostics which overlap the runes
+// from start to end.
+func (e *CodeEditor) lineDiagnostics(start, end int) []input.Diagnostic {
+	var diags []input.Diagnostic
+	for _, d := range e.diagnostics {
+		if d.Range.Start > end {
+			break
+		}
+		if d.Range.End < start {
+			continue
+		}
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+// diagnosticsAt returns the diagnostics which include the rune at
+// offset.  Empty ranges include the rune that they start at, since
+// their squiggle is drawn under it.
+func (e *CodeEditor) diagnosticsAt(offset int) []input.Diagnostic {
+	var diags []input.Diagnostic
+	for _, d := range e.lineDiagnostics(offset, offset) {
+		if offset < d.Range.End || (offset == d.Range.Start && d.Range.Start == d.Range.End) {
+			diags = append(diags, d)
+		}
+	}
+	return diags
+}
+
+// hoverDiagnostics shows a tooltip with the diagnostics under the
+// mouse, or hides it if there aren't any.
+func (e *CodeEditor) hoverDiagnostics(ev gxui.MouseEvent) {
+	var diags []input.Diagnostic
+	if !e.minimap || ev.Point.X < e.minimapRect().Min.X {
+		if offset, ok := e.RuneIndexAt(ev.Point); ok {
+			diags = e.diagnosticsAt(offset)
+		}
+	}
+	msgs := make([]string, 0, len(diags))
+	worst := input.SeverityHint
+	for _, d := range diags {
+		msgs = append(msgs, d.String())
+		if d.Severity < worst {
+			worst = d.Severity
+		}
+	}
+	text := strings.Join(msgs, "\n")
+	if text == e.diagTip {
+		return
+	}
+	e.diagTip = text
+	tip, ok := diagnosticTips[ev.Window]
+	if !ok {
+		if text == "" {
+			return
+		}
+		tip = e.theme.CreateBubbleOverlay()
+		ev.Window.AddChild(tip)
+		diagnosticTips[ev.Window] = tip
+	}
+	if text == "" {
+		tip.Hide()
+		return
+	}
+	label := e.theme.CreateLabel()
+	label.SetMultiline(true)
+	label.SetText(text)
+	label.SetColor(e.diagnosticColor(worst))
+	tip.Show(label, ev.WindowPoint)
+}
+
+func (e *CodeEditor) MouseExit(ev gxui.MouseEvent) {
+	e.CodeEditor.MouseExit(ev)
+	if e.diagTip == "" {
+		return
+	}
+	e.diagTip = ""
+	if tip, ok := diagnosticTips[ev.Window]; ok {
+		tip.Hide()
+	}
+}
+
+func (e *CodeEditor) diagnosticColor(s input.Severity) gxui.Color {
+	colors := e.syntaxTheme.Diagnostics
+	c := colors.Error
+	switch s {
+	case input.SeverityWarning:
+		c = colors.Warning
+	case input.SeverityInfo:
+		c = colors.Info
+	case input.SeverityHint:
+		c = colors.Hint
+	}
+	return gxui.Color(c)
+}
+
+// diagnosticLine is a line in a CodeEditor which draws a wavy
+// underline below any diagnostics on it.  It also highlights the line
+// that the caret is on and draws the caret itself; see caret.go.
+type diagnosticLine struct {
+	mixins.CodeEditorLine
+
+	editor *CodeEditor
+	index  int
+}
+
+func (l *diagnosticLine) Paint(c gxui.Canvas) {
+	l.paintCurrentLine(c)
+	l.CodeEditorLine.Paint(c)
+	if !l.editor.HasFocus() {
+		// CodeEditorLine only paints carets while the editor has
+		// focus.
+		l.PaintCarets(c)
+	}
+
+	ctrl := l.editor.Controller()
+	if l.index >= ctrl.LineCount() {
+		return
+	}
+	start, end := ctrl.LineStart(l.index), ctrl.LineEnd(l.index)
+	bottom := l.Size().H - 1
+	for _, d := range l.editor.lineDiagnostics(start, end) {
+		from, to := d.Range.Start, d.Range.End
+		if from < start {
+			from = start
+		}
+		if to > end {
+			to = end
+		}
+		left, right := l.PositionAt(from).X, l.PositionAt(to).X
+		if d.Unnecessary {
+			l.paintDim(c, left, right)
+			continue
+		}
+		if right-left < squiggleHeight*2 {
+			// Make sure empty ranges (e.g. a missing token at the
+			// end of a line) are still visible.
+			right = left + squiggleHeight*2
+		}
+		var wave gxui.Polygon
+		for x, up := left, false; x <= right; x, up = x+squiggleHeight, !up {
+			y := bottom
+			if up {
+				y -= squiggleHeight
+			}
+			wave = append(wave, gxui.PolygonVertex{Position: math.Point{X: x, Y: y}})
+		}
+		c.DrawLines(wave, gxui.CreatePen(1, l.editor.diagnosticColor(d.Severity)))
+	}
+}
+
+// paintDim dims the text between left and right by drawing the
+// editor's background over it.
+func (l *diagnosticLine) paintDim(c gxui.Canvas, left, right int) {
+	bg := l.editor.theme.WindowBackground
+	if ui := l.editor.syntaxTheme.UI.Background; ui != (theme.Color{}) {
+		bg = gxui.Color(ui)
+	}
+	bg.A = unnecessaryDim
+	c.DrawRect(math.CreateRect(left, 0, right, l.Size().H), gxui.CreateBrush(bg))
+}
+
+// gutter is the layout containing a line number and its line.  It
+// highlights the line number of bookmarked lines and draws an icon to
+// the left of the line number if there are any diagnostics on the
+// line.
+type gutter struct {
+	mixins.LinearLayout
+
+	editor *CodeEditor
+	index  int
+}
+
+func (g *gutter) Paint(c gxui.Canvas) {
+	ctrl := g.editor.Controller()
+	if g.index < ctrl.LineCount() && g.editor.bookmarked(g.index) {
+		g.paintBookmark(c)
+	}
+	g.LinearLayout.Paint(c)
+
+	if g.index >= ctrl.LineCount() {
+		return
+	}
+	diags := g.editor.lineDiagnostics(ctrl.LineStart(g.index), ctrl.LineEnd(g.index))
+	if len(diags) == 0 {
+		return
+	}
+	worst := diags[0].Severity
+	for _, d := range diags[1:] {
+		if d.Severity < worst {
+			worst = d.Severity
+		}
+	}
+	top := (g.Size().H - gutterIconSize) / 2
+	r := math.CreateRect(1, top, 1+gutterIconSize, top+gutterIconSize)
+	radius := float32(gutterIconSize) / 2
+	c.DrawRoundedRect(r, radius, radius, radius, radius, gxui.TransparentPen, gxui.CreateBrush(g.editor.diagnosticColor(worst)))
+}
@@ -0,0 +1,224 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/vidar/command/focus"
+)
+
+const (
+	// dragThreshold is the distance that the mouse has to move
+	// between pressing and releasing a tab for it to count as a
+	// drag instead of a click.
+	dragThreshold = 8
+
+	// edgeDropSize is the width of the area along the edges of a
+	// project's editors where dropping a tab will move it into a
+	// new split.
+	edgeDropSize = 32
+)
+
+// tabDropper is a type that can handle tabs being dropped within
+// it.  Points are relative to the tabDropper.
+type tabDropper interface {
+	dropTab(from, to math.Point)
+}
+
+// watchDrag waits for the mouse button that started dragging one of
+// e's tabs to be released, then lets the outermost split that e is
+// in handle the drop.
+//
+// gxui moves the tab's panel between PanelHolders while it is being
+// dragged, but it knows nothing about splits, so it leaves empty
+// TabbedEditors behind and has no way to create new splits.
+func (e *TabbedEditor) watchDrag(down gxui.MouseEvent) {
+	var sub gxui.EventSubscription
+	sub = down.Window.OnMouseUp(func(up gxui.MouseEvent) {
+		sub.Unlisten()
+
+		// Let gxui finish its own handling of the drop before we
+		// start rearranging things.
+		e.driver.Call(func() {
+			root := e.dropRoot()
+			if root == nil {
+				return
+			}
+			offset := windowOffset(root)
+			root.(tabDropper).dropTab(down.WindowPoint.Sub(offset), up.WindowPoint.Sub(offset))
+		})
+	})
+}
+
+// dropRoot returns the outermost tabDropper that e is a child of.
+func (e *TabbedEditor) dropRoot() gxui.Control {
+	var root gxui.Control
+	for parent := e.Parent(); parent != nil; {
+		c, ok := parent.(gxui.Control)
+		if !ok {
+			break
+		}
+		if _, ok := c.(tabDropper); ok {
+			root = c
+		}
+		parent = c.Parent()
+	}
+	return root
+}
+
+// windowOffset returns the position of c within its window.
+func windowOffset(c gxui.Control) math.Point {
+	var offset math.Point
+	for {
+		parent := c.Parent()
+		if parent == nil {
+			return offset
+		}
+		child := parent.Children().Find(c)
+		if child == nil {
+			return offset
+		}
+		offset = offset.Add(child.Offset)
+		pc, ok := parent.(gxui.Control)
+		if !ok {
+			return offset
+		}
+		c = pc
+	}
+}
+
+func (e *SplitEditor) dropTab(from, to math.Point) {
+	var target *TabbedEditor
+	drag := to.Sub(from)
+	if drag.X*drag.X+drag.Y*drag.Y >= dragThreshold*dragThreshold {
+		target = e.tabsAt(to)
+	}
+	if target != nil && target.CurrentEditor() != nil {
+		if d, ok := e.edgeAt(to); ok && e.Editors() > 1 {
+			target = e.splitToEdge(d, target)
+		}
+	}
+	e.prune()
+	if target == nil || target.CurrentEditor() == nil {
+		return
+	}
+	opener := e.cmdr.Bindable("focus-location").(Opener)
+	e.cmdr.Execute(opener.For(focus.Path(target.CurrentEditor().Filepath())))
+}
+
+// tabsAt returns the *TabbedEditor at p, or nil if there isn't one.
+func (e *SplitEditor) tabsAt(p math.Point) *TabbedEditor {
+	for _, child := range e.Children() {
+		childPoint := p.Sub(child.Offset)
+		if !child.Control.ContainsPoint(childPoint) {
+			continue
+		}
+		switch src := child.Control.(type) {
+		case *SplitEditor:
+			return src.tabsAt(childPoint)
+		case *TabbedEditor:
+			return src
+		}
+	}
+	return nil
+}
+
+// edgeAt returns the edge of e that p is close enough to for a drop
+// to create a new split.  The top edge is left out, since that is
+// where tabs are dropped to reorder them.
+func (e *SplitEditor) edgeAt(p math.Point) (Direction, bool) {
+	size := e.Size()
+	switch {
+	case p.X < edgeDropSize:
+		return Left, true
+	case p.X > size.W-edgeDropSize:
+		return Right, true
+	case p.Y > size.H-edgeDropSize:
+		return Down, true
+	}
+	return 0, false
+}
+
+// splitToEdge moves the current editor in from into a new
+// *TabbedEditor along edge d of e, returning the new *TabbedEditor.
+func (e *SplitEditor) splitToEdge(d Direction, from *TabbedEditor) *TabbedEditor {
+	name, editor := from.CloseCurrentEditor()
+	newSplit := NewTabbedEditor(e.driver, e.cmdr, e.theme, e.syntaxTheme, e.font)
+	orientation := gxui.Horizontal
+	if d == Down {
+		orientation = gxui.Vertical
+	}
+	if e.Orientation() != orientation {
+		// The new split has to span all of our existing editors,
+		// so they need to move into a split of their own.
+		inner := NewSplitEditor(e.driver, e.cmdr, e.window, e.theme, e.syntaxTheme, e.font)
+		inner.SetOrientation(e.Orientation())
+		current := e.current
+		for _, child := range e.editors() {
+			e.RemoveChild(child)
+			inner.AddChild(child)
+		}
+		inner.current = current
+		e.SetOrientation(orientation)
+		e.AddChild(inner)
+	}
+	if d == Left {
+		e.AddChildAt(0, newSplit)
+	} else {
+		e.AddChild(newSplit)
+	}
+	e.current = newSplit
+	newSplit.Add(name, editor)
+	return newSplit
+}
+
+// prune removes empty editors from e and any splits inside of it,
+// replacing splits that are left with a single editor by that
+// editor.  The last editor in e is never removed.
+func (e *SplitEditor) prune() {
+	for _, child := range e.editors() {
+		split, ok := child.(*SplitEditor)
+		if !ok {
+			continue
+		}
+		split.prune()
+		remaining := split.editors()
+		if len(remaining) != 1 {
+			continue
+		}
+		only := remaining[0]
+		split.RemoveChild(only)
+		index := e.ChildIndex(split)
+		e.RemoveChildAt(index)
+		e.AddChildAt(index, only)
+		if e.current == split {
+			e.current = only
+		}
+	}
+	for _, child := range e.editors() {
+		if child.Editors() > 0 || len(e.editors()) == 1 {
+			continue
+		}
+		e.RemoveChild(child)
+		if e.current == child {
+			e.current = nil
+		}
+	}
+	if editors := e.editors(); e.current == nil && len(editors) > 0 {
+		e.current = editors[0]
+	}
+}
+
+// editors returns the MultiEditors in e, skipping splitter bars.
+func (e *SplitEditor) editors() []MultiEditor {
+	var editors []MultiEditor
+	for _, child := range e.Children() {
+		if me, ok := child.Control.(MultiEditor); ok {
+			editors = append(editors, me)
+		}
+	}
+	return editors
+}
@@ -5,7 +5,9 @@
 package editor
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
@@ -20,7 +22,9 @@ import (
 	"github.com/nelsam/gxui/mixins"
 	"github.com/nelsam/gxui/themes/basic"
 	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/eol"
 	"github.com/nelsam/vidar/fsw"
+	"github.com/nelsam/vidar/setting"
 	"github.com/nelsam/vidar/theme"
 )
 
@@ -30,50 +34,172 @@ type CodeEditor struct {
 	theme       *basic.Theme
 	syntaxTheme theme.Theme
 	driver      gxui.Driver
+	font        gxui.Font
 
 	lock         sync.RWMutex
 	lastModified time.Time
-	hasChanges   bool
 	filepath     string
 
+	// revision is bumped by each edit to e's text, and savedRevision
+	// is the revision that was last loaded from or saved to disk.
+	// They're only accessed on the UI goroutine.
+	revision      int
+	savedRevision int
+	onDirty       func(dirty bool)
+
 	watcher fsw.Watcher
 
 	selections      []gxui.TextSelection
 	scrollPositions math.Point
 	layers          []input.SyntaxLayer
+	layerColors     []gxui.Color
+	lineNumbers     bool
+
+	minimap     bool
+	minimapDrag bool
+
+	diagSources map[string][]input.Diagnostic
+	diagnostics []input.Diagnostic
+
+	// occurrences holds the other occurrences of the identifier
+	// under the caret, and occurrenceTimer delays finding them until
+	// the caret stops moving.  They're only accessed on the UI
+	// goroutine.
+	occurrences     []input.Span
+	occurrenceTimer *time.Timer
+
+	// caretHidden is set during the off half of a caret blink, and
+	// blinkGen is bumped each time the blink restarts, so that
+	// blinkTimer can tell when it's out of date.  They're only
+	// accessed on the UI goroutine.
+	caretHidden bool
+	blinkGen    int
+	blinkTimer  *time.Timer
+
+	// diagTip is the text of the diagnostic tooltip that the mouse
+	// is hovering over, if any.  It's only accessed on the UI
+	// goroutine.
+	diagTip string
+
+	// bookmarks holds the start of each bookmarked line, in order.
+	bookmarks []int
 
-	renamed  bool
 	onRename func(newPath string)
+
+	// conflict is set when the file changes on disk while e has
+	// unsaved changes.  It's only accessed on the UI goroutine.
+	conflict   bool
+	onConflict func(conflicted bool)
+
+	// pinned is set when e's tab is pinned, which keeps it at the
+	// start of its tabs and stops close-current-tab from closing
+	// it.  It's only accessed on the UI goroutine.
+	pinned bool
+
+	// lineEnding is the line ending of e's file, which e's text is
+	// converted to when it's written.  mixedEndings is set when the
+	// file had lines with other endings when it was loaded, and
+	// binary is set when it couldn't be loaded as text.  They're
+	// only accessed on the UI goroutine.
+	lineEnding   eol.Ending
+	mixedEndings bool
+	binary       bool
+
+	// readOnly is set when e's text should not be edited.  It's
+	// only accessed on the UI goroutine.
+	readOnly   bool
+	onReadOnly func(readOnly bool)
+
+	// annotations are displayed in the gutter, indexed by line.
+	// annotationWidth is the length of the longest one.
+	annotations     []input.Annotation
+	annotationWidth int
+
+	// folds are the regions of e's text that can be folded, and
+	// folded holds the start of the first line of each folded
+	// region, in order.  rows holds the line displayed in each row,
+	// and is nil when no lines are hidden.
+	folds  []input.Fold
+	folded []int
+	rows   []int
+
+	// buffer is shared with the other views of e's file, if there
+	// are any.  lastScroll is e's scroll offset when it was last
+	// painted, which is used to scroll e's views along with it.
+	buffer     *buffer
+	lastScroll int
+
+	// scrolling is set while a smooth scroll is animating towards
+	// scrollTarget.  They're only accessed on the UI goroutine.
+	scrolling    bool
+	scrollTarget int
 }
 
 func (e *CodeEditor) Init(driver gxui.Driver, theme *basic.Theme, syntaxTheme theme.Theme, font gxui.Font, file, headerText string) {
+	e.init(driver, theme, syntaxTheme, font, file)
+	e.open(headerText)
+	// The text is set on the UI goroutine, so bookmarks have to wait
+	// until after it has been set.
+	e.driver.Call(e.restoreBookmarks)
+}
+
+// init sets up e to edit file, without loading its text.
+func (e *CodeEditor) init(driver gxui.Driver, theme *basic.Theme, syntaxTheme theme.Theme, font gxui.Font, file string) {
 	e.theme = theme
 	e.syntaxTheme = syntaxTheme
 	e.driver = driver
+	e.font = font
+	e.lineNumbers = setting.LineNumbers()
 
 	e.CodeEditor.Init(e, driver, theme, font)
+	e.SetAdapter(&foldAdapter{ListAdapter: e.Adapter(), editor: e})
 	e.CodeEditor.SetScrollBarEnabled(true)
-	e.CodeEditor.SetScrollRound(true)
+	e.CodeEditor.SetScrollRound(!setting.SmoothScroll())
 	e.SetDesiredWidth(math.MaxSize.W)
 	e.watcherSetup()
 
 	// TODO: move to hooks on the input.Handler
 	e.OnTextChanged(func(changes []gxui.TextBoxEdit) {
-		e.hasChanges = true
+		e.Edited()
+		e.queueOccurrences()
+		e.resetBlink()
 	})
+	e.Controller().OnSelectionChanged(e.revealCarets)
+	e.Controller().OnSelectionChanged(e.queueOccurrences)
+	e.Controller().OnSelectionChanged(e.resetBlink)
+	e.OnGainedFocus(e.resetBlink)
+	e.OnLostFocus(e.resetBlink)
 	e.filepath = file
-	e.open(headerText)
+	e.readOnly = readOnlyPath(file)
 
-	e.SetTextColor(theme.TextBoxDefaultStyle.FontColor)
+	e.applyUIColors()
 	e.SetMargin(math.Spacing{L: 3, T: 3, R: 3, B: 3})
 	e.SetPadding(math.Spacing{L: 3, T: 3, R: 3, B: 3})
 	e.SetBorderPen(gxui.TransparentPen)
+	e.SetMinimap(setting.Minimap())
 }
 
 func (e *CodeEditor) DataChanged(recreate bool) {
 	e.List.DataChanged(recreate)
 }
 
+// LineNumbers returns whether or not e is displaying its line
+// number gutter.
+func (e *CodeEditor) LineNumbers() bool {
+	return e.lineNumbers
+}
+
+// SetLineNumbers shows or hides e's line number gutter.  Since the
+// gutter is part of each line's control, the lines have to be
+// recreated when it changes.
+func (e *CodeEditor) SetLineNumbers(show bool) {
+	if e.lineNumbers == show {
+		return
+	}
+	e.lineNumbers = show
+	e.DataChanged(true)
+}
+
 func (e *CodeEditor) Carets() []int {
 	return e.Controller().Carets()
 }
@@ -94,6 +220,104 @@ func (e *CodeEditor) OnRename(callback func(newPath string)) {
 	e.onRename = callback
 }
 
+// Rename points e at newPath after its file has been moved there.
+// Unsaved changes are kept, and will be saved to newPath.
+func (e *CodeEditor) Rename(newPath string) {
+	if newPath == e.filepath {
+		return
+	}
+	old := e.watcher
+	e.watcherSetup()
+	if old != nil {
+		// Closing the old watcher ends the goroutine that is
+		// watching the old path.
+		if err := old.Close(); err != nil {
+			log.Printf("Error closing watcher for %s: %s", e.filepath, err)
+		}
+	}
+	e.filepath = newPath
+	if e.onRename != nil {
+		e.onRename(newPath)
+	}
+	go e.watch()
+}
+
+// OnConflict sets a callback that is called on the UI goroutine when
+// e's file changes on disk while e has unsaved changes, and again
+// when the conflict is resolved.
+func (e *CodeEditor) OnConflict(callback func(conflicted bool)) {
+	e.onConflict = callback
+}
+
+// HasConflict returns whether or not e's file has changed on disk
+// since e was last edited without saving.
+func (e *CodeEditor) HasConflict() bool {
+	return e.conflict
+}
+
+// Pinned returns whether or not e's tab is pinned.
+func (e *CodeEditor) Pinned() bool {
+	return e.pinned
+}
+
+// ReadOnly returns whether or not edits to e are blocked.
+func (e *CodeEditor) ReadOnly() bool {
+	return e.readOnly
+}
+
+// SetReadOnly blocks or allows edits to e.
+func (e *CodeEditor) SetReadOnly(readOnly bool) {
+	if e.readOnly == readOnly {
+		return
+	}
+	e.readOnly = readOnly
+	if e.onReadOnly != nil {
+		e.onReadOnly(readOnly)
+	}
+}
+
+// OnReadOnly sets a callback to be called when e is made read-only
+// or editable.  Only one callback is kept.
+func (e *CodeEditor) OnReadOnly(callback func(readOnly bool)) {
+	e.onReadOnly = callback
+}
+
+func (e *CodeEditor) setConflict(conflict bool) {
+	if e.conflict == conflict {
+		return
+	}
+	e.conflict = conflict
+	if e.onConflict != nil {
+		e.onConflict(conflict)
+	}
+}
+
+// Reload discards any unsaved changes in e and loads its file from
+// disk.
+func (e *CodeEditor) Reload() {
+	e.markSaved()
+	e.load("")
+}
+
+// ReloadIfChanged loads e's file from disk if it was changed by
+// something other than e, e.g. a code generator, in case e's watcher
+// missed it.  Unsaved changes in e are kept and marked as a conflict.
+func (e *CodeEditor) ReloadIfChanged() {
+	e.changedOnDisk()
+}
+
+// KeepChanges resolves a conflict by keeping e's text, allowing it to
+// overwrite the file on disk when it's saved.
+func (e *CodeEditor) KeepChanges() {
+	finfo, err := os.Stat(e.filepath)
+	if err != nil {
+		log.Printf("Error stating file %s: %s", e.filepath, err)
+		return
+	}
+	e.setLastModified(finfo.ModTime())
+	e.setConflict(false)
+}
+
 func (e *CodeEditor) open(headerText string) {
 	go e.watch()
 	e.load(headerText)
@@ -107,83 +331,145 @@ func (e *CodeEditor) watcherSetup() {
 	}
 }
 
-func (e *CodeEditor) startWatch() error {
-	err := e.watcher.Add(e.filepath)
-	if os.IsNotExist(err) {
-		err = e.waitForFileCreate()
-		if err != nil {
-			return err
-		}
-		return e.startWatch()
+// startWatch waits for the file at path to exist, so that its
+// directory can be watched.
+func (e *CodeEditor) startWatch(w fsw.Watcher, path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return e.waitForFileCreate(w, path)
 	}
 	return nil
 }
 
+// watch watches e's file for changes.  The watcher is read once, up
+// front, since Rename replaces it to stop watching the old path.
+// Everything that happens to the file, including being replaced by an
+// atomic save, is handled here rather than by starting another watch,
+// so that only one goroutine ever adds or removes e's watches.
 func (e *CodeEditor) watch() {
-	if e.watcher == nil {
+	w := e.watcher
+	if w == nil {
 		return
 	}
-	err := e.startWatch()
+	path := e.filepath
+	err := e.startWatch(w, path)
 	if err != nil {
-		log.Printf("Error trying to watch %s for changes: %s", e.filepath, err)
+		log.Printf("Error trying to watch %s for changes: %s", path, err)
 		return
 	}
-	defer e.watcher.Remove(e.filepath)
-	fileDir := filepath.Dir(e.filepath)
-	err = e.watcher.Add(fileDir)
+	f, err := fsw.WatchFile(w, path)
 	if err != nil {
-		log.Printf("Error trying to watch %s for changes: %s", fileDir, err)
+		log.Printf("Error trying to watch %s for changes: %s", path, err)
 		return
 	}
-	defer e.watcher.Remove(fileDir)
+	defer f.Close()
 	for {
-		ev, err := e.watcher.Next()
-		if err != nil {
-			log.Printf("Error from watcher: %s", err)
+		ev, err := f.Next()
+		if err == io.EOF {
 			return
 		}
-		if ev.Path != e.filepath {
-			if !e.renamed || ev.Op != fsw.Create {
-				continue
-			}
-			e.filepath = ev.Path
-			e.onRename(e.filepath)
-			e.open("")
+		if err != nil {
+			log.Printf("Error from watcher: %s", err)
 			return
 		}
 		switch ev.Op {
 		case fsw.Write:
-			e.load("")
+			e.changedOnDisk()
 		case fsw.Rename:
-			e.renamed = true
+			e.filepath = ev.Path
+			if e.onRename != nil {
+				e.onRename(e.filepath)
+			}
+			e.load("")
 		case fsw.Remove:
-			e.open("")
-			return
+			e.load("")
 		}
 	}
 }
 
-func (e *CodeEditor) waitForFileCreate() error {
-	dir := filepath.Dir(e.filepath)
-	if err := os.MkdirAll(dir, 0750|os.ModeDir); err != nil {
+// waitForFileCreate waits for path to be created.  Its parent
+// directories may not exist yet either (they're created when the file
+// is first saved), so the closest parent that does exist is watched
+// until the next one down is created.
+func (e *CodeEditor) waitForFileCreate(w fsw.Watcher, path string) error {
+	dir := existingParent(path)
+	if err := w.Add(dir); err != nil {
 		return err
 	}
-	if err := e.watcher.Add(dir); err != nil {
-		return err
+	defer w.Remove(dir)
+	if _, err := os.Stat(path); err == nil {
+		return nil
 	}
-	defer e.watcher.Remove(dir)
 
 	for {
-		ev, err := e.watcher.Next()
+		ev, err := w.Next()
 		if err != nil {
 			return err
 		}
-		if ev.Path == e.filepath && ev.Op&fsw.Create == fsw.Create {
+		if ev.Op&fsw.Create != fsw.Create {
+			continue
+		}
+		if ev.Path == path {
 			return nil
 		}
+		if strings.HasPrefix(path, ev.Path+string(filepath.Separator)) {
+			return e.waitForFileCreate(w, path)
+		}
+	}
+}
+
+// existingParent returns the closest parent directory of path that
+// exists.
+func existingParent(path string) string {
+	dir := filepath.Dir(path)
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
 	}
 }
 
+// changedOnDisk loads e's file after it has been written to.  If e
+// has unsaved changes, the text is left alone and e is marked as
+// conflicting with the file instead.
+func (e *CodeEditor) changedOnDisk() {
+	finfo, err := os.Stat(e.filepath)
+	if err != nil {
+		log.Printf("Error stating file %s: %s", e.filepath, err)
+		return
+	}
+	if !finfo.ModTime().After(e.LastKnownMTime()) {
+		// We wrote this change ourselves.
+		return
+	}
+	b, err := ioutil.ReadFile(e.filepath)
+	if err != nil {
+		log.Printf("Error reading file %s: %s", e.filepath, err)
+		return
+	}
+	newText := string(b)
+	if !eol.Binary(b) {
+		newText = eol.Normalize(newText)
+	}
+	e.driver.Call(func() {
+		if e.Text() == newText {
+			e.setLastModified(finfo.ModTime())
+			e.markSaved()
+			e.setConflict(false)
+			return
+		}
+		if e.HasChanges() {
+			e.setConflict(true)
+			return
+		}
+		e.load("")
+	})
+}
+
 func (e *CodeEditor) load(headerText string) {
 	f, err := os.Open(e.filepath)
 	if os.IsNotExist(err) {
@@ -209,14 +495,27 @@ func (e *CodeEditor) load(headerText string) {
 		return
 	}
 	newText := string(b)
+	binary := eol.Binary(b)
+	ending, mixed := eol.Detect(newText)
+	if !binary {
+		newText = eol.Normalize(newText)
+	}
 	if !strings.HasPrefix(newText, headerText) {
 		log.Printf("%s: header text does not match requested header text", e.filepath)
 	}
 	e.driver.Call(func() {
+		defer e.setConflict(false)
+		e.lineEnding, e.mixedEndings = ending, mixed
+		e.binary = binary
+		if binary {
+			// Saving would replace any invalid UTF-8 in the file.
+			e.SetReadOnly(true)
+		}
 		if e.Text() == newText {
 			return
 		}
 		e.SetText(newText)
+		e.markSaved()
 		if len(e.selections) > 0 {
 			e.restorePositions()
 		}
@@ -224,7 +523,34 @@ func (e *CodeEditor) load(headerText string) {
 }
 
 func (e *CodeEditor) HasChanges() bool {
-	return e.hasChanges
+	return e.revision != e.savedRevision
+}
+
+// Edited records an edit to e's text.  Edits that are applied without
+// a text changed event, e.g. by the input handler, have to call it
+// themselves.
+func (e *CodeEditor) Edited() {
+	dirty := e.HasChanges()
+	e.revision++
+	if !dirty && e.onDirty != nil {
+		e.onDirty(true)
+	}
+}
+
+// markSaved records that e's text matches its file.
+func (e *CodeEditor) markSaved() {
+	dirty := e.HasChanges()
+	e.savedRevision = e.revision
+	if dirty && e.onDirty != nil {
+		e.onDirty(false)
+	}
+}
+
+// OnDirty sets a callback that is called when e gains unsaved
+// changes, and again when they're saved or discarded.  Only one
+// callback is kept.
+func (e *CodeEditor) OnDirty(callback func(dirty bool)) {
+	e.onDirty = callback
 }
 
 func (e *CodeEditor) LastKnownMTime() time.Time {
@@ -244,8 +570,37 @@ func (e *CodeEditor) Filepath() string {
 }
 
 func (e *CodeEditor) FlushedChanges() {
-	e.hasChanges = false
+	e.markSaved()
+	e.mixedEndings = false
+	for _, v := range e.Views() {
+		// Views share e's text, so it was saved for them too.
+		v.markSaved()
+		v.mixedEndings = false
+	}
 	e.setLastModified(time.Now())
+	e.setConflict(false)
+}
+
+// save writes e's text to its file, unless the file has changed on
+// disk since e last loaded or saved it.
+func (e *CodeEditor) save() error {
+	if mtime := e.LastKnownMTime(); !mtime.IsZero() {
+		finfo, err := os.Stat(e.filepath)
+		if err != nil {
+			return err
+		}
+		if finfo.ModTime().After(mtime) {
+			return errors.New("changed on disk; refusing to overwrite")
+		}
+	}
+	if e.binary {
+		return errors.New("binary file; refusing to overwrite")
+	}
+	if err := WriteFile(e.filepath, eol.Convert(e.Text(), e.lineEnding)); err != nil {
+		return err
+	}
+	e.FlushedChanges()
+	return nil
 }
 
 func (e *CodeEditor) Elements() []interface{} {
@@ -254,27 +609,105 @@ func (e *CodeEditor) Elements() []interface{} {
 	}
 }
 
+// SetSyntaxLayers replaces the syntax layers of e and its views.
 func (e *CodeEditor) SetSyntaxLayers(layers []input.SyntaxLayer) {
+	e.setSyntaxLayers(layers)
+	for _, v := range e.Views() {
+		v.setSyntaxLayers(layers)
+	}
+}
+
+func (e *CodeEditor) setSyntaxLayers(layers []input.SyntaxLayer) {
 	defer e.syntaxTheme.Rainbow.Reset()
 	sort.Slice(layers, func(i, j int) bool {
 		return layers[i].Construct < layers[j].Construct
 	})
 	e.layers = layers
-	gLayers := make(gxui.CodeSyntaxLayers, 0, len(layers))
+	e.layerColors = make([]gxui.Color, 0, len(layers))
+	gLayers := make(gxui.CodeSyntaxLayers, 0, len(layers)+1)
+	// Occurrences are kept separately from the layers that
+	// highlighters set, but they're still added in the order of their
+	// construct.
+	occurrences := len(e.occurrences) > 0
 	for _, l := range layers {
+		if occurrences && l.Construct > theme.Occurrence {
+			gLayers = append(gLayers, e.occurrenceLayer())
+			occurrences = false
+		}
 		highlight, found := e.syntaxTheme.Constructs[l.Construct]
+		if !found {
+			highlight, found = e.syntaxTheme.Rainbow.Depth(l.Construct)
+		}
 		if !found {
 			highlight = e.syntaxTheme.Rainbow.Next()
 		}
-		gLayer := gxui.CreateCodeSyntaxLayer()
+		e.layerColors = append(e.layerColors, gxui.Color(highlight.Foreground))
+		gLayers = append(gLayers, codeLayer(highlight, l.Spans))
+	}
+	if occurrences {
+		gLayers = append(gLayers, e.occurrenceLayer())
+	}
+	e.CodeEditor.SetSyntaxLayers(gLayers)
+}
+
+// codeLayer returns a gxui syntax layer which highlights spans.
+// Colors that are left out of highlight don't override the colors of
+// other layers.
+func codeLayer(highlight theme.Highlight, spans []input.Span) *gxui.CodeSyntaxLayer {
+	gLayer := gxui.CreateCodeSyntaxLayer()
+	if highlight.Foreground != (theme.Color{}) {
 		gLayer.SetColor(gxui.Color(highlight.Foreground))
+	}
+	if highlight.Background != (theme.Color{}) {
 		gLayer.SetBackgroundColor(gxui.Color(highlight.Background))
-		for _, s := range l.Spans {
-			gLayer.Add(s.Start, s.End-s.Start)
-		}
-		gLayers = append(gLayers, gLayer)
 	}
-	e.CodeEditor.SetSyntaxLayers(gLayers)
+	for _, s := range spans {
+		gLayer.Add(s.Start, s.End-s.Start)
+	}
+	return gLayer
+}
+
+// SetSyntaxTheme changes the theme that e uses to highlight its text
+// and recolors the current syntax layers.
+func (e *CodeEditor) SetSyntaxTheme(t theme.Theme) {
+	e.syntaxTheme = t
+	e.applyUIColors()
+	e.setSyntaxLayers(e.layers)
+}
+
+// SetFont changes the font that e uses for its text.
+func (e *CodeEditor) SetFont(font gxui.Font) {
+	e.font = font
+	e.CodeEditor.SetFont(font)
+}
+
+// MouseScroll scrolls e (smoothly, if that's enabled), unless the
+// control key is held.  Control scrolling is left for e's parent,
+// which uses it to change the font size.
+func (e *CodeEditor) MouseScroll(ev gxui.MouseEvent) bool {
+	if ev.Modifier.Control() {
+		return false
+	}
+	return e.smoothly(func() bool {
+		return e.CodeEditor.MouseScroll(ev)
+	})
+}
+
+// applyUIColors sets e's text color and background from the UI
+// colors in e's syntax theme, falling back to the gxui theme's
+// colors.
+func (e *CodeEditor) applyUIColors() {
+	ui := e.syntaxTheme.UI
+	text := e.theme.TextBoxDefaultStyle.FontColor
+	if ui.Foreground != (theme.Color{}) {
+		text = gxui.Color(ui.Foreground)
+	}
+	e.SetTextColor(text)
+	bg := gxui.TransparentBrush
+	if ui.Background != (theme.Color{}) {
+		bg = gxui.CreateBrush(gxui.Color(ui.Background))
+	}
+	e.SetBackgroundBrush(bg)
 }
 
 func (e *CodeEditor) SyntaxLayers() []input.SyntaxLayer {
@@ -283,7 +716,11 @@ func (e *CodeEditor) SyntaxLayers() []input.SyntaxLayer {
 
 func (e *CodeEditor) Paint(c gxui.Canvas) {
 	e.CodeEditor.Paint(c)
+	e.syncScroll()
 
+	if e.minimap {
+		e.paintMinimap(c)
+	}
 	if e.HasFocus() {
 		r := e.Size().Rect()
 		c.DrawRoundedRect(r, 3, 3, 3, 3, e.theme.FocusedStyle.Pen, e.theme.FocusedStyle.Brush)
@@ -296,6 +733,9 @@ func (e *CodeEditor) storePositions() {
 		X: e.HorizOffset(),
 		Y: e.ScrollOffset(),
 	}
+	if e.scrolling {
+		e.scrollPositions.Y = e.scrollTarget
+	}
 }
 
 func (e *CodeEditor) restorePositions() {
@@ -314,8 +754,13 @@ func (e *CodeEditor) KeyPress(event gxui.KeyboardEvent) bool {
 	switch event.Key {
 	case gxui.KeyPageUp, gxui.KeyPageDown:
 		// These are all bindings that the TextBox handles fine.
-		return e.TextBox.KeyPress(event)
+		return e.smoothly(func() bool {
+			return e.TextBox.KeyPress(event)
+		})
 	case gxui.KeyTab:
+		if e.readOnly {
+			return true
+		}
 		// TODO: Gain knowledge about scope, so we know how much to indent.
 		switch {
 		case event.Modifier.Shift():
@@ -337,16 +782,29 @@ func (e *CodeEditor) KeyStroke(event gxui.KeyStrokeEvent) (consume bool) {
 }
 
 func (e *CodeEditor) CreateLine(theme gxui.Theme, index int) (mixins.TextBoxLine, gxui.Control) {
-	lineNumber := theme.CreateLabel()
-	lineNumber.SetText(fmt.Sprintf("%4d", index+1))
-	lineNumber.SetMargin(math.Spacing{L: 0, T: 0, R: 3, B: 0})
-
-	line := &mixins.CodeEditorLine{}
+	line := &diagnosticLine{editor: e, index: index}
 	line.Init(line, theme, &e.CodeEditor, index)
 
-	layout := theme.CreateLinearLayout()
+	if !e.lineNumbers && e.annotations == nil && e.folds == nil {
+		return line, line
+	}
+
+	layout := &gutter{editor: e, index: index}
+	layout.Init(layout, theme)
 	layout.SetDirection(gxui.LeftToRight)
-	layout.AddChild(lineNumber)
+	layout.SetPadding(math.Spacing{L: gutterIconSize + 2})
+	if e.annotations != nil {
+		layout.AddChild(e.annotationLabel(index))
+	}
+	if e.lineNumbers {
+		lineNumber := theme.CreateLabel()
+		lineNumber.SetText(fmt.Sprintf("%4d", index+1))
+		lineNumber.SetMargin(math.Spacing{L: 0, T: 0, R: 3, B: 0})
+		layout.AddChild(lineNumber)
+	}
+	if e.folds != nil {
+		layout.AddChild(e.foldMarker(index))
+	}
 	layout.AddChild(line)
 
 	return line, layout
@@ -0,0 +1,311 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"sort"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+// foldAdapter wraps the adapter for e's lines, skipping the lines
+// that are hidden by folded regions.  Items are still line indexes,
+// so only the mapping between rows and lines changes.  Empty rows
+// are added after the last line when e may scroll past its end.
+type foldAdapter struct {
+	gxui.ListAdapter
+
+	editor *CodeEditor
+}
+
+// lineRows returns the number of rows that display lines.
+func (a *foldAdapter) lineRows() int {
+	if a.editor.rows == nil {
+		return a.ListAdapter.Count()
+	}
+	return len(a.editor.rows)
+}
+
+func (a *foldAdapter) Count() int {
+	return a.lineRows() + a.editor.pastEndRows()
+}
+
+func (a *foldAdapter) ItemAt(index int) gxui.AdapterItem {
+	if index >= a.lineRows() {
+		return pastEndItem(index)
+	}
+	return a.ListAdapter.ItemAt(a.editor.rowLine(index))
+}
+
+func (a *foldAdapter) ItemIndex(item gxui.AdapterItem) int {
+	if row, ok := item.(pastEndItem); ok {
+		return int(row)
+	}
+	return a.editor.lineRow(a.ListAdapter.ItemIndex(item))
+}
+
+func (a *foldAdapter) Create(theme gxui.Theme, index int) gxui.Control {
+	if index >= a.lineRows() {
+		return createPastEnd(theme)
+	}
+	return a.ListAdapter.Create(theme, a.editor.rowLine(index))
+}
+
+// Folds returns the regions of e's text that can be folded.
+func (e *CodeEditor) Folds() []input.Fold {
+	return e.folds
+}
+
+// SetFolds replaces the regions of e's text (and its views' text)
+// that can be folded.  Folded lines stay folded as long as a region
+// still starts on them.
+func (e *CodeEditor) SetFolds(folds []input.Fold) {
+	e.setFolds(folds)
+	for _, v := range e.Views() {
+		v.setFolds(folds)
+	}
+}
+
+func (e *CodeEditor) setFolds(folds []input.Fold) {
+	// Each view shifts its own regions as the text is edited.
+	e.folds = append([]input.Fold(nil), folds...)
+	e.refreshFolds()
+}
+
+// FoldedLines returns the first line of each folded region in e, in
+// order.
+func (e *CodeEditor) FoldedLines() []int {
+	lines := make([]int, 0, len(e.folded))
+	for _, f := range e.folded {
+		lines = append(lines, e.lineAt(f))
+	}
+	return lines
+}
+
+// SetFoldedLines folds the regions that start on lines, unfolding
+// everything else.  Lines may be folded before any regions start on
+// them, e.g. while a file's regions are still being found.
+func (e *CodeEditor) SetFoldedLines(lines ...int) {
+	ctrl := e.Controller()
+	e.folded = e.folded[:0]
+	for _, l := range lines {
+		if l < 0 || l >= ctrl.LineCount() {
+			continue
+		}
+		e.folded = append(e.folded, ctrl.LineStart(l))
+	}
+	e.folded = dedupe(e.folded)
+	e.refreshFolds()
+}
+
+// Fold folds the innermost region around line that isn't folded yet.
+// It returns false if there is no such region.
+func (e *CodeEditor) Fold(line int) bool {
+	inner, found := -1, false
+	for _, f := range e.folds {
+		start, end := e.lineAt(f.Start), e.lineAt(f.End)
+		if line < start || line > end || e.isFolded(start) {
+			continue
+		}
+		if start > inner {
+			inner, found = start, true
+		}
+	}
+	if !found {
+		return false
+	}
+	e.SetFoldedLines(append(e.FoldedLines(), inner)...)
+	return true
+}
+
+// Unfold unfolds the innermost folded region around line.  It returns
+// false if line isn't in a folded region.
+func (e *CodeEditor) Unfold(line int) bool {
+	lines := e.FoldedLines()
+	inner := -1
+	for i, l := range lines {
+		f, ok := e.foldAt(l)
+		if !ok || line < l || line > e.lineAt(f.End) {
+			continue
+		}
+		inner = i
+	}
+	if inner == -1 {
+		return false
+	}
+	e.SetFoldedLines(append(lines[:inner], lines[inner+1:]...)...)
+	return true
+}
+
+// FoldAll folds every region of kind in e.
+func (e *CodeEditor) FoldAll(kind input.FoldKind) {
+	lines := e.FoldedLines()
+	for _, f := range e.folds {
+		if f.Kind == kind {
+			lines = append(lines, e.lineAt(f.Start))
+		}
+	}
+	e.SetFoldedLines(lines...)
+}
+
+// ToggleFold folds the region that starts on line, or unfolds it if
+// it's already folded.
+func (e *CodeEditor) ToggleFold(line int) {
+	if e.isFolded(line) {
+		e.Unfold(line)
+		return
+	}
+	lines := e.FoldedLines()
+	e.SetFoldedLines(append(lines, line)...)
+}
+
+// ShiftFolds moves e's regions and folded lines to follow edits.
+func (e *CodeEditor) ShiftFolds(edits []input.Edit) {
+	if len(e.folds) == 0 && len(e.folded) == 0 {
+		return
+	}
+	for i, f := range e.folds {
+		e.folds[i].Start = shiftOffset(f.Start, edits)
+		e.folds[i].End = shiftOffset(f.End, edits)
+	}
+	ctrl := e.Controller()
+	for i, f := range e.folded {
+		e.folded[i] = ctrl.LineStart(e.lineAt(shiftOffset(f, edits)))
+	}
+	e.folded = dedupe(e.folded)
+	e.refreshFolds()
+}
+
+// foldAt returns the outermost region that starts on line.
+func (e *CodeEditor) foldAt(line int) (input.Fold, bool) {
+	var (
+		outer input.Fold
+		found bool
+	)
+	for _, f := range e.folds {
+		if e.lineAt(f.Start) != line {
+			continue
+		}
+		if !found || f.End > outer.End {
+			outer, found = f, true
+		}
+	}
+	return outer, found
+}
+
+// isFolded returns whether or not line is the first line of a folded
+// region in e.
+func (e *CodeEditor) isFolded(line int) bool {
+	start := e.Controller().LineStart(line)
+	i := sort.SearchInts(e.folded, start)
+	return i < len(e.folded) && e.folded[i] == start
+}
+
+// refreshFolds finds the lines that are hidden by folded regions and
+// recreates e's lines.
+func (e *CodeEditor) refreshFolds() {
+	e.rows = nil
+	count := e.Controller().LineCount()
+	var hidden []bool
+	for _, start := range e.folded {
+		line := e.lineAt(start)
+		f, ok := e.foldAt(line)
+		if !ok {
+			continue
+		}
+		if hidden == nil {
+			hidden = make([]bool, count)
+		}
+		for l := line + 1; l < e.lineAt(f.End) && l < count; l++ {
+			hidden[l] = true
+		}
+	}
+	if hidden != nil {
+		e.rows = make([]int, 0, count)
+		for l, h := range hidden {
+			if !h {
+				e.rows = append(e.rows, l)
+			}
+		}
+	}
+	e.DataChanged(true)
+}
+
+// revealCarets unfolds any regions that hide one of e's carets.
+func (e *CodeEditor) revealCarets() {
+	if e.rows == nil {
+		return
+	}
+	for _, c := range e.Carets() {
+		line := e.lineAt(c)
+		if e.rows[e.lineRow(line)] != line {
+			e.Unfold(line)
+		}
+	}
+}
+
+// rowLine returns the line that is displayed in row.
+func (e *CodeEditor) rowLine(row int) int {
+	if e.rows == nil {
+		return row
+	}
+	if row >= len(e.rows) {
+		return e.rows[len(e.rows)-1] + row - len(e.rows) + 1
+	}
+	return e.rows[row]
+}
+
+// lineRow returns the row that line is displayed in.  Hidden lines
+// return the row of the folded region that hides them.
+func (e *CodeEditor) lineRow(line int) int {
+	if e.rows == nil {
+		return line
+	}
+	i := sort.SearchInts(e.rows, line)
+	if i < len(e.rows) && e.rows[i] == line {
+		return i
+	}
+	if i == 0 {
+		return 0
+	}
+	return i - 1
+}
+
+// foldMarker returns the label that marks line as the start of a
+// region that can be folded.  Clicking it folds or unfolds the
+// region.
+func (e *CodeEditor) foldMarker(line int) gxui.Label {
+	label := e.theme.CreateLabel()
+	label.SetColor(gxui.Color(e.syntaxTheme.Constructs[theme.Comment].Foreground))
+	label.SetMargin(math.Spacing{R: 3})
+	if _, ok := e.foldAt(line); !ok {
+		label.SetText(" ")
+		return label
+	}
+	label.SetText("-")
+	if e.isFolded(line) {
+		label.SetText("+")
+	}
+	label.OnClick(func(gxui.MouseEvent) {
+		e.ToggleFold(line)
+	})
+	return label
+}
+
+// dedupe sorts offsets and removes duplicates from it.
+func dedupe(offsets []int) []int {
+	sort.Ints(offsets)
+	unique := offsets[:0]
+	for i, o := range offsets {
+		if i > 0 && o == offsets[i-1] {
+			continue
+		}
+		unique = append(unique, o)
+	}
+	return unique
+}
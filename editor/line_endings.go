@@ -0,0 +1,44 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import "github.com/nelsam/vidar/eol"
+
+// LineEnding returns the line ending that e's text is written with.
+// Files that don't exist yet use LF.
+func (e *CodeEditor) LineEnding() eol.Ending {
+	if e.lineEnding == "" {
+		return eol.LF
+	}
+	return e.lineEnding
+}
+
+// MixedLineEndings returns whether or not e's file had lines that
+// don't end with LineEnding when it was loaded.  They're converted to
+// LineEnding the next time e is saved.
+func (e *CodeEditor) MixedLineEndings() bool {
+	return e.mixedEndings
+}
+
+// SetLineEnding changes the line ending that e's text is written
+// with.  Since the file will change when it's saved, e is marked as
+// having unsaved changes.
+func (e *CodeEditor) SetLineEnding(ending eol.Ending) {
+	if ending == e.LineEnding() && !e.mixedEndings {
+		return
+	}
+	e.lineEnding, e.mixedEndings = ending, false
+	e.Edited()
+	for _, v := range e.Views() {
+		v.lineEnding, v.mixedEndings = ending, false
+		v.Edited()
+	}
+}
+
+// Binary returns whether or not e's file couldn't be loaded as text.
+// Binary files are read-only, and are never saved.
+func (e *CodeEditor) Binary() bool {
+	return e.binary
+}
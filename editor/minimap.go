@@ -0,0 +1,212 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+)
+
+const (
+	// minimapWidth is the width of the minimap, including its
+	// margin.
+	minimapWidth = 100
+
+	// minimapMargin is the space between the minimap and the rest
+	// of the editor.
+	minimapMargin = 4
+
+	// minimapLineHeight and minimapCharWidth are the size of each
+	// line and character in the minimap.
+	minimapLineHeight = 2
+	minimapCharWidth  = 1
+
+	minimapTabWidth = 4
+)
+
+var (
+	minimapBG       = gxui.Color{R: 0, G: 0, B: 0, A: 0.2}
+	minimapViewport = gxui.Color{R: 1, G: 1, B: 1, A: 0.1}
+)
+
+// Minimap returns whether or not e is displaying a minimap.
+func (e *CodeEditor) Minimap() bool {
+	return e.minimap
+}
+
+// SetMinimap shows or hides e's minimap.
+func (e *CodeEditor) SetMinimap(show bool) {
+	if e.minimap == show {
+		return
+	}
+	e.minimap = show
+	e.minimapDrag = false
+	padding := e.Padding()
+	padding.R = 3
+	if show {
+		padding.R += minimapWidth
+	}
+	e.SetPadding(padding)
+	e.Redraw()
+}
+
+// minimapRect returns the area that e's minimap is drawn in.
+func (e *CodeEditor) minimapRect() math.Rect {
+	size := e.Size()
+	return math.CreateRect(size.W-minimapWidth+minimapMargin, 0, size.W, size.H)
+}
+
+func (e *CodeEditor) lineHeight() int {
+	h := e.font.GlyphMaxSize().H
+	if h <= 0 {
+		return 1
+	}
+	return h
+}
+
+// minimapStart returns the first line displayed in the minimap.  When
+// the file has more lines than can fit, the minimap scrolls along
+// with the editor so that the viewport stays in view.
+func (e *CodeEditor) minimapStart(r math.Rect) int {
+	lines := e.Controller().LineCount()
+	fits := r.H() / minimapLineHeight
+	if lines <= fits {
+		return 0
+	}
+	visible := e.Size().H / e.lineHeight()
+	scrollable := lines - visible
+	if scrollable <= 0 {
+		return 0
+	}
+	first := e.ScrollOffset() / e.lineHeight()
+	return (lines - fits) * first / scrollable
+}
+
+func (e *CodeEditor) paintMinimap(c gxui.Canvas) {
+	r := e.minimapRect()
+	c.DrawRect(r, gxui.CreateBrush(minimapBG))
+
+	ctrl := e.Controller()
+	lines := ctrl.LineCount()
+	start := e.minimapStart(r)
+	end := start + r.H()/minimapLineHeight
+	if end > lines {
+		end = lines
+	}
+	if start >= end {
+		return
+	}
+	runes := e.Runes()
+	lo, hi := ctrl.LineStart(start), ctrl.LineEnd(end-1)
+	colors := e.minimapColors(lo, hi)
+	maxCols := r.W() / minimapCharWidth
+	for i := start; i < end; i++ {
+		y := r.Min.Y + (i-start)*minimapLineHeight
+		col, runStart := 0, -1
+		var runColor gxui.Color
+		flush := func() {
+			if runStart < 0 {
+				return
+			}
+			left := r.Min.X + runStart*minimapCharWidth
+			right := r.Min.X + col*minimapCharWidth
+			c.DrawRect(math.CreateRect(left, y, right, y+minimapLineHeight-1), gxui.CreateBrush(runColor))
+			runStart = -1
+		}
+		for pos := ctrl.LineStart(i); pos < ctrl.LineEnd(i) && col < maxCols; pos++ {
+			ch := runes[pos]
+			color := colors[pos-lo]
+			if ch == ' ' || ch == '\t' || color != runColor {
+				flush()
+			}
+			switch ch {
+			case ' ':
+				col++
+				continue
+			case '\t':
+				col += minimapTabWidth
+				continue
+			}
+			if runStart < 0 {
+				runStart, runColor = col, color
+			}
+			col++
+		}
+		flush()
+	}
+
+	first := e.ScrollOffset() / e.lineHeight()
+	visible := e.Size().H / e.lineHeight()
+	top := r.Min.Y + (first-start)*minimapLineHeight
+	bottom := top + visible*minimapLineHeight
+	c.DrawRect(math.CreateRect(r.Min.X, top, r.Max.X, bottom), gxui.CreateBrush(minimapViewport))
+}
+
+// minimapColors returns the color of each rune from lo to hi, based
+// on e's syntax layers.
+func (e *CodeEditor) minimapColors(lo, hi int) []gxui.Color {
+	text := e.theme.TextBoxDefaultStyle.FontColor
+	text.A *= 0.6
+	colors := make([]gxui.Color, hi-lo)
+	for i := range colors {
+		colors[i] = text
+	}
+	for i, l := range e.layers {
+		for _, s := range l.Spans {
+			if s.End <= lo || s.Start >= hi {
+				continue
+			}
+			from, to := s.Start, s.End
+			if from < lo {
+				from = lo
+			}
+			if to > hi {
+				to = hi
+			}
+			for pos := from; pos < to; pos++ {
+				colors[pos-lo] = e.layerColors[i]
+			}
+		}
+	}
+	return colors
+}
+
+// minimapScroll scrolls e so that the line at y in the minimap is in
+// the middle of the editor.
+func (e *CodeEditor) minimapScroll(y int) {
+	r := e.minimapRect()
+	line := e.minimapStart(r) + (y-r.Min.Y)/minimapLineHeight
+	offset := line*e.lineHeight() - e.Size().H/2
+	if offset < 0 {
+		offset = 0
+	}
+	e.SetScrollOffset(offset)
+}
+
+func (e *CodeEditor) MouseDown(ev gxui.MouseEvent) {
+	if e.minimap && ev.Button == gxui.MouseButtonLeft && ev.Point.X >= e.minimapRect().Min.X {
+		e.minimapDrag = true
+		e.minimapScroll(ev.Point.Y)
+		return
+	}
+	e.CodeEditor.MouseDown(ev)
+}
+
+func (e *CodeEditor) MouseMove(ev gxui.MouseEvent) {
+	if e.minimapDrag {
+		e.minimapScroll(ev.Point.Y)
+		return
+	}
+	e.CodeEditor.MouseMove(ev)
+	e.hoverDiagnostics(ev)
+}
+
+func (e *CodeEditor) MouseUp(ev gxui.MouseEvent) {
+	if e.minimapDrag {
+		e.minimapDrag = false
+		return
+	}
+	e.CodeEditor.MouseUp(ev)
+}
@@ -0,0 +1,81 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"time"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/occurrence"
+	"github.com/nelsam/vidar/setting"
+	"github.com/nelsam/vidar/theme"
+)
+
+// occurrenceDelay is how long the caret has to rest before the other
+// occurrences of the identifier under it are highlighted.
+const occurrenceDelay = 250 * time.Millisecond
+
+// queueOccurrences clears e's occurrences, since they're out of date
+// as soon as the caret moves or the text changes, and finds them again
+// once e has gone occurrenceDelay without changing.
+func (e *CodeEditor) queueOccurrences() {
+	e.setOccurrences(nil)
+	if e.occurrenceTimer != nil {
+		e.occurrenceTimer.Stop()
+	}
+	e.occurrenceTimer = time.AfterFunc(occurrenceDelay, func() {
+		e.driver.Call(e.findOccurrences)
+	})
+}
+
+// findOccurrences finds the other occurrences of the identifier under
+// e's caret in the background.  They're only highlighted if e's text
+// and caret haven't changed by the time they're found.  Nothing is
+// highlighted while text is selected or there are multiple carets.
+func (e *CodeEditor) findOccurrences() {
+	if !setting.HighlightOccurrences() {
+		return
+	}
+	sel := e.Controller().SelectionSlice()
+	if len(sel) != 1 || sel[0].Start() != sel[0].End() {
+		return
+	}
+	caret, revision := sel[0].Start(), e.revision
+	path, text := e.filepath, append([]rune(nil), e.Runes()...)
+	go func() {
+		found := occurrence.Find(path, text, caret)
+		if len(found) == 0 {
+			return
+		}
+		spans := make([]input.Span, 0, len(found))
+		for _, f := range found {
+			spans = append(spans, input.Span{Start: f.Start, End: f.End})
+		}
+		e.driver.Call(func() {
+			if e.revision != revision || e.Controller().LastCaret() != caret {
+				return
+			}
+			e.setOccurrences(spans)
+		})
+	}()
+}
+
+// setOccurrences replaces the occurrences that e highlights.  They're
+// only e's, rather than being shared with its views, since each view
+// has its own caret.
+func (e *CodeEditor) setOccurrences(spans []input.Span) {
+	if len(spans) == 0 && len(e.occurrences) == 0 {
+		return
+	}
+	e.occurrences = spans
+	e.setSyntaxLayers(e.layers)
+}
+
+// occurrenceLayer returns the syntax layer that highlights e's
+// occurrences.
+func (e *CodeEditor) occurrenceLayer() *gxui.CodeSyntaxLayer {
+	return codeLayer(e.syntaxTheme.Constructs[theme.Occurrence], e.occurrences)
+}
@@ -20,6 +20,12 @@ type ProjectEditor struct {
 }
 
 func NewProjectEditor(driver gxui.Driver, window gxui.Window, cmdr Commander, theme *basic.Theme, syntaxTheme theme.Theme, font gxui.Font, project setting.Project) *ProjectEditor {
+	return newProjectEditor(driver, window, cmdr, theme, syntaxTheme, font, project, true)
+}
+
+// newProjectEditor creates a *ProjectEditor for project, restoring the
+// layout that was saved for it in the session if restore is set.
+func newProjectEditor(driver gxui.Driver, window gxui.Window, cmdr Commander, theme *basic.Theme, syntaxTheme theme.Theme, font gxui.Font, project setting.Project, restore bool) *ProjectEditor {
 	p := &ProjectEditor{}
 	p.driver = driver
 	p.window = window
@@ -34,12 +40,30 @@ func NewProjectEditor(driver gxui.Driver, window gxui.Window, cmdr Commander, th
 	p.project = project
 	p.SetMouseEventTarget(true)
 
-	p.AddChild(NewTabbedEditor(driver, cmdr, theme, syntaxTheme, font))
+	if !restore || !p.restore() {
+		p.AddChild(NewTabbedEditor(driver, cmdr, theme, syntaxTheme, font))
+	}
 	return p
 }
 
 func (p *ProjectEditor) Open(path string) (e input.Editor, existed bool) {
-	return p.SplitEditor.Open(p.project.Path, path, p.project.LicenseHeader(), p.project.Environ())
+	return p.SplitEditor.Open(p.project.Path, path, p.project.LicenseHeaderFor(path), p.project.GoEnviron())
+}
+
+// OpenSplit opens path in a new split next to the current one.  If
+// path is already open, the new split is a view of it, so that edits
+// in either split show up in both.
+func (p *ProjectEditor) OpenSplit(orientation gxui.Orientation, path string) {
+	for _, ed := range p.OpenEditors() {
+		if ce, ok := ed.(*CodeEditor); ok && ce.Filepath() == path {
+			p.splitWith(orientation, p.project.Path, ce.NewView())
+			return
+		}
+	}
+	ce := &CodeEditor{}
+	ce.Init(p.driver, p.theme, p.syntaxTheme, p.font, path, p.project.LicenseHeaderFor(path))
+	ce.SetTabWidth(setting.IndentFor(path).Width)
+	p.splitWith(orientation, p.project.Path, ce)
 }
 
 func (p *ProjectEditor) Project() setting.Project {
@@ -58,37 +82,101 @@ type MultiProjectEditor struct {
 
 	current  *ProjectEditor
 	projects map[string]*ProjectEditor
+
+	// session is set when e's layouts are restored from and saved to
+	// the session.  Only one window's editor uses the session.
+	session bool
 }
 
+// New creates a *MultiProjectEditor whose layout for each project is
+// restored from the session.
 func New(driver gxui.Driver, window gxui.Window, cmdr Commander, theme *basic.Theme, syntaxTheme theme.Theme, font gxui.Font) *MultiProjectEditor {
-	defaultEditor := NewProjectEditor(driver, window, cmdr, theme, syntaxTheme, font, setting.DefaultProject)
+	return newMultiProjectEditor(driver, window, cmdr, theme, syntaxTheme, font, true)
+}
+
+// NewWithoutSession creates a *MultiProjectEditor which starts out
+// empty and doesn't save its layouts, for windows other than the
+// first.
+func NewWithoutSession(driver gxui.Driver, window gxui.Window, cmdr Commander, theme *basic.Theme, syntaxTheme theme.Theme, font gxui.Font) *MultiProjectEditor {
+	return newMultiProjectEditor(driver, window, cmdr, theme, syntaxTheme, font, false)
+}
 
+func newMultiProjectEditor(driver gxui.Driver, window gxui.Window, cmdr Commander, theme *basic.Theme, syntaxTheme theme.Theme, font gxui.Font, session bool) *MultiProjectEditor {
 	e := &MultiProjectEditor{
-		projects: map[string]*ProjectEditor{
-			"*default*": defaultEditor,
-		},
 		driver:      driver,
 		window:      window,
 		cmdr:        cmdr,
 		font:        font,
 		theme:       theme,
 		syntaxTheme: syntaxTheme,
+		session:     session,
+	}
+	defaultEditor := newProjectEditor(driver, window, cmdr, theme, syntaxTheme, e.projectFont(setting.DefaultProject), setting.DefaultProject, session)
+	e.projects = map[string]*ProjectEditor{
+		"*default*": defaultEditor,
 	}
 	e.LinearLayout.Init(e, theme)
 	e.AddChild(defaultEditor)
 	e.current = defaultEditor
+	setting.OnProjectSettingsChange(func(root string) {
+		driver.Call(func() {
+			e.reloadFonts(root)
+		})
+	})
 	return e
 }
 
+// projectFont returns the font for editors in project, which is e's
+// font unless project's settings have their own fonts.
+func (e *MultiProjectEditor) projectFont(project setting.Project) gxui.Font {
+	if len(project.Settings().Fonts) == 0 {
+		return e.font
+	}
+	return setting.ProjectFont(e.driver, project, setting.FontSize())
+}
+
+// reloadFonts updates the font of each project editor whose project
+// is at root, after the project's settings change.
+func (e *MultiProjectEditor) reloadFonts(root string) {
+	for _, p := range e.projects {
+		if p.Project().Path == root {
+			p.SetFont(e.projectFont(p.Project()))
+		}
+	}
+}
+
 func (e *MultiProjectEditor) SetProject(project setting.Project) {
 	editor, ok := e.projects[project.Name]
 	if !ok {
-		editor = NewProjectEditor(e.driver, e.window, e.cmdr, e.theme, e.syntaxTheme, e.font, project)
+		editor = newProjectEditor(e.driver, e.window, e.cmdr, e.theme, e.syntaxTheme, e.projectFont(project), project, e.session)
 		e.projects[project.Name] = editor
 	}
+	// The project may have changed since it was last opened (e.g. a
+	// root was added to it).
+	editor.project = project
 	e.RemoveChild(e.current)
 	e.AddChild(editor)
 	e.current = editor
+	e.updateTitle()
+}
+
+// SetSyntaxTheme changes the syntax theme of every project's editors,
+// including projects that aren't currently open.
+func (e *MultiProjectEditor) SetSyntaxTheme(t theme.Theme) {
+	e.syntaxTheme = t
+	for _, p := range e.projects {
+		p.SetSyntaxTheme(t)
+	}
+}
+
+// SetFont changes the font of every project's editors, including
+// projects that aren't currently open.  Projects with their own fonts
+// keep them, at the size from setting.FontSize.
+func (e *MultiProjectEditor) SetFont(font gxui.Font) {
+	e.font = font
+	for _, p := range e.projects {
+		p.SetFont(e.projectFont(p.Project()))
+	}
 }
 
 func (e *MultiProjectEditor) Elements() []interface{} {
@@ -110,5 +198,7 @@ func (e *MultiProjectEditor) CurrentProject() setting.Project {
 }
 
 func (e *MultiProjectEditor) Open(file string) (ed input.Editor, existed bool) {
-	return e.current.Open(file)
+	ed, existed = e.current.Open(file)
+	e.setTitle(ed)
+	return ed, existed
 }
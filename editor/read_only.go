@@ -0,0 +1,48 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"go/build"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// readOnlyDirs returns the directories whose files are opened
+// read-only: GOROOT and the module cache.  Those files are shared by
+// every project, so editing them is almost always a mistake.
+func readOnlyDirs() []string {
+	dirs := []string{build.Default.GOROOT}
+	if cache := os.Getenv("GOMODCACHE"); cache != "" {
+		return append(dirs, cache)
+	}
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		dirs = append(dirs, filepath.Join(gopath, "pkg", "mod"))
+	}
+	return dirs
+}
+
+// readOnlyPath returns whether or not the file at path should be
+// opened read-only, either because nobody has permission to write
+// to it or because it's in one of the readOnlyDirs.
+func readOnlyPath(path string) bool {
+	if path == "" {
+		return false
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0222 == 0 {
+		return true
+	}
+	for _, dir := range readOnlyDirs() {
+		if dir == "" {
+			continue
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+// minPaneSize is the smallest fraction of a split that a pane can be
+// shrunk to from the keyboard.
+const minPaneSize = 0.05
+
+// ResizeCurrent grows the focused pane by delta, which is a fraction
+// of the split that the pane is in, and shrinks the pane's siblings
+// to make room.  A negative delta shrinks the pane.
+//
+// Nested splits are checked first, so the pane that is resized is
+// the innermost one that has siblings.  ResizeCurrent returns false
+// if the focused pane has no siblings.
+func (e *SplitEditor) ResizeCurrent(delta float64) bool {
+	if inner, ok := e.current.(*SplitEditor); ok && inner.ResizeCurrent(delta) {
+		return true
+	}
+	panes := e.editors()
+	if len(panes) < 2 {
+		return false
+	}
+	idx := -1
+	for i, p := range panes {
+		if p == e.current {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return false
+	}
+	weights := e.Weights()
+	size := weights[idx] + delta
+	if max := 1 - minPaneSize*float64(len(panes)-1); size > max {
+		size = max
+	}
+	if size < minPaneSize {
+		size = minPaneSize
+	}
+	rest := 1 - weights[idx]
+	for i, p := range panes {
+		w := size
+		if i != idx {
+			w = (1 - size) / float64(len(panes)-1)
+			if rest > 0 {
+				w = weights[i] * (1 - size) / rest
+			}
+		}
+		e.SetChildWeight(p, float32(w))
+	}
+	e.Relayout()
+	return true
+}
+
+// ToggleMaximized expands the focused pane to fill e, shrinking every
+// other pane that it shares a split with, including the splits that
+// it's nested in.  If the focused pane is already maximized, the
+// sizes that the panes had before are restored instead.  It returns
+// whether or not the focused pane is now maximized.
+func (e *SplitEditor) ToggleMaximized() bool {
+	if e.maximized() {
+		e.restoreSizes()
+		return false
+	}
+	return e.maximize()
+}
+
+// maximized returns whether or not e or any split between e and the
+// focused pane has been maximized.
+func (e *SplitEditor) maximized() bool {
+	if e.saved != nil {
+		return true
+	}
+	inner, ok := e.current.(*SplitEditor)
+	return ok && inner.maximized()
+}
+
+// maximize gives e's current pane all of e's space, saving the old
+// weights so that restoreSizes can put them back.  It returns false
+// if there was nothing to shrink.
+func (e *SplitEditor) maximize() bool {
+	maximized := false
+	if inner, ok := e.current.(*SplitEditor); ok {
+		maximized = inner.maximize()
+	}
+	panes := e.editors()
+	if len(panes) < 2 {
+		return maximized
+	}
+	e.saved = e.Weights()
+	for _, p := range panes {
+		var w float32
+		if p == e.current {
+			w = 1
+		}
+		e.SetChildWeight(p, w)
+	}
+	e.Relayout()
+	return true
+}
+
+// restoreSizes puts back the weights that were saved by maximize, in
+// e and every split inside of it.  Panes that were added or removed
+// since then leave the sizes equal instead.
+func (e *SplitEditor) restoreSizes() {
+	panes := e.editors()
+	if e.saved != nil {
+		changed := len(e.saved) != len(panes)
+		for i, p := range panes {
+			w := float32(1)
+			if !changed {
+				w = float32(e.saved[i])
+			}
+			e.SetChildWeight(p, w)
+		}
+		e.saved = nil
+	}
+	for _, p := range panes {
+		if inner, ok := p.(*SplitEditor); ok {
+			inner.restoreSizes()
+		}
+	}
+	e.Relayout()
+}
+
+// EqualizePanes gives every pane in e the same size, including the
+// panes in nested splits.
+func (e *SplitEditor) EqualizePanes() {
+	e.saved = nil
+	for _, p := range e.editors() {
+		e.SetChildWeight(p, 1)
+		if inner, ok := p.(*SplitEditor); ok {
+			inner.EqualizePanes()
+		}
+	}
+	e.Relayout()
+}
+
+// Weights returns the fraction of e that each of its panes takes
+// up, in order.
+func (e *SplitEditor) Weights() []float64 {
+	panes := e.editors()
+	weights := make([]float64, len(panes))
+	var total float64
+	for i, p := range panes {
+		weights[i] = float64(e.ChildWeight(p))
+		total += weights[i]
+	}
+	for i := range weights {
+		if total <= 0 {
+			weights[i] = 1 / float64(len(weights))
+			continue
+		}
+		weights[i] /= total
+	}
+	return weights
+}
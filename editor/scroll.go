@@ -0,0 +1,118 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"time"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/setting"
+)
+
+const (
+	// scrollFrame is the time between each step of a smooth scroll.
+	scrollFrame = 15 * time.Millisecond
+
+	// scrollEase is how quickly a smooth scroll slows down: each step
+	// moves 1/scrollEase of the distance that is left.
+	scrollEase = 4
+)
+
+// smoothly runs scroll, which may change e's scroll offset, and then
+// animates the change if smooth scrolling is enabled.  Changes made
+// while e is still animating are added to where it's headed, so that
+// quick turns of the mouse wheel aren't lost.  It must be called on
+// the UI goroutine.
+func (e *CodeEditor) smoothly(scroll func() bool) bool {
+	smooth := setting.SmoothScroll()
+	// Rounding to whole lines would turn each step into a jump.
+	e.SetScrollRound(!smooth)
+	if !smooth {
+		return e.stopScrolling(scroll)
+	}
+	from := e.ScrollOffset()
+	consume := scroll()
+	to := e.ScrollOffset()
+	if to == from {
+		return consume
+	}
+	target := from
+	if e.scrolling {
+		target = e.scrollTarget
+	}
+	e.SetScrollOffset(from)
+	e.animateScroll(target + to - from)
+	return consume
+}
+
+// stopScrolling runs scroll after stopping any smooth scroll that is
+// in progress.
+func (e *CodeEditor) stopScrolling(scroll func() bool) bool {
+	e.scrollTarget = e.ScrollOffset()
+	return scroll()
+}
+
+// animateScroll scrolls e a step at a time until its scroll offset
+// reaches target.  It must be called on the UI goroutine.
+func (e *CodeEditor) animateScroll(target int) {
+	e.scrollTarget = target
+	if e.scrolling {
+		return
+	}
+	e.scrolling = true
+	go func() {
+		ticker := time.NewTicker(scrollFrame)
+		defer ticker.Stop()
+		for range ticker.C {
+			done := false
+			e.driver.CallSync(func() {
+				done = e.scrollStep()
+			})
+			if done {
+				return
+			}
+		}
+	}()
+}
+
+// scrollStep moves e's scroll offset closer to e.scrollTarget.  It
+// returns true when there's nowhere left to scroll.
+func (e *CodeEditor) scrollStep() (done bool) {
+	from := e.ScrollOffset()
+	step := (e.scrollTarget - from) / scrollEase
+	switch {
+	case step == 0 && e.scrollTarget > from:
+		step = 1
+	case step == 0 && e.scrollTarget < from:
+		step = -1
+	}
+	e.SetScrollOffset(from + step)
+	if e.ScrollOffset() == from || e.ScrollOffset() == e.scrollTarget {
+		// Either the target has been reached, or it's past the
+		// start or end of e and can't be.
+		e.scrolling = false
+		return true
+	}
+	return false
+}
+
+// pastEndRows returns the number of empty rows that are added after
+// e's last line, so that it can be scrolled up to the middle of the
+// view.
+func (e *CodeEditor) pastEndRows() int {
+	if !setting.ScrollPastEnd() {
+		return 0
+	}
+	return e.Size().Contract(e.Padding()).H / e.lineHeight() / 2
+}
+
+// pastEndItem is the adapter item for a row that pastEndRows added.
+// It's a separate type so that it isn't mistaken for a line.
+type pastEndItem int
+
+// createPastEnd creates the control for a row that pastEndRows added.
+func createPastEnd(theme gxui.Theme) gxui.Control {
+	return theme.CreateLabel()
+}
@@ -0,0 +1,204 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"log"
+	"os"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/setting"
+)
+
+const (
+	horizontal = "horizontal"
+	vertical   = "vertical"
+)
+
+func orientationName(o gxui.Orientation) string {
+	if o.Vertical() {
+		return vertical
+	}
+	return horizontal
+}
+
+func orientation(name string) gxui.Orientation {
+	if name == vertical {
+		return gxui.Vertical
+	}
+	return gxui.Horizontal
+}
+
+// SaveSession saves the layout of every open project, so that it
+// can be restored the next time the project is opened.
+func (e *MultiProjectEditor) SaveSession() {
+	if !e.session {
+		return
+	}
+	layouts := make(map[string]setting.Layout, len(e.projects))
+	for name, p := range e.projects {
+		layouts[name] = p.layout()
+	}
+	setting.SaveSession(e.current.Project().Name, layouts)
+}
+
+// restore rebuilds the layout that was saved for p's project.  It
+// returns false if there was nothing to restore.
+func (p *ProjectEditor) restore() bool {
+	l, ok := setting.SessionLayout(p.project.Name)
+	if !ok {
+		return false
+	}
+	p.restoreLayout(p.project, l)
+	linkViews(p.OpenEditors())
+	return len(p.Children()) > 0
+}
+
+// Layout returns the layout of p's splits and the files open in them.
+func (p *ProjectEditor) Layout() setting.Layout {
+	return p.layout()
+}
+
+// LoadLayout replaces p's splits with the ones in l.  Files that are
+// open in p and not in l are closed without saving, so unsaved changes
+// should be saved first.
+func (p *ProjectEditor) LoadLayout(l setting.Layout) {
+	p.RemoveAll()
+	p.current = nil
+	p.saved = nil
+	p.restoreLayout(p.project, l)
+	if len(p.Children()) == 0 {
+		p.SetOrientation(gxui.Horizontal)
+		p.AddChild(NewTabbedEditor(p.driver, p.cmdr, p.theme, p.syntaxTheme, p.font))
+	}
+	linkViews(p.OpenEditors())
+}
+
+func (e *SplitEditor) layout() setting.Layout {
+	l := setting.Layout{Orientation: orientationName(e.Orientation())}
+	weights := e.Weights()
+	if len(e.saved) == len(weights) {
+		// A maximized pane is only temporary, so the layout from
+		// before it was maximized is the one that's kept.
+		weights = e.saved
+	}
+	for i, child := range e.editors() {
+		var cl setting.Layout
+		switch src := child.(type) {
+		case *SplitEditor:
+			cl = src.layout()
+		case *TabbedEditor:
+			cl = src.layout()
+		default:
+			continue
+		}
+		if cl.Empty() {
+			continue
+		}
+		if child == e.current {
+			l.Current = len(l.Splits)
+		}
+		l.Splits = append(l.Splits, cl)
+		l.Weights = append(l.Weights, weights[i])
+	}
+	return l
+}
+
+func (e *SplitEditor) restoreLayout(p setting.Project, l setting.Layout) {
+	e.SetOrientation(orientation(l.Orientation))
+	for i, cl := range l.Splits {
+		var child MultiEditor
+		if len(cl.Splits) > 0 {
+			split := NewSplitEditor(e.driver, e.cmdr, e.window, e.theme, e.syntaxTheme, e.font)
+			split.restoreLayout(p, cl)
+			if len(split.Children()) == 0 {
+				continue
+			}
+			child = split
+		} else {
+			tabs := NewTabbedEditor(e.driver, e.cmdr, e.theme, e.syntaxTheme, e.font)
+			tabs.restoreLayout(p, cl)
+			if tabs.Editors() == 0 {
+				continue
+			}
+			child = tabs
+		}
+		e.AddChild(child)
+		if i < len(l.Weights) && l.Weights[i] > 0 {
+			e.SetChildWeight(child, float32(l.Weights[i]))
+		}
+		if i == l.Current {
+			e.current = child
+		}
+	}
+}
+
+func (e *TabbedEditor) layout() setting.Layout {
+	var l setting.Layout
+	for i := 0; i < e.PanelCount(); i++ {
+		ce, ok := e.Panel(i).(*CodeEditor)
+		if !ok {
+			continue
+		}
+		if ce == e.SelectedPanel() {
+			l.Current = len(l.Files)
+		}
+		l.Files = append(l.Files, ce.openFile())
+	}
+	return l
+}
+
+func (e *TabbedEditor) restoreLayout(p setting.Project, l setting.Layout) {
+	var current input.Editor
+	for i, f := range l.Files {
+		if _, err := os.Stat(f.Path); err != nil {
+			log.Printf("WARNING: not restoring %s: %s", f.Path, err)
+			continue
+		}
+		ed, _ := e.Open(p.Path, f.Path, p.LicenseHeaderFor(f.Path), p.GoEnviron())
+		if ce, ok := ed.(*CodeEditor); ok {
+			ce.restoreOpenFile(f)
+		}
+		if i == l.Current {
+			current = ed
+		}
+	}
+	if current != nil {
+		e.Select(e.PanelIndex(current.(gxui.Control)))
+	}
+}
+
+func (e *CodeEditor) openFile() setting.OpenFile {
+	return setting.OpenFile{
+		Path:    e.Filepath(),
+		Carets:  e.Carets(),
+		ScrollX: e.HorizOffset(),
+		ScrollY: e.ScrollOffset(),
+		Folds:   e.FoldedLines(),
+	}
+}
+
+func (e *CodeEditor) restoreOpenFile(f setting.OpenFile) {
+	// The file's text is loaded asynchronously, so the positions have
+	// to be restored after it is set.
+	e.driver.Call(func() {
+		max := len(e.Controller().TextRunes())
+		var sel []gxui.TextSelection
+		for _, c := range f.Carets {
+			if c > max {
+				c = max
+			}
+			sel = append(sel, gxui.CreateTextSelection(c, c, true))
+		}
+		if len(sel) > 0 {
+			e.Controller().SetSelections(sel)
+		}
+		e.SetFoldedLines(f.Folds...)
+		e.SetHorizOffset(f.ScrollX)
+		e.SetScrollOffset(f.ScrollY)
+		e.storePositions()
+	})
+}
@@ -7,6 +7,7 @@ package editor
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/nelsam/gxui"
@@ -50,19 +51,29 @@ type Opener interface {
 type Commander interface {
 	Bindable(string) bind.Bindable
 	Execute(bind.Bindable)
+	Run(bind.Command)
+}
+
+// A Zener is a Commander that can be put in zen mode, which hides
+// the tabs of every editor.
+type Zener interface {
+	Zen() bool
 }
 
 type MultiEditor interface {
 	gxui.Control
 	outer.LayoutChildren
 	Has(hiddenPrefix, path string) bool
+	OpenEditors() []input.Editor
 	Open(hiddenPrefix, path, headerText string, environ []string) (editor input.Editor, existed bool)
 	Editors() uint
 	CurrentEditor() input.Editor
 	CurrentFile() string
 	CloseCurrentEditor() (name string, editor input.Editor)
 	Add(name string, editor input.Editor)
-	SaveAll()
+	SaveAll() []error
+	SetSyntaxTheme(theme.Theme)
+	SetFont(gxui.Font)
 }
 
 type Direction int
@@ -85,6 +96,10 @@ type SplitEditor struct {
 	window      gxui.Window
 
 	current MultiEditor
+
+	// saved holds the weights of e's panes from before the focused
+	// pane was maximized, or nil if it isn't.
+	saved []float64
 }
 
 func NewSplitEditor(driver gxui.Driver, cmdr Commander, window gxui.Window, theme *basic.Theme, syntaxTheme theme.Theme, font gxui.Font) *SplitEditor {
@@ -113,6 +128,46 @@ func (e *SplitEditor) Split(orientation gxui.Orientation) {
 		return
 	}
 	name, editor := e.current.CloseCurrentEditor()
+	e.addSplit(orientation, name, editor)
+}
+
+// SplitFile opens the current file in a new split next to the current
+// one, leaving it open where it is.  The two editors share their text,
+// so edits in either one show up in both.
+func (e *SplitEditor) SplitFile(orientation gxui.Orientation) {
+	if splitter, ok := e.current.(*SplitEditor); ok {
+		splitter.SplitFile(orientation)
+		return
+	}
+	tabs, ok := e.current.(*TabbedEditor)
+	if !ok {
+		return
+	}
+	ce, ok := tabs.CurrentEditor().(*CodeEditor)
+	if !ok {
+		return
+	}
+	name := tabs.nameOf(ce)
+	hiddenPrefix := strings.TrimSuffix(ce.Filepath(), name)
+	view := ce.NewView()
+	newSplit := e.addSplit(orientation, name, view)
+	view.OnRename(newSplit.renameTab(view, hiddenPrefix))
+}
+
+// splitWith adds a split with ce in it next to the innermost current
+// split.  hiddenPrefix is trimmed from ce's path for its tab name.
+func (e *SplitEditor) splitWith(orientation gxui.Orientation, hiddenPrefix string, ce *CodeEditor) {
+	if splitter, ok := e.current.(*SplitEditor); ok {
+		splitter.splitWith(orientation, hiddenPrefix, ce)
+		return
+	}
+	newSplit := e.addSplit(orientation, relPath(hiddenPrefix, ce.Filepath()), ce)
+	ce.OnRename(newSplit.renameTab(ce, hiddenPrefix))
+}
+
+// addSplit adds a split with editor in it next to the current split,
+// and focuses editor.
+func (e *SplitEditor) addSplit(orientation gxui.Orientation, name string, editor input.Editor) *TabbedEditor {
 	newSplit := NewTabbedEditor(e.driver, e.cmdr, e.theme, e.syntaxTheme, e.font)
 	defer func() {
 		newSplit.Add(name, editor)
@@ -121,7 +176,7 @@ func (e *SplitEditor) Split(orientation gxui.Orientation) {
 	}()
 	if e.Orientation() == orientation {
 		e.AddChild(newSplit)
-		return
+		return newSplit
 	}
 	newSplitter := NewSplitEditor(e.driver, e.cmdr, e.window, e.theme, e.syntaxTheme, e.font)
 	newSplitter.SetOrientation(orientation)
@@ -139,6 +194,7 @@ func (e *SplitEditor) Split(orientation gxui.Orientation) {
 	e.current = newSplitter
 	e.AddChildAt(index, e.current)
 	newSplitter.AddChild(newSplit)
+	return newSplit
 }
 
 func (e *SplitEditor) Editors() (count uint) {
@@ -240,6 +296,15 @@ func (e *SplitEditor) Has(hiddenPrefix, path string) bool {
 	return false
 }
 
+// OpenEditors returns all of the editors that are open in e.
+func (e *SplitEditor) OpenEditors() []input.Editor {
+	var editors []input.Editor
+	for _, me := range e.editors() {
+		editors = append(editors, me.OpenEditors()...)
+	}
+	return editors
+}
+
 func (e *SplitEditor) Open(hiddenPrefix, path, headerText string, environ []string) (editor input.Editor, existed bool) {
 	for _, child := range e.Children() {
 		if me, ok := child.Control.(MultiEditor); ok && me.Has(hiddenPrefix, path) {
@@ -385,13 +450,43 @@ func (e *SplitEditor) first(d Direction) input.Editor {
 	}
 }
 
-func (e *SplitEditor) SaveAll() {
+// SaveAll saves the unsaved changes in every editor in e, returning
+// an error for each file that could not be saved.
+func (e *SplitEditor) SaveAll() []error {
+	var errs []error
+	for _, child := range e.Children() {
+		editor, ok := child.Control.(MultiEditor)
+		if !ok {
+			continue
+		}
+		errs = append(errs, editor.SaveAll()...)
+	}
+	return errs
+}
+
+// SetSyntaxTheme changes the syntax theme of e and every editor
+// inside of it.
+func (e *SplitEditor) SetSyntaxTheme(t theme.Theme) {
+	e.syntaxTheme = t
+	for _, child := range e.Children() {
+		editor, ok := child.Control.(MultiEditor)
+		if !ok {
+			continue
+		}
+		editor.SetSyntaxTheme(t)
+	}
+}
+
+// SetFont changes the font of every editor inside of e.  It's also
+// used for editors that are split off later.
+func (e *SplitEditor) SetFont(font gxui.Font) {
+	e.font = font
 	for _, child := range e.Children() {
 		editor, ok := child.Control.(MultiEditor)
 		if !ok {
 			continue
 		}
-		editor.SaveAll()
+		editor.SetFont(font)
 	}
 }
 
@@ -0,0 +1,136 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/mixins/parts"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// tabItems are the labels and command names of the items in the tab
+// context menu.  The pin item's label is changed to "Unpin Tab" for
+// tabs that are already pinned.
+var tabItems = []struct {
+	label, command string
+}{
+	{"Close", "close-current-tab"},
+	{"Close Others", "close-other-tabs"},
+	{"Close to the Right", "close-tabs-to-right"},
+	{"Close Saved", "close-saved-tabs"},
+	{"Pin Tab", "toggle-pin-tab"},
+}
+
+// tabMenus holds the tab context menu for each window, since one menu
+// is shared by every TabbedEditor in a window.  It's only accessed on
+// the UI goroutine.
+var tabMenus = make(map[gxui.Window]*tabMenu)
+
+// tabMenu is the menu that is shown when a tab is right-clicked.  Each
+// item focuses the tab's editor and then runs a command, so the same
+// operations can be bound to keys.
+type tabMenu struct {
+	parts.Focusable
+	mixins.LinearLayout
+
+	cmdr    Commander
+	overlay gxui.BubbleOverlay
+	pin     gxui.Button
+
+	tabs   *TabbedEditor
+	editor input.Editor
+}
+
+// tabMenuFor returns the tab menu for window, creating it if it
+// doesn't exist yet.  Menus are added to their window the first time
+// that they're needed, so that they're drawn on top of everything
+// that was added when vidar started.
+func tabMenuFor(window gxui.Window, theme *basic.Theme, cmdr Commander) *tabMenu {
+	if m, ok := tabMenus[window]; ok {
+		return m
+	}
+	m := &tabMenu{
+		cmdr:    cmdr,
+		overlay: theme.CreateBubbleOverlay(),
+	}
+	m.Focusable.Init(m)
+	m.LinearLayout.Init(m, theme)
+	m.SetDirection(gxui.TopToBottom)
+	m.SetBackgroundBrush(theme.ButtonDefaultStyle.Brush)
+	m.SetBorderPen(theme.ButtonDefaultStyle.Pen)
+	for _, item := range tabItems {
+		name := item.command
+		b := theme.CreateButton()
+		b.SetText(item.label)
+		b.SetMargin(math.Spacing{L: 2, R: 2})
+		b.OnClick(func(gxui.MouseEvent) {
+			m.hide()
+			m.run(name)
+		})
+		if name == "toggle-pin-tab" {
+			m.pin = b
+		}
+		m.AddChild(b)
+	}
+	m.OnLostFocus(m.hide)
+	window.AddChild(m.overlay)
+	tabMenus[window] = m
+	return m
+}
+
+// show displays m at point, which is in window coordinates, for
+// editor's tab in tabs.
+func (m *tabMenu) show(tabs *TabbedEditor, editor input.Editor, point math.Point) {
+	m.tabs = tabs
+	m.editor = editor
+	label := "Pin Tab"
+	if p, ok := editor.(*CodeEditor); ok && p.Pinned() {
+		label = "Unpin Tab"
+	}
+	m.pin.SetText(label)
+	m.overlay.Show(m, point)
+	gxui.SetFocus(m)
+}
+
+func (m *tabMenu) hide() {
+	m.overlay.Hide()
+}
+
+func (m *tabMenu) run(name string) {
+	cmd, ok := m.cmdr.Bindable(name).(bind.Command)
+	if !ok || m.tabs == nil || m.editor == nil {
+		return
+	}
+	m.tabs.SelectEditor(m.editor)
+	if opener, ok := m.cmdr.Bindable("focus-location").(Opener); ok {
+		m.cmdr.Execute(opener.For(focus.Path(m.editor.Filepath())))
+	}
+	m.cmdr.Run(cmd)
+}
+
+// watchMenu waits for the right mouse button that was pressed on tab
+// to be released, then shows the tab menu for tab's editor.  The menu
+// is shown after the release has been handled, since a release on
+// the editors focuses the current editor.
+func (e *TabbedEditor) watchMenu(tab mixins.PanelTab, down gxui.MouseEvent) {
+	var sub gxui.EventSubscription
+	sub = down.Window.OnMouseUp(func(gxui.MouseEvent) {
+		sub.Unlisten()
+		e.driver.Call(func() {
+			for c, t := range e.tabs {
+				if t != tab {
+					continue
+				}
+				tabMenuFor(down.Window, e.theme, e.cmdr).show(e, c.(input.Editor), down.WindowPoint)
+				return
+			}
+		})
+	})
+}
@@ -5,8 +5,7 @@
 package editor
 
 import (
-	"log"
-	"os"
+	"fmt"
 	"path/filepath"
 	"strings"
 
@@ -15,25 +14,45 @@ import (
 	"github.com/nelsam/gxui/mixins"
 	"github.com/nelsam/gxui/themes/basic"
 	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
 	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/controller"
+	"github.com/nelsam/vidar/setting"
 	"github.com/nelsam/vidar/theme"
 )
 
-type refocuser interface {
-	ReFocus()
-}
+const (
+	// conflictMarker is prepended to the names of tabs whose files
+	// have changed on disk while they had unsaved changes.
+	conflictMarker = "! "
+
+	// pinMarker is prepended to the names of pinned tabs.
+	pinMarker = "• "
+
+	// readOnlyMarker is prepended to the names of tabs whose
+	// editors are read-only.
+	readOnlyMarker = "[ro] "
+)
 
 type TabbedEditor struct {
 	mixins.PanelHolder
 
 	editors map[string]input.Editor
 
+	// tabs holds the tab for each editor.  newTab is the tab that
+	// was most recently created, which is stored in tabs once its
+	// panel has been added.
+	tabs   map[gxui.Control]mixins.PanelTab
+	newTab mixins.PanelTab
+
+	// crumbs shows where the caret is in the current editor.
+	crumbs *breadcrumbs
+
 	driver      gxui.Driver
 	cmdr        Commander
 	theme       *basic.Theme
 	syntaxTheme theme.Theme
 	font        gxui.Font
-	cur         string
 }
 
 func NewTabbedEditor(driver gxui.Driver, cmdr Commander, theme *basic.Theme, syntaxTheme theme.Theme, font gxui.Font) *TabbedEditor {
@@ -44,6 +63,7 @@ func NewTabbedEditor(driver gxui.Driver, cmdr Commander, theme *basic.Theme, syn
 
 func (e *TabbedEditor) Init(outer mixins.PanelHolderOuter, driver gxui.Driver, cmdr Commander, theme *basic.Theme, syntaxTheme theme.Theme, font gxui.Font) {
 	e.editors = make(map[string]input.Editor)
+	e.tabs = make(map[gxui.Control]mixins.PanelTab)
 	e.driver = driver
 	e.cmdr = cmdr
 	e.theme = theme
@@ -51,6 +71,8 @@ func (e *TabbedEditor) Init(outer mixins.PanelHolderOuter, driver gxui.Driver, c
 	e.font = font
 	e.PanelHolder.Init(outer, theme)
 	e.SetMargin(math.Spacing{L: 0, T: 2, R: 0, B: 0})
+	e.crumbs = newBreadcrumbs(driver, theme, cmdr)
+	e.AddChild(e.crumbs)
 }
 
 func (e *TabbedEditor) Has(hiddenPrefix, path string) bool {
@@ -69,12 +91,24 @@ func (e *TabbedEditor) Open(hiddenPrefix, path, headerText string, environ []str
 	editor = ce
 	// We want the OnRename trigger set up before the editor opens the file
 	// in its Init method.
-	ce.OnRename(func(newPath string) {
+	ce.OnRename(e.renameTab(ce, hiddenPrefix))
+	ce.Init(e.driver, e.theme, e.syntaxTheme, e.font, path, headerText)
+	ce.SetTabWidth(setting.IndentFor(path).Width)
+	e.Add(name, editor)
+	return editor, false
+}
+
+// renameTab returns a callback for ce.OnRename that updates the name
+// of ce's tab.
+func (e *TabbedEditor) renameTab(ce *CodeEditor, hiddenPrefix string) func(newPath string) {
+	return func(newPath string) {
 		e.driver.Call(func() {
-			delete(e.editors, name)
+			// The editor may have been renamed before, so its
+			// entry in e.editors is replaced by RemovePanel and
+			// AddPanelAt rather than by the name it was opened
+			// with.
 			newName := relPath(hiddenPrefix, newPath)
 			focused := e.SelectedPanel()
-			e.editors[newName] = editor
 			idx := e.PanelIndex(ce)
 			if idx == -1 {
 				return
@@ -83,28 +117,174 @@ func (e *TabbedEditor) Open(hiddenPrefix, path, headerText string, environ []str
 			e.AddPanelAt(ce, newName, idx)
 			e.Select(e.PanelIndex(focused))
 			gxui.SetFocus(focused.(gxui.Focusable))
+			if t := titlerOf(e); t != nil {
+				t.updateTitle()
+			}
 		})
-	})
-	ce.Init(e.driver, e.theme, e.syntaxTheme, e.font, path, headerText)
-	ce.SetTabWidth(4)
-	e.Add(name, editor)
-	return editor, false
+	}
 }
 
 func (e *TabbedEditor) Add(name string, editor input.Editor) {
 	e.editors[name] = editor
 	ec := editor.(gxui.Control)
 	e.AddPanel(ec, name)
+	e.addedTab(ec)
 	e.Select(e.PanelIndex(ec))
 	gxui.SetFocus(editor.(gxui.Focusable))
 }
 
 func (e *TabbedEditor) AddPanelAt(c gxui.Control, n string, i int) {
+	// Pinned tabs always come before unpinned tabs.
+	pinned := e.pinnedCount()
+	if ce, ok := c.(*CodeEditor); ok && ce.Pinned() {
+		if i > pinned {
+			i = pinned
+		}
+	} else if i < pinned {
+		i = pinned
+	}
 	e.PanelHolder.AddPanelAt(c, n, i)
 	e.editors[n] = c.(input.Editor)
+	e.addedTab(c)
+}
+
+// addedTab stores the tab that was just created for c and starts
+// tracking conflicts between c and its file.
+func (e *TabbedEditor) addedTab(c gxui.Control) {
+	e.tabs[c] = e.newTab
+	ce, ok := c.(*CodeEditor)
+	if !ok {
+		return
+	}
+	// Editors may be moved between TabbedEditors, so the callback is
+	// replaced each time one is added.
+	ce.OnConflict(func(conflicted bool) {
+		e.markConflict(ce, conflicted)
+	})
+	ce.OnReadOnly(func(bool) {
+		e.updateTab(ce)
+	})
+	ce.OnDirty(func(bool) {
+		e.updateTab(ce)
+		if t := titlerOf(e); t != nil {
+			t.updateTitle()
+		}
+	})
+	if ce.Pinned() || ce.ReadOnly() || ce.HasChanges() {
+		e.updateTab(ce)
+	}
+	if ce.HasConflict() {
+		e.markConflict(ce, true)
+	}
+}
+
+// updateTab sets the text of c's tab to c's name, with markers for
+// whether or not it's pinned, read-only, has unsaved changes, or its
+// file has a conflict.
+func (e *TabbedEditor) updateTab(c gxui.Control) {
+	tab, ok := e.tabs[c]
+	if !ok || tab == nil {
+		return
+	}
+	name := e.nameOf(c)
+	if ce, ok := c.(*CodeEditor); ok {
+		if ce.HasChanges() {
+			name = dirtyMarker + name
+		}
+		if ce.HasConflict() {
+			name = conflictMarker + name
+		}
+		if ce.ReadOnly() {
+			name = readOnlyMarker + name
+		}
+		if ce.Pinned() {
+			name = pinMarker + name
+		}
+	}
+	tab.SetText(name)
+}
+
+// nameOf returns the name of c's tab, without any markers.
+func (e *TabbedEditor) nameOf(c gxui.Control) string {
+	for name, editor := range e.editors {
+		if editor == c {
+			return name
+		}
+	}
+	return ""
+}
+
+// pinnedCount returns the number of pinned tabs in e.
+func (e *TabbedEditor) pinnedCount() int {
+	count := 0
+	for i := 0; i < e.PanelCount(); i++ {
+		if ce, ok := e.Panel(i).(*CodeEditor); ok && ce.Pinned() {
+			count++
+		}
+	}
+	return count
+}
+
+// SetPinned pins or unpins editor's tab.  Pinned tabs are moved to the
+// end of the pinned tabs at the start of e, and unpinned tabs to just
+// after them.
+func (e *TabbedEditor) SetPinned(editor input.Editor, pinned bool) {
+	ce, ok := editor.(*CodeEditor)
+	if !ok || ce.pinned == pinned {
+		return
+	}
+	name := e.nameOf(ce)
+	if name == "" {
+		return
+	}
+	focused := e.SelectedPanel()
+	// The panel is removed without going through e.RemovePanel,
+	// since it's about to be added right back.
+	delete(e.tabs, ce)
+	delete(e.editors, name)
+	e.PanelHolder.RemovePanel(ce)
+	ce.pinned = pinned
+	e.AddPanelAt(ce, name, e.pinnedCount())
+	if focused != nil {
+		e.Select(e.PanelIndex(focused))
+	}
+}
+
+// TabEditors returns the editors in e, in the order of their tabs.
+func (e *TabbedEditor) TabEditors() []input.Editor {
+	editors := make([]input.Editor, 0, e.PanelCount())
+	for i := 0; i < e.PanelCount(); i++ {
+		editors = append(editors, e.Panel(i).(input.Editor))
+	}
+	return editors
+}
+
+// SelectEditor selects editor's tab, if it is in e.
+func (e *TabbedEditor) SelectEditor(editor input.Editor) {
+	if idx := e.PanelIndex(editor.(gxui.Control)); idx >= 0 {
+		e.Select(idx)
+	}
+}
+
+// markConflict updates the tab for ce to show whether or not its file
+// has changed on disk while it had unsaved changes.  If ce is the
+// focused editor, the user is asked whether or not to reload it.
+func (e *TabbedEditor) markConflict(ce *CodeEditor, conflicted bool) {
+	tab, ok := e.tabs[ce]
+	if !ok || tab == nil {
+		return
+	}
+	e.updateTab(ce)
+	if !conflicted || e.SelectedPanel() != ce {
+		return
+	}
+	if reload, ok := e.cmdr.Bindable("reload-current-file").(bind.Command); ok {
+		e.cmdr.Run(reload)
+	}
 }
 
 func (e *TabbedEditor) RemovePanel(panel gxui.Control) {
+	delete(e.tabs, panel)
 	toRemove := panel.(input.Editor)
 	for name, editor := range e.editors {
 		if editor == toRemove {
@@ -113,10 +293,15 @@ func (e *TabbedEditor) RemovePanel(panel gxui.Control) {
 		}
 	}
 	e.PanelHolder.RemovePanel(panel)
-	if ed := e.CurrentEditor(); ed != nil {
-		opener := e.cmdr.Bindable("focus-location").(Opener)
-		e.cmdr.Execute(opener.For(focus.Path(ed.Filepath())))
+	ed := e.CurrentEditor()
+	if ed == nil {
+		if t := titlerOf(e); t != nil {
+			t.setTitle(nil)
+		}
+		return
 	}
+	opener := e.cmdr.Bindable("focus-location").(Opener)
+	e.cmdr.Execute(opener.For(focus.Path(ed.Filepath())))
 }
 
 func (e *TabbedEditor) Files() []string {
@@ -127,40 +312,41 @@ func (e *TabbedEditor) Files() []string {
 	return files
 }
 
+// OpenEditors returns all of the editors that are open in e.
+func (e *TabbedEditor) OpenEditors() []input.Editor {
+	editors := make([]input.Editor, 0, len(e.editors))
+	for _, ed := range e.editors {
+		editors = append(editors, ed)
+	}
+	return editors
+}
+
 func (e *TabbedEditor) Editors() uint {
 	return uint(len(e.editors))
 }
 
 func (e *TabbedEditor) CreatePanelTab() mixins.PanelTab {
 	tab := basic.CreatePanelTab(e.theme)
-	tab.OnMouseDown(func(ev gxui.MouseEvent) {
-		if e.CurrentEditor() != nil {
-			e.cur = e.CurrentEditor().Filepath()
-		}
-	})
-	tab.OnMouseUp(func(gxui.MouseEvent) {
-		if e.CurrentEditor() == nil {
-			if len(e.editors) <= 1 {
-				e.purgeSelf()
-			} else {
-				delete(e.editors, e.cur)
+	tab.OnClick(func(gxui.MouseEvent) {
+		// The tab's panel is selected by another click handler,
+		// which may run after this one.
+		e.driver.Call(func() {
+			if t := titlerOf(e); t != nil {
+				t.setTitle(e.CurrentEditor())
 			}
+		})
+	})
+	tab.OnMouseDown(func(ev gxui.MouseEvent) {
+		if ev.Button == gxui.MouseButtonRight {
+			e.watchMenu(tab, ev)
+			return
 		}
+		e.watchDrag(ev)
 	})
-
+	e.newTab = tab
 	return tab
 }
 
-func (e *TabbedEditor) purgeSelf() {
-	// Because of the order of events in gxui when a mouse drag happens,
-	// the tab will move to a separate split *after* the SplitEditor's
-	// MouseUp method is called, so the SplitEditor has no idea that
-	// we're now empty.  We have to purge ourselves from the SplitEditor.
-	parent := e.Parent()
-	parent.(gxui.Container).RemoveChild(e)
-	parent.(refocuser).ReFocus()
-}
-
 func (e *TabbedEditor) EditorAt(d Direction) input.Editor {
 	panels := e.PanelCount()
 	if panels < 2 {
@@ -201,15 +387,108 @@ func (e *TabbedEditor) CloseCurrentEditor() (name string, editor input.Editor) {
 	return name, toRemove
 }
 
-func (e *TabbedEditor) SaveAll() {
-	for name, editor := range e.editors {
-		f, err := os.Create(name)
-		if err != nil {
-			log.Printf("Could not save %s : %s", name, err)
+// SaveAll writes every editor in e that has unsaved changes to disk,
+// returning an error for each file that could not be saved.  Files
+// that changed on disk since they were loaded are not overwritten.
+func (e *TabbedEditor) SaveAll() []error {
+	var errs []error
+	for _, editor := range e.editors {
+		ce, ok := editor.(*CodeEditor)
+		if !ok || ce.ReadOnly() || !ce.HasChanges() {
+			continue
+		}
+		if err := ce.save(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", filepath.Base(ce.Filepath()), err))
 		}
-		defer f.Close()
-		if _, err := f.WriteString(editor.Text()); err != nil {
-			log.Printf("Could not write to file %s: %s", name, err)
+	}
+	return errs
+}
+
+// SetSyntaxTheme changes the syntax theme of e and all of its
+// editors.
+func (e *TabbedEditor) SetSyntaxTheme(t theme.Theme) {
+	e.syntaxTheme = t
+	for _, editor := range e.editors {
+		if ce, ok := editor.(*CodeEditor); ok {
+			ce.SetSyntaxTheme(t)
+		}
+	}
+}
+
+// SetFont changes the font of e's tabs and all of its editors.
+func (e *TabbedEditor) SetFont(font gxui.Font) {
+	e.font = font
+	for _, editor := range e.editors {
+		if ce, ok := editor.(*CodeEditor); ok {
+			ce.SetFont(font)
+		}
+	}
+	for _, child := range e.Children() {
+		if _, ok := child.Control.(input.Editor); ok {
+			continue
+		}
+		controller.SetFont(child.Control, font)
+	}
+}
+
+// MouseScroll changes the font size when the control key is held.
+// Otherwise, the event is left for other controls.
+func (e *TabbedEditor) MouseScroll(ev gxui.MouseEvent) bool {
+	if !ev.Modifier.Control() || ev.ScrollY == 0 {
+		return e.PanelHolder.MouseScroll(ev)
+	}
+	name := "increase-font-size"
+	if ev.ScrollY < 0 {
+		name = "decrease-font-size"
+	}
+	if b := e.cmdr.Bindable(name); b != nil {
+		e.cmdr.Execute(b)
+	}
+	return true
+}
+
+// LayoutChildren lays out e's tabs, then its breadcrumbs, then its
+// current editor below them.  In zen mode, the tabs and breadcrumbs
+// are hidden and the editor fills e.
+func (e *TabbedEditor) LayoutChildren() {
+	if ed := e.CurrentEditor(); !e.crumbs.follows(ed) {
+		// The selected editor can change without e hearing about
+		// it (e.g. when a tab is clicked), but it always lays e out
+		// again.  Updating the breadcrumbs lays e out too, so they
+		// wait until this layout is done.
+		e.driver.Call(func() {
+			e.crumbs.setEditor(ed)
+		})
+	}
+	zen := false
+	if z, ok := e.cmdr.(Zener); ok {
+		zen = z.Zen()
+	}
+	s := e.Size().Contract(e.Padding())
+	o := e.Padding().LT()
+	var tabsHeight, crumbsHeight int
+	for _, child := range e.Children() {
+		switch child.Control.(type) {
+		case input.Editor:
+		case *breadcrumbs:
+			if !zen && !e.crumbs.empty() {
+				crumbsHeight = e.crumbs.DesiredSize(math.ZeroSize, s).H
+			}
+		default:
+			// This is the layout that holds the tabs.
+			if !zen {
+				tabsHeight = child.Control.DesiredSize(math.ZeroSize, s).H
+			}
+		}
+	}
+	for _, child := range e.Children() {
+		switch child.Control.(type) {
+		case input.Editor:
+			child.Layout(math.CreateRect(0, tabsHeight+crumbsHeight, s.W, s.H).Offset(o))
+		case *breadcrumbs:
+			child.Layout(math.CreateRect(0, tabsHeight, s.W, tabsHeight+crumbsHeight).Offset(o))
+		default:
+			child.Layout(math.CreateRect(0, 0, s.W, tabsHeight).Offset(o))
 		}
 	}
 }
@@ -0,0 +1,84 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/setting"
+)
+
+const (
+	// dirtyMarker is prepended to the names of tabs, and to the
+	// window title, when an editor has unsaved changes.
+	dirtyMarker = "● "
+
+	// titleSeparator separates the parts of the window title.
+	titleSeparator = " — "
+
+	appName = "vidar"
+)
+
+// A titler keeps the title of a window up to date with the editor
+// that is being worked on.
+type titler interface {
+	// setTitle sets the window title for ed, which may be nil.
+	setTitle(ed input.Editor)
+
+	// updateTitle sets the window title for the current editor.
+	updateTitle()
+}
+
+// windowTitle returns the window title for ed in project, in the
+// form "filename — project — vidar".  Parts that don't apply are
+// left out.
+func windowTitle(ed input.Editor, project setting.Project) string {
+	var parts []string
+	dirty := false
+	if ed != nil && ed.Filepath() != "" {
+		parts = append(parts, filepath.Base(ed.Filepath()))
+		if ce, ok := ed.(*CodeEditor); ok {
+			dirty = ce.HasChanges()
+		}
+	}
+	if project.Name != "" && project.Name != setting.DefaultProject.Name {
+		parts = append(parts, project.Name)
+	}
+	title := strings.Join(append(parts, appName), titleSeparator)
+	if dirty {
+		title = dirtyMarker + title
+	}
+	return title
+}
+
+// titlerOf returns the titler that c is a child of, or nil if c
+// isn't in one.
+func titlerOf(c gxui.Control) titler {
+	for parent := c.Parent(); parent != nil; {
+		if t, ok := parent.(titler); ok {
+			return t
+		}
+		pc, ok := parent.(gxui.Control)
+		if !ok {
+			return nil
+		}
+		parent = pc.Parent()
+	}
+	return nil
+}
+
+func (e *MultiProjectEditor) setTitle(ed input.Editor) {
+	if e.window == nil {
+		return
+	}
+	e.window.SetTitle(windowTitle(ed, e.current.Project()))
+}
+
+func (e *MultiProjectEditor) updateTitle() {
+	e.setTitle(e.CurrentEditor())
+}
@@ -0,0 +1,146 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/setting"
+)
+
+// buffer is shared by every editor that shows the same file in a
+// different split.  Edits, syntax layers, diagnostics, and fold
+// regions in one of them are copied to the rest, so they act as
+// views of one text.
+type buffer struct {
+	editors []*CodeEditor
+
+	// scrollLock is set when scrolling one view should scroll the
+	// others by the same amount.
+	scrollLock bool
+}
+
+// NewView creates another editor for e's file which shares e's text,
+// so that edits in either of them show up in both.  Carets, scroll
+// positions, and folded regions are separate for each view.
+func (e *CodeEditor) NewView() *CodeEditor {
+	if e.buffer == nil {
+		e.buffer = &buffer{editors: []*CodeEditor{e}}
+	}
+	v := &CodeEditor{buffer: e.buffer}
+	v.init(e.driver, e.theme, e.syntaxTheme, e.font, e.filepath)
+	e.buffer.editors = append(e.buffer.editors, v)
+
+	v.SetTabWidth(setting.IndentFor(e.filepath).Width)
+	v.SetText(e.Text())
+	v.revision, v.savedRevision = e.revision, e.savedRevision
+	v.setLastModified(e.LastKnownMTime())
+	v.readOnly = e.readOnly
+	v.lineEnding, v.mixedEndings, v.binary = e.lineEnding, e.mixedEndings, e.binary
+	v.SetBookmarks(e.Bookmarks()...)
+	v.setSyntaxLayers(e.layers)
+	for source, diags := range e.diagSources {
+		v.setDiagnostics(source, diags)
+	}
+	v.setFolds(e.folds)
+	go v.watch()
+	return v
+}
+
+// linkViews makes the editors in editors that have the same file into
+// views of each other, e.g. after they have been restored from a
+// session.
+func linkViews(editors []input.Editor) {
+	buffers := make(map[string]*buffer)
+	for _, ed := range editors {
+		ce, ok := ed.(*CodeEditor)
+		if !ok {
+			continue
+		}
+		b, ok := buffers[ce.filepath]
+		if !ok {
+			b = &buffer{}
+			buffers[ce.filepath] = b
+		}
+		b.editors = append(b.editors, ce)
+		ce.buffer = b
+	}
+}
+
+// Views returns the other editors that are showing e's text.  Editors
+// that have been closed are left out.
+func (e *CodeEditor) Views() []*CodeEditor {
+	if e.buffer == nil {
+		return nil
+	}
+	var views []*CodeEditor
+	for _, v := range e.buffer.editors {
+		if v != e && v.Attached() {
+			views = append(views, v)
+		}
+	}
+	return views
+}
+
+// Mirror copies edits that were applied to e's text to the rest of
+// e's views.
+func (e *CodeEditor) Mirror(edits []input.Edit) {
+	text := e.Controller().TextRunes()
+	for _, v := range e.Views() {
+		v.mirror(text, edits)
+	}
+}
+
+// mirror replaces e's text with text after edits were applied to
+// another view, moving e's carets, bookmarks, diagnostics, and folds
+// along with them.
+func (e *CodeEditor) mirror(text []rune, edits []input.Edit) {
+	ctrl := e.Controller()
+	sel := ctrl.SelectionSlice()
+	ctrl.SetTextRunesNoEvent(append([]rune(nil), text...))
+	for i, s := range sel {
+		sel[i] = gxui.CreateTextSelection(shiftOffset(s.Start(), edits), shiftOffset(s.End(), edits), false)
+	}
+	ctrl.SetSelections(sel)
+	e.Edited()
+	e.ShiftBookmarks(edits)
+	e.ShiftDiagnostics(edits)
+	e.ShiftFolds(edits)
+}
+
+// ScrollLocked returns whether or not scrolling e scrolls its views
+// along with it.
+func (e *CodeEditor) ScrollLocked() bool {
+	return e.buffer != nil && e.buffer.scrollLock
+}
+
+// SetScrollLocked sets whether or not scrolling any of e's views
+// scrolls the rest of them by the same amount, which keeps distant
+// parts of a file side by side.
+func (e *CodeEditor) SetScrollLocked(locked bool) {
+	if e.buffer == nil {
+		return
+	}
+	e.buffer.scrollLock = locked
+	for _, v := range e.buffer.editors {
+		v.lastScroll = v.ScrollOffset()
+	}
+}
+
+// syncScroll scrolls e's views by as much as e has scrolled since it
+// was last painted, if scrolling is locked.
+func (e *CodeEditor) syncScroll() {
+	offset := e.ScrollOffset()
+	delta := offset - e.lastScroll
+	e.lastScroll = offset
+	if delta == 0 || !e.ScrollLocked() {
+		return
+	}
+	for _, v := range e.Views() {
+		v.SetScrollOffset(v.ScrollOffset() + delta)
+		// Keep v from scrolling e right back.
+		v.lastScroll = v.ScrollOffset()
+	}
+}
@@ -0,0 +1,80 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package editor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// defaultFileMode is the mode of files that are written for the
+// first time.
+const defaultFileMode os.FileMode = 0644
+
+// WriteFile replaces the contents of the file at path with text.  The
+// text is written to a temporary file in the same directory, which is
+// then renamed over path, so a crash part way through a save leaves
+// either the old contents or the new ones, never a truncated file.
+// The new file keeps the mode and, where the platform allows it, the
+// owner of the file that it replaces.  Missing parent directories of
+// a new file are created.
+func WriteFile(path, text string) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		// Renaming over a symlink would replace the link instead of
+		// the file that it points to.
+		path = resolved
+	}
+	mode := defaultFileMode
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		mode = info.Mode().Perm()
+	case os.IsNotExist(err):
+		info = nil
+	default:
+		return err
+	}
+
+	dir, name := filepath.Split(path)
+	if info == nil && dir != "" {
+		if err := os.MkdirAll(dir, 0750|os.ModeDir); err != nil {
+			return err
+		}
+	}
+	tmp, err := ioutil.TempFile(dir, "."+name+".")
+	if err != nil {
+		return err
+	}
+	done := false
+	defer func() {
+		if !done {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := tmp.WriteString(text); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return err
+	}
+	if info != nil {
+		if err := chown(tmp, info); err != nil {
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	done = true
+	return nil
+}
@@ -0,0 +1,93 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package eol detects and converts the line endings of files.
+// Editors hold text with LF line endings, and files are converted to
+// and from their own line endings as they're read and written.
+package eol
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// binarySniffLen is the number of bytes at the start of a file that
+// are checked for NUL bytes, which text files don't have.
+const binarySniffLen = 8000
+
+// Ending is a line ending.
+type Ending string
+
+const (
+	LF   Ending = "\n"
+	CRLF Ending = "\r\n"
+)
+
+// String returns the name of e, as it's shown in the status bar.
+func (e Ending) String() string {
+	switch e {
+	case LF:
+		return "LF"
+	case CRLF:
+		return "CRLF"
+	}
+	return fmt.Sprintf("%q", string(e))
+}
+
+// Parse returns the Ending named name, which is "lf" or "crlf" in any
+// case.
+func Parse(name string) (Ending, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "lf", "unix":
+		return LF, nil
+	case "crlf", "windows", "dos":
+		return CRLF, nil
+	}
+	return "", fmt.Errorf("unknown line ending %q (expected lf or crlf)", name)
+}
+
+// Detect returns the line ending that most of the lines in text use,
+// and whether any of them use a different one.  Text with no line
+// endings, or with as many of one as the other, uses LF.
+func Detect(text string) (e Ending, mixed bool) {
+	lines := strings.Count(text, "\n")
+	crlf := strings.Count(text, "\r\n")
+	mixed = crlf > 0 && crlf < lines
+	if crlf*2 > lines {
+		return CRLF, mixed
+	}
+	return LF, mixed
+}
+
+// Normalize returns text with its CRLF line endings replaced by LF.
+// Carriage returns that aren't part of a line ending are kept.
+func Normalize(text string) string {
+	return strings.Replace(text, "\r\n", "\n", -1)
+}
+
+// Convert returns text with every line ending replaced by e.
+func Convert(text string, e Ending) string {
+	text = Normalize(text)
+	if e == LF || e == "" {
+		return text
+	}
+	return strings.Replace(text, "\n", string(e), -1)
+}
+
+// Binary returns whether or not b looks like the contents of a binary
+// file, which can't be edited as text without changing it.  Files
+// with NUL bytes or invalid UTF-8 are binary.
+func Binary(b []byte) bool {
+	sniff := b
+	if len(sniff) > binarySniffLen {
+		sniff = sniff[:binarySniffLen]
+	}
+	for _, c := range sniff {
+		if c == 0 {
+			return true
+		}
+	}
+	return !utf8.Valid(b)
+}
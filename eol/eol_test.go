@@ -0,0 +1,90 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package eol_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/eol"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal        = matchers.Equal
+	not          = matchers.Not
+	haveOccurred = matchers.HaveOccurred
+)
+
+func TestEOL(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it detects the dominant line ending", func(expect expect.Expectation) {
+		e, mixed := eol.Detect("foo\r\nbar\r\nbaz\n")
+		expect(e).To(equal(eol.CRLF))
+		expect(mixed).To(equal(true))
+
+		e, mixed = eol.Detect("foo\nbar\r\nbaz\n")
+		expect(e).To(equal(eol.LF))
+		expect(mixed).To(equal(true))
+
+		e, mixed = eol.Detect("foo\r\nbar\r\n")
+		expect(e).To(equal(eol.CRLF))
+		expect(mixed).To(equal(false))
+
+		e, mixed = eol.Detect("foo")
+		expect(e).To(equal(eol.LF))
+		expect(mixed).To(equal(false))
+	})
+
+	o.Spec("it uses LF for ties", func(expect expect.Expectation) {
+		e, mixed := eol.Detect("foo\r\nbar\n")
+		expect(e).To(equal(eol.LF))
+		expect(mixed).To(equal(true))
+	})
+
+	o.Spec("it converts line endings", func(expect expect.Expectation) {
+		expect(eol.Normalize("a\r\nb\rc\n")).To(equal("a\nb\rc\n"))
+		expect(eol.Convert("a\r\nb\n", eol.CRLF)).To(equal("a\r\nb\r\n"))
+		expect(eol.Convert("a\r\nb\n", eol.LF)).To(equal("a\nb\n"))
+	})
+
+	o.Spec("it round trips files with one line ending", func(expect expect.Expectation) {
+		const text = "package foo\r\n\r\nfunc Foo() {}\r\n"
+		e, _ := eol.Detect(text)
+		expect(eol.Convert(eol.Normalize(text), e)).To(equal(text))
+	})
+
+	o.Spec("it parses line ending names", func(expect expect.Expectation) {
+		e, err := eol.Parse("CRLF")
+		expect(err).To(not(haveOccurred()))
+		expect(e).To(equal(eol.CRLF))
+
+		e, err = eol.Parse(" lf ")
+		expect(err).To(not(haveOccurred()))
+		expect(e).To(equal(eol.LF))
+
+		_, err = eol.Parse("cr")
+		expect(err).To(haveOccurred())
+	})
+
+	o.Spec("it names line endings", func(expect expect.Expectation) {
+		expect(eol.LF.String()).To(equal("LF"))
+		expect(eol.CRLF.String()).To(equal("CRLF"))
+	})
+
+	o.Spec("it detects binary files", func(expect expect.Expectation) {
+		expect(eol.Binary([]byte("package foo\n"))).To(equal(false))
+		expect(eol.Binary([]byte("héllo\r\n"))).To(equal(false))
+		expect(eol.Binary([]byte("foo\x00bar"))).To(equal(true))
+		expect(eol.Binary([]byte{0xff, 0xfe, 'a'})).To(equal(true))
+	})
+}
@@ -0,0 +1,96 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fsw
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// mountsTTL is how long the mount table is cached for when checking
+// whether or not paths are remote.
+const mountsTTL = 5 * time.Second
+
+// A Backend creates Watchers for the paths that it supports.
+type Backend interface {
+	// Supports reports whether or not Watchers created by the
+	// Backend are able to watch path.
+	Supports(path string) bool
+
+	// New creates a new Watcher.
+	New() (Watcher, error)
+}
+
+var (
+	backendsMu sync.RWMutex
+	backends   = defaultBackends()
+)
+
+// Register adds b to the list of backends that Watchers returned by
+// New use.  Backends that are registered later take precedence, and
+// the default backends are always checked last.
+func Register(b Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends = append([]Backend{b}, backends...)
+}
+
+// New returns a Watcher which watches each path with the first
+// backend that supports it.
+func New() (Watcher, error) {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	return newMux(append([]Backend(nil), backends...)), nil
+}
+
+type pollBackend struct {
+	interval time.Duration
+}
+
+// PollBackend returns a Backend which polls for changes every
+// interval.  It supports all paths.
+func PollBackend(interval time.Duration) Backend {
+	return pollBackend{interval: interval}
+}
+
+func (b pollBackend) Supports(string) bool {
+	return true
+}
+
+func (b pollBackend) New() (Watcher, error) {
+	return NewPoller(b.interval), nil
+}
+
+type remoteBackend struct {
+	pollBackend
+
+	mu      sync.Mutex
+	mounts  []Mount
+	expires time.Time
+}
+
+// RemoteBackend returns a Backend which polls for changes every
+// interval, but only supports paths on network filesystems (e.g. NFS
+// or SSHFS).  Native watchers only see changes that are made by the
+// local machine on those filesystems.
+func RemoteBackend(interval time.Duration) Backend {
+	return &remoteBackend{pollBackend: pollBackend{interval: interval}}
+}
+
+func (b *remoteBackend) Supports(path string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if time.Now().After(b.expires) {
+		mounts, err := loadMounts()
+		if err != nil {
+			log.Printf("Warning: could not read mount table: %s", err)
+		}
+		b.mounts = mounts
+		b.expires = time.Now().Add(mountsTTL)
+	}
+	m, ok := MountFor(b.mounts, path)
+	return ok && m.Remote()
+}
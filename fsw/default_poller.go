@@ -0,0 +1,23 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// +build darwin windows
+
+package fsw
+
+import "time"
+
+const pollDuration = 200 * time.Millisecond
+
+// defaultBackends polls for everything, since native watchers use
+// up open files.
+func defaultBackends() []Backend {
+	return []Backend{PollBackend(pollDuration)}
+}
+
+// IsWatchLimit reports whether err was caused by the system running
+// out of watches.  Pollers have no such limit.
+func IsWatchLimit(err error) bool {
+	return false
+}
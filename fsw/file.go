@@ -0,0 +1,97 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fsw
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// File watches a single file by watching its directory, so that the
+// file can be followed when it's replaced or renamed.  Saving a file
+// atomically (writing a temporary file and renaming it over the
+// original) replaces the file that a watch was added for, so watching
+// the path itself would stop reporting changes after the first save.
+// The path isn't watched as well, since every change would then be
+// reported twice.
+//
+// File owns the watch that it adds, so only one goroutine should
+// call Next, and the watcher shouldn't be used for anything else
+// while the File is in use.
+type File struct {
+	w    Watcher
+	path string
+	dir  string
+
+	moved bool
+}
+
+// WatchFile starts watching the file at path using w.  The file's
+// directory must exist.
+func WatchFile(w Watcher, path string) (*File, error) {
+	f := &File{w: w, path: path, dir: filepath.Dir(path)}
+	if err := w.Add(f.dir); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Path returns the path of the file that f is watching.  It changes
+// when Next returns a Rename event.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Next waits for the next change to f's file.  The returned event's
+// Op is one of:
+//
+//   - Write, if the file's contents may have changed, including when
+//     it was replaced by another file.
+//   - Rename, if the file was moved to another name in the same
+//     directory.  The event's Path is the new name, which f will
+//     continue to watch.
+//   - Remove, if the file was removed.  f will report a Write if it is
+//     created again.
+func (f *File) Next() (Event, error) {
+	for {
+		ev, err := f.w.Next()
+		if err != nil {
+			return Event{}, err
+		}
+		if ev.Path != f.path {
+			if !f.moved || ev.Op&Create == 0 {
+				continue
+			}
+			f.path = ev.Path
+			f.moved = false
+			return Event{Path: f.path, Op: Rename}, nil
+		}
+		switch {
+		case ev.Op&Create != 0:
+			// The file was created again or replaced.
+			f.moved = false
+			return Event{Path: f.path, Op: Write}, nil
+		case ev.Op&Write != 0:
+			return Event{Path: f.path, Op: Write}, nil
+		case ev.Op&Rename != 0:
+			// If the file was renamed within the directory, a
+			// Create for its new name will follow.
+			f.moved = true
+		case ev.Op&Remove != 0:
+			if _, err := os.Stat(f.path); err == nil {
+				// The file was replaced before we heard about
+				// it being removed.
+				return Event{Path: f.path, Op: Write}, nil
+			}
+			return Event{Path: f.path, Op: Remove}, nil
+		}
+	}
+}
+
+// Close removes the watch that f added.  It doesn't close the
+// watcher that f uses.
+func (f *File) Close() error {
+	return f.w.Remove(f.dir)
+}
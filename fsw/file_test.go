@@ -0,0 +1,125 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fsw_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nelsam/vidar/fsw"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+)
+
+// settleDelay is how long to wait for duplicate events that a File
+// shouldn't report.
+const settleDelay = 100 * time.Millisecond
+
+// fileEvents records the events that a fsw.File reports.
+type fileEvents struct {
+	mu     sync.Mutex
+	events []fsw.Event
+}
+
+func (e *fileEvents) watch(f *fsw.File) {
+	for {
+		ev, err := f.Next()
+		if err != nil {
+			return
+		}
+		e.mu.Lock()
+		e.events = append(e.events, ev)
+		e.mu.Unlock()
+	}
+}
+
+// count returns the number of events with op that have been
+// reported.
+func (e *fileEvents) count(op fsw.Op) func() int {
+	return func() int {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		n := 0
+		for _, ev := range e.events {
+			if ev.Op == op {
+				n++
+			}
+		}
+		return n
+	}
+}
+
+func (e *fileEvents) last() fsw.Event {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.events) == 0 {
+		return fsw.Event{}
+	}
+	return e.events[len(e.events)-1]
+}
+
+type fileSetup struct {
+	dir    string
+	path   string
+	w      fsw.Watcher
+	events *fileEvents
+}
+
+func TestFile(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, fileSetup) {
+		expect := expect.New(t)
+		dir, err := ioutil.TempDir("", "fsw-file")
+		expect(err).To(not(haveOccurred()))
+		path := filepath.Join(dir, "foo.go")
+		expect(ioutil.WriteFile(path, []byte("package foo\n"), 0644)).To(not(haveOccurred()))
+
+		w, err := fsw.New()
+		expect(err).To(not(haveOccurred()))
+		f, err := fsw.WatchFile(w, path)
+		expect(err).To(not(haveOccurred()))
+		events := &fileEvents{}
+		go events.watch(f)
+		return expect, fileSetup{dir: dir, path: path, w: w, events: events}
+	})
+
+	o.AfterEach(func(_ expect.Expectation, s fileSetup) {
+		s.w.Close()
+		os.RemoveAll(s.dir)
+	})
+
+	o.Spec("it reports writes after the file is saved atomically", func(expect expect.Expectation, s fileSetup) {
+		tmp := filepath.Join(s.dir, ".foo.go.tmp")
+		expect(ioutil.WriteFile(tmp, []byte("package foo\n\nvar saved int\n"), 0644)).To(not(haveOccurred()))
+		expect(os.Rename(tmp, s.path)).To(not(haveOccurred()))
+		expect(s.events.count(fsw.Write)).To(viaPolling(not(equal(0))))
+
+		saves := s.events.count(fsw.Write)()
+		expect(ioutil.WriteFile(s.path, []byte("package foo\n\nvar external int\n"), 0644)).To(not(haveOccurred()))
+		expect(func() bool { return s.events.count(fsw.Write)() > saves }).To(viaPolling(beTrue()))
+	})
+
+	o.Spec("it follows the file when it is renamed", func(expect expect.Expectation, s fileSetup) {
+		newPath := filepath.Join(s.dir, "bar.go")
+		expect(os.Rename(s.path, newPath)).To(not(haveOccurred()))
+		expect(s.events.count(fsw.Rename)).To(viaPolling(equal(1)))
+		time.Sleep(settleDelay)
+		expect(s.events.count(fsw.Rename)()).To(equal(1))
+		expect(s.events.last()).To(equal(fsw.Event{Path: newPath, Op: fsw.Rename}))
+	})
+
+	o.Spec("it reports when the file is removed", func(expect expect.Expectation, s fileSetup) {
+		expect(os.Remove(s.path)).To(not(haveOccurred()))
+		expect(s.events.count(fsw.Remove)).To(viaPolling(equal(1)))
+		time.Sleep(settleDelay)
+		expect(s.events.count(fsw.Remove)()).To(equal(1))
+	})
+}
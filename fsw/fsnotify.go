@@ -7,13 +7,42 @@
 package fsw
 
 import (
+	"errors"
 	"io"
 	"log"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 )
 
+// remotePollInterval is the interval that the default backend for
+// network filesystems polls at.
+const remotePollInterval = time.Second
+
+// defaultBackends uses fsnotify for everything except network
+// filesystems, which are polled.
+func defaultBackends() []Backend {
+	return []Backend{RemoteBackend(remotePollInterval), NativeBackend()}
+}
+
+type nativeBackend struct{}
+
+// NativeBackend returns a Backend which uses the operating system's
+// filesystem notifications.  It supports all paths.
+func NativeBackend() Backend {
+	return nativeBackend{}
+}
+
+func (nativeBackend) Supports(string) bool {
+	return true
+}
+
+func (nativeBackend) New() (Watcher, error) {
+	return newNative()
+}
+
 type watcher struct {
 	*fsnotify.Watcher
 
@@ -21,7 +50,7 @@ type watcher struct {
 	mu       sync.Mutex
 }
 
-func New() (Watcher, error) {
+func newNative() (Watcher, error) {
 	fsw, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err
@@ -29,6 +58,12 @@ func New() (Watcher, error) {
 	return &watcher{Watcher: fsw, tracking: make(map[string]struct{})}, nil
 }
 
+// IsWatchLimit reports whether err was caused by the system running
+// out of watches (or open files, which some systems use for watches).
+func IsWatchLimit(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EMFILE)
+}
+
 func (w *watcher) Add(path string) error {
 	if err := w.Watcher.Add(path); err != nil {
 		return err
@@ -0,0 +1,123 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fsw
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// mountsPath is the mount table on linux.  Other systems don't have
+// one, so no paths will be detected as remote on them.
+const mountsPath = "/proc/self/mounts"
+
+// remoteTypes are the filesystem types that are on other machines.
+// Native watchers only see changes made by the local machine on
+// these filesystems.
+var remoteTypes = map[string]bool{
+	"nfs":         true,
+	"nfs4":        true,
+	"cifs":        true,
+	"smbfs":       true,
+	"smb3":        true,
+	"sshfs":       true,
+	"fuse.sshfs":  true,
+	"9p":          true,
+	"afs":         true,
+	"ceph":        true,
+	"glusterfs":   true,
+	"davfs":       true,
+	"fuse.rclone": true,
+}
+
+// Mount is an entry in the system's mount table.
+type Mount struct {
+	Dir  string
+	Type string
+}
+
+// Remote reports whether or not m is a network filesystem.
+func (m Mount) Remote() bool {
+	return remoteTypes[m.Type]
+}
+
+// ParseMounts parses a mount table in the format of /proc/mounts.
+func ParseMounts(r io.Reader) ([]Mount, error) {
+	var mounts []Mount
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		mounts = append(mounts, Mount{Dir: unescape(fields[1]), Type: fields[2]})
+	}
+	return mounts, s.Err()
+}
+
+// MountFor returns the mount in mounts that path is on.
+func MountFor(mounts []Mount, path string) (Mount, bool) {
+	var (
+		best  Mount
+		found bool
+	)
+	path = filepath.Clean(path)
+	for _, m := range mounts {
+		if !within(m.Dir, path) {
+			continue
+		}
+		if !found || len(m.Dir) >= len(best.Dir) {
+			// Later mounts on the same directory hide earlier
+			// ones, hence the >=.
+			best, found = m, true
+		}
+	}
+	return best, found
+}
+
+func within(dir, path string) bool {
+	if dir == path || dir == string(filepath.Separator) {
+		return true
+	}
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// unescape replaces the octal escapes (e.g. \040 for spaces) that
+// the mount table uses in paths.
+func unescape(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+4 <= len(field) {
+			if v, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(field[i])
+	}
+	return b.String()
+}
+
+// loadMounts reads the system's mount table.  A missing mount table
+// is not an error.
+func loadMounts() ([]Mount, error) {
+	f, err := os.Open(mountsPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseMounts(f)
+}
@@ -0,0 +1,70 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fsw_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/fsw"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	not          = matchers.Not
+	equal        = matchers.Equal
+	beTrue       = matchers.BeTrue
+	haveLen      = matchers.HaveLen
+	haveOccurred = matchers.HaveOccurred
+)
+
+const mountTable = `sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sda1 / ext4 rw,relatime 0 0
+server:/export/home /home nfs4 rw,relatime 0 0
+/dev/sdb1 /home/local ext4 rw,relatime 0 0
+user@host:/src /mnt/remote\040src fuse.sshfs rw,nosuid,nodev 0 0
+`
+
+func TestMounts(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, []fsw.Mount) {
+		expect := expect.New(t)
+		mounts, err := fsw.ParseMounts(strings.NewReader(mountTable))
+		expect(err).To(not(haveOccurred()))
+		return expect, mounts
+	})
+
+	o.Spec("it parses every mount", func(expect expect.Expectation, mounts []fsw.Mount) {
+		expect(mounts).To(haveLen(5))
+		expect(mounts[2]).To(equal(fsw.Mount{Dir: "/home", Type: "nfs4"}))
+	})
+
+	o.Spec("it unescapes spaces in paths", func(expect expect.Expectation, mounts []fsw.Mount) {
+		expect(mounts[4].Dir).To(equal("/mnt/remote src"))
+	})
+
+	o.Spec("it finds the closest mount for a path", func(expect expect.Expectation, mounts []fsw.Mount) {
+		m, ok := fsw.MountFor(mounts, "/home/me/project/main.go")
+		expect(ok).To(beTrue())
+		expect(m.Remote()).To(beTrue())
+
+		m, ok = fsw.MountFor(mounts, "/home/local/project")
+		expect(ok).To(beTrue())
+		expect(m.Remote()).To(not(beTrue()))
+
+		m, ok = fsw.MountFor(mounts, "/mnt/remote src/foo")
+		expect(ok).To(beTrue())
+		expect(m.Type).To(equal("fuse.sshfs"))
+	})
+
+	o.Spec("it doesn't match directories by name prefix", func(expect expect.Expectation, mounts []fsw.Mount) {
+		m, _ := fsw.MountFor(mounts, "/homework")
+		expect(m.Dir).To(equal("/"))
+	})
+}
@@ -0,0 +1,155 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fsw
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// mux is a Watcher which sends each path to the first of its
+// backends that supports it.  Watchers are only created for backends
+// that are needed, and events from all of them are merged.
+type mux struct {
+	backends []Backend
+
+	mu       sync.Mutex
+	watchers []Watcher
+	paths    map[string]Watcher
+	closed   bool
+
+	events chan Event
+	errs   chan error
+	done   chan struct{}
+}
+
+func newMux(backends []Backend) *mux {
+	return &mux{
+		backends: backends,
+		watchers: make([]Watcher, len(backends)),
+		paths:    make(map[string]Watcher),
+		events:   make(chan Event),
+		errs:     make(chan error),
+		done:     make(chan struct{}),
+	}
+}
+
+// watcher returns the watcher that should be used for path, creating
+// it if needed.  m.mu must be held while calling watcher.
+func (m *mux) watcher(path string) (Watcher, error) {
+	for i, b := range m.backends {
+		if !b.Supports(path) {
+			continue
+		}
+		if m.watchers[i] == nil {
+			w, err := b.New()
+			if err != nil {
+				return nil, err
+			}
+			m.watchers[i] = w
+			go m.forward(w)
+		}
+		return m.watchers[i], nil
+	}
+	return nil, errors.New("no filesystem watcher supports " + path)
+}
+
+func (m *mux) forward(w Watcher) {
+	for {
+		e, err := w.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			select {
+			case m.errs <- err:
+			case <-m.done:
+				return
+			}
+			continue
+		}
+		select {
+		case m.events <- e:
+		case <-m.done:
+			return
+		}
+	}
+}
+
+func (m *mux) Add(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return errors.New("Add called on closed watcher")
+	}
+	w, err := m.watcher(path)
+	if err != nil {
+		return err
+	}
+	if err := w.Add(path); err != nil {
+		return err
+	}
+	m.paths[path] = w
+	return nil
+}
+
+func (m *mux) Remove(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.paths[path]
+	if !ok {
+		return nil
+	}
+	delete(m.paths, path)
+	return w.Remove(path)
+}
+
+func (m *mux) RemoveAll() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var firstErr error
+	for _, w := range m.watchers {
+		if w == nil {
+			continue
+		}
+		if err := w.RemoveAll(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	m.paths = make(map[string]Watcher)
+	return firstErr
+}
+
+func (m *mux) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	close(m.done)
+	var firstErr error
+	for _, w := range m.watchers {
+		if w == nil {
+			continue
+		}
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m *mux) Next() (Event, error) {
+	select {
+	case e := <-m.events:
+		return e, nil
+	case err := <-m.errs:
+		return Event{}, err
+	case <-m.done:
+		return Event{}, io.EOF
+	}
+}
@@ -2,8 +2,6 @@
 // domain.  For more information, see <http://unlicense.org> or the
 // accompanying UNLICENSE file.
 
-// +build darwin windows
-
 package fsw
 
 import (
@@ -16,8 +14,6 @@ import (
 	"time"
 )
 
-const pollDuration = 200 * time.Millisecond
-
 type poll map[string]os.FileInfo
 
 func (p poll) addChildren(name string, closed *uint32) error {
@@ -47,15 +43,19 @@ type poller struct {
 	errs   chan error
 }
 
-func New() (Watcher, error) {
+// NewPoller returns a Watcher which polls the filesystem for
+// changes every interval.  It is used by default on systems where
+// native watchers are too limited, but may also be used as a
+// fallback when native watchers fail.
+func NewPoller(interval time.Duration) Watcher {
 	p := &poller{
-		ticker: time.NewTicker(pollDuration),
+		ticker: time.NewTicker(interval),
 		last:   make(map[string]poll),
 		events: make(chan Event),
 		errs:   make(chan error),
 	}
 	go p.run()
-	return p, nil
+	return p
 }
 
 func (p *poller) run() {
@@ -0,0 +1,127 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fsw
+
+import (
+	"sync"
+	"time"
+)
+
+// maxDelays is how many delays a Queue will put off processing for
+// while events keep arriving.
+const maxDelays = 10
+
+// Queue coalesces bursts of events, such as the ones from a git
+// checkout, and processes each path that changed once the burst is
+// over.  Paths are processed one at a time, in the order that their
+// first event arrived.  A path that changes while it's being
+// processed is queued again, so the latest state of every path is
+// always processed.
+type Queue struct {
+	delay   time.Duration
+	process func(path string)
+
+	mu      sync.Mutex
+	pending []string
+	queued  map[string]struct{}
+	first   time.Time
+	timer   *time.Timer
+	timers  int
+
+	ready chan struct{}
+	done  chan struct{}
+}
+
+// NewQueue returns a Queue which calls process for each pushed path
+// once no events have been pushed for delay.  If events keep arriving,
+// processing is only put off for a limited time, so that long bursts
+// still show up as they happen.
+func NewQueue(delay time.Duration, process func(path string)) *Queue {
+	q := &Queue{
+		delay:   delay,
+		process: process,
+		queued:  make(map[string]struct{}),
+		ready:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Push queues path to be processed.  Pushing a path that is already
+// queued only delays processing.
+func (q *Queue) Push(path string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.queued[path]; !ok {
+		q.queued[path] = struct{}{}
+		q.pending = append(q.pending, path)
+	}
+	now := time.Now()
+	if q.timer == nil {
+		q.first = now
+		q.timers++
+		id := q.timers
+		q.timer = time.AfterFunc(q.delay, func() { q.flush(id) })
+		return
+	}
+	wait := q.delay
+	if left := q.first.Add(maxDelays * q.delay).Sub(now); left < wait {
+		wait = left
+	}
+	q.timer.Reset(wait)
+}
+
+// Close stops q.  Paths that haven't been processed yet are dropped.
+func (q *Queue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+	select {
+	case <-q.done:
+	default:
+		close(q.done)
+	}
+}
+
+// flush is called when the timer with the given id fires, to let q's
+// run goroutine know that the pending paths are ready.
+func (q *Queue) flush(id int) {
+	q.mu.Lock()
+	if q.timers == id {
+		q.timer = nil
+	}
+	q.mu.Unlock()
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) run() {
+	for {
+		select {
+		case <-q.done:
+			return
+		case <-q.ready:
+		}
+		q.mu.Lock()
+		paths := q.pending
+		q.pending = nil
+		q.queued = make(map[string]struct{})
+		q.mu.Unlock()
+		for _, path := range paths {
+			select {
+			case <-q.done:
+				return
+			default:
+			}
+			q.process(path)
+		}
+	}
+}
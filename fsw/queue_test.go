@@ -0,0 +1,136 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package fsw_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nelsam/vidar/fsw"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var viaPolling = matchers.ViaPolling
+
+const queueDelay = 10 * time.Millisecond
+
+// processed records the paths that a fsw.Queue processes.
+type processed struct {
+	mu    sync.Mutex
+	paths []string
+	block chan struct{}
+}
+
+func (p *processed) process(path string) {
+	if p.block != nil {
+		<-p.block
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paths = append(p.paths, path)
+}
+
+func (p *processed) get() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.paths...)
+}
+
+func (p *processed) count() int {
+	return len(p.get())
+}
+
+func TestQueue(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *processed, *fsw.Queue) {
+		p := &processed{}
+		q := fsw.NewQueue(queueDelay, p.process)
+		return expect.New(t), p, q
+	})
+
+	o.AfterEach(func(_ expect.Expectation, _ *processed, q *fsw.Queue) {
+		q.Close()
+	})
+
+	o.Spec("it coalesces a burst of events for one path", func(expect expect.Expectation, p *processed, q *fsw.Queue) {
+		for i := 0; i < 100; i++ {
+			q.Push("/foo")
+		}
+		expect(p.count).To(viaPolling(equal(1)))
+		time.Sleep(5 * queueDelay)
+		expect(p.get()).To(equal([]string{"/foo"}))
+	})
+
+	o.Spec("it processes paths in the order that they first changed", func(expect expect.Expectation, p *processed, q *fsw.Queue) {
+		for _, path := range []string{"/a", "/b", "/a", "/c", "/b"} {
+			q.Push(path)
+		}
+		expect(p.count).To(viaPolling(equal(3)))
+		expect(p.get()).To(equal([]string{"/a", "/b", "/c"}))
+	})
+
+	o.Spec("it processes paths again when they change during processing", func(expect expect.Expectation, p *processed, q *fsw.Queue) {
+		p.block = make(chan struct{})
+		q.Push("/foo")
+
+		// Wait for the first event to start processing, then
+		// change the path again before it finishes.
+		time.Sleep(5 * queueDelay)
+		q.Push("/foo")
+		close(p.block)
+
+		expect(p.count).To(viaPolling(equal(2)))
+		expect(p.get()).To(equal([]string{"/foo", "/foo"}))
+	})
+
+	o.Spec("it processes every path from a storm of events", func(expect expect.Expectation, p *processed, q *fsw.Queue) {
+		const paths = 50
+		var wg sync.WaitGroup
+		for g := 0; g < 20; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 500; i++ {
+					q.Push(fmt.Sprintf("/dir/%d", i%paths))
+				}
+			}()
+		}
+		wg.Wait()
+
+		expect(func() []string {
+			seen := make(map[string]bool)
+			for _, path := range p.get() {
+				seen[path] = true
+			}
+			var missing []string
+			for i := 0; i < paths; i++ {
+				if path := fmt.Sprintf("/dir/%d", i); !seen[path] {
+					missing = append(missing, path)
+				}
+			}
+			return missing
+		}).To(viaPolling(haveLen(0)))
+	})
+
+	o.Spec("it doesn't put off processing forever during a long storm", func(expect expect.Expectation, p *processed, q *fsw.Queue) {
+		stop := time.After(30 * queueDelay)
+		for storming := true; storming; {
+			select {
+			case <-stop:
+				storming = false
+			default:
+				q.Push("/foo")
+				time.Sleep(queueDelay / 5)
+			}
+		}
+		expect(p.count() > 0).To(beTrue())
+	})
+}
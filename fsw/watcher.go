@@ -7,6 +7,12 @@
 // For example, fsnotify is used on most systems; but on darwin,
 // where the open file limits are low and watchers use up open
 // files, we instead use a polling watcher.
+//
+// Watchers returned by New pick a Backend for each path that they
+// watch, so that paths on network filesystems (where native watchers
+// miss changes made by other machines) can be polled while local
+// paths use native watchers.  Additional backends may be added with
+// Register.
 package fsw
 
 type Op uint32
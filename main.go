@@ -10,20 +10,16 @@ import (
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/drivers/gl"
-	"github.com/nelsam/gxui/math"
-	"github.com/nelsam/gxui/themes/basic"
-	"github.com/nelsam/gxui/themes/dark"
 	"github.com/nelsam/vidar/command"
 	"github.com/nelsam/vidar/command/focus"
-	"github.com/nelsam/vidar/command/input"
+	"github.com/nelsam/vidar/command/project"
+	"github.com/nelsam/vidar/command/recovery"
 	"github.com/nelsam/vidar/commander"
 	"github.com/nelsam/vidar/commander/bind"
-	"github.com/nelsam/vidar/controller"
 	"github.com/nelsam/vidar/editor"
-	"github.com/nelsam/vidar/navigator"
+	"github.com/nelsam/vidar/fsw"
 	"github.com/nelsam/vidar/plugin"
 	"github.com/nelsam/vidar/setting"
-	"github.com/nelsam/vidar/theme"
 	"github.com/spf13/cobra"
 )
 
@@ -40,84 +36,74 @@ func init() {
 		Short: "An experimental Go editor",
 		Long: "An editor for Go code, still in its infancy.  " +
 			"Basic editing of Go code is mostly complete, but " +
-			"panics still happen and can result in the loss of " +
-			"unsaved work.",
+			"panics still happen.  Unsaved work is kept in " +
+			setting.RecoveryDir + " and will be offered for " +
+			"recovery on the next startup.",
 		Run: func(cmd *cobra.Command, args []string) {
 			files = args
 			gl.StartDriver(uiMain, gl.Debug())
 		},
 	}
+	// The setting package has already read this flag from os.Args by
+	// the time cobra parses it.
+	cmd.Flags().String(setting.ConfigDirFlag, "", "keep config files and data in this directory (e.g. for a portable install)")
 }
 
 func main() {
+	defer recovery.FlushOnPanic()
+	fsw.Register(fsw.RemoteBackend(setting.PollInterval()))
 	cmd.Execute()
 }
 
 func uiMain(driver gxui.Driver) {
-	gTheme := dark.CreateTheme(driver).(*basic.Theme)
-	font := setting.PrefFont(driver)
-	if font == nil {
-		font = gTheme.DefaultMonospaceFont()
+	defer recovery.FlushOnPanic()
+	a := &app{driver: driver}
+	w := a.openWindow(true)
+	if err := setting.WatchConfig(); err != nil {
+		log.Printf("Error watching config files: %s", err)
 	}
-	gTheme.SetDefaultMonospaceFont(font)
-	gTheme.SetDefaultFont(font)
-	gTheme.WindowBackground = background
 
-	// TODO: figure out a better way to get this resolution
-	window := newWindow(gTheme)
-	controller := controller.New(driver, gTheme)
-
-	// Bindings should be added immediately after creating the commander,
-	// since other types rely on the bindings having been bound.
-	cmdr := commander.New(driver, gTheme, window, controller)
-	window.child = cmdr
-	bindings := []bind.Bindable{input.New(driver, cmdr)}
-	bindings = append(bindings, command.Bindables(cmdr, driver, gTheme)...)
-	bindings = append(bindings, plugin.Bindables(cmdr, driver, gTheme)...)
-	cmdr.Push(bindings...)
-
-	nav := navigator.New(driver, gTheme)
-	controller.SetNavigator(nav)
-
-	editor := editor.New(driver, window, cmdr, gTheme, theme.Default, gTheme.DefaultMonospaceFont())
-	controller.SetEditor(editor)
-
-	projTree := navigator.NewProjectTree(cmdr, driver, window, gTheme)
-	projects := navigator.NewProjectsPane(cmdr, driver, gTheme, projTree.Frame())
-
-	nav.Add(projects)
-	nav.Add(projTree)
-
-	nav.Resize(window.Size().H)
-	window.OnResize(func() {
-		nav.Resize(window.Size().H)
-	})
-
-	// TODO: Check the system's DPI settings for this value
-	window.SetScale(1)
-
-	window.AddChild(cmdr)
-
-	window.OnKeyDown(func(event gxui.KeyboardEvent) {
-		if window.Focus() == nil {
-			cmdr.KeyDown(event)
-		}
-	})
-	window.OnKeyUp(func(event gxui.KeyboardEvent) {
-		if window.Focus() == nil {
-			cmdr.KeyPress(event)
-		}
-	})
-
-	opener := cmdr.Bindable("focus-location").(*focus.Location)
+	opener := w.cmdr.Bindable("focus-location").(*focus.Location)
+	if len(files) == 0 {
+		restoreSession(w.cmdr, w.projects, opener)
+	}
 	for _, file := range files {
 		filepath, err := filepath.Abs(file)
 		if err != nil {
 			log.Printf("Failed to get path: %s", err)
 		}
-		cmdr.Execute(opener.For(focus.Path(filepath)))
+		w.cmdr.Execute(opener.For(focus.Path(filepath)))
+	}
+
+	restore, canRestore := w.cmdr.Bindable(recovery.RestoreName).(bind.Command)
+	if canRestore && len(recovery.Snapshots(setting.RecoveryDir)) > 0 {
+		w.cmdr.Run(restore)
+	} else if errs, ok := w.cmdr.Bindable(plugin.ErrorsName).(bind.Command); ok {
+		w.cmdr.Run(errs)
+	} else if len(w.cmdr.BindingConflicts()) > 0 {
+		if conflicts, ok := w.cmdr.Bindable(command.BindingConflictsName).(bind.Command); ok {
+			w.cmdr.Run(conflicts)
+		}
 	}
+}
 
-	window.OnClose(driver.Terminate)
-	window.SetPadding(math.Spacing{L: 10, T: 10, R: 10, B: 10})
+// restoreSession focuses the files that were restored from the last
+// session and switches back to the project that was active when the
+// session was saved.
+func restoreSession(cmdr *commander.Commander, e *editor.MultiProjectEditor, opener *focus.Location) {
+	if file := e.CurrentFile(); file != "" {
+		cmdr.Execute(opener.For(focus.Path(file)))
+	}
+	last := setting.LastProject()
+	if last == "" || last == e.CurrentProject().Name {
+		return
+	}
+	for _, p := range setting.Projects() {
+		if p.Name != last {
+			continue
+		}
+		changer := cmdr.Bindable("project-change").(*project.Open)
+		cmdr.Execute(changer.For(project.Project(p)))
+		return
+	}
 }
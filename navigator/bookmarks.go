@@ -0,0 +1,139 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigator
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Bookmarks is a navigator pane that lists the bookmarks in the
+// current project.  Clicking a bookmark opens its file at the
+// bookmarked line.
+type Bookmarks struct {
+	button gxui.Button
+
+	cmdr   Commander
+	driver gxui.Driver
+	theme  *basic.Theme
+
+	layout *searchLayout
+	status gxui.Label
+	list   gxui.LinearLayout
+
+	project setting.Project
+}
+
+// NewBookmarks creates a bookmarks pane that lists the bookmarks in
+// the default project until a project is opened.
+func NewBookmarks(cmdr Commander, driver gxui.Driver, theme *basic.Theme) *Bookmarks {
+	b := &Bookmarks{
+		cmdr:    cmdr,
+		driver:  driver,
+		theme:   theme,
+		button:  createIconButton(driver, theme, "bookmark.png"),
+		layout:  newSearchLayout(theme),
+		status:  theme.CreateLabel(),
+		list:    theme.CreateLinearLayout(),
+		project: setting.DefaultProject,
+	}
+	b.layout.SetDirection(gxui.TopToBottom)
+	b.layout.AddChild(b.status)
+
+	b.list.SetDirection(gxui.TopToBottom)
+	scrollable := theme.CreateScrollLayout()
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(b.list)
+	b.layout.AddChild(scrollable)
+	return b
+}
+
+func (b *Bookmarks) Button() gxui.Button {
+	return b.button
+}
+
+// Frame refreshes the list of bookmarks and returns the frame that
+// displays them.
+func (b *Bookmarks) Frame() gxui.Control {
+	b.RefreshBookmarks()
+	return b.layout
+}
+
+// SetProject sets the project to list bookmarks for.
+func (b *Bookmarks) SetProject(project setting.Project) {
+	b.driver.Call(func() {
+		b.project = project
+		b.RefreshBookmarks()
+	})
+}
+
+// RefreshBookmarks reloads the list of bookmarks from the projects
+// file.  It must be called on the UI goroutine.
+func (b *Bookmarks) RefreshBookmarks() {
+	b.list.RemoveAll()
+	marks := setting.Bookmarks(b.project.Name)
+	if len(marks) == 0 {
+		b.status.SetText("No bookmarks")
+		return
+	}
+	b.status.SetText(fmt.Sprintf("%d bookmarks", len(marks)))
+	for len(marks) > 0 {
+		path := marks[0].Path
+		var lines []int
+		for len(marks) > 0 && marks[0].Path == path {
+			lines = append(lines, marks[0].Line)
+			marks = marks[1:]
+		}
+		b.addFile(path, lines)
+	}
+}
+
+func (b *Bookmarks) addFile(path string, lines []int) {
+	name, err := filepath.Rel(b.project.Path, path)
+	if err != nil {
+		name = path
+	}
+	file := newGenericNode(b.driver, b.theme, name, fileColor)
+	texts := readLines(path, lines)
+	for _, l := range lines {
+		file.AddChild(newSearchResult(b.cmdr, b.driver, b.theme, path, match{line: l, text: texts[l]}))
+	}
+	b.list.AddChild(file)
+	file.button.Click(gxui.MouseEvent{})
+}
+
+// readLines returns the text of each line in lines from the file at
+// path.  Lines that can't be read are left out.
+func readLines(path string, lines []int) map[int]string {
+	texts := make(map[int]string, len(lines))
+	f, err := os.Open(path)
+	if err != nil {
+		return texts
+	}
+	defer f.Close()
+
+	want := make(map[int]bool, len(lines))
+	last := 0
+	for _, l := range lines {
+		want[l] = true
+		if l > last {
+			last = l
+		}
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, maxSearchFileSize)
+	for line := 0; line <= last && scanner.Scan(); line++ {
+		if want[line] {
+			texts[line] = scanner.Text()
+		}
+	}
+	return texts
+}
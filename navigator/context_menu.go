@@ -0,0 +1,104 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigator
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/mixins/parts"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+)
+
+// contextItems are the labels and command names of the items in the
+// project tree's context menu.
+var contextItems = []struct {
+	label, command string
+}{
+	{"New File", "new-file"},
+	{"New Directory", "new-directory"},
+	{"Rename", "rename-file"},
+	{"Duplicate", "duplicate-file"},
+	{"Delete", "delete-file"},
+	{"Go Generate", "go-generate"},
+}
+
+// A PathSetter is a command which can be told which file or directory
+// to act on, rather than acting on the current file.
+type PathSetter interface {
+	SetPath(path string)
+}
+
+// contextMenu is the menu that is shown when a directory or file in
+// the project tree is right-clicked.  Each item runs a command with
+// the clicked path, so the same operations can be bound to keys.
+type contextMenu struct {
+	parts.Focusable
+	mixins.LinearLayout
+
+	cmdr    Commander
+	window  gxui.Window
+	overlay gxui.BubbleOverlay
+	added   bool
+
+	path string
+}
+
+func newContextMenu(cmdr Commander, window gxui.Window, theme *basic.Theme) *contextMenu {
+	m := &contextMenu{
+		cmdr:    cmdr,
+		window:  window,
+		overlay: theme.CreateBubbleOverlay(),
+	}
+	m.Focusable.Init(m)
+	m.LinearLayout.Init(m, theme)
+	m.SetDirection(gxui.TopToBottom)
+	m.SetBackgroundBrush(theme.ButtonDefaultStyle.Brush)
+	m.SetBorderPen(theme.ButtonDefaultStyle.Pen)
+	for _, item := range contextItems {
+		name := item.command
+		b := theme.CreateButton()
+		b.SetText(item.label)
+		b.SetMargin(math.Spacing{L: 2, R: 2})
+		b.OnClick(func(gxui.MouseEvent) {
+			m.hide()
+			m.run(name)
+		})
+		m.AddChild(b)
+	}
+	m.OnLostFocus(m.hide)
+	return m
+}
+
+// show displays m at point, which is in window coordinates, for the
+// file or directory at path.
+func (m *contextMenu) show(path string, point math.Point) {
+	if !m.added {
+		// The overlay is added the first time that it's needed,
+		// so that it's drawn on top of everything that was added
+		// to the window when vidar started.
+		m.window.AddChild(m.overlay)
+		m.added = true
+	}
+	m.path = path
+	m.overlay.Show(m, point)
+	gxui.SetFocus(m)
+}
+
+func (m *contextMenu) hide() {
+	m.overlay.Hide()
+}
+
+func (m *contextMenu) run(name string) {
+	cmd, ok := m.cmdr.Bindable(name).(bind.Command)
+	if !ok {
+		return
+	}
+	if setter, ok := cmd.(PathSetter); ok {
+		setter.SetPath(m.path)
+	}
+	m.cmdr.Run(cmd)
+}
@@ -0,0 +1,24 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigator
+
+// DependencyTOC is a hook which shows the table of contents for go
+// files that are opened from outside of the project, e.g. when
+// goto-definition jumps into GOROOT or the module cache.
+type DependencyTOC struct {
+	Tree *ProjectTree
+}
+
+func (d DependencyTOC) Name() string {
+	return "dependency-toc"
+}
+
+func (d DependencyTOC) OpName() string {
+	return "focus-location"
+}
+
+func (d DependencyTOC) FileChanged(_, newPath string) {
+	d.Tree.showDependencyTOC(newPath)
+}
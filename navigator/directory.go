@@ -5,7 +5,6 @@
 package navigator
 
 import (
-	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
@@ -36,6 +35,12 @@ type directory struct {
 	button  *treeButton
 	tree    *dirTree
 	watcher Watcher
+
+	// loaded and expandTo are only accessed on the UI goroutine.
+	// expandTo is a directory that should be expanded once this
+	// directory's children have been read.
+	loaded   bool
+	expandTo string
 }
 
 func newDirectory(projTree *ProjectTree, path string, watcher Watcher) *directory {
@@ -53,37 +58,48 @@ func newDirectory(projTree *ProjectTree, path string, watcher Watcher) *director
 	}
 	d.Init(d, theme)
 	d.AddChild(button)
-	button.OnClick(func(gxui.MouseEvent) {
-		if projTree.tocCtl != nil {
-			projTree.layout.RemoveChild(projTree.tocCtl)
+	button.OnClick(func(ev gxui.MouseEvent) {
+		if ev.Button == gxui.MouseButtonRight {
+			projTree.menu.show(path, ev.WindowPoint)
+			return
 		}
-		toc := NewTOC(projTree.cmdr, projTree.driver, projTree.theme, path)
-		projTree.SetTOC(toc)
-		scrollable := theme.CreateScrollLayout()
-		// Disable horiz scrolling until we can figure out an accurate
-		// way to calculate our width.
-		scrollable.SetScrollAxis(false, true)
-		scrollable.SetChild(toc)
-		projTree.tocCtl = scrollable
-		projTree.layout.AddChild(projTree.tocCtl)
-		projTree.layout.SetChildWeight(projTree.tocCtl, 2)
-		if d.Length() == 0 {
+		projTree.showTOC(path).menu = projTree.menu
+		if !d.loaded {
+			// Expand once the directory has been read.
+			d.expandTo = path
 			return
 		}
 		if d.tree.Attached() {
-			d.tree.Unload(watcher)
-			d.button.Collapse()
-			d.RemoveChild(d.tree)
+			d.hideChildren()
 			return
 		}
-		d.tree.Load(watcher)
-		d.button.Expand()
-		d.AddChild(d.tree)
+		d.showChildren()
 	})
 	d.reload()
 	return d
 }
 
+// showChildren expands d, reading its children's children.  Nothing
+// happens until d itself has been read.
+func (d *directory) showChildren() {
+	if !d.loaded || d.Length() == 0 || d.tree.Attached() {
+		return
+	}
+	d.tree.Load(d.watcher, d.expandChildren)
+	d.button.Expand()
+	d.AddChild(d.tree)
+}
+
+// hideChildren collapses d.
+func (d *directory) hideChildren() {
+	if !d.tree.Attached() {
+		return
+	}
+	d.tree.Unload(d.watcher)
+	d.button.Collapse()
+	d.RemoveChild(d.tree)
+}
+
 func (d *directory) update(path string) {
 	if !strings.HasPrefix(path, d.tree.path) {
 		return
@@ -97,13 +113,51 @@ func (d *directory) update(path string) {
 	}
 }
 
+// refresh reads the directory at dir again, if it's d or one of its
+// loaded children.
+func (d *directory) refresh(dir string) {
+	if d.tree.path == dir {
+		d.reload()
+		return
+	}
+	if !strings.HasPrefix(dir, d.tree.path) {
+		return
+	}
+	for _, child := range d.tree.Dirs() {
+		child.refresh(dir)
+	}
+}
+
+// ExpandTo expands d and its children down to dir.  Directories that
+// haven't been read yet will be expanded once they have been.
 func (d *directory) ExpandTo(dir string) {
 	if !strings.HasPrefix(dir, d.tree.path) {
 		return
 	}
+	d.expandTo = dir
+	if !d.loaded {
+		return
+	}
+	if d.Length() == 0 {
+		d.expandTo = ""
+		return
+	}
 	if !d.button.Expanded() {
+		// The children will be expanded once they're loaded.
 		d.button.Click(gxui.MouseEvent{})
+		return
+	}
+	d.expandChildren()
+}
+
+// expandChildren continues expanding towards d.expandTo, if it is
+// set.
+func (d *directory) expandChildren() {
+	dir := d.expandTo
+	if dir == "" {
+		return
 	}
+	d.expandTo = ""
 	for _, child := range d.tree.Dirs() {
 		child.ExpandTo(dir)
 	}
@@ -121,29 +175,44 @@ func (d *directory) updateExpandable(children int64) {
 	d.button.SetExpandable(true)
 }
 
+// reload reads d's directory in the background, updating d on the UI
+// goroutine once it has been read.
 func (d *directory) reload() {
-	finfos, err := ioutil.ReadDir(d.tree.path)
-	if err != nil {
-		log.Printf("Unexpected error reading directory %s: %s", d.tree.path, err)
-		return
-	}
-	defer d.driver.Call(func() {
-		d.Relayout()
-		d.Redraw()
-	})
+	progress := d.tree.projTree.progress
+	progress.Start()
+	go func() {
+		defer progress.Done()
+		finfos, err := d.tree.projTree.readDir(d.tree.path)
+		if err != nil {
+			log.Printf("Unexpected error reading directory %s: %s", d.tree.path, err)
+			return
+		}
 
-	children := int64(0)
-	for _, finfo := range finfos {
-		if finfo.IsDir() {
-			children++
+		children := int64(0)
+		for _, finfo := range finfos {
+			if finfo.IsDir() {
+				children++
+			}
 		}
-	}
+		atomic.StoreInt64(&d.length, children)
 
-	d.updateExpandable(children)
-	atomic.StoreInt64(&d.length, children)
-	if d.tree.Attached() {
-		d.tree.parse(finfos, d.watcher)
-	}
+		d.driver.Call(func() {
+			d.updateExpandable(children)
+			if d.tree.Attached() {
+				d.tree.parse(finfos, d.watcher)
+			}
+			if !d.loaded {
+				d.loaded = true
+				if d.expandTo != "" {
+					d.ExpandTo(d.expandTo)
+				}
+			} else if d.tree.Attached() {
+				d.expandChildren()
+			}
+			d.Relayout()
+			d.Redraw()
+		})
+	}()
 }
 
 type dirTree struct {
@@ -189,16 +258,27 @@ func (d *dirTree) Unload(w Watcher) error {
 	return w.Remove(d.path)
 }
 
-func (d *dirTree) Load(w Watcher) error {
-	if w != nil {
-		w.Add(d.path)
-	}
-	finfos, err := ioutil.ReadDir(d.path)
-	if err != nil {
-		return err
-	}
-	d.parse(finfos, w)
-	return nil
+// Load watches and reads d's directory in the background, then adds
+// its children and calls done on the UI goroutine.
+func (d *dirTree) Load(w Watcher, done func()) {
+	progress := d.projTree.progress
+	progress.Start()
+	go func() {
+		defer progress.Done()
+		if w != nil {
+			w.Add(d.path)
+		}
+		finfos, err := d.projTree.readDir(d.path)
+		if err != nil {
+			log.Printf("Unexpected error reading directory %s: %s", d.path, err)
+			return
+		}
+		d.driver.Call(func() {
+			d.parse(finfos, w)
+			done()
+			d.Relayout()
+		})
+	}()
 }
 
 func (d *dirTree) parse(finfos []os.FileInfo, w Watcher) {
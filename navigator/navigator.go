@@ -5,8 +5,11 @@
 package navigator
 
 import (
+	"reflect"
+
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/vidar/controller"
 )
 
 // Pane is a type that has a button and a window frame.
@@ -39,6 +42,9 @@ type Navigator struct {
 	buttons gxui.LinearLayout
 	frame   gxui.Control
 
+	// shown is the pane that frame was last shown for.
+	shown Pane
+
 	panes []Pane
 }
 
@@ -66,6 +72,14 @@ func (n *Navigator) Elements() []interface{} {
 	return elements
 }
 
+// SetFont changes the font of every pane's frame, including frames
+// that aren't currently shown.
+func (n *Navigator) SetFont(font gxui.Font) {
+	for _, pane := range n.panes {
+		controller.SetFont(pane.Frame(), font)
+	}
+}
+
 func (n *Navigator) Buttons() gxui.LinearLayout {
 	return n.buttons
 }
@@ -78,6 +92,7 @@ func (n *Navigator) Add(pane Pane) {
 			return
 		}
 		n.ToggleNavPane(pane.Frame())
+		n.shown = pane
 	})
 	n.caller.Call(func() {
 		n.buttons.AddChild(button)
@@ -119,3 +134,40 @@ func (n *Navigator) ShowNavPane(frame gxui.Control) {
 		gxui.SetFocus(focusable)
 	}
 }
+
+// ShownPane returns the name of the pane whose frame is shown, or an
+// empty string if no pane is shown.
+func (n *Navigator) ShownPane() string {
+	if n.frame == nil || n.shown == nil {
+		return ""
+	}
+	return paneName(n.shown)
+}
+
+// ShowPane shows the frame of the pane named name, as returned by
+// ShownPane, or hides the shown pane if name is empty.  It returns
+// false if there is no pane named name.
+func (n *Navigator) ShowPane(name string) bool {
+	if name == "" {
+		n.HideNavPane()
+		return true
+	}
+	for _, pane := range n.panes {
+		if paneName(pane) == name {
+			n.ShowNavPane(pane.Frame())
+			n.shown = pane
+			return true
+		}
+	}
+	return false
+}
+
+// paneName returns the name of pane's type, which is the same for
+// every run of vidar.
+func paneName(pane Pane) string {
+	t := reflect.TypeOf(pane)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
@@ -0,0 +1,80 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// spinnerInterval is the time between frames of the spinner that is
+// displayed while directories are being scanned.
+const spinnerInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// progress keeps track of background directory scans, displaying a
+// spinner on a button until all of them have finished.
+type progress struct {
+	driver gxui.Driver
+	button gxui.Button
+
+	mu      sync.Mutex
+	pending int
+	stop    chan struct{}
+	done    func()
+}
+
+func newProgress(driver gxui.Driver, button gxui.Button) *progress {
+	return &progress{driver: driver, button: button}
+}
+
+// Start records that a scan has started.  Each call to Start must be
+// followed by a call to Done.
+func (p *progress) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending++
+	if p.pending > 1 {
+		return
+	}
+	p.stop = make(chan struct{})
+	p.done = status.StartTask("scanning project")
+	go p.spin(p.stop)
+}
+
+// Done records that a scan has finished.
+func (p *progress) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending--
+	if p.pending > 0 {
+		return
+	}
+	close(p.stop)
+	p.done()
+}
+
+func (p *progress) spin(stop <-chan struct{}) {
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	for frame := 0; ; frame = (frame + 1) % len(spinnerFrames) {
+		text := spinnerFrames[frame]
+		p.driver.Call(func() {
+			p.button.SetText(text)
+		})
+		select {
+		case <-stop:
+			p.driver.Call(func() {
+				p.button.SetText("")
+			})
+			return
+		case <-ticker.C:
+		}
+	}
+}
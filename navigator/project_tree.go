@@ -7,10 +7,13 @@ package navigator
 import (
 	"go/token"
 	"io"
+	"io/ioutil"
 	"log"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/math"
@@ -21,6 +24,10 @@ import (
 	"github.com/nelsam/vidar/setting"
 )
 
+// updateDelay is how long the project tree waits for a burst of
+// filesystem events to end before reloading the paths that changed.
+const updateDelay = 100 * time.Millisecond
+
 var (
 	dirColor = gxui.Color{
 		R: 0.8,
@@ -36,6 +43,13 @@ var (
 	}
 	fileColor = gxui.Gray80
 
+	moduleColor = gxui.Color{
+		R: 0.6,
+		G: 0.8,
+		B: 1,
+		A: 1,
+	}
+
 	splitterBarBackgroundColor = gxui.Color{
 		R: 0.6,
 		G: 0.3,
@@ -62,29 +76,52 @@ type ProjectTree struct {
 	driver gxui.Driver
 	theme  *basic.Theme
 
-	dirs    *directory
-	tocCtl  gxui.Control
-	toc     *TOC
-	tocLock sync.RWMutex
-
-	watcher    fsw.Watcher
-	reloadLock chan struct{}
+	header   gxui.Label
+	menu     *contextMenu
+	dirs     []*directory
+	progress *progress
+	tocCtl   gxui.Control
+	toc      *TOC
+	tocLock  sync.RWMutex
+
+	watchLock sync.Mutex
+	roots     []string
+	watcher   fsw.Watcher
+	watching  map[string]struct{}
+	polling   bool
+	updates   *fsw.Queue
 
 	layout *splitterLayout
 }
 
 func NewProjectTree(cmdr Commander, driver gxui.Driver, window gxui.Window, theme *basic.Theme) *ProjectTree {
 	tree := &ProjectTree{
-		cmdr:       cmdr,
-		driver:     driver,
-		theme:      theme,
-		reloadLock: make(chan struct{}, 1),
-		button:     createIconButton(driver, theme, "folder.png"),
-		layout:     newSplitterLayout(window, theme),
+		cmdr:     cmdr,
+		driver:   driver,
+		theme:    theme,
+		watching: make(map[string]struct{}),
+		button:   createIconButton(driver, theme, "folder.png"),
+		header:   theme.CreateLabel(),
+		menu:     newContextMenu(cmdr, window, theme),
+		layout:   newSplitterLayout(window, theme),
 	}
+	tree.updates = fsw.NewQueue(updateDelay, tree.update)
+	tree.header.SetColor(moduleColor)
+	tree.header.SetMargin(math.Spacing{L: 3, T: 2, R: 3, B: 2})
+	tree.setHeader(setting.DefaultProject)
+	tree.progress = newProgress(driver, tree.button)
 	tree.initWatcher()
 	tree.layout.SetOrientation(gxui.Vertical)
 	tree.SetRoot(setting.DefaultProject.Path)
+	setting.OnBuildContextChange(func(string) {
+		// The TOC grays out files that aren't built, so it has to
+		// check them again.
+		driver.Call(func() {
+			if toc := tree.TOC(); toc != nil {
+				toc.Reload()
+			}
+		})
+	})
 
 	return tree
 }
@@ -94,10 +131,75 @@ func (p *ProjectTree) initWatcher() {
 	if err != nil {
 		// TODO: report to the UI
 		log.Printf("WARNING: could not watch project tree: %s", err)
-		return
+		if !fsw.IsWatchLimit(err) {
+			return
+		}
+		log.Printf("WARNING: falling back to polling for project tree changes")
+		w = fsw.NewPoller(setting.PollInterval())
+		p.polling = true
 	}
 	p.watcher = w
-	go p.watch()
+	go p.watch(w)
+}
+
+// Add starts watching path for changes.  If the system's watch limit
+// has been reached, p will fall back to polling for changes.  Paths
+// outside of the current roots are ignored, since they may be added
+// by scans that were started before the roots changed, and so are
+// paths that the ignore settings match.
+func (p *ProjectTree) Add(path string) error {
+	p.watchLock.Lock()
+	defer p.watchLock.Unlock()
+	if p.watcher == nil {
+		return nil
+	}
+	root, ok := rootFor(p.roots, path)
+	if !ok || setting.IgnoredDir(root, path) {
+		return nil
+	}
+	err := p.watcher.Add(path)
+	if fsw.IsWatchLimit(err) && !p.polling {
+		log.Printf("WARNING: watch limit reached while watching %s; falling back to polling", path)
+		p.startPolling()
+		err = p.watcher.Add(path)
+	}
+	if err != nil {
+		return err
+	}
+	p.watching[path] = struct{}{}
+	return nil
+}
+
+// Remove stops watching path for changes.
+func (p *ProjectTree) Remove(path string) error {
+	p.watchLock.Lock()
+	defer p.watchLock.Unlock()
+	if p.watcher == nil {
+		return nil
+	}
+	delete(p.watching, path)
+	return p.watcher.Remove(path)
+}
+
+// startPolling replaces p.watcher with a polling watcher, moving all
+// current watches over to the new watcher.  p.watchLock must be held
+// while calling startPolling.
+//
+// TODO: try switching back to a native watcher when the project
+// changes to one with fewer directories.
+func (p *ProjectTree) startPolling() {
+	poller := fsw.NewPoller(setting.PollInterval())
+	for path := range p.watching {
+		if err := poller.Add(path); err != nil {
+			log.Printf("WARNING: could not poll %s for changes: %s", path, err)
+		}
+	}
+	if err := p.watcher.Close(); err != nil {
+		log.Printf("WARNING: error closing project tree watcher: %s", err)
+	}
+	p.watcher = poller
+	p.polling = true
+	go p.watch(poller)
 }
 
 func (p *ProjectTree) SetTOC(toc *TOC) {
@@ -112,49 +214,160 @@ func (p *ProjectTree) TOC() *TOC {
 	return p.toc
 }
 
+// showTOC replaces the table of contents below the tree with one for
+// dir.  It must be called on the UI goroutine.
+func (p *ProjectTree) showTOC(dir string) *TOC {
+	if p.tocCtl != nil {
+		p.layout.RemoveChild(p.tocCtl)
+	}
+	toc := NewTOC(p.cmdr, p.driver, p.theme, dir)
+	p.SetTOC(toc)
+	scrollable := p.theme.CreateScrollLayout()
+	// Disable horiz scrolling until we can figure out an accurate
+	// way to calculate our width.
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(toc)
+	tocLayout := p.theme.CreateLinearLayout()
+	tocLayout.SetDirection(gxui.TopToBottom)
+	tocLayout.AddChild(toc.Filter())
+	tocLayout.AddChild(scrollable)
+	p.tocCtl = tocLayout
+	p.layout.AddChild(p.tocCtl)
+	p.layout.SetChildWeight(p.tocCtl, 2)
+	return toc
+}
+
+// showDependencyTOC shows the table of contents for the directory of
+// path if path is a go file outside of the project (e.g. in GOROOT or
+// the module cache), since those directories can't be reached through
+// the tree.
+func (p *ProjectTree) showDependencyTOC(path string) {
+	if filepath.Ext(path) != ".go" {
+		return
+	}
+	p.watchLock.Lock()
+	roots := p.roots
+	p.watchLock.Unlock()
+	dir := filepath.Dir(path)
+	if len(roots) == 0 {
+		return
+	}
+	if _, ok := rootFor(roots, dir); ok {
+		return
+	}
+	p.driver.Call(func() {
+		if toc := p.TOC(); toc != nil && toc.dir == dir {
+			return
+		}
+		// Dependencies are read-only, so the context menu's file
+		// operations are left off of their TOC.
+		p.showTOC(dir)
+	})
+}
+
 func (p *ProjectTree) Button() gxui.Button {
 	return p.button
 }
 
+// SetRoot changes the root directory of p.  Directories are scanned
+// and watched in the background, with a spinner on p's button until
+// they're done; the tree fills in as each one is read.
 func (p *ProjectTree) SetRoot(path string) {
+	p.SetRoots(path)
+}
+
+// SetRoots is like SetRoot, but shows a separate tree for each of
+// roots.
+func (p *ProjectTree) SetRoots(roots ...string) {
 	p.layout.RemoveAll()
 	p.SetTOC(nil)
 	p.tocCtl = nil
 
-	if p.watcher != nil {
-		if err := p.watcher.RemoveAll(); err != nil {
-			log.Printf("WARNING: failed to remove current watches from watcher: %s", err)
+	go func() {
+		p.resetWatches(roots)
+		p.driver.Call(func() {
+			p.setDirs(roots)
+		})
+	}()
+}
+
+// setDirs displays the directory trees for roots.  It must be called
+// on the UI goroutine.
+func (p *ProjectTree) setDirs(roots []string) {
+	p.dirs = nil
+	trees := p.theme.CreateLinearLayout()
+	trees.SetDirection(gxui.TopToBottom)
+	for _, root := range roots {
+		d := newDirectory(p, root, p)
+		p.dirs = append(p.dirs, d)
+		trees.AddChild(d)
+	}
+	scrollable := p.theme.CreateScrollLayout()
+	// Disable horiz scrolling until we can figure out an accurate
+	// way to calculate our width.
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(trees)
+	dirsLayout := p.theme.CreateLinearLayout()
+	dirsLayout.SetDirection(gxui.TopToBottom)
+	dirsLayout.AddChild(p.header)
+	dirsLayout.AddChild(scrollable)
+	p.layout.AddChild(dirsLayout)
+	p.layout.SetChildWeight(dirsLayout, 1)
+
+	// Expand the top level of each root once it has been read.
+	for i, d := range p.dirs {
+		d.ExpandTo(roots[i])
+	}
+
+	p.layout.Relayout()
+	p.layout.Redraw()
+}
+
+// readDir reads the directory at path, leaving out the files and
+// subdirectories that the ignore settings match.
+func (p *ProjectTree) readDir(path string) ([]os.FileInfo, error) {
+	finfos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	p.watchLock.Lock()
+	root, _ := rootFor(p.roots, path)
+	p.watchLock.Unlock()
+	kept := finfos[:0]
+	for _, finfo := range finfos {
+		if setting.Ignored(root, filepath.Join(path, finfo.Name()), finfo.IsDir()) {
+			continue
 		}
+		kept = append(kept, finfo)
 	}
+	return kept, nil
+}
 
-	p.driver.Call(func() {
-		p.dirs = newDirectory(p, path, p.watcher)
-		scrollable := p.theme.CreateScrollLayout()
-		// Disable horiz scrolling until we can figure out an accurate
-		// way to calculate our width.
-		scrollable.SetScrollAxis(false, true)
-		scrollable.SetChild(p.dirs)
-		p.layout.AddChild(scrollable)
-		p.layout.SetChildWeight(p.dirs, 1)
-
-		// Expand the top level
-		p.dirs.button.Click(gxui.MouseEvent{})
-
-		p.layout.Relayout()
-		p.layout.Redraw()
-	})
+// resetWatches removes all current watches and sets the roots that
+// new watches must be in.
+func (p *ProjectTree) resetWatches(roots []string) {
+	p.watchLock.Lock()
+	defer p.watchLock.Unlock()
+	p.roots = roots
+	if p.watcher == nil {
+		return
+	}
+	if err := p.watcher.RemoveAll(); err != nil {
+		log.Printf("WARNING: failed to remove current watches from watcher: %s", err)
+	}
+	p.watching = make(map[string]struct{})
 }
 
-// watch waits for events from p.watcher.  For each event, the tree will
-// spin off a goroutine to update the its children.
+// watch waits for events from w and queues the paths that they refer
+// to for p.update.  Bursts of events, e.g. from a `git checkout` that
+// touches many, many files and directories, are coalesced by the
+// queue so that each path is only reloaded once.
 //
-// Events are processed in separate goroutines to help us keep up with
-// rapidly occurring events, e.g. in the case of a `git checkout` that
-// touches many, many files and directories.  It doesn't completely
-// prevent UI lock up, but it mitigates it some.
-func (p *ProjectTree) watch() {
+// When p falls back to polling, the old watcher is closed, which ends
+// its watch goroutine.
+func (p *ProjectTree) watch(w fsw.Watcher) {
 	for {
-		e, err := p.watcher.Next()
+		e, err := w.Next()
 		if err == io.EOF {
 			return
 		}
@@ -163,39 +376,18 @@ func (p *ProjectTree) watch() {
 		}
 		switch e.Op {
 		case fsw.Write, fsw.Create, fsw.Remove, fsw.Rename:
-			go p.update(e.Path)
+			p.updates.Push(e.Path)
 		}
 	}
 }
 
 // update will update any parts of p that changes to path would affect.
-//
-// If other concurrent calls to update are running, update may bail out
-// in order to prevent locking up the UI.
-//
-// KNOWN ISSUE: This logic could cause the UI to get out of sync with
-// the filesystem.  Either an event for /bar could be ignored while
-// we process an event for /foo, or an event for /foo could be ignored
-// when the current state of the filesystem has already been processed,
-// but before the lock has been released.
-//
-// So far, though, I haven't seen a situation where fs events overwhelm
-// this logic to the point that the UI displays incorrect data, so maybe
-// it's good enough?  I'll be keeping my eye out for the UI getting in
-// to a bad state, but I'm not going to solve the issue until I know
-// it really is an issue.
+// It's only called by p.updates, one path at a time.
 func (p *ProjectTree) update(path string) {
-	select {
-	case p.reloadLock <- struct{}{}:
-	default:
-		return
-	}
-	defer func() {
-		<-p.reloadLock
-	}()
-
 	p.driver.CallSync(func() {
-		p.dirs.update(path)
+		for _, d := range p.dirs {
+			d.update(path)
+		}
 	})
 	toc := p.TOC()
 	if toc != nil && strings.HasPrefix(path, toc.dir) {
@@ -203,7 +395,31 @@ func (p *ProjectTree) update(path string) {
 	}
 }
 
+// Refresh reads dir and the table of contents for it again, in case
+// files were generated in it faster than p's watcher could keep up.
+func (p *ProjectTree) Refresh(dir string) {
+	p.driver.Call(func() {
+		for _, d := range p.dirs {
+			d.refresh(dir)
+		}
+		if toc := p.TOC(); toc != nil && toc.dir == dir {
+			toc.Reload()
+		}
+	})
+}
+
+// setHeader displays the name of project above the tree, along with
+// the path of the go module that it's in, if any.
+func (p *ProjectTree) setHeader(project setting.Project) {
+	text := project.Name
+	if m, ok := project.Module(); ok && m.Path != "" {
+		text += " (module " + m.Path + ")"
+	}
+	p.header.SetText(text)
+}
+
 func (p *ProjectTree) SetProject(project setting.Project) {
+	p.setHeader(project)
 	// Ensure that the project tree is the current pane before
 	// the UI goroutine does our relayout/redraw logic.
 	p.driver.Call(func() {
@@ -214,23 +430,49 @@ func (p *ProjectTree) SetProject(project setting.Project) {
 			Button: gxui.MouseButtonLeft,
 		})
 	})
-	p.SetRoot(project.Path)
+	p.SetRoots(project.Dirs()...)
 }
 
 func (p *ProjectTree) Open(path string, pos token.Position) {
 	dir, _ := filepath.Split(path)
-	p.dirs.ExpandTo(dir)
+	for _, d := range p.dirs {
+		d.ExpandTo(dir)
+	}
+}
+
+// FocusTree shows p and moves keyboard focus to the button in p that
+// last had it, so that p can be navigated without the mouse.  It
+// returns false if p has no buttons yet.
+func (p *ProjectTree) FocusTree() bool {
+	if !p.layout.Attached() {
+		p.button.Click(gxui.MouseEvent{
+			Button: gxui.MouseButtonLeft,
+		})
+	}
+	return p.layout.nav.focus()
 }
 
 func (p *ProjectTree) Frame() gxui.Control {
 	return p.layout
 }
 
+// rootFor returns the deepest of roots that contains path.
+func rootFor(roots []string, path string) (string, bool) {
+	var root string
+	for _, r := range roots {
+		if r != "" && len(r) > len(root) && (path == r || strings.HasPrefix(path, r+string(filepath.Separator))) {
+			root = r
+		}
+	}
+	return root, root != ""
+}
+
 type splitterLayout struct {
 	mixins.SplitterLayout
 
 	window gxui.Window
 	theme  gxui.Theme
+	nav    *treeNav
 }
 
 func newSplitterLayout(window gxui.Window, theme gxui.Theme) *splitterLayout {
@@ -238,10 +480,17 @@ func newSplitterLayout(window gxui.Window, theme gxui.Theme) *splitterLayout {
 		window: window,
 		theme:  theme,
 	}
+	l.nav = &treeNav{root: l}
 	l.Init(l, theme)
 	return l
 }
 
+// treeNav returns the keyboard navigation for the directories and
+// table of contents in l.
+func (l *splitterLayout) treeNav() *treeNav {
+	return l.nav
+}
+
 func (l *splitterLayout) DesiredSize(min, max math.Size) math.Size {
 	s := l.SplitterLayout.DesiredSize(min, max)
 	width := 20 * l.theme.DefaultMonospaceFont().GlyphMaxSize().W
@@ -0,0 +1,403 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+const (
+	// maxSearchFileSize is the largest file that the search pane
+	// will search.  Anything larger is most likely generated.
+	maxSearchFileSize = 1 << 20
+
+	// maxLineLen is the longest line that the search pane will
+	// display in its results.
+	maxLineLen = 80
+)
+
+var (
+	matchColor = gxui.Gray60
+
+	errSearchStopped = errors.New("search stopped")
+)
+
+// Search is a navigator pane that searches every file in the current
+// project's roots for lines matching a regular expression.
+type Search struct {
+	button gxui.Button
+
+	cmdr   Commander
+	driver gxui.Driver
+	theme  *basic.Theme
+
+	layout  *searchLayout
+	pattern gxui.TextBox
+	status  gxui.Label
+	results gxui.LinearLayout
+
+	roots []string
+
+	lock    sync.Mutex
+	stop    chan struct{}
+	matched int
+}
+
+// NewSearch creates a search pane that searches the default project
+// until a project is opened.
+func NewSearch(cmdr Commander, driver gxui.Driver, theme *basic.Theme) *Search {
+	s := &Search{
+		cmdr:    cmdr,
+		driver:  driver,
+		theme:   theme,
+		button:  createIconButton(driver, theme, "search.png"),
+		layout:  newSearchLayout(theme),
+		pattern: theme.CreateTextBox(),
+		status:  theme.CreateLabel(),
+		results: theme.CreateLinearLayout(),
+		roots:   setting.DefaultProject.Dirs(),
+	}
+	s.layout.SetDirection(gxui.TopToBottom)
+
+	s.pattern.SetDesiredWidth(math.MaxSize.W)
+	s.pattern.OnKeyPress(func(ev gxui.KeyboardEvent) {
+		switch ev.Key {
+		case gxui.KeyEnter:
+			s.Start(s.pattern.Text())
+		case gxui.KeyEscape:
+			s.Cancel()
+		}
+	})
+	s.layout.AddChild(s.pattern)
+
+	s.status.SetText("Press enter to search")
+	s.layout.AddChild(s.status)
+
+	s.results.SetDirection(gxui.TopToBottom)
+	scrollable := theme.CreateScrollLayout()
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(s.results)
+	s.layout.AddChild(scrollable)
+	return s
+}
+
+func (s *Search) Button() gxui.Button {
+	return s.button
+}
+
+func (s *Search) Frame() gxui.Control {
+	return s.layout
+}
+
+// SetProject cancels any running search and sets the root
+// directories for future searches to the project's roots.
+func (s *Search) SetProject(project setting.Project) {
+	s.Cancel()
+	s.roots = project.Dirs()
+	s.driver.Call(func() {
+		s.results.RemoveAll()
+		s.status.SetText("Press enter to search")
+	})
+}
+
+// Start cancels any running search and starts searching the project
+// for pattern in the background.  It must be called on the UI
+// goroutine.
+func (s *Search) Start(pattern string) {
+	s.Cancel()
+	s.results.RemoveAll()
+	if pattern == "" {
+		s.status.SetText("Press enter to search")
+		return
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		s.status.SetText(fmt.Sprintf("Invalid pattern: %s", err))
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stop := make(chan struct{})
+	s.stop = stop
+	s.matched = 0
+	s.status.SetText(fmt.Sprintf("Searching %s...", strings.Join(s.roots, ", ")))
+	task := status.Start("searching project", func() {
+		if s.cancel(stop) {
+			s.driver.Call(func() {
+				s.status.SetText("Search canceled")
+			})
+		}
+	})
+	go s.search(task, stop, s.roots, re)
+}
+
+// SearchOpen cancels any running search and searches texts, which
+// maps the paths of open files to their text, for pattern.  The text
+// is searched rather than the files on disk, so that unsaved changes
+// are included.  It must be called on the UI goroutine.
+func (s *Search) SearchOpen(pattern string, texts map[string]string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	s.Cancel()
+	if !s.layout.Attached() {
+		s.button.Click(gxui.MouseEvent{Button: gxui.MouseButtonLeft})
+	}
+	s.pattern.SetText(pattern)
+	s.results.RemoveAll()
+	s.matched = 0
+
+	paths := make([]string, 0, len(texts))
+	for path := range texts {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		matches := grepReader(strings.NewReader(texts[path]), re)
+		if len(matches) == 0 {
+			continue
+		}
+		s.addFile(s.roots, path, matches)
+	}
+	s.status.SetText(fmt.Sprintf("%d matches found in %d open files", s.matched, len(paths)))
+	return nil
+}
+
+// Cancel stops any running search.
+func (s *Search) Cancel() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	s.stop = nil
+}
+
+// cancel stops the search that stop belongs to, returning whether or
+// not it was still running.
+func (s *Search) cancel(stop chan struct{}) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stop == nil || s.stop != stop {
+		return false
+	}
+	close(s.stop)
+	s.stop = nil
+	return true
+}
+
+func (s *Search) search(task *status.Task, stop <-chan struct{}, roots []string, re *regexp.Regexp) {
+	defer task.Done()
+	paths := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				path := path
+				matches := grep(path, re)
+				if len(matches) == 0 {
+					continue
+				}
+				s.driver.Call(func() {
+					s.addResult(stop, roots, path, matches)
+				})
+			}
+		}()
+	}
+
+	for _, root := range roots {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() {
+				if path != root && (strings.HasPrefix(info.Name(), ".") || setting.IgnoredDir(root, path)) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !info.Mode().IsRegular() || info.Size() > maxSearchFileSize || setting.Ignored(root, path, false) {
+				return nil
+			}
+			select {
+			case paths <- path:
+				return nil
+			case <-stop:
+				return errSearchStopped
+			}
+		})
+		if err == errSearchStopped {
+			break
+		}
+	}
+	close(paths)
+	wg.Wait()
+
+	s.driver.Call(func() {
+		if !s.finish(stop) {
+			return
+		}
+		s.status.SetText(fmt.Sprintf("%d matches found", s.matched))
+	})
+}
+
+// finish clears s.stop if stop is the channel for the current
+// search, returning whether or not it was.
+func (s *Search) finish(stop <-chan struct{}) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stop == nil || s.stop != stop {
+		return false
+	}
+	s.stop = nil
+	return true
+}
+
+func (s *Search) current(stop <-chan struct{}) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.stop != nil && s.stop == stop
+}
+
+func (s *Search) addResult(stop <-chan struct{}, roots []string, path string, matches []match) {
+	if !s.current(stop) {
+		return
+	}
+	s.addFile(roots, path, matches)
+	s.status.SetText(fmt.Sprintf("Searching... %d matches found", s.matched))
+}
+
+// addFile adds the matches in the file at path to s's results.
+func (s *Search) addFile(roots []string, path string, matches []match) {
+	name := resultName(roots, path)
+	file := newGenericNode(s.driver, s.theme, name, fileColor)
+	for _, m := range matches {
+		file.AddChild(newSearchResult(s.cmdr, s.driver, s.theme, path, m))
+	}
+	s.results.AddChild(file)
+	file.button.Click(gxui.MouseEvent{})
+	s.matched += len(matches)
+}
+
+// resultName returns the name to show for path in search results: its
+// path relative to the root that contains it.  When there is more than
+// one root, the root's name is included.
+func resultName(roots []string, path string) string {
+	root, ok := rootFor(roots, path)
+	if !ok {
+		return path
+	}
+	name, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	if len(roots) > 1 {
+		name = filepath.Join(filepath.Base(root), name)
+	}
+	return name
+}
+
+type match struct {
+	line, col int
+	text      string
+}
+
+// grep returns all lines in the file at path that match re.  Files
+// that look like binary files are skipped.
+func grep(path string, re *regexp.Regexp) []match {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	return grepReader(f, re)
+}
+
+// grepReader returns all lines read from r that match re.  If the
+// text looks like a binary file, nothing is returned.
+func grepReader(r io.Reader, re *regexp.Regexp) []match {
+	var matches []match
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxSearchFileSize)
+	for line := 0; scanner.Scan(); line++ {
+		b := scanner.Bytes()
+		if bytes.IndexByte(b, 0) != -1 {
+			return nil
+		}
+		loc := re.FindIndex(b)
+		if loc == nil {
+			continue
+		}
+		matches = append(matches, match{
+			line: line,
+			col:  utf8.RuneCount(b[:loc[0]]),
+			text: string(b),
+		})
+	}
+	return matches
+}
+
+func newSearchResult(cmdr Commander, driver gxui.Driver, theme gxui.Theme, path string, m match) *genericNode {
+	text := strings.TrimSpace(m.text)
+	if utf8.RuneCountInString(text) > maxLineLen {
+		text = string([]rune(text)[:maxLineLen]) + "…"
+	}
+	node := newGenericNode(driver, theme, fmt.Sprintf("%d: %s", m.line+1, text), matchColor)
+	node.button.OnClick(func(gxui.MouseEvent) {
+		opener := cmdr.Bindable("focus-location").(Opener)
+		cmdr.Execute(opener.For(focus.Path(path), focus.Line(m.line), focus.Column(m.col)))
+	})
+	return node
+}
+
+type searchLayout struct {
+	mixins.LinearLayout
+
+	theme gxui.Theme
+}
+
+func newSearchLayout(theme gxui.Theme) *searchLayout {
+	l := &searchLayout{theme: theme}
+	l.Init(l, theme)
+	return l
+}
+
+func (l *searchLayout) DesiredSize(min, max math.Size) math.Size {
+	s := l.LinearLayout.DesiredSize(min, max)
+	width := 40 * l.theme.DefaultMonospaceFont().GlyphMaxSize().W
+	if min.W > width {
+		width = min.W
+	}
+	if max.W < width {
+		width = max.W
+	}
+	s.W = width
+	return s
+}
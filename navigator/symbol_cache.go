@@ -0,0 +1,113 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigator
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// symbols is shared by every TOC and by ParseSymbols, so that a file
+// that goto-symbol's index has already parsed doesn't have to be parsed
+// again when its directory is shown.
+var symbols = &symbolCache{dirs: make(map[string]map[string]*cachedFile)}
+
+// cachedFile holds the results of parsing a go file, along with the
+// modification time and size it had when it was parsed.
+type cachedFile struct {
+	modTime time.Time
+	size    int64
+
+	pkg *packageSymbols
+	err error
+}
+
+// symbolCache holds the symbols declared in go files, keyed by
+// directory and then file name.
+type symbolCache struct {
+	mu   sync.Mutex
+	dirs map[string]map[string]*cachedFile
+}
+
+// load returns the symbols declared in the go file at path.  The file
+// is only parsed if it has changed since it was last loaded, according
+// to info.  The returned symbols are shared, so they must not be
+// modified.
+func (c *symbolCache) load(path string, info os.FileInfo) (*packageSymbols, error) {
+	dir, name := filepath.Dir(path), filepath.Base(path)
+	c.mu.Lock()
+	f, ok := c.dirs[dir][name]
+	c.mu.Unlock()
+	if ok && f.modTime.Equal(info.ModTime()) && f.size == info.Size() {
+		return f.pkg, f.err
+	}
+
+	f = &cachedFile{modTime: info.ModTime(), size: info.Size()}
+	p := &fileParser{
+		fileSet:    token.NewFileSet(),
+		packageMap: make(map[string]*packageSymbols),
+	}
+	astFile, err := parser.ParseFile(p.fileSet, path, nil, parser.ParseComments)
+	if err != nil {
+		f.err = err
+	} else {
+		f.pkg = p.parseAstFile(path, astFile)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	files, ok := c.dirs[dir]
+	if !ok {
+		files = make(map[string]*cachedFile)
+		c.dirs[dir] = files
+	}
+	files[name] = f
+	return f.pkg, f.err
+}
+
+// prune removes the files in dir that aren't in infos.
+func (c *symbolCache) prune(dir string, infos []os.FileInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	files, ok := c.dirs[dir]
+	if !ok {
+		return
+	}
+	names := make(map[string]bool, len(infos))
+	for _, info := range infos {
+		names[info.Name()] = true
+	}
+	for name := range files {
+		if !names[name] {
+			delete(files, name)
+		}
+	}
+}
+
+// forget removes path from c.  If path is a directory, every file
+// under it is removed.
+func (c *symbolCache) forget(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.dirs[filepath.Dir(path)], filepath.Base(path))
+	prefix := path + string(filepath.Separator)
+	for dir := range c.dirs {
+		if dir == path || strings.HasPrefix(dir, prefix) {
+			delete(c.dirs, dir)
+		}
+	}
+}
+
+// ForgetSymbols drops the symbols that were parsed from path, or from
+// the files under it if it's a directory.  It should be called when
+// path is removed.
+func ForgetSymbols(path string) {
+	symbols.forget(path)
+}
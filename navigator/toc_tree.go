@@ -7,7 +7,6 @@ package navigator
 import (
 	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
 	"io/ioutil"
 	"log"
@@ -22,6 +21,7 @@ import (
 	"github.com/nelsam/gxui/themes/basic"
 	"github.com/nelsam/vidar/command/focus"
 	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/setting"
 )
 
 var (
@@ -56,6 +56,10 @@ var (
 		A: 1,
 	}
 
+	// excludedColor is used for go files that aren't built in the
+	// project's build context.
+	excludedColor = gxui.Gray60
+
 	// Since the const values aren't exported by go/build, I've just copied them
 	// from https://github.com/golang/go/blob/master/src/go/build/syslist.go
 	gooses = []string{
@@ -70,6 +74,7 @@ var (
 type Commander interface {
 	Bindable(name string) bind.Bindable
 	Execute(bind.Bindable)
+	Run(bind.Command)
 }
 
 type Opener interface {
@@ -127,6 +132,25 @@ func (n *genericNode) AddChild(c gxui.Control) *gxui.Child {
 	return child
 }
 
+// showChildren expands n without clicking its button, since some
+// buttons do more than expand their node when clicked.
+func (n *genericNode) showChildren() {
+	if n.children.Attached() || len(n.children.Children()) == 0 {
+		return
+	}
+	n.LinearLayout.AddChild(n.children)
+	n.button.Expand()
+}
+
+// hideChildren collapses n without clicking its button.
+func (n *genericNode) hideChildren() {
+	if !n.children.Attached() {
+		return
+	}
+	n.LinearLayout.RemoveChild(n.children)
+	n.button.Collapse()
+}
+
 func (n *genericNode) MissingChild() gxui.Control {
 	if n.children.Attached() {
 		return nil
@@ -137,78 +161,109 @@ func (n *genericNode) MissingChild() gxui.Control {
 type packageNode struct {
 	genericNode
 
-	cmdr                       Commander
-	consts, vars, types, funcs *genericNode
-	typeMap                    map[string]*Name
+	sections []*genericNode
 }
 
-func newPackageNode(cmdr Commander, driver gxui.Driver, theme gxui.Theme, name string) *packageNode {
-	node := &packageNode{
-		cmdr:    cmdr,
-		consts:  newGenericNode(driver, theme, "constants", genericColor),
-		vars:    newGenericNode(driver, theme, "vars", genericColor),
-		types:   newGenericNode(driver, theme, "types", genericColor),
-		funcs:   newGenericNode(driver, theme, "funcs", genericColor),
-		typeMap: make(map[string]*Name),
-	}
+func newPackageNode(driver gxui.Driver, theme gxui.Theme, name string) *packageNode {
+	node := &packageNode{}
 	node.Init(node, driver, theme, name, skippableColor)
-	node.AddChild(node.consts)
-	node.AddChild(node.vars)
-	node.AddChild(node.types)
-	node.AddChild(node.funcs)
 	return node
 }
 
-func (p *packageNode) expand() {
-	p.consts.button.Click(gxui.MouseEvent{})
-	p.vars.button.Click(gxui.MouseEvent{})
-	p.types.button.Click(gxui.MouseEvent{})
-	p.funcs.button.Click(gxui.MouseEvent{})
+// addSection adds a section named name to p, containing names.
+func (p *packageNode) addSection(name string, names []*Name) {
+	section := newGenericNode(p.driver, p.theme, name, genericColor)
+	for _, n := range names {
+		section.AddChild(n)
+	}
+	p.sections = append(p.sections, section)
+	p.AddChild(section)
 }
 
-func (p *packageNode) addConsts(consts ...*Name) {
-	for _, c := range consts {
-		p.consts.AddChild(c)
+func (p *packageNode) expand() {
+	for _, s := range p.sections {
+		s.button.Click(gxui.MouseEvent{})
 	}
 }
 
-func (p *packageNode) addVars(vars ...*Name) {
-	for _, v := range vars {
-		p.vars.AddChild(v)
-	}
+// symbol is a declaration that is listed in the TOC.  The TOC keeps
+// a list of symbols so that it can rebuild its tree whenever the
+// filter changes, without parsing files again.
+type symbol struct {
+	Location
+
+	name  string
+	color gxui.Color
+	kind  symbolKind
+
+	methods []*symbol
 }
 
-func (p *packageNode) addTypes(types ...*Name) {
-	for _, typ := range types {
-		// Since we can't guarantee that we parsed the type declaration before
-		// any method declarations, we have to check to see if the type already
-		// exists.
-		existingType, ok := p.typeMap[typ.button.Text()]
-		if !ok {
-			p.typeMap[typ.button.Text()] = typ
-			p.types.AddChild(typ)
-			continue
-		}
-		existingType.button.Label().SetColor(typ.button.Label().Color())
-		existingType.filepath = typ.filepath
-		existingType.position = typ.position
-	}
+type symbolKind int
+
+const (
+	otherKind symbolKind = iota
+	interfaceKind
+	structKind
+)
+
+// matches returns whether or not s's name contains filter, which
+// must already be lower case.
+func (s *symbol) matches(filter string) bool {
+	return strings.Contains(strings.ToLower(s.name), filter)
+}
+
+// packageSymbols holds the symbols declared in a package.
+type packageSymbols struct {
+	name                string
+	consts, vars, funcs []*symbol
+
+	// types is in the order that types were first seen, either by
+	// their declaration or by one of their methods.  typeNames holds
+	// the name that each of them is keyed by in typeMap.
+	types     []*symbol
+	typeNames []string
+	typeMap   map[string]*symbol
 }
 
-func (p *packageNode) addFuncs(funcs ...*Name) {
-	for _, f := range funcs {
-		p.funcs.AddChild(f)
+func newPackageSymbols(name string) *packageSymbols {
+	return &packageSymbols{
+		name:    name,
+		typeMap: make(map[string]*symbol),
 	}
 }
 
-func (p *packageNode) addMethod(typeName string, method *Name) {
-	typ, ok := p.typeMap[typeName]
+// typeNamed returns the symbol for the type named name, creating it
+// if it hasn't been seen yet.  Since we can't guarantee that we
+// parsed the type declaration before any method declarations, types
+// may be created by their methods and filled in later.
+func (p *packageSymbols) typeNamed(name string) *symbol {
+	typ, ok := p.typeMap[name]
 	if !ok {
-		typ = newName(p.cmdr, p.driver, p.theme, typeName, nameColor)
-		p.typeMap[typeName] = typ
-		p.types.AddChild(typ)
+		typ = &symbol{name: name, color: nameColor}
+		p.typeMap[name] = typ
+		p.types = append(p.types, typ)
+		p.typeNames = append(p.typeNames, name)
+	}
+	return typ
+}
+
+// merge adds the symbols from other, which must be from a single
+// file, to p.  Types are copied rather than shared, since methods
+// from other files are added to them.
+func (p *packageSymbols) merge(other *packageSymbols) {
+	p.consts = append(p.consts, other.consts...)
+	p.vars = append(p.vars, other.vars...)
+	p.funcs = append(p.funcs, other.funcs...)
+	for i, typ := range other.types {
+		merged := p.typeNamed(other.typeNames[i])
+		if typ.filepath != "" {
+			merged.name = typ.name
+			merged.Location = typ.Location
+			merged.kind = typ.kind
+		}
+		merged.methods = append(merged.methods, typ.methods...)
 	}
-	typ.AddChild(method)
 }
 
 type Location struct {
@@ -234,7 +289,10 @@ type Name struct {
 func newName(cmdr Commander, driver gxui.Driver, theme gxui.Theme, name string, color gxui.Color) *Name {
 	node := &Name{cmdr: cmdr}
 	node.Init(node, driver, theme, name, color)
-	node.button.OnClick(func(gxui.MouseEvent) {
+	node.button.OnClick(func(ev gxui.MouseEvent) {
+		if ev.Button != gxui.MouseButtonLeft {
+			return
+		}
 		cmd := node.cmdr.Bindable("focus-location").(Opener)
 		node.cmdr.Execute(cmd.For(focus.Path(node.File()), focus.Offset(node.Position().Offset)))
 	})
@@ -248,146 +306,412 @@ type TOC struct {
 	driver gxui.Driver
 	theme  gxui.Theme
 
-	dir        string
-	fileSet    *token.FileSet
-	packageMap map[string]*packageNode
+	dir      string
+	files    []*symbol
+	packages []*packageSymbols
+
+	// loads counts the calls to Reload, so that a slow load can't
+	// replace the results of a later one.
+	loads int
+
+	filterBox gxui.TextBox
+	filter    string
+
+	// menu is shown when a file is right-clicked.  It may be nil.
+	menu *contextMenu
 
 	lock sync.Mutex
 }
 
 func NewTOC(cmdr Commander, driver gxui.Driver, theme gxui.Theme, dir string) *TOC {
 	toc := &TOC{
-		cmdr:   cmdr,
-		driver: driver,
-		theme:  theme,
-		dir:    dir,
+		cmdr:      cmdr,
+		driver:    driver,
+		theme:     theme,
+		dir:       dir,
+		filterBox: theme.CreateTextBox(),
 	}
 	toc.Init(toc, theme)
+	toc.SetDirection(gxui.TopToBottom)
+	toc.filterBox.SetDesiredWidth(math.MaxSize.W)
+	toc.filterBox.OnTextChanged(func([]gxui.TextBoxEdit) {
+		toc.SetFilter(toc.filterBox.Text())
+	})
 	toc.Reload()
 	return toc
 }
 
-func (t *TOC) Reload() {
+// Filter returns the text box that is used to filter t's symbols.
+// It should be displayed above t.
+func (t *TOC) Filter() gxui.Control {
+	return t.filterBox
+}
+
+// SetFilter narrows t down to the symbols and files with names that
+// contain filter, ignoring case.  It must be called on the UI
+// goroutine.
+func (t *TOC) SetFilter(filter string) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
-	defer t.expandPackages()
-	t.fileSet = token.NewFileSet()
+	t.filter = strings.ToLower(filter)
+	t.render()
+}
+
+// Reload reads t's directory again.  The directory is loaded in the
+// background, and t is rendered on the UI goroutine when it's done, so
+// that large packages don't freeze the navigator.
+func (t *TOC) Reload() {
+	t.lock.Lock()
+	t.loads++
+	load := t.loads
+	t.lock.Unlock()
+	go func() {
+		files, packages := loadDir(t.dir)
+		t.driver.Call(func() {
+			t.lock.Lock()
+			defer t.lock.Unlock()
+			if load != t.loads {
+				return
+			}
+			t.files, t.packages = files, packages
+			t.render()
+		})
+	}()
+}
+
+// render rebuilds t's tree from its symbols, leaving out anything
+// that doesn't match t's filter.  t.lock must be held while calling
+// render.
+func (t *TOC) render() {
 	t.RemoveAll()
-	t.packageMap = make(map[string]*packageNode)
-	allFiles, err := ioutil.ReadDir(t.dir)
-	if err != nil {
-		log.Printf("Received error reading directory %s: %s", t.dir, err)
-		return
+
+	var files []*Name
+	for _, f := range t.files {
+		if f.matches(t.filter) {
+			files = append(files, t.newFile(f))
+		}
+	}
+	if t.filter == "" || len(files) > 0 {
+		filesNode := newGenericNode(t.driver, t.theme, "files", skippableColor)
+		for _, f := range files {
+			filesNode.AddChild(f)
+		}
+		t.AddChild(filesNode)
+		filesNode.button.Click(gxui.MouseEvent{})
+	}
+
+	for _, pkg := range t.packages {
+		t.renderPackage(pkg)
 	}
-	t.parseFiles(t.dir, allFiles...)
 }
 
-func (t *TOC) expandPackages() {
-	for _, c := range t.Children() {
-		pkg, ok := c.Control.(*packageNode)
-		if !ok {
+func (t *TOC) renderPackage(pkg *packageSymbols) {
+	var interfaces, structs, types []*Name
+	for _, typ := range pkg.types {
+		name := t.newType(typ)
+		if name == nil {
 			continue
 		}
-		pkg.button.Click(gxui.MouseEvent{})
-		pkg.expand()
+		switch typ.kind {
+		case interfaceKind:
+			interfaces = append(interfaces, name)
+		case structKind:
+			structs = append(structs, name)
+		default:
+			types = append(types, name)
+		}
+	}
+	sections := []struct {
+		name  string
+		names []*Name
+	}{
+		{"constants", t.newNames(pkg.consts)},
+		{"vars", t.newNames(pkg.vars)},
+		{"interfaces", interfaces},
+		{"structs", structs},
+		{"types", types},
+		{"funcs", t.newNames(pkg.funcs)},
+	}
+	node := newPackageNode(t.driver, t.theme, pkg.name)
+	for _, s := range sections {
+		if t.filter != "" && len(s.names) == 0 {
+			continue
+		}
+		node.addSection(s.name, s.names)
+	}
+	if len(node.sections) == 0 {
+		return
 	}
+	t.AddChild(node)
+	node.button.Click(gxui.MouseEvent{})
+	node.expand()
 }
 
-func (t *TOC) parseFiles(dir string, files ...os.FileInfo) {
-	filesNode := newGenericNode(t.driver, t.theme, "files", skippableColor)
-	t.AddChild(filesNode)
-	defer filesNode.button.Click(gxui.MouseEvent{})
-	for _, file := range files {
-		if file.IsDir() {
-			continue
+// newNames returns a *Name for each symbol in syms that matches t's
+// filter.
+func (t *TOC) newNames(syms []*symbol) []*Name {
+	var names []*Name
+	for _, s := range syms {
+		if s.matches(t.filter) {
+			names = append(names, t.newName(s))
 		}
-		fileNode := t.parseFile(dir, file)
-		fileNode.filepath = filepath.Join(dir, file.Name())
-		filesNode.AddChild(fileNode)
 	}
+	return names
 }
 
-func (t *TOC) parseFile(dir string, file os.FileInfo) *Name {
-	if !strings.HasSuffix(file.Name(), ".go") {
-		return newName(t.cmdr, t.driver, t.theme, file.Name(), nonGoColor)
+// newType returns a *Name for typ, with its methods as children.  If
+// typ's name doesn't match t's filter, only its matching methods are
+// included, and nil is returned if none of them match.
+func (t *TOC) newType(typ *symbol) *Name {
+	methods := typ.methods
+	if !typ.matches(t.filter) {
+		methods = nil
+		for _, m := range typ.methods {
+			if m.matches(t.filter) {
+				methods = append(methods, m)
+			}
+		}
+		if len(methods) == 0 {
+			return nil
+		}
+	}
+	name := t.newName(typ)
+	for _, m := range methods {
+		name.AddChild(t.newName(m))
 	}
-	path := filepath.Join(dir, file.Name())
-	f, err := parser.ParseFile(t.fileSet, path, nil, parser.ParseComments)
+	if t.filter != "" {
+		name.showChildren()
+	}
+	return name
+}
+
+func (t *TOC) newName(s *symbol) *Name {
+	name := newName(t.cmdr, t.driver, t.theme, s.name, s.color)
+	name.Location = s.Location
+	return name
+}
+
+// newFile returns a *Name for the file f, which shows t's context
+// menu when it's right-clicked.
+func (t *TOC) newFile(f *symbol) *Name {
+	name := t.newName(f)
+	name.button.OnClick(func(ev gxui.MouseEvent) {
+		if ev.Button == gxui.MouseButtonRight && t.menu != nil {
+			t.menu.show(name.File(), ev.WindowPoint)
+		}
+	})
+	return name
+}
+
+// loadDir returns the files in dir and the symbols declared in its go
+// files.  Go files are loaded through symbols, so only the files that
+// changed since they were last loaded are parsed.
+func loadDir(dir string) ([]*symbol, []*packageSymbols) {
+	build := setting.ProjectFor(dir).BuildContext()
+	infos, err := ioutil.ReadDir(dir)
 	if err != nil {
-		return newName(t.cmdr, t.driver, t.theme, file.Name(), errColor)
+		log.Printf("Received error reading directory %s: %s", dir, err)
+		return nil, nil
+	}
+	symbols.prune(dir, infos)
+
+	var (
+		files      []*symbol
+		packages   []*packageSymbols
+		packageMap = make(map[string]*packageSymbols)
+	)
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, info.Name())
+		file := &symbol{name: info.Name(), color: nonGoColor, Location: Location{filepath: path}}
+		files = append(files, file)
+		if !strings.HasSuffix(info.Name(), ".go") {
+			continue
+		}
+		if !build.Matches(path) {
+			// Symbols from files that aren't built would show up
+			// alongside the ones that are, e.g. the same func from
+			// both foo_linux.go and foo_windows.go.
+			file.color = excludedColor
+			continue
+		}
+		pkg, err := symbols.load(path, info)
+		if err != nil {
+			file.color = errColor
+			continue
+		}
+		file.color = nameColor
+		merged, ok := packageMap[pkg.name]
+		if !ok {
+			merged = newPackageSymbols(pkg.name)
+			packageMap[pkg.name] = merged
+			packages = append(packages, merged)
+		}
+		merged.merge(pkg)
 	}
-	t.parseAstFile(path, f)
-	return newName(t.cmdr, t.driver, t.theme, file.Name(), nameColor)
+	return files, packages
 }
 
-func (t *TOC) parseAstFile(filepath string, file *ast.File) *packageNode {
+// fileParser collects the symbols declared in go files.
+type fileParser struct {
+	fileSet    *token.FileSet
+	packageMap map[string]*packageSymbols
+}
+
+func (t *fileParser) parseAstFile(filepath string, file *ast.File) *packageSymbols {
 	buildTags := findBuildTags(filepath, file)
 	buildTagLine := strings.Join(buildTags, " ")
 
 	packageName := file.Name.String()
-	pkgNode, ok := t.packageMap[packageName]
+	pkg, ok := t.packageMap[packageName]
 	if !ok {
-		pkgNode = newPackageNode(t.cmdr, t.driver, t.theme, packageName)
-		t.packageMap[pkgNode.button.Text()] = pkgNode
-		t.AddChild(pkgNode)
+		pkg = newPackageSymbols(packageName)
+		t.packageMap[packageName] = pkg
 	}
 	for _, decl := range file.Decls {
 		switch src := decl.(type) {
 		case *ast.GenDecl:
-			t.parseGenDecl(pkgNode, src, filepath, buildTagLine)
+			t.parseGenDecl(pkg, src, filepath, buildTagLine)
 		case *ast.FuncDecl:
 			if src.Name.String() == "init" {
 				// There can be multiple inits in the package, so this
 				// doesn't really help us in the TOC.
 				continue
 			}
-			text := src.Name.String()
-			if buildTagLine != "" {
-				text = fmt.Sprintf("%s (%s)", text, buildTagLine)
+			f := &symbol{
+				name:  withTags(src.Name.String(), buildTagLine),
+				color: nameColor,
+				Location: Location{
+					filepath: filepath,
+					position: t.fileSet.Position(src.Pos()),
+				},
 			}
-			name := newName(t.cmdr, t.driver, t.theme, text, nameColor)
-			name.filepath = filepath
-			name.position = t.fileSet.Position(src.Pos())
 			if src.Recv == nil {
-				pkgNode.addFuncs(name)
+				pkg.funcs = append(pkg.funcs, f)
 				continue
 			}
 			if len(src.Recv.List) == 0 {
-				log.Printf("Incorrect definition for %s function\n", text)
+				log.Printf("Incorrect definition for %s function\n", f.name)
+				continue
+			}
+			recvTypeName, ptr, ok := receiver(src.Recv.List[0].Type)
+			if !ok {
+				log.Printf("Could not find the receiver type of method %s\n", f.name)
 				continue
 			}
-			recvTyp := src.Recv.List[0].Type
-			if starExpr, ok := recvTyp.(*ast.StarExpr); ok {
-				recvTyp = starExpr.X
+			recv := recvTypeName
+			if ptr {
+				recv = "*" + recv
 			}
-			recvTypeName := recvTyp.(*ast.Ident).String()
-			pkgNode.addMethod(recvTypeName, name)
+			f.name = fmt.Sprintf("(%s) %s", recv, f.name)
+			typ := pkg.typeNamed(recvTypeName)
+			typ.methods = append(typ.methods, f)
 		}
 	}
-	return pkgNode
+	return pkg
 }
 
-func (t *TOC) parseGenDecl(pkgNode *packageNode, decl *ast.GenDecl, filepath, buildTags string) {
+// A Symbol is a top-level declaration in a go file.
+type Symbol struct {
+	Location
+
+	// Name is the name that the TOC displays for the symbol.
+	// Methods are named with their receiver, e.g. "(*T) M", and
+	// symbols in files with build constraints include them.
+	Name    string
+	Package string
+}
+
+// ParseSymbols parses the go file at path and returns the symbols
+// that the TOC would list for it.  Parsed files are shared with the
+// TOC, and are only parsed again when they change.
+func ParseSymbols(path string) ([]Symbol, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := symbols.load(path, info)
+	if err != nil {
+		return nil, err
+	}
+	var syms []Symbol
+	add := func(s *symbol) {
+		syms = append(syms, Symbol{Location: s.Location, Name: s.name, Package: pkg.name})
+	}
+	for _, group := range [][]*symbol{pkg.consts, pkg.vars, pkg.funcs} {
+		for _, s := range group {
+			add(s)
+		}
+	}
+	for _, typ := range pkg.types {
+		if typ.filepath != "" {
+			// Types that are only known through their methods are
+			// declared in other files.
+			add(typ)
+		}
+		for _, m := range typ.methods {
+			add(m)
+		}
+	}
+	return syms, nil
+}
+
+// receiver returns the name of the type in a method receiver, and
+// whether or not the receiver is a pointer.
+func receiver(expr ast.Expr) (name string, ptr bool, ok bool) {
+	if star, isStar := expr.(*ast.StarExpr); isStar {
+		ptr = true
+		expr = star.X
+	}
+	if index, isIndex := expr.(*ast.IndexExpr); isIndex {
+		// Generic types with a single type parameter.
+		expr = index.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return "", false, false
+	}
+	return ident.String(), ptr, true
+}
+
+func withTags(name, buildTags string) string {
+	if buildTags == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, buildTags)
+}
+
+func (t *fileParser) parseGenDecl(pkg *packageSymbols, decl *ast.GenDecl, filepath, buildTags string) {
 	switch decl.Tok.String() {
 	case "const":
-		pkgNode.addConsts(t.valueNamesFrom(filepath, buildTags, decl.Specs)...)
+		pkg.consts = append(pkg.consts, t.valueSymbolsFrom(filepath, buildTags, decl.Specs)...)
 	case "var":
-		pkgNode.addVars(t.valueNamesFrom(filepath, buildTags, decl.Specs)...)
+		pkg.vars = append(pkg.vars, t.valueSymbolsFrom(filepath, buildTags, decl.Specs)...)
 	case "type":
-		// I have yet to see a case where a type declaration has len(Specs) != 0.
-		typeSpec := decl.Specs[0].(*ast.TypeSpec)
-		name := typeSpec.Name.String()
-		if buildTags != "" {
-			name = fmt.Sprintf("%s (%s)", name, buildTags)
+		for _, spec := range decl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			typ := pkg.typeNamed(typeSpec.Name.String())
+			typ.name = withTags(typeSpec.Name.String(), buildTags)
+			typ.filepath = filepath
+			typ.position = t.fileSet.Position(typeSpec.Pos())
+			switch typeSpec.Type.(type) {
+			case *ast.InterfaceType:
+				typ.kind = interfaceKind
+			case *ast.StructType:
+				typ.kind = structKind
+			default:
+				typ.kind = otherKind
+			}
 		}
-		typ := newName(t.cmdr, t.driver, t.theme, name, nameColor)
-		typ.filepath = filepath
-		typ.position = t.fileSet.Position(typeSpec.Pos())
-		pkgNode.addTypes(typ)
 	}
 }
 
-func (t *TOC) valueNamesFrom(filepath, buildTags string, specs []ast.Spec) (names []*Name) {
+func (t *fileParser) valueSymbolsFrom(filepath, buildTags string, specs []ast.Spec) (syms []*symbol) {
 	for _, spec := range specs {
 		valSpec, ok := spec.(*ast.ValueSpec)
 		if !ok {
@@ -399,17 +723,17 @@ func (t *TOC) valueNamesFrom(filepath, buildTags string, specs []ast.Spec) (name
 				// it in the TOC isn't that useful.
 				continue
 			}
-			n := name.String()
-			if buildTags != "" {
-				n = fmt.Sprintf("%s (%s)", n, buildTags)
-			}
-			node := newName(t.cmdr, t.driver, t.theme, n, nameColor)
-			node.filepath = filepath
-			node.position = t.fileSet.Position(name.Pos())
-			names = append(names, node)
+			syms = append(syms, &symbol{
+				name:  withTags(name.String(), buildTags),
+				color: nameColor,
+				Location: Location{
+					filepath: filepath,
+					position: t.fileSet.Position(name.Pos()),
+				},
+			})
 		}
 	}
-	return names
+	return syms
 }
 
 func findBuildTags(filename string, file *ast.File) (tags []string) {
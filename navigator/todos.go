@@ -0,0 +1,362 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigator
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/fsw"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+	"github.com/nelsam/vidar/setting/ignore"
+	"github.com/nelsam/vidar/todo"
+)
+
+// Todos is a navigator pane that lists the comments in the current
+// project which start with a marker like TODO or FIXME, grouped by
+// file.  The project is scanned in the background and watched for
+// changes, so the list stays up to date as files are edited.
+// Clicking a comment opens its file at the comment.
+type Todos struct {
+	button gxui.Button
+
+	cmdr   Commander
+	driver gxui.Driver
+	theme  *basic.Theme
+
+	layout *searchLayout
+	status gxui.Label
+	list   gxui.LinearLayout
+
+	updates *fsw.Queue
+
+	// mu guards the fields below it, which are used by the scan
+	// and watch goroutines.
+	mu       sync.Mutex
+	project  setting.Project
+	config   setting.Todo
+	roots    []string
+	scanner  *todo.Scanner
+	exclude  *ignore.Patterns
+	watcher  fsw.Watcher
+	scanning bool
+	files    map[string][]todo.Item
+}
+
+// NewTodos creates a todo pane that lists the comments in the default
+// project until a project is opened.
+func NewTodos(cmdr Commander, driver gxui.Driver, theme *basic.Theme) *Todos {
+	t := &Todos{
+		cmdr:   cmdr,
+		driver: driver,
+		theme:  theme,
+		button: createIconButton(driver, theme, "todo.png"),
+		layout: newSearchLayout(theme),
+		status: theme.CreateLabel(),
+		list:   theme.CreateLinearLayout(),
+	}
+	t.updates = fsw.NewQueue(updateDelay, t.update)
+	t.layout.SetDirection(gxui.TopToBottom)
+	t.layout.AddChild(t.status)
+
+	t.list.SetDirection(gxui.TopToBottom)
+	scrollable := theme.CreateScrollLayout()
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(t.list)
+	t.layout.AddChild(scrollable)
+
+	t.load(setting.DefaultProject, setting.DefaultProject.TodoConfig())
+	return t
+}
+
+func (t *Todos) Button() gxui.Button {
+	return t.button
+}
+
+// Frame returns the frame that lists the comments.  If the todo
+// settings have changed since the project was scanned, it's scanned
+// again.
+func (t *Todos) Frame() gxui.Control {
+	t.mu.Lock()
+	project, old := t.project, t.config
+	t.mu.Unlock()
+	if config := project.TodoConfig(); !reflect.DeepEqual(config, old) {
+		t.load(project, config)
+	}
+	t.render()
+	return t.layout
+}
+
+// SetProject scans project for comments in place of the current
+// project.
+func (t *Todos) SetProject(project setting.Project) {
+	t.load(project, project.TodoConfig())
+}
+
+// load stops watching the current project and starts scanning and
+// watching project in the background.  The default project is the
+// whole filesystem, so it's never scanned.
+func (t *Todos) load(project setting.Project, config setting.Todo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.watcher != nil {
+		if err := t.watcher.Close(); err != nil {
+			log.Printf("WARNING: error closing todo watcher: %s", err)
+		}
+	}
+	t.project = project
+	t.config = config
+	t.roots = project.Dirs()
+	t.scanner = todo.New(config.Markers)
+	t.exclude = ignore.New(config.Exclude)
+	t.files = make(map[string][]todo.Item)
+	t.watcher = nil
+	t.scanning = false
+	defer t.driver.Call(t.render)
+	if project.Name == setting.DefaultProject.Name {
+		return
+	}
+	t.scanning = true
+	t.watcher = newTodoWatcher()
+	if t.watcher != nil {
+		go t.watch(t.watcher)
+	}
+	go t.scan(t.watcher, t.roots)
+}
+
+// newTodoWatcher returns a watcher for the todo pane, falling back to
+// polling if the system's watch limit has been reached.  If no watcher
+// can be created, nil is returned and the list is only updated when
+// the project is scanned.
+func newTodoWatcher() fsw.Watcher {
+	w, err := fsw.New()
+	if err == nil {
+		return w
+	}
+	if !fsw.IsWatchLimit(err) {
+		log.Printf("WARNING: could not watch the project for TODOs: %s", err)
+		return nil
+	}
+	return fsw.NewPoller(setting.PollInterval())
+}
+
+// current returns whether or not w is the watcher for the project
+// that is being listed, i.e. whether or not results found for w are
+// still wanted.  t.mu must be held while calling current.
+func (t *Todos) current(w fsw.Watcher) bool {
+	return t.watcher == w
+}
+
+// isCurrent is like current, but locks t.mu.
+func (t *Todos) isCurrent(w fsw.Watcher) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.current(w)
+}
+
+func (t *Todos) scan(w fsw.Watcher, roots []string) {
+	done := status.StartTask("scanning TODOs")
+	defer done()
+	for _, root := range roots {
+		t.walk(w, root, root)
+	}
+
+	t.mu.Lock()
+	if t.current(w) {
+		t.scanning = false
+	}
+	t.mu.Unlock()
+	t.driver.Call(t.render)
+}
+
+// walk scans the files in dir, which is in root, and watches dir and
+// its subdirectories.  Hidden directories and paths that are ignored
+// or excluded are skipped.
+func (t *Todos) walk(w fsw.Watcher, root, dir string) {
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(info.Name(), ".") || t.excluded(root, path, true)) {
+				return filepath.SkipDir
+			}
+			if !t.isCurrent(w) {
+				return filepath.SkipDir
+			}
+			if w != nil {
+				if err := w.Add(path); err != nil {
+					log.Printf("WARNING: could not watch %s for TODOs: %s", path, err)
+				}
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || info.Size() > maxSearchFileSize || t.excluded(root, path, false) {
+			return nil
+		}
+		return t.scanFile(w, path)
+	})
+}
+
+// scanFile finds the comments in the file at path, replacing any that
+// were found before.  It returns filepath.SkipDir once w is no longer
+// current, which stops walks.
+func (t *Todos) scanFile(w fsw.Watcher, path string) error {
+	t.mu.Lock()
+	scanner := t.scanner
+	t.mu.Unlock()
+
+	var items []todo.Item
+	if f, err := os.Open(path); err == nil {
+		items = scanner.Scan(f)
+		f.Close()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.current(w) {
+		return filepath.SkipDir
+	}
+	if len(items) == 0 {
+		delete(t.files, path)
+		return nil
+	}
+	t.files[path] = items
+	return nil
+}
+
+// excluded returns whether or not path, in root, is matched by the
+// ignore patterns or the todo settings' exclude patterns.
+func (t *Todos) excluded(root, path string, isDir bool) bool {
+	if setting.Ignored(root, path, isDir) {
+		return true
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.exclude.Match(filepath.ToSlash(rel), isDir)
+}
+
+func (t *Todos) watch(w fsw.Watcher) {
+	for {
+		e, err := w.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.Printf("WARNING: error from todo watcher: %s", err)
+			continue
+		}
+		t.updates.Push(e.Path)
+	}
+}
+
+// update scans path again after it changed.  New directories are
+// scanned and watched, and the comments of removed paths are dropped.
+func (t *Todos) update(path string) {
+	t.mu.Lock()
+	w, roots := t.watcher, t.roots
+	t.mu.Unlock()
+	root, ok := rootFor(roots, path)
+	if !ok {
+		return
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		t.remove(w, path)
+	case info.IsDir():
+		if !t.excluded(root, path, true) {
+			t.walk(w, root, path)
+		}
+	case info.Mode().IsRegular() && info.Size() <= maxSearchFileSize && !t.excluded(root, path, false):
+		t.scanFile(w, path)
+	default:
+		t.remove(w, path)
+	}
+	t.driver.Call(t.render)
+}
+
+// remove drops the comments in path, or in the files under it if it
+// was a directory.
+func (t *Todos) remove(w fsw.Watcher, path string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.current(w) {
+		return
+	}
+	prefix := path + string(filepath.Separator)
+	for p := range t.files {
+		if p == path || strings.HasPrefix(p, prefix) {
+			delete(t.files, p)
+		}
+	}
+}
+
+// render displays the comments that have been found so far.  It must
+// be called on the UI goroutine.
+func (t *Todos) render() {
+	t.mu.Lock()
+	roots := t.roots
+	scanning := t.scanning
+	isDefault := t.project.Name == setting.DefaultProject.Name
+	paths := make([]string, 0, len(t.files))
+	for path := range t.files {
+		paths = append(paths, path)
+	}
+	files := make(map[string][]todo.Item, len(t.files))
+	for path, items := range t.files {
+		files[path] = items
+	}
+	t.mu.Unlock()
+
+	sort.Strings(paths)
+	t.list.RemoveAll()
+	count := 0
+	for _, path := range paths {
+		t.addFile(roots, path, files[path])
+		count += len(files[path])
+	}
+	switch {
+	case isDefault:
+		t.status.SetText("Open a project to list its TODOs")
+	case scanning:
+		t.status.SetText(fmt.Sprintf("Scanning... %d found", count))
+	case count == 0:
+		t.status.SetText("Nothing to do")
+	default:
+		t.status.SetText(fmt.Sprintf("%d found in %d files", count, len(paths)))
+	}
+}
+
+func (t *Todos) addFile(roots []string, path string, items []todo.Item) {
+	file := newGenericNode(t.driver, t.theme, resultName(roots, path), fileColor)
+	for _, item := range items {
+		text := item.Marker
+		if item.Owner != "" {
+			text += "(" + item.Owner + ")"
+		}
+		if item.Text != "" {
+			text += ": " + item.Text
+		}
+		file.AddChild(newSearchResult(t.cmdr, t.driver, t.theme, path, match{line: item.Line, col: item.Col, text: text}))
+	}
+	t.list.AddChild(file)
+	file.button.Click(gxui.MouseEvent{})
+}
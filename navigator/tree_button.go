@@ -72,11 +72,47 @@ func newTreeButton(driver gxui.Driver, theme *basic.Theme, name string) *treeBut
 	d.OnMouseExit(func(gxui.MouseEvent) { d.Redraw() })
 	d.OnMouseDown(func(gxui.MouseEvent) { d.Redraw() })
 	d.OnMouseUp(func(gxui.MouseEvent) { d.Redraw() })
-	d.OnGainedFocus(d.Redraw)
+	d.OnGainedFocus(func() {
+		if n := d.nav(); n != nil {
+			n.focused = d
+		}
+		d.Redraw()
+	})
 	d.OnLostFocus(d.Redraw)
 	return d
 }
 
+// nav returns the treeNav of the tree that d is in, or nil if d isn't
+// in one.
+func (d *treeButton) nav() *treeNav {
+	var p gxui.Parent = d.Parent()
+	for p != nil {
+		if r, ok := p.(navRoot); ok {
+			return r.treeNav()
+		}
+		c, ok := p.(gxui.Control)
+		if !ok {
+			return nil
+		}
+		p = c.Parent()
+	}
+	return nil
+}
+
+func (d *treeButton) KeyPress(ev gxui.KeyboardEvent) bool {
+	if n := d.nav(); n != nil && n.keyPress(d, ev) {
+		return true
+	}
+	return d.Button.KeyPress(ev)
+}
+
+func (d *treeButton) KeyStroke(ev gxui.KeyStrokeEvent) bool {
+	if n := d.nav(); n != nil && n.keyStroke(d, ev) {
+		return true
+	}
+	return d.Button.KeyStroke(ev)
+}
+
 func (d *treeButton) chooseDropSet() {
 	font := d.theme.DefaultFont()
 	for _, d.dropSet = range preferred {
@@ -130,7 +166,7 @@ func (d *treeButton) Style() (s basic.Style) {
 	if d.IsMouseDown(gxui.MouseButtonLeft) && d.IsMouseOver() {
 		return d.theme.ButtonPressedStyle
 	}
-	if d.IsMouseOver() {
+	if d.IsMouseOver() || d.HasFocus() {
 		return d.theme.ButtonOverStyle
 	}
 	return d.theme.ButtonDefaultStyle
@@ -0,0 +1,184 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package navigator
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nelsam/gxui"
+)
+
+// typeAheadTimeout is how long a tree waits after a character is
+// typed before the next one starts a new type-ahead search.
+const typeAheadTimeout = time.Second
+
+// expandable is a node in a tree which can show and hide its
+// children without clicking its button.
+type expandable interface {
+	showChildren()
+	hideChildren()
+}
+
+// navRoot is the control at the top of a tree of treeButtons, which
+// keeps track of keyboard navigation for the whole tree.
+type navRoot interface {
+	treeNav() *treeNav
+}
+
+// navNode is a treeButton that is currently shown in a tree.
+type navNode struct {
+	button *treeButton
+
+	// owner is the node that button expands, and depth is how many
+	// nodes owner is nested in.
+	owner gxui.Control
+	depth int
+}
+
+// treeNav moves focus between the visible buttons of a tree using
+// the keyboard.  Up and down move to the previous and next button,
+// right and left expand and collapse nodes (or move to a node's first
+// child or its parent), enter clicks the button, and typing moves to
+// the next button that starts with the typed text.
+type treeNav struct {
+	root gxui.Control
+
+	focused *treeButton
+	prefix  string
+	typed   time.Time
+}
+
+// nodes returns the buttons that are shown under n.root, in order.
+func (n *treeNav) nodes() []navNode {
+	return visibleNodes(n.root, 0, nil)
+}
+
+func visibleNodes(ctrl gxui.Control, depth int, nodes []navNode) []navNode {
+	p, ok := ctrl.(parent)
+	if !ok {
+		return nodes
+	}
+	nested := depth
+	for _, c := range p.Children() {
+		if b, ok := c.Control.(*treeButton); ok {
+			nodes = append(nodes, navNode{button: b, owner: ctrl, depth: depth})
+			nested = depth + 1
+			continue
+		}
+		nodes = visibleNodes(c.Control, nested, nodes)
+	}
+	return nodes
+}
+
+func nodeIndex(nodes []navNode, b *treeButton) int {
+	for i, node := range nodes {
+		if node.button == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// focus gives keyboard focus to the button that last had it, or the
+// first button if that one isn't shown any more.  It returns false if
+// there are no buttons to focus.
+func (n *treeNav) focus() bool {
+	if n.focused != nil && n.focused.Attached() {
+		gxui.SetFocus(n.focused)
+		return true
+	}
+	nodes := n.nodes()
+	if len(nodes) == 0 {
+		return false
+	}
+	gxui.SetFocus(nodes[0].button)
+	return true
+}
+
+func (n *treeNav) keyPress(b *treeButton, ev gxui.KeyboardEvent) bool {
+	if ev.Modifier != 0 {
+		return false
+	}
+	nodes := n.nodes()
+	i := nodeIndex(nodes, b)
+	if i == -1 {
+		return false
+	}
+	switch ev.Key {
+	case gxui.KeyUp:
+		focusNode(nodes, i-1)
+	case gxui.KeyDown:
+		focusNode(nodes, i+1)
+	case gxui.KeyHome:
+		focusNode(nodes, 0)
+	case gxui.KeyEnd:
+		focusNode(nodes, len(nodes)-1)
+	case gxui.KeyRight:
+		if e, ok := nodes[i].owner.(expandable); ok && b.Expandable() && !b.Expanded() {
+			e.showChildren()
+			return true
+		}
+		if i+1 < len(nodes) && nodes[i+1].depth > nodes[i].depth {
+			focusNode(nodes, i+1)
+		}
+	case gxui.KeyLeft:
+		if e, ok := nodes[i].owner.(expandable); ok && b.Expanded() {
+			e.hideChildren()
+			return true
+		}
+		for j := i - 1; j >= 0; j-- {
+			if nodes[j].depth < nodes[i].depth {
+				focusNode(nodes, j)
+				break
+			}
+		}
+	case gxui.KeyEnter:
+		b.Click(gxui.MouseEvent{Button: gxui.MouseButtonLeft})
+	default:
+		return false
+	}
+	return true
+}
+
+func focusNode(nodes []navNode, i int) {
+	if i < 0 || i >= len(nodes) {
+		return
+	}
+	gxui.SetFocus(nodes[i].button)
+}
+
+// keyStroke adds the typed character to the type-ahead text and moves
+// to the next button that starts with it.  Typing the same character
+// again moves on to the next match.
+func (n *treeNav) keyStroke(b *treeButton, ev gxui.KeyStrokeEvent) bool {
+	// Space clicks the focused button, like any other button.
+	if ev.Modifier&^gxui.ModShift != 0 || !unicode.IsGraphic(ev.Character) || ev.Character == ' ' {
+		return false
+	}
+	now := time.Now()
+	if now.Sub(n.typed) > typeAheadTimeout {
+		n.prefix = ""
+	}
+	n.typed = now
+	n.prefix += strings.ToLower(string(ev.Character))
+
+	nodes := n.nodes()
+	start := nodeIndex(nodes, b)
+	if start == -1 {
+		start = 0
+	} else if len([]rune(n.prefix)) == 1 {
+		start++
+	}
+	for j := range nodes {
+		i := (start + j) % len(nodes)
+		if strings.HasPrefix(strings.ToLower(nodes[i].button.Text()), n.prefix) {
+			focusNode(nodes, i)
+			break
+		}
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package occurrence finds the other occurrences of the identifier
+// under a caret.  Go code is type checked, so that identifiers are
+// only matched with the ones that refer to the same object (e.g. a
+// shadowed variable isn't matched with the variable it shadows).
+// Other text is matched word by word.
+package occurrence
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Span is a range of runes.
+type Span struct {
+	Start, End int
+}
+
+// Find returns the spans of the other occurrences of the identifier
+// at offset, a rune offset in text, which is the contents of the file
+// at path.  Offsets just past the end of an identifier count as being
+// on it.  Find returns nil if there is no identifier at offset.
+func Find(path string, text []rune, offset int) []Span {
+	if offset < 0 || offset > len(text) {
+		return nil
+	}
+	if filepath.Ext(path) == ".go" {
+		if spans, ok := goOccurrences(string(text), offset); ok {
+			return spans
+		}
+	}
+	return words(text, offset)
+}
+
+// goOccurrences finds the occurrences of the go identifier at offset.
+// It returns false if src can't be parsed, so that words can be
+// matched instead.
+func goOccurrences(src string, offset int) ([]Span, bool) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.AllErrors)
+	if f == nil {
+		return nil, false
+	}
+	if err != nil && len(f.Decls) == 0 {
+		return nil, false
+	}
+	file := fset.File(f.Pos())
+	byteOffset := len(string([]rune(src)[:offset]))
+
+	var target *ast.Ident
+	var idents []*ast.Ident
+	ast.Inspect(f, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		idents = append(idents, id)
+		start, end := file.Offset(id.Pos()), file.Offset(id.End())
+		if start <= byteOffset && byteOffset <= end && id.Name != "_" {
+			target = id
+		}
+		return true
+	})
+	if target == nil {
+		return nil, true
+	}
+
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{
+		// Only this file is checked, so imports and the rest of the
+		// package are unknown.  Identifiers that refer to them
+		// aren't resolved, and are matched by name.
+		Importer: emptyImporter{},
+		Error:    func(error) {},
+	}
+	conf.Check(f.Name.Name, fset, []*ast.File{f}, info)
+
+	obj := info.ObjectOf(target)
+	var found []int
+	for _, id := range idents {
+		if id == target || id.Name != target.Name {
+			continue
+		}
+		if info.ObjectOf(id) != obj {
+			continue
+		}
+		found = append(found, file.Offset(id.Pos()))
+	}
+	return runeSpans(src, found, len(target.Name)), true
+}
+
+// emptyImporter imports every package as an empty package, named
+// after the last element of its path.
+type emptyImporter struct{}
+
+func (emptyImporter) Import(path string) (*types.Package, error) {
+	name := path[strings.LastIndex(path, "/")+1:]
+	pkg := types.NewPackage(path, name)
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+// runeSpans converts the byte offsets in starts, which are in order,
+// to spans of length bytes, in runes.
+func runeSpans(src string, starts []int, length int) []Span {
+	spans := make([]Span, 0, len(starts))
+	prev, runes := 0, 0
+	for _, s := range starts {
+		runes += utf8.RuneCountInString(src[prev:s])
+		n := utf8.RuneCountInString(src[s : s+length])
+		spans = append(spans, Span{Start: runes, End: runes + n})
+		runes += n
+		prev = s + length
+	}
+	return spans
+}
+
+func isWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// words returns the spans of the other occurrences of the whole word
+// at offset in text.
+func words(text []rune, offset int) []Span {
+	start, end := offset, offset
+	for start > 0 && isWord(text[start-1]) {
+		start--
+	}
+	for end < len(text) && isWord(text[end]) {
+		end++
+	}
+	if start == end {
+		return nil
+	}
+	word := text[start:end]
+	var spans []Span
+	for i := 0; i+len(word) <= len(text); i++ {
+		if i == start || !matchAt(text, word, i) {
+			continue
+		}
+		spans = append(spans, Span{Start: i, End: i + len(word)})
+		i += len(word) - 1
+	}
+	return spans
+}
+
+// matchAt returns whether or not word is at i in text as a whole word.
+func matchAt(text, word []rune, i int) bool {
+	if i > 0 && isWord(text[i-1]) {
+		return false
+	}
+	if end := i + len(word); end < len(text) && isWord(text[end]) {
+		return false
+	}
+	for j, r := range word {
+		if text[i+j] != r {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package occurrence_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/occurrence"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal   = matchers.Equal
+	haveLen = matchers.HaveLen
+)
+
+// find calls occurrence.Find with src as runes.
+func find(path, src string, offset int) []occurrence.Span {
+	return occurrence.Find(path, []rune(src), offset)
+}
+
+func TestFind(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Group("go files", func() {
+		const src = `package foo
+
+import "fmt"
+
+func foo(x int) int {
+	y := x
+	{
+		x := "shadowed"
+		fmt.Println(x)
+	}
+	fmt.Println(y)
+	return x + y
+}
+`
+		o.Spec("it matches uses of the same variable", func(expect expect.Expectation) {
+			def := strings.Index(src, "x int")
+			spans := find("foo.go", src, def)
+			expect(spans).To(haveLen(2))
+			expect(spans[0].Start).To(equal(strings.Index(src, "y := x") + 5))
+			expect(spans[1].Start).To(equal(strings.Index(src, "x + y")))
+		})
+
+		o.Spec("it does not conflate shadowed variables", func(expect expect.Expectation) {
+			shadow := strings.Index(src, `x := "`)
+			spans := find("foo.go", src, shadow)
+			expect(spans).To(equal([]occurrence.Span{
+				{Start: strings.Index(src, "(x)") + 1, End: strings.Index(src, "(x)") + 2},
+			}))
+		})
+
+		o.Spec("it matches identifiers when the caret is just past them", func(expect expect.Expectation) {
+			end := strings.Index(src, "y :=") + 1
+			expect(find("foo.go", src, end)).To(haveLen(2))
+		})
+
+		o.Spec("it matches unresolved identifiers by name", func(expect expect.Expectation) {
+			println := strings.Index(src, "Println")
+			expect(find("foo.go", src, println)).To(haveLen(1))
+		})
+
+		o.Spec("it matches imported package names", func(expect expect.Expectation) {
+			fmt := strings.Index(src, "fmt.Println")
+			expect(find("foo.go", src, fmt)).To(haveLen(1))
+		})
+
+		o.Spec("it returns nothing outside of identifiers", func(expect expect.Expectation) {
+			expect(find("foo.go", src, strings.Index(src, `"shadowed"`)+3)).To(haveLen(0))
+		})
+
+		o.Spec("it uses rune offsets", func(expect expect.Expectation) {
+			src := "package foo\n\nvar ü, ẍ = 1, ẍ\n"
+			spans := find("foo.go", src, len([]rune("package foo\n\nvar ü, ")))
+			expect(spans).To(equal([]occurrence.Span{
+				{Start: len([]rune("package foo\n\nvar ü, ẍ = 1, ")), End: len([]rune("package foo\n\nvar ü, ẍ = 1, ẍ"))},
+			}))
+		})
+	})
+
+	o.Group("other files", func() {
+		const src = "foo bar foobar\nbar_foo foo\n"
+
+		o.Spec("it matches whole words", func(expect expect.Expectation) {
+			expect(find("notes.txt", src, 1)).To(equal([]occurrence.Span{
+				{Start: 23, End: 26},
+			}))
+		})
+
+		o.Spec("it returns nothing outside of words", func(expect expect.Expectation) {
+			expect(find("notes.txt", src, len(src))).To(haveLen(0))
+		})
+	})
+}
@@ -2,6 +2,7 @@
 // domain.  For more information, see <http://unlicense.org> or the
 // accompanying UNLICENSE file.
 
-// Package comments contains logic for working with comments.  It's
-// currently go-specific (only works with //).
+// Package comments contains logic for working with comments.  Comment
+// syntax is looked up by file extension (see Register and For), so
+// toggling comments works in any language with a registered syntax.
 package comments
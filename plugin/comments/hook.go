@@ -0,0 +1,27 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package comments
+
+import "github.com/nelsam/vidar/commander/bind"
+
+// Hook is a hook on focus-location which binds a *Toggle to files
+// with a registered comment syntax.
+type Hook struct{}
+
+func (h Hook) Name() string {
+	return "comments-hook"
+}
+
+func (h Hook) OpName() string {
+	return "focus-location"
+}
+
+func (h Hook) FileBindables(path string) []bind.Bindable {
+	s, ok := For(path)
+	if !ok {
+		return nil
+	}
+	return []bind.Bindable{NewToggle(s)}
+}
@@ -5,37 +5,15 @@
 package main
 
 import (
-	"strings"
-
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/vidar/commander/bind"
 	"github.com/nelsam/vidar/plugin/command"
 	"github.com/nelsam/vidar/plugin/comments"
 )
 
-type GolangHook struct {
-}
-
-func (h GolangHook) Name() string {
-	return "golang-hook"
-}
-
-func (h GolangHook) OpName() string {
-	return "focus-location"
-}
-
-func (h GolangHook) FileBindables(path string) []bind.Bindable {
-	if !strings.HasSuffix(path, ".go") {
-		return nil
-	}
-	return []bind.Bindable{
-		comments.NewToggle(),
-	}
-}
-
 // Bindables is the main entry point to the command.
 func Bindables(cmdr command.Commander, driver gxui.Driver, theme gxui.Theme) []bind.Bindable {
 	return []bind.Bindable{
-		GolangHook{},
+		comments.Hook{},
 	}
 }
@@ -0,0 +1,248 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package comments
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// Syntax is the comment syntax of a language.  Languages with line
+// comments have lines commented individually; languages with only
+// block comments have each selection wrapped in a single block.
+type Syntax struct {
+	// Line starts a comment that runs to the end of the line, e.g.
+	// "//" or "#".
+	Line string
+
+	// BlockStart and BlockEnd surround block comments, e.g. "/*"
+	// and "*/".
+	BlockStart, BlockEnd string
+}
+
+var (
+	cLike  = Syntax{Line: "//", BlockStart: "/*", BlockEnd: "*/"}
+	hash   = Syntax{Line: "#"}
+	dashes = Syntax{Line: "--"}
+	markup = Syntax{BlockStart: "<!--", BlockEnd: "-->"}
+
+	syntaxMu sync.RWMutex
+
+	// syntaxes holds comment syntax by file extension, or by file
+	// name for files that are usually named without an extension.
+	syntaxes = map[string]Syntax{
+		".go":    cLike,
+		".c":     cLike,
+		".h":     cLike,
+		".cc":    cLike,
+		".cpp":   cLike,
+		".hpp":   cLike,
+		".java":  cLike,
+		".js":    cLike,
+		".ts":    cLike,
+		".rs":    cLike,
+		".swift": cLike,
+		".kt":    cLike,
+		".proto": cLike,
+		".scss":  cLike,
+		".css":   {BlockStart: "/*", BlockEnd: "*/"},
+
+		".sh":   hash,
+		".bash": hash,
+		".zsh":  hash,
+		".py":   hash,
+		".rb":   hash,
+		".pl":   hash,
+		".yml":  hash,
+		".yaml": hash,
+		".toml": hash,
+		".conf": hash,
+		".mk":   hash,
+
+		"Makefile":   hash,
+		"Dockerfile": hash,
+		".gitignore": hash,
+
+		".sql": {Line: "--", BlockStart: "/*", BlockEnd: "*/"},
+		".lua": dashes,
+		".hs":  dashes,
+
+		".html":     markup,
+		".htm":      markup,
+		".xml":      markup,
+		".svg":      markup,
+		".md":       markup,
+		".markdown": markup,
+	}
+)
+
+// Register sets the comment syntax for files with the extension ext
+// (including the leading dot), replacing any syntax that was already
+// registered.  ext may also be a full file name, e.g. "Makefile".
+func Register(ext string, s Syntax) {
+	syntaxMu.Lock()
+	defer syntaxMu.Unlock()
+	syntaxes[ext] = s
+}
+
+// For returns the comment syntax for the file at path.  File names
+// are checked before extensions, and extensions are matched without
+// regard to case.  The returned bool will be false if path's syntax
+// is unknown.
+func For(path string) (Syntax, bool) {
+	syntaxMu.RLock()
+	defer syntaxMu.RUnlock()
+	base := filepath.Base(path)
+	if s, ok := syntaxes[base]; ok {
+		return s, true
+	}
+	s, ok := syntaxes[strings.ToLower(filepath.Ext(base))]
+	return s, ok
+}
+
+// Toggle returns the edits that comment out, or uncomment, the lines
+// in text[start:end].  start and end should be the start and end of
+// whole lines.  If the lines are already commented, the comment
+// markers are removed; otherwise they're added.  Line comment markers
+// are inserted at the indentation of the least indented line, so
+// that the lines keep their alignment.
+func (s Syntax) Toggle(text []rune, start, end int) []input.Edit {
+	if s.BlockStart != "" {
+		if edits, ok := s.unblock(text, start, end); ok {
+			return edits
+		}
+	}
+	if s.Line != "" {
+		return s.toggleLines(text, start, end)
+	}
+	if s.BlockStart != "" {
+		return s.block(text, start, end)
+	}
+	return nil
+}
+
+func (s Syntax) toggleLines(text []rune, start, end int) []input.Edit {
+	type line struct {
+		start, indent int
+	}
+	var lines []line
+	minIndent := -1
+	commented := true
+	for i := start; i <= end; {
+		lineEnd := i
+		for lineEnd < end && text[lineEnd] != '\n' {
+			lineEnd++
+		}
+		indent := i
+		for indent < lineEnd && isBlank(text[indent]) {
+			indent++
+		}
+		if indent < lineEnd {
+			lines = append(lines, line{start: i, indent: indent - i})
+			if minIndent == -1 || indent-i < minIndent {
+				minIndent = indent - i
+			}
+			if !hasPrefix(text[indent:lineEnd], s.Line) {
+				commented = false
+			}
+		}
+		i = lineEnd + 1
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	edits := make([]input.Edit, 0, len(lines))
+	marker := []rune(s.Line)
+	for _, l := range lines {
+		if !commented {
+			edits = append(edits, input.Edit{
+				At:  l.start + minIndent,
+				New: append(append([]rune(nil), marker...), ' '),
+			})
+			continue
+		}
+		at := l.start + l.indent
+		n := len(marker)
+		if at+n < len(text) && text[at+n] == ' ' {
+			n++
+		}
+		edits = append(edits, input.Edit{
+			At:  at,
+			Old: text[at : at+n],
+		})
+	}
+	return edits
+}
+
+// unblock returns the edits that remove a block comment surrounding
+// text[start:end], ignoring leading and trailing whitespace.  It
+// returns false if text[start:end] isn't a block comment.
+func (s Syntax) unblock(text []rune, start, end int) ([]input.Edit, bool) {
+	first, last := trim(text, start, end)
+	startLen, endLen := len([]rune(s.BlockStart)), len([]rune(s.BlockEnd))
+	if last-first < startLen+endLen {
+		return nil, false
+	}
+	if !hasPrefix(text[first:last], s.BlockStart) || !hasSuffix(text[first:last], s.BlockEnd) {
+		return nil, false
+	}
+	openEnd := first + startLen
+	closeStart := last - endLen
+	if openEnd < closeStart && text[openEnd] == ' ' {
+		openEnd++
+	}
+	if closeStart > openEnd && text[closeStart-1] == ' ' {
+		closeStart--
+	}
+	return []input.Edit{
+		{At: first, Old: text[first:openEnd]},
+		{At: closeStart, Old: text[closeStart:last]},
+	}, true
+}
+
+// block returns the edits that wrap text[start:end] in a block
+// comment, leaving leading and trailing whitespace outside of it.
+func (s Syntax) block(text []rune, start, end int) []input.Edit {
+	first, last := trim(text, start, end)
+	if first == last {
+		return nil
+	}
+	return []input.Edit{
+		{At: first, New: []rune(s.BlockStart + " ")},
+		{At: last, New: []rune(" " + s.BlockEnd)},
+	}
+}
+
+// trim returns the indexes of the first non-blank rune in
+// text[start:end] and the index just after the last one.
+func trim(text []rune, start, end int) (first, last int) {
+	first, last = start, end
+	for first < last && isSpace(text[first]) {
+		first++
+	}
+	for last > first && isSpace(text[last-1]) {
+		last--
+	}
+	return first, last
+}
+
+func isBlank(r rune) bool {
+	return r == ' ' || r == '\t'
+}
+
+func isSpace(r rune) bool {
+	return isBlank(r) || r == '\n' || r == '\r'
+}
+
+func hasPrefix(text []rune, prefix string) bool {
+	return strings.HasPrefix(string(text), prefix)
+}
+
+func hasSuffix(text []rune, suffix string) bool {
+	return strings.HasSuffix(string(text), suffix)
+}
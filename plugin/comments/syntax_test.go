@@ -0,0 +1,108 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package comments_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/comments"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal   = matchers.Equal
+	beTrue  = matchers.BeTrue
+	beFalse = matchers.BeFalse
+)
+
+// apply applies edits to text the same way that the editor does:
+// positions are relative to the original text.
+func apply(text string, edits []input.Edit) string {
+	runes := []rune(text)
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].At > edits[j].At
+	})
+	for _, e := range edits {
+		end := e.At + len(e.Old)
+		runes = append(runes[:e.At], append(append([]rune(nil), e.New...), runes[end:]...)...)
+	}
+	return string(runes)
+}
+
+func toggle(s comments.Syntax, text string) string {
+	runes := []rune(text)
+	return apply(text, s.Toggle(runes, 0, len(runes)))
+}
+
+func TestSyntax(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it finds syntax by extension and by file name", func(expect expect.Expectation) {
+		s, ok := comments.For("/foo/bar.PY")
+		expect(ok).To(beTrue())
+		expect(s.Line).To(equal("#"))
+
+		s, ok = comments.For("/foo/Makefile")
+		expect(ok).To(beTrue())
+		expect(s.Line).To(equal("#"))
+
+		_, ok = comments.For("/foo/bar.unknown")
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it uses registered syntax", func(expect expect.Expectation) {
+		comments.Register(".vidartest", comments.Syntax{Line: ";"})
+		s, ok := comments.For("foo.vidartest")
+		expect(ok).To(beTrue())
+		expect(toggle(s, "foo")).To(equal("; foo"))
+	})
+
+	o.Group("line comments", func() {
+		hash := comments.Syntax{Line: "#"}
+
+		o.Spec("it comments lines at the shallowest indent", func(expect expect.Expectation) {
+			expect(toggle(hash, "  foo:\n    bar: baz\n\n  qux: 1")).To(equal("  # foo:\n  #   bar: baz\n\n  # qux: 1"))
+		})
+
+		o.Spec("it uncomments commented lines", func(expect expect.Expectation) {
+			expect(toggle(hash, "  # foo:\n  #   bar: baz")).To(equal("  foo:\n    bar: baz"))
+		})
+
+		o.Spec("it comments mixed lines", func(expect expect.Expectation) {
+			expect(toggle(hash, "# foo\nbar")).To(equal("# # foo\n# bar"))
+		})
+
+		o.Spec("it prefers line comments over wrapping in a block", func(expect expect.Expectation) {
+			c := comments.Syntax{Line: "//", BlockStart: "/*", BlockEnd: "*/"}
+			expect(toggle(c, "\tfoo()")).To(equal("\t// foo()"))
+		})
+	})
+
+	o.Group("block comments", func() {
+		markup := comments.Syntax{BlockStart: "<!--", BlockEnd: "-->"}
+
+		o.Spec("it wraps lines in a block", func(expect expect.Expectation) {
+			expect(toggle(markup, "  <p>\n  foo\n  </p>")).To(equal("  <!-- <p>\n  foo\n  </p> -->"))
+		})
+
+		o.Spec("it unwraps a block", func(expect expect.Expectation) {
+			expect(toggle(markup, "  <!-- <p>\n  foo\n  </p> -->")).To(equal("  <p>\n  foo\n  </p>"))
+		})
+
+		o.Spec("it unwraps blocks in languages with line comments", func(expect expect.Expectation) {
+			c := comments.Syntax{Line: "//", BlockStart: "/*", BlockEnd: "*/"}
+			expect(toggle(c, "/* foo() */")).To(equal("foo()"))
+		})
+	})
+}
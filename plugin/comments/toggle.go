@@ -6,7 +6,6 @@ package comments
 
 import (
 	"fmt"
-	"regexp"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/vidar/commander/bind"
@@ -25,14 +24,20 @@ type Selecter interface {
 	SelectionSlice() []gxui.TextSelection
 }
 
+// Toggle is a command which comments out, or uncomments, every line
+// touched by the current selections using the comment syntax of the
+// file being edited.
 type Toggle struct {
+	syntax Syntax
+
 	editor   input.Editor
 	applier  Applier
 	selecter Selecter
 }
 
-func NewToggle() *Toggle {
-	return &Toggle{}
+// NewToggle returns a *Toggle that comments lines using s.
+func NewToggle(s Syntax) *Toggle {
+	return &Toggle{syntax: s}
 }
 
 func (c *Toggle) Name() string {
@@ -40,7 +45,7 @@ func (c *Toggle) Name() string {
 }
 
 func (c *Toggle) Menu() string {
-	return "Golang"
+	return "Edit"
 }
 
 func (c *Toggle) Defaults() []fmt.Stringer {
@@ -72,28 +77,44 @@ func (t *Toggle) Store(target interface{}) bind.Status {
 }
 
 func (t *Toggle) Exec() error {
-	selections := t.selecter.SelectionSlice()
-
+	text := t.editor.Runes()
 	var edits []input.Edit
-	for i := len(selections) - 1; i >= 0; i-- {
-		begin, end := selections[i].Start(), selections[i].End()
-		runes := t.editor.Runes()[begin:end]
-		str := string(runes)
-		re, replace := regexpReplace(str)
-		newstr := re.ReplaceAllString(str, replace)
-		edits = append(edits, input.Edit{
-			At:  int(begin),
-			Old: runes,
-			New: []rune(newstr),
-		})
+	for _, r := range lineRanges(text, t.selecter.SelectionSlice()) {
+		edits = append(edits, t.syntax.Toggle(text, r.start, r.end)...)
 	}
 	t.applier.Apply(t.editor, edits...)
 	return nil
 }
 
-func regexpReplace(str string) (*regexp.Regexp, string) {
-	if regexp.MustCompile(`^(\s*?)//`).MatchString(str) {
-		return regexp.MustCompile(`(?m)^(\s*?)//(.*)$`), `${1}${2}`
+type lineRange struct {
+	start, end int
+}
+
+// lineRanges expands each selection to cover the whole lines that it
+// touches, merging selections that share lines.  The returned ranges
+// do not include the trailing newline.
+func lineRanges(text []rune, selections []gxui.TextSelection) []lineRange {
+	var ranges []lineRange
+	for _, s := range selections {
+		start, end := int(s.Start()), int(s.End())
+		if end > start && text[end-1] == '\n' {
+			// A selection ending just after a newline doesn't
+			// touch the next line.
+			end--
+		}
+		for start > 0 && text[start-1] != '\n' {
+			start--
+		}
+		for end < len(text) && text[end] != '\n' {
+			end++
+		}
+		if n := len(ranges); n > 0 && start <= ranges[n-1].end {
+			if end > ranges[n-1].end {
+				ranges[n-1].end = end
+			}
+			continue
+		}
+		ranges = append(ranges, lineRange{start: start, end: end})
 	}
-	return regexp.MustCompile("(?m)^(.*)$"), `//${1}`
+	return ranges
 }
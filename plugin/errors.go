@@ -0,0 +1,73 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package plugin
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// ErrorsName is the name of the command that displays errors from
+// loading plugins.  It is only registered if a plugin failed to
+// load.
+const ErrorsName = "show-plugin-errors"
+
+// LoadError is an error from loading a single plugin.
+type LoadError struct {
+	Path string
+	Err  error
+}
+
+func (e LoadError) Error() string {
+	return fmt.Sprintf("%s: %s", filepath.Base(e.Path), e.Err)
+}
+
+// Errors is a command that displays the errors that happened while
+// loading plugins.
+type Errors struct {
+	status.General
+
+	errs []error
+}
+
+// NewErrors returns an *Errors that will display errs.
+func NewErrors(theme gxui.Theme, errs []error) *Errors {
+	e := &Errors{errs: errs}
+	e.Theme = theme
+	return e
+}
+
+func (e *Errors) Name() string {
+	return ErrorsName
+}
+
+func (e *Errors) Menu() string {
+	return "View"
+}
+
+func (e *Errors) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (e *Errors) Exec(interface{}) bind.Status {
+	msgs := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		msgs = append(msgs, err.Error())
+	}
+	switch len(msgs) {
+	case 0:
+		e.Info = "All plugins loaded successfully"
+	case 1:
+		e.Err = fmt.Sprintf("A plugin failed to load: %s", msgs[0])
+	default:
+		e.Err = fmt.Sprintf("%d plugins failed to load: %s", len(msgs), strings.Join(msgs, "; "))
+	}
+	return bind.Done
+}
@@ -0,0 +1,86 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gobuild
+
+import (
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/nelsam/vidar/setting"
+	"github.com/nelsam/vidar/setting/config"
+)
+
+const configName = "gobuild"
+
+type opener struct{}
+
+func (opener) Open(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+func (opener) Create(path string) (io.WriteCloser, error) {
+	return os.Create(path)
+}
+
+// Command is a command that is run on save.  It is run in the
+// directory of the saved file, with the project's environment.
+type Command struct {
+	Command string
+	Args    []string
+}
+
+func (c Command) String() string {
+	return strings.Join(append([]string{c.Command}, c.Args...), " ")
+}
+
+// Config is the configuration for a single project.
+type Config struct {
+	// Disabled turns off running commands on save.
+	Disabled bool
+
+	// Commands overrides the default commands.
+	Commands []Command
+}
+
+// DefaultCommands is the list of commands that will be run if no
+// commands are configured.  The build output is discarded, since
+// only the errors are interesting.
+var DefaultCommands = []Command{
+	{Command: "go", Args: []string{"build", "-o", os.DevNull, "."}},
+	{Command: "go", Args: []string{"vet", "."}},
+}
+
+// Commands loads the commands to run when a file in the project
+// named project is saved.  Commands for each project are configured
+// in a "gobuild" config file in vidar's config directory, under
+// projects.<name>; the commands for all other projects can be
+// changed with a top level commands list.
+func Commands(project string) []Command {
+	c, err := config.New(opener{}, configName, setting.ConfigDir())
+	if err != nil {
+		log.Printf("Error reading gobuild config: %s", err)
+		return DefaultCommands
+	}
+	c.SetDefault("commands", DefaultCommands)
+	c.SetDefault("projects", map[string]Config(nil))
+	defaults, ok := c.Get("commands").([]Command)
+	if !ok || len(defaults) == 0 {
+		defaults = DefaultCommands
+	}
+	projects, _ := c.Get("projects").(map[string]Config)
+	proj, ok := projects[project]
+	if !ok {
+		return defaults
+	}
+	if proj.Disabled {
+		return nil
+	}
+	if len(proj.Commands) == 0 {
+		return defaults
+	}
+	return proj.Commands
+}
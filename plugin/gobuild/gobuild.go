@@ -0,0 +1,109 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package gobuild contains a hook that runs go build and go vet on
+// the package of each go file that is saved, displaying their output
+// in a panel below the editor.  It can be imported directly or used
+// as a plugin.
+//
+// The commands that are run can be changed per project in a
+// "gobuild" config file in vidar's config directory.
+package gobuild
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// A Paneler is a type that can display panels below the editor.
+type Paneler interface {
+	ShowPanel(gxui.Control)
+	HidePanel(gxui.Control)
+	HasPanel(gxui.Control) bool
+}
+
+// New returns the hook that runs commands on save and the command
+// that shows their output.  They share a single *Pane.
+func New(cmdr command.Commander, driver gxui.Driver, theme gxui.Theme) []bind.Bindable {
+	pane := NewPane(cmdr, driver, theme)
+	show := NewShowOutput(theme, pane)
+	pane.show = show
+	return []bind.Bindable{OnSave{pane: pane}, show}
+}
+
+// OnSave is a hook that runs the configured commands for a project
+// in the directory of each file that is saved.
+type OnSave struct {
+	pane *Pane
+}
+
+func (o OnSave) Name() string {
+	return "gobuild-on-save"
+}
+
+func (o OnSave) OpName() string {
+	return "save-current-file"
+}
+
+func (o OnSave) AfterSave(proj setting.Project, path, _ string) error {
+	cmds := Commands(proj.Name)
+	if len(cmds) == 0 {
+		return nil
+	}
+	o.pane.Start(filepath.Dir(path), proj.GoEnviron(), cmds)
+	return nil
+}
+
+// ShowOutput is a command which shows the output of the last
+// commands that were run on save.
+type ShowOutput struct {
+	status.General
+
+	pane    *Pane
+	paneler Paneler
+}
+
+func NewShowOutput(theme gxui.Theme, pane *Pane) *ShowOutput {
+	s := &ShowOutput{pane: pane}
+	s.Theme = theme
+	return s
+}
+
+func (s *ShowOutput) Name() string {
+	return "show-build-output"
+}
+
+func (s *ShowOutput) Menu() string {
+	return "Golang"
+}
+
+func (s *ShowOutput) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModShift,
+		Key:      gxui.KeyB,
+	}}
+}
+
+func (s *ShowOutput) Reset() {
+	s.paneler = nil
+}
+
+func (s *ShowOutput) Store(elem interface{}) bind.Status {
+	if p, ok := elem.(Paneler); ok {
+		s.paneler = p
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (s *ShowOutput) Exec() error {
+	s.pane.Show(s.paneler)
+	return nil
+}
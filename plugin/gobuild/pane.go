@@ -0,0 +1,218 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gobuild
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/gotest"
+)
+
+var (
+	passColor    = gxui.Color{R: 0.3, G: 0.8, B: 0.3, A: 1}
+	failColor    = gxui.Color{R: 0.9, G: 0.3, B: 0.3, A: 1}
+	commandColor = gxui.Gray60
+	locColor     = gxui.Color{R: 0.4, G: 0.6, B: 1, A: 1}
+)
+
+// Commander is a type that can look up and execute bindables.
+type Commander interface {
+	Bindable(name string) bind.Bindable
+	Execute(bind.Bindable)
+}
+
+// Opener is a type that can create a bindable which focuses a
+// location.
+type Opener interface {
+	For(...focus.Opt) bind.Bindable
+}
+
+// Pane is a panel that displays the output of the commands that are
+// run on save.  File locations in the output can be clicked to open
+// them.
+type Pane struct {
+	mixins.LinearLayout
+
+	cmdr   Commander
+	driver gxui.Driver
+	theme  gxui.Theme
+
+	status  gxui.Label
+	output  gxui.LinearLayout
+	paneler Paneler
+
+	// show is executed to display the pane when commands print
+	// any output, since hooks don't have access to a Paneler.
+	show bind.Bindable
+
+	lock  sync.Mutex
+	stop  chan struct{}
+	lines int
+}
+
+// NewPane creates a *Pane which will use cmdr to open locations.
+func NewPane(cmdr Commander, driver gxui.Driver, theme gxui.Theme) *Pane {
+	p := &Pane{
+		cmdr:   cmdr,
+		driver: driver,
+		theme:  theme,
+		status: theme.CreateLabel(),
+		output: theme.CreateLinearLayout(),
+	}
+	p.Init(p, theme)
+	p.SetDirection(gxui.TopToBottom)
+
+	header := theme.CreateLinearLayout()
+	header.SetDirection(gxui.LeftToRight)
+	closer := theme.CreateButton()
+	closer.SetText("x")
+	closer.OnClick(func(gxui.MouseEvent) {
+		p.Cancel()
+		if p.paneler != nil {
+			p.paneler.HidePanel(p)
+		}
+	})
+	header.AddChild(closer)
+	p.status.SetText("Nothing has been built yet")
+	header.AddChild(p.status)
+	p.AddChild(header)
+
+	p.output.SetDirection(gxui.TopToBottom)
+	scrollable := theme.CreateScrollLayout()
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(p.output)
+	p.AddChild(scrollable)
+	return p
+}
+
+// Show shows p using paneler, if it isn't already shown.
+func (p *Pane) Show(paneler Paneler) {
+	p.paneler = paneler
+	if !paneler.HasPanel(p) {
+		paneler.ShowPanel(p)
+	}
+}
+
+// Start cancels any running commands and runs cmds in dir, one at a
+// time, in the background.  It must be called on the UI goroutine.
+func (p *Pane) Start(dir string, environ []string, cmds []Command) {
+	p.Cancel()
+
+	p.lock.Lock()
+	stop := make(chan struct{})
+	p.stop = stop
+	p.lines = 0
+	p.lock.Unlock()
+
+	p.output.RemoveAll()
+	p.status.SetText(fmt.Sprintf("Building %s...", dir))
+	p.status.SetColor(commandColor)
+	go func() {
+		failed := 0
+		for _, c := range cmds {
+			c := c
+			p.driver.Call(func() {
+				p.addCommand(stop, c)
+			})
+			ok, err := run(stop, dir, environ, c, func(line string) {
+				p.driver.Call(func() {
+					p.addLine(stop, dir, line)
+				})
+			})
+			if err != nil {
+				p.driver.Call(func() {
+					p.finish(stop, fmt.Sprintf("Could not run %s: %s", c, err), failColor)
+				})
+				return
+			}
+			if !ok {
+				failed++
+			}
+		}
+		p.driver.Call(func() {
+			if failed > 0 {
+				p.finish(stop, fmt.Sprintf("%d of %d commands failed", failed, len(cmds)), failColor)
+				return
+			}
+			p.finish(stop, fmt.Sprintf("%d commands passed", len(cmds)), passColor)
+		})
+	}()
+}
+
+// Cancel stops any running commands.
+func (p *Pane) Cancel() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+}
+
+func (p *Pane) current(stop chan struct{}) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.stop != nil && p.stop == stop
+}
+
+func (p *Pane) addCommand(stop chan struct{}, c Command) {
+	if !p.current(stop) {
+		return
+	}
+	l := p.theme.CreateLabel()
+	l.SetText(c.String())
+	l.SetColor(commandColor)
+	p.output.AddChild(l)
+}
+
+func (p *Pane) addLine(stop chan struct{}, dir, line string) {
+	if !p.current(stop) {
+		return
+	}
+	p.lock.Lock()
+	p.lines++
+	first := p.lines == 1
+	p.lock.Unlock()
+
+	l := p.theme.CreateLabel()
+	l.SetText(strings.Replace(line, "\t", "    ", -1))
+	l.SetMargin(math.Spacing{L: 10})
+	p.output.AddChild(l)
+	if loc, ok := gotest.ParseLocation(dir, line); ok {
+		l.SetColor(locColor)
+		l.OnClick(func(gxui.MouseEvent) {
+			p.open(loc)
+		})
+	}
+	if first && p.show != nil {
+		p.cmdr.Execute(p.show)
+	}
+}
+
+func (p *Pane) finish(stop chan struct{}, msg string, color gxui.Color) {
+	if !p.current(stop) {
+		return
+	}
+	p.lock.Lock()
+	p.stop = nil
+	p.lock.Unlock()
+	p.status.SetText(msg)
+	p.status.SetColor(color)
+}
+
+func (p *Pane) open(l gotest.Location) {
+	opener, ok := p.cmdr.Bindable("focus-location").(Opener)
+	if !ok {
+		return
+	}
+	p.cmdr.Execute(opener.For(focus.Path(l.Path), focus.Line(l.Line), focus.Column(l.Column)))
+}
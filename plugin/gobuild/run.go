@@ -0,0 +1,57 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gobuild
+
+import (
+	"bufio"
+	"io"
+	"os/exec"
+)
+
+// run runs c in dir, calling fn with each line of its output.  It
+// returns whether or not c succeeded; err is only non-nil if c could
+// not be run at all.  c will be killed if stop is closed before it
+// finishes.
+func run(stop <-chan struct{}, dir string, environ []string, c Command, fn func(string)) (ok bool, err error) {
+	cmd := exec.Command(c.Command, c.Args...)
+	cmd.Dir = dir
+	cmd.Env = environ
+
+	r, w := io.Pipe()
+	cmd.Stdout = w
+	cmd.Stderr = w
+	if err := cmd.Start(); err != nil {
+		return false, err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+			cmd.Process.Kill()
+		case <-done:
+		}
+	}()
+	waitErr := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		w.Close()
+		waitErr <- err
+	}()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fn(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		r.CloseWithError(err)
+		<-waitErr
+		return false, err
+	}
+	err = <-waitErr
+	if _, isExit := err.(*exec.ExitError); isExit {
+		return false, nil
+	}
+	return err == nil, err
+}
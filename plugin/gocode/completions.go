@@ -7,10 +7,12 @@ package gocode
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/themes/basic"
 	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
 	"github.com/nelsam/vidar/plugin/status"
 )
 
@@ -19,14 +21,22 @@ type TextController interface {
 	Carets() []int
 }
 
+// Lister is any type that knows which editors are open.  Words are
+// suggested from the other open editors with the same file type.
+type Lister interface {
+	OpenEditors() []input.Editor
+}
+
 type Completions struct {
 	status.General
 	gocode *GoCode
+	menu   string
 
 	ctrl      TextController
 	editor    Editor
 	projecter Projecter
 	applier   Applier
+	lister    Lister
 }
 
 func (c *Completions) Name() string {
@@ -34,7 +44,7 @@ func (c *Completions) Name() string {
 }
 
 func (c *Completions) Menu() string {
-	return "Golang"
+	return c.menu
 }
 
 func (c *Completions) Defaults() []fmt.Stringer {
@@ -49,9 +59,15 @@ func (c *Completions) Reset() {
 	c.ctrl = nil
 	c.projecter = nil
 	c.applier = nil
+	c.lister = nil
 }
 
 func (c *Completions) Store(elem interface{}) bind.Status {
+	if l, ok := elem.(Lister); ok {
+		// The lister is optional, and it may also be one of the
+		// required elements.
+		c.lister = l
+	}
 	switch src := elem.(type) {
 	case TextController:
 		c.ctrl = src
@@ -75,6 +91,24 @@ func (c *Completions) Exec() error {
 		return errors.New("completions: cannot show suggestions for multiple carets")
 	}
 	l := newSuggestionList(c.gocode.driver, c.Theme.(*basic.Theme), c.projecter.Project(), c.editor, c.ctrl, c.applier, c.gocode)
+	l.buffers = c.buffers()
 	c.gocode.set(c.editor, l, carets[0])
 	return nil
 }
+
+// buffers returns the text of the other open editors with the same
+// file type as the current editor.
+func (c *Completions) buffers() [][]rune {
+	if c.lister == nil {
+		return nil
+	}
+	path := c.editor.Filepath()
+	var b [][]rune
+	for _, e := range c.lister.OpenEditors() {
+		if e.Filepath() == path || filepath.Ext(e.Filepath()) != filepath.Ext(path) {
+			continue
+		}
+		b = append(b, e.Runes())
+	}
+	return b
+}
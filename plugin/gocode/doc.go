@@ -0,0 +1,74 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gocode
+
+import (
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/suggestion"
+)
+
+// docWidth is the number of characters that documentation is wrapped
+// at in a docPanel.
+const docWidth = 60
+
+// docPanel displays the signature and documentation of the selected
+// suggestion next to the suggestion list.
+type docPanel struct {
+	mixins.LinearLayout
+
+	signature gxui.Label
+	doc       gxui.Label
+}
+
+func newDocPanel(theme *basic.Theme) *docPanel {
+	d := &docPanel{
+		signature: theme.CreateLabel(),
+		doc:       theme.CreateLabel(),
+	}
+	d.LinearLayout.Init(d, theme)
+	d.SetDirection(gxui.TopToBottom)
+	d.SetPadding(math.CreateSpacing(4))
+	d.SetBackgroundBrush(theme.CodeSuggestionListStyle.Brush)
+	d.SetBorderPen(theme.CodeSuggestionListStyle.Pen)
+
+	d.signature.SetFont(theme.DefaultMonospaceFont())
+	d.signature.SetMultiline(true)
+	d.doc.SetMultiline(true)
+	d.doc.SetMargin(math.Spacing{T: 4})
+	d.AddChild(d.signature)
+	d.AddChild(d.doc)
+	return d
+}
+
+// set displays s in d.  It returns false if s has nothing to display.
+func (d *docPanel) set(s suggestion.Suggestion) bool {
+	d.signature.SetText(wrap(s.Signature, docWidth))
+	d.doc.SetText(wrap(s.Doc, docWidth))
+	return s.Signature != "" || s.Doc != ""
+}
+
+// wrap breaks the lines in text so that they're no longer than width,
+// where possible.  Lines are only broken at spaces.
+func wrap(text string, width int) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	var wrapped []string
+	for _, l := range lines {
+		for len(l) > width {
+			i := strings.LastIndex(l[:width], " ")
+			if i <= 0 {
+				break
+			}
+			wrapped = append(wrapped, l[:i])
+			l = l[i+1:]
+		}
+		wrapped = append(wrapped, l)
+	}
+	return strings.Join(wrapped, "\n")
+}
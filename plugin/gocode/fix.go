@@ -0,0 +1,109 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gocode
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/suggestion/quickfix"
+)
+
+// FixEditor is the type of editor that quickfixes can be applied in.
+type FixEditor interface {
+	input.Editor
+	Carets() []int
+}
+
+// Fix is a command which applies one of the quickfixes from
+// suggestion/quickfix at the caret.
+type Fix struct {
+	status.General
+
+	name string
+	fix  func([]rune, int) ([]quickfix.Edit, error)
+
+	editor  FixEditor
+	applier Applier
+}
+
+// NewFixes returns the quickfix commands.
+func NewFixes(theme gxui.Theme) []bind.Bindable {
+	return []bind.Bindable{
+		newFix(theme, quickfix.ErrCheckName, quickfix.ErrCheck),
+		newFix(theme, quickfix.WrapErrorName, quickfix.WrapError),
+		newFix(theme, quickfix.RemoveUnusedName, quickfix.RemoveUnused),
+	}
+}
+
+func newFix(theme gxui.Theme, name string, fix func([]rune, int) ([]quickfix.Edit, error)) *Fix {
+	f := &Fix{name: name, fix: fix}
+	f.Theme = theme
+	return f
+}
+
+func (f *Fix) Name() string {
+	return f.name
+}
+
+func (f *Fix) Menu() string {
+	return "Golang"
+}
+
+func (f *Fix) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (f *Fix) Reset() {
+	f.General.Clear()
+	f.editor = nil
+	f.applier = nil
+}
+
+func (f *Fix) Store(target interface{}) bind.Status {
+	switch src := target.(type) {
+	case FixEditor:
+		f.editor = src
+	case Applier:
+		f.applier = src
+	}
+	if f.editor != nil && f.applier != nil {
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (f *Fix) Exec() error {
+	carets := f.editor.Carets()
+	if len(carets) != 1 {
+		f.Warn = fmt.Sprintf("%s needs exactly one caret; got %d", f.name, len(carets))
+		return nil
+	}
+	text := f.editor.Runes()
+	edits, err := f.fix(text, carets[0])
+	if err != nil {
+		f.Err = fmt.Sprintf("%s: %s", f.name, err)
+		return err
+	}
+	f.applier.Apply(f.editor, fixEdits(text, edits)...)
+	return nil
+}
+
+// fixEdits converts edits from a quickfix to edits that can be
+// applied to text.
+func fixEdits(text []rune, edits []quickfix.Edit) []input.Edit {
+	inputEdits := make([]input.Edit, 0, len(edits))
+	for _, e := range edits {
+		inputEdits = append(inputEdits, input.Edit{
+			At:  e.Start,
+			Old: append([]rune(nil), text[e.Start:e.End]...),
+			New: []rune(e.Text),
+		})
+	}
+	return inputEdits
+}
@@ -15,20 +15,45 @@ import (
 	"github.com/nelsam/vidar/command/caret"
 	"github.com/nelsam/vidar/commander/input"
 	"github.com/nelsam/vidar/setting"
+	"github.com/nelsam/vidar/suggestion"
 )
 
 type Projecter interface {
 	Project() setting.Project
 }
 
+// Source is a function that loads suggestions for the text at
+// runeIndex in contents.  suggestion.For is the default Source.
+type Source func(environ []string, path, contents string, runeIndex int) ([]suggestion.Suggestion, error)
+
 func New(theme *basic.Theme, driver gxui.Driver) (*Completions, *GoCode) {
+	return NewWithSource(theme, driver, suggestion.For)
+}
+
+// NewWithSource is like New, but loads suggestions from src instead
+// of gocode.  This allows other plugins (e.g. language server clients)
+// to reuse the suggestion list.  The words in open files of the same
+// type are suggested along with src's suggestions.
+func NewWithSource(theme *basic.Theme, driver gxui.Driver, src Source) (*Completions, *GoCode) {
+	return newCompletions(theme, driver, src, "Golang")
+}
+
+// NewWords is like New, but only suggests the words in open files of
+// the same type, for files that have no language-aware source.
+func NewWords(theme *basic.Theme, driver gxui.Driver) (*Completions, *GoCode) {
+	return newCompletions(theme, driver, nil, "Edit")
+}
+
+func newCompletions(theme *basic.Theme, driver gxui.Driver, src Source, menu string) (*Completions, *GoCode) {
 	g := GoCode{
+		source:  src,
 		driver:  driver,
 		lists:   make(map[Editor]*suggestionList),
 		cancels: make(map[Editor]func()),
 	}
 	c := Completions{
 		gocode: &g,
+		menu:   menu,
 	}
 	c.Theme = theme
 	return &c, &g
@@ -44,11 +69,17 @@ func ctxCancelled(ctx context.Context) bool {
 }
 
 type GoCode struct {
+	// source may be nil, in which case only words are suggested.
+	source Source
 	driver gxui.Driver
 
 	mu      sync.RWMutex
 	lists   map[Editor]*suggestionList
 	cancels map[Editor]func()
+
+	// recent is shared by every suggestion list, so that symbols
+	// used in one file are ranked higher in the others.
+	recent suggestion.Recent
 }
 
 func (g *GoCode) Name() string {
@@ -91,6 +122,7 @@ func (g *GoCode) show(ctx context.Context, l *suggestionList, pos int) {
 		l.SetSize(cs)
 		c := l.editor.AddChild(l)
 		c.Layout(cs.Rect().Offset(target).Intersect(bounds))
+		l.showDoc()
 		l.Redraw()
 		l.editor.Redraw()
 	})
@@ -162,6 +194,7 @@ func (g *GoCode) Moved(ie input.Editor, carets []int) {
 	ctx, cancel := context.WithCancel(context.Background())
 	g.cancels[e] = cancel
 	e.RemoveChild(l)
+	l.hideDoc()
 	go g.show(ctx, l, pos)
 }
 
@@ -180,6 +213,7 @@ func (g *GoCode) stop(e Editor) bool {
 		if e.Children().Find(l) != nil {
 			e.RemoveChild(l)
 		}
+		l.hideDoc()
 		delete(g.lists, e)
 		return true
 	}
@@ -7,7 +7,11 @@ package gocode
 import (
 	"context"
 	"log"
+	"path"
+	"path/filepath"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/math"
@@ -16,8 +20,14 @@ import (
 	"github.com/nelsam/vidar/commander/input"
 	"github.com/nelsam/vidar/setting"
 	"github.com/nelsam/vidar/suggestion"
+	"github.com/nelsam/vidar/suggestion/literal"
+	"github.com/nelsam/vidar/suggestion/quickfix"
 )
 
+// fixSignature is the signature of suggestions that apply a quickfix
+// rather than completing a word.
+const fixSignature = "quickfix"
+
 type Editor interface {
 	input.Editor
 	gxui.Parent
@@ -45,21 +55,30 @@ type suggestionList struct {
 	ctrl    TextController
 	applier Applier
 	gocode  *GoCode
+	doc     *docPanel
+
+	// buffers is the text of the other open files that words are
+	// suggested from.
+	buffers [][]rune
 }
 
 func newSuggestionList(driver gxui.Driver, theme *basic.Theme, proj setting.Project, editor Editor, ctrl TextController, applier Applier, gocode *GoCode) *suggestionList {
 	s := &suggestionList{
 		driver:  driver,
-		adapter: &suggestion.Adapter{},
+		adapter: &suggestion.Adapter{Recent: &gocode.recent},
 		font:    theme.DefaultMonospaceFont(),
 		project: proj,
 		editor:  editor,
 		ctrl:    ctrl,
 		applier: applier,
 		gocode:  gocode,
+		doc:     newDocPanel(theme),
 	}
 
 	s.Init(s, theme)
+	s.OnSelectionChanged(func(gxui.AdapterItem) {
+		s.showDoc()
+	})
 	s.OnGainedFocus(s.Redraw)
 	s.OnLostFocus(s.Redraw)
 	s.OnKeyPress(func(ev gxui.KeyboardEvent) {
@@ -94,16 +113,26 @@ func (s *suggestionList) show(ctx context.Context, pos int) int {
 		return 0
 	}
 
+	lit, inLit := s.literal(runes, pos)
 	start := pos
-	for start > 0 && wordPart(runes[start-1]) {
-		start--
+	if inLit {
+		start = lit.Start + utf8.RuneCountInString(replaced(lit))
+	} else {
+		for start > 0 && wordPart(runes[start-1]) {
+			start--
+		}
 	}
 	if s.adapter.Pos() != start {
 		if ctxCancelled(ctx) {
 			return 0
 		}
-		suggestion := s.parseSuggestions(runes, start)
-		s.adapter.Set(start, suggestion...)
+		var found []suggestion.Suggestion
+		if inLit {
+			found = s.literalSuggestions(lit)
+		} else {
+			found = append(s.fixSuggestions(runes, start, pos), s.parseSuggestions(runes, start)...)
+		}
+		s.adapter.Set(start, found...)
 	}
 	if ctxCancelled(ctx) {
 		return 0
@@ -124,13 +153,104 @@ func (s *suggestionList) show(ctx context.Context, pos int) int {
 	return s.adapter.Len()
 }
 
+// showDoc displays the documentation for the selected suggestion to
+// the right of s.  It must be called on the UI goroutine.
+func (s *suggestionList) showDoc() {
+	s.hideDoc()
+	selected, ok := s.Selected().(suggestion.Suggestion)
+	if !ok || !s.doc.set(selected) {
+		return
+	}
+	child := s.editor.Children().Find(s)
+	if child == nil {
+		return
+	}
+	bounds := s.editor.Size().Rect().Contract(s.editor.Padding())
+	listBounds := child.Bounds()
+	size := s.doc.DesiredSize(math.ZeroSize, bounds.Size())
+	target := math.Point{X: listBounds.Max.X, Y: listBounds.Min.Y}
+	s.editor.AddChild(s.doc).Layout(size.Rect().Offset(target).Intersect(bounds))
+}
+
+// hideDoc removes s's documentation panel from the editor.
+func (s *suggestionList) hideDoc() {
+	if s.editor.Children().Find(s.doc) != nil {
+		s.editor.RemoveChild(s.doc)
+	}
+}
+
 func (s *suggestionList) parseSuggestions(runes []rune, start int) []suggestion.Suggestion {
-	suggestion, err := suggestion.For(s.project.Environ(), s.editor.Filepath(), string(runes), start)
-	if err != nil {
-		log.Printf("Failed to load suggestion: %s", err)
+	var found []suggestion.Suggestion
+	if s.gocode.source != nil {
+		var err error
+		found, err = s.gocode.source(s.project.GoEnviron(), s.editor.Filepath(), string(runes), start)
+		if err != nil {
+			log.Printf("Failed to load suggestion: %s", err)
+		}
+		if start > 0 && runes[start-1] == '.' {
+			// Only the source knows which fields and methods
+			// make sense after a selector.
+			return found
+		}
+	}
+	return suggestion.Merge(found, suggestion.Words(runes, start, s.buffers...))
+}
+
+// fixSuggestions returns a suggestion for each of the quickfixes that
+// can be applied at pos, if the editor holds go source.
+func (s *suggestionList) fixSuggestions(runes []rune, start, pos int) []suggestion.Suggestion {
+	if !strings.HasSuffix(s.editor.Filepath(), ".go") {
 		return nil
 	}
-	return suggestion
+	var found []suggestion.Suggestion
+	for _, f := range quickfix.At(runes, start, pos) {
+		found = append(found, suggestion.Suggestion{Name: f.Name, Signature: fixSignature, Doc: f.Description})
+	}
+	return found
+}
+
+// literal returns the string literal that pos is in, if the editor
+// holds go source and the literal can be completed: either an import
+// path or something that looks like a file path.
+func (s *suggestionList) literal(runes []rune, pos int) (literal.Literal, bool) {
+	if !strings.HasSuffix(s.editor.Filepath(), ".go") {
+		return literal.Literal{}, false
+	}
+	lit, ok := literal.At(runes, pos)
+	if !ok || (!lit.Import && !literal.LooksLikePath(lit.Text)) {
+		return literal.Literal{}, false
+	}
+	return lit, true
+}
+
+// replaced returns the part of lit's text that is kept when a
+// suggestion is applied.  Import paths are replaced entirely, while
+// file paths are completed one directory at a time.
+func replaced(lit literal.Literal) string {
+	if lit.Import {
+		return ""
+	}
+	dir, _ := path.Split(lit.Text)
+	return dir
+}
+
+// literalSuggestions returns the import paths or file paths that lit
+// can be completed with.  Relative file paths are relative to the
+// project root.
+func (s *suggestionList) literalSuggestions(lit literal.Literal) []suggestion.Suggestion {
+	if lit.Import {
+		return suggestion.ImportPaths(s.project.GoEnviron(), filepath.Dir(s.editor.Filepath()))
+	}
+	_, names := literal.Paths(s.project.Path, lit.Text)
+	found := make([]suggestion.Suggestion, 0, len(names))
+	for _, n := range names {
+		kind := "file"
+		if strings.HasSuffix(n, "/") {
+			kind = "dir"
+		}
+		found = append(found, suggestion.Suggestion{Name: n, Signature: kind})
+	}
+	return found
 }
 
 func (s *suggestionList) apply() {
@@ -144,6 +264,11 @@ func (s *suggestionList) apply() {
 	end := carets[0]
 	runes := s.ctrl.TextRunes()
 
+	s.gocode.recent.Use(suggestion.Name)
+	if suggestion.Signature == fixSignature {
+		s.applyFix(suggestion.Name, runes, start, end)
+		return
+	}
 	if start <= end {
 		go s.applier.Apply(s.editor, input.Edit{
 			At:  start,
@@ -153,6 +278,21 @@ func (s *suggestionList) apply() {
 	}
 }
 
+// applyFix applies the quickfix with the passed in name.  The fixes
+// are found again, since the caret may have moved since the
+// suggestions were listed.
+func (s *suggestionList) applyFix(name string, runes []rune, start, end int) {
+	if start > end {
+		return
+	}
+	for _, f := range quickfix.At(runes, start, end) {
+		if f.Name == name {
+			go s.applier.Apply(s.editor, fixEdits(runes, f.Edits)...)
+			return
+		}
+	}
+}
+
 func wordPart(r rune) bool {
 	return r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r)
 }
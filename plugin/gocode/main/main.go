@@ -31,11 +31,13 @@ func (h GolangHook) FileBindables(path string) []bind.Bindable {
 	if !strings.HasSuffix(path, ".go") {
 		return nil
 	}
+	fixes := gocode.NewFixes(h.Theme)
 	completions, gocode := gocode.New(h.Theme, h.Driver)
-	return []bind.Bindable{
+	b := []bind.Bindable{
 		completions,
 		gocode,
 	}
+	return append(b, fixes...)
 }
 
 // Bindables is the main entry point to the command.
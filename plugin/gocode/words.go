@@ -0,0 +1,37 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gocode
+
+import (
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+)
+
+// WordsHook is a hook on focus-location which binds show-suggestions
+// to every file, suggesting the words in the open files of the same
+// type.  Hooks that are bound later with language-aware sources (e.g.
+// gocode or a language server) replace these bindables for their own
+// files.
+type WordsHook struct {
+	Theme  *basic.Theme
+	Driver gxui.Driver
+}
+
+func (h WordsHook) Name() string {
+	return "words-hook"
+}
+
+func (h WordsHook) OpName() string {
+	return "focus-location"
+}
+
+func (h WordsHook) FileBindables(string) []bind.Bindable {
+	completions, updates := NewWords(h.Theme, h.Driver)
+	return []bind.Bindable{
+		completions,
+		updates,
+	}
+}
@@ -46,6 +46,8 @@ type CursorController interface {
 type Godef struct {
 	status.General
 
+	index *Index
+
 	proj   Projecter
 	cmdr   Commander
 	opener Opener
@@ -53,8 +55,11 @@ type Godef struct {
 	ctrl   CursorController
 }
 
-func New(theme gxui.Theme) *Godef {
-	g := &Godef{}
+// New returns a *Godef which looks up definitions in index, falling
+// back to the godef command for definitions that index can't find.
+// index may be nil, in which case godef is always used.
+func New(theme gxui.Theme, index *Index) *Godef {
+	g := &Godef{index: index}
 	g.Theme = theme
 	return g
 }
@@ -104,11 +109,19 @@ func (g *Godef) Store(target interface{}) bind.Status {
 func (g *Godef) Exec() error {
 	proj := g.proj.Project()
 	lastCaret := g.ctrl.LastCaret()
+	text := g.editor.Text()
+	if g.index != nil {
+		g.index.SetProject(proj)
+		if l, ok := g.index.Find(g.editor.Filepath(), text, lastCaret); ok {
+			g.cmdr.Execute(g.opener.For(focus.Path(l.Path), focus.Line(l.Line), focus.Column(l.Column)))
+			return nil
+		}
+	}
 	cmd := exec.Command("godef", "-f", g.editor.Filepath(), "-o", strconv.Itoa(lastCaret), "-i")
-	cmd.Stdin = bytes.NewBufferString(g.editor.Text())
+	cmd.Stdin = bytes.NewBufferString(text)
 	errBuffer := &bytes.Buffer{}
 	cmd.Stderr = errBuffer
-	cmd.Env = proj.Environ()
+	cmd.Env = proj.GoEnviron()
 	cmd.Dir = filepath.Dir(g.editor.Filepath())
 	output, err := cmd.Output()
 	path, line, col, err := parseGodef(output)
@@ -0,0 +1,238 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package godef
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// HintEditor is an editor that parameter hints can be shown in.
+type HintEditor interface {
+	input.Editor
+	gxui.Parent
+
+	Carets() []int
+	Size() math.Size
+	Padding() math.Spacing
+	LineIndex(caret int) int
+	Line(idx int) mixins.TextBoxLine
+	AddChild(gxui.Control) *gxui.Child
+	RemoveChild(gxui.Control)
+}
+
+// hint is a signature that was found for a call after the text
+// changed, waiting to be shown.
+type hint struct {
+	editor HintEditor
+	caret  int
+	sig    Signature
+	call   Call
+	found  bool
+}
+
+// ParamHints shows the signature of the function being called above
+// the caret while call arguments are typed, highlighting the parameter
+// that the caret is in.  The hint is updated as arguments are typed
+// and hidden when the call's closing paren is typed, when the caret
+// leaves the call, or when the edit is cancelled.
+type ParamHints struct {
+	theme *basic.Theme
+	index *Index
+
+	mu      sync.Mutex
+	pending hint
+
+	// popups is only accessed on the UI goroutine.
+	popups map[HintEditor]*hintPopup
+}
+
+// NewParamHints returns a *ParamHints which finds signatures in index.
+func NewParamHints(theme *basic.Theme, index *Index) *ParamHints {
+	return &ParamHints{
+		theme:  theme,
+		index:  index,
+		popups: make(map[HintEditor]*hintPopup),
+	}
+}
+
+func (h *ParamHints) Name() string {
+	return "param-hints"
+}
+
+func (h *ParamHints) OpNames() []string {
+	return []string{"caret-movement", "input-handler"}
+}
+
+func (h *ParamHints) Init(input.Editor, []rune) {}
+
+// TextChanged looks up the signature of the call that the caret is in
+// after edits.  Hints are only shown while typing with a single caret,
+// so nothing is looked up for multiple edits.
+func (h *ParamHints) TextChanged(ctx context.Context, ie input.Editor, edits []input.Edit) {
+	e, ok := ie.(HintEditor)
+	if !ok {
+		return
+	}
+	next := hint{editor: e}
+	if len(edits) == 1 {
+		next.caret = edits[0].At + len(edits[0].New)
+		next.sig, next.call, next.found = h.index.Signature(e.Filepath(), e.Runes(), next.caret)
+	}
+	select {
+	case <-ctx.Done():
+		return
+	default:
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pending = next
+}
+
+func (h *ParamHints) Apply(input.Editor) error {
+	h.mu.Lock()
+	next := h.pending
+	h.pending = hint{}
+	h.mu.Unlock()
+	if next.editor == nil {
+		return nil
+	}
+	if !next.found {
+		h.hide(next.editor)
+		return nil
+	}
+	p, ok := h.popups[next.editor]
+	if !ok {
+		p = newHintPopup(h.theme)
+		h.popups[next.editor] = p
+	}
+	p.sig, p.call = next.sig, next.call
+	p.show(next.editor, next.caret, next.call.Arg)
+	return nil
+}
+
+// Moved updates the highlighted parameter when the caret moves between
+// the arguments of the hinted call, and hides the hint when it moves
+// out of the call.
+func (h *ParamHints) Moved(ie input.Editor, carets []int) {
+	e, ok := ie.(HintEditor)
+	if !ok {
+		return
+	}
+	p, ok := h.popups[e]
+	if !ok {
+		return
+	}
+	if len(carets) != 1 {
+		h.hide(e)
+		return
+	}
+	call, ok := CallAt(e.Runes(), carets[0])
+	if !ok || call.Paren != p.call.Paren {
+		h.hide(e)
+		return
+	}
+	p.call = call
+	p.show(e, carets[0], call.Arg)
+}
+
+func (h *ParamHints) Cancel(ie input.Editor) bool {
+	e, ok := ie.(HintEditor)
+	if !ok {
+		return false
+	}
+	return h.hide(e)
+}
+
+// hide removes the hint from e, returning whether or not it was
+// shown.
+func (h *ParamHints) hide(e HintEditor) bool {
+	p, ok := h.popups[e]
+	if !ok {
+		return false
+	}
+	delete(h.popups, e)
+	if e.Children().Find(p) == nil {
+		return false
+	}
+	e.RemoveChild(p)
+	e.Redraw()
+	return true
+}
+
+// hintPopup displays a signature with one of its parameters
+// highlighted.
+type hintPopup struct {
+	mixins.LinearLayout
+
+	before, param, after gxui.Label
+
+	sig  Signature
+	call Call
+}
+
+func newHintPopup(theme *basic.Theme) *hintPopup {
+	p := &hintPopup{
+		before: theme.CreateLabel(),
+		param:  theme.CreateLabel(),
+		after:  theme.CreateLabel(),
+	}
+	p.LinearLayout.Init(p, theme)
+	p.SetDirection(gxui.LeftToRight)
+	p.SetPadding(math.CreateSpacing(4))
+	p.SetBackgroundBrush(theme.CodeSuggestionListStyle.Brush)
+	p.SetBorderPen(theme.CodeSuggestionListStyle.Pen)
+	font := theme.DefaultMonospaceFont()
+	for _, l := range []gxui.Label{p.before, p.param, p.after} {
+		l.SetFont(font)
+		p.AddChild(l)
+	}
+	p.param.SetColor(theme.FocusedStyle.Pen.Color)
+	return p
+}
+
+// show displays p's signature in e with the parameter for argument arg
+// highlighted.  p is placed above the line that caret is on, starting
+// at the call's paren if it's on the same line, or below the line if
+// there's no room above it.
+func (p *hintPopup) show(e HintEditor, caret, arg int) {
+	label := []rune(p.sig.Label)
+	start, end := len(label), len(label)
+	if i := p.sig.Param(arg); i >= 0 {
+		start, end = p.sig.Params[i].Start, p.sig.Params[i].End
+	}
+	p.before.SetText(string(label[:start]))
+	p.param.SetText(string(label[start:end]))
+	p.after.SetText(string(label[end:]))
+
+	anchor := caret
+	if e.LineIndex(p.call.Paren) == e.LineIndex(caret) {
+		anchor = p.call.Paren
+	}
+	bounds := e.Size().Rect().Contract(e.Padding())
+	line := e.Line(e.LineIndex(caret))
+	lineOffset := gxui.ChildToParent(math.ZeroPoint, line, e)
+	target := line.PositionAt(anchor).Add(lineOffset)
+	size := p.DesiredSize(math.ZeroSize, bounds.Size())
+	target.Y -= size.H
+	if target.Y < bounds.Min.Y {
+		target.Y = lineOffset.Y + line.Size().H
+	}
+
+	if e.Children().Find(p) != nil {
+		e.RemoveChild(p)
+	}
+	p.SetSize(size)
+	c := e.AddChild(p)
+	c.Layout(size.Rect().Offset(target).Intersect(bounds))
+	p.Redraw()
+	e.Redraw()
+}
@@ -0,0 +1,626 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package godef
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/nelsam/vidar/fsw"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Location is the position of a definition.  Line and Column are
+// zero-indexed, and Column is counted in runes.
+type Location struct {
+	Path   string
+	Line   int
+	Column int
+}
+
+// Index keeps track of where the package-level identifiers (and the
+// methods and fields of package-level types) in a project and its
+// imports are defined.  The project's directories are watched, so
+// that the index stays up to date as files change; imports are only
+// listed again when go.mod changes.
+type Index struct {
+	mu   sync.RWMutex
+	gen  int
+	proj setting.Project
+
+	// imports maps import paths to package directories.
+	imports map[string]string
+
+	// names maps package directories to package names.
+	names map[string]string
+
+	// defs maps package directories to the definitions in each of
+	// their files.
+	defs map[string]map[string]map[string]Location
+
+	watchMu sync.Mutex
+	watcher fsw.Watcher
+}
+
+// NewIndex returns an *Index with no project.  It won't contain any
+// definitions until SetProject is called.
+func NewIndex() *Index {
+	i := &Index{}
+	i.clear()
+	w, err := fsw.New()
+	if err != nil {
+		log.Printf("WARNING: definition index: could not create watcher: %s", err)
+		return i
+	}
+	i.watcher = w
+	go i.watch(w)
+	return i
+}
+
+// SetProject replaces the contents of i with the definitions in p
+// and its imports.  The scan runs in the background, so the index
+// fills in over time.  Calling SetProject with the project that i
+// already contains does nothing.
+func (i *Index) SetProject(p setting.Project) {
+	i.mu.Lock()
+	if p.Name == i.proj.Name && p.Path == i.proj.Path {
+		i.mu.Unlock()
+		return
+	}
+	i.gen++
+	gen := i.gen
+	i.proj = p
+	i.clear()
+	i.mu.Unlock()
+
+	i.watchMu.Lock()
+	if i.watcher != nil {
+		if err := i.watcher.RemoveAll(); err != nil {
+			log.Printf("WARNING: definition index: could not remove watches: %s", err)
+		}
+	}
+	i.watchMu.Unlock()
+
+	if p.Path == "" {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		t := status.Start("indexing definitions", cancel)
+		defer t.Done()
+		i.scan(ctx, gen, p.Path)
+		i.loadImports(ctx, gen, t.Progress)
+	}()
+}
+
+// clear empties i.  i.mu must be held while calling clear.
+func (i *Index) clear() {
+	i.imports = make(map[string]string)
+	i.names = make(map[string]string)
+	i.defs = make(map[string]map[string]map[string]Location)
+}
+
+func (i *Index) current(gen int) bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return gen == i.gen
+}
+
+func (i *Index) root() string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.proj.Path
+}
+
+func (i *Index) within(path string) bool {
+	root := i.root()
+	return root != "" && (path == root || strings.HasPrefix(path, root+string(filepath.Separator)))
+}
+
+// scan parses every go file under dir and watches each directory.
+func (i *Index) scan(ctx context.Context, gen int, dir string) {
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !i.current(gen) {
+			return filepath.SkipDir
+		}
+		if info.IsDir() {
+			if path != dir && i.skip(path) {
+				return filepath.SkipDir
+			}
+			i.add(path)
+			return nil
+		}
+		i.parse(path)
+		return nil
+	})
+}
+
+// loadImports lists the packages that the project depends on and
+// parses the ones that are outside of the project.  If progress is
+// non-nil, it's called with the number of packages that have been
+// loaded so far.
+func (i *Index) loadImports(ctx context.Context, gen int, progress func(done, total int)) {
+	i.mu.RLock()
+	proj := i.proj
+	i.mu.RUnlock()
+
+	cmd := exec.CommandContext(ctx, "go", "list", "-e", "-deps", "-f", "{{.ImportPath}}\t{{.Name}}\t{{.Dir}}", "./...")
+	cmd.Dir = proj.Path
+	cmd.Env = proj.GoEnviron()
+	out, err := cmd.Output()
+	if err != nil && len(out) == 0 {
+		log.Printf("WARNING: definition index: could not list imports: %s", err)
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for n, l := range lines {
+		if ctx.Err() != nil || !i.current(gen) {
+			return
+		}
+		if progress != nil {
+			progress(n, len(lines))
+		}
+		fields := strings.Split(l, "\t")
+		if len(fields) != 3 || fields[2] == "" {
+			continue
+		}
+		importPath, name, dir := fields[0], fields[1], fields[2]
+		i.mu.Lock()
+		i.imports[importPath] = dir
+		i.names[dir] = name
+		_, parsed := i.defs[dir]
+		i.mu.Unlock()
+		if parsed || i.within(dir) {
+			continue
+		}
+		i.parseDir(dir)
+	}
+}
+
+func (i *Index) parseDir(dir string) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, info := range infos {
+		if info.IsDir() || strings.HasSuffix(info.Name(), "_test.go") {
+			continue
+		}
+		i.parse(filepath.Join(dir, info.Name()))
+	}
+}
+
+// parse replaces the definitions for path.  Files that can't be
+// parsed keep the definitions they had when they last parsed, since
+// they're usually in the middle of being edited.
+func (i *Index) parse(path string) {
+	if !strings.HasSuffix(path, ".go") {
+		return
+	}
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, 0)
+	if err != nil {
+		return
+	}
+	defs := Definitions(fset, f, src)
+	dir := filepath.Dir(path)
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.defs[dir] == nil {
+		i.defs[dir] = make(map[string]map[string]Location)
+	}
+	i.defs[dir][path] = defs
+	if _, ok := i.names[dir]; !ok {
+		i.names[dir] = f.Name.Name
+	}
+}
+
+// forget removes path from i.  If path is a directory, every file
+// under it is removed.
+func (i *Index) forget(path string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	prefix := path + string(filepath.Separator)
+	for dir, files := range i.defs {
+		if dir == path || strings.HasPrefix(dir, prefix) {
+			delete(i.defs, dir)
+			continue
+		}
+		delete(files, path)
+	}
+}
+
+// lookup returns the location of name in the package in dir.  If
+// name is defined in more than one file (e.g. foo_linux.go and
+// foo_windows.go), the file that is built in the project's build
+// context is preferred.
+func (i *Index) lookup(dir, name string) (Location, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var (
+		found Location
+		ok    bool
+	)
+	build := i.proj.BuildContext()
+	for path, defs := range i.defs[dir] {
+		l, defined := defs[name]
+		if !defined {
+			continue
+		}
+		if build.Matches(path) {
+			return l, true
+		}
+		found, ok = l, true
+	}
+	return found, ok
+}
+
+// importDir returns the directory of the package imported as
+// importPath and the package's name.
+func (i *Index) importDir(importPath string) (dir, name string, ok bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	dir, ok = i.imports[importPath]
+	if !ok {
+		return "", "", false
+	}
+	return dir, i.names[dir], true
+}
+
+// external returns whether or not the file at path is outside of
+// the project, e.g. in GOROOT or the module cache.  Files like that
+// use packages that the project doesn't import, so their imports are
+// listed and parsed when they're needed rather than up front.
+func (i *Index) external(path string) bool {
+	return i.root() != "" && !i.within(path)
+}
+
+// listImport returns the directory and name of the package imported
+// as importPath from a file in dir, adding it to i's imports.
+func (i *Index) listImport(dir, importPath string) (pkgDir, name string, ok bool) {
+	i.mu.RLock()
+	dep := i.proj
+	i.mu.RUnlock()
+
+	// Whether or not to use module mode depends on where the
+	// importing file is, not where the project is.
+	dep.Path = dir
+	cmd := exec.Command("go", "list", "-e", "-f", "{{.Name}}\t{{.Dir}}", importPath)
+	cmd.Dir = dir
+	cmd.Env = dep.GoEnviron()
+	out, err := cmd.Output()
+	if err != nil {
+		log.Printf("WARNING: definition index: could not list %s: %s", importPath, err)
+		return "", "", false
+	}
+	fields := strings.Split(strings.TrimSpace(string(out)), "\t")
+	if len(fields) != 2 || fields[1] == "" {
+		return "", "", false
+	}
+	name, pkgDir = fields[0], fields[1]
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.imports[importPath] = pkgDir
+	i.names[pkgDir] = name
+	return pkgDir, name, true
+}
+
+// ensureParsed parses the package in dir if it isn't in i yet.
+func (i *Index) ensureParsed(dir string) {
+	i.mu.RLock()
+	_, parsed := i.defs[dir]
+	i.mu.RUnlock()
+	if !parsed {
+		i.parseDir(dir)
+	}
+}
+
+// Find returns the location of the definition of the identifier at
+// caret (a rune offset) in text, which is the contents of the file at
+// path.  Identifiers declared in text are found by parsing it;
+// package-level identifiers from the rest of the package and from
+// imported packages are looked up in i.  Files outside of the
+// project have their package and imports indexed on demand, so that
+// definitions can be followed through dependencies.  The returned
+// bool is false when the definition isn't known, e.g. for methods
+// called on values, which need type information.
+func (i *Index) Find(path, text string, caret int) (Location, bool) {
+	src := []byte(text)
+	offset := byteOffset(text, caret)
+	fset := token.NewFileSet()
+	f, _ := parser.ParseFile(fset, path, src, 0)
+	if f == nil {
+		return Location{}, false
+	}
+	ident, sel := identAt(fset, f, offset)
+	if ident == nil {
+		return Location{}, false
+	}
+	external := i.external(path)
+	if sel != nil {
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok || pkg.Obj != nil {
+			return Location{}, false
+		}
+		for _, imp := range f.Imports {
+			importPath, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			dir, name, ok := i.importDir(importPath)
+			if !ok && external && mayName(imp, importPath, pkg.Name) {
+				dir, name, ok = i.listImport(filepath.Dir(path), importPath)
+			}
+			if !ok {
+				continue
+			}
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if name == pkg.Name {
+				if external {
+					i.ensureParsed(dir)
+				}
+				return i.lookup(dir, ident.Name)
+			}
+		}
+		return Location{}, false
+	}
+	if ident.Obj != nil {
+		pos := ident.Obj.Pos()
+		if !pos.IsValid() {
+			return Location{}, false
+		}
+		return location(fset.Position(pos), src), true
+	}
+	if external {
+		i.ensureParsed(filepath.Dir(path))
+	}
+	return i.lookup(filepath.Dir(path), ident.Name)
+}
+
+// mayName returns whether or not imp, which imports importPath, could
+// be the import named name.  It's used to avoid listing every import
+// of a file when only one of them is needed.
+func mayName(imp *ast.ImportSpec, importPath, name string) bool {
+	if imp.Name != nil {
+		return imp.Name.Name == name
+	}
+	return strings.Contains(path.Base(importPath), name)
+}
+
+// identAt returns the identifier at offset in f.  If the identifier
+// is the selected name in a selector expression (e.g. Bar in
+// foo.Bar), the selector expression is also returned.
+func identAt(fset *token.FileSet, f *ast.File, offset int) (*ast.Ident, *ast.SelectorExpr) {
+	tf := fset.File(f.Pos())
+	if tf == nil || offset > tf.Size() {
+		return nil, nil
+	}
+	pos := tf.Pos(offset)
+	var (
+		ident *ast.Ident
+		sel   *ast.SelectorExpr
+	)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if n == nil || pos < n.Pos() || pos > n.End() {
+			return false
+		}
+		switch src := n.(type) {
+		case *ast.SelectorExpr:
+			if pos >= src.Sel.Pos() {
+				sel = src
+			}
+		case *ast.Ident:
+			ident = src
+			if sel != nil && sel.Sel != src {
+				sel = nil
+			}
+		}
+		return true
+	})
+	if sel != nil && sel.Sel != ident {
+		sel = nil
+	}
+	return ident, sel
+}
+
+// Definitions returns the locations of the package-level identifiers
+// declared in f, which was parsed from src.  Methods, struct fields,
+// and interface methods are keyed as "Type.Name".
+func Definitions(fset *token.FileSet, f *ast.File, src []byte) map[string]Location {
+	defs := make(map[string]Location)
+	add := func(prefix string, ident *ast.Ident) {
+		if ident == nil || ident.Name == "_" {
+			return
+		}
+		defs[prefix+ident.Name] = location(fset.Position(ident.Pos()), src)
+	}
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			prefix := ""
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				prefix = recvName(d.Recv.List[0].Type) + "."
+			}
+			add(prefix, d.Name)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					add("", s.Name)
+					prefix := s.Name.Name + "."
+					switch t := s.Type.(type) {
+					case *ast.StructType:
+						for _, field := range t.Fields.List {
+							for _, name := range field.Names {
+								add(prefix, name)
+							}
+						}
+					case *ast.InterfaceType:
+						for _, method := range t.Methods.List {
+							for _, name := range method.Names {
+								add(prefix, name)
+							}
+						}
+					}
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						add("", name)
+					}
+				}
+			}
+		}
+	}
+	return defs
+}
+
+func recvName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return recvName(t.X)
+	case *ast.IndexExpr:
+		return recvName(t.X)
+	case *ast.IndexListExpr:
+		return recvName(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+func location(pos token.Position, src []byte) Location {
+	l := Location{Path: pos.Filename, Line: pos.Line - 1}
+	start := pos.Offset - (pos.Column - 1)
+	if start >= 0 && pos.Offset <= len(src) {
+		l.Column = utf8.RuneCount(src[start:pos.Offset])
+	}
+	return l
+}
+
+// byteOffset converts the rune offset caret in text to a byte offset.
+func byteOffset(text string, caret int) int {
+	for b := range text {
+		if caret == 0 {
+			return b
+		}
+		caret--
+	}
+	return len(text)
+}
+
+func (i *Index) add(dir string) {
+	i.watchMu.Lock()
+	defer i.watchMu.Unlock()
+	if i.watcher == nil {
+		return
+	}
+	err := i.watcher.Add(dir)
+	if fsw.IsWatchLimit(err) {
+		log.Printf("WARNING: definition index: watch limit reached; falling back to polling")
+		i.startPolling()
+		err = i.watcher.Add(dir)
+	}
+	if err != nil {
+		log.Printf("WARNING: definition index: could not watch %s: %s", dir, err)
+	}
+}
+
+// startPolling replaces i.watcher with a polling watcher.  Since a
+// poller can't take over the old watcher's directories, the project
+// is rescanned.  i.watchMu must be held while calling startPolling.
+func (i *Index) startPolling() {
+	if err := i.watcher.Close(); err != nil {
+		log.Printf("WARNING: definition index: error closing watcher: %s", err)
+	}
+	poller := fsw.NewPoller(setting.PollInterval())
+	i.watcher = poller
+	go i.watch(poller)
+	i.mu.RLock()
+	gen, root := i.gen, i.proj.Path
+	i.mu.RUnlock()
+	if root != "" {
+		go i.scan(context.Background(), gen, root)
+	}
+}
+
+// watch updates i for each event from w, until w is closed.
+func (i *Index) watch(w fsw.Watcher) {
+	for {
+		e, err := w.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.Printf("WARNING: definition index: error from watcher: %s", err)
+			continue
+		}
+		if !i.within(e.Path) {
+			continue
+		}
+		i.mu.RLock()
+		gen := i.gen
+		i.mu.RUnlock()
+		if path.Base(filepath.ToSlash(e.Path)) == "go.mod" {
+			go i.loadImports(context.Background(), gen, nil)
+			continue
+		}
+		switch e.Op {
+		case fsw.Write:
+			i.parse(e.Path)
+		case fsw.Create:
+			info, err := os.Stat(e.Path)
+			if err != nil {
+				continue
+			}
+			if info.IsDir() {
+				if !i.skip(e.Path) {
+					go i.scan(context.Background(), gen, e.Path)
+				}
+				continue
+			}
+			i.parse(e.Path)
+		case fsw.Remove, fsw.Rename:
+			i.forget(e.Path)
+		}
+	}
+}
+
+// skip returns whether or not the directory at path should be left
+// out of the index.
+func (i *Index) skip(path string) bool {
+	return skipDir(filepath.Base(path)) || setting.IgnoredDir(i.root(), path)
+}
+
+// skipDir returns whether or not directories named name should be
+// left out of the index, regardless of the project's settings.
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata"
+}
@@ -0,0 +1,135 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package godef_test
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/plugin/godef"
+	"github.com/nelsam/vidar/setting"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal   = matchers.Equal
+	beTrue  = matchers.BeTrue
+	beFalse = matchers.BeFalse
+)
+
+const src = `package foo
+
+import "fmt"
+
+type Bar struct {
+	Baz string
+}
+
+func (b *Bar) Print() {
+	fmt.Println(b.Baz)
+}
+
+func New() *Bar {
+	ünï := &Bar{}
+	return ünï
+}
+`
+
+func TestDefinitions(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it keys methods and fields by their type", func(expect expect.Expectation) {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "foo.go", src, 0)
+		expect(err).To(equal(nil))
+		defs := godef.Definitions(fset, f, []byte(src))
+		expect(defs["Bar"]).To(equal(godef.Location{Path: "foo.go", Line: 4, Column: 5}))
+		expect(defs["Bar.Baz"]).To(equal(godef.Location{Path: "foo.go", Line: 5, Column: 1}))
+		expect(defs["Bar.Print"]).To(equal(godef.Location{Path: "foo.go", Line: 8, Column: 14}))
+		expect(defs["New"]).To(equal(godef.Location{Path: "foo.go", Line: 12, Column: 5}))
+	})
+}
+
+func TestFind(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *godef.Index) {
+		return expect.New(t), godef.NewIndex()
+	})
+
+	o.Spec("it finds identifiers declared in the same file", func(expect expect.Expectation, i *godef.Index) {
+		caret := len([]rune(src[:strings.Index(src, "*Bar {")])) + 1
+		l, ok := i.Find("/tmp/foo.go", src, caret)
+		expect(ok).To(beTrue())
+		expect(l).To(equal(godef.Location{Path: "/tmp/foo.go", Line: 4, Column: 5}))
+	})
+
+	o.Spec("it counts columns in runes", func(expect expect.Expectation, i *godef.Index) {
+		caret := len([]rune(src[:strings.Index(src, "return ünï")])) + len("return ")
+		l, ok := i.Find("/tmp/foo.go", src, caret)
+		expect(ok).To(beTrue())
+		expect(l).To(equal(godef.Location{Path: "/tmp/foo.go", Line: 13, Column: 1}))
+	})
+
+	o.Spec("it leaves selectors on values to godef", func(expect expect.Expectation, i *godef.Index) {
+		caret := len([]rune(src[:strings.Index(src, "b.Baz")])) + 2
+		_, ok := i.Find("/tmp/foo.go", src, caret)
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it can't find imports that haven't been indexed", func(expect expect.Expectation, i *godef.Index) {
+		caret := len([]rune(src[:strings.Index(src, "Println")]))
+		_, ok := i.Find("/tmp/foo.go", src, caret)
+		expect(ok).To(beFalse())
+	})
+}
+
+func TestFindExternal(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *godef.Index) {
+		i := godef.NewIndex()
+		i.SetProject(setting.Project{Name: "external-test", Path: t.TempDir()})
+		return expect.New(t), i
+	})
+
+	const depSrc = `package fmt
+
+import "strings"
+
+func build() {
+	var b strings.Builder
+	Fprint(&b)
+}
+`
+	dir := filepath.Join(runtime.GOROOT(), "src", "fmt")
+
+	o.Spec("it indexes imports of files outside of the project", func(expect expect.Expectation, i *godef.Index) {
+		caret := len([]rune(depSrc[:strings.Index(depSrc, "Builder")]))
+		l, ok := i.Find(filepath.Join(dir, "build.go"), depSrc, caret)
+		expect(ok).To(beTrue())
+		expect(l.Path).To(equal(filepath.Join(runtime.GOROOT(), "src", "strings", "builder.go")))
+	})
+
+	o.Spec("it indexes the package of files outside of the project", func(expect expect.Expectation, i *godef.Index) {
+		caret := len([]rune(depSrc[:strings.Index(depSrc, "Fprint")]))
+		l, ok := i.Find(filepath.Join(dir, "build.go"), depSrc, caret)
+		expect(ok).To(beTrue())
+		expect(l.Path).To(equal(filepath.Join(dir, "print.go")))
+	})
+}
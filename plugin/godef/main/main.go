@@ -8,19 +8,26 @@ import (
 	"strings"
 
 	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
 	"github.com/nelsam/vidar/commander/bind"
 	"github.com/nelsam/vidar/plugin/command"
 	"github.com/nelsam/vidar/plugin/godef"
+	"github.com/nelsam/vidar/setting"
 )
 
 type GolangHook struct {
-	Theme gxui.Theme
+	Theme       *basic.Theme
+	Definitions *godef.Index
 }
 
 func (h GolangHook) Name() string {
 	return "golang-hook"
 }
 
+func (h GolangHook) SetProject(p setting.Project) {
+	h.Definitions.SetProject(p)
+}
+
 func (h GolangHook) OpName() string {
 	return "focus-location"
 }
@@ -30,13 +37,16 @@ func (h GolangHook) FileBindables(path string) []bind.Bindable {
 		return nil
 	}
 	return []bind.Bindable{
-		godef.New(h.Theme),
+		godef.New(h.Theme, h.Definitions),
+		godef.NewParamHints(h.Theme, h.Definitions),
 	}
 }
 
 // Bindables is the main entry point to the command.
 func Bindables(cmdr command.Commander, driver gxui.Driver, theme gxui.Theme) []bind.Bindable {
+	definitions := godef.NewIndex()
+	definitions.SetProject(setting.DefaultProject)
 	return []bind.Bindable{
-		GolangHook{Theme: theme},
+		GolangHook{Theme: theme.(*basic.Theme), Definitions: definitions},
 	}
 }
@@ -0,0 +1,393 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package godef
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// Call is a call expression that a caret is between the parentheses
+// of.
+type Call struct {
+	// Name is the rune offset of the last rune of the called
+	// function's name.
+	Name int
+
+	// Paren is the rune offset of the call's opening paren.
+	Paren int
+
+	// Arg is the index of the argument that the caret is in.
+	Arg int
+}
+
+// CallAt returns the innermost call whose parentheses caret (a rune
+// offset) is between in text.  Brackets in strings and comments are
+// skipped, and carets inside of composite literals, index expressions,
+// and function declarations are not in a call.
+func CallAt(text []rune, caret int) (Call, bool) {
+	if caret > len(text) {
+		return Call{}, false
+	}
+	type frame struct {
+		open   rune
+		pos    int
+		commas int
+	}
+	var stack []frame
+scan:
+	for i := 0; i < caret; i++ {
+		switch r := text[i]; r {
+		case '/':
+			end := commentEnd(text, i)
+			if end < 0 {
+				continue
+			}
+			if end >= caret {
+				return Call{}, false
+			}
+			i = end
+		case '"', '\'', '`':
+			end := quoteEnd(text, i)
+			if end >= caret {
+				break scan
+			}
+			i = end
+		case '(', '[', '{':
+			stack = append(stack, frame{open: r, pos: i})
+		case ')', ']', '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ',':
+			if len(stack) > 0 {
+				stack[len(stack)-1].commas++
+			}
+		}
+	}
+	if len(stack) == 0 {
+		return Call{}, false
+	}
+	top := stack[len(stack)-1]
+	if top.open != '(' {
+		return Call{}, false
+	}
+	end := top.pos
+	start := end
+	for start > 0 && isIdent(text[start-1]) {
+		start--
+	}
+	if start == end || unicode.IsDigit(text[start]) || token.Lookup(string(text[start:end])).IsKeyword() {
+		return Call{}, false
+	}
+	if declared(text, start) {
+		return Call{}, false
+	}
+	return Call{Name: end - 1, Paren: top.pos, Arg: top.commas}, true
+}
+
+// commentEnd returns the offset of the rune that ends the comment
+// that starts at i in text (the newline, for line comments), or -1 if
+// no comment starts at i.
+func commentEnd(text []rune, i int) int {
+	if i+1 >= len(text) {
+		return -1
+	}
+	switch text[i+1] {
+	case '/':
+		for j := i + 2; j < len(text); j++ {
+			if text[j] == '\n' {
+				return j
+			}
+		}
+		return len(text)
+	case '*':
+		for j := i + 2; j+1 < len(text); j++ {
+			if text[j] == '*' && text[j+1] == '/' {
+				return j + 1
+			}
+		}
+		return len(text)
+	}
+	return -1
+}
+
+// quoteEnd returns the offset of the quote that closes the string or
+// rune literal that starts at i in text.  Unterminated interpreted
+// strings and runes end at the end of their line.
+func quoteEnd(text []rune, i int) int {
+	quote := text[i]
+	for j := i + 1; j < len(text); j++ {
+		switch text[j] {
+		case quote:
+			return j
+		case '\\':
+			if quote != '`' {
+				j++
+			}
+		case '\n':
+			if quote != '`' {
+				return j
+			}
+		}
+	}
+	return len(text)
+}
+
+// declared returns whether or not the name starting at start in text
+// is being declared as a function or method rather than called.
+func declared(text []rune, start int) bool {
+	i := start
+	for i > 0 && (text[i-1] == ' ' || text[i-1] == '\t') {
+		i--
+	}
+	if i == start {
+		return false
+	}
+	if i > 0 && text[i-1] == ')' {
+		// func (r T) name(
+		return true
+	}
+	wordStart := i
+	for wordStart > 0 && isIdent(text[wordStart-1]) {
+		wordStart--
+	}
+	return string(text[wordStart:i]) == "func"
+}
+
+func isIdent(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Signature is a function's signature, formatted for display.
+type Signature struct {
+	// Label is the function's name followed by its parameters and
+	// results, e.g. "Join(elems []string, sep string) string".
+	Label string
+
+	// Params are the rune spans of each parameter in Label.
+	Params []input.Span
+
+	// Variadic is whether or not the last parameter is variadic.
+	Variadic bool
+}
+
+// Param returns the index of the parameter that argument arg of a
+// call is passed to, or -1 if there is no such parameter.
+func (s Signature) Param(arg int) int {
+	switch {
+	case arg < len(s.Params):
+		return arg
+	case s.Variadic:
+		return len(s.Params) - 1
+	}
+	return -1
+}
+
+// Signature returns the signature of the function whose call caret
+// (a rune offset) is in, along with the call itself.  text is the
+// contents of the file at path.  The function is found the same way
+// that Find finds definitions, so only functions that Find can locate
+// have signatures.
+func (i *Index) Signature(path string, text []rune, caret int) (Signature, Call, bool) {
+	call, ok := CallAt(text, caret)
+	if !ok {
+		return Signature{}, Call{}, false
+	}
+	src := string(text)
+	l, ok := i.Find(path, src, call.Name)
+	if !ok {
+		// Unfinished calls often keep the rest of the file from
+		// parsing, so try again with the call closed.
+		closed := string(text[:caret]) + ")" + string(text[caret:])
+		if l, ok = i.Find(path, closed, call.Name); ok {
+			src = closed
+		}
+	}
+	if !ok {
+		return Signature{}, Call{}, false
+	}
+	def := []byte(src)
+	if l.Path != path {
+		var err error
+		if def, err = ioutil.ReadFile(l.Path); err != nil {
+			return Signature{}, Call{}, false
+		}
+	}
+	fset := token.NewFileSet()
+	f, _ := parser.ParseFile(fset, l.Path, def, 0)
+	if f == nil {
+		return Signature{}, Call{}, false
+	}
+	ident, _ := identAt(fset, f, offsetOf(def, l))
+	if ident == nil {
+		return Signature{}, Call{}, false
+	}
+	typ := funcType(f, ident)
+	if typ == nil {
+		return Signature{}, Call{}, false
+	}
+	return format(fset, ident.Name, typ), call, true
+}
+
+// offsetOf returns the byte offset of l in src.
+func offsetOf(src []byte, l Location) int {
+	offset := 0
+	for line := 0; line < l.Line; line++ {
+		i := bytes.IndexByte(src[offset:], '\n')
+		if i < 0 {
+			return len(src)
+		}
+		offset += i + 1
+	}
+	for col := 0; col < l.Column && offset < len(src); col++ {
+		_, size := utf8.DecodeRune(src[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// funcType returns the type of the function that ident declares in
+// f, or nil if ident isn't declared as a function.  Functions,
+// methods, interface methods, func-typed fields and parameters, and
+// variables assigned function literals are supported.
+func funcType(f *ast.File, ident *ast.Ident) *ast.FuncType {
+	var typ *ast.FuncType
+	ast.Inspect(f, func(n ast.Node) bool {
+		if typ != nil || n == nil || ident.Pos() < n.Pos() || ident.Pos() > n.End() {
+			return false
+		}
+		switch d := n.(type) {
+		case *ast.FuncDecl:
+			if d.Name == ident {
+				typ = d.Type
+			}
+		case *ast.Field:
+			if t, ok := d.Type.(*ast.FuncType); ok && hasIdent(d.Names, ident) {
+				typ = t
+			}
+		case *ast.ValueSpec:
+			for n, name := range d.Names {
+				if name != ident {
+					continue
+				}
+				if t, ok := d.Type.(*ast.FuncType); ok {
+					typ = t
+				} else if n < len(d.Values) {
+					typ = litType(d.Values[n])
+				}
+			}
+		case *ast.AssignStmt:
+			if len(d.Lhs) != len(d.Rhs) {
+				break
+			}
+			for n, lhs := range d.Lhs {
+				if lhs == ident {
+					typ = litType(d.Rhs[n])
+				}
+			}
+		}
+		return typ == nil
+	})
+	return typ
+}
+
+func hasIdent(names []*ast.Ident, ident *ast.Ident) bool {
+	for _, name := range names {
+		if name == ident {
+			return true
+		}
+	}
+	return false
+}
+
+func litType(e ast.Expr) *ast.FuncType {
+	if lit, ok := e.(*ast.FuncLit); ok {
+		return lit.Type
+	}
+	return nil
+}
+
+// format returns the signature of the function named name with type
+// typ.  Parameters that share a type are listed separately, so that
+// each argument has its own parameter to highlight.
+func format(fset *token.FileSet, name string, typ *ast.FuncType) Signature {
+	var (
+		s Signature
+		b strings.Builder
+	)
+	b.WriteString(name)
+	b.WriteString("(")
+	for _, field := range typ.Params.List {
+		t := node(fset, field.Type)
+		_, s.Variadic = field.Type.(*ast.Ellipsis)
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{nil}
+		}
+		for _, n := range names {
+			if len(s.Params) > 0 {
+				b.WriteString(", ")
+			}
+			start := utf8.RuneCountInString(b.String())
+			if n != nil {
+				b.WriteString(n.Name)
+				b.WriteString(" ")
+			}
+			b.WriteString(t)
+			s.Params = append(s.Params, input.Span{Start: start, End: utf8.RuneCountInString(b.String())})
+		}
+	}
+	b.WriteString(")")
+	if typ.Results != nil && len(typ.Results.List) > 0 {
+		results := typ.Results.List
+		if len(results) == 1 && len(results[0].Names) == 0 {
+			b.WriteString(" ")
+			b.WriteString(node(fset, results[0].Type))
+		} else {
+			b.WriteString(" (")
+			b.WriteString(fields(fset, results))
+			b.WriteString(")")
+		}
+	}
+	s.Label = b.String()
+	return s
+}
+
+// fields formats list the way it's written in source, e.g.
+// "n int, err error".
+func fields(fset *token.FileSet, list []*ast.Field) string {
+	formatted := make([]string, 0, len(list))
+	for _, field := range list {
+		t := node(fset, field.Type)
+		if len(field.Names) == 0 {
+			formatted = append(formatted, t)
+			continue
+		}
+		names := make([]string, 0, len(field.Names))
+		for _, n := range field.Names {
+			names = append(names, n.Name)
+		}
+		formatted = append(formatted, strings.Join(names, ", ")+" "+t)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func node(fset *token.FileSet, n ast.Node) string {
+	var b bytes.Buffer
+	if err := printer.Fprint(&b, fset, n); err != nil {
+		return ""
+	}
+	return b.String()
+}
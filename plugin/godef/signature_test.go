@@ -0,0 +1,185 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package godef_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/godef"
+	"github.com/nelsam/vidar/setting"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+)
+
+func TestCallAt(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	// callAt returns the call at the | in text.
+	callAt := func(text string) (godef.Call, bool) {
+		caret := strings.Index(text, "|")
+		runes := []rune(text[:caret] + text[caret+1:])
+		return godef.CallAt(runes, len([]rune(text[:caret])))
+	}
+
+	o.Spec("it counts the arguments before the caret", func(expect expect.Expectation) {
+		c, ok := callAt("foo(a, b|")
+		expect(ok).To(beTrue())
+		expect(c).To(equal(godef.Call{Name: 2, Paren: 3, Arg: 1}))
+	})
+
+	o.Spec("it skips nested brackets, strings, and comments", func(expect expect.Expectation) {
+		c, ok := callAt(`foo(bar(x, y), []int{1, 2}, "(,", ',', /* , */ |`)
+		expect(ok).To(beTrue())
+		expect(c.Paren).To(equal(3))
+		expect(c.Arg).To(equal(4))
+	})
+
+	o.Spec("it finds calls on selected names", func(expect expect.Expectation) {
+		c, ok := callAt("strings.Join(x, ü|")
+		expect(ok).To(beTrue())
+		expect(c).To(equal(godef.Call{Name: 11, Paren: 12, Arg: 1}))
+	})
+
+	o.Spec("it finds calls while the caret is in a string argument", func(expect expect.Expectation) {
+		c, ok := callAt(`foo(a, "b, |c")`)
+		expect(ok).To(beTrue())
+		expect(c.Arg).To(equal(1))
+	})
+
+	o.Spec("it ends at the closing paren", func(expect expect.Expectation) {
+		_, ok := callAt("foo(a, b)|")
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it ignores carets in comments", func(expect expect.Expectation) {
+		_, ok := callAt("foo(a, // b|\n")
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it ignores composite literals and keywords", func(expect expect.Expectation) {
+		_, ok := callAt("foo(Bar{a, |")
+		expect(ok).To(beFalse())
+		_, ok = callAt("if (a|")
+		expect(ok).To(beFalse())
+		_, ok = callAt("x := func(a |")
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it ignores declarations", func(expect expect.Expectation) {
+		_, ok := callAt("func foo(a |")
+		expect(ok).To(beFalse())
+		_, ok = callAt("func (b *Bar) foo(a |")
+		expect(ok).To(beFalse())
+	})
+}
+
+const sigSrc = `package foo
+
+import "strings"
+
+type Bar struct {
+	OnDone func(err error)
+}
+
+func Sum(a, b int, rest ...int) (total int, ok bool) {
+	return 0, true
+}
+
+func use(b Bar) {
+	add := func(x float64) {}
+	Sum(1, 2, 3, 4)
+	add(2.0)
+	strings.Join(nil, "")
+}
+`
+
+func TestSignature(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *godef.Index) {
+		return expect.New(t), godef.NewIndex()
+	})
+
+	// caretAfter returns the rune offset just after the first
+	// occurrence of s in sigSrc.
+	caretAfter := func(s string) int {
+		return len([]rune(sigSrc[:strings.Index(sigSrc, s)+len(s)]))
+	}
+
+	o.Spec("it formats each parameter separately", func(expect expect.Expectation, i *godef.Index) {
+		s, c, ok := i.Signature("/tmp/foo.go", []rune(sigSrc), caretAfter("Sum(1, "))
+		expect(ok).To(beTrue())
+		expect(c.Arg).To(equal(1))
+		expect(s.Label).To(equal("Sum(a int, b int, rest ...int) (total int, ok bool)"))
+		expect(s.Params).To(equal([]input.Span{{Start: 4, End: 9}, {Start: 11, End: 16}, {Start: 18, End: 29}}))
+		expect(s.Variadic).To(beTrue())
+	})
+
+	o.Spec("it highlights the variadic parameter for extra arguments", func(expect expect.Expectation, i *godef.Index) {
+		s, c, ok := i.Signature("/tmp/foo.go", []rune(sigSrc), caretAfter("Sum(1, 2, 3, "))
+		expect(ok).To(beTrue())
+		expect(s.Param(c.Arg)).To(equal(2))
+	})
+
+	o.Spec("it finds local function literals", func(expect expect.Expectation, i *godef.Index) {
+		s, _, ok := i.Signature("/tmp/foo.go", []rune(sigSrc), caretAfter("add("))
+		expect(ok).To(beTrue())
+		expect(s.Label).To(equal("add(x float64)"))
+		expect(s.Param(1)).To(equal(-1))
+	})
+
+	o.Spec("it finds signatures while the call is unfinished", func(expect expect.Expectation, i *godef.Index) {
+		text := strings.Replace(sigSrc, "Sum(1, 2, 3, 4)", "Sum(1, ", 1)
+		caret := len([]rune(text[:strings.Index(text, "Sum(1, ")+len("Sum(1, ")]))
+		s, c, ok := i.Signature("/tmp/foo.go", []rune(text), caret)
+		expect(ok).To(beTrue())
+		expect(c.Arg).To(equal(1))
+		expect(s.Label).To(equal("Sum(a int, b int, rest ...int) (total int, ok bool)"))
+	})
+
+	o.Spec("it has no signature for calls it can't find", func(expect expect.Expectation, i *godef.Index) {
+		_, _, ok := i.Signature("/tmp/foo.go", []rune(sigSrc), caretAfter("Join("))
+		expect(ok).To(beFalse())
+	})
+}
+
+func TestSignatureExternal(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *godef.Index) {
+		i := godef.NewIndex()
+		i.SetProject(setting.Project{Name: "signature-test", Path: t.TempDir()})
+		return expect.New(t), i
+	})
+
+	const depSrc = `package fmt
+
+import "strings"
+
+func build() {
+	strings.Join(nil,
+}
+`
+	path := filepath.Join(runtime.GOROOT(), "src", "fmt", "build.go")
+
+	o.Spec("it reads signatures from other files", func(expect expect.Expectation, i *godef.Index) {
+		caret := len([]rune(depSrc[:strings.Index(depSrc, "nil,")+len("nil,")]))
+		s, c, ok := i.Signature(path, []rune(depSrc), caret)
+		expect(ok).To(beTrue())
+		expect(s.Label).To(equal("Join(elems []string, sep string) string"))
+		expect(s.Param(c.Arg)).To(equal(1))
+	})
+}
@@ -6,19 +6,30 @@ package goimports
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/vidar/commander/bind"
 	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/command"
 	"github.com/nelsam/vidar/plugin/status"
 	"github.com/nelsam/vidar/setting"
 )
 
-const stdinPathPattern = "<standard input>:"
+const (
+	stdinPathPattern = "<standard input>:"
+
+	// timeout is how long goimports may run before it's cancelled.
+	// It's mostly slow when the module cache is cold.
+	timeout = 30 * time.Second
+)
 
 type Projecter interface {
 	Project() setting.Project
@@ -28,19 +39,152 @@ type Applier interface {
 	Apply(input.Editor, ...input.Edit)
 }
 
+// An EditorSaver is a save command that can save a specific editor.
+type EditorSaver interface {
+	ForEditor(input.Editor) bind.Bindable
+}
+
+// OnSave is a hook that runs goimports on each go file after it's
+// saved.  goimports runs in the background, so a slow run doesn't
+// hold up the save; when it finishes, its changes are applied and the
+// file is saved again, unless the text was edited in the meantime.
 type OnSave struct {
+	cmdr   command.Commander
+	driver gxui.Driver
+
+	// running holds the run in progress for each path, so that it
+	// can be cancelled when the file is saved again.  applied holds
+	// the text that the last run changed each path to, so that the
+	// save which writes the changes doesn't start another run.  They
+	// are only accessed on the UI goroutine.
+	running map[string]*run
+	applied map[string]string
+}
+
+// run is a goimports run in progress.  It's a pointer so that a
+// finished run can tell whether it has been replaced by a newer one.
+type run struct {
+	cancel func()
 }
 
-func (o OnSave) Name() string {
+// NewOnSave returns an OnSave which executes its changes with cmdr.
+// It should only be created once, since it keeps track of the runs
+// for every file.
+func NewOnSave(cmdr command.Commander, driver gxui.Driver) *OnSave {
+	return &OnSave{
+		cmdr:    cmdr,
+		driver:  driver,
+		running: make(map[string]*run),
+		applied: make(map[string]string),
+	}
+}
+
+func (o *OnSave) Name() string {
 	return "goimports-on-save"
 }
 
-func (o OnSave) OpName() string {
+func (o *OnSave) OpName() string {
 	return "save-current-file"
 }
 
-func (o OnSave) BeforeSave(proj setting.Project, path, text string) (newText string, err error) {
-	return goimports(path, text, proj.Environ())
+// BackgroundSave starts goimports on text, unless it is disabled for
+// proj.  A run that is still going for e's file is cancelled, since
+// its output is already out of date.
+func (o *OnSave) BackgroundSave(proj setting.Project, e input.Editor, text string) {
+	path := e.Filepath()
+	if prev, ok := o.running[path]; ok {
+		prev.cancel()
+		delete(o.running, path)
+	}
+	applied, ok := o.applied[path]
+	delete(o.applied, path)
+	if (ok && applied == text) || proj.GoimportsConfig().Disabled {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	r := &run{cancel: cancel}
+	o.running[path] = r
+	go func() {
+		defer cancel()
+		formatted, err := goimports(ctx, path, text, proj)
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("goimports: timed out after %s; %s was saved without it", timeout, filepath.Base(path))
+		}
+		if ctx.Err() == context.Canceled {
+			return
+		}
+		o.driver.Call(func() {
+			if o.running[path] != r {
+				return
+			}
+			delete(o.running, path)
+			o.finish(e, text, formatted, err)
+		})
+	}()
+}
+
+// finish applies the result of running goimports on text to e.  The
+// result is thrown out if e's text has changed since goimports
+// started, since it would undo the changes.
+func (o *OnSave) finish(e input.Editor, text, formatted string, err error) {
+	if e.Text() != text {
+		return
+	}
+	var diags []input.Diagnostic
+	if gErr, ok := err.(Error); ok {
+		diags = gErr.Diagnostics()
+	}
+	e.SetDiagnostics(o.Name(), diags)
+	if err != nil {
+		status.Warn(err.Error())
+		return
+	}
+	if formatted == text {
+		return
+	}
+	saver, ok := o.cmdr.Bindable("save-current-file").(EditorSaver)
+	if !ok {
+		status.Warn("goimports: save-current-file cannot save specific editors")
+		return
+	}
+	o.cmdr.Execute(&apply{editor: e, from: text, to: formatted})
+	o.applied[e.Filepath()] = formatted
+	o.cmdr.Execute(saver.ForEditor(e))
+}
+
+// apply is an op which replaces the text of an editor with the output
+// of goimports.
+type apply struct {
+	editor   input.Editor
+	from, to string
+
+	applier Applier
+}
+
+func (a *apply) Name() string {
+	return "goimports-apply"
+}
+
+func (a *apply) Reset() {
+	a.applier = nil
+}
+
+func (a *apply) Store(target interface{}) bind.Status {
+	applier, ok := target.(Applier)
+	if !ok {
+		return bind.Waiting
+	}
+	a.applier = applier
+	return bind.Done
+}
+
+func (a *apply) Exec() error {
+	a.applier.Apply(a.editor, input.Edit{
+		At:  0,
+		Old: []rune(a.from),
+		New: []rune(a.to),
+	})
+	return nil
 }
 
 type GoImports struct {
@@ -96,7 +240,12 @@ func (gi *GoImports) Store(target interface{}) bind.Status {
 func (gi *GoImports) Exec() error {
 	proj := gi.projecter.Project()
 	text := gi.editor.Text()
-	formatted, err := goimports(gi.editor.Filepath(), text, proj.Environ())
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	formatted, err := goimports(ctx, gi.editor.Filepath(), text, proj)
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("goimports timed out after %s", timeout)
+	}
 	if err != nil {
 		gi.Err = err.Error()
 		return err
@@ -109,12 +258,35 @@ func (gi *GoImports) Exec() error {
 	return nil
 }
 
-func goimports(path, text string, env []string) (newText string, err error) {
-	cmd := exec.Command("goimports")
+// Error is an error from goimports.  Any errors that refer to a
+// position in the source are included as diagnostics.
+type Error struct {
+	msg   string
+	diags []input.Diagnostic
+}
+
+func (e Error) Error() string {
+	return "goimports: " + e.msg
+}
+
+// Diagnostics returns the diagnostics parsed from goimports' output.
+func (e Error) Diagnostics() []input.Diagnostic {
+	return e.diags
+}
+
+func goimports(ctx context.Context, path, text string, proj setting.Project) (newText string, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer status.Start("goimports", cancel).Done()
+	var args []string
+	if local := proj.GoimportsConfig().Local; local != "" {
+		args = append(args, "-local", local)
+	}
+	cmd := exec.CommandContext(ctx, "goimports", args...)
 	cmd.Stdin = bytes.NewBufferString(text)
 	errBuffer := &bytes.Buffer{}
 	cmd.Stderr = errBuffer
-	cmd.Env = env
+	cmd.Env = proj.GoEnviron()
 	cmd.Dir = filepath.Dir(path)
 	formatted, err := cmd.Output()
 	if err != nil {
@@ -122,12 +294,67 @@ func goimports(path, text string, env []string) (newText string, err error) {
 		if msg == "" {
 			return "", err
 		}
-		stdinPatternStart := strings.Index(msg, stdinPathPattern)
-		if stdinPatternStart > 0 {
-			pathEnd := stdinPatternStart + len(stdinPathPattern)
-			msg = msg[pathEnd:]
-		}
-		return "", fmt.Errorf("goimports: %s", msg)
+		return "", newError(text, msg)
 	}
 	return string(formatted), nil
 }
+
+// newError parses the stderr output of goimports into an Error.
+// Lines in the format "<standard input>:line:col: message" are
+// converted to diagnostics in text.
+func newError(text, output string) Error {
+	var (
+		msgs  []string
+		diags []input.Diagnostic
+	)
+	for _, l := range strings.Split(strings.TrimSpace(output), "\n") {
+		if i := strings.Index(l, stdinPathPattern); i >= 0 {
+			l = l[i+len(stdinPathPattern):]
+		}
+		msgs = append(msgs, l)
+		parts := strings.SplitN(l, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		line, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+		col, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		start := offset(text, line, col)
+		diags = append(diags, input.Diagnostic{
+			Severity: input.SeverityError,
+			Range:    input.Span{Start: start, End: start},
+			Message:  strings.TrimSpace(parts[2]),
+		})
+	}
+	return Error{msg: strings.Join(msgs, "; "), diags: diags}
+}
+
+// offset returns the rune offset in text of the 1-indexed line and
+// byte column that goimports reports.
+func offset(text string, line, col int) int {
+	byteOffset := 0
+	for ; line > 1; line-- {
+		next := strings.IndexByte(text[byteOffset:], '\n')
+		if next < 0 {
+			byteOffset = len(text)
+			break
+		}
+		byteOffset += next + 1
+	}
+	lineEnd := len(text)
+	if next := strings.IndexByte(text[byteOffset:], '\n'); next >= 0 {
+		lineEnd = byteOffset + next
+	}
+	if col > 1 {
+		byteOffset += col - 1
+	}
+	if byteOffset > lineEnd {
+		byteOffset = lineEnd
+	}
+	return utf8.RuneCountInString(text[:byteOffset])
+}
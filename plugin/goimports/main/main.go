@@ -14,7 +14,8 @@ import (
 )
 
 type GolangHook struct {
-	Theme gxui.Theme
+	Theme   gxui.Theme
+	Imports *goimports.OnSave
 }
 
 func (h GolangHook) Name() string {
@@ -31,13 +32,13 @@ func (h GolangHook) FileBindables(path string) []bind.Bindable {
 	}
 	return []bind.Bindable{
 		goimports.New(h.Theme),
-		goimports.OnSave{},
+		h.Imports,
 	}
 }
 
 // Bindables is the main entry point to the command.
 func Bindables(cmdr command.Commander, driver gxui.Driver, theme gxui.Theme) []bind.Bindable {
 	return []bind.Bindable{
-		GolangHook{Theme: theme},
+		GolangHook{Theme: theme, Imports: goimports.NewOnSave(cmdr, driver)},
 	}
 }
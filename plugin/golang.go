@@ -12,23 +12,48 @@ import (
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/themes/basic"
 	"github.com/nelsam/vidar/commander/bind"
-	"github.com/nelsam/vidar/plugin/comments"
 	"github.com/nelsam/vidar/plugin/gocode"
 	"github.com/nelsam/vidar/plugin/godef"
 	"github.com/nelsam/vidar/plugin/goimports"
 	"github.com/nelsam/vidar/plugin/gosyntax"
 	"github.com/nelsam/vidar/plugin/license"
+	"github.com/nelsam/vidar/plugin/structtags"
+	"github.com/nelsam/vidar/setting"
 )
 
 type GolangHook struct {
 	Theme  *basic.Theme
 	Driver gxui.Driver
+
+	// Tests are the bindables from the gotest plugin.  They are
+	// created once, rather than per file, so that they can share
+	// their results pane.
+	Tests []bind.Bindable
+
+	// Build are the bindables from the gobuild plugin, which are
+	// shared for the same reason.
+	Build []bind.Bindable
+
+	// Definitions is the index that goto-definition uses.  It is
+	// kept up to date in the background for the current project.
+	Definitions *godef.Index
+
+	// Imports runs goimports after go files are saved.  It keeps
+	// track of the runs for every file, so it's shared too.
+	Imports *goimports.OnSave
 }
 
 func (h GolangHook) Name() string {
 	return "golang-hook"
 }
 
+// SetProject updates h's definition index when the project changes.
+func (h GolangHook) SetProject(p setting.Project) {
+	if h.Definitions != nil {
+		h.Definitions.SetProject(p)
+	}
+}
+
 func (h GolangHook) OpName() string {
 	return "focus-location"
 }
@@ -37,15 +62,20 @@ func (h GolangHook) FileBindables(path string) []bind.Bindable {
 	if !strings.HasSuffix(path, ".go") {
 		return nil
 	}
+	fixes := gocode.NewFixes(h.Theme)
 	completions, gocode := gocode.New(h.Theme, h.Driver)
-	return []bind.Bindable{
-		comments.NewToggle(),
-		godef.New(h.Theme),
+	b := []bind.Bindable{
+		godef.New(h.Theme, h.Definitions),
+		godef.NewParamHints(h.Theme, h.Definitions),
 		goimports.New(h.Theme),
-		goimports.OnSave{},
+		h.Imports,
 		gosyntax.New(),
 		license.NewHeaderUpdate(h.Theme),
 		completions,
 		gocode,
 	}
+	b = append(b, structtags.New(h.Theme)...)
+	b = append(b, fixes...)
+	b = append(b, h.Build...)
+	return append(b, h.Tests...)
 }
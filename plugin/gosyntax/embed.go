@@ -0,0 +1,100 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gosyntax
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/nelsam/vidar/plugin/highlight"
+)
+
+// tagPrefix may be used before the name of the language in a comment
+// that tags a string, for editors that expect it.
+const tagPrefix = "language="
+
+// embedded finds the raw strings in go source that are tagged with a
+// block comment naming the language that they're written in, so that
+// they can be highlighted in that language.  For example:
+//
+//	rows, err := db.Query(/* sql */ `SELECT name FROM users`)
+func embedded(text []rune) []highlight.Region {
+	var (
+		regions []highlight.Region
+		tag     string
+		tagEnd  = -1
+	)
+	for i := 0; i < len(text); {
+		switch {
+		case hasPrefix(text, i, "//"):
+			i = indexFrom(text, i, "\n")
+		case hasPrefix(text, i, "/*"):
+			end := indexFrom(text, i+2, "*/")
+			tag = strings.TrimPrefix(strings.TrimSpace(string(text[i+2:end])), tagPrefix)
+			i = end + 2
+			tagEnd = i
+		case text[i] == '"', text[i] == '\'':
+			i = quotedEnd(text, i)
+		case text[i] == '`':
+			end := indexFrom(text, i+1, "`")
+			if tagEnd >= 0 && blank(text[tagEnd:i]) {
+				if lexer, ok := highlight.Lookup(tag); ok {
+					regions = append(regions, highlight.Region{Start: i + 1, End: end, Lexer: lexer})
+				}
+			}
+			i = end + 1
+		default:
+			i++
+		}
+	}
+	return regions
+}
+
+// indexFrom returns the index of the first occurrence of sub in text
+// at or after i, or len(text) if there is none.
+func indexFrom(text []rune, i int, sub string) int {
+	for ; i < len(text); i++ {
+		if hasPrefix(text, i, sub) {
+			return i
+		}
+	}
+	return len(text)
+}
+
+func hasPrefix(text []rune, i int, prefix string) bool {
+	for _, r := range prefix {
+		if i >= len(text) || text[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// quotedEnd returns the index just after the interpreted string or
+// rune literal starting at text[i].
+func quotedEnd(text []rune, i int) int {
+	quote := text[i]
+	for i++; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		case '\n':
+			return i
+		}
+	}
+	return len(text)
+}
+
+func blank(text []rune) bool {
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
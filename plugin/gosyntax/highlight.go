@@ -6,17 +6,43 @@ package gosyntax
 
 import (
 	"context"
+	"go/scanner"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/highlight"
+	"github.com/nelsam/vidar/suggestion/quickfix"
 	"github.com/nelsam/vidar/syntax"
 )
 
+const (
+	// diagnosticSource is the source used when publishing parse
+	// errors as diagnostics.
+	diagnosticSource = "gosyntax"
+
+	// unusedSource is the source used when publishing unused
+	// imports and variables as diagnostics.
+	unusedSource = "unused"
+)
+
+// A Folder is an editor that can fold regions of its text.
+type Folder interface {
+	SetFolds([]input.Fold)
+}
+
 type Highlight struct {
 	ctx    context.Context
 	layers []input.SyntaxLayer
+	diags  []input.Diagnostic
+	folds  []input.Fold
 	syntax *syntax.Syntax
 
+	// unused is only updated when the text can be parsed, so that
+	// unused code stays dimmed while it's being edited.
+	unused        []input.Diagnostic
+	unusedChanged bool
+
 	mu sync.Mutex
 }
 
@@ -33,42 +59,7 @@ func (h *Highlight) OpName() string {
 }
 
 func (h *Highlight) Applied(e input.Editor, edits []input.Edit) {
-	layers := e.SyntaxLayers()
-	for i, l := range layers {
-		layers[i] = h.moveLayer(l, edits)
-	}
-	e.SetSyntaxLayers(layers)
-}
-
-func (h *Highlight) moveLayer(l input.SyntaxLayer, edits []input.Edit) input.SyntaxLayer {
-	for i, s := range l.Spans {
-		l.Spans[i] = h.moveSpan(s, edits)
-	}
-	return l
-}
-
-func (h *Highlight) moveSpan(s input.Span, edits []input.Edit) input.Span {
-	for _, e := range edits {
-		if e.At > s.End {
-			return s
-		}
-		delta := len(e.New) - len(e.Old)
-		if delta == 0 {
-			continue
-		}
-		s.End += delta
-		if s.End < e.At {
-			s.End = e.At
-		}
-		if e.At > s.Start {
-			continue
-		}
-		s.Start += delta
-		if s.Start < e.At {
-			s.Start = e.At
-		}
-	}
-	return s
+	e.SetSyntaxLayers(highlight.Move(e.SyntaxLayers(), edits))
 }
 
 func (h *Highlight) Init(e input.Editor, text []rune) {
@@ -79,23 +70,76 @@ func (h *Highlight) TextChanged(ctx context.Context, editor input.Editor, _ []in
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	// TODO: only update layers that changed.
-	err := h.syntax.Parse(editor.Text())
-	if err != nil {
-		// TODO: Report the error in the UI
-		_ = err
-	}
+	text := editor.Text()
+	err := h.syntax.Parse(text)
 	select {
 	case <-ctx.Done():
 		return
 	default:
 	}
 
-	h.layers = h.syntax.Layers()
+	runes := []rune(text)
+	h.layers = highlight.Embed(h.syntax.Layers(), runes, embedded(runes))
+	h.diags = diagnostics(text, err)
+	h.folds = h.syntax.Folds()
+	h.unusedChanged = false
+	if err == nil {
+		h.unused = unused(runes)
+		h.unusedChanged = true
+	}
 }
 
 func (h *Highlight) Apply(e input.Editor) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	e.SetSyntaxLayers(h.layers)
+	e.SetDiagnostics(diagnosticSource, h.diags)
+	if h.unusedChanged {
+		e.SetDiagnostics(unusedSource, h.unused)
+	}
+	if f, ok := e.(Folder); ok {
+		f.SetFolds(h.folds)
+	}
 	return nil
 }
+
+// unused type checks text and returns a diagnostic for each unused
+// import and variable, which the remove-unused command can fix.
+func unused(text []rune) []input.Diagnostic {
+	found := quickfix.FindUnused(text)
+	diags := make([]input.Diagnostic, 0, len(found))
+	for _, u := range found {
+		diags = append(diags, input.Diagnostic{
+			Severity:    input.SeverityWarning,
+			Range:       input.Span{Start: u.Start, End: u.End},
+			Message:     u.Message(),
+			Unnecessary: true,
+		})
+	}
+	return diags
+}
+
+// diagnostics converts an error from parsing text to diagnostics.
+func diagnostics(text string, err error) []input.Diagnostic {
+	if err == nil {
+		return nil
+	}
+	list, ok := err.(scanner.ErrorList)
+	if !ok {
+		return []input.Diagnostic{{Severity: input.SeverityError, Message: err.Error()}}
+	}
+	diags := make([]input.Diagnostic, 0, len(list))
+	for _, e := range list {
+		offset := e.Pos.Offset
+		if offset > len(text) {
+			offset = len(text)
+		}
+		start := utf8.RuneCountInString(text[:offset])
+		diags = append(diags, input.Diagnostic{
+			Severity: input.SeverityError,
+			Range:    input.Span{Start: start, End: start},
+			Message:  e.Msg,
+		})
+	}
+	return diags
+}
@@ -0,0 +1,58 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gotest
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var testPrefixes = []string{"Test", "Benchmark", "Example"}
+
+// TestAt returns the name of the test function in src that contains
+// the rune at offset.  An empty string is returned if offset is not
+// inside of a test function.
+func TestAt(path, src string, offset int) string {
+	fset := token.NewFileSet()
+	f, _ := parser.ParseFile(fset, path, src, 0)
+	if f == nil {
+		return ""
+	}
+	byteOffset := len(src)
+	if runes := []rune(src); offset < len(runes) {
+		byteOffset = len(string(runes[:offset]))
+	}
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || !isTest(fn.Name.Name) {
+			continue
+		}
+		start, end := fset.Position(fn.Pos()).Offset, fset.Position(fn.End()).Offset
+		if byteOffset >= start && byteOffset <= end {
+			return fn.Name.Name
+		}
+	}
+	return ""
+}
+
+// isTest reports whether name is the name of a function that go test
+// will run, following the same rules as go test.
+func isTest(name string) bool {
+	for _, prefix := range testPrefixes {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if len(name) == len(prefix) {
+			return true
+		}
+		r, _ := utf8.DecodeRuneInString(name[len(prefix):])
+		return !unicode.IsLower(r)
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package gotest contains commands for running go test and
+// displaying its results.  It can be imported directly or used as a
+// plugin.
+package gotest
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// A Paneler is a type that can display panels below the editor.
+type Paneler interface {
+	ShowPanel(gxui.Control)
+	HidePanel(gxui.Control)
+	HasPanel(gxui.Control) bool
+}
+
+// A Projecter is a type that knows which project is currently open.
+type Projecter interface {
+	Project() setting.Project
+}
+
+// Editor is the type of editor that tests can be run from.
+type Editor interface {
+	Filepath() string
+	Text() string
+	Carets() []int
+}
+
+// New returns the commands for running tests.  They share a single
+// *Pane, so that failed tests can be re-run.
+func New(cmdr command.Commander, driver gxui.Driver, theme gxui.Theme) []bind.Bindable {
+	pane := NewPane(cmdr, driver, theme)
+	return []bind.Bindable{
+		newRun(theme, pane, "run-tests", allTests),
+		newRun(theme, pane, "rerun-failed-tests", failedTests),
+		newRun(theme, pane, "run-test-at-cursor", testAtCursor),
+	}
+}
+
+type mode int
+
+const (
+	allTests mode = iota
+	failedTests
+	testAtCursor
+)
+
+// Run is a bind.MultiOp that runs go test and shows the results in
+// a *Pane.
+type Run struct {
+	status.General
+
+	name string
+	mode mode
+	pane *Pane
+
+	paneler Paneler
+	proj    Projecter
+	editor  Editor
+}
+
+func newRun(theme gxui.Theme, pane *Pane, name string, m mode) *Run {
+	r := &Run{name: name, mode: m, pane: pane}
+	r.Theme = theme
+	return r
+}
+
+func (r *Run) Name() string {
+	return r.name
+}
+
+func (r *Run) Menu() string {
+	return "Golang"
+}
+
+func (r *Run) Defaults() []fmt.Stringer {
+	e := gxui.KeyboardEvent{Key: gxui.KeyF8}
+	switch r.mode {
+	case failedTests:
+		e.Modifier = gxui.ModShift
+	case testAtCursor:
+		e.Modifier = gxui.ModControl
+	}
+	return []fmt.Stringer{e}
+}
+
+func (r *Run) Reset() {
+	r.General.Clear()
+	r.paneler = nil
+	r.proj = nil
+	r.editor = nil
+}
+
+func (r *Run) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Paneler:
+		r.paneler = src
+	case Projecter:
+		r.proj = src
+	case Editor:
+		r.editor = src
+	}
+	if r.paneler == nil || r.proj == nil {
+		return bind.Waiting
+	}
+	if r.editor == nil && r.mode != failedTests {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (r *Run) Exec() error {
+	switch r.mode {
+	case failedTests:
+		dir, environ, names := r.pane.Failed()
+		if dir == "" {
+			r.Err = "No tests have been run yet"
+			return errors.New(r.Err)
+		}
+		if len(names) == 0 {
+			r.Info = "No failed tests to re-run"
+			return nil
+		}
+		r.pane.Start(dir, environ, pattern(names...))
+	case testAtCursor:
+		carets := r.editor.Carets()
+		if len(carets) == 0 {
+			r.Err = "No caret to find a test at"
+			return errors.New(r.Err)
+		}
+		name := TestAt(r.editor.Filepath(), r.editor.Text(), carets[len(carets)-1])
+		if name == "" {
+			r.Err = "The cursor is not inside of a test function"
+			return errors.New(r.Err)
+		}
+		r.pane.Start(filepath.Dir(r.editor.Filepath()), r.proj.Project().GoEnviron(), pattern(name))
+	default:
+		r.pane.Start(filepath.Dir(r.editor.Filepath()), r.proj.Project().GoEnviron(), "")
+	}
+	r.pane.Show(r.paneler)
+	return nil
+}
+
+// pattern returns a -run pattern that matches exactly the top level
+// tests in names.
+func pattern(names ...string) string {
+	quoted := make([]string, 0, len(names))
+	for _, n := range names {
+		quoted = append(quoted, regexp.QuoteMeta(n))
+	}
+	return fmt.Sprintf("^(%s)$", strings.Join(quoted, "|"))
+}
@@ -0,0 +1,311 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gotest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+var (
+	passColor = gxui.Color{R: 0.3, G: 0.8, B: 0.3, A: 1}
+	failColor = gxui.Color{R: 0.9, G: 0.3, B: 0.3, A: 1}
+	skipColor = gxui.Color{R: 0.8, G: 0.8, B: 0.3, A: 1}
+	runColor  = gxui.Gray60
+	locColor  = gxui.Color{R: 0.4, G: 0.6, B: 1, A: 1}
+)
+
+// Commander is a type that can look up and execute bindables.
+type Commander interface {
+	Bindable(name string) bind.Bindable
+	Execute(bind.Bindable)
+}
+
+// Opener is a type that can create a bindable which focuses a
+// location.
+type Opener interface {
+	For(...focus.Opt) bind.Bindable
+}
+
+// Pane is a panel that displays the results of go test.  Failure
+// locations in the output of failed tests can be clicked to open
+// them.
+type Pane struct {
+	mixins.LinearLayout
+
+	cmdr   Commander
+	driver gxui.Driver
+	theme  gxui.Theme
+
+	status  gxui.Label
+	results gxui.LinearLayout
+	paneler Paneler
+
+	lock    sync.Mutex
+	stop    chan struct{}
+	dir     string
+	environ []string
+	report  *Report
+	rows    map[*Result]*resultRow
+}
+
+// NewPane creates a *Pane which will use cmdr to open failure
+// locations.
+func NewPane(cmdr Commander, driver gxui.Driver, theme gxui.Theme) *Pane {
+	p := &Pane{
+		cmdr:    cmdr,
+		driver:  driver,
+		theme:   theme,
+		status:  theme.CreateLabel(),
+		results: theme.CreateLinearLayout(),
+	}
+	p.Init(p, theme)
+	p.SetDirection(gxui.TopToBottom)
+
+	header := theme.CreateLinearLayout()
+	header.SetDirection(gxui.LeftToRight)
+	closer := theme.CreateButton()
+	closer.SetText("x")
+	closer.OnClick(func(gxui.MouseEvent) {
+		p.Cancel()
+		if p.paneler != nil {
+			p.paneler.HidePanel(p)
+		}
+	})
+	header.AddChild(closer)
+	p.status.SetText("No tests have been run")
+	header.AddChild(p.status)
+	p.AddChild(header)
+
+	p.results.SetDirection(gxui.TopToBottom)
+	scrollable := theme.CreateScrollLayout()
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(p.results)
+	p.AddChild(scrollable)
+	return p
+}
+
+// Show shows p using paneler, if it isn't already shown.
+func (p *Pane) Show(paneler Paneler) {
+	p.paneler = paneler
+	if !paneler.HasPanel(p) {
+		paneler.ShowPanel(p)
+	}
+}
+
+// Start cancels any running tests and runs the tests in dir which
+// match pattern, in the background.  An empty pattern runs all
+// tests.  It must be called on the UI goroutine.
+func (p *Pane) Start(dir string, environ []string, pattern string) {
+	p.Cancel()
+
+	p.lock.Lock()
+	stop := make(chan struct{})
+	p.stop = stop
+	p.dir = dir
+	p.environ = environ
+	p.report = &Report{}
+	p.rows = make(map[*Result]*resultRow)
+	p.lock.Unlock()
+
+	p.results.RemoveAll()
+	msg := fmt.Sprintf("Running tests in %s", dir)
+	if pattern != "" {
+		msg = fmt.Sprintf("Running tests matching %s in %s", pattern, dir)
+	}
+	p.status.SetText(msg + "...")
+	task := status.Start("go test", func() {
+		if p.cancel(stop) {
+			p.driver.Call(func() {
+				p.status.SetText("Tests canceled")
+			})
+		}
+	})
+	go func() {
+		defer task.Done()
+		err := run(stop, dir, environ, pattern, func(e Event) {
+			p.driver.Call(func() {
+				p.add(stop, e)
+			})
+		})
+		p.driver.Call(func() {
+			p.finish(stop, err)
+		})
+	}()
+}
+
+// Cancel stops any running tests.
+func (p *Pane) Cancel() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+}
+
+// cancel stops the run that stop belongs to, returning whether or not
+// it was still running.
+func (p *Pane) cancel(stop chan struct{}) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.stop == nil || p.stop != stop {
+		return false
+	}
+	close(p.stop)
+	p.stop = nil
+	return true
+}
+
+// Failed returns the directory and environment of the last run, and
+// the names of the top level tests that failed in it.  dir will be
+// empty if no tests have been run.
+func (p *Pane) Failed() (dir string, environ []string, names []string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.report == nil {
+		return "", nil, nil
+	}
+	return p.dir, p.environ, p.report.Failed()
+}
+
+func (p *Pane) current(stop chan struct{}) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.stop != nil && p.stop == stop
+}
+
+func (p *Pane) add(stop chan struct{}, e Event) {
+	if !p.current(stop) {
+		return
+	}
+	p.lock.Lock()
+	res := p.report.Add(e)
+	row, ok := p.rows[res]
+	if !ok {
+		row = newResultRow(p, p.theme)
+		p.rows[res] = row
+	}
+	p.lock.Unlock()
+	if !ok {
+		p.results.AddChild(row)
+	}
+	row.update(p.dir, res)
+}
+
+func (p *Pane) finish(stop chan struct{}, err error) {
+	if !p.current(stop) {
+		return
+	}
+	p.lock.Lock()
+	p.stop = nil
+	passed, failed, skipped := p.report.Count(Passed), p.report.Count(Failed), p.report.Count(Skipped)
+	p.lock.Unlock()
+	if err != nil {
+		p.status.SetText(fmt.Sprintf("Could not run tests: %s", err))
+		return
+	}
+	p.status.SetText(fmt.Sprintf("%d passed, %d failed, %d skipped", passed, failed, skipped))
+}
+
+func (p *Pane) open(l Location) {
+	opener, ok := p.cmdr.Bindable("focus-location").(Opener)
+	if !ok {
+		return
+	}
+	p.cmdr.Execute(opener.For(focus.Path(l.Path), focus.Line(l.Line), focus.Column(l.Column)))
+}
+
+// resultRow displays a single Result.  Output is only displayed for
+// failures and for output that isn't attributed to a package.
+type resultRow struct {
+	mixins.LinearLayout
+
+	pane   *Pane
+	theme  gxui.Theme
+	header gxui.Label
+	output gxui.LinearLayout
+	shown  int
+}
+
+func newResultRow(pane *Pane, theme gxui.Theme) *resultRow {
+	r := &resultRow{
+		pane:   pane,
+		theme:  theme,
+		header: theme.CreateLabel(),
+		output: theme.CreateLinearLayout(),
+	}
+	r.Init(r, theme)
+	r.SetDirection(gxui.TopToBottom)
+	r.AddChild(r.header)
+	r.output.SetDirection(gxui.TopToBottom)
+	r.output.SetMargin(math.Spacing{L: 10})
+	r.AddChild(r.output)
+	return r
+}
+
+func (r *resultRow) update(dir string, res *Result) {
+	if res.Package == "" {
+		// Output that isn't part of any package is almost always
+		// a build error, so we always display it.
+		r.header.SetText("Output")
+		r.header.SetColor(failColor)
+	} else {
+		name := res.Test
+		if name == "" {
+			name = res.Package
+		}
+		text := fmt.Sprintf("%s %s", res.Status, name)
+		if res.Status != Running {
+			text += fmt.Sprintf(" (%.2fs)", res.Elapsed.Seconds())
+		}
+		r.header.SetText(text)
+		r.header.SetColor(statusColor(res.Status))
+		if res.Status != Failed {
+			return
+		}
+	}
+	for _, line := range res.Output[r.shown:] {
+		if strings.HasPrefix(strings.TrimSpace(line), "=== ") {
+			continue
+		}
+		r.addLine(dir, line)
+	}
+	r.shown = len(res.Output)
+}
+
+func (r *resultRow) addLine(dir, line string) {
+	l := r.theme.CreateLabel()
+	l.SetText(strings.Replace(line, "\t", "    ", -1))
+	r.output.AddChild(l)
+	loc, ok := ParseLocation(dir, line)
+	if !ok {
+		return
+	}
+	l.SetColor(locColor)
+	l.OnClick(func(gxui.MouseEvent) {
+		r.pane.open(loc)
+	})
+}
+
+func statusColor(s Status) gxui.Color {
+	switch s {
+	case Passed:
+		return passColor
+	case Failed:
+		return failColor
+	case Skipped:
+		return skipColor
+	}
+	return runColor
+}
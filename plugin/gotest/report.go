@@ -0,0 +1,192 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gotest
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLineSize is the longest line of output that will be read from
+// go test.
+const maxLineSize = 1 << 20
+
+var locationPattern = regexp.MustCompile(`^\s*((?:[a-zA-Z]:)?[^\s:]+\.go):(\d+)(?::(\d+))?:`)
+
+// Event is a single event in the output of go test -json.
+type Event struct {
+	Time       time.Time
+	Action     string
+	Package    string
+	ImportPath string
+	Test       string
+	Elapsed    float64
+	Output     string
+}
+
+// Parse reads go test -json output from r, calling fn with each
+// event.  Lines which are not JSON (e.g. build errors printed by
+// older versions of go) are passed to fn as output events without
+// a package.
+func Parse(r io.Reader, fn func(Event)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var e Event
+		if len(line) == 0 || line[0] != '{' || json.Unmarshal(line, &e) != nil {
+			fn(Event{Action: "output", Output: string(line) + "\n"})
+			continue
+		}
+		fn(e)
+	}
+	return scanner.Err()
+}
+
+// Status is the state of a test or package.
+type Status int
+
+const (
+	Running Status = iota
+	Passed
+	Failed
+	Skipped
+)
+
+func (s Status) String() string {
+	switch s {
+	case Running:
+		return "RUN"
+	case Passed:
+		return "PASS"
+	case Failed:
+		return "FAIL"
+	case Skipped:
+		return "SKIP"
+	}
+	return "UNKNOWN"
+}
+
+// Result is the result of a single test.  Results for a whole
+// package have an empty Test.
+type Result struct {
+	Package string
+	Test    string
+	Status  Status
+	Elapsed time.Duration
+	Output  []string
+}
+
+// Report collects the results from a stream of events.  The zero
+// value is ready to use.
+type Report struct {
+	results []*Result
+	byName  map[string]*Result
+}
+
+// Add updates r with e, returning the result that e applies to.
+func (r *Report) Add(e Event) *Result {
+	pkg := e.Package
+	if pkg == "" {
+		pkg = e.ImportPath
+	}
+	key := pkg + "\x00" + e.Test
+	res, ok := r.byName[key]
+	if !ok {
+		if r.byName == nil {
+			r.byName = make(map[string]*Result)
+		}
+		res = &Result{Package: pkg, Test: e.Test}
+		r.byName[key] = res
+		r.results = append(r.results, res)
+	}
+	switch e.Action {
+	case "output", "build-output":
+		res.Output = append(res.Output, strings.TrimSuffix(e.Output, "\n"))
+	case "pass":
+		res.Status = Passed
+	case "fail", "build-fail":
+		res.Status = Failed
+	case "skip":
+		res.Status = Skipped
+	}
+	if e.Elapsed > 0 {
+		res.Elapsed = time.Duration(e.Elapsed * float64(time.Second))
+	}
+	return res
+}
+
+// Results returns all results in r, in the order that they were
+// first seen.
+func (r *Report) Results() []*Result {
+	return r.results
+}
+
+// Count returns the number of tests (not packages) in r which have
+// status s.
+func (r *Report) Count(s Status) int {
+	count := 0
+	for _, res := range r.results {
+		if res.Test != "" && res.Status == s {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed returns the names of the top level tests that failed.  A
+// failing subtest causes its parent test to be returned.
+func (r *Report) Failed() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, res := range r.results {
+		if res.Test == "" || res.Status != Failed {
+			continue
+		}
+		name := strings.SplitN(res.Test, "/", 2)[0]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// Location is a position in a file that was mentioned in test
+// output.  Line and Column are zero-indexed.
+type Location struct {
+	Path   string
+	Line   int
+	Column int
+}
+
+// ParseLocation parses a file location (e.g. "foo_test.go:12:" or
+// "./foo.go:3:14:") at the start of line.  Relative paths are
+// resolved against dir.
+func ParseLocation(dir, line string) (Location, bool) {
+	m := locationPattern.FindStringSubmatch(line)
+	if m == nil {
+		return Location{}, false
+	}
+	path := m[1]
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	l := Location{Path: path}
+	l.Line, _ = strconv.Atoi(m[2])
+	l.Line--
+	if m[3] != "" {
+		l.Column, _ = strconv.Atoi(m[3])
+		l.Column--
+	}
+	return l, true
+}
@@ -0,0 +1,129 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gotest_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nelsam/vidar/plugin/gotest"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+const testOutput = `{"Action":"run","Package":"foo","Test":"TestPass"}
+{"Action":"output","Package":"foo","Test":"TestPass","Output":"=== RUN   TestPass\n"}
+{"Action":"pass","Package":"foo","Test":"TestPass","Elapsed":0.5}
+{"Action":"run","Package":"foo","Test":"TestFail"}
+{"Action":"run","Package":"foo","Test":"TestFail/sub"}
+{"Action":"output","Package":"foo","Test":"TestFail/sub","Output":"    foo_test.go:12: bad\n"}
+{"Action":"fail","Package":"foo","Test":"TestFail/sub","Elapsed":0}
+{"Action":"fail","Package":"foo","Test":"TestFail","Elapsed":0.25}
+{"Action":"skip","Package":"foo","Test":"TestSkip"}
+# foo
+./foo.go:3:14: undefined: bar
+{"Action":"fail","Package":"foo","Elapsed":1}
+`
+
+var (
+	not          = matchers.Not
+	haveOccurred = matchers.HaveOccurred
+	equal        = matchers.Equal
+	haveLen      = matchers.HaveLen
+	beTrue       = matchers.BeTrue
+)
+
+func TestReport(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *gotest.Report) {
+		r := &gotest.Report{}
+		err := gotest.Parse(strings.NewReader(testOutput), func(e gotest.Event) {
+			r.Add(e)
+		})
+		expect.New(t)(err).To(not(haveOccurred()))
+		return expect.New(t), r
+	})
+
+	o.Spec("it tracks the status of each test", func(expect expect.Expectation, r *gotest.Report) {
+		results := r.Results()
+		expect(results).To(haveLen(6))
+		expect(results[0].Test).To(equal("TestPass"))
+		expect(results[0].Status).To(equal(gotest.Passed))
+		expect(results[0].Elapsed).To(equal(500 * time.Millisecond))
+		expect(results[2].Test).To(equal("TestFail/sub"))
+		expect(results[2].Output).To(equal([]string{"    foo_test.go:12: bad"}))
+		expect(results[3].Status).To(equal(gotest.Skipped))
+	})
+
+	o.Spec("it reports non-JSON output as unattributed output", func(expect expect.Expectation, r *gotest.Report) {
+		build := r.Results()[4]
+		expect(build.Package).To(equal(""))
+		expect(build.Output).To(equal([]string{"# foo", "./foo.go:3:14: undefined: bar"}))
+	})
+
+	o.Spec("it counts tests", func(expect expect.Expectation, r *gotest.Report) {
+		expect(r.Count(gotest.Passed)).To(equal(1))
+		expect(r.Count(gotest.Failed)).To(equal(2))
+		expect(r.Count(gotest.Skipped)).To(equal(1))
+	})
+
+	o.Spec("it returns the top level failed tests", func(expect expect.Expectation, r *gotest.Report) {
+		expect(r.Failed()).To(equal([]string{"TestFail"}))
+	})
+}
+
+func TestParseLocation(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it parses test failure locations", func(expect expect.Expectation) {
+		l, ok := gotest.ParseLocation("/src/foo", "    foo_test.go:12: bad")
+		expect(ok).To(beTrue())
+		expect(l).To(equal(gotest.Location{Path: "/src/foo/foo_test.go", Line: 11}))
+	})
+
+	o.Spec("it parses build error locations", func(expect expect.Expectation) {
+		l, ok := gotest.ParseLocation("/src/foo", "./foo.go:3:14: undefined: bar")
+		expect(ok).To(beTrue())
+		expect(l).To(equal(gotest.Location{Path: "/src/foo/foo.go", Line: 2, Column: 13}))
+	})
+
+	o.Spec("it ignores lines without locations", func(expect expect.Expectation) {
+		_, ok := gotest.ParseLocation("/src/foo", "--- FAIL: TestFoo (0.00s)")
+		expect(ok).To(not(beTrue()))
+	})
+}
+
+func TestTestAt(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	src := "package foo\n\nfunc TestFoo(t *testing.T) {\n\t// ☃\n}\n\nfunc Testify() {}\n\nfunc helper() {}\n"
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it finds the test containing the cursor", func(expect expect.Expectation) {
+		expect(gotest.TestAt("foo_test.go", src, runeIndex(src, "☃"))).To(equal("TestFoo"))
+	})
+
+	o.Spec("it ignores functions that go test will not run", func(expect expect.Expectation) {
+		expect(gotest.TestAt("foo_test.go", src, runeIndex(src, "Testify"))).To(equal(""))
+		expect(gotest.TestAt("foo_test.go", src, runeIndex(src, "helper"))).To(equal(""))
+	})
+}
+
+func runeIndex(s, substr string) int {
+	return len([]rune(s[:strings.Index(s, substr)]))
+}
@@ -0,0 +1,60 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package gotest
+
+import (
+	"io"
+	"os/exec"
+)
+
+// run runs go test -json in dir, calling fn with each event that
+// it outputs.  The tests will be killed if stop is closed before
+// they finish.
+func run(stop <-chan struct{}, dir string, environ []string, pattern string, fn func(Event)) error {
+	args := []string{"test", "-json"}
+	if pattern != "" {
+		args = append(args, "-run", pattern)
+	}
+	cmd := exec.Command("go", append(args, ".")...)
+	cmd.Dir = dir
+	cmd.Env = environ
+
+	// Build errors are written to stderr, so we read both streams
+	// from the same pipe and let Parse treat anything that isn't
+	// JSON as output.
+	r, w := io.Pipe()
+	cmd.Stdout = w
+	cmd.Stderr = w
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+			cmd.Process.Kill()
+		case <-done:
+		}
+	}()
+	waitErr := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		w.Close()
+		waitErr <- err
+	}()
+	if err := Parse(r, fn); err != nil {
+		r.CloseWithError(err)
+		<-waitErr
+		return err
+	}
+	err := <-waitErr
+	if _, ok := err.(*exec.ExitError); ok {
+		// go test exits with a non-zero status when tests fail,
+		// which will already show up in the events.
+		return nil
+	}
+	return err
+}
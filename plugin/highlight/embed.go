@@ -0,0 +1,115 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package highlight
+
+import (
+	"sort"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// A Region is a part of some text that is written in a different
+// language than the text around it (e.g. a template action in HTML,
+// or a SQL query in a go string).
+type Region struct {
+	Start, End int
+
+	// Lexer finds the layers of the region's text.  A nil Lexer
+	// leaves the region unhighlighted.
+	Lexer Lexer
+}
+
+// A RegionFinder finds the regions of text that are written in other
+// languages.  The regions it returns must be sorted and must not
+// overlap.
+type RegionFinder func(text []rune) []Region
+
+// Embedded returns a Lexer for text in the language that host lexes,
+// which contains regions of other languages.  Each region is blanked
+// out before host sees the text, so that host doesn't try to make
+// sense of it, and is lexed on its own by the region's Lexer.
+func Embedded(host Lexer, find RegionFinder) Lexer {
+	return LexerFunc(func(text []rune) []input.SyntaxLayer {
+		regions := find(text)
+		var layers []input.SyntaxLayer
+		if host != nil {
+			layers = host.Layers(blank(text, regions))
+		}
+		return Embed(layers, text, regions)
+	})
+}
+
+// Embed returns layers with regions of text highlighted by their own
+// Lexers.  Any spans in layers are cut where they overlap a region.
+func Embed(layers []input.SyntaxLayer, text []rune, regions []Region) []input.SyntaxLayer {
+	if len(regions) == 0 {
+		return layers
+	}
+	l := make(Layers)
+	for _, layer := range layers {
+		for _, s := range layer.Spans {
+			for _, piece := range cut(s, regions) {
+				l.Add(layer.Construct, piece.Start, piece.End)
+			}
+		}
+	}
+	for _, r := range regions {
+		if r.Lexer == nil {
+			continue
+		}
+		for _, layer := range r.Lexer.Layers(text[r.Start:r.End]) {
+			for _, s := range layer.Spans {
+				l.Add(layer.Construct, s.Start+r.Start, s.End+r.Start)
+			}
+		}
+	}
+	merged := l.Slice()
+	for _, layer := range merged {
+		spans := layer.Spans
+		sort.Slice(spans, func(i, j int) bool {
+			return spans[i].Start < spans[j].Start
+		})
+	}
+	return merged
+}
+
+// cut returns the parts of s that are outside of regions.
+func cut(s input.Span, regions []Region) []input.Span {
+	var pieces []input.Span
+	for _, r := range regions {
+		if r.End <= s.Start {
+			continue
+		}
+		if r.Start >= s.End {
+			break
+		}
+		if r.Start > s.Start {
+			pieces = append(pieces, input.Span{Start: s.Start, End: r.Start})
+		}
+		s.Start = r.End
+	}
+	if s.Start < s.End {
+		pieces = append(pieces, s)
+	}
+	return pieces
+}
+
+// blank returns a copy of text with the runes in regions replaced by
+// spaces.  Newlines are kept, so that lexers which care about lines
+// still see them.
+func blank(text []rune, regions []Region) []rune {
+	if len(regions) == 0 {
+		return text
+	}
+	blanked := append([]rune(nil), text...)
+	for _, r := range regions {
+		for i := r.Start; i < r.End; i++ {
+			if blanked[i] != '\n' {
+				blanked[i] = ' '
+			}
+		}
+	}
+	return blanked
+}
@@ -0,0 +1,157 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package highlight contains a small framework for syntax
+// highlighting plugins, along with highlighters for some of the
+// non-go files that most go projects contain.
+//
+// A highlighter only needs to provide a Lexer, which finds the
+// layers to highlight in some text.  Highlight takes care of keeping
+// those layers in place while the text is edited and updating them
+// once the text has settled.
+package highlight
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+// A Lexer is a type that finds the syntax layers in some text.
+// Lexers are shared between files, so they must not store any state
+// between calls to Layers.
+type Lexer interface {
+	Layers(text []rune) []input.SyntaxLayer
+}
+
+// LexerFunc is a function that implements Lexer.
+type LexerFunc func(text []rune) []input.SyntaxLayer
+
+// Layers calls f(text).
+func (f LexerFunc) Layers(text []rune) []input.SyntaxLayer {
+	return f(text)
+}
+
+// Highlight is a hook on input-handler which highlights the text of
+// an editor using a Lexer.
+type Highlight struct {
+	name   string
+	lexer  Lexer
+	layers []input.SyntaxLayer
+
+	mu sync.Mutex
+}
+
+// New returns a *Highlight named name, which uses lexer to find
+// syntax layers.
+func New(name string, lexer Lexer) *Highlight {
+	return &Highlight{name: name, lexer: lexer}
+}
+
+func (h *Highlight) Name() string {
+	return h.name
+}
+
+func (h *Highlight) OpName() string {
+	return "input-handler"
+}
+
+// Applied moves the existing layers to account for edits, so that
+// the highlighting doesn't jump around while the text is being
+// lexed again.
+func (h *Highlight) Applied(e input.Editor, edits []input.Edit) {
+	e.SetSyntaxLayers(Move(e.SyntaxLayers(), edits))
+}
+
+func (h *Highlight) Init(e input.Editor, text []rune) {
+	h.TextChanged(context.Background(), e, nil)
+}
+
+func (h *Highlight) TextChanged(ctx context.Context, e input.Editor, _ []input.Edit) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	layers := h.lexer.Layers(e.Runes())
+	select {
+	case <-ctx.Done():
+		return
+	default:
+	}
+	h.layers = layers
+}
+
+func (h *Highlight) Apply(e input.Editor) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	e.SetSyntaxLayers(h.layers)
+	return nil
+}
+
+// Move updates the spans in layers to account for edits, which must
+// be sorted.  Spans that an edit removes entirely are left empty.
+func Move(layers []input.SyntaxLayer, edits []input.Edit) []input.SyntaxLayer {
+	for i, l := range layers {
+		for j, s := range l.Spans {
+			l.Spans[j] = moveSpan(s, edits)
+		}
+		layers[i] = l
+	}
+	return layers
+}
+
+func moveSpan(s input.Span, edits []input.Edit) input.Span {
+	for _, e := range edits {
+		if e.At > s.End {
+			return s
+		}
+		delta := len(e.New) - len(e.Old)
+		if delta == 0 {
+			continue
+		}
+		s.End += delta
+		if s.End < e.At {
+			s.End = e.At
+		}
+		if e.At > s.Start {
+			continue
+		}
+		s.Start += delta
+		if s.Start < e.At {
+			s.Start = e.At
+		}
+	}
+	return s
+}
+
+// Layers collects spans by theme.LanguageConstruct.  It's intended to
+// be used by Lexers while they scan text.
+type Layers map[theme.LanguageConstruct]*input.SyntaxLayer
+
+// Add adds a span from start to end for construct.  Empty spans are
+// ignored.
+func (l Layers) Add(construct theme.LanguageConstruct, start, end int) {
+	if end <= start {
+		return
+	}
+	layer, ok := l[construct]
+	if !ok {
+		layer = &input.SyntaxLayer{Construct: construct}
+		l[construct] = layer
+	}
+	layer.Spans = append(layer.Spans, input.Span{Start: start, End: end})
+}
+
+// Slice returns the layers in l, sorted by construct.
+func (l Layers) Slice() []input.SyntaxLayer {
+	layers := make([]input.SyntaxLayer, 0, len(l))
+	for _, layer := range l {
+		layers = append(layers, *layer)
+	}
+	sort.Slice(layers, func(i, j int) bool {
+		return layers[i].Construct < layers[j].Construct
+	})
+	return layers
+}
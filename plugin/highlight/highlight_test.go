@@ -0,0 +1,294 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package highlight_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/highlight"
+	"github.com/nelsam/vidar/theme"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal   = matchers.Equal
+	haveLen = matchers.HaveLen
+)
+
+// spans returns the text of each span in layers for construct.
+func spans(text string, layers []input.SyntaxLayer, construct theme.LanguageConstruct) []string {
+	runes := []rune(text)
+	var s []string
+	for _, l := range layers {
+		if l.Construct != construct {
+			continue
+		}
+		for _, span := range l.Spans {
+			s = append(s, string(runes[span.Start:span.End]))
+		}
+	}
+	return s
+}
+
+func lex(lexer func([]rune) []input.SyntaxLayer, text string) func(theme.LanguageConstruct) []string {
+	layers := lexer([]rune(text))
+	return func(c theme.LanguageConstruct) []string {
+		return spans(text, layers, c)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it highlights keys, values, and brackets", func(expect expect.Expectation) {
+		found := lex(highlight.JSON, `{"fööd": "bar", "n": [-1.5e3, true, null]}`)
+		expect(found(theme.Type)).To(equal([]string{`"fööd"`, `"n"`}))
+		expect(found(theme.String)).To(equal([]string{`"bar"`}))
+		expect(found(theme.Num)).To(equal([]string{"-1.5e3"}))
+		expect(found(theme.Keyword)).To(equal([]string{"true"}))
+		expect(found(theme.Nil)).To(equal([]string{"null"}))
+		expect(found(theme.ScopePair)).To(equal([]string{"{", "}"}))
+		expect(found(theme.ScopePair + 1)).To(equal([]string{"[", "]"}))
+		expect(found(theme.Bad)).To(haveLen(0))
+	})
+
+	o.Spec("it highlights comments", func(expect expect.Expectation) {
+		found := lex(highlight.JSON, "// foo\n{/* bar */}")
+		expect(found(theme.Comment)).To(equal([]string{"// foo", "/* bar */"}))
+	})
+
+	o.Spec("it marks unterminated strings and unknown words", func(expect expect.Expectation) {
+		found := lex(highlight.JSON, "[foo, \"bar\n]")
+		expect(found(theme.Bad)).To(equal([]string{"foo", `"bar`}))
+	})
+}
+
+func TestYAML(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it highlights keys and values", func(expect expect.Expectation) {
+		found := lex(highlight.YAML, "---\nname: vidar # editor\nitems:\n  - count: 3\n  - \"quoted: key\": yes\n")
+		expect(found(theme.Keyword)).To(equal([]string{"---", "-", "-", "yes"}))
+		expect(found(theme.Type)).To(equal([]string{"name", "items", "count", `"quoted: key"`}))
+		expect(found(theme.String)).To(equal([]string{"vidar"}))
+		expect(found(theme.Num)).To(equal([]string{"3"}))
+		expect(found(theme.Comment)).To(equal([]string{"# editor"}))
+	})
+
+	o.Spec("it highlights block scalars as strings", func(expect expect.Expectation) {
+		found := lex(highlight.YAML, "script: |\n  go test\n\n  go vet\nnext: ~\n")
+		expect(found(theme.Keyword)).To(equal([]string{"|"}))
+		expect(found(theme.String)).To(equal([]string{"go test", "go vet"}))
+		expect(found(theme.Type)).To(equal([]string{"script", "next"}))
+		expect(found(theme.Nil)).To(equal([]string{"~"}))
+	})
+
+	o.Spec("it highlights anchors and flow collections", func(expect expect.Expectation) {
+		found := lex(highlight.YAML, "base: &base {a: 1, b: [x]}\nother: *base\n")
+		expect(found(theme.Func)).To(equal([]string{"&base", "*base"}))
+		expect(found(theme.Type)).To(equal([]string{"base", "a", "b", "other"}))
+		expect(found(theme.ScopePair)).To(equal([]string{"{", "}"}))
+		expect(found(theme.ScopePair + 1)).To(equal([]string{"[", "]"}))
+	})
+}
+
+func TestTOML(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it highlights tables, keys, and values", func(expect expect.Expectation) {
+		found := lex(highlight.TOML, "# config\n[server]\nhost = \"localhost\"\nport = 8080\n\n[[projects]]\nenabled = true\n")
+		expect(found(theme.Comment)).To(equal([]string{"# config"}))
+		expect(found(theme.Keyword)).To(equal([]string{"[server]", "[[projects]]", "true"}))
+		expect(found(theme.Type)).To(equal([]string{"host", "port", "enabled"}))
+		expect(found(theme.String)).To(equal([]string{`"localhost"`}))
+		expect(found(theme.Num)).To(equal([]string{"8080"}))
+		expect(found(theme.Bad)).To(haveLen(0))
+	})
+
+	o.Spec("it highlights arrays, inline tables, and multi-line strings", func(expect expect.Expectation) {
+		found := lex(highlight.TOML, "a = [\n  1,\n  2,\n]\nb = { c = 'd', e = {} }\nf = \"\"\"\nmulti\nline\"\"\"\n")
+		expect(found(theme.Type)).To(equal([]string{"a", "b", "c", "e", "f"}))
+		expect(found(theme.Num)).To(equal([]string{"1", "2"}))
+		expect(found(theme.String)).To(equal([]string{"'d'", "\"\"\"\nmulti\nline\"\"\""}))
+		expect(found(theme.ScopePair)).To(equal([]string{"[", "]", "{", "}"}))
+		expect(found(theme.ScopePair + 1)).To(equal([]string{"{", "}"}))
+		expect(found(theme.Bad)).To(haveLen(0))
+	})
+}
+
+func TestMarkdown(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it highlights block level syntax", func(expect expect.Expectation) {
+		found := lex(highlight.Markdown, "# Title\n\n> quote\n\n- item\n1. item\n\n---\n<!-- a\ncomment -->\n")
+		expect(found(theme.Keyword)).To(equal([]string{"# Title", "-", "1.", "---"}))
+		expect(found(theme.Comment)).To(equal([]string{"> quote", "<!-- a\ncomment -->"}))
+	})
+
+	o.Spec("it highlights code blocks", func(expect expect.Expectation) {
+		found := lex(highlight.Markdown, "```go\n# not a heading\n```\n# heading\n")
+		expect(found(theme.String)).To(equal([]string{"```go", "# not a heading", "```"}))
+		expect(found(theme.Keyword)).To(equal([]string{"# heading"}))
+	})
+
+	o.Spec("it highlights inline syntax", func(expect expect.Expectation) {
+		found := lex(highlight.Markdown, "Some `code`, **bold**, snake_case_word, and [a link](http://example.com).")
+		expect(found(theme.String)).To(equal([]string{"`code`", "(http://example.com)"}))
+		expect(found(theme.Func)).To(equal([]string{"**bold**"}))
+		expect(found(theme.Type)).To(equal([]string{"[a link]"}))
+	})
+}
+
+func TestHTML(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it highlights tags, attributes, and comments", func(expect expect.Expectation) {
+		found := lex(highlight.HTML, `<!-- hi --><a href="/x" hidden>&amp;</a>`)
+		expect(found(theme.Comment)).To(equal([]string{"<!-- hi -->"}))
+		expect(found(theme.Keyword)).To(equal([]string{"<a", ">", "</a", ">"}))
+		expect(found(theme.Type)).To(equal([]string{"href", "hidden"}))
+		expect(found(theme.String)).To(equal([]string{`"/x"`}))
+		expect(found(theme.Num)).To(equal([]string{"&amp;"}))
+	})
+
+	o.Spec("it leaves the contents of scripts alone", func(expect expect.Expectation) {
+		found := lex(highlight.HTML, `<script>if (a <b) {}</script>`)
+		expect(found(theme.Keyword)).To(equal([]string{"<script", ">", "</script", ">"}))
+	})
+}
+
+func TestTemplate(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it highlights actions separately from the text around them", func(expect expect.Expectation) {
+		found := lex(highlight.Template(highlight.LexerFunc(highlight.HTML)).Layers, `<p class="{{.Class}}">{{- range $i, $v := .Items -}}{{len $v | printf "%d"}}{{end}}</p>`)
+		expect(found(theme.Keyword)).To(equal([]string{"<p", ">", "range", "end", "</p", ">"}))
+		expect(found(theme.Type)).To(equal([]string{"class"}))
+		expect(found(theme.String)).To(equal([]string{`"`, `"`, `"%d"`}))
+		expect(found(theme.Ident)).To(equal([]string{".Class", "$i", "$v", ".Items", "$v"}))
+		expect(found(theme.Builtin)).To(equal([]string{"len", "printf"}))
+		expect(found(theme.ScopePair)).To(equal([]string{"{{", "}}", "{{-", "-}}", "{{", "}}", "{{", "}}"}))
+	})
+
+	o.Spec("it doesn't end actions at braces in strings", func(expect expect.Expectation) {
+		found := lex(highlight.Template(nil).Layers, `a {{printf "}}" | myFunc}} b`)
+		expect(found(theme.String)).To(equal([]string{`"}}"`}))
+		expect(found(theme.Func)).To(equal([]string{"myFunc"}))
+	})
+
+	o.Spec("it marks unclosed actions", func(expect expect.Expectation) {
+		found := lex(highlight.Template(nil).Layers, `a {{.Foo`)
+		expect(found(theme.Bad)).To(equal([]string{"{{"}))
+		expect(found(theme.Ident)).To(equal([]string{".Foo"}))
+	})
+
+	o.Spec("it highlights comments", func(expect expect.Expectation) {
+		found := lex(highlight.Template(nil).Layers, `{{/* a }} comment */}}`)
+		expect(found(theme.Comment)).To(equal([]string{"/* a }} comment */"}))
+	})
+}
+
+func TestSQL(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it highlights queries", func(expect expect.Expectation) {
+		found := lex(highlight.SQL, "select count(*) from \"users\" -- all\nWHERE name = 'bob' AND id > $1 and x is null")
+		expect(found(theme.Keyword)).To(equal([]string{"select", "from", "WHERE", "AND", "and", "is"}))
+		expect(found(theme.Func)).To(equal([]string{"count"}))
+		expect(found(theme.Ident)).To(equal([]string{`"users"`, "name", "id", "$1", "x"}))
+		expect(found(theme.String)).To(equal([]string{"'bob'"}))
+		expect(found(theme.Comment)).To(equal([]string{"-- all"}))
+		expect(found(theme.Nil)).To(equal([]string{"null"}))
+	})
+}
+
+func TestEmbed(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it cuts the host's spans around regions", func(expect expect.Expectation) {
+		text := "x := `select 1`"
+		layers := []input.SyntaxLayer{{Construct: theme.String, Spans: []input.Span{{Start: 5, End: 15}}}}
+		embedded := highlight.Embed(layers, []rune(text), []highlight.Region{{Start: 6, End: 14, Lexer: highlight.LexerFunc(highlight.SQL)}})
+		expect(spans(text, embedded, theme.String)).To(equal([]string{"`", "`"}))
+		expect(spans(text, embedded, theme.Keyword)).To(equal([]string{"select"}))
+		expect(spans(text, embedded, theme.Num)).To(equal([]string{"1"}))
+	})
+}
+
+func TestMove(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it moves spans after an edit", func(expect expect.Expectation) {
+		layers := []input.SyntaxLayer{{Spans: []input.Span{{Start: 0, End: 2}, {Start: 5, End: 7}}}}
+		moved := highlight.Move(layers, []input.Edit{{At: 3, New: []rune("ab")}})
+		expect(moved[0].Spans).To(equal([]input.Span{{Start: 0, End: 2}, {Start: 7, End: 9}}))
+	})
+}
+
+func TestHook(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it binds highlighting to files with a matching suffix", func(expect expect.Expectation) {
+		h := highlight.NewHook(highlight.Languages()...)
+		expect(h.FileBindables("/foo/README.MD")).To(haveLen(1))
+		expect(h.FileBindables("/foo/config.yaml")[0].Name()).To(equal("yaml-syntax-highlight"))
+		expect(h.FileBindables("/foo/main.go")).To(haveLen(0))
+		expect(h.FileBindables("/foo/index.html.tmpl")[0].Name()).To(equal("html-syntax-highlight"))
+		expect(h.FileBindables("/foo/email.tmpl")[0].Name()).To(equal("template-syntax-highlight"))
+	})
+}
@@ -0,0 +1,89 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package highlight
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/vidar/commander/bind"
+)
+
+// Language is a language that can be highlighted.
+type Language struct {
+	// Name is used to name the Highlight for the language (e.g.
+	// "json" results in "json-syntax-highlight").
+	Name string
+
+	// Suffixes are the suffixes of the file names that the
+	// language is used for (e.g. ".json").  They are matched
+	// without regard to case.
+	Suffixes []string
+
+	Lexer Lexer
+}
+
+// Languages returns the languages that are highlighted by this
+// package.
+func Languages() []Language {
+	return []Language{
+		{Name: "markdown", Suffixes: []string{".md", ".markdown"}, Lexer: LexerFunc(Markdown)},
+		{Name: "json", Suffixes: []string{".json"}, Lexer: LexerFunc(JSON)},
+		{Name: "yaml", Suffixes: []string{".yml", ".yaml"}, Lexer: LexerFunc(YAML)},
+		{Name: "toml", Suffixes: []string{".toml"}, Lexer: LexerFunc(TOML)},
+		{Name: "sql", Suffixes: []string{".sql"}, Lexer: LexerFunc(SQL)},
+
+		// html/template files need to be matched before text/template
+		// files, since they often end in ".html.tmpl".  Plain HTML
+		// files are highlighted as templates too, since that's what
+		// they usually are in go projects.
+		{Name: "html", Suffixes: []string{".html", ".htm", ".gohtml", ".html.tmpl", ".html.tpl"}, Lexer: Template(LexerFunc(HTML))},
+		{Name: "template", Suffixes: []string{".tmpl", ".tpl", ".gotmpl"}, Lexer: Template(nil)},
+	}
+}
+
+// Lookup returns the Lexer for the language named name, for
+// highlighting text that is embedded in another language.  Names are
+// matched without regard to case.
+func Lookup(name string) (Lexer, bool) {
+	for _, l := range Languages() {
+		if strings.EqualFold(l.Name, name) {
+			return l.Lexer, true
+		}
+	}
+	return nil, false
+}
+
+// Hook is a hook on focus-location which binds a *Highlight to files
+// with a suffix that matches one of its languages.
+type Hook struct {
+	languages []Language
+}
+
+// NewHook returns a Hook for languages.  When more than one language
+// matches a file, the first one is used.
+func NewHook(languages ...Language) Hook {
+	return Hook{languages: languages}
+}
+
+func (h Hook) Name() string {
+	return "syntax-highlight-hook"
+}
+
+func (h Hook) OpName() string {
+	return "focus-location"
+}
+
+func (h Hook) FileBindables(path string) []bind.Bindable {
+	name := strings.ToLower(filepath.Base(path))
+	for _, l := range h.languages {
+		for _, s := range l.Suffixes {
+			if strings.HasSuffix(name, strings.ToLower(s)) {
+				return []bind.Bindable{New(l.Name+"-syntax-highlight", l.Lexer)}
+			}
+		}
+	}
+	return nil
+}
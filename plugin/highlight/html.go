@@ -0,0 +1,126 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package highlight
+
+import (
+	"strings"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+// HTML finds the syntax layers in HTML text.  Tags are highlighted as
+// keywords, attribute names as types, attribute values as strings,
+// character references (e.g. "&amp;") as numbers, and comments and
+// doctypes as comments.  The contents of script and style elements
+// are left alone.
+func HTML(text []rune) []input.SyntaxLayer {
+	l := make(Layers)
+	for i := 0; i < len(text); {
+		switch {
+		case hasPrefix(text, i, "<!--"):
+			end := len(text)
+			if stop := index(text, i+4, "-->"); stop >= 0 {
+				end = stop + len("-->")
+			}
+			l.Add(theme.Comment, i, end)
+			i = end
+		case hasPrefix(text, i, "<!"), hasPrefix(text, i, "<?"):
+			end := len(text)
+			if stop := index(text, i, ">"); stop >= 0 {
+				end = stop + 1
+			}
+			l.Add(theme.Comment, i, end)
+			i = end
+		case text[i] == '<' && i+1 < len(text) && (isWord(text[i+1]) || text[i+1] == '/'):
+			i = htmlTag(l, text, i)
+		case text[i] == '&':
+			end := skip(text, i+1, len(text), func(r rune) bool {
+				return isWord(r) || r == '#'
+			})
+			if end > i+1 && end < len(text) && text[end] == ';' {
+				l.Add(theme.Num, i, end+1)
+				i = end + 1
+				continue
+			}
+			i++
+		default:
+			i++
+		}
+	}
+	return l.Slice()
+}
+
+// htmlTag adds the layers for the tag starting at text[i], which must
+// be its opening '<', and returns the index just after it.  If the
+// tag opens a script or style element, the index of the tag that
+// closes the element is returned instead.
+func htmlTag(l Layers, text []rune, i int) int {
+	start := i
+	nameStart := i + 1
+	if text[nameStart] == '/' {
+		nameStart++
+	}
+	nameEnd := skip(text, nameStart, len(text), isTagName)
+	l.Add(theme.Keyword, start, nameEnd)
+	name := strings.ToLower(string(text[nameStart:nameEnd]))
+	closing := nameStart > start+1
+
+	value := false
+	for i = nameEnd; i < len(text); {
+		r := text[i]
+		switch {
+		case isBlank(r), r == '\n':
+			i++
+		case r == '>', hasPrefix(text, i, "/>"):
+			end := i + 1
+			if r == '/' {
+				end++
+			}
+			l.Add(theme.Keyword, i, end)
+			if closing || r == '/' || (name != "script" && name != "style") {
+				return end
+			}
+			if stop := index(text, end, "</"+name); stop >= 0 {
+				return stop
+			}
+			return len(text)
+		case r == '<':
+			// The tag was never closed.
+			return i
+		case r == '=':
+			value = true
+			i++
+		case r == '"', r == '\'':
+			end := len(text)
+			construct := theme.Bad
+			if stop := index(text, i+1, string(r)); stop >= 0 {
+				end, construct = stop+1, theme.String
+			}
+			l.Add(construct, i, end)
+			value = false
+			i = end
+		default:
+			end := skip(text, i, len(text), func(r rune) bool {
+				return !isBlank(r) && r != '\n' && r != '=' && r != '>' && r != '<' && r != '"' && r != '\''
+			})
+			if end == i {
+				end++
+			}
+			construct := theme.Type
+			if value {
+				construct = theme.String
+			}
+			l.Add(construct, i, end)
+			value = false
+			i = end
+		}
+	}
+	return i
+}
+
+func isTagName(r rune) bool {
+	return isWord(r) || r == '-' || r == ':'
+}
@@ -0,0 +1,91 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package highlight
+
+import (
+	"unicode"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+// JSON finds the syntax layers in JSON text.  Object keys are
+// highlighted as types and brackets are rainbow highlighted.  Since
+// plenty of tools accept them in their config files, comments are
+// highlighted rather than marked as bad syntax.
+func JSON(text []rune) []input.SyntaxLayer {
+	l := make(Layers)
+	depth := 0
+	for i := 0; i < len(text); {
+		r := text[i]
+		switch {
+		case unicode.IsSpace(r), r == ',', r == ':':
+			i++
+		case r == '"':
+			end, closed := scanQuoted(text, i, len(text), true)
+			construct := theme.String
+			switch {
+			case !closed:
+				construct = theme.Bad
+			case jsonKey(text, end):
+				construct = theme.Type
+			}
+			l.Add(construct, i, end)
+			i = end
+		case r == '{', r == '[':
+			l.Add(scope(depth), i, i+1)
+			depth++
+			i++
+		case r == '}', r == ']':
+			if depth == 0 {
+				l.Add(theme.Bad, i, i+1)
+				i++
+				continue
+			}
+			depth--
+			l.Add(scope(depth), i, i+1)
+			i++
+		case r == '-', isDigit(r):
+			end := skip(text, i+1, len(text), func(r rune) bool {
+				return isDigit(r) || r == '.' || r == 'e' || r == 'E' || r == '+' || r == '-'
+			})
+			l.Add(theme.Num, i, end)
+			i = end
+		case hasPrefix(text, i, "//"):
+			end := lineEnd(text, i)
+			l.Add(theme.Comment, i, end)
+			i = end
+		case hasPrefix(text, i, "/*"):
+			end := len(text)
+			if stop := index(text, i+2, "*/"); stop >= 0 {
+				end = stop + 2
+			}
+			l.Add(theme.Comment, i, end)
+			i = end
+		case isWord(r):
+			end := skip(text, i, len(text), isWord)
+			switch string(text[i:end]) {
+			case "true", "false":
+				l.Add(theme.Keyword, i, end)
+			case "null":
+				l.Add(theme.Nil, i, end)
+			default:
+				l.Add(theme.Bad, i, end)
+			}
+			i = end
+		default:
+			l.Add(theme.Bad, i, i+1)
+			i++
+		}
+	}
+	return l.Slice()
+}
+
+// jsonKey returns whether or not the string ending just before
+// text[end] is an object key.
+func jsonKey(text []rune, end int) bool {
+	i := skip(text, end, len(text), unicode.IsSpace)
+	return i < len(text) && text[i] == ':'
+}
@@ -0,0 +1,221 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package highlight
+
+import (
+	"strings"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+// Markdown finds the syntax layers in Markdown text.  Headings, list
+// markers, and thematic breaks are highlighted as keywords; code as
+// strings; block quotes and HTML comments as comments; emphasis as
+// funcs; and link text as types.
+func Markdown(text []rune) []input.SyntaxLayer {
+	l := make(Layers)
+
+	// fence is the fence that opened the current code block, or ""
+	// if the current line isn't in a code block.
+	fence := ""
+	for start, end := 0, 0; start < len(text); start = end + 1 {
+		end = lineEnd(text, start)
+		i := skip(text, start, end, isBlank)
+		if fence != "" {
+			l.Add(theme.String, i, end)
+			if hasPrefix(text, i, fence) && skip(text, i, end, func(r rune) bool { return r == rune(fence[0]) || isBlank(r) }) == end {
+				fence = ""
+			}
+			continue
+		}
+		if i-start < 4 {
+			if fence = mdFence(text, i, end); fence != "" {
+				l.Add(theme.String, i, end)
+				continue
+			}
+		}
+		if hasPrefix(text, i, "<!--") {
+			commentEnd := len(text)
+			if stop := index(text, i+4, "-->"); stop >= 0 {
+				commentEnd = stop + len("-->")
+			}
+			l.Add(theme.Comment, i, commentEnd)
+			if commentEnd > end {
+				end = lineEnd(text, commentEnd)
+			}
+			continue
+		}
+		mdLine(l, text, i, end)
+	}
+	return l.Slice()
+}
+
+// mdFence returns the fence that opens a code block at text[i], or ""
+// if the line doesn't open a code block.
+func mdFence(text []rune, i, end int) string {
+	if i == end || (text[i] != '`' && text[i] != '~') {
+		return ""
+	}
+	n := skip(text, i, end, func(r rune) bool { return r == text[i] })
+	if n-i < 3 {
+		return ""
+	}
+	return string(text[i:n])
+}
+
+// mdLine adds the layers for a line outside of any code block,
+// starting at its first non-blank rune.
+func mdLine(l Layers, text []rune, i, end int) {
+	if i == end {
+		return
+	}
+	switch text[i] {
+	case '#':
+		n := skip(text, i, end, func(r rune) bool { return r == '#' })
+		if n-i <= 6 && (n == end || isBlank(text[n])) {
+			l.Add(theme.Keyword, i, end)
+			return
+		}
+	case '>':
+		l.Add(theme.Comment, i, end)
+		return
+	}
+	if mdBreak(text, i, end) {
+		l.Add(theme.Keyword, i, end)
+		return
+	}
+	if m := mdListMarker(text, i, end); m > i {
+		l.Add(theme.Keyword, i, m)
+		i = m
+	}
+	mdInline(l, text, i, end)
+}
+
+// mdBreak returns whether or not text[i:end] is a thematic break
+// (e.g. "***") or a setext heading underline (e.g. "===").
+func mdBreak(text []rune, i, end int) bool {
+	if !strings.ContainsRune("-*_=", text[i]) {
+		return false
+	}
+	count := 0
+	for _, r := range text[i:end] {
+		switch r {
+		case text[i]:
+			count++
+		case ' ', '\t', '\r':
+		default:
+			return false
+		}
+	}
+	return count >= 3
+}
+
+// mdListMarker returns the index just after the list marker that
+// starts at text[i], or i if there isn't one.
+func mdListMarker(text []rune, i, end int) int {
+	m := i
+	switch {
+	case text[i] == '-', text[i] == '*', text[i] == '+':
+		m++
+	case isDigit(text[i]):
+		m = skip(text, i, end, isDigit)
+		if m == end || (text[m] != '.' && text[m] != ')') {
+			return i
+		}
+		m++
+	default:
+		return i
+	}
+	if m < end && !isBlank(text[m]) {
+		return i
+	}
+	return m
+}
+
+// mdInline adds the layers for inline code, emphasis, and links in
+// text[i:end].
+func mdInline(l Layers, text []rune, i, end int) {
+	line := text[:end]
+	for i < end {
+		r := text[i]
+		switch {
+		case r == '\\':
+			i += 2
+		case r == '`':
+			n := skip(text, i, end, func(r rune) bool { return r == '`' })
+			stop := index(line, n, string(text[i:n]))
+			if stop < 0 {
+				i = n
+				continue
+			}
+			stop += n - i
+			l.Add(theme.String, i, stop)
+			i = stop
+		case r == '*', r == '_':
+			n := skip(text, i, end, func(c rune) bool { return c == r })
+			if n-i > 3 || n == end || isBlank(text[n]) || (r == '_' && i > 0 && isWord(text[i-1])) {
+				i = n
+				continue
+			}
+			stop := mdEmphasisEnd(text, n, end, string(text[i:n]))
+			if stop < 0 {
+				i = n
+				continue
+			}
+			l.Add(theme.Func, i, stop)
+			i = stop
+		case r == '[', r == '!' && i+1 < end && text[i+1] == '[':
+			stop := index(line, i+1, "]")
+			if stop < 0 {
+				i++
+				continue
+			}
+			stop++
+			l.Add(theme.Type, i, stop)
+			i = stop
+			if i < end && (text[i] == '(' || text[i] == '[') {
+				closer := ")"
+				if text[i] == '[' {
+					closer = "]"
+				}
+				if stop := index(line, i, closer); stop >= 0 {
+					l.Add(theme.String, i, stop+1)
+					i = stop + 1
+				}
+			}
+		case r == '<':
+			stop := index(line, i, ">")
+			if stop < 0 {
+				i++
+				continue
+			}
+			link := string(text[i+1 : stop])
+			if !strings.ContainsAny(link, " \t") && (strings.Contains(link, "://") || strings.Contains(link, "@")) {
+				l.Add(theme.String, i, stop+1)
+			}
+			i = stop + 1
+		default:
+			i++
+		}
+	}
+}
+
+// mdEmphasisEnd returns the index just after the delimiter that
+// closes emphasis opened by delim, searching from text[i], or -1 if
+// the emphasis isn't closed on the same line.
+func mdEmphasisEnd(text []rune, i, end int, delim string) int {
+	for ; i < end; i++ {
+		if !hasPrefix(text[:end], i, delim) || isBlank(text[i-1]) {
+			continue
+		}
+		after := i + len(delim)
+		if delim[0] == '_' && after < end && isWord(text[after]) {
+			continue
+		}
+		return after
+	}
+	return -1
+}
@@ -0,0 +1,137 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package highlight
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/nelsam/vidar/theme"
+)
+
+// lineEnd returns the index of the newline that ends the line
+// containing i, or len(text) if it's the last line.
+func lineEnd(text []rune, i int) int {
+	for ; i < len(text); i++ {
+		if text[i] == '\n' {
+			return i
+		}
+	}
+	return len(text)
+}
+
+// skip returns the index of the first rune at or after i, and before
+// end, that doesn't match f.
+func skip(text []rune, i, end int, f func(rune) bool) int {
+	for i < end && f(text[i]) {
+		i++
+	}
+	return i
+}
+
+// trimBlanks returns the index just after the last non-blank rune in
+// text[start:end].
+func trimBlanks(text []rune, start, end int) int {
+	for end > start && isBlank(text[end-1]) {
+		end--
+	}
+	return end
+}
+
+func isBlank(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\r'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// scanQuoted scans the quoted string starting at text[i], which must
+// be the opening quote.  The string may not continue past end.  It
+// returns the index just after the closing quote and whether or not
+// the closing quote was found.  If escapes is true, backslashes
+// escape the rune that follows them.
+func scanQuoted(text []rune, i, end int, escapes bool) (int, bool) {
+	quote := text[i]
+	for i++; i < end; i++ {
+		switch text[i] {
+		case '\\':
+			if escapes {
+				i++
+			}
+		case quote:
+			return i + 1, true
+		case '\n':
+			return i, false
+		}
+	}
+	return end, false
+}
+
+// hasPrefix returns whether or not the runes at text[i:] start with
+// prefix.
+func hasPrefix(text []rune, i int, prefix string) bool {
+	for _, r := range prefix {
+		if i >= len(text) || text[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// index returns the index of the first occurrence of sub in text at
+// or after i, or -1 if there is none.
+func index(text []rune, i int, sub string) int {
+	for ; i < len(text); i++ {
+		if hasPrefix(text, i, sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// scope returns the construct used for brackets that are nested
+// depth levels deep.
+func scope(depth int) theme.LanguageConstruct {
+	return theme.ScopePair + theme.LanguageConstruct(depth)
+}
+
+// scalar returns the construct for a plain (unquoted) value in YAML
+// or TOML.  Numbers are matched loosely, since dates and times are
+// highlighted the same way.
+func scalar(value string) theme.LanguageConstruct {
+	switch strings.ToLower(value) {
+	case "true", "false", "yes", "no", "on", "off":
+		return theme.Keyword
+	case "null", "~":
+		return theme.Nil
+	case "inf", "+inf", "-inf", "nan", "+nan", "-nan", ".inf", "-.inf", "+.inf", ".nan":
+		return theme.Num
+	}
+	if value == "" {
+		return theme.String
+	}
+	start := value[0]
+	if start == '-' || start == '+' {
+		if len(value) == 1 {
+			return theme.String
+		}
+		start = value[1]
+	}
+	if start < '0' || start > '9' {
+		return theme.String
+	}
+	for _, r := range value {
+		if !isDigit(r) && !strings.ContainsRune("+-_.:eExXoObBabcdefABCDEFTZ ", r) {
+			return theme.String
+		}
+	}
+	return theme.Num
+}
@@ -0,0 +1,121 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package highlight
+
+import (
+	"strings"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+var (
+	sqlKeywords = words(`
+		add all alter and as asc begin between by cascade case check
+		commit conflict constraint create cross default delete desc
+		distinct do drop else end exists false foreign from full group
+		having if in index inner insert intersect into is join key left
+		like limit not nothing offset on or order outer primary
+		references replace returning right rollback select set table
+		then transaction trigger true union unique update using values
+		view when where with`)
+	sqlTypes = words(`
+		bigint blob bool boolean char date decimal double float int
+		integer json jsonb numeric real serial smallint text time
+		timestamp timestamptz uuid varchar`)
+)
+
+// words returns a set of the words in list.
+func words(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(list) {
+		set[w] = true
+	}
+	return set
+}
+
+// SQL finds the syntax layers in SQL text.  Keywords are matched
+// without regard to case.  Column types are highlighted as types,
+// function calls as funcs, quoted identifiers and query parameters
+// (e.g. "$1" or ":name") as idents, and parentheses are rainbow
+// highlighted.
+func SQL(text []rune) []input.SyntaxLayer {
+	l := make(Layers)
+	depth := 0
+	for i := 0; i < len(text); {
+		r := text[i]
+		switch {
+		case hasPrefix(text, i, "--"):
+			end := lineEnd(text, i)
+			l.Add(theme.Comment, i, end)
+			i = end
+		case hasPrefix(text, i, "/*"):
+			end := len(text)
+			if stop := index(text, i+2, "*/"); stop >= 0 {
+				end = stop + 2
+			}
+			l.Add(theme.Comment, i, end)
+			i = end
+		case r == '\'', r == '"':
+			end := len(text)
+			construct := theme.Bad
+			if stop := index(text, i+1, string(r)); stop >= 0 {
+				end, construct = stop+1, theme.String
+				if r == '"' {
+					construct = theme.Ident
+				}
+			}
+			l.Add(construct, i, end)
+			i = end
+		case r == '(':
+			l.Add(scope(depth), i, i+1)
+			depth++
+			i++
+		case r == ')':
+			if depth == 0 {
+				l.Add(theme.Bad, i, i+1)
+				i++
+				continue
+			}
+			depth--
+			l.Add(scope(depth), i, i+1)
+			i++
+		case r == '?', (r == '$' || r == ':' || r == '@') && i+1 < len(text) && isWord(text[i+1]):
+			end := skip(text, i+1, len(text), isWord)
+			l.Add(theme.Ident, i, end)
+			i = end
+		case isDigit(r):
+			end := skip(text, i, len(text), func(r rune) bool {
+				return isDigit(r) || r == '.'
+			})
+			l.Add(theme.Num, i, end)
+			i = end
+		case isWord(r):
+			end := skip(text, i, len(text), isWord)
+			l.Add(sqlWord(text, string(text[i:end]), end), i, end)
+			i = end
+		default:
+			i++
+		}
+	}
+	return l.Slice()
+}
+
+// sqlWord returns the construct for word, which ends at text[end].
+func sqlWord(text []rune, word string, end int) theme.LanguageConstruct {
+	lower := strings.ToLower(word)
+	switch {
+	case lower == "null":
+		return theme.Nil
+	case sqlKeywords[lower]:
+		return theme.Keyword
+	case sqlTypes[lower]:
+		return theme.Type
+	}
+	if next := skip(text, end, len(text), isBlank); next < len(text) && text[next] == '(' {
+		return theme.Func
+	}
+	return theme.Ident
+}
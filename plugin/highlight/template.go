@@ -0,0 +1,174 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package highlight
+
+import (
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+var (
+	templateKeywords = map[string]bool{
+		"if": true, "else": true, "end": true, "range": true, "with": true,
+		"define": true, "template": true, "block": true, "break": true,
+		"continue": true, "true": true, "false": true,
+	}
+	templateBuiltins = map[string]bool{
+		"and": true, "or": true, "not": true, "call": true, "html": true,
+		"index": true, "slice": true, "js": true, "len": true, "print": true,
+		"printf": true, "println": true, "urlquery": true, "eq": true,
+		"ne": true, "lt": true, "le": true, "gt": true, "ge": true,
+	}
+)
+
+// Template returns a Lexer for go templates (as used by text/template
+// and html/template).  Actions are highlighted by TemplateAction, and
+// the text around them is lexed by host, which may be nil to leave it
+// unhighlighted.
+func Template(host Lexer) Lexer {
+	return Embedded(host, templateActions)
+}
+
+// templateActions finds the actions in a template.  An action that
+// isn't closed runs to the end of the text.
+func templateActions(text []rune) []Region {
+	var regions []Region
+	for i := 0; i < len(text); {
+		start := index(text, i, "{{")
+		if start < 0 {
+			break
+		}
+		end := actionEnd(text, start+2)
+		regions = append(regions, Region{Start: start, End: end, Lexer: LexerFunc(TemplateAction)})
+		i = end
+	}
+	return regions
+}
+
+// actionEnd returns the index just after the "}}" that closes the
+// action whose body starts at text[i], skipping over any strings and
+// comments in it.
+func actionEnd(text []rune, i int) int {
+	for i < len(text) {
+		switch {
+		case hasPrefix(text, i, "}}"):
+			return i + 2
+		case hasPrefix(text, i, "/*"):
+			stop := index(text, i+2, "*/")
+			if stop < 0 {
+				return len(text)
+			}
+			i = stop + 2
+		case text[i] == '"', text[i] == '\'':
+			i, _ = scanQuoted(text, i, len(text), true)
+		case text[i] == '`':
+			stop := index(text, i+1, "`")
+			if stop < 0 {
+				return len(text)
+			}
+			i = stop + 1
+		default:
+			i++
+		}
+	}
+	return len(text)
+}
+
+// TemplateAction finds the syntax layers in a single go template
+// action, from its opening "{{" to its closing "}}".  The delimiters
+// and parentheses are rainbow highlighted; keywords like range and
+// end as keywords; builtin functions as builtins; other functions as
+// funcs; and fields and variables as idents.
+func TemplateAction(text []rune) []input.SyntaxLayer {
+	l := make(Layers)
+	open := 2
+	if hasPrefix(text, open, "- ") {
+		open++
+	}
+	end := len(text)
+	openConstruct := theme.Bad
+	if end >= open+2 && hasPrefix(text, end-2, "}}") {
+		openConstruct = scope(0)
+		end -= 2
+		if end-2 >= open && hasPrefix(text, end-2, " -") {
+			end--
+		}
+		l.Add(scope(0), end, len(text))
+	}
+	l.Add(openConstruct, 0, open)
+
+	depth := 0
+	for i := open; i < end; {
+		r := text[i]
+		switch {
+		case hasPrefix(text[:end], i, "/*"):
+			stop := end
+			if j := index(text[:end], i+2, "*/"); j >= 0 {
+				stop = j + 2
+			}
+			l.Add(theme.Comment, i, stop)
+			i = stop
+		case r == '"', r == '\'':
+			stop, ok := scanQuoted(text, i, end, true)
+			construct := theme.String
+			if !ok {
+				construct = theme.Bad
+			}
+			l.Add(construct, i, stop)
+			i = stop
+		case r == '`':
+			stop, construct := end, theme.Bad
+			if j := index(text[:end], i+1, "`"); j >= 0 {
+				stop, construct = j+1, theme.String
+			}
+			l.Add(construct, i, stop)
+			i = stop
+		case r == '(':
+			depth++
+			l.Add(scope(depth), i, i+1)
+			i++
+		case r == ')':
+			if depth == 0 {
+				l.Add(theme.Bad, i, i+1)
+				i++
+				continue
+			}
+			l.Add(scope(depth), i, i+1)
+			depth--
+			i++
+		case r == '.' || r == '$':
+			stop := skip(text, i+1, end, func(r rune) bool {
+				return isWord(r) || r == '.'
+			})
+			l.Add(theme.Ident, i, stop)
+			i = stop
+		case isDigit(r), (r == '-' || r == '+') && i+1 < end && isDigit(text[i+1]):
+			stop := skip(text, i+1, end, func(r rune) bool {
+				return isWord(r) || r == '.'
+			})
+			l.Add(theme.Num, i, stop)
+			i = stop
+		case isWord(r):
+			stop := skip(text, i, end, isWord)
+			l.Add(templateWord(string(text[i:stop])), i, stop)
+			i = stop
+		default:
+			i++
+		}
+	}
+	return l.Slice()
+}
+
+func templateWord(word string) theme.LanguageConstruct {
+	switch {
+	case templateKeywords[word]:
+		return theme.Keyword
+	case templateBuiltins[word]:
+		return theme.Builtin
+	case word == "nil":
+		return theme.Nil
+	}
+	return theme.Func
+}
@@ -0,0 +1,158 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package highlight
+
+import (
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+// TOML finds the syntax layers in TOML text.  Table headers are
+// highlighted as keywords, keys as types, and the brackets of arrays
+// and inline tables are rainbow highlighted.
+func TOML(text []rune) []input.SyntaxLayer {
+	l := make(Layers)
+
+	// open holds the brackets of the arrays and inline tables that
+	// haven't been closed yet.
+	var open []rune
+	key := true
+	for i := 0; i < len(text); {
+		r := text[i]
+		switch {
+		case r == '\n':
+			if len(open) == 0 {
+				key = true
+			}
+			i++
+		case isBlank(r):
+			i++
+		case r == '#':
+			end := lineEnd(text, i)
+			l.Add(theme.Comment, i, end)
+			i = end
+		case key && len(open) == 0 && r == '[':
+			end := lineEnd(text, i)
+			stop := index(text[:end], i, "]")
+			if stop < 0 {
+				l.Add(theme.Bad, i, end)
+				i = end
+				continue
+			}
+			stop++
+			if hasPrefix(text[:end], stop, "]") {
+				stop++
+			}
+			l.Add(theme.Keyword, i, stop)
+			i = stop
+			key = false
+		case r == ']', r == '}':
+			if len(open) == 0 {
+				l.Add(theme.Bad, i, i+1)
+				i++
+				continue
+			}
+			open = open[:len(open)-1]
+			l.Add(scope(len(open)), i, i+1)
+			i++
+		case r == ',':
+			key = len(open) > 0 && open[len(open)-1] == '{'
+			i++
+		case key:
+			next := tomlKey(l, text, i)
+			if next == i {
+				next++
+			}
+			i = next
+			key = false
+		case r == '=':
+			i++
+		case r == '"', r == '\'':
+			i = tomlString(l, text, i)
+		case r == '[', r == '{':
+			l.Add(scope(len(open)), i, i+1)
+			open = append(open, r)
+			key = r == '{'
+			i++
+		default:
+			end := skip(text, i, len(text), func(r rune) bool {
+				return isWord(r) || r == '+' || r == '-' || r == '.' || r == ':'
+			})
+			if end == i {
+				end++
+			}
+			construct := theme.Bad
+			switch word := string(text[i:end]); word {
+			case "true", "false":
+				construct = theme.Keyword
+			default:
+				if scalar(word) == theme.Num {
+					construct = theme.Num
+				}
+			}
+			l.Add(construct, i, end)
+			i = end
+		}
+	}
+	return l.Slice()
+}
+
+// tomlKey adds the layer for the key starting at text[i] and returns
+// the index just after it.  Dotted keys are highlighted as a single
+// key.
+func tomlKey(l Layers, text []rune, i int) int {
+	end := lineEnd(text, i)
+	j := i
+	for j < end && text[j] != '=' {
+		switch text[j] {
+		case '"', '\'':
+			j, _ = scanQuoted(text, j, end, text[j] == '"')
+		case '#', ',', '}':
+			l.Add(theme.Bad, i, trimBlanks(text, i, j))
+			return j
+		default:
+			j++
+		}
+	}
+	construct := theme.Type
+	if j == end {
+		construct = theme.Bad
+	}
+	l.Add(construct, i, trimBlanks(text, i, j))
+	return j
+}
+
+// tomlString adds the layer for the string starting at text[i] and
+// returns the index just after it.  Multi-line strings are supported.
+func tomlString(l Layers, text []rune, i int) int {
+	quote := string([]rune{text[i], text[i], text[i]})
+	if !hasPrefix(text, i, quote) {
+		end, closed := scanQuoted(text, i, lineEnd(text, i), text[i] == '"')
+		construct := theme.String
+		if !closed {
+			construct = theme.Bad
+		}
+		l.Add(construct, i, end)
+		return end
+	}
+	end := len(text)
+	for j := i + 3; j < len(text); j++ {
+		if text[j] == '\\' && text[i] == '"' {
+			j++
+			continue
+		}
+		if hasPrefix(text, j, quote) {
+			end = j + 3
+			// Up to two quotes are allowed just before the
+			// closing quotes.
+			for end < len(text) && text[end] == text[i] && end-j < 5 {
+				end++
+			}
+			break
+		}
+	}
+	l.Add(theme.String, i, end)
+	return end
+}
@@ -0,0 +1,185 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package highlight
+
+import (
+	"strings"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/theme"
+)
+
+// YAML finds the syntax layers in YAML text.  Keys are highlighted as
+// types; anchors, aliases, and tags as funcs; and block scalars as
+// strings.
+//
+// YAML is lexed a line at a time, so flow collections and quoted
+// strings that span multiple lines are only partially highlighted.
+func YAML(text []rune) []input.SyntaxLayer {
+	l := make(Layers)
+
+	// block is the indentation of the line that started the current
+	// block scalar, or -1 if there isn't one.
+	block := -1
+	for start, end := 0, 0; start < len(text); start = end + 1 {
+		end = lineEnd(text, start)
+		i := skip(text, start, end, isBlank)
+		indent := i - start
+		if block >= 0 {
+			if i == end || indent > block {
+				l.Add(theme.String, i, end)
+				continue
+			}
+			block = -1
+		}
+		if yamlLine(l, text, i, end) {
+			block = indent
+		}
+	}
+	return l.Slice()
+}
+
+// yamlLine adds the layers for a line, starting at its first
+// non-blank rune.  It returns whether or not the line starts a block
+// scalar.
+func yamlLine(l Layers, text []rune, i, end int) bool {
+	if i == end {
+		return false
+	}
+	bol := i == 0 || text[i-1] == '\n'
+	switch {
+	case text[i] == '#':
+		l.Add(theme.Comment, i, end)
+		return false
+	case bol && text[i] == '%':
+		l.Add(theme.Keyword, i, end)
+		return false
+	case bol && (hasPrefix(text, i, "---") || hasPrefix(text, i, "...")) && (i+3 == end || isBlank(text[i+3])):
+		l.Add(theme.Keyword, i, i+3)
+		i = skip(text, i+3, end, isBlank)
+	}
+	for i < end && (text[i] == '-' || text[i] == '?') && (i+1 == end || isBlank(text[i+1])) {
+		l.Add(theme.Keyword, i, i+1)
+		i = skip(text, i+1, end, isBlank)
+	}
+	if colon := yamlKey(text, i, end); colon >= 0 {
+		l.Add(theme.Type, i, trimBlanks(text, i, colon))
+		i = colon + 1
+	}
+	return yamlValue(l, text, i, end)
+}
+
+// yamlKey returns the index of the colon that ends the key starting
+// at text[i], or -1 if there isn't a key.
+func yamlKey(text []rune, i, end int) int {
+	if i == end {
+		return -1
+	}
+	j := i
+	switch text[i] {
+	case '"', '\'':
+		j, _ = scanQuoted(text, i, end, text[i] == '"')
+		j = skip(text, j, end, isBlank)
+	case '[', '{', '#', '|', '>', '&', '*', '!':
+		return -1
+	}
+	for ; j < end; j++ {
+		switch text[j] {
+		case '#':
+			if isBlank(text[j-1]) {
+				return -1
+			}
+		case ':':
+			if j+1 == end || isBlank(text[j+1]) {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// yamlValue adds the layers for the value starting at text[i].  It
+// returns whether or not the value starts a block scalar.
+func yamlValue(l Layers, text []rune, i, end int) bool {
+	for i < end {
+		r := text[i]
+		switch {
+		case isBlank(r):
+			i++
+		case r == '#':
+			l.Add(theme.Comment, i, end)
+			return false
+		case r == '&', r == '*', r == '!':
+			e := skip(text, i+1, end, func(r rune) bool { return !isBlank(r) })
+			l.Add(theme.Func, i, e)
+			i = e
+		case r == '|', r == '>':
+			e := skip(text, i+1, end, func(r rune) bool {
+				return r == '-' || r == '+' || isDigit(r)
+			})
+			l.Add(theme.Keyword, i, e)
+			yamlValue(l, text, e, end)
+			return true
+		case r == '"', r == '\'':
+			e, _ := scanQuoted(text, i, end, r == '"')
+			l.Add(theme.String, i, e)
+			i = e
+		case r == '[', r == '{':
+			yamlFlow(l, text, i, end)
+			return false
+		default:
+			e := i + 1
+			for e < end && !(text[e] == '#' && isBlank(text[e-1])) {
+				e++
+			}
+			vEnd := trimBlanks(text, i, e)
+			l.Add(scalar(string(text[i:vEnd])), i, vEnd)
+			i = e
+		}
+	}
+	return false
+}
+
+// yamlFlow adds the layers for a flow collection (e.g. [a, b] or
+// {a: b}) starting at text[i].
+func yamlFlow(l Layers, text []rune, i, end int) {
+	depth := 0
+	for i < end {
+		r := text[i]
+		switch {
+		case isBlank(r), r == ',', r == ':':
+			i++
+		case r == '[', r == '{':
+			l.Add(scope(depth), i, i+1)
+			depth++
+			i++
+		case r == ']', r == '}':
+			if depth > 0 {
+				depth--
+			}
+			l.Add(scope(depth), i, i+1)
+			i++
+		case r == '#' && isBlank(text[i-1]):
+			l.Add(theme.Comment, i, end)
+			return
+		case r == '"', r == '\'':
+			e, _ := scanQuoted(text, i, end, r == '"')
+			l.Add(theme.String, i, e)
+			i = e
+		default:
+			e := i + 1
+			for e < end && !strings.ContainsRune(",[]{}", text[e]) && !(text[e] == ':' && (e+1 == end || isBlank(text[e+1]))) {
+				e++
+			}
+			vEnd := trimBlanks(text, i, e)
+			construct := scalar(string(text[i:vEnd]))
+			if e < end && text[e] == ':' {
+				construct = theme.Type
+			}
+			l.Add(construct, i, vEnd)
+			i = e
+		}
+	}
+}
@@ -3,5 +3,6 @@
 // accompanying UNLICENSE file.
 
 // Package license contains plugins for working with project licenses.
-// Currently, it's limited to updating the blurb in go file headers.
+// Currently, it's limited to updating the blurb in go file headers,
+// either in the current file or across a whole project.
 package license
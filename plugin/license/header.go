@@ -0,0 +1,50 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package license
+
+import (
+	"strings"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// HeaderEdit returns the edit that replaces the license header at the
+// start of text with header, or nil if text already starts with
+// header.
+//
+// License headers should be the first comment block in a file, should
+// have an empty line following them, and should not contain build tag
+// comments.  If text has no such block, header is inserted at the
+// start of text.
+func HeaderEdit(text, header string) *input.Edit {
+	header = strings.TrimSpace(header)
+	if header != "" {
+		header += "\n\n"
+	}
+	end, offset := 0, 0
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		content := strings.TrimSuffix(line, "\n")
+		if !strings.HasPrefix(content, "//") && i+1 != len(lines) {
+			if content == "" {
+				end = offset + len(line)
+			}
+			break
+		}
+		offset += len(line)
+	}
+	old := text[:end]
+	if strings.Contains(old, "// +build ") || strings.Contains(old, "//go:build ") {
+		old = ""
+	}
+	if old == header {
+		return nil
+	}
+	return &input.Edit{
+		At:  0,
+		Old: []rune(old),
+		New: []rune(header),
+	}
+}
@@ -6,7 +6,6 @@ package license
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/vidar/commander/bind"
@@ -23,19 +22,12 @@ type Applier interface {
 	Apply(input.Editor, ...input.Edit)
 }
 
-type Controller interface {
-	LineCount() int
-	LineStart(int) int
-	Line(int) string
-}
-
 type HeaderUpdate struct {
 	status.General
 
 	editor    input.Editor
 	applier   Applier
 	projecter Projecter
-	ctrl      Controller
 }
 
 func NewHeaderUpdate(theme gxui.Theme) *HeaderUpdate {
@@ -67,10 +59,8 @@ func (u *HeaderUpdate) Store(target interface{}) bind.Status {
 		u.applier = src
 	case input.Editor:
 		u.editor = src
-	case Controller:
-		u.ctrl = src
 	}
-	if u.projecter != nil && u.applier != nil && u.editor != nil && u.ctrl != nil {
+	if u.projecter != nil && u.applier != nil && u.editor != nil {
 		return bind.Done
 	}
 	return bind.Waiting
@@ -80,7 +70,6 @@ func (u *HeaderUpdate) Reset() {
 	u.projecter = nil
 	u.applier = nil
 	u.editor = nil
-	u.ctrl = nil
 }
 
 func (u *HeaderUpdate) Exec() error {
@@ -93,36 +82,10 @@ func (u *HeaderUpdate) Exec() error {
 }
 
 func (u *HeaderUpdate) LicenseEdit() *input.Edit {
-	licenseHeader := strings.TrimSpace(u.projecter.Project().LicenseHeader())
-	if licenseHeader != "" {
-		licenseHeader += "\n\n"
-	}
-
-	// License headers should be the first comment block on a file,
-	// should have an empty line following them, and should not
-	// contain build tag comments
-	firstCommentBlockEnd := 0
-	numberOfLines := u.ctrl.LineCount()
-	for i := 0; i < numberOfLines; i++ {
-		line := u.ctrl.Line(i)
-		if !strings.HasPrefix(string(line), "//") && i+1 != numberOfLines {
-			if len(line) == 0 {
-				firstCommentBlockEnd = u.ctrl.LineStart(i + 1)
-			}
-			break
-		}
-	}
-	commentText := u.editor.Text()[:firstCommentBlockEnd]
-	if strings.Contains(commentText, "// +build ") {
-		commentText = ""
-	}
-	if commentText == licenseHeader {
+	header := u.projecter.Project().LicenseHeaderFor(u.editor.Filepath())
+	edit := HeaderEdit(u.editor.Text(), header)
+	if edit == nil {
 		u.Info = "license is already set correctly"
-		return nil
-	}
-	return &input.Edit{
-		At:  0,
-		Old: []rune(commentText),
-		New: []rune(licenseHeader),
 	}
+	return edit
 }
@@ -38,5 +38,6 @@ func (h GolangHook) FileBindables(path string) []bind.Bindable {
 func Bindables(cmdr command.Commander, driver gxui.Driver, theme gxui.Theme) []bind.Bindable {
 	return []bind.Bindable{
 		GolangHook{Theme: theme},
+		license.NewRelicense(driver, theme),
 	}
 }
@@ -0,0 +1,322 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package license
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/setting"
+)
+
+const (
+	includedText = "[x]"
+	excludedText = "[ ]"
+)
+
+var errRelicenseStopped = errors.New("relicense stopped")
+
+// relicenseJob is a project-wide header update that is being
+// previewed.
+type relicenseJob struct {
+	project setting.Project
+
+	// open holds the editors for files in the project that are
+	// open.  Those files are read and edited in place; all others
+	// are read from and written to disk.
+	open    map[string]input.Editor
+	applier Applier
+}
+
+// headerChange is the header update for a single file.
+type headerChange struct {
+	path     string
+	text     string
+	edit     input.Edit
+	excluded bool
+}
+
+// Preview is a panel that lists every file whose license header would
+// change in a relicense.  Files can be excluded, and nothing is
+// written until the changes are applied.
+type Preview struct {
+	mixins.LinearLayout
+
+	driver gxui.Driver
+	theme  gxui.Theme
+
+	status  gxui.Label
+	list    gxui.LinearLayout
+	paneler Paneler
+
+	// job and changes are only accessed on the UI goroutine.
+	job     *relicenseJob
+	changes []*headerChange
+
+	lock sync.Mutex
+	stop chan struct{}
+}
+
+// NewPreview creates a *Preview.
+func NewPreview(driver gxui.Driver, theme gxui.Theme) *Preview {
+	p := &Preview{
+		driver: driver,
+		theme:  theme,
+		status: theme.CreateLabel(),
+		list:   theme.CreateLinearLayout(),
+	}
+	p.Init(p, theme)
+	p.SetDirection(gxui.TopToBottom)
+
+	header := theme.CreateLinearLayout()
+	header.SetDirection(gxui.LeftToRight)
+	closer := theme.CreateButton()
+	closer.SetText("x")
+	closer.OnClick(func(gxui.MouseEvent) {
+		p.cancel()
+		p.job = nil
+		p.changes = nil
+		if p.paneler != nil {
+			p.paneler.HidePanel(p)
+		}
+	})
+	header.AddChild(closer)
+	apply := theme.CreateButton()
+	apply.SetText("Apply")
+	apply.OnClick(func(gxui.MouseEvent) {
+		p.apply()
+	})
+	header.AddChild(apply)
+	header.AddChild(p.status)
+	p.AddChild(header)
+
+	p.list.SetDirection(gxui.TopToBottom)
+	scrollable := theme.CreateScrollLayout()
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(p.list)
+	p.AddChild(scrollable)
+	return p
+}
+
+// Start cancels any running search, shows p using paneler, and starts
+// looking for files whose headers don't match job's template in the
+// background.  It must be called on the UI goroutine.
+func (p *Preview) Start(paneler Paneler, job relicenseJob) {
+	p.cancel()
+	p.paneler = paneler
+	p.job = &job
+	p.changes = nil
+	p.list.RemoveAll()
+
+	// Open editors can only be read on the UI goroutine, so their
+	// text is copied before searching.
+	texts := make(map[string]string, len(job.open))
+	for path, e := range job.open {
+		texts[path] = e.Text()
+	}
+
+	p.lock.Lock()
+	stop := make(chan struct{})
+	p.stop = stop
+	p.lock.Unlock()
+
+	p.status.SetText(fmt.Sprintf("Checking license headers in %s...", job.project.Path))
+	if !paneler.HasPanel(p) {
+		paneler.ShowPanel(p)
+	}
+	go p.search(stop, job, texts)
+}
+
+func (p *Preview) cancel() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	p.stop = nil
+}
+
+func (p *Preview) searching() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.stop != nil
+}
+
+func (p *Preview) search(stop chan struct{}, job relicenseJob, texts map[string]string) {
+	root := job.project.Path
+	var changes []*headerChange
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(info.Name(), ".") || setting.IgnoredDir(root, path)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" || !info.Mode().IsRegular() || setting.Ignored(root, path, false) {
+			return nil
+		}
+		select {
+		case <-stop:
+			return errRelicenseStopped
+		default:
+		}
+		text, ok := texts[path]
+		if !ok {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			text = string(b)
+		}
+		if edit := HeaderEdit(text, job.project.LicenseHeaderFor(path)); edit != nil {
+			changes = append(changes, &headerChange{path: path, text: text, edit: *edit})
+		}
+		return nil
+	})
+
+	p.driver.Call(func() {
+		p.lock.Lock()
+		current := p.stop == stop
+		if current {
+			p.stop = nil
+		}
+		p.lock.Unlock()
+		if !current {
+			return
+		}
+		p.changes = changes
+		for _, c := range changes {
+			p.list.AddChild(p.row(c))
+		}
+		p.updateStatus()
+	})
+}
+
+// row returns a row for c, with a toggle to exclude it.
+func (p *Preview) row(c *headerChange) gxui.Control {
+	row := p.theme.CreateLinearLayout()
+	row.SetDirection(gxui.LeftToRight)
+	row.SetMargin(math.Spacing{L: 10})
+
+	toggle := p.theme.CreateButton()
+	toggle.SetText(includedText)
+	toggle.OnClick(func(gxui.MouseEvent) {
+		c.excluded = !c.excluded
+		toggle.SetText(includedText)
+		if c.excluded {
+			toggle.SetText(excludedText)
+		}
+		p.updateStatus()
+	})
+	row.AddChild(toggle)
+
+	name, err := filepath.Rel(p.job.project.Path, c.path)
+	if err != nil {
+		name = c.path
+	}
+	action := "add header"
+	if n := strings.Count(string(c.edit.Old), "\n"); n > 0 {
+		action = fmt.Sprintf("replace %d line header", n)
+	}
+	l := p.theme.CreateLabel()
+	l.SetText(fmt.Sprintf("%s: %s", name, action))
+	row.AddChild(l)
+	return row
+}
+
+func (p *Preview) updateStatus() {
+	included := 0
+	for _, c := range p.changes {
+		if !c.excluded {
+			included++
+		}
+	}
+	p.status.SetText(fmt.Sprintf("Update the license header in %d of %d files", included, len(p.changes)))
+}
+
+// read returns the current text of path, using the open editor for it
+// if there is one.
+func (p *Preview) read(path string) (string, error) {
+	if e, ok := p.job.open[path]; ok {
+		return e.Text(), nil
+	}
+	b, err := ioutil.ReadFile(path)
+	return string(b), err
+}
+
+// apply updates the header of every file that hasn't been excluded.
+// Nothing is changed if any of the files have changed since they were
+// checked.
+func (p *Preview) apply() {
+	if p.job == nil {
+		return
+	}
+	if p.searching() {
+		p.status.SetText("Still checking; wait for it to finish before applying")
+		return
+	}
+	for _, c := range p.changes {
+		text, err := p.read(c.path)
+		if err != nil {
+			p.status.SetText(fmt.Sprintf("Could not read %s: %s", c.path, err))
+			return
+		}
+		if text != c.text {
+			p.status.SetText(fmt.Sprintf("%s has changed since it was checked; run relicense again", filepath.Base(c.path)))
+			return
+		}
+	}
+
+	var (
+		failed []string
+		count  int
+	)
+	for _, c := range p.changes {
+		if c.excluded {
+			continue
+		}
+		if e, ok := p.job.open[c.path]; ok {
+			p.job.applier.Apply(e, c.edit)
+		} else if err := writeHeader(c.path, c.text, c.edit); err != nil {
+			log.Printf("Error writing license header to %s: %s", c.path, err)
+			failed = append(failed, filepath.Base(c.path))
+			continue
+		}
+		count++
+	}
+	p.job = nil
+	p.changes = nil
+	p.list.RemoveAll()
+	if len(failed) > 0 {
+		p.status.SetText(fmt.Sprintf("Updated %d license headers, but could not write %s", count, strings.Join(failed, ", ")))
+		return
+	}
+	p.status.SetText(fmt.Sprintf("Updated %d license headers", count))
+}
+
+// writeHeader applies edit, which must be at the start of text, and
+// writes the result to path.
+func writeHeader(path, text string, edit input.Edit) error {
+	finfo, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	result := string(edit.New) + text[len(string(edit.Old)):]
+	return ioutil.WriteFile(path, []byte(result), finfo.Mode())
+}
@@ -0,0 +1,104 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package license
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// ProjectEditors is a type that knows the current project and the
+// editors that are open in it.
+type ProjectEditors interface {
+	Project() setting.Project
+	OpenEditors() []input.Editor
+}
+
+// A Paneler is a type that can display panels below the editor.
+type Paneler interface {
+	ShowPanel(gxui.Control)
+	HidePanel(gxui.Control)
+	HasPanel(gxui.Control) bool
+}
+
+// Relicense is a command which updates the license header of every Go
+// file in the current project from the project's header template.
+// The files that would change are listed in a *Preview, where they
+// can be excluded before anything is written.
+type Relicense struct {
+	status.General
+
+	preview *Preview
+
+	editors ProjectEditors
+	applier Applier
+	paneler Paneler
+}
+
+func NewRelicense(driver gxui.Driver, theme gxui.Theme) *Relicense {
+	r := &Relicense{preview: NewPreview(driver, theme)}
+	r.Theme = theme
+	return r
+}
+
+func (r *Relicense) Name() string {
+	return "relicense"
+}
+
+func (r *Relicense) Menu() string {
+	return "Golang"
+}
+
+func (r *Relicense) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (r *Relicense) Reset() {
+	r.Clear()
+	r.editors = nil
+	r.applier = nil
+	r.paneler = nil
+}
+
+func (r *Relicense) Store(target interface{}) bind.Status {
+	switch src := target.(type) {
+	case ProjectEditors:
+		r.editors = src
+	case Applier:
+		r.applier = src
+	case Paneler:
+		r.paneler = src
+	}
+	if r.editors != nil && r.applier != nil && r.paneler != nil {
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (r *Relicense) Exec() error {
+	proj := r.editors.Project()
+	if strings.TrimSpace(proj.LicenseHeader()) == "" {
+		r.Warn = fmt.Sprintf("%s has no license header template", proj.Name)
+		return nil
+	}
+	open := make(map[string]input.Editor)
+	for _, e := range r.editors.OpenEditors() {
+		if strings.HasPrefix(e.Filepath(), proj.Path) {
+			open[e.Filepath()] = e
+		}
+	}
+	r.preview.Start(r.paneler, relicenseJob{
+		project: proj,
+		open:    open,
+		applier: r.applier,
+	})
+	return nil
+}
@@ -0,0 +1,321 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const shutdownTimeout = 2 * time.Second
+
+// Client is a client connection to a single language server
+// process.
+type Client struct {
+	server  Server
+	root    string
+	environ []string
+	cmd     *exec.Cmd
+	conn    *conn
+
+	mu       sync.Mutex
+	versions map[string]int
+
+	diagMu      sync.RWMutex
+	diagnostics map[string][]Diagnostic
+	onDiag      []func(path string, diags []Diagnostic)
+}
+
+// Start starts the language server described by s in the directory
+// root and initializes it.
+func Start(ctx context.Context, s Server, root string, environ []string) (*Client, error) {
+	cmd := exec.Command(s.Command, s.Args...)
+	cmd.Dir = root
+	cmd.Env = environ
+	cmd.Stderr = logWriter(s.Command)
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("lsp: could not start %s: %s", s.Command, err)
+	}
+	c := &Client{
+		server:      s,
+		root:        root,
+		environ:     environ,
+		cmd:         cmd,
+		versions:    make(map[string]int),
+		diagnostics: make(map[string][]Diagnostic),
+	}
+	c.conn = newConn(out, in, c.handle)
+	if err := c.initialize(ctx); err != nil {
+		cmd.Process.Kill()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) initialize(ctx context.Context) error {
+	root := URI(c.root)
+	params := initializeParams{
+		ProcessID:    os.Getpid(),
+		RootURI:      root,
+		Capabilities: clientCapabilities,
+		WorkspaceFolders: []workspaceFolder{
+			{URI: root, Name: filepath.Base(c.root)},
+		},
+	}
+	if err := c.conn.call(ctx, "initialize", params, nil); err != nil {
+		return fmt.Errorf("lsp: could not initialize %s: %s", c.server.Command, err)
+	}
+	return c.conn.notify("initialized", struct{}{})
+}
+
+// Root returns the root directory that c was started in.
+func (c *Client) Root() string {
+	return c.root
+}
+
+func (c *Client) handle(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "textDocument/publishDiagnostics":
+		var p publishDiagnosticsParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		c.setDiagnostics(Path(p.URI), p.Diagnostics)
+		return nil, nil
+	case "workspace/configuration":
+		// We don't have any server-specific configuration, so
+		// every requested item gets a null value.
+		var p configurationParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		return make([]interface{}, len(p.Items)), nil
+	case "window/logMessage", "window/showMessage":
+		var p struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(params, &p)
+		log.Printf("%s: %s", c.server.Command, p.Message)
+		return nil, nil
+	case "window/workDoneProgress/create", "client/registerCapability", "client/unregisterCapability":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("method %s not supported", method)
+}
+
+func (c *Client) setDiagnostics(path string, diags []Diagnostic) {
+	c.diagMu.Lock()
+	c.diagnostics[path] = diags
+	callbacks := c.onDiag
+	c.diagMu.Unlock()
+	for _, cb := range callbacks {
+		cb(path, diags)
+	}
+}
+
+// OnDiagnostics registers a callback to be called whenever the server
+// publishes diagnostics.  The callback is called outside of the UI
+// goroutine.
+func (c *Client) OnDiagnostics(cb func(path string, diags []Diagnostic)) {
+	c.diagMu.Lock()
+	defer c.diagMu.Unlock()
+	c.onDiag = append(c.onDiag, cb)
+}
+
+// Diagnostics returns the most recent diagnostics that the server
+// published for path.
+func (c *Client) Diagnostics(path string) []Diagnostic {
+	c.diagMu.RLock()
+	defer c.diagMu.RUnlock()
+	return c.diagnostics[path]
+}
+
+// Open tells the server that path has been opened with the passed in
+// text.
+func (c *Client) Open(path, text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.open(path, text)
+}
+
+func (c *Client) open(path, text string) error {
+	c.versions[path] = 1
+	return c.conn.notify("textDocument/didOpen", didOpenParams{
+		TextDocument: textDocumentItem{
+			URI:        URI(path),
+			LanguageID: c.server.LanguageID,
+			Version:    1,
+			Text:       text,
+		},
+	})
+}
+
+// Change tells the server that the text of path has changed.  The
+// full text is always sent, which all servers are required to
+// support.  If path has not been opened, it will be opened instead.
+func (c *Client) Change(path, text string) error {
+	// The lock is held while sending so that versions are always
+	// sent in order.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.versions[path]
+	if !ok {
+		return c.open(path, text)
+	}
+	v++
+	c.versions[path] = v
+	return c.conn.notify("textDocument/didChange", didChangeParams{
+		TextDocument:   versionedTextDocumentIdentifier{URI: URI(path), Version: v},
+		ContentChanges: []contentChange{{Text: text}},
+	})
+}
+
+// Saved tells the server that path has been written to disk.  It
+// does nothing if path isn't open.
+func (c *Client) Saved(path string) error {
+	if !c.isOpen(path) {
+		return nil
+	}
+	return c.conn.notify("textDocument/didSave", documentParams{
+		TextDocument: textDocumentIdentifier{URI: URI(path)},
+	})
+}
+
+// Close tells the server that path has been closed.  It does nothing
+// if path isn't open.
+func (c *Client) Close(path string) error {
+	c.mu.Lock()
+	_, open := c.versions[path]
+	delete(c.versions, path)
+	c.mu.Unlock()
+	if !open {
+		return nil
+	}
+	return c.conn.notify("textDocument/didClose", documentParams{
+		TextDocument: textDocumentIdentifier{URI: URI(path)},
+	})
+}
+
+func (c *Client) isOpen(path string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, open := c.versions[path]
+	return open
+}
+
+func (c *Client) position(path string, text []rune, offset int) textDocumentPositionParams {
+	return textDocumentPositionParams{
+		TextDocument: textDocumentIdentifier{URI: URI(path)},
+		Position:     PositionAt(text, offset),
+	}
+}
+
+// Completion requests completions at offset in path.  The server's
+// copy of path is updated to text before the request is made.
+func (c *Client) Completion(ctx context.Context, path string, text []rune, offset int) ([]CompletionItem, error) {
+	if err := c.Change(path, string(text)); err != nil {
+		return nil, err
+	}
+	var raw json.RawMessage
+	if err := c.conn.call(ctx, "textDocument/completion", c.position(path, text, offset), &raw); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if raw[0] == '[' {
+		var items []CompletionItem
+		err := json.Unmarshal(raw, &items)
+		return items, err
+	}
+	var l completionList
+	err := json.Unmarshal(raw, &l)
+	return l.Items, err
+}
+
+// Definition requests the locations that the symbol at offset in path
+// is defined at.
+func (c *Client) Definition(ctx context.Context, path string, text []rune, offset int) ([]Location, error) {
+	var raw json.RawMessage
+	if err := c.conn.call(ctx, "textDocument/definition", c.position(path, text, offset), &raw); err != nil {
+		return nil, err
+	}
+	return locations(raw)
+}
+
+// Hover requests hover text for the symbol at offset in path.
+func (c *Client) Hover(ctx context.Context, path string, text []rune, offset int) (string, error) {
+	var h struct {
+		Contents json.RawMessage `json:"contents"`
+	}
+	if err := c.conn.call(ctx, "textDocument/hover", c.position(path, text, offset), &h); err != nil {
+		return "", err
+	}
+	return hoverText(h.Contents), nil
+}
+
+// Rename requests the edits needed to rename the symbol at offset in
+// path to newName, keyed by file path.  The server's copy of path is
+// updated to text before the request is made.
+func (c *Client) Rename(ctx context.Context, path string, text []rune, offset int, newName string) (map[string][]TextEdit, error) {
+	if err := c.Change(path, string(text)); err != nil {
+		return nil, err
+	}
+	params := renameParams{
+		textDocumentPositionParams: c.position(path, text, offset),
+		NewName:                    newName,
+	}
+	var w workspaceEdit
+	if err := c.conn.call(ctx, "textDocument/rename", params, &w); err != nil {
+		return nil, err
+	}
+	return w.byPath(), nil
+}
+
+// Shutdown asks the server to shut down and waits for it to exit.
+// If it doesn't exit in time, it is killed.
+func (c *Client) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := c.conn.call(ctx, "shutdown", nil, nil)
+	if err == nil {
+		err = c.conn.notify("exit", nil)
+	}
+	done := make(chan error, 1)
+	go func() { done <- c.cmd.Wait() }()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		c.cmd.Process.Kill()
+		<-done
+	}
+	if errors.Is(err, ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+type logWriter string
+
+func (w logWriter) Write(b []byte) (int, error) {
+	log.Printf("%s: %s", string(w), b)
+	return len(b), nil
+}
@@ -0,0 +1,64 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/vidar/setting"
+	"github.com/nelsam/vidar/setting/config"
+)
+
+const configName = "lsp"
+
+type opener struct{}
+
+func (opener) Open(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+func (opener) Create(path string) (io.WriteCloser, error) {
+	return os.Create(path)
+}
+
+// Servers loads the list of configured language servers from the lsp
+// config file in vidar's config directory, falling back to
+// DefaultServers.
+func Servers() []Server {
+	c, err := config.New(opener{}, configName, setting.ConfigDir())
+	if err != nil {
+		log.Printf("Error reading lsp config: %s", err)
+		return DefaultServers
+	}
+	c.SetDefault("servers", DefaultServers)
+	servers, ok := c.Get("servers").([]Server)
+	if !ok || len(servers) == 0 {
+		return DefaultServers
+	}
+	return servers
+}
+
+// environ returns the go environment of the project that path is
+// in, which includes its build context, or the OS environment if path
+// isn't in a known project.
+func environ(path string) []string {
+	var best setting.Project
+	for _, p := range setting.Projects() {
+		if !strings.HasPrefix(path, p.Path+string(filepath.Separator)) {
+			continue
+		}
+		if len(p.Path) > len(best.Path) {
+			best = p
+		}
+	}
+	if best.Path == "" {
+		return os.Environ()
+	}
+	return best.GoEnviron()
+}
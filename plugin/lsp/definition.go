@@ -0,0 +1,132 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+const requestTimeout = 5 * time.Second
+
+type Commander interface {
+	Execute(bind.Bindable)
+}
+
+type Opener interface {
+	For(...focus.Opt) bind.Bindable
+}
+
+type CursorController interface {
+	LastCaret() int
+}
+
+// Definition is a command which uses a language server to find the
+// definition of the symbol under the cursor and opens it.
+type Definition struct {
+	status.General
+
+	pool *Pool
+
+	cmdr   Commander
+	opener Opener
+	editor input.Editor
+	ctrl   CursorController
+}
+
+func NewDefinition(pool *Pool, theme gxui.Theme) *Definition {
+	d := &Definition{pool: pool}
+	d.Theme = theme
+	return d
+}
+
+func (d *Definition) Name() string {
+	return "lsp-goto-definition"
+}
+
+func (d *Definition) Menu() string {
+	return "Language"
+}
+
+func (d *Definition) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Key: gxui.KeyF12,
+	}}
+}
+
+func (d *Definition) Reset() {
+	d.cmdr = nil
+	d.opener = nil
+	d.editor = nil
+	d.ctrl = nil
+}
+
+func (d *Definition) Store(target interface{}) bind.Status {
+	switch src := target.(type) {
+	case Commander:
+		d.cmdr = src
+	case input.Editor:
+		d.editor = src
+	case CursorController:
+		d.ctrl = src
+	case Opener:
+		d.opener = src
+	}
+	if d.cmdr != nil && d.opener != nil && d.editor != nil && d.ctrl != nil {
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (d *Definition) Exec() error {
+	path := d.editor.Filepath()
+	c, err := d.pool.Client(path, environ(path))
+	if err != nil {
+		d.Err = err.Error()
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	locs, err := c.Definition(ctx, path, d.editor.Runes(), d.ctrl.LastCaret())
+	if err != nil {
+		d.Err = fmt.Sprintf("%s: %s", c.server.Command, err)
+		return err
+	}
+	if len(locs) == 0 {
+		d.Warn = "no definition found"
+		return errors.New("lsp: no definition found")
+	}
+	l := locs[0]
+	target := Path(l.URI)
+	text, err := d.text(target)
+	if err != nil {
+		d.Err = fmt.Sprintf("could not read %s: %s", target, err)
+		return err
+	}
+	d.cmdr.Execute(d.opener.For(focus.Path(target), focus.Line(l.Range.Start.Line), focus.Column(Column(text, l.Range.Start))))
+	return nil
+}
+
+// text returns the text of the file at path, which is needed to
+// convert the server's UTF-16 columns to runes.
+func (d *Definition) text(path string) ([]rune, error) {
+	if path == d.editor.Filepath() {
+		return d.editor.Runes(), nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return []rune(string(b)), nil
+}
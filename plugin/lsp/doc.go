@@ -0,0 +1,13 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package lsp contains a client for the language server protocol
+// and bindables that feed language server results (completions,
+// definitions, hover text, renames, and diagnostics) into vidar's
+// editors.
+//
+// Language servers are configured per file extension in an "lsp"
+// config file in vidar's config directory.  By default, gopls is
+// used for go files.
+package lsp
@@ -0,0 +1,269 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/gocode"
+	"github.com/nelsam/vidar/setting"
+	"github.com/nelsam/vidar/suggestion"
+)
+
+// diagnosticSource is the source used when publishing diagnostics
+// from language servers.
+const diagnosticSource = "lsp"
+
+// Hook is a hook on focus-location which binds language server
+// bindables to files that have a configured language server.
+type Hook struct {
+	pool   *Pool
+	driver gxui.Driver
+	theme  *basic.Theme
+
+	mu      sync.Mutex
+	editors map[string]input.Editor
+
+	// preview is only accessed from the UI goroutine.
+	preview *Preview
+}
+
+// New returns a Hook that starts language servers from servers as
+// they are needed.
+func New(driver gxui.Driver, theme *basic.Theme, servers ...Server) *Hook {
+	h := &Hook{
+		pool:    NewPool(servers...),
+		driver:  driver,
+		theme:   theme,
+		editors: make(map[string]input.Editor),
+	}
+	h.pool.OnStart(func(c *Client) {
+		c.OnDiagnostics(h.diagnosed)
+	})
+	return h
+}
+
+func (h *Hook) Name() string {
+	return "lsp-hook"
+}
+
+func (h *Hook) OpName() string {
+	return "focus-location"
+}
+
+func (h *Hook) FileBindables(path string) []bind.Bindable {
+	if _, ok := h.pool.Server(path); !ok {
+		return nil
+	}
+	completions, updates := gocode.NewWithSource(h.theme, h.driver, h.suggestions)
+	return []bind.Bindable{
+		&Sync{hook: h},
+		NewDefinition(h.pool, h.theme),
+		NewHover(h.pool, h.theme),
+		NewRename(h, h.theme),
+		onSave{hook: h},
+		onClose{hook: h},
+		completions,
+		updates,
+	}
+}
+
+func (h *Hook) setEditor(e input.Editor) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.editors[e.Filepath()] = e
+}
+
+func (h *Hook) editor(path string) input.Editor {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.editors[path]
+}
+
+func (h *Hook) forget(path string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.editors, path)
+}
+
+// renamePreview returns the *Preview that renames are shown in,
+// creating it the first time it's needed.
+func (h *Hook) renamePreview() *Preview {
+	if h.preview == nil {
+		h.preview = NewPreview(h.theme)
+	}
+	return h.preview
+}
+
+// suggestions loads completions for path.  The environment that the
+// completion list passes in is ignored, so that every request for
+// path starts its server with the same environment.
+func (h *Hook) suggestions(_ []string, path, contents string, offset int) ([]suggestion.Suggestion, error) {
+	c, err := h.pool.Client(path, environ(path))
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	items, err := c.Completion(ctx, path, []rune(contents), offset)
+	if err != nil {
+		return nil, err
+	}
+	s := make([]suggestion.Suggestion, 0, len(items))
+	for _, i := range items {
+		s = append(s, suggestion.Suggestion{Name: i.Text(), Signature: i.Detail, Doc: i.Doc()})
+	}
+	return s, nil
+}
+
+func (h *Hook) diagnosed(path string, diags []Diagnostic) {
+	e := h.editor(path)
+	if e == nil {
+		return
+	}
+	h.driver.Call(func() {
+		applyDiagnostics(e, diags)
+	})
+}
+
+// applyDiagnostics publishes diags as e's lsp diagnostics.
+func applyDiagnostics(e input.Editor, diags []Diagnostic) {
+	text := e.Runes()
+	converted := make([]input.Diagnostic, 0, len(diags))
+	for _, d := range diags {
+		converted = append(converted, input.Diagnostic{
+			Severity:    d.Severity.input(),
+			Range:       input.Span{Start: Offset(text, d.Range.Start), End: Offset(text, d.Range.End)},
+			Message:     d.Message,
+			Source:      d.Source,
+			Unnecessary: d.unnecessary(),
+		})
+	}
+	e.SetDiagnostics(diagnosticSource, converted)
+}
+
+// Sync is a hook on the input-handler which keeps language servers
+// up to date with the text in the editor.
+type Sync struct {
+	hook *Hook
+}
+
+func (s *Sync) Name() string {
+	return "lsp-sync"
+}
+
+func (s *Sync) OpName() string {
+	return "input-handler"
+}
+
+func (s *Sync) Init(e input.Editor, text []rune) {
+	s.hook.setEditor(e)
+	path := e.Filepath()
+	go func() {
+		c, err := s.hook.pool.Wait(path, environ(path))
+		if err != nil {
+			log.Printf("lsp: %s", err)
+			return
+		}
+		if err := c.Change(path, string(text)); err != nil {
+			log.Printf("lsp: error sending %s to %s: %s", path, c.server.Command, err)
+			return
+		}
+		s.hook.diagnosed(path, c.Diagnostics(path))
+	}()
+}
+
+func (s *Sync) TextChanged(ctx context.Context, e input.Editor, _ []input.Edit) {
+	path := e.Filepath()
+	c, err := s.hook.pool.Client(path, environ(path))
+	if err == ErrStarting {
+		// Init sends the text once the server has started.
+		return
+	}
+	if err != nil {
+		log.Printf("lsp: %s", err)
+		return
+	}
+	if err := c.Change(path, e.Text()); err != nil {
+		log.Printf("lsp: error sending changes in %s to %s: %s", path, c.server.Command, err)
+	}
+}
+
+func (s *Sync) Apply(input.Editor) error {
+	// Diagnostics are applied as the server publishes them.
+	return nil
+}
+
+// onSave is a hook that tells language servers when a file they have
+// open is saved.
+type onSave struct {
+	hook *Hook
+}
+
+func (o onSave) Name() string {
+	return "lsp-on-save"
+}
+
+func (o onSave) OpName() string {
+	return "save-current-file"
+}
+
+func (o onSave) AfterSave(_ setting.Project, path, _ string) error {
+	c, ok := o.hook.pool.Running(path)
+	if !ok {
+		return nil
+	}
+	return c.Saved(path)
+}
+
+// onClose is a hook that tells language servers when a file they
+// have open is closed.
+type onClose struct {
+	hook *Hook
+}
+
+func (o onClose) Name() string {
+	return "lsp-on-close"
+}
+
+func (o onClose) OpName() string {
+	return "close-current-tab"
+}
+
+func (o onClose) BeforeClose(e input.Editor) {
+	path := e.Filepath()
+	o.hook.forget(path)
+	c, ok := o.hook.pool.Running(path)
+	if !ok {
+		return
+	}
+	if err := c.Close(path); err != nil {
+		log.Printf("lsp: error closing %s in %s: %s", path, c.server.Command, err)
+	}
+}
+
+// OnQuit is a hook that shuts down every running language server
+// when vidar quits.
+type OnQuit struct {
+	Hook *Hook
+}
+
+func (o OnQuit) Name() string {
+	return "lsp-on-quit"
+}
+
+func (o OnQuit) OpName() string {
+	return "quit"
+}
+
+func (o OnQuit) BeforeQuit() {
+	o.Hook.pool.Shutdown()
+}
@@ -0,0 +1,87 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+)
+
+// Hover is a command which displays a language server's hover text
+// for the symbol under the cursor.
+type Hover struct {
+	status.General
+
+	pool *Pool
+
+	editor input.Editor
+	ctrl   CursorController
+}
+
+func NewHover(pool *Pool, theme gxui.Theme) *Hover {
+	h := &Hover{pool: pool}
+	h.Theme = theme
+	return h
+}
+
+func (h *Hover) Name() string {
+	return "lsp-hover"
+}
+
+func (h *Hover) Menu() string {
+	return "Language"
+}
+
+func (h *Hover) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModShift,
+		Key:      gxui.KeyH,
+	}}
+}
+
+func (h *Hover) Reset() {
+	h.editor = nil
+	h.ctrl = nil
+}
+
+func (h *Hover) Store(target interface{}) bind.Status {
+	switch src := target.(type) {
+	case input.Editor:
+		h.editor = src
+	case CursorController:
+		h.ctrl = src
+	}
+	if h.editor != nil && h.ctrl != nil {
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (h *Hover) Exec() error {
+	path := h.editor.Filepath()
+	c, err := h.pool.Client(path, environ(path))
+	if err != nil {
+		h.Err = err.Error()
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	text, err := c.Hover(ctx, path, h.editor.Runes(), h.ctrl.LastCaret())
+	if err != nil {
+		h.Err = fmt.Sprintf("%s: %s", c.server.Command, err)
+		return err
+	}
+	if text == "" {
+		h.Info = "no information found"
+		return nil
+	}
+	h.Info = text
+	return nil
+}
@@ -0,0 +1,236 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+const (
+	jsonrpcVersion = "2.0"
+
+	codeMethodNotFound = -32601
+)
+
+// ErrClosed is returned from calls on a closed connection.
+var ErrClosed = errors.New("lsp: connection closed")
+
+// RPCError is an error returned by a language server.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("lsp: server error %d: %s", e.Code, e.Message)
+}
+
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// handler is called for messages that the server sends on its own,
+// i.e. notifications and requests.  The returned value will be sent
+// back to the server as the result if the message was a request.
+type handler func(method string, params json.RawMessage) (result interface{}, err error)
+
+// conn is a JSON-RPC 2.0 connection using the base protocol from the
+// language server protocol spec, where each message is prefixed with
+// a Content-Length header.
+type conn struct {
+	r       *bufio.Reader
+	w       io.Writer
+	wmu     sync.Mutex
+	handler handler
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan *message
+	err     error
+}
+
+func newConn(r io.Reader, w io.Writer, h handler) *conn {
+	c := &conn{
+		r:       bufio.NewReader(r),
+		w:       w,
+		handler: h,
+		pending: make(map[int64]chan *message),
+	}
+	go c.read()
+	return c
+}
+
+// call sends a request to the server and decodes the response in to
+// result.  result may be nil if the response should be ignored.
+func (c *conn) call(ctx context.Context, method string, params, result interface{}) error {
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return c.err
+	}
+	id := c.nextID
+	c.nextID++
+	resp := make(chan *message, 1)
+	c.pending[id] = resp
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.pending, id)
+	}()
+
+	if err := c.send(strconv.AppendInt(nil, id, 10), method, params); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		c.notify("$/cancelRequest", map[string]int64{"id": id})
+		return ctx.Err()
+	case m, ok := <-resp:
+		if !ok {
+			return ErrClosed
+		}
+		if m.Error != nil {
+			return m.Error
+		}
+		if result == nil || len(m.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(m.Result, result)
+	}
+}
+
+// notify sends a notification to the server.
+func (c *conn) notify(method string, params interface{}) error {
+	return c.send(nil, method, params)
+}
+
+func (c *conn) send(id json.RawMessage, method string, params interface{}) error {
+	m := message{JSONRPC: jsonrpcVersion, ID: id, Method: method}
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		m.Params = b
+	}
+	return c.write(m)
+}
+
+func (c *conn) write(m message) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(b)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(b)
+	return err
+}
+
+func (c *conn) read() {
+	var err error
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.err = ErrClosed
+		if err != nil && err != io.EOF {
+			c.err = err
+		}
+		for id, p := range c.pending {
+			close(p)
+			delete(c.pending, id)
+		}
+	}()
+	for {
+		var b []byte
+		b, err = readMessage(c.r)
+		if err != nil {
+			return
+		}
+		var m message
+		if err := json.Unmarshal(b, &m); err != nil {
+			log.Printf("lsp: error parsing message from server: %s", err)
+			continue
+		}
+		if m.Method != "" {
+			go c.handle(m)
+			continue
+		}
+		id, err := strconv.ParseInt(string(m.ID), 10, 64)
+		if err != nil {
+			log.Printf("lsp: response from server has unexpected id %s", m.ID)
+			continue
+		}
+		c.mu.Lock()
+		p, ok := c.pending[id]
+		c.mu.Unlock()
+		if ok {
+			p <- &m
+		}
+	}
+}
+
+func (c *conn) handle(m message) {
+	result, err := c.handler(m.Method, m.Params)
+	if len(m.ID) == 0 {
+		return
+	}
+	resp := message{JSONRPC: jsonrpcVersion, ID: m.ID}
+	if err != nil {
+		resp.Error = &RPCError{Code: codeMethodNotFound, Message: err.Error()}
+	}
+	if err == nil {
+		b, err := json.Marshal(result)
+		if err != nil {
+			log.Printf("lsp: could not marshal response to %s: %s", m.Method, err)
+			return
+		}
+		resp.Result = b
+	}
+	if err := c.write(resp); err != nil {
+		log.Printf("lsp: could not respond to %s: %s", m.Method, err)
+	}
+}
+
+// readMessage reads a single message using the base protocol's
+// header format.
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	h, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	l := h.Get("Content-Length")
+	if l == "" {
+		return nil, errors.New("lsp: message is missing a Content-Length header")
+	}
+	n, err := strconv.Atoi(l)
+	if err != nil {
+		return nil, fmt.Errorf("lsp: invalid Content-Length %q: %s", l, err)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
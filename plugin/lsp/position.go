@@ -0,0 +1,112 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// PositionAt converts a rune offset in text to a Position.
+func PositionAt(text []rune, offset int) Position {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	var p Position
+	for _, r := range text[:offset] {
+		if r == '\n' {
+			p.Line++
+			p.Character = 0
+			continue
+		}
+		p.Character += utf16Len(r)
+	}
+	return p
+}
+
+// Offset converts p to a rune offset in text.  Positions past the
+// end of a line are clamped to the end of that line.
+func Offset(text []rune, p Position) int {
+	line := 0
+	i := 0
+	for ; i < len(text) && line < p.Line; i++ {
+		if text[i] == '\n' {
+			line++
+		}
+	}
+	for char := 0; i < len(text) && text[i] != '\n'; i++ {
+		char += utf16Len(text[i])
+		if char > p.Character {
+			break
+		}
+	}
+	return i
+}
+
+// Column converts p.Character, which counts UTF-16 code units, to a
+// column in runes on p's line of text.
+func Column(text []rune, p Position) int {
+	offset := Offset(text, p)
+	start := offset
+	for start > 0 && text[start-1] != '\n' {
+		start--
+	}
+	return offset - start
+}
+
+// Edits converts edits to input.Edits against text, sorted by where
+// they start.  Like the edits a language server sends, the returned
+// edits are all relative to the original text.
+func Edits(text []rune, edits []TextEdit) []input.Edit {
+	converted := make([]input.Edit, 0, len(edits))
+	for _, e := range edits {
+		start, end := Offset(text, e.Range.Start), Offset(text, e.Range.End)
+		converted = append(converted, input.Edit{
+			At:  start,
+			Old: append([]rune(nil), text[start:end]...),
+			New: []rune(e.NewText),
+		})
+	}
+	sort.Slice(converted, func(i, j int) bool {
+		return converted[i].At < converted[j].At
+	})
+	return converted
+}
+
+func utf16Len(r rune) int {
+	if utf16.IsSurrogate(r) || r < 0x10000 {
+		return 1
+	}
+	return 2
+}
+
+// URI converts a file path to a file:// URI.
+func URI(path string) string {
+	path = filepath.ToSlash(path)
+	if runtime.GOOS == "windows" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	u := url.URL{Scheme: "file", Path: path}
+	return u.String()
+}
+
+// Path converts a file:// URI to a file path.
+func Path(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	if runtime.GOOS == "windows" {
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.FromSlash(path)
+}
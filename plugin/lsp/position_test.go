@@ -0,0 +1,70 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/lsp"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var equal = matchers.Equal
+
+func TestPosition(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	text := []rune("package foo\n\nvar 𝔵 = \"bar\"\n")
+
+	o.Spec("it converts offsets to positions", func(expect expect.Expectation) {
+		expect(lsp.PositionAt(text, 0)).To(equal(lsp.Position{}))
+		expect(lsp.PositionAt(text, 12)).To(equal(lsp.Position{Line: 1}))
+		expect(lsp.PositionAt(text, 17)).To(equal(lsp.Position{Line: 2, Character: 4}))
+	})
+
+	o.Spec("it counts characters in utf-16 code units", func(expect expect.Expectation) {
+		expect(lsp.PositionAt(text, 18)).To(equal(lsp.Position{Line: 2, Character: 6}))
+	})
+
+	o.Spec("it converts positions to offsets", func(expect expect.Expectation) {
+		expect(lsp.Offset(text, lsp.Position{})).To(equal(0))
+		expect(lsp.Offset(text, lsp.Position{Line: 2, Character: 4})).To(equal(17))
+		expect(lsp.Offset(text, lsp.Position{Line: 2, Character: 6})).To(equal(18))
+	})
+
+	o.Spec("it clamps positions past the end of a line", func(expect expect.Expectation) {
+		expect(lsp.Offset(text, lsp.Position{Line: 0, Character: 100})).To(equal(11))
+	})
+
+	o.Spec("it converts characters to rune columns", func(expect expect.Expectation) {
+		expect(lsp.Column(text, lsp.Position{Line: 2, Character: 4})).To(equal(4))
+		expect(lsp.Column(text, lsp.Position{Line: 2, Character: 6})).To(equal(5))
+		expect(lsp.Column(text, lsp.Position{Line: 2, Character: 9})).To(equal(8))
+	})
+
+	o.Spec("it converts text edits to sorted input edits", func(expect expect.Expectation) {
+		edits := lsp.Edits(text, []lsp.TextEdit{
+			{Range: lsp.Range{Start: lsp.Position{Line: 2, Character: 4}, End: lsp.Position{Line: 2, Character: 6}}, NewText: "x"},
+			{Range: lsp.Range{Start: lsp.Position{Character: 8}, End: lsp.Position{Character: 11}}, NewText: "bar"},
+		})
+		expect(edits).To(equal([]input.Edit{
+			{At: 8, Old: []rune("foo"), New: []rune("bar")},
+			{At: 17, Old: []rune("𝔵"), New: []rune("x")},
+		}))
+	})
+
+	o.Spec("it round trips file paths through URIs", func(expect expect.Expectation) {
+		expect(lsp.URI("/foo/bar baz.go")).To(equal("file:///foo/bar%20baz.go"))
+		expect(lsp.Path(lsp.URI("/foo/bar baz.go"))).To(equal("/foo/bar baz.go"))
+	})
+}
@@ -0,0 +1,231 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander/input"
+)
+
+var locationColor = gxui.Color{R: 0.4, G: 0.6, B: 1, A: 1}
+
+// pendingRename is a rename that has been requested from a language
+// server but not yet applied.
+type pendingRename struct {
+	root, name string
+	edits      map[string][]TextEdit
+
+	// open holds the editors for files in edits that are open.
+	// Those files are edited in place; all others are edited on
+	// disk.
+	open map[string]input.Editor
+
+	cmdr    Commander
+	opener  Opener
+	applier Applier
+}
+
+// Preview is a panel that lists the locations that a rename will
+// change.  Locations can be clicked to open them, and the rename is
+// only applied when the apply button is clicked.
+type Preview struct {
+	mixins.LinearLayout
+
+	theme gxui.Theme
+
+	status  gxui.Label
+	list    gxui.LinearLayout
+	paneler Paneler
+
+	pending *pendingRename
+
+	// texts holds the text of each file in pending when it was
+	// previewed, so that we can refuse to apply edits to files that
+	// have changed since.
+	texts map[string][]rune
+}
+
+// NewPreview creates a *Preview.
+func NewPreview(theme gxui.Theme) *Preview {
+	p := &Preview{
+		theme:  theme,
+		status: theme.CreateLabel(),
+		list:   theme.CreateLinearLayout(),
+	}
+	p.Init(p, theme)
+	p.SetDirection(gxui.TopToBottom)
+
+	header := theme.CreateLinearLayout()
+	header.SetDirection(gxui.LeftToRight)
+	closer := theme.CreateButton()
+	closer.SetText("x")
+	closer.OnClick(func(gxui.MouseEvent) {
+		p.pending = nil
+		if p.paneler != nil {
+			p.paneler.HidePanel(p)
+		}
+	})
+	header.AddChild(closer)
+	apply := theme.CreateButton()
+	apply.SetText("Apply")
+	apply.OnClick(func(gxui.MouseEvent) {
+		p.apply()
+	})
+	header.AddChild(apply)
+	header.AddChild(p.status)
+	p.AddChild(header)
+
+	p.list.SetDirection(gxui.TopToBottom)
+	scrollable := theme.CreateScrollLayout()
+	scrollable.SetScrollAxis(false, true)
+	scrollable.SetChild(p.list)
+	p.AddChild(scrollable)
+	return p
+}
+
+// Show displays the locations that r will change using paneler,
+// replacing any rename that was previously shown.  It must be called
+// on the UI goroutine.
+func (p *Preview) Show(paneler Paneler, r pendingRename) {
+	p.paneler = paneler
+	p.pending = &r
+	p.texts = make(map[string][]rune)
+	p.list.RemoveAll()
+
+	paths := make([]string, 0, len(r.edits))
+	for path := range r.edits {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	count := 0
+	for _, path := range paths {
+		name := path
+		if rel, err := filepath.Rel(r.root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			name = rel
+		}
+		header := p.theme.CreateLabel()
+		header.SetText(name)
+		p.list.AddChild(header)
+
+		text, err := p.read(path)
+		if err != nil {
+			header.SetText(fmt.Sprintf("%s: %s", name, err))
+			continue
+		}
+		p.texts[path] = text
+		for _, e := range Edits(text, r.edits[path]) {
+			p.list.AddChild(p.location(path, text, e))
+			count++
+		}
+	}
+	p.status.SetText(fmt.Sprintf("Rename to %s: %d locations in %d files", r.name, count, len(paths)))
+	if !paneler.HasPanel(p) {
+		paneler.ShowPanel(p)
+	}
+}
+
+// read returns the current text of path in p.pending, using the open
+// editor for it if there is one.
+func (p *Preview) read(path string) ([]rune, error) {
+	if e, ok := p.pending.open[path]; ok {
+		return e.Runes(), nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return []rune(string(b)), nil
+}
+
+// location returns a label for e, which will open the location of e
+// when clicked.
+func (p *Preview) location(path string, text []rune, e input.Edit) gxui.Label {
+	pos := PositionAt(text, e.At)
+	start, end := e.At, e.At
+	for start > 0 && text[start-1] != '\n' {
+		start--
+	}
+	for end < len(text) && text[end] != '\n' {
+		end++
+	}
+	l := p.theme.CreateLabel()
+	l.SetText(fmt.Sprintf("%d: %s", pos.Line+1, strings.Replace(strings.TrimSpace(string(text[start:end])), "\t", "    ", -1)))
+	l.SetColor(locationColor)
+	l.SetMargin(math.Spacing{L: 10})
+	r := p.pending
+	l.OnClick(func(gxui.MouseEvent) {
+		r.cmdr.Execute(r.opener.For(focus.Path(path), focus.Line(pos.Line), focus.Column(e.At-start)))
+	})
+	return l
+}
+
+// apply applies the rename that p is showing.  Nothing is changed if
+// any of the files have changed since they were previewed.
+func (p *Preview) apply() {
+	r := p.pending
+	if r == nil {
+		return
+	}
+	for path := range r.edits {
+		text, err := p.read(path)
+		if err != nil {
+			p.status.SetText(fmt.Sprintf("Could not read %s: %s", path, err))
+			return
+		}
+		if string(text) != string(p.texts[path]) {
+			p.status.SetText(fmt.Sprintf("%s has changed since the rename was previewed; run it again", filepath.Base(path)))
+			return
+		}
+	}
+
+	var failed []string
+	for path, edits := range r.edits {
+		text := p.texts[path]
+		if e, ok := r.open[path]; ok {
+			r.applier.Apply(e, Edits(text, edits)...)
+			continue
+		}
+		if err := write(path, text, Edits(text, edits)); err != nil {
+			log.Printf("Error writing rename to %s: %s", path, err)
+			failed = append(failed, filepath.Base(path))
+		}
+	}
+	p.pending = nil
+	p.list.RemoveAll()
+	if len(failed) > 0 {
+		p.status.SetText(fmt.Sprintf("Renamed to %s, but could not write %s", r.name, strings.Join(failed, ", ")))
+		return
+	}
+	p.status.SetText(fmt.Sprintf("Renamed to %s in %d files", r.name, len(r.edits)))
+}
+
+// write applies edits to text and writes the result to path.  Edits
+// must be sorted and relative to text.
+func write(path string, text []rune, edits []input.Edit) error {
+	finfo, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	var result []rune
+	last := 0
+	for _, e := range edits {
+		result = append(result, text[last:e.At]...)
+		result = append(result, e.New...)
+		last = e.At + len(e.Old)
+	}
+	result = append(result, text[last:]...)
+	return ioutil.WriteFile(path, []byte(string(result)), finfo.Mode())
+}
@@ -0,0 +1,315 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// This file contains the subset of the language server protocol
+// types that vidar makes use of.  See
+// https://microsoft.github.io/language-server-protocol/specification
+// for the full protocol.
+
+// Severity is the severity of a Diagnostic.
+type Severity int
+
+const (
+	SeverityError Severity = 1 + iota
+	SeverityWarning
+	SeverityInformation
+	SeverityHint
+)
+
+// input returns the input.Severity matching s.  Servers may omit the
+// severity, in which case it is treated as an error.
+func (s Severity) input() input.Severity {
+	switch s {
+	case SeverityWarning:
+		return input.SeverityWarning
+	case SeverityInformation:
+		return input.SeverityInfo
+	case SeverityHint:
+		return input.SeverityHint
+	}
+	return input.SeverityError
+}
+
+// Position is a zero-based line and character offset.  Characters
+// are counted in UTF-16 code units.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a range between two Positions.  End is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location is a Range within a document.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// locationLink is an alternate result type for definition requests.
+type locationLink struct {
+	TargetURI            string `json:"targetUri"`
+	TargetSelectionRange Range  `json:"targetSelectionRange"`
+}
+
+// DiagnosticTag is extra information about a Diagnostic.
+type DiagnosticTag int
+
+// DiagnosticTagUnnecessary marks unused or unnecessary code, which
+// is dimmed instead of underlined.
+const DiagnosticTagUnnecessary DiagnosticTag = 1
+
+// Diagnostic is a problem reported by a language server.
+type Diagnostic struct {
+	Range    Range           `json:"range"`
+	Severity Severity        `json:"severity,omitempty"`
+	Source   string          `json:"source,omitempty"`
+	Message  string          `json:"message"`
+	Tags     []DiagnosticTag `json:"tags,omitempty"`
+}
+
+// unnecessary returns whether or not d is tagged as unnecessary.
+func (d Diagnostic) unnecessary() bool {
+	for _, t := range d.Tags {
+		if t == DiagnosticTagUnnecessary {
+			return true
+		}
+	}
+	return false
+}
+
+// CompletionItem is a single completion result.
+type CompletionItem struct {
+	Label         string          `json:"label"`
+	Kind          int             `json:"kind,omitempty"`
+	Detail        string          `json:"detail,omitempty"`
+	Documentation json.RawMessage `json:"documentation,omitempty"`
+	InsertText    string          `json:"insertText,omitempty"`
+}
+
+// Text returns the text that should be inserted for c.
+func (c CompletionItem) Text() string {
+	if c.InsertText != "" {
+		return c.InsertText
+	}
+	return c.Label
+}
+
+// Doc returns c's documentation, which may be a string or a
+// MarkupContent.
+func (c CompletionItem) Doc() string {
+	if len(c.Documentation) == 0 {
+		return ""
+	}
+	return hoverText(c.Documentation)
+}
+
+// TextEdit is a change to the text of a document.
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+type completionList struct {
+	IsIncomplete bool             `json:"isIncomplete"`
+	Items        []CompletionItem `json:"items"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type versionedTextDocumentIdentifier struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+}
+
+type textDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+type textDocumentPositionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+type renameParams struct {
+	textDocumentPositionParams
+	NewName string `json:"newName"`
+}
+
+// workspaceEdit is a set of changes to documents.  Servers may use
+// either Changes or DocumentChanges.
+type workspaceEdit struct {
+	Changes         map[string][]TextEdit `json:"changes"`
+	DocumentChanges []textDocumentEdit    `json:"documentChanges"`
+}
+
+type textDocumentEdit struct {
+	TextDocument versionedTextDocumentIdentifier `json:"textDocument"`
+	Edits        []TextEdit                      `json:"edits"`
+}
+
+// byPath returns the edits in w, keyed by file path.
+func (w workspaceEdit) byPath() map[string][]TextEdit {
+	edits := make(map[string][]TextEdit)
+	for uri, e := range w.Changes {
+		edits[Path(uri)] = append(edits[Path(uri)], e...)
+	}
+	for _, d := range w.DocumentChanges {
+		// File operations (create, rename, delete) are also
+		// allowed in DocumentChanges, but we don't advertise
+		// support for them, so anything without a document is
+		// skipped.
+		if d.TextDocument.URI == "" {
+			continue
+		}
+		path := Path(d.TextDocument.URI)
+		edits[path] = append(edits[path], d.Edits...)
+	}
+	return edits
+}
+
+type didOpenParams struct {
+	TextDocument textDocumentItem `json:"textDocument"`
+}
+
+type contentChange struct {
+	Text string `json:"text"`
+}
+
+type didChangeParams struct {
+	TextDocument   versionedTextDocumentIdentifier `json:"textDocument"`
+	ContentChanges []contentChange                 `json:"contentChanges"`
+}
+
+type documentParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+type workspaceFolder struct {
+	URI  string `json:"uri"`
+	Name string `json:"name"`
+}
+
+type initializeParams struct {
+	ProcessID        int               `json:"processId"`
+	RootURI          string            `json:"rootUri"`
+	Capabilities     interface{}       `json:"capabilities"`
+	WorkspaceFolders []workspaceFolder `json:"workspaceFolders"`
+}
+
+type configurationParams struct {
+	Items []json.RawMessage `json:"items"`
+}
+
+// clientCapabilities are the capabilities that vidar advertises to
+// language servers.
+var clientCapabilities = map[string]interface{}{
+	"textDocument": map[string]interface{}{
+		"synchronization": map[string]interface{}{
+			"didSave": true,
+		},
+		"completion": map[string]interface{}{
+			"completionItem": map[string]interface{}{
+				"snippetSupport":      false,
+				"documentationFormat": []string{"plaintext", "markdown"},
+			},
+		},
+		"hover": map[string]interface{}{
+			"contentFormat": []string{"plaintext", "markdown"},
+		},
+		"definition": map[string]interface{}{},
+		"rename":     map[string]interface{}{},
+		"publishDiagnostics": map[string]interface{}{
+			"tagSupport": map[string]interface{}{
+				"valueSet": []DiagnosticTag{DiagnosticTagUnnecessary},
+			},
+		},
+	},
+	"workspace": map[string]interface{}{
+		"workspaceFolders": true,
+		"configuration":    true,
+		"workspaceEdit": map[string]interface{}{
+			"documentChanges": true,
+		},
+	},
+}
+
+// hoverText parses the contents of a hover result, which may be a
+// MarkupContent, a MarkedString, or a list of MarkedStrings.
+func hoverText(raw json.RawMessage) string {
+	var markup struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &markup); err == nil && markup.Value != "" {
+		return markup.Value
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, l := range list {
+		if t := hoverText(l); t != "" {
+			parts = append(parts, t)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// locations parses the result of a definition request, which may be
+// a Location, a list of Locations, or a list of LocationLinks.
+func locations(raw json.RawMessage) ([]Location, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if raw[0] == '{' {
+		var l Location
+		if err := json.Unmarshal(raw, &l); err != nil {
+			return nil, err
+		}
+		return []Location{l}, nil
+	}
+	var links []locationLink
+	if err := json.Unmarshal(raw, &links); err != nil {
+		return nil, err
+	}
+	if len(links) > 0 && links[0].TargetURI != "" {
+		locs := make([]Location, 0, len(links))
+		for _, l := range links {
+			locs = append(locs, Location{URI: l.TargetURI, Range: l.TargetSelectionRange})
+		}
+		return locs, nil
+	}
+	var locs []Location
+	if err := json.Unmarshal(raw, &locs); err != nil {
+		return nil, err
+	}
+	return locs, nil
+}
@@ -0,0 +1,170 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Applier is a type that can apply edits to an editor.
+type Applier interface {
+	Apply(input.Editor, ...input.Edit)
+}
+
+// A Paneler is a type that can display panels below the editor.
+type Paneler interface {
+	ShowPanel(gxui.Control)
+	HidePanel(gxui.Control)
+	HasPanel(gxui.Control) bool
+}
+
+// FileChecker is a type that knows which files are open in the
+// current project.
+type FileChecker interface {
+	Project() setting.Project
+	Has(hiddenPrefix, path string) bool
+}
+
+// Rename is a command which uses a language server to rename the
+// symbol under the cursor everywhere that it is used.  The affected
+// locations are listed in a *Preview, and nothing is changed until
+// the rename is applied from there.
+type Rename struct {
+	status.General
+
+	hook *Hook
+
+	nameInput gxui.TextBox
+	input     gxui.Focusable
+
+	cmdr    Commander
+	opener  Opener
+	editor  input.Editor
+	ctrl    CursorController
+	applier Applier
+	paneler Paneler
+	files   FileChecker
+}
+
+func NewRename(hook *Hook, theme gxui.Theme) *Rename {
+	r := &Rename{hook: hook, nameInput: theme.CreateTextBox()}
+	r.Theme = theme
+	return r
+}
+
+func (r *Rename) Name() string {
+	return "rename-symbol"
+}
+
+func (r *Rename) Menu() string {
+	return "Language"
+}
+
+func (r *Rename) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Key: gxui.KeyF2,
+	}}
+}
+
+func (r *Rename) Start(gxui.Control) gxui.Control {
+	r.nameInput.SetText("")
+	r.input = r.nameInput
+	return nil
+}
+
+func (r *Rename) Next() gxui.Focusable {
+	input := r.input
+	r.input = nil
+	return input
+}
+
+func (r *Rename) Reset() {
+	r.cmdr = nil
+	r.opener = nil
+	r.editor = nil
+	r.ctrl = nil
+	r.applier = nil
+	r.paneler = nil
+	r.files = nil
+}
+
+func (r *Rename) Store(target interface{}) bind.Status {
+	switch src := target.(type) {
+	case Commander:
+		r.cmdr = src
+	case input.Editor:
+		r.editor = src
+	case CursorController:
+		r.ctrl = src
+	case Opener:
+		r.opener = src
+	case Applier:
+		r.applier = src
+	case Paneler:
+		r.paneler = src
+	case FileChecker:
+		r.files = src
+	}
+	if r.cmdr != nil && r.opener != nil && r.editor != nil && r.ctrl != nil &&
+		r.applier != nil && r.paneler != nil && r.files != nil {
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (r *Rename) Exec() error {
+	newName := strings.TrimSpace(r.nameInput.Text())
+	if newName == "" {
+		r.Warn = "No name provided"
+		return nil
+	}
+	path := r.editor.Filepath()
+	c, err := r.hook.pool.Client(path, environ(path))
+	if err != nil {
+		r.Err = err.Error()
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	edits, err := c.Rename(ctx, path, r.editor.Runes(), r.ctrl.LastCaret(), newName)
+	if err != nil {
+		r.Err = fmt.Sprintf("%s: %s", c.server.Command, err)
+		return err
+	}
+	if len(edits) == 0 {
+		r.Warn = "nothing to rename"
+		return errors.New("lsp: no rename edits returned")
+	}
+
+	proj := r.files.Project()
+	pending := pendingRename{
+		root:    proj.Path,
+		name:    newName,
+		edits:   edits,
+		open:    make(map[string]input.Editor),
+		cmdr:    r.cmdr,
+		opener:  r.opener,
+		applier: r.applier,
+	}
+	for p := range edits {
+		if !r.files.Has(proj.Path, p) {
+			continue
+		}
+		if e := r.hook.editor(p); e != nil {
+			pending.open[p] = e
+		}
+	}
+	r.hook.renamePreview().Show(r.paneler, pending)
+	return nil
+}
@@ -0,0 +1,305 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	startTimeout = 30 * time.Second
+
+	// minRetry and maxRetry bound how long a server that failed to
+	// start is left alone before it is started again.
+	minRetry = 5 * time.Second
+	maxRetry = 5 * time.Minute
+)
+
+// ErrStarting is returned by (*Pool).Client while the server for a
+// file is still starting.
+var ErrStarting = errors.New("lsp: language server is still starting")
+
+// Server is the configuration for a language server.
+type Server struct {
+	// Command and Args are used to start the server.  It must
+	// speak the language server protocol over stdio.
+	Command string
+	Args    []string
+
+	// LanguageID is the language identifier sent to the server
+	// for each document.
+	LanguageID string
+
+	// Extensions is the list of file extensions (including the
+	// leading dot) that the server handles.
+	Extensions []string
+
+	// RootMarkers is a list of file names that mark the root
+	// directory of a project for this server.  The closest
+	// parent directory of a file containing one of these will
+	// be used as the server's root.  If none are found, the
+	// file's directory will be used.
+	RootMarkers []string
+}
+
+// DefaultServers is the list of servers that will be used if no
+// servers are configured.
+var DefaultServers = []Server{
+	{
+		Command:     "gopls",
+		LanguageID:  "go",
+		Extensions:  []string{".go"},
+		RootMarkers: []string{"go.mod", ".git"},
+	},
+}
+
+// Handles returns whether or not s handles the file at path.
+func (s Server) Handles(path string) bool {
+	ext := filepath.Ext(path)
+	for _, e := range s.Extensions {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// Root returns the root directory that s should be started in for
+// the file at path.
+func (s Server) Root(path string) string {
+	dir := filepath.Dir(path)
+	for d := dir; ; d = filepath.Dir(d) {
+		for _, m := range s.RootMarkers {
+			if _, err := os.Stat(filepath.Join(d, m)); err == nil {
+				return d
+			}
+		}
+		if parent := filepath.Dir(d); parent == d {
+			return dir
+		}
+	}
+}
+
+// Pool keeps track of running language servers, starting them as
+// they are needed.
+type Pool struct {
+	servers []Server
+
+	mu       sync.Mutex
+	clients  map[string]*Client
+	starting map[string]*pending
+	failed   map[string]failure
+	onStart  []func(*Client)
+}
+
+// pending is a server that is being started.  done is closed once
+// the start has finished, after which c or err is set.
+type pending struct {
+	done chan struct{}
+	c    *Client
+	err  error
+}
+
+// failure is a server that failed to start.  It won't be started
+// again until retry.
+type failure struct {
+	err   error
+	wait  time.Duration
+	retry time.Time
+}
+
+// NewPool returns a Pool that will start servers from the passed in
+// list.
+func NewPool(servers ...Server) *Pool {
+	return &Pool{
+		servers:  servers,
+		clients:  make(map[string]*Client),
+		starting: make(map[string]*pending),
+		failed:   make(map[string]failure),
+	}
+}
+
+// Server returns the Server that handles path.
+func (p *Pool) Server(path string) (Server, bool) {
+	for _, s := range p.servers {
+		if s.Handles(path) {
+			return s, true
+		}
+	}
+	return Server{}, false
+}
+
+// OnStart registers a callback that will be called for each new
+// Client that p starts.
+func (p *Pool) OnStart(cb func(*Client)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onStart = append(p.onStart, cb)
+}
+
+// Client returns the Client for the file at path.  If the server is
+// not already running, it is started in the background and
+// ErrStarting is returned, so Client is safe to call on the UI
+// goroutine.  Servers that fail to start are not tried again until a
+// backoff has passed; until then, the error they failed with is
+// returned.
+func (p *Pool) Client(path string, environ []string) (*Client, error) {
+	c, pend, err := p.lookup(path, environ)
+	if pend == nil {
+		return c, err
+	}
+	select {
+	case <-pend.done:
+		return pend.c, pend.err
+	default:
+		return nil, ErrStarting
+	}
+}
+
+// Wait is like Client, but waits for the server to start if it is
+// not already running.  It must not be called on the UI goroutine.
+func (p *Pool) Wait(path string, environ []string) (*Client, error) {
+	c, pend, err := p.lookup(path, environ)
+	if pend == nil {
+		return c, err
+	}
+	<-pend.done
+	return pend.c, pend.err
+}
+
+// Running returns the Client for the file at path, if its server is
+// running.  It never starts a server.
+func (p *Pool) Running(path string) (*Client, bool) {
+	s, ok := p.Server(path)
+	if !ok {
+		return nil, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c, ok := p.clients[clientKey(s, s.Root(path))]
+	return c, ok
+}
+
+// lookup returns the running Client for path, or the pending start
+// of its server.
+func (p *Pool) lookup(path string, environ []string) (*Client, *pending, error) {
+	s, ok := p.Server(path)
+	if !ok {
+		return nil, nil, fmt.Errorf("lsp: no language server configured for %s", path)
+	}
+	root := s.Root(path)
+	key := clientKey(s, root)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.clients[key]; ok {
+		if sameEnviron(c.environ, environ) {
+			return c, nil, nil
+		}
+		// The environment changes when the project's build
+		// context does, which the server only reads on startup.
+		go c.Shutdown()
+		delete(p.clients, key)
+	}
+	if pend, ok := p.starting[key]; ok {
+		return nil, pend, nil
+	}
+	f, failed := p.failed[key]
+	if failed && time.Now().Before(f.retry) {
+		return nil, nil, f.err
+	}
+	pend := &pending{done: make(chan struct{})}
+	p.starting[key] = pend
+	go p.start(key, pend, s, root, environ)
+	return nil, pend, nil
+}
+
+// start starts s for pend, outside of p.mu so that callers don't wait
+// on it.
+func (p *Pool) start(key string, pend *pending, s Server, root string, environ []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	c, err := Start(ctx, s, root, environ)
+
+	p.mu.Lock()
+	delete(p.starting, key)
+	if err != nil {
+		f := p.failed[key]
+		f.err = err
+		f.wait *= 2
+		if f.wait < minRetry {
+			f.wait = minRetry
+		}
+		if f.wait > maxRetry {
+			f.wait = maxRetry
+		}
+		f.retry = time.Now().Add(f.wait)
+		p.failed[key] = f
+		p.mu.Unlock()
+		log.Printf("lsp: %s (retrying in %s)", err, f.wait)
+		pend.err = err
+		close(pend.done)
+		return
+	}
+	delete(p.failed, key)
+	p.clients[key] = c
+	onStart := make([]func(*Client), len(p.onStart))
+	copy(onStart, p.onStart)
+	p.mu.Unlock()
+	for _, cb := range onStart {
+		cb(c)
+	}
+	pend.c = c
+	close(pend.done)
+}
+
+func clientKey(s Server, root string) string {
+	return strings.Join(append([]string{root, s.Command}, s.Args...), "\x00")
+}
+
+// sameEnviron returns whether or not a and b contain the same
+// variables, in any order.
+func sameEnviron(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Shutdown shuts down all running servers.  Servers that are still
+// starting are left to exit with vidar.
+func (p *Pool) Shutdown() {
+	p.mu.Lock()
+	clients := p.clients
+	p.clients = make(map[string]*Client)
+	p.mu.Unlock()
+	var wg sync.WaitGroup
+	for _, c := range clients {
+		wg.Add(1)
+		go func(c *Client) {
+			defer wg.Done()
+			c.Shutdown()
+		}(c)
+	}
+	wg.Wait()
+}
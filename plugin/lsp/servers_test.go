@@ -0,0 +1,34 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package lsp_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/plugin/lsp"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+func TestPool(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *lsp.Pool) {
+		missing := lsp.Server{Command: "vidar-test-missing-server", Extensions: []string{".go"}}
+		return expect.New(t), lsp.NewPool(missing)
+	})
+
+	o.Spec("it remembers servers that failed to start", func(expect expect.Expectation, p *lsp.Pool) {
+		_, startErr := p.Wait("/tmp/foo.go", nil)
+		expect(startErr).To(matchers.HaveOccurred())
+
+		_, err := p.Client("/tmp/foo.go", nil)
+		expect(err).To(equal(startErr))
+		_, running := p.Running("/tmp/foo.go")
+		expect(running).To(equal(false))
+	})
+}
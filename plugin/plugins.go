@@ -26,6 +26,7 @@
 package plugin
 
 import (
+	"fmt"
 	"log"
 	"plugin"
 
@@ -38,34 +39,57 @@ import (
 const lookupName = "Bindables"
 
 // Bindables returns all bindables that are found via plugins
-// in the plugin directory.
+// in the plugins directory.
 //
 // For each plugin, Bindables will look up a Bindables function
 // and expect it to accept a Commander, a gxui.Driver, and a
-// gxui.Theme as arguments.
+// gxui.Theme as arguments.  Plugins that fail to load are skipped,
+// and their errors are reported by a command named ErrorsName.
 func Bindables(cmdr command.Commander, driver gxui.Driver, theme gxui.Theme) []bind.Bindable {
-	var bindables []bind.Bindable
-	for _, path := range setting.Plugins() {
-		plugin, err := plugin.Open(path)
-		if err != nil {
-			log.Printf("Error opening plugin at %s: %s", path, err)
-			continue
-		}
-		c, err := plugin.Lookup(lookupName)
+	paths, err := setting.Plugins()
+	if err != nil {
+		log.Printf("Error listing plugins: %s", err)
+		return []bind.Bindable{NewErrors(theme, []error{err})}
+	}
+	var (
+		bindables []bind.Bindable
+		errs      []error
+	)
+	for _, path := range paths {
+		newBindables, err := load(path, cmdr, driver, theme)
 		if err != nil {
-			log.Printf("Error looking up constructor %s in plugin %s: %s", lookupName, path, err)
-			continue
-		}
-
-		var newBindables []bind.Bindable
-		switch construct := c.(type) {
-		case func(command.Commander, gxui.Driver, gxui.Theme) []bind.Bindable:
-			newBindables = construct(cmdr, driver, theme)
-		default:
-			log.Printf("Error: don't know how to call constructor of type %T from plugin %s", c, path)
+			log.Printf("Error loading plugin %s: %s", path, err)
+			errs = append(errs, LoadError{Path: path, Err: err})
 			continue
 		}
 		bindables = append(bindables, newBindables...)
 	}
+	if len(errs) > 0 {
+		bindables = append(bindables, NewErrors(theme, errs))
+	}
 	return bindables
 }
+
+// load opens the plugin at path and calls its Bindables function.
+// Panics from the plugin are returned as errors, so that a broken
+// plugin can't stop the editor from starting.
+func load(path string, cmdr command.Commander, driver gxui.Driver, theme gxui.Theme) (b []bind.Bindable, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while loading: %v", r)
+		}
+	}()
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := p.Lookup(lookupName)
+	if err != nil {
+		return nil, err
+	}
+	construct, ok := c.(func(command.Commander, gxui.Driver, gxui.Theme) []bind.Bindable)
+	if !ok {
+		return nil, fmt.Errorf("don't know how to call %s of type %T", lookupName, c)
+	}
+	return construct(cmdr, driver, theme), nil
+}
@@ -9,12 +9,36 @@ package plugin
 import (
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/gxui/themes/basic"
-	"github.com/nelsam/vidar/commander"
 	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/plugin/comments"
+	"github.com/nelsam/vidar/plugin/gobuild"
+	"github.com/nelsam/vidar/plugin/godef"
+	"github.com/nelsam/vidar/plugin/goimports"
+	"github.com/nelsam/vidar/plugin/gotest"
+	"github.com/nelsam/vidar/plugin/highlight"
+	"github.com/nelsam/vidar/plugin/license"
+	"github.com/nelsam/vidar/plugin/lsp"
+	"github.com/nelsam/vidar/setting"
 )
 
-func Bindables(cmdr *commander.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
+func Bindables(cmdr command.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	definitions := godef.NewIndex()
+	definitions.SetProject(setting.DefaultProject)
+	servers := lsp.New(driver, theme, lsp.Servers()...)
 	return []bind.Bindable{
-		GolangHook{Theme: theme, Driver: driver},
+		GolangHook{
+			Theme:       theme,
+			Driver:      driver,
+			Tests:       gotest.New(cmdr, driver, theme),
+			Build:       gobuild.New(cmdr, driver, theme),
+			Definitions: definitions,
+			Imports:     goimports.NewOnSave(cmdr, driver),
+		},
+		highlight.NewHook(highlight.Languages()...),
+		comments.Hook{},
+		license.NewRelicense(driver, theme),
+		servers,
+		lsp.OnQuit{Hook: servers},
 	}
 }
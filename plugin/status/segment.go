@@ -0,0 +1,25 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package status
+
+// A Segment is a bindable which displays a short piece of text in the
+// status bar at the bottom of the window.  Plugins add segments to
+// the status bar by including them in their bindables; segments are
+// displayed in the order that they're bound.
+type Segment interface {
+	// Name returns the name of the segment.  It must be unique
+	// among all bindables.
+	Name() string
+
+	// Text returns the text that the segment should display.
+	// editor is the focused editor, or nil if no file is open; it
+	// should be type asserted to whatever methods the segment
+	// needs.  The segment is hidden when Text returns an empty
+	// string.
+	//
+	// Text is called on the UI goroutine every time the status bar
+	// is refreshed, so it must be fast.
+	Text(editor interface{}) string
+}
@@ -0,0 +1,139 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package status
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	taskMu sync.Mutex
+	tasks  []*Task
+)
+
+// A Task is a background task that is listed in the status bar while
+// it runs.
+type Task struct {
+	name   string
+	cancel func()
+
+	mu       sync.Mutex
+	progress int
+	total    int
+	canceled bool
+	once     sync.Once
+}
+
+// Start records that a background task named name has started, so
+// that it's listed in the status bar.  If cancel is non-nil, the task
+// may be canceled from the cancel-task command, which calls cancel.
+// cancel should stop the task without waiting for it; Done must still
+// be called once the task has stopped.
+func Start(name string, cancel func()) *Task {
+	t := &Task{name: name, cancel: cancel}
+	taskMu.Lock()
+	tasks = append(tasks, t)
+	taskMu.Unlock()
+	return t
+}
+
+// StartTask records that a background task named name has started,
+// so that it's listed in the status bar.  The returned function must
+// be called when the task is done.  Tasks with the same name may run
+// at the same time; each is listed until its own done function is
+// called.
+func StartTask(name string) (done func()) {
+	return Start(name, nil).Done
+}
+
+// Name returns the name that t was started with.
+func (t *Task) Name() string {
+	return t.name
+}
+
+// Progress records that done out of total units of t's work have
+// been finished.  A total of 0 means the amount of work is unknown.
+func (t *Task) Progress(done, total int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress, t.total = done, total
+}
+
+// String returns t's name along with its progress, if it has
+// reported any.
+func (t *Task) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case t.canceled:
+		return t.name + " (canceling)"
+	case t.total > 0:
+		return fmt.Sprintf("%s (%d%%)", t.name, t.progress*100/t.total)
+	}
+	return t.name
+}
+
+// Cancelable returns whether or not t can be canceled.
+func (t *Task) Cancelable() bool {
+	return t.cancel != nil
+}
+
+// Cancel asks t to stop.  It does nothing if t can't be canceled or
+// has already been asked to stop.
+func (t *Task) Cancel() {
+	if t.cancel == nil {
+		return
+	}
+	t.mu.Lock()
+	canceled := t.canceled
+	t.canceled = true
+	t.mu.Unlock()
+	if !canceled {
+		t.cancel()
+	}
+}
+
+// Done records that t has finished, removing it from the status bar.
+// Calls after the first do nothing.
+func (t *Task) Done() {
+	t.once.Do(func() {
+		taskMu.Lock()
+		defer taskMu.Unlock()
+		for i, running := range tasks {
+			if running == t {
+				tasks = append(tasks[:i], tasks[i+1:]...)
+				return
+			}
+		}
+	})
+}
+
+// Running returns the background tasks that are running, in the
+// order that they started.
+func Running() []*Task {
+	taskMu.Lock()
+	defer taskMu.Unlock()
+	return append([]*Task(nil), tasks...)
+}
+
+// Tasks returns a description of each background task that is
+// running, in the order that they started.  Descriptions are only
+// listed once, even if more than one task matches the same
+// description.
+func Tasks() []string {
+	running := Running()
+	seen := make(map[string]bool, len(running))
+	var names []string
+	for _, t := range running {
+		name := t.String()
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,52 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package status
+
+import (
+	"sync"
+	"time"
+)
+
+// warningTime is how long a warning from Warn stays in the status
+// bar.
+const warningTime = 5 * time.Second
+
+var (
+	warnMu   sync.Mutex
+	warnings []warning
+)
+
+type warning struct {
+	msg     string
+	expires time.Time
+}
+
+// Warn displays msg in the status bar for a few seconds.  It's meant
+// for background work that finishes after the command that started
+// it, which has no command status left to report to.
+func Warn(msg string) {
+	warnMu.Lock()
+	defer warnMu.Unlock()
+	warnings = append(warnings, warning{msg: msg, expires: time.Now().Add(warningTime)})
+}
+
+// Warnings returns the messages passed to Warn that are still being
+// displayed, oldest first.
+func Warnings() []string {
+	warnMu.Lock()
+	defer warnMu.Unlock()
+	now := time.Now()
+	var msgs []string
+	current := warnings[:0]
+	for _, w := range warnings {
+		if now.After(w.expires) {
+			continue
+		}
+		current = append(current, w)
+		msgs = append(msgs, w.msg)
+	}
+	warnings = current
+	return msgs
+}
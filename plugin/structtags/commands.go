@@ -0,0 +1,142 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package structtags
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+type Applier interface {
+	Apply(input.Editor, ...input.Edit)
+}
+
+// A Projecter is a type that knows which project is currently open.
+type Projecter interface {
+	Project() setting.Project
+}
+
+// Editor is the type of editor that tags can be edited in.
+type Editor interface {
+	input.Editor
+	Carets() []int
+}
+
+type mode int
+
+const (
+	generate mode = iota
+	toggleOmitEmpty
+)
+
+// Command is a bind.MultiOp that edits the tags of the struct under
+// the caret, using the project's structtags settings.
+type Command struct {
+	status.General
+
+	mode mode
+
+	editor    Editor
+	projecter Projecter
+	applier   Applier
+}
+
+// New returns the struct tag commands.
+func New(theme gxui.Theme) []bind.Bindable {
+	return []bind.Bindable{
+		newCommand(theme, generate),
+		newCommand(theme, toggleOmitEmpty),
+	}
+}
+
+func newCommand(theme gxui.Theme, m mode) *Command {
+	c := &Command{mode: m}
+	c.Theme = theme
+	return c
+}
+
+func (c *Command) Name() string {
+	if c.mode == toggleOmitEmpty {
+		return "toggle-struct-tag-omitempty"
+	}
+	return "generate-struct-tags"
+}
+
+func (c *Command) Menu() string {
+	return "Golang"
+}
+
+func (c *Command) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (c *Command) Reset() {
+	c.General.Clear()
+	c.editor = nil
+	c.projecter = nil
+	c.applier = nil
+}
+
+func (c *Command) Store(target interface{}) bind.Status {
+	switch src := target.(type) {
+	case Editor:
+		c.editor = src
+	case Projecter:
+		c.projecter = src
+	case Applier:
+		c.applier = src
+	}
+	if c.editor != nil && c.projecter != nil && c.applier != nil {
+		return bind.Done
+	}
+	return bind.Waiting
+}
+
+func (c *Command) Exec() error {
+	carets := c.editor.Carets()
+	if len(carets) == 0 {
+		c.Warn = "there is no caret to find a struct at"
+		return nil
+	}
+	cfg := c.projecter.Project().StructTagsConfig()
+	text := c.editor.Runes()
+	var (
+		edits []Edit
+		err   error
+	)
+	switch c.mode {
+	case toggleOmitEmpty:
+		edits, err = ToggleOmitEmpty(text, carets[0], cfg.Keys)
+	default:
+		edits, err = Generate(text, carets[0], Options{
+			Keys:      cfg.Keys,
+			Case:      Case(cfg.Case),
+			OmitEmpty: cfg.OmitEmpty,
+		})
+	}
+	if err != nil {
+		c.Err = fmt.Sprintf("%s: %s", c.Name(), err)
+		return err
+	}
+	if len(edits) == 0 {
+		c.Info = "the struct's tags are already up to date"
+		return nil
+	}
+	inputEdits := make([]input.Edit, 0, len(edits))
+	for _, e := range edits {
+		inputEdits = append(inputEdits, input.Edit{
+			At:  e.Start,
+			Old: append([]rune(nil), text[e.Start:e.End]...),
+			New: []rune(e.Text),
+		})
+	}
+	c.applier.Apply(c.editor, inputEdits...)
+	return nil
+}
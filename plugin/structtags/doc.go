@@ -0,0 +1,9 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package structtags contains commands for adding and editing the
+// tags on go struct fields.  The source is parsed and only the tags
+// of the struct under the caret are rewritten, so the rest of the
+// file keeps its formatting.
+package structtags
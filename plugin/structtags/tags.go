@@ -0,0 +1,370 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package structtags
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Case is a naming convention for the names in struct tags.
+type Case string
+
+// The naming conventions that tags can be generated with.
+const (
+	SnakeCase Case = "snake"
+	CamelCase Case = "camel"
+)
+
+const omitEmpty = "omitempty"
+
+// ErrNoStruct is returned when there is no struct type at the offset
+// that tags should be edited at.
+var ErrNoStruct = errors.New("no struct type found at the caret")
+
+// Options control the tags that Generate adds.
+type Options struct {
+	// Keys are the tag keys to add to each field, e.g. json.
+	Keys []string
+
+	// Case is the naming convention used for the names in the
+	// tags.
+	Case Case
+
+	// OmitEmpty adds the omitempty option to tags that are added.
+	// Existing tags keep their options.
+	OmitEmpty bool
+}
+
+// Edit is a change to go source, in runes.
+type Edit struct {
+	Start, End int
+	Text       string
+}
+
+// tag is a key and value from a struct tag.
+type tag struct {
+	key, value string
+}
+
+// Generate returns the edits that add a tag for each of opts.Keys to
+// the exported fields of the struct type at offset, a rune offset in
+// src.  Fields that already have one of the keys have its name
+// updated to match opts.Case; fields ignored with "-" are left alone.
+// Embedded fields are skipped.
+func Generate(src []rune, offset int, opts Options) ([]Edit, error) {
+	return editTags(src, offset, func(field string, tags []tag) []tag {
+		name := Name(field, opts.Case)
+		for _, k := range opts.Keys {
+			i := find(tags, k)
+			if i < 0 {
+				value := name
+				if opts.OmitEmpty {
+					value += "," + omitEmpty
+				}
+				tags = append(tags, tag{key: k, value: value})
+				continue
+			}
+			parts := strings.Split(tags[i].value, ",")
+			if parts[0] == "-" && len(parts) == 1 {
+				continue
+			}
+			parts[0] = name
+			tags[i].value = strings.Join(parts, ",")
+		}
+		return tags
+	})
+}
+
+// ToggleOmitEmpty returns the edits that toggle the omitempty option
+// on the tags for keys in the struct type at offset.  If every one of
+// those tags already has it, it's removed; otherwise, it's added to
+// the tags that are missing it.
+func ToggleOmitEmpty(src []rune, offset int, keys []string) ([]Edit, error) {
+	all := true
+	_, err := editTags(src, offset, func(_ string, tags []tag) []tag {
+		for _, k := range keys {
+			if i := find(tags, k); i >= 0 && !ignored(tags[i].value) && !hasOption(tags[i].value, omitEmpty) {
+				all = false
+			}
+		}
+		return tags
+	})
+	if err != nil {
+		return nil, err
+	}
+	return editTags(src, offset, func(_ string, tags []tag) []tag {
+		for _, k := range keys {
+			i := find(tags, k)
+			if i < 0 || ignored(tags[i].value) {
+				continue
+			}
+			switch {
+			case all:
+				tags[i].value = removeOption(tags[i].value, omitEmpty)
+			case !hasOption(tags[i].value, omitEmpty):
+				tags[i].value += "," + omitEmpty
+			}
+		}
+		return tags
+	})
+}
+
+// editTags calls update with the name and tags of each exported,
+// named field of the struct type at offset, and returns the edits
+// that replace their tags with the result.  If the source outside of
+// the struct is formatted the way gofmt would format it, the struct
+// is formatted too, so that the new tags are aligned.
+func editTags(src []rune, offset int, update func(field string, tags []tag) []tag) ([]Edit, error) {
+	if offset < 0 || offset > len(src) {
+		return nil, ErrNoStruct
+	}
+	text := string(src)
+	byteOffset := len(string(src[:offset]))
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", text, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	file := fset.File(f.Pos())
+	st := structAt(f, file, byteOffset)
+	if st == nil {
+		return nil, ErrNoStruct
+	}
+
+	type byteEdit struct {
+		start, end int
+		text       string
+	}
+	var edits []byteEdit
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 || !field.Names[0].IsExported() {
+			continue
+		}
+		var (
+			old   string
+			start = file.Offset(field.Type.End())
+			end   = start
+		)
+		if field.Tag != nil {
+			if old, err = strconv.Unquote(field.Tag.Value); err != nil {
+				return nil, fmt.Errorf("field %s: %s", field.Names[0].Name, err)
+			}
+			start, end = file.Offset(field.Tag.Pos()), file.Offset(field.Tag.End())
+		}
+		tags, err := parse(old)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %s", field.Names[0].Name, err)
+		}
+		lit := literal(update(field.Names[0].Name, tags))
+		if field.Tag == nil {
+			if lit == "" {
+				continue
+			}
+			lit = " " + lit
+		}
+		if lit == text[start:end] {
+			continue
+		}
+		edits = append(edits, byteEdit{start: start, end: end, text: lit})
+	}
+	if len(edits) == 0 {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, e := range edits {
+		b.WriteString(text[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.WriteString(text[last:])
+	start, end := file.Offset(st.Fields.Opening), file.Offset(st.Fields.Closing)+1
+	prefix, suffix := text[:start], text[end:]
+	if formatted, err := format.Source([]byte(b.String())); err == nil {
+		out := string(formatted)
+		if len(out) >= len(prefix)+len(suffix) && strings.HasPrefix(out, prefix) && strings.HasSuffix(out, suffix) {
+			return []Edit{{
+				Start: utf8.RuneCountInString(prefix),
+				End:   utf8.RuneCountInString(text[:end]),
+				Text:  out[len(prefix) : len(out)-len(suffix)],
+			}}, nil
+		}
+	}
+
+	runeEdits := make([]Edit, 0, len(edits))
+	for _, e := range edits {
+		runeEdits = append(runeEdits, Edit{
+			Start: utf8.RuneCountInString(text[:e.start]),
+			End:   utf8.RuneCountInString(text[:e.end]),
+			Text:  e.text,
+		})
+	}
+	return runeEdits, nil
+}
+
+// structAt returns the innermost struct type that contains the byte
+// offset, counting the name of a type spec as part of its struct.
+func structAt(f *ast.File, file *token.File, offset int) *ast.StructType {
+	contains := func(n ast.Node) bool {
+		return file.Offset(n.Pos()) <= offset && offset <= file.Offset(n.End())
+	}
+	var found *ast.StructType
+	ast.Inspect(f, func(n ast.Node) bool {
+		if n == nil || !contains(n) {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.TypeSpec:
+			if st, ok := n.Type.(*ast.StructType); ok {
+				found = st
+			}
+		case *ast.StructType:
+			found = n
+		}
+		return true
+	})
+	return found
+}
+
+// parse splits a struct tag into its keys and values, following the
+// conventions of reflect.StructTag.
+func parse(s string) ([]tag, error) {
+	var tags []tag
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return tags, nil
+		}
+		i := strings.Index(s, ":")
+		if i <= 0 || strings.ContainsAny(s[:i], " \t\"") || i+1 >= len(s) || s[i+1] != '"' {
+			return nil, fmt.Errorf("malformed tag %q", s)
+		}
+		key := s[:i]
+		s = s[i+1:]
+		end := 1
+		for end < len(s) && s[end] != '"' {
+			if s[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(s) {
+			return nil, fmt.Errorf("unterminated value for tag key %s", key)
+		}
+		value, err := strconv.Unquote(s[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("bad value for tag key %s: %s", key, err)
+		}
+		tags = append(tags, tag{key: key, value: value})
+		s = s[end+1:]
+	}
+}
+
+// literal returns the go string literal for tags, or "" if there are
+// none.
+func literal(tags []tag) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(tags))
+	for _, t := range tags {
+		parts = append(parts, t.key+":"+strconv.Quote(t.value))
+	}
+	s := strings.Join(parts, " ")
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+func find(tags []tag, key string) int {
+	for i, t := range tags {
+		if t.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// ignored returns whether or not value tells encoders to skip its
+// field.
+func ignored(value string) bool {
+	return value == "-"
+}
+
+func hasOption(value, option string) bool {
+	for _, o := range strings.Split(value, ",")[1:] {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func removeOption(value, option string) string {
+	parts := strings.Split(value, ",")
+	kept := parts[:1]
+	for _, o := range parts[1:] {
+		if o != option {
+			kept = append(kept, o)
+		}
+	}
+	return strings.Join(kept, ",")
+}
+
+// Name returns the name for a field in tags using the naming
+// convention c.  Acronyms are kept together, so UserID is user_id in
+// SnakeCase and userID in CamelCase.
+func Name(field string, c Case) string {
+	words := split(field)
+	if c == CamelCase {
+		for i, w := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(w)
+				continue
+			}
+			r, size := utf8.DecodeRuneInString(w)
+			words[i] = string(unicode.ToUpper(r)) + w[size:]
+		}
+		return strings.Join(words, "")
+	}
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return strings.Join(words, "_")
+}
+
+// split splits a go identifier into words at underscores and changes
+// in case.
+func split(name string) []string {
+	var words []string
+	for _, part := range strings.Split(name, "_") {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			prev, r := runes[i-1], runes[i]
+			lowerToUpper := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev))
+			acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || acronymEnd {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < len(runes) {
+			words = append(words, string(runes[start:]))
+		}
+	}
+	return words
+}
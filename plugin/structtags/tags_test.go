@@ -0,0 +1,137 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package structtags_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/plugin/structtags"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal          = matchers.Equal
+	haveOccurred   = matchers.HaveOccurred
+	not            = matchers.Not
+	defaultOptions = structtags.Options{Keys: []string{"json"}, Case: structtags.SnakeCase}
+)
+
+// apply applies edits to src.
+func apply(src []rune, edits []structtags.Edit) string {
+	for i := len(edits) - 1; i >= 0; i-- {
+		e := edits[i]
+		src = append(src[:e.Start:e.Start], append([]rune(e.Text), src[e.End:]...)...)
+	}
+	return string(src)
+}
+
+// generate runs structtags.Generate at the '|' in src and returns the
+// result of applying its edits.
+func generate(src string, opts structtags.Options) (string, error) {
+	pos := strings.Index(src, "|")
+	runes := []rune(src[:pos] + src[pos+1:])
+	edits, err := structtags.Generate(runes, len([]rune(src[:pos])), opts)
+	return apply(runes, edits), err
+}
+
+func toggle(src string, keys ...string) (string, error) {
+	pos := strings.Index(src, "|")
+	runes := []rune(src[:pos] + src[pos+1:])
+	edits, err := structtags.ToggleOmitEmpty(runes, len([]rune(src[:pos])), keys)
+	return apply(runes, edits), err
+}
+
+func TestGenerate(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it adds aligned tags to exported fields", func(expect expect.Expectation) {
+		out, err := generate("package foo\n\ntype User struct {\n\tID |int\n\tFullName string // their name\n\tsecret string\n\tEmbedded\n}\n", defaultOptions)
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\ntype User struct {\n\tID       int    `json:\"id\"`\n\tFullName string `json:\"full_name\"` // their name\n\tsecret   string\n\tEmbedded\n}\n"))
+	})
+
+	o.Spec("it updates existing tags and keeps their options", func(expect expect.Expectation) {
+		out, err := generate("package foo\n\ntype |User struct {\n\tUserID int `json:\"uid,string\" db:\"user_id\"`\n\tSkipped int `json:\"-\"`\n}\n", structtags.Options{
+			Keys: []string{"json", "yaml"},
+			Case: structtags.CamelCase,
+		})
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\ntype User struct {\n\tUserID  int `json:\"userID,string\" db:\"user_id\" yaml:\"userID\"`\n\tSkipped int `json:\"-\" yaml:\"skipped\"`\n}\n"))
+	})
+
+	o.Spec("it adds omitempty to new tags", func(expect expect.Expectation) {
+		opts := defaultOptions
+		opts.OmitEmpty = true
+		out, err := generate("package foo\n\ntype T struct {\n\tA int|\n}\n", opts)
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\ntype T struct {\n\tA int `json:\"a,omitempty\"`\n}\n"))
+	})
+
+	o.Spec("it only edits the innermost struct", func(expect expect.Expectation) {
+		out, err := generate("package foo\n\ntype T struct {\n\tA struct {\n\t\tB| int\n\t}\n}\n", defaultOptions)
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\ntype T struct {\n\tA struct {\n\t\tB int `json:\"b\"`\n\t}\n}\n"))
+	})
+
+	o.Spec("it leaves unformatted files unformatted", func(expect expect.Expectation) {
+		out, err := generate("package foo\ntype T struct {\n\tLong  int\n\tB string|\n}\nvar   x = 1\n", defaultOptions)
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\ntype T struct {\n\tLong  int `json:\"long\"`\n\tB string `json:\"b\"`\n}\nvar   x = 1\n"))
+	})
+
+	o.Spec("it fails outside of structs", func(expect expect.Expectation) {
+		_, err := generate("package foo\n\nvar x| = 1\n", defaultOptions)
+		expect(err).To(equal(structtags.ErrNoStruct))
+	})
+}
+
+func TestToggleOmitEmpty(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it adds omitempty when some tags are missing it", func(expect expect.Expectation) {
+		out, err := toggle("package foo\n\ntype T struct {|\n\tA int `json:\"a,omitempty\"`\n\tB int `json:\"b\"`\n\tC int `json:\"-\"`\n}\n", "json")
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\ntype T struct {\n\tA int `json:\"a,omitempty\"`\n\tB int `json:\"b,omitempty\"`\n\tC int `json:\"-\"`\n}\n"))
+	})
+
+	o.Spec("it removes omitempty when every tag has it", func(expect expect.Expectation) {
+		out, err := toggle("package foo\n\ntype T struct {|\n\tA int `json:\"a,omitempty,string\"`\n\tB int `json:\"b,omitempty\"`\n}\n", "json")
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\ntype T struct {\n\tA int `json:\"a,string\"`\n\tB int `json:\"b\"`\n}\n"))
+	})
+}
+
+func TestName(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it keeps acronyms together", func(expect expect.Expectation) {
+		expect(structtags.Name("HTTPServerID", structtags.SnakeCase)).To(equal("http_server_id"))
+		expect(structtags.Name("HTTPServerID", structtags.CamelCase)).To(equal("httpServerID"))
+		expect(structtags.Name("ID", structtags.CamelCase)).To(equal("id"))
+	})
+
+	o.Spec("it splits on underscores and digits", func(expect expect.Expectation) {
+		expect(structtags.Name("Address2Line", structtags.SnakeCase)).To(equal("address2_line"))
+		expect(structtags.Name("Legacy_name", structtags.CamelCase)).To(equal("legacyName"))
+	})
+}
@@ -0,0 +1,97 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scaffold
+
+// goMod is the go.mod file shared by the built-in templates.
+// Dependencies aren't listed, so that `go mod tidy` can pick their
+// latest versions.
+const goMod = `module {{.Module}}
+{{if .GoVersion}}
+go {{.GoVersion}}
+{{end}}`
+
+// builtins are the templates that are available without any template
+// directories.
+var builtins = []Template{
+	{
+		Name: "library",
+		Files: map[string]string{
+			"go.mod": goMod,
+			"{{.Package}}.go": `// Package {{.Package}} is the {{.Name}} library.
+package {{.Package}}
+`,
+		},
+	},
+	{
+		Name: "cli",
+		Files: map[string]string{
+			"go.mod": goMod,
+			"main.go": `package main
+
+import "{{.Module}}/cmd"
+
+func main() {
+	cmd.Execute()
+}
+`,
+			"cmd/root.go": `// Package cmd contains the commands for {{.Name}}.
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "{{.Package}}",
+	Short: "{{.Name}}",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+}
+
+// Execute runs the root command, exiting when it fails.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`,
+		},
+	},
+	{
+		Name: "web",
+		Files: map[string]string{
+			"go.mod": goMod,
+			"main.go": `package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "the address to listen on")
+	flag.Parse()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", index)
+
+	log.Printf("{{.Name}} listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+func index(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, "Hello from {{.Name}}")
+}
+`,
+		},
+	},
+}
@@ -0,0 +1,232 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package scaffold creates the files for new projects from templates.
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// templateExt is stripped from the names of files in template
+// directories, so that templates for Go files can be stored without
+// being mistaken for Go code.
+const templateExt = ".tmpl"
+
+// A Template is a set of files that a new project is created from.
+type Template struct {
+	Name string
+
+	// Files maps each file's slash-separated path, relative to the
+	// project's root, to its contents.  Both are text/template
+	// templates which are executed with a Data.
+	Files map[string]string
+}
+
+// Data is the data that template files are executed with.
+type Data struct {
+	// Name is the name of the project.
+	Name string
+
+	// Module is the project's module path.
+	Module string
+
+	// Package is a package name derived from Module.
+	Package string
+
+	// GoVersion is the language version for go.mod, e.g. "1.21".
+	// It's empty if vidar was built with a development version of
+	// Go.
+	GoVersion string
+}
+
+// NewData returns the Data for a project named name with the module
+// path module.
+func NewData(name, module string) Data {
+	return Data{
+		Name:      name,
+		Module:    module,
+		Package:   packageName(module),
+		GoVersion: goVersion(runtime.Version()),
+	}
+}
+
+var nonIdent = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// packageName returns a valid package name for the module path
+// module, following the go tool's convention of using the last
+// element of the path.
+func packageName(module string) string {
+	name := path.Base(module)
+	if len(name) >= 2 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		// Major version suffixes aren't part of the package name.
+		name = path.Base(path.Dir(module))
+	}
+	name = strings.TrimPrefix(name, "go-")
+	name = strings.ToLower(nonIdent.ReplaceAllString(name, ""))
+	if name == "" || name == "." || name == "/" || (name[0] >= '0' && name[0] <= '9') {
+		return "app"
+	}
+	return name
+}
+
+// goVersion returns the language version of the Go release version,
+// e.g. "1.21" for "go1.21.3".
+func goVersion(version string) string {
+	if !strings.HasPrefix(version, "go") {
+		return ""
+	}
+	parts := strings.SplitN(strings.TrimPrefix(version, "go"), ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	minor := parts[1]
+	if i := strings.IndexAny(minor, "abcdefghijklmnopqrstuvwxyz"); i >= 0 {
+		// Pre-releases, like go1.21rc2.
+		minor = minor[:i]
+	}
+	return parts[0] + "." + minor
+}
+
+// Names returns the names of the built-in templates and the templates
+// in dir, sorted.
+func Names(dir string) []string {
+	templates, _ := Load(dir)
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load returns the built-in templates along with the templates in
+// dir, keyed by name.  Each directory in dir is a template named after
+// the directory, and each file below it (with any .tmpl extension
+// removed) is one of the template's files.  A template in dir replaces
+// the built-in template of the same name.  A missing dir is not an
+// error.
+func Load(dir string) (map[string]Template, error) {
+	templates := make(map[string]Template, len(builtins))
+	for _, t := range builtins {
+		templates[t.Name] = t
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return templates, nil
+	}
+	if err != nil {
+		return templates, err
+	}
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		t, err := loadDir(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return templates, err
+		}
+		templates[t.Name] = t
+	}
+	return templates, nil
+}
+
+func loadDir(dir string) (Template, error) {
+	t := Template{
+		Name:  filepath.Base(dir),
+		Files: make(map[string]string),
+	}
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		contents, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		t.Files[strings.TrimSuffix(filepath.ToSlash(rel), templateExt)] = string(contents)
+		return nil
+	})
+	return t, err
+}
+
+// Create writes t's files to dir, executed with data, and returns the
+// paths of the files that it wrote.  Files that already exist in dir
+// are left alone.  Non-Go files are written first, and then header is
+// called with the path of each Go file and its result is written at
+// the top of the file, so that a license header template that t
+// creates is used for its own Go files.
+func Create(dir string, t Template, data Data, header func(path string) string) ([]string, error) {
+	rendered := make(map[string][]byte, len(t.Files))
+	for file, contents := range t.Files {
+		name, err := execute(file, file, data)
+		if err != nil {
+			return nil, fmt.Errorf("could not execute the name of %s in template %s: %s", file, t.Name, err)
+		}
+		body, err := execute(file, contents, data)
+		if err != nil {
+			return nil, fmt.Errorf("could not execute %s in template %s: %s", file, t.Name, err)
+		}
+		rendered[string(name)] = body
+	}
+	names := make([]string, 0, len(rendered))
+	for name := range rendered {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		iGo, jGo := path.Ext(names[i]) == ".go", path.Ext(names[j]) == ".go"
+		if iGo != jGo {
+			return jGo
+		}
+		return names[i] < names[j]
+	})
+	var created []string
+	for _, name := range names {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if _, err := os.Stat(p); err == nil {
+			continue
+		}
+		contents := rendered[name]
+		if path.Ext(name) == ".go" && header != nil {
+			contents = append([]byte(header(p)), contents...)
+		}
+		if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+			return created, err
+		}
+		if err := ioutil.WriteFile(p, contents, 0644); err != nil {
+			return created, err
+		}
+		created = append(created, p)
+	}
+	return created, nil
+}
+
+func execute(name, text string, data Data) ([]byte, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,121 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scaffold_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nelsam/vidar/scaffold"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+type Expectation = expect.Expectation
+
+var (
+	Not              = matchers.Not
+	HaveOccurred     = matchers.HaveOccurred
+	Equal            = matchers.Equal
+	HaveLen          = matchers.HaveLen
+	ContainSubstring = matchers.ContainSubstring
+)
+
+func TestScaffold(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (Expectation, string) {
+		dir, err := ioutil.TempDir("", "vidar-scaffold")
+		if err != nil {
+			t.Fatalf("Could not create temp dir: %s", err)
+		}
+		return expect.New(t), dir
+	})
+
+	o.AfterEach(func(expect Expectation, dir string) {
+		os.RemoveAll(dir)
+	})
+
+	read := func(expect Expectation, path string) string {
+		b, err := ioutil.ReadFile(path)
+		expect(err).To(Not(HaveOccurred()))
+		return string(b)
+	}
+
+	o.Spec("it includes the built-in templates", func(expect Expectation, dir string) {
+		expect(scaffold.Names(filepath.Join(dir, "missing"))).To(Equal([]string{"cli", "library", "web"}))
+	})
+
+	o.Spec("it loads templates from directories", func(expect Expectation, dir string) {
+		err := os.MkdirAll(filepath.Join(dir, "tool", "cmd"), os.ModePerm)
+		expect(err).To(Not(HaveOccurred()))
+		err = ioutil.WriteFile(filepath.Join(dir, "tool", "cmd", "main.go.tmpl"), []byte("package main\n"), 0600)
+		expect(err).To(Not(HaveOccurred()))
+
+		templates, err := scaffold.Load(dir)
+		expect(err).To(Not(HaveOccurred()))
+		expect(templates).To(HaveLen(4))
+		expect(templates["tool"].Files).To(Equal(map[string]string{"cmd/main.go": "package main\n"}))
+	})
+
+	o.Spec("it executes file names and contents", func(expect Expectation, dir string) {
+		tmpl := scaffold.Template{
+			Name: "test",
+			Files: map[string]string{
+				"go.mod":          "module {{.Module}}\n",
+				"{{.Package}}.go": "package {{.Package}}\n",
+			},
+		}
+		created, err := scaffold.Create(dir, tmpl, scaffold.NewData("Foo", "example.com/go-foo/v2"), nil)
+		expect(err).To(Not(HaveOccurred()))
+		expect(created).To(Equal([]string{filepath.Join(dir, "go.mod"), filepath.Join(dir, "foo.go")}))
+		expect(read(expect, filepath.Join(dir, "go.mod"))).To(Equal("module example.com/go-foo/v2\n"))
+		expect(read(expect, filepath.Join(dir, "foo.go"))).To(Equal("package foo\n"))
+	})
+
+	o.Spec("it adds headers to go files after writing other files", func(expect Expectation, dir string) {
+		tmpl := scaffold.Template{
+			Name: "test",
+			Files: map[string]string{
+				".license-header": "// Copyright me\n",
+				"main.go":         "package main\n",
+			},
+		}
+		header := func(path string) string {
+			b, err := ioutil.ReadFile(filepath.Join(dir, ".license-header"))
+			expect(err).To(Not(HaveOccurred()))
+			return string(b) + "\n"
+		}
+		_, err := scaffold.Create(dir, tmpl, scaffold.NewData("main", "main"), header)
+		expect(err).To(Not(HaveOccurred()))
+		expect(read(expect, filepath.Join(dir, "main.go"))).To(Equal("// Copyright me\n\npackage main\n"))
+		expect(read(expect, filepath.Join(dir, ".license-header"))).To(Equal("// Copyright me\n"))
+	})
+
+	o.Spec("it leaves existing files alone", func(expect Expectation, dir string) {
+		err := ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package existing\n"), 0600)
+		expect(err).To(Not(HaveOccurred()))
+
+		tmpl := scaffold.Template{Name: "test", Files: map[string]string{"main.go": "package main\n"}}
+		created, err := scaffold.Create(dir, tmpl, scaffold.NewData("main", "main"), nil)
+		expect(err).To(Not(HaveOccurred()))
+		expect(created).To(HaveLen(0))
+		expect(read(expect, filepath.Join(dir, "main.go"))).To(Equal("package existing\n"))
+	})
+
+	o.Spec("it creates the cli template", func(expect Expectation, dir string) {
+		templates, err := scaffold.Load(filepath.Join(dir, "missing"))
+		expect(err).To(Not(HaveOccurred()))
+		_, err = scaffold.Create(dir, templates["cli"], scaffold.NewData("Tool", "example.com/tool"), nil)
+		expect(err).To(Not(HaveOccurred()))
+		expect(read(expect, filepath.Join(dir, "go.mod"))).To(ContainSubstring("module example.com/tool\n"))
+		expect(read(expect, filepath.Join(dir, "main.go"))).To(ContainSubstring(`import "example.com/tool/cmd"`))
+		expect(read(expect, filepath.Join(dir, "cmd", "root.go"))).To(ContainSubstring("github.com/spf13/cobra"))
+	})
+}
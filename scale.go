@@ -0,0 +1,92 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package main
+
+import (
+	"time"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/dpi"
+	"github.com/nelsam/vidar/setting"
+)
+
+// scaleInterval is how often windows check whether they've moved to a
+// monitor that needs a different scale.
+const scaleInterval = time.Second
+
+// UpdateScale scales w by the uiscale setting, or by the scale for
+// the DPI of the monitor that most of w is on if the setting is 0.  It
+// returns w's new scale.
+func (w *window) UpdateScale() float32 {
+	scale := setting.UIScale()
+	if scale <= 0 {
+		scale = detectScale(w.Viewport())
+	}
+	if scale != w.Scale() {
+		w.SetScale(scale)
+	}
+	return scale
+}
+
+// detectScale returns the scale for the monitor that the center of v
+// is on.  It must be called on the UI goroutine, since that's where
+// glfw may be used.
+func detectScale(v gxui.Viewport) float32 {
+	pos, size := v.Position(), v.SizePixels()
+	m, ok := dpi.At(monitors(), pos.X+size.W/2, pos.Y+size.H/2)
+	if !ok {
+		return 1
+	}
+	return dpi.Scale(m.DPI())
+}
+
+func monitors() []dpi.Monitor {
+	var all []dpi.Monitor
+	for _, m := range glfw.GetMonitors() {
+		mode := m.GetVideoMode()
+		if mode == nil {
+			continue
+		}
+		x, y := m.GetPos()
+		widthMM, heightMM := m.GetPhysicalSize()
+		all = append(all, dpi.Monitor{
+			X:        x,
+			Y:        y,
+			Width:    mode.Width,
+			Height:   mode.Height,
+			WidthMM:  widthMM,
+			HeightMM: heightMM,
+		})
+	}
+	return all
+}
+
+// watchScale updates w's scale every scaleInterval until w is closed,
+// so that moving w to a monitor with a different DPI rescales it.
+func watchScale(driver gxui.Driver, w *window) {
+	closed := make(chan struct{})
+	w.OnClose(func() {
+		close(closed)
+	})
+	go func() {
+		ticker := time.NewTicker(scaleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-closed:
+				return
+			case <-ticker.C:
+			}
+			driver.Call(func() {
+				select {
+				case <-closed:
+				default:
+					w.UpdateScale()
+				}
+			})
+		}
+	}()
+}
@@ -0,0 +1,110 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package scoring
+
+import (
+	"math"
+	"unicode"
+)
+
+const (
+	// Costs of skipping runes in Fuzzy.  Skipping to the start
+	// of a word is cheap, since abbreviations are usually made
+	// of the first letters of words.
+	skip       = 1
+	skipToWord = 0.2
+	leading    = 2
+	leadToWord = 0.2
+)
+
+// Fuzzy scores suggestion against partial, where the runes in partial
+// may be spread out in suggestion as long as they're in order.  Like
+// Score, lower is better; suggestions that don't contain all of
+// partial score math.MaxFloat64.  Runs of matching runes and matches
+// at the start of a word (after an underscore or at a capital letter)
+// score best, so "nrc" matches NewRuneCount better than
+// unrecognized.
+func Fuzzy(suggestion, partial []rune) float64 {
+	score := 0.0
+	next := 0
+	for i, r := range partial {
+		idx := fuzzyIndex(suggestion, partial[i:], next)
+		if idx == -1 {
+			return math.MaxFloat64
+		}
+		if suggestion[idx] != r {
+			score += changeCase
+		}
+		gap := float64(idx - next)
+		word := wordStart(suggestion, idx)
+		switch {
+		case i == 0 && word:
+			score += gap * leadToWord
+		case i == 0:
+			score += gap * leading
+		case word:
+			score += gap * skipToWord
+		default:
+			score += gap * skip
+		}
+		next = idx + 1
+	}
+	return score + float64(len(suggestion)-next)*append
+}
+
+// fuzzyIndex returns the index in suggestion, starting at from, that
+// partial[0] should be matched at.  The rune at from is used if it
+// matches, so that runs stay together.  Otherwise, the start of a
+// word is preferred as long as the rest of partial can still be
+// matched after it.
+func fuzzyIndex(suggestion, partial []rune, from int) int {
+	first := -1
+	for i := from; i < len(suggestion); i++ {
+		if !sameRune(suggestion[i], partial[0]) {
+			continue
+		}
+		if i == from {
+			return i
+		}
+		if first == -1 {
+			first = i
+		}
+		if wordStart(suggestion, i) && subsequence(suggestion[i+1:], partial[1:]) {
+			return i
+		}
+	}
+	return first
+}
+
+func subsequence(s, sub []rune) bool {
+	for _, r := range s {
+		if len(sub) == 0 {
+			break
+		}
+		if sameRune(r, sub[0]) {
+			sub = sub[1:]
+		}
+	}
+	return len(sub) == 0
+}
+
+func sameRune(a, b rune) bool {
+	return a == b || unicode.ToLower(a) == unicode.ToLower(b)
+}
+
+// wordStart returns whether the rune at i in s starts a word.
+func wordStart(s []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, r := s[i-1], s[i]
+	switch {
+	case prev == '_' || prev == '-' || prev == '.' || prev == ' ':
+		return true
+	case unicode.IsUpper(r) && !unicode.IsUpper(prev):
+		return true
+	}
+	return false
+}
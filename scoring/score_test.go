@@ -5,6 +5,7 @@
 package scoring_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/a8m/expect"
@@ -41,3 +42,20 @@ func TestSort(t *testing.T) {
 		"SomeLongThing",
 	})
 }
+
+func TestFuzzy(t *testing.T) {
+	expect := expect.New(t)
+
+	expect(scoring.Fuzzy([]rune("Println"), []rune("pnl"))).To.Be.Below(math.MaxFloat64)
+	expect(scoring.Fuzzy([]rune("Println"), []rune("lnp"))).To.Equal(math.MaxFloat64)
+
+	score := func(s string) float64 {
+		return scoring.Fuzzy([]rune(s), []rune("nrc"))
+	}
+	expect(score("nrc")).To.Be.Below(score("NewRuneCount"))
+	expect(score("NewRuneCount")).To.Be.Below(score("unrecognized"))
+
+	// Word starts should be preferred, but not at the cost of
+	// matching the rest of the partial.
+	expect(scoring.Fuzzy([]rune("abcBd"), []rune("bc"))).To.Be.Below(math.MaxFloat64)
+}
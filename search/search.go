@@ -0,0 +1,143 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package search finds the matches of a find or replace pattern in
+// text, using the options that vidar's find and replace prompts can
+// toggle.
+package search
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrBadRegexp is returned when a pattern is meant to be a regular
+// expression, but can't be compiled.
+var ErrBadRegexp = errors.New("Incorrect regexp")
+
+// Options change how a pattern is matched.
+type Options struct {
+	// CaseSensitive only matches letters with letters of the same
+	// case.
+	CaseSensitive bool
+
+	// WholeWord only matches text that isn't next to a letter,
+	// digit, or underscore.
+	WholeWord bool
+
+	// Regex treats the pattern as a regular expression instead of
+	// literal text.
+	Regex bool
+
+	// PreserveCase changes the case of replacements to match the
+	// text that they replace.  Matches can only differ in case if
+	// letters of any case match, so patterns are matched without
+	// case sensitivity when it's set.
+	PreserveCase bool
+}
+
+// Span is a range of runes.
+type Span struct {
+	Start, End int
+}
+
+// Pattern is a compiled find pattern.
+type Pattern struct {
+	exp  *regexp.Regexp
+	opts Options
+}
+
+// Compile compiles pattern with opts.
+func Compile(pattern string, opts Options) (*Pattern, error) {
+	expr := pattern
+	if !opts.Regex {
+		expr = regexp.QuoteMeta(pattern)
+	}
+	if !opts.CaseSensitive || opts.PreserveCase {
+		expr = "(?i)" + expr
+	}
+	exp, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, ErrBadRegexp
+	}
+	return &Pattern{exp: exp, opts: opts}, nil
+}
+
+// Find returns the spans of p's matches in text, in order.  Empty
+// matches are skipped, since there's nothing to highlight or select.
+func (p *Pattern) Find(text string) []Span {
+	var (
+		spans       []Span
+		prev, runes int
+	)
+	for _, m := range p.exp.FindAllStringIndex(text, -1) {
+		if m[1] == m[0] || p.opts.WholeWord && !wholeWord(text, m[0], m[1]) {
+			continue
+		}
+		runes += utf8.RuneCountInString(text[prev:m[0]])
+		start := runes
+		runes += utf8.RuneCountInString(text[m[0]:m[1]])
+		spans = append(spans, Span{Start: start, End: runes})
+		prev = m[1]
+	}
+	return spans
+}
+
+// Replacement returns the text that replaces matched, one of p's
+// matches, with replacement.  Regular expressions may refer to
+// capture groups (e.g. $1 or ${name}) in replacement.
+func (p *Pattern) Replacement(matched, replacement string) string {
+	if p.opts.Regex {
+		replacement = p.exp.ReplaceAllString(matched, replacement)
+	}
+	if p.opts.PreserveCase {
+		replacement = PreserveCase(matched, replacement)
+	}
+	return replacement
+}
+
+// PreserveCase changes the case of replacement to follow the case of
+// matched: it's upper case if matched is upper case, lower case if
+// matched is lower case, and otherwise starts with the same case that
+// matched starts with.  For example, replacing "Foo" with "Bar" also
+// replaces "foo" with "bar" and "FOO" with "BAR".
+func PreserveCase(matched, replacement string) string {
+	hasUpper, hasLower := false, false
+	for _, r := range matched {
+		hasUpper = hasUpper || unicode.IsUpper(r)
+		hasLower = hasLower || unicode.IsLower(r)
+	}
+	switch {
+	case hasUpper && !hasLower:
+		return strings.ToUpper(replacement)
+	case hasLower && !hasUpper:
+		return strings.ToLower(replacement)
+	}
+	first, _ := utf8.DecodeRuneInString(matched)
+	r, size := utf8.DecodeRuneInString(replacement)
+	switch {
+	case size == 0:
+		return replacement
+	case unicode.IsUpper(first):
+		return string(unicode.ToUpper(r)) + replacement[size:]
+	case unicode.IsLower(first):
+		return string(unicode.ToLower(r)) + replacement[size:]
+	}
+	return replacement
+}
+
+// wholeWord returns whether or not the bytes from start to end in
+// text are a whole word, i.e. they aren't next to word characters.
+func wholeWord(text string, start, end int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:start])
+	after, _ := utf8.DecodeRuneInString(text[end:])
+	return !isWord(before) && !isWord(after)
+}
+
+func isWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,109 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package search_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/search"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal        = matchers.Equal
+	haveOccurred = matchers.HaveOccurred
+	not          = matchers.Not
+)
+
+func TestFind(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	find := func(expect expect.Expectation, pattern string, opts search.Options, text string) []search.Span {
+		p, err := search.Compile(pattern, opts)
+		expect(err).To(not(haveOccurred()))
+		return p.Find(text)
+	}
+
+	o.Spec("it matches literal text in runes", func(expect expect.Expectation) {
+		spans := find(expect, "a.b", search.Options{CaseSensitive: true}, "ü a.b axb A.B")
+		expect(spans).To(equal([]search.Span{{Start: 2, End: 5}}))
+	})
+
+	o.Spec("it ignores case unless it's case sensitive", func(expect expect.Expectation) {
+		spans := find(expect, "foo", search.Options{}, "foo Foo FOO")
+		expect(spans).To(equal([]search.Span{{Start: 0, End: 3}, {Start: 4, End: 7}, {Start: 8, End: 11}}))
+	})
+
+	o.Spec("it skips matches inside of other words", func(expect expect.Expectation) {
+		spans := find(expect, "foo", search.Options{CaseSensitive: true, WholeWord: true}, "foo foobar _foo (foo)")
+		expect(spans).To(equal([]search.Span{{Start: 0, End: 3}, {Start: 17, End: 20}}))
+	})
+
+	o.Spec("it matches regular expressions", func(expect expect.Expectation) {
+		spans := find(expect, "b+", search.Options{CaseSensitive: true, Regex: true}, "abbc x* b")
+		expect(spans).To(equal([]search.Span{{Start: 1, End: 3}, {Start: 8, End: 9}}))
+	})
+
+	o.Spec("it skips empty matches", func(expect expect.Expectation) {
+		spans := find(expect, "x*", search.Options{Regex: true}, "ab x")
+		expect(spans).To(equal([]search.Span{{Start: 3, End: 4}}))
+	})
+
+	o.Spec("it rejects bad regular expressions", func(expect expect.Expectation) {
+		_, err := search.Compile("(", search.Options{Regex: true})
+		expect(err).To(equal(search.ErrBadRegexp))
+		_, err = search.Compile("(", search.Options{})
+		expect(err).To(not(haveOccurred()))
+	})
+
+	o.Spec("it ignores case when preserving case", func(expect expect.Expectation) {
+		spans := find(expect, "Foo", search.Options{CaseSensitive: true, PreserveCase: true}, "foo FOO")
+		expect(spans).To(equal([]search.Span{{Start: 0, End: 3}, {Start: 4, End: 7}}))
+	})
+}
+
+func TestReplacement(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it preserves the case of the replaced text", func(expect expect.Expectation) {
+		for _, c := range []struct{ matched, want string }{
+			{"Foo", "Bar"},
+			{"foo", "bar"},
+			{"FOO", "BAR"},
+			{"fOO", "bar"},
+			{"fooBaz", "bar"},
+			{"123", "Bar"},
+		} {
+			expect(search.PreserveCase(c.matched, "Bar")).To(equal(c.want))
+		}
+		expect(search.PreserveCase("Foo", "")).To(equal(""))
+		expect(search.PreserveCase("Foo", "éclair")).To(equal("Éclair"))
+	})
+
+	o.Spec("it expands capture groups in regular expressions", func(expect expect.Expectation) {
+		p, err := search.Compile(`(\w+)\.Foo`, search.Options{Regex: true, PreserveCase: true})
+		expect(err).To(not(haveOccurred()))
+		expect(p.Replacement("x.foo", "${1}_bar")).To(equal("x_bar"))
+		expect(p.Replacement("X.FOO", "${1}_bar")).To(equal("X_BAR"))
+	})
+
+	o.Spec("it leaves the replacement alone without options", func(expect expect.Expectation) {
+		p, err := search.Compile("foo", search.Options{CaseSensitive: true})
+		expect(err).To(not(haveOccurred()))
+		expect(p.Replacement("foo", "$1Bar")).To(equal("$1Bar"))
+	})
+}
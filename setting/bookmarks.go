@@ -0,0 +1,108 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const bookmarksKey = "bookmarks"
+
+// Bookmark is a bookmarked line in a file.
+type Bookmark struct {
+	Path string
+
+	// Line is the zero-based index of the bookmarked line.
+	Line int
+}
+
+func allBookmarks() map[string][]Bookmark {
+	b, ok := projects.Get(bookmarksKey).(map[string][]Bookmark)
+	if !ok {
+		return nil
+	}
+	return b
+}
+
+// Bookmarks returns the bookmarks in the project named project,
+// sorted by path and line.
+func Bookmarks(project string) []Bookmark {
+	marks := append([]Bookmark(nil), allBookmarks()[project]...)
+	sort.Slice(marks, func(i, j int) bool {
+		if marks[i].Path != marks[j].Path {
+			return marks[i].Path < marks[j].Path
+		}
+		return marks[i].Line < marks[j].Line
+	})
+	return marks
+}
+
+// FileBookmarks returns the bookmarked lines in path, in the project
+// named project.
+func FileBookmarks(project, path string) []int {
+	var lines []int
+	for _, b := range Bookmarks(project) {
+		if b.Path == path {
+			lines = append(lines, b.Line)
+		}
+	}
+	return lines
+}
+
+// SetFileBookmarks replaces the bookmarked lines in path, in the
+// project named project, and writes them to the projects file.
+func SetFileBookmarks(project, path string, lines []int) {
+	all := make(map[string][]Bookmark)
+	for name, marks := range allBookmarks() {
+		all[name] = marks
+	}
+	var marks []Bookmark
+	for _, b := range all[project] {
+		if b.Path != path {
+			marks = append(marks, b)
+		}
+	}
+	for _, l := range lines {
+		marks = append(marks, Bookmark{Path: path, Line: l})
+	}
+	if len(marks) == 0 {
+		delete(all, project)
+	} else {
+		all[project] = marks
+	}
+	projects.Set(bookmarksKey, all)
+	if err := projects.Write(); err != nil {
+		log.Printf("Error updating projects file: %s", err)
+	}
+}
+
+// ProjectFor returns the project that path belongs to.  If more than
+// one project contains path, the one with the deepest root directory
+// is used; if none of them do, DefaultProject is returned.
+func ProjectFor(path string) Project {
+	proj := DefaultProject
+	deepest := ""
+	for _, p := range Projects() {
+		root, ok := p.RootFor(path)
+		if !ok {
+			continue
+		}
+		if proj.Name == DefaultProject.Name || len(root) > len(deepest) {
+			proj, deepest = p, root
+		}
+	}
+	return proj
+}
+
+func contains(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,166 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"fmt"
+	"go/build"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const buildContextsKey = "build_contexts"
+
+// BuildContext is the GOOS, GOARCH, and build tags that go files in a
+// project are matched against.  Empty fields use the defaults from
+// go/build.
+type BuildContext struct {
+	GOOS   string
+	GOARCH string
+	Tags   []string
+}
+
+// ParseBuildContext parses a build context in the form that
+// BuildContext.String returns: "GOOS/GOARCH", optionally followed by
+// a space and a comma separated list of tags.  An empty string is the
+// default context.
+func ParseBuildContext(s string) (BuildContext, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return BuildContext{}, nil
+	}
+	if len(fields) > 2 {
+		return BuildContext{}, fmt.Errorf("expected GOOS/GOARCH followed by tags, got %q", s)
+	}
+	var c BuildContext
+	platform := strings.Split(fields[0], "/")
+	if len(platform) != 2 {
+		return BuildContext{}, fmt.Errorf("expected GOOS/GOARCH, got %q", fields[0])
+	}
+	c.GOOS, c.GOARCH = platform[0], platform[1]
+	if len(fields) == 2 {
+		for _, t := range strings.Split(fields[1], ",") {
+			if t != "" {
+				c.Tags = append(c.Tags, t)
+			}
+		}
+	}
+	return c, nil
+}
+
+// Context returns the go/build context for c.
+func (c BuildContext) Context() build.Context {
+	ctx := build.Default
+	if c.GOOS != "" {
+		ctx.GOOS = c.GOOS
+	}
+	if c.GOARCH != "" {
+		ctx.GOARCH = c.GOARCH
+	}
+	ctx.BuildTags = c.Tags
+	return ctx
+}
+
+// Default returns whether or not c is the default context.
+func (c BuildContext) Default() bool {
+	return c.GOOS == "" && c.GOARCH == "" && len(c.Tags) == 0
+}
+
+func (c BuildContext) String() string {
+	ctx := c.Context()
+	s := ctx.GOOS + "/" + ctx.GOARCH
+	if len(c.Tags) > 0 {
+		s += " " + strings.Join(c.Tags, ",")
+	}
+	return s
+}
+
+// Matches returns whether or not the file at path would be built in
+// c, by its name and build constraints.  Files that can't be read
+// match, so that they aren't hidden by mistake.
+func (c BuildContext) Matches(path string) bool {
+	ctx := c.Context()
+	ok, err := ctx.MatchFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return true
+	}
+	return ok
+}
+
+// environ adds c to environ, for go tools.  Since c is chosen for
+// the project while it's open, it replaces any GOOS and GOARCH that
+// are already set.  Tags are added to GOFLAGS.
+func (c BuildContext) environ(environ []string) []string {
+	if c.GOOS != "" {
+		environ = addEnv(environ, "GOOS", "="+c.GOOS)
+	}
+	if c.GOARCH != "" {
+		environ = addEnv(environ, "GOARCH", "="+c.GOARCH)
+	}
+	if len(c.Tags) == 0 {
+		return environ
+	}
+	tags := "-tags=" + strings.Join(c.Tags, ",")
+	for i, v := range environ {
+		if strings.HasPrefix(v, "GOFLAGS=") {
+			environ[i] = v + " " + tags
+			return environ
+		}
+	}
+	return append(environ, "GOFLAGS="+tags)
+}
+
+var (
+	buildContextListenersMu sync.RWMutex
+	buildContextListeners   []func(project string)
+)
+
+// OnBuildContextChange registers f to be called whenever the build
+// context of a project is changed with SetBuildContext.  project is
+// the name of the project.
+func OnBuildContextChange(f func(project string)) {
+	buildContextListenersMu.Lock()
+	defer buildContextListenersMu.Unlock()
+	buildContextListeners = append(buildContextListeners, f)
+}
+
+func allBuildContexts() map[string]BuildContext {
+	c, ok := projects.Get(buildContextsKey).(map[string]BuildContext)
+	if !ok {
+		return nil
+	}
+	return c
+}
+
+// BuildContext returns p's build context.
+func (p Project) BuildContext() BuildContext {
+	return allBuildContexts()[p.Name]
+}
+
+// SetBuildContext replaces the build context of the project named
+// project and writes it to the projects file.
+func SetBuildContext(project string, c BuildContext) {
+	all := make(map[string]BuildContext)
+	for name, ctx := range allBuildContexts() {
+		all[name] = ctx
+	}
+	if c.Default() {
+		delete(all, project)
+	} else {
+		all[project] = c
+	}
+	projects.Set(buildContextsKey, all)
+	if err := projects.Write(); err != nil {
+		log.Printf("Error updating projects file: %s", err)
+	}
+
+	buildContextListenersMu.RLock()
+	defer buildContextListenersMu.RUnlock()
+	for _, f := range buildContextListeners {
+		f(project)
+	}
+}
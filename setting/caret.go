@@ -0,0 +1,88 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	caretStyleKey  = "caretstyle"
+	caretBlinkKey  = "caretblink"
+	currentLineKey = "highlightcurrentline"
+
+	// DefaultCaretBlink is the time that the caret spends shown, and
+	// then hidden, if no blink rate is found in the config files.
+	DefaultCaretBlink = 500 * time.Millisecond
+)
+
+// CaretStyle is the shape that carets are drawn with.
+type CaretStyle string
+
+// The shapes that carets may be drawn with.
+const (
+	CaretBar       CaretStyle = "bar"
+	CaretBlock     CaretStyle = "block"
+	CaretUnderline CaretStyle = "underline"
+)
+
+// CaretStyles are the valid values for the caret style setting.
+var CaretStyles = []CaretStyle{CaretBar, CaretBlock, CaretUnderline}
+
+// Caret returns the shape that carets should be drawn with.
+func Caret() CaretStyle {
+	style, _ := settings.Get(caretStyleKey).(string)
+	for _, s := range CaretStyles {
+		if CaretStyle(style) == s {
+			return s
+		}
+	}
+	return CaretBar
+}
+
+// CaretBlink returns the time that carets spend shown, and then
+// hidden, while they blink.  A zero duration means that carets don't
+// blink.
+func CaretBlink() time.Duration {
+	v, _ := settings.Get(caretBlinkKey).(string)
+	if v == "" {
+		return DefaultCaretBlink
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Printf("Error parsing %s %q: %v", caretBlinkKey, v, err)
+		return DefaultCaretBlink
+	}
+	return d
+}
+
+// HighlightCurrentLine returns whether or not the background of the
+// lines that carets are on should be highlighted.
+func HighlightCurrentLine() bool {
+	highlight, ok := settings.Get(currentLineKey).(bool)
+	if !ok {
+		return true
+	}
+	return highlight
+}
+
+func setCaretStyle(v string) error {
+	for _, s := range CaretStyles {
+		if CaretStyle(v) == s {
+			return save(settings, caretStyleKey, v)
+		}
+	}
+	return fmt.Errorf("%q is not a caret style", v)
+}
+
+func setCaretBlink(v string) error {
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return fmt.Errorf("%q is not a duration", v)
+	}
+	return save(settings, caretBlinkKey, v)
+}
@@ -0,0 +1,54 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import "log"
+
+const (
+	clipboardKey        = "clipboard"
+	clipboardHistoryKey = "clipboardhistory"
+
+	// DefaultClipboardHistorySize is the number of copied or cut
+	// texts that are kept if no size is found in the config files.
+	DefaultClipboardHistorySize = 20
+)
+
+// Clipboard is the configuration for clipboard history.
+type Clipboard struct {
+	// HistorySize is the number of copied or cut texts that are
+	// kept for pasting from the history.
+	HistorySize int
+
+	// Persist turns on saving clipboard history to the session
+	// file, so that it's kept across restarts.
+	Persist bool
+}
+
+// ClipboardConfig returns the current clipboard history settings.
+func ClipboardConfig() Clipboard {
+	c, ok := settings.Get(clipboardKey).(Clipboard)
+	if !ok {
+		return Clipboard{HistorySize: DefaultClipboardHistorySize}
+	}
+	if c.HistorySize <= 0 {
+		c.HistorySize = DefaultClipboardHistorySize
+	}
+	return c
+}
+
+// ClipboardHistory returns the clipboard history that was saved to
+// the session file, most recent first.
+func ClipboardHistory() []string {
+	entries, _ := sessions.Get(clipboardHistoryKey).([]string)
+	return entries
+}
+
+// SetClipboardHistory writes entries to the session file.
+func SetClipboardHistory(entries []string) {
+	sessions.Set(clipboardHistoryKey, entries)
+	if err := sessions.Write(); err != nil {
+		log.Printf("Error updating session file: %s", err)
+	}
+}
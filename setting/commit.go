@@ -0,0 +1,51 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+const commitKey = "commit"
+
+// DefaultCommitGuides are the columns that guide lines are drawn at in
+// the commit message editor if none are found in the config files:
+// the usual limits for the summary line and for the body.
+var DefaultCommitGuides = []int{50, 72}
+
+// Commit is the configuration for the commit message editor in the
+// source control pane.
+type Commit struct {
+	// Guides are the columns that vertical guide lines are drawn at.
+	Guides []int
+
+	// Templates are named commit messages that can be used to start
+	// a new message, e.g. "fix": "Fix \n\nFixes #".
+	Templates map[string]string
+}
+
+// CommitConfig returns the global commit message settings.
+func CommitConfig() Commit {
+	c, ok := settings.Get(commitKey).(Commit)
+	if !ok {
+		return Commit{Guides: DefaultCommitGuides}
+	}
+	return c.withDefaults()
+}
+
+// CommitConfig returns p's commit message settings, from its project
+// settings if they override them or from the global settings
+// otherwise.
+func (p Project) CommitConfig() Commit {
+	if c := p.Settings().Commit; c != nil {
+		return c.withDefaults()
+	}
+	return CommitConfig()
+}
+
+// withDefaults fills in the guides if they were left out of the
+// config files.  An empty, non-nil list turns the guides off.
+func (c Commit) withDefaults() Commit {
+	if c.Guides == nil {
+		c.Guides = DefaultCommitGuides
+	}
+	return c
+}
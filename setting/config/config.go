@@ -7,6 +7,7 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,6 +17,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v2"
@@ -86,10 +88,15 @@ type Config struct {
 
 	writePath  string
 	choseCodec codec
+	found      bool
 
-	// TODO: use an fsw.Watcher to watch for filesystem changes and
-	// reload.
-	data map[string]interface{}
+	mu       sync.RWMutex
+	data     map[string]interface{}
+	defaults map[string]interface{}
+
+	// raw is the contents of c's file when it was last read or
+	// written, so that Reload can tell whether it has changed.
+	raw []byte
 }
 
 // New returns a Config for the given Config file name (without
@@ -111,7 +118,8 @@ func New(opener Opener, name string, dirs ...string) (*Config, error) {
 			"yml":  codecYAML,
 			"json": codecJSON,
 		},
-		data: make(map[string]interface{}),
+		data:     make(map[string]interface{}),
+		defaults: make(map[string]interface{}),
 	}
 	if err := c.load(); err != nil && !os.IsNotExist(err) {
 		return nil, err
@@ -135,6 +143,7 @@ func (c *Config) bestFile() (io.ReadCloser, unmarshaller, error) {
 			}
 			c.writePath = path
 			c.choseCodec = c.codecs[ext]
+			c.found = true
 			return f, c.codecs[ext].unmarshal, nil
 		}
 	}
@@ -144,40 +153,100 @@ func (c *Config) bestFile() (io.ReadCloser, unmarshaller, error) {
 }
 
 func (c *Config) load() error {
-	f, unm, err := c.bestFile()
+	data, raw, err := c.read()
 	if err != nil {
 		return err
 	}
+	c.data, c.raw = data, raw
+	return nil
+}
+
+// read parses c's file, returning its data (with lowercase keys) and
+// its contents.
+func (c *Config) read() (map[string]interface{}, []byte, error) {
+	f, unm, err := c.bestFile()
+	if err != nil {
+		return nil, nil, err
+	}
 	defer f.Close()
-	if err := unm(f, &c.data); err != nil {
-		return err
+	raw, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, nil, err
 	}
-	for k, v := range c.data {
+	data := make(map[string]interface{})
+	if err := unm(bytes.NewReader(raw), &data); err != nil {
+		return nil, nil, err
+	}
+	for k, v := range data {
 		lk := strings.ToLower(k)
 		if lk == k {
 			continue
 		}
-		if _, ok := c.data[lk]; ok {
-			return fmt.Errorf("duplicate keys %s and %s found; Configs are case insensitive", k, lk)
+		if _, ok := data[lk]; ok {
+			return nil, nil, fmt.Errorf("duplicate keys %s and %s found; Configs are case insensitive", k, lk)
 		}
-		c.data[lk] = v
-		delete(c.data, k)
+		data[lk] = v
+		delete(data, k)
 	}
-	return nil
+	return data, raw, nil
+}
+
+// Reload reads c's file again, replacing c's data with its contents
+// and then applying the defaults from SetDefault.  It returns false
+// if the file hasn't changed since c last read or wrote it.  If the
+// file can't be parsed, c is left as it was.
+func (c *Config) Reload() (changed bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, raw, err := c.read()
+	if os.IsNotExist(err) {
+		data, raw, err = make(map[string]interface{}), nil, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(raw, c.raw) {
+		return false, nil
+	}
+	c.data, c.raw = data, raw
+	for k, v := range c.defaults {
+		c.setDefault(k, v)
+	}
+	return true, nil
+}
+
+// Path returns the path that c is read from and written to.
+func (c *Config) Path() string {
+	return c.writePath
+}
+
+// Exists returns whether or not a config file was found when c was
+// loaded.
+func (c *Config) Exists() bool {
+	return c.found
 }
 
 // SetDefault sets the type and the default value for the data at k.
 func (c *Config) SetDefault(k string, v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	k = strings.ToLower(k)
+	c.defaults[k] = v
+	c.setDefault(k, v)
+}
+
+func (c *Config) setDefault(k string, v interface{}) {
 	if d, ok := c.data[k]; ok {
 		c.data[k] = convert(reflect.ValueOf(d), reflect.TypeOf(v)).Interface()
 		return
 	}
-	c.Set(k, v)
+	c.data[k] = v
 }
 
 // Set sets the value at k.
 func (c *Config) Set(k string, v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	k = strings.ToLower(k)
 	c.data[k] = v
 }
@@ -185,11 +254,15 @@ func (c *Config) Set(k string, v interface{}) {
 // Get gets the value at k.  If the default value has been set using SetDefault,
 // the data will be converted to the same type as the default value.
 func (c *Config) Get(k string) interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.data[k]
 }
 
 // Keys returns a list of all keys available in c.
 func (c *Config) Keys() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	var l []string
 	for k := range c.data {
 		l = append(l, k)
@@ -200,14 +273,21 @@ func (c *Config) Keys() []string {
 // Write writes c to the path it was opened from, or the most preferred path
 // path otherwise.
 func (c *Config) Write() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var buf bytes.Buffer
+	if err := c.choseCodec.marshal(&buf, c.data); err != nil {
+		return fmt.Errorf("could not marshal settings: %s", err)
+	}
 	f, err := c.opener.Create(c.writePath)
 	if err != nil {
 		return fmt.Errorf("could not create config file %s: %s", c.writePath, err)
 	}
 	defer f.Close()
-	if err := c.choseCodec.marshal(f, c.data); err != nil {
-		return fmt.Errorf("could not marshal settings: %s", err)
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("could not write config file %s: %s", c.writePath, err)
 	}
+	c.raw = buf.Bytes()
 	return nil
 }
 
@@ -8,7 +8,9 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,6 +23,34 @@ import (
 
 type Expectation = expect.Expectation
 
+// memFiles is a config.Opener for files that are kept in memory,
+// keyed by path.
+type memFiles map[string]string
+
+func (m memFiles) Open(path string) (io.ReadCloser, error) {
+	s, ok := m[path]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return ioutil.NopCloser(strings.NewReader(s)), nil
+}
+
+func (m memFiles) Create(path string) (io.WriteCloser, error) {
+	return &memFile{files: m, path: path}, nil
+}
+
+type memFile struct {
+	bytes.Buffer
+
+	files memFiles
+	path  string
+}
+
+func (f *memFile) Close() error {
+	f.files[f.path] = f.String()
+	return nil
+}
+
 var (
 	Not          = matchers.Not
 	HaveOccurred = matchers.HaveOccurred
@@ -47,6 +77,7 @@ func TestConfig(t *testing.T) {
 
 		c, err := config.New(o, "foo", "/bar", "/baz")
 		expect(err).To(Not(HaveOccurred()))
+		expect(c.Exists()).To(Equal(false))
 		expect(c.Get("foo")).To(Equal(nil))
 		c.Set("foo", "bar")
 		expect(c.Get("foo")).To(Equal("bar"))
@@ -119,6 +150,7 @@ func TestConfig(t *testing.T) {
 	o.Spec("it loads from primary paths", func(expect Expectation, o *mockOpener) {
 		ret := newConfig(expect, o, "/bar/foo.toml", `foo = "bar"`, "foo", "/bar", "/baz")
 		expect(ret.err).To(Not(HaveOccurred()))
+		expect(ret.c.Exists()).To(Equal(true))
 		expect(ret.c.Get("foo")).To(Equal("bar"))
 	})
 
@@ -127,4 +159,42 @@ func TestConfig(t *testing.T) {
 		expect(ret.err).To(Not(HaveOccurred()))
 		expect(ret.c.Get("foo")).To(Equal("bar"))
 	})
+
+	o.Spec("it reloads files that have changed", func(expect Expectation, _ *mockOpener) {
+		files := memFiles{"/bar/foo.toml": `foo = "bar"`}
+		c, err := config.New(files, "foo", "/bar")
+		expect(err).To(Not(HaveOccurred()))
+		c.SetDefault("baz", "qux")
+
+		files["/bar/foo.toml"] = `foo = "eggs"`
+		changed, err := c.Reload()
+		expect(err).To(Not(HaveOccurred()))
+		expect(changed).To(Equal(true))
+		expect(c.Get("foo")).To(Equal("eggs"))
+		expect(c.Get("baz")).To(Equal("qux"))
+	})
+
+	o.Spec("it doesn't reload files that it wrote", func(expect Expectation, _ *mockOpener) {
+		files := memFiles{"/bar/foo.toml": `foo = "bar"`}
+		c, err := config.New(files, "foo", "/bar")
+		expect(err).To(Not(HaveOccurred()))
+
+		c.Set("foo", "eggs")
+		expect(c.Write()).To(Not(HaveOccurred()))
+		changed, err := c.Reload()
+		expect(err).To(Not(HaveOccurred()))
+		expect(changed).To(Equal(false))
+		expect(c.Get("foo")).To(Equal("eggs"))
+	})
+
+	o.Spec("it keeps its data when a reloaded file can't be parsed", func(expect Expectation, _ *mockOpener) {
+		files := memFiles{"/bar/foo.toml": `foo = "bar"`}
+		c, err := config.New(files, "foo", "/bar")
+		expect(err).To(Not(HaveOccurred()))
+
+		files["/bar/foo.toml"] = `foo = `
+		_, err = c.Reload()
+		expect(err).To(HaveOccurred())
+		expect(c.Get("foo")).To(Equal("bar"))
+	})
 }
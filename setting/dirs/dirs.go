@@ -0,0 +1,171 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package dirs decides where vidar keeps its config files and data,
+// and moves them there from older locations.
+package dirs
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Name is the name of the directories that vidar uses under each
+// platform's config and data directories.
+const Name = "vidar"
+
+// portableDataDirname is the directory under a portable config
+// directory that data is kept in.
+const portableDataDirname = "data"
+
+// Dirs is a pair of directories: Config for config files, which users
+// may edit, and Data for files that vidar manages itself (e.g. undo
+// history, recovery snapshots, and plugins).
+type Dirs struct {
+	Config string
+	Data   string
+}
+
+// Default returns the directories for goos, with environment variables
+// looked up by getenv.  XDG_CONFIG_HOME and XDG_DATA_HOME are used on
+// every platform when they're set to absolute paths, as the XDG base
+// directory spec requires.  Otherwise, linux and the BSDs use
+// ~/.config and ~/.local/share, darwin uses ~/Library/Application
+// Support for both, and windows uses %APPDATA% for config files and
+// %LOCALAPPDATA% for data.
+func Default(goos string, getenv func(string) string) Dirs {
+	home := getenv("HOME")
+	var d Dirs
+	switch goos {
+	case "darwin":
+		support := filepath.Join(home, "Library", "Application Support")
+		d = Dirs{Config: support, Data: support}
+	case "windows":
+		if home = getenv("USERPROFILE"); home == "" {
+			home = getenv("HOME")
+		}
+		d = Dirs{
+			Config: firstAbs(getenv("APPDATA"), filepath.Join(home, "AppData", "Roaming")),
+			Data:   firstAbs(getenv("LOCALAPPDATA"), filepath.Join(home, "AppData", "Local")),
+		}
+	default:
+		d = Dirs{
+			Config: filepath.Join(home, ".config"),
+			Data:   filepath.Join(home, ".local", "share"),
+		}
+	}
+	return Dirs{
+		Config: filepath.Join(firstAbs(getenv("XDG_CONFIG_HOME"), d.Config), Name),
+		Data:   filepath.Join(firstAbs(getenv("XDG_DATA_HOME"), d.Data), Name),
+	}
+}
+
+// firstAbs returns path if it's absolute, or fallback otherwise.
+func firstAbs(path, fallback string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return fallback
+}
+
+// Portable returns the directories for a portable install, which keeps
+// everything in root.
+func Portable(root string) Dirs {
+	return Dirs{
+		Config: root,
+		Data:   filepath.Join(root, portableDataDirname),
+	}
+}
+
+// FlagValue returns the value of the long flag name (without its
+// dashes) in args, which may be passed as either "--name=value" or
+// "--name value".  Args after a "--" are not flags.
+func FlagValue(args []string, name string) (string, bool) {
+	flag := "--" + name
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return "", false
+		case arg == flag:
+			if i+1 < len(args) {
+				return args[i+1], true
+			}
+			return "", false
+		case strings.HasPrefix(arg, flag+"="):
+			return strings.TrimPrefix(arg, flag+"="), true
+		}
+	}
+	return "", false
+}
+
+// Migrate copies the files in from to to, if to doesn't exist yet (or
+// is empty) and from does.  It returns whether or not anything was
+// copied.  The files in from are left alone, so that older versions
+// of vidar still find them.
+func Migrate(from, to string) (bool, error) {
+	from, to = filepath.Clean(from), filepath.Clean(to)
+	if from == to || strings.HasPrefix(to, from+string(filepath.Separator)) {
+		return false, nil
+	}
+	if !empty(to) {
+		return false, nil
+	}
+	if empty(from) {
+		return false, nil
+	}
+	if err := copyDir(from, to); err != nil {
+		return false, fmt.Errorf("could not copy %s to %s: %s", from, to, err)
+	}
+	return true, nil
+}
+
+// empty returns whether or not dir is missing or has no entries.
+func empty(dir string) bool {
+	infos, err := ioutil.ReadDir(dir)
+	return err != nil || len(infos) == 0
+}
+
+func copyDir(from, to string) error {
+	return filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(to, rel)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(dest, 0700)
+		case info.Mode().IsRegular():
+			return copyFile(path, dest, info.Mode().Perm())
+		default:
+			// Sockets, symlinks, and the like can't be copied
+			// meaningfully.
+			return nil
+		}
+	})
+}
+
+func copyFile(from, to string, perm os.FileMode) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dest, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dest, src); err != nil {
+		dest.Close()
+		return err
+	}
+	return dest.Close()
+}
@@ -0,0 +1,176 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package dirs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nelsam/vidar/setting/dirs"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal        = matchers.Equal
+	not          = matchers.Not
+	haveOccurred = matchers.HaveOccurred
+	beTrue       = matchers.BeTrue
+	beFalse      = matchers.BeFalse
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func TestDefault(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it uses ~/.config and ~/.local/share on linux", func(expect expect.Expectation) {
+		d := dirs.Default("linux", env(map[string]string{"HOME": "/home/me"}))
+		expect(d).To(equal(dirs.Dirs{Config: "/home/me/.config/vidar", Data: "/home/me/.local/share/vidar"}))
+	})
+
+	o.Spec("it follows XDG variables", func(expect expect.Expectation) {
+		d := dirs.Default("linux", env(map[string]string{
+			"HOME":            "/home/me",
+			"XDG_CONFIG_HOME": "/xdg/config",
+			"XDG_DATA_HOME":   "/xdg/data",
+		}))
+		expect(d).To(equal(dirs.Dirs{Config: "/xdg/config/vidar", Data: "/xdg/data/vidar"}))
+	})
+
+	o.Spec("it ignores relative XDG variables", func(expect expect.Expectation) {
+		d := dirs.Default("linux", env(map[string]string{
+			"HOME":            "/home/me",
+			"XDG_CONFIG_HOME": "config",
+		}))
+		expect(d.Config).To(equal("/home/me/.config/vidar"))
+	})
+
+	o.Spec("it uses Application Support on darwin", func(expect expect.Expectation) {
+		d := dirs.Default("darwin", env(map[string]string{"HOME": "/Users/me"}))
+		support := "/Users/me/Library/Application Support/vidar"
+		expect(d).To(equal(dirs.Dirs{Config: support, Data: support}))
+	})
+
+	o.Spec("it uses APPDATA and LOCALAPPDATA on windows", func(expect expect.Expectation) {
+		d := dirs.Default("windows", env(map[string]string{
+			"APPDATA":      "/users/me/roaming",
+			"LOCALAPPDATA": "/users/me/local",
+		}))
+		expect(d).To(equal(dirs.Dirs{Config: "/users/me/roaming/vidar", Data: "/users/me/local/vidar"}))
+	})
+
+	o.Spec("it keeps portable data next to the config files", func(expect expect.Expectation) {
+		expect(dirs.Portable("/usb/vidar")).To(equal(dirs.Dirs{Config: "/usb/vidar", Data: "/usb/vidar/data"}))
+	})
+}
+
+func TestFlagValue(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it reads separate values", func(expect expect.Expectation) {
+		v, ok := dirs.FlagValue([]string{"main.go", "--config-dir", "/cfg"}, "config-dir")
+		expect(ok).To(beTrue())
+		expect(v).To(equal("/cfg"))
+	})
+
+	o.Spec("it reads values after an equals sign", func(expect expect.Expectation) {
+		v, ok := dirs.FlagValue([]string{"--config-dir=/cfg", "main.go"}, "config-dir")
+		expect(ok).To(beTrue())
+		expect(v).To(equal("/cfg"))
+	})
+
+	o.Spec("it stops at a double dash", func(expect expect.Expectation) {
+		_, ok := dirs.FlagValue([]string{"--", "--config-dir=/cfg"}, "config-dir")
+		expect(ok).To(beFalse())
+	})
+
+	o.Spec("it requires a value", func(expect expect.Expectation) {
+		_, ok := dirs.FlagValue([]string{"--config-dir"}, "config-dir")
+		expect(ok).To(beFalse())
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, string) {
+		dir, err := ioutil.TempDir("", "vidar-dirs")
+		if err != nil {
+			t.Fatalf("Could not create temp dir: %s", err)
+		}
+		old := filepath.Join(dir, "old")
+		if err := os.MkdirAll(filepath.Join(old, "themes"), 0700); err != nil {
+			t.Fatalf("Could not create old dir: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(old, "settings.toml"), []byte("minimap = true\n"), 0600); err != nil {
+			t.Fatalf("Could not write settings: %s", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(old, "themes", "dark.toml"), []byte("\n"), 0600); err != nil {
+			t.Fatalf("Could not write theme: %s", err)
+		}
+		return expect.New(t), dir
+	})
+
+	o.AfterEach(func(expect expect.Expectation, dir string) {
+		os.RemoveAll(dir)
+	})
+
+	o.Spec("it copies files to a new directory", func(expect expect.Expectation, dir string) {
+		to := filepath.Join(dir, "new")
+		copied, err := dirs.Migrate(filepath.Join(dir, "old"), to)
+		expect(err).To(not(haveOccurred()))
+		expect(copied).To(beTrue())
+		b, err := ioutil.ReadFile(filepath.Join(to, "settings.toml"))
+		expect(err).To(not(haveOccurred()))
+		expect(string(b)).To(equal("minimap = true\n"))
+		_, err = os.Stat(filepath.Join(to, "themes", "dark.toml"))
+		expect(err).To(not(haveOccurred()))
+		_, err = os.Stat(filepath.Join(dir, "old", "settings.toml"))
+		expect(err).To(not(haveOccurred()))
+	})
+
+	o.Spec("it leaves existing directories alone", func(expect expect.Expectation, dir string) {
+		to := filepath.Join(dir, "new")
+		expect(os.MkdirAll(to, 0700)).To(not(haveOccurred()))
+		expect(ioutil.WriteFile(filepath.Join(to, "keys.toml"), []byte("\n"), 0600)).To(not(haveOccurred()))
+		copied, err := dirs.Migrate(filepath.Join(dir, "old"), to)
+		expect(err).To(not(haveOccurred()))
+		expect(copied).To(beFalse())
+		_, err = os.Stat(filepath.Join(to, "settings.toml"))
+		expect(os.IsNotExist(err)).To(beTrue())
+	})
+
+	o.Spec("it does nothing without old files", func(expect expect.Expectation, dir string) {
+		copied, err := dirs.Migrate(filepath.Join(dir, "missing"), filepath.Join(dir, "new"))
+		expect(err).To(not(haveOccurred()))
+		expect(copied).To(beFalse())
+	})
+
+	o.Spec("it does not copy a directory into itself", func(expect expect.Expectation, dir string) {
+		old := filepath.Join(dir, "old")
+		copied, err := dirs.Migrate(old, filepath.Join(old, "data"))
+		expect(err).To(not(haveOccurred()))
+		expect(copied).To(beFalse())
+	})
+}
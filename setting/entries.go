@@ -0,0 +1,532 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nelsam/vidar/setting/config"
+	"github.com/nelsam/vidar/theme"
+)
+
+// The sections that Entries are grouped in.
+const (
+	GeneralSection   = "General"
+	FontsSection     = "Fonts"
+	BindingsSection  = "Key bindings"
+	ProjectsSection  = "Projects"
+	FileTypesSection = "File types"
+)
+
+// An Entry is a single setting that can be read and changed while
+// vidar is running, without editing the config files by hand.
+type Entry struct {
+	// Section is the group that the entry belongs in, e.g.
+	// FontsSection.
+	Section string
+
+	// Key is the name of the entry within its section.
+	Key string
+
+	// Help describes the format of the entry's value.
+	Help string
+
+	// Restart is set for entries that are only read on startup.
+	Restart bool
+
+	get func() string
+	set func(string) error
+}
+
+// Value returns the entry's current value as text.
+func (e Entry) Value() string {
+	return e.get()
+}
+
+// Set parses value and writes it to the config files.  If value is
+// not valid for the entry, an error is returned and nothing is
+// changed.
+func (e Entry) Set(value string) error {
+	return e.set(strings.TrimSpace(value))
+}
+
+// Entries returns every entry that can be changed, in the order of
+// their sections.  commands are the names of the commands that can
+// be bound to keys.
+func Entries(commands []string) []Entry {
+	var entries []Entry
+	entries = append(entries, generalEntries()...)
+	entries = append(entries, Entry{
+		Section: FontsSection,
+		Key:     "fonts",
+		Help:    "comma separated list of \"name size\", most preferred first",
+		get:     fontsValue,
+		set:     setFonts,
+	})
+	entries = append(entries, bindingEntries(commands)...)
+	entries = append(entries, projectEntries()...)
+	entries = append(entries, indentEntries()...)
+	return entries
+}
+
+func generalEntries() []Entry {
+	return []Entry{
+		boolEntry(lineNumbersKey, LineNumbers),
+		boolEntry(minimapKey, Minimap),
+		boolEntry(firstHunkKey, JumpToFirstHunk),
+		boolEntry(smoothScrollKey, SmoothScroll),
+		boolEntry(pastEndKey, ScrollPastEnd),
+		boolEntry(breadcrumbsKey, Breadcrumbs),
+		boolEntry(occurrencesKey, HighlightOccurrences),
+		boolEntry(currentLineKey, HighlightCurrentLine),
+		{
+			Section: GeneralSection,
+			Key:     caretStyleKey,
+			Help:    "one of: bar, block, underline",
+			get:     func() string { return string(Caret()) },
+			set:     setCaretStyle,
+		},
+		{
+			Section: GeneralSection,
+			Key:     caretBlinkKey,
+			Help:    "a duration, e.g. 500ms, or 0 to stop blinking",
+			get:     func() string { return CaretBlink().String() },
+			set:     setCaretBlink,
+		},
+		{
+			Section: GeneralSection,
+			Key:     modalKey,
+			Help:    "true or false",
+			Restart: true,
+			get:     func() string { return strconv.FormatBool(Modal()) },
+			set:     boolSetter(modalKey),
+		},
+		{
+			Section: GeneralSection,
+			Key:     themeKey,
+			Help:    "one of: " + strings.Join(theme.Names(ThemesDir()), ", "),
+			get:     Theme,
+			set:     setTheme,
+		},
+		{
+			Section: GeneralSection,
+			Key:     fontSizeKey,
+			Help:    "a size in points, or 0 to use the sizes in fonts",
+			get:     func() string { return strconv.Itoa(FontSize()) },
+			set:     setFontSize,
+		},
+		{
+			Section: GeneralSection,
+			Key:     zenWidthKey,
+			Help:    "a number of columns, e.g. 100",
+			get:     func() string { return strconv.Itoa(ZenWidth()) },
+			set:     setZenWidth,
+		},
+		{
+			Section: GeneralSection,
+			Key:     pollIntervalKey,
+			Help:    "a duration, e.g. 1s",
+			Restart: true,
+			get:     func() string { return PollInterval().String() },
+			set: func(v string) error {
+				if _, err := parsePositiveDuration(v); err != nil {
+					return err
+				}
+				return save(settings, pollIntervalKey, v)
+			},
+		},
+		autoSaveEntry("enabled", func(a AutoSave) string { return strconv.FormatBool(a.Enabled) }, func(a *AutoSave, v string) error {
+			b, err := strconv.ParseBool(v)
+			a.Enabled = b
+			return err
+		}),
+		autoSaveEntry("delay", func(a AutoSave) string { return a.DelayDuration().String() }, func(a *AutoSave, v string) error {
+			_, err := parsePositiveDuration(v)
+			a.Delay = v
+			return err
+		}),
+		autoSaveEntry("onfocuslost", func(a AutoSave) string { return strconv.FormatBool(a.OnFocusLost) }, func(a *AutoSave, v string) error {
+			b, err := strconv.ParseBool(v)
+			a.OnFocusLost = b
+			return err
+		}),
+		structTagsEntry("keys", "comma separated list of tag keys, e.g. json, yaml", func(t StructTags) string { return strings.Join(t.Keys, ", ") }, func(t *StructTags, v string) error {
+			t.Keys = nil
+			for _, k := range strings.Split(v, ",") {
+				if k = strings.TrimSpace(k); k != "" {
+					t.Keys = append(t.Keys, k)
+				}
+			}
+			if len(t.Keys) == 0 {
+				return errors.New("at least one key is needed")
+			}
+			return nil
+		}),
+		structTagsEntry("case", "snake or camel", func(t StructTags) string { return t.Case }, func(t *StructTags, v string) error {
+			if v != "snake" && v != "camel" {
+				return errors.New("the case must be snake or camel")
+			}
+			t.Case = v
+			return nil
+		}),
+		structTagsEntry("omitempty", "true or false", func(t StructTags) string { return strconv.FormatBool(t.OmitEmpty) }, func(t *StructTags, v string) error {
+			b, err := strconv.ParseBool(v)
+			t.OmitEmpty = b
+			return err
+		}),
+		commitGuidesEntry(),
+		todoMarkersEntry(),
+	}
+}
+
+func boolEntry(key string, get func() bool) Entry {
+	return Entry{
+		Section: GeneralSection,
+		Key:     key,
+		Help:    "true or false",
+		get:     func() string { return strconv.FormatBool(get()) },
+		set:     boolSetter(key),
+	}
+}
+
+func boolSetter(key string) func(string) error {
+	return func(v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", v)
+		}
+		return save(settings, key, b)
+	}
+}
+
+func autoSaveEntry(field string, get func(AutoSave) string, set func(*AutoSave, string) error) Entry {
+	return Entry{
+		Section: GeneralSection,
+		Key:     autoSaveKey + "." + field,
+		get:     func() string { return get(AutoSaveConfig()) },
+		set: func(v string) error {
+			a := AutoSaveConfig()
+			if err := set(&a, v); err != nil {
+				return fmt.Errorf("%q is not valid for %s: %s", v, field, err)
+			}
+			return save(settings, autoSaveKey, a)
+		},
+	}
+}
+
+func structTagsEntry(field, help string, get func(StructTags) string, set func(*StructTags, string) error) Entry {
+	return Entry{
+		Section: GeneralSection,
+		Key:     structTagsKey + "." + field,
+		Help:    help,
+		get:     func() string { return get(StructTagsConfig()) },
+		set: func(v string) error {
+			t := StructTagsConfig()
+			if err := set(&t, v); err != nil {
+				return fmt.Errorf("%q is not valid for %s: %s", v, field, err)
+			}
+			return save(settings, structTagsKey, t)
+		},
+	}
+}
+
+func commitGuidesEntry() Entry {
+	return Entry{
+		Section: GeneralSection,
+		Key:     commitKey + ".guides",
+		Help:    "comma separated list of columns, e.g. 50, 72",
+		get: func() string {
+			var cols []string
+			for _, g := range CommitConfig().Guides {
+				cols = append(cols, strconv.Itoa(g))
+			}
+			return strings.Join(cols, ", ")
+		},
+		set: func(v string) error {
+			c := CommitConfig()
+			c.Guides = []int{}
+			for _, col := range strings.Split(v, ",") {
+				if col = strings.TrimSpace(col); col == "" {
+					continue
+				}
+				g, err := strconv.Atoi(col)
+				if err != nil || g <= 0 {
+					return fmt.Errorf("%q is not valid for guides: %q is not a positive column", v, col)
+				}
+				c.Guides = append(c.Guides, g)
+			}
+			return save(settings, commitKey, c)
+		},
+	}
+}
+
+func todoMarkersEntry() Entry {
+	return Entry{
+		Section: GeneralSection,
+		Key:     todoKey + ".markers",
+		Help:    "comma separated list of markers, e.g. TODO, FIXME",
+		get:     func() string { return strings.Join(TodoConfig().Markers, ", ") },
+		set: func(v string) error {
+			t := TodoConfig()
+			t.Markers = nil
+			for _, m := range strings.Split(v, ",") {
+				if m = strings.TrimSpace(m); m != "" {
+					t.Markers = append(t.Markers, m)
+				}
+			}
+			if len(t.Markers) == 0 {
+				return errors.New("at least one marker is needed")
+			}
+			return save(settings, todoKey, t)
+		},
+	}
+}
+
+func parsePositiveDuration(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, errors.New("durations must be longer than 0")
+	}
+	return d, nil
+}
+
+func setTheme(v string) error {
+	for _, name := range theme.Names(ThemesDir()) {
+		if name == v {
+			return save(settings, themeKey, v)
+		}
+	}
+	return fmt.Errorf("there is no theme named %q in %s", v, ThemesDir())
+}
+
+func setFontSize(v string) error {
+	size, err := strconv.Atoi(v)
+	if err != nil || size < 0 {
+		return fmt.Errorf("%q is not a font size", v)
+	}
+	return save(settings, fontSizeKey, size)
+}
+
+func setZenWidth(v string) error {
+	width, err := strconv.Atoi(v)
+	if err != nil || width <= 0 {
+		return fmt.Errorf("%q is not a number of columns", v)
+	}
+	return save(settings, zenWidthKey, width)
+}
+
+func fontsValue() string {
+	fonts, _ := settings.Get("fonts").([]Font)
+	values := make([]string, 0, len(fonts))
+	for _, f := range fonts {
+		values = append(values, fmt.Sprintf("%s %d", f.Name, f.Size))
+	}
+	return strings.Join(values, ", ")
+}
+
+func setFonts(v string) error {
+	var fonts []Font
+	for _, item := range splitList(v) {
+		fields := strings.Fields(item)
+		if len(fields) < 2 {
+			return fmt.Errorf("%q needs a font name and a size", item)
+		}
+		size, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil || size <= 0 {
+			return fmt.Errorf("%q does not end with a font size", item)
+		}
+		name := strings.Join(fields[:len(fields)-1], " ")
+		r, err := loadFont(name)
+		if err != nil {
+			return fmt.Errorf("could not load font %s: %s", name, err)
+		}
+		if c, ok := r.(io.Closer); ok {
+			c.Close()
+		}
+		fonts = append(fonts, Font{Name: name, Size: size})
+	}
+	return save(settings, "fonts", fonts)
+}
+
+// bindingEntries returns an entry for the key bindings of each
+// command in commands.
+func bindingEntries(commands []string) []Entry {
+	sorted := append([]string(nil), commands...)
+	sort.Strings(sorted)
+	entries := make([]Entry, 0, len(sorted))
+	for _, name := range sorted {
+		name := name
+		entries = append(entries, Entry{
+			Section: BindingsSection,
+			Key:     name,
+			Help:    "comma separated list of keys, e.g. ctrl-s or ctrl-k ctrl-c",
+			get:     func() string { return strings.Join(patterns(name), ", ") },
+			set:     func(v string) error { return SetBindings(name, splitList(v)...) },
+		})
+	}
+	return entries
+}
+
+// patterns returns the key patterns in the key bindings file that
+// are bound to commandName, sorted.
+func patterns(commandName string) []string {
+	var bound []string
+	for _, pattern := range bindings.Keys() {
+		if bindings.Get(pattern) == commandName {
+			bound = append(bound, pattern)
+		}
+	}
+	sort.Strings(bound)
+	return bound
+}
+
+// SetBindings replaces the key bindings of the command named
+// commandName with patterns, in the format of the key bindings file.
+// Patterns that were bound to other commands are taken from them.
+func SetBindings(commandName string, patterns ...string) error {
+	keep := make(map[string]bool, len(patterns))
+	for _, p := range patterns {
+		if len(parseChord(p)) == 0 {
+			return fmt.Errorf("%q is not a valid key binding", p)
+		}
+		keep[strings.ToLower(p)] = true
+	}
+	for _, pattern := range bindings.Keys() {
+		if bindings.Get(pattern) == commandName && !keep[pattern] {
+			// Removing the pattern would let the command's
+			// defaults add it right back, so it's unbound
+			// instead.
+			bindings.Set(pattern, "")
+		}
+	}
+	for p := range keep {
+		bindings.Set(p, commandName)
+	}
+	return bindings.Write()
+}
+
+func projectEntries() []Entry {
+	projs := Projects()
+	entries := make([]Entry, 0, len(projs))
+	for _, p := range projs {
+		name := p.Name
+		entries = append(entries, Entry{
+			Section: ProjectsSection,
+			Key:     name,
+			Help:    "the project's root directory",
+			get: func() string {
+				for _, p := range Projects() {
+					if p.Name == name {
+						return p.Path
+					}
+				}
+				return ""
+			},
+			set: func(v string) error { return setProjectPath(name, v) },
+		})
+	}
+	return entries
+}
+
+func setProjectPath(name, path string) error {
+	finfo, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !finfo.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+	projs := Projects()
+	for i, p := range projs {
+		if p.Name == name {
+			projs[i].Path = path
+			return save(projects, "projects", projs)
+		}
+	}
+	return fmt.Errorf("there is no project named %s", name)
+}
+
+// indentEntries returns an entry for the indentation of each file
+// type that has default or configured indent settings.
+func indentEntries() []Entry {
+	indents, _ := settings.Get(indentKey).(map[string]Indent)
+	var exts []string
+	for ext := range defaultIndents {
+		exts = append(exts, ext)
+	}
+	for ext := range indents {
+		if _, ok := defaultIndents[ext]; !ok {
+			exts = append(exts, ext)
+		}
+	}
+	sort.Strings(exts)
+	entries := make([]Entry, 0, len(exts))
+	for _, ext := range exts {
+		ext := ext
+		entries = append(entries, Entry{
+			Section: FileTypesSection,
+			Key:     ext + " indent",
+			Help:    "tabs or spaces, then a width, e.g. spaces 2",
+			get: func() string {
+				i := IndentFor("." + ext)
+				style := "tabs"
+				if i.Spaces {
+					style = "spaces"
+				}
+				return fmt.Sprintf("%s %d", style, i.Width)
+			},
+			set: func(v string) error { return setIndent(ext, v) },
+		})
+	}
+	return entries
+}
+
+func setIndent(ext, v string) error {
+	fields := strings.Fields(v)
+	if len(fields) != 2 || (fields[0] != "tabs" && fields[0] != "spaces") {
+		return fmt.Errorf("%q is not tabs or spaces followed by a width", v)
+	}
+	width, err := strconv.Atoi(fields[1])
+	if err != nil || width <= 0 {
+		return fmt.Errorf("%q is not a valid width", fields[1])
+	}
+	old, _ := settings.Get(indentKey).(map[string]Indent)
+	indents := make(map[string]Indent, len(old)+1)
+	for k, i := range old {
+		indents[k] = i
+	}
+	indents[ext] = Indent{Spaces: fields[0] == "spaces", Width: width}
+	return save(settings, indentKey, indents)
+}
+
+// splitList splits a comma separated list, dropping empty items.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// save sets key to v in c and writes c to its file.
+func save(c *config.Config, key string, v interface{}) error {
+	c.Set(key, v)
+	if err := c.Write(); err != nil {
+		return fmt.Errorf("could not write settings: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import "path/filepath"
+
+const (
+	historyDirname = "history"
+	historyKey     = "history"
+
+	// DefaultHistoryMaxEdits is the number of edits that will be
+	// saved for each file if no limit is found in the config files.
+	DefaultHistoryMaxEdits = 1000
+)
+
+// HistoryDir is the directory that undo history is saved to.
+var HistoryDir = filepath.Join(DataDir(), historyDirname)
+
+// History is the configuration for undo history.
+type History struct {
+	// Persist turns on saving undo history when files are closed
+	// or saved, so that it can be restored when they are opened
+	// again.
+	Persist bool
+
+	// MaxEdits is the maximum number of edits to save for each
+	// file.  When a file's history has more edits than this, only
+	// the most recent edits leading up to the current state are
+	// saved.
+	MaxEdits int
+}
+
+// HistoryConfig returns the current undo history settings.
+func HistoryConfig() History {
+	h, ok := settings.Get(historyKey).(History)
+	if !ok {
+		return History{Persist: true, MaxEdits: DefaultHistoryMaxEdits}
+	}
+	if h.MaxEdits <= 0 {
+		h.MaxEdits = DefaultHistoryMaxEdits
+	}
+	return h
+}
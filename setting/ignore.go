@@ -0,0 +1,112 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/nelsam/vidar/setting/ignore"
+)
+
+// DefaultIgnore is the global list of ignore patterns that is used
+// when the settings file doesn't have one.
+var DefaultIgnore = []string{"node_modules/", "vendor/"}
+
+var (
+	ignoreMu    sync.Mutex
+	ignoreCache = make(map[string]compiledIgnore)
+)
+
+// compiledIgnore is the compiled ignore patterns for a project, along
+// with the lines they were compiled from so that changes to the
+// settings can be noticed.
+type compiledIgnore struct {
+	lines    []string
+	patterns *ignore.Patterns
+}
+
+// Ignore returns the global ignore patterns, which apply to every
+// project.  They use the same syntax as .gitignore files.
+func Ignore() []string {
+	patterns, ok := settings.Get(ignoreKey).([]string)
+	if !ok {
+		return DefaultIgnore
+	}
+	return patterns
+}
+
+// Ignored returns whether path should be left out of the project at
+// root: its tree, searches, indexes, and filesystem watches.  The
+// global ignore patterns are checked first, followed by the
+// project's, so a project can re-include paths with a "!" pattern.
+//
+// If root is empty, only the global patterns are checked, against
+// path's name.
+func Ignored(root, path string, isDir bool) bool {
+	if root == "" {
+		return ignorePatterns("").Match(filepath.Base(path), isDir)
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	return ignorePatterns(root).Match(filepath.ToSlash(rel), isDir)
+}
+
+// IgnoredDir returns whether or not the directory at dir should be
+// left out of the project at root.
+func IgnoredDir(root, dir string) bool {
+	if root == "" {
+		return false
+	}
+	return Ignored(root, dir, true)
+}
+
+// ProjectRoot returns the path of the project that contains path, or
+// an empty string if it isn't in any project.  If projects are nested,
+// the innermost one is returned.
+func ProjectRoot(path string) string {
+	root := ""
+	for _, p := range Projects() {
+		rel, err := filepath.Rel(p.Path, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if len(p.Path) > len(root) {
+			root = p.Path
+		}
+	}
+	return root
+}
+
+// ignorePatterns returns the compiled ignore patterns for the project
+// at root, compiling them again if the settings have changed.
+func ignorePatterns(root string) *ignore.Patterns {
+	lines := append([]string(nil), Ignore()...)
+	lines = append(lines, LoadProjectSettings(root).Ignore...)
+
+	ignoreMu.Lock()
+	defer ignoreMu.Unlock()
+	if c, ok := ignoreCache[root]; ok && sameLines(c.lines, lines) {
+		return c.patterns
+	}
+	p := ignore.New(lines)
+	ignoreCache[root] = compiledIgnore{lines: lines, patterns: p}
+	return p
+}
+
+func sameLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package ignore matches paths against gitignore-style patterns.
+package ignore
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pattern is a single compiled line of a pattern list.
+type pattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// Patterns is a compiled list of gitignore-style patterns.  The zero
+// value matches nothing.
+type Patterns struct {
+	patterns []pattern
+}
+
+// New compiles lines, which use the same syntax as .gitignore files:
+//
+//   - Blank lines and lines starting with # are skipped.
+//   - A leading ! re-includes paths that earlier patterns excluded.
+//   - A trailing / only matches directories.
+//   - Patterns without a / in them (other than a trailing one) match
+//     names at any depth; other patterns are relative to the root.
+//   - * and ? match within a single path element, and ** matches any
+//     number of elements.
+//
+// Lines that can't be compiled are skipped.
+func New(lines []string) *Patterns {
+	p := &Patterns{}
+	for _, l := range lines {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		var pat pattern
+		if strings.HasPrefix(l, "!") {
+			pat.negate = true
+			l = l[1:]
+		}
+		if strings.HasSuffix(l, "/") {
+			pat.dirOnly = true
+			l = strings.TrimRight(l, "/")
+		}
+		anchored := strings.Contains(l, "/")
+		l = strings.TrimPrefix(l, "/")
+		if l == "" {
+			continue
+		}
+		expr := globExpr(l)
+		if !anchored {
+			expr = "(.*/)?" + expr
+		}
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			continue
+		}
+		pat.re = re
+		p.patterns = append(p.patterns, pat)
+	}
+	return p
+}
+
+// Match returns whether the path rel, which is slash-separated and
+// relative to the root, is ignored.  isDir tells Match whether rel is
+// a directory.  Paths inside of an ignored directory are ignored too.
+func (p *Patterns) Match(rel string, isDir bool) bool {
+	if p == nil || len(p.patterns) == 0 {
+		return false
+	}
+	rel = strings.Trim(rel, "/")
+	parts := strings.Split(rel, "/")
+	for i := 1; i < len(parts); i++ {
+		if p.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return p.match(rel, isDir)
+}
+
+// match returns whether rel is matched by p, ignoring its parents.
+// Later patterns override earlier ones.
+func (p *Patterns) match(rel string, isDir bool) bool {
+	ignored := false
+	for _, pat := range p.patterns {
+		if pat.dirOnly && !isDir {
+			continue
+		}
+		if pat.re.MatchString(rel) {
+			ignored = !pat.negate
+		}
+	}
+	return ignored
+}
+
+// globExpr converts a glob to a regular expression.
+func globExpr(glob string) string {
+	var expr strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					// "**/" matches zero or more
+					// directories.
+					i++
+					expr.WriteString("(.*/)?")
+					continue
+				}
+				expr.WriteString(".*")
+				continue
+			}
+			expr.WriteString("[^/]*")
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end == -1 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				expr.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return expr.String()
+}
@@ -0,0 +1,85 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package ignore_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/setting/ignore"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+type Expectation = expect.Expectation
+
+var (
+	BeTrue  = matchers.BeTrue
+	BeFalse = matchers.BeFalse
+)
+
+func TestPatterns(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it matches nothing without patterns", func(expect Expectation) {
+		expect(ignore.New(nil).Match("foo", true)).To(BeFalse())
+	})
+
+	o.Spec("it matches names at any depth", func(expect Expectation) {
+		p := ignore.New([]string{"node_modules", "*.log"})
+		expect(p.Match("node_modules", true)).To(BeTrue())
+		expect(p.Match("web/node_modules", true)).To(BeTrue())
+		expect(p.Match("a/b/debug.log", false)).To(BeTrue())
+		expect(p.Match("a/b/debug.go", false)).To(BeFalse())
+	})
+
+	o.Spec("it only matches directories with a trailing slash", func(expect Expectation) {
+		p := ignore.New([]string{"build/"})
+		expect(p.Match("build", true)).To(BeTrue())
+		expect(p.Match("build", false)).To(BeFalse())
+	})
+
+	o.Spec("it matches paths inside of ignored directories", func(expect Expectation) {
+		p := ignore.New([]string{"vendor/"})
+		expect(p.Match("vendor/github.com/foo/bar.go", false)).To(BeTrue())
+		expect(p.Match("vendored.go", false)).To(BeFalse())
+	})
+
+	o.Spec("it anchors patterns that contain a slash", func(expect Expectation) {
+		p := ignore.New([]string{"/gen", "docs/*.html"})
+		expect(p.Match("gen", true)).To(BeTrue())
+		expect(p.Match("pkg/gen", true)).To(BeFalse())
+		expect(p.Match("docs/index.html", false)).To(BeTrue())
+		expect(p.Match("docs/api/index.html", false)).To(BeFalse())
+		expect(p.Match("site/docs/index.html", false)).To(BeFalse())
+	})
+
+	o.Spec("it matches any number of directories with **", func(expect Expectation) {
+		p := ignore.New([]string{"**/testdata/*.golden", "out/**"})
+		expect(p.Match("testdata/a.golden", false)).To(BeTrue())
+		expect(p.Match("pkg/x/testdata/a.golden", false)).To(BeTrue())
+		expect(p.Match("out/a/b/c", false)).To(BeTrue())
+		expect(p.Match("out", true)).To(BeFalse())
+	})
+
+	o.Spec("it lets later patterns re-include paths", func(expect Expectation) {
+		p := ignore.New([]string{"# generated files", "", "*.pb.go", "!keep.pb.go"})
+		expect(p.Match("api/foo.pb.go", false)).To(BeTrue())
+		expect(p.Match("api/keep.pb.go", false)).To(BeFalse())
+	})
+
+	o.Spec("it supports character classes", func(expect Expectation) {
+		p := ignore.New([]string{"*.[oa]", "tmp[!x]"})
+		expect(p.Match("lib.a", false)).To(BeTrue())
+		expect(p.Match("lib.so", false)).To(BeFalse())
+		expect(p.Match("tmp1", false)).To(BeTrue())
+		expect(p.Match("tmpx", false)).To(BeFalse())
+	})
+}
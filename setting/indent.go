@@ -0,0 +1,63 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const (
+	indentKey = "indent"
+
+	// DefaultIndentWidth is the width of each level of indentation
+	// for file types that aren't found in the config files.
+	DefaultIndentWidth = 4
+)
+
+// defaultIndents are the indent settings for file types whose
+// conventions don't match the default of tabs.
+var defaultIndents = map[string]Indent{
+	"py":   {Spaces: true, Width: 4},
+	"yaml": {Spaces: true, Width: 2},
+	"yml":  {Spaces: true, Width: 2},
+	"json": {Spaces: true, Width: 2},
+	"md":   {Spaces: true, Width: 4},
+}
+
+// Indent is the indentation settings for a type of file.
+type Indent struct {
+	// Spaces turns on indenting with spaces instead of tabs.
+	Spaces bool
+
+	// Width is the number of columns in each level of
+	// indentation.  It's also the width that tabs are displayed
+	// with.
+	Width int
+}
+
+// Level returns the text for one level of indentation.
+func (i Indent) Level() string {
+	if !i.Spaces {
+		return "\t"
+	}
+	return strings.Repeat(" ", i.Width)
+}
+
+// IndentFor returns the indent settings for the file at path.  The
+// settings are looked up by the file's extension (e.g. `go` or
+// `yaml`) in the indent table in the settings file.
+func IndentFor(path string) Indent {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	indents, _ := settings.Get(indentKey).(map[string]Indent)
+	i, ok := indents[ext]
+	if !ok {
+		i = defaultIndents[ext]
+	}
+	if i.Width <= 0 {
+		i.Width = DefaultIndentWidth
+	}
+	return i
+}
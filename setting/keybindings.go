@@ -5,9 +5,11 @@
 package setting
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/nelsam/gxui"
@@ -15,7 +17,12 @@ import (
 	"github.com/nelsam/vidar/setting/config"
 )
 
-const keysFilename = "keys"
+const (
+	keysFilename = "keys"
+
+	// maxChordLen is the maximum number of key events in a Chord.
+	maxChordLen = 2
+)
 
 var bindings *config.Config
 
@@ -37,13 +44,62 @@ func (o opener) Create(path string) (io.WriteCloser, error) {
 	return os.Create(path)
 }
 
-func Bindings(commandName string) (events []gxui.KeyboardEvent) {
-	for _, event := range bindings.Keys() {
-		if bindings.Get(event) == commandName {
-			events = append(events, parseBinding(event)...)
+// A Chord is a sequence of key events that are pressed one after
+// another to run a command, e.g. ctrl-k followed by ctrl-c.  Most
+// bindings are chords of a single event.
+type Chord []gxui.KeyboardEvent
+
+// String returns c in the format that it is written in the key
+// bindings file: each event, separated by spaces.
+func (c Chord) String() string {
+	strokes := make([]string, 0, len(c))
+	for _, e := range c {
+		strokes = append(strokes, e.String())
+	}
+	return strings.Join(strokes, " ")
+}
+
+// Bindings returns the chords that the command named commandName is
+// bound to.
+func Bindings(commandName string) (chords []Chord) {
+	for _, pattern := range bindings.Keys() {
+		if bindings.Get(pattern) == commandName {
+			chords = append(chords, parseChord(pattern)...)
 		}
 	}
-	return events
+	return chords
+}
+
+// BoundCommand returns the name of the command that pattern is bound
+// to in the key bindings file, or "" if it isn't bound.
+func BoundCommand(pattern string) string {
+	name, _ := bindings.Get(strings.ToLower(pattern)).(string)
+	return name
+}
+
+// parseChord parses a space separated list of key events.  Every
+// combination of the events that each stroke parses to is returned.
+func parseChord(pattern string) []Chord {
+	strokes := strings.Fields(pattern)
+	if len(strokes) == 0 || len(strokes) > maxChordLen {
+		log.Printf("Error parsing key bindings: %s: chords must have between 1 and %d keys", pattern, maxChordLen)
+		return nil
+	}
+	chords := []Chord{nil}
+	for _, stroke := range strokes {
+		events := parseBinding(stroke)
+		if len(events) == 0 {
+			return nil
+		}
+		next := make([]Chord, 0, len(chords)*len(events))
+		for _, c := range chords {
+			for _, e := range events {
+				next = append(next, append(append(Chord(nil), c...), e))
+			}
+		}
+		chords = next
+	}
+	return chords
 }
 
 func parseBinding(eventPattern string) []gxui.KeyboardEvent {
@@ -86,6 +142,18 @@ func parseBinding(eventPattern string) []gxui.KeyboardEvent {
 	return nil
 }
 
+// A Conflict is a key binding that more than one command is bound
+// to.  A single key also conflicts with any chord that starts with
+// it, since the chord could never be completed.
+type Conflict struct {
+	Binding  string
+	Commands []string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s is bound to %s", c.Binding, strings.Join(c.Commands, " and "))
+}
+
 func SetDefaultBindings(cmds ...bind.Command) {
 	for _, c := range cmds {
 		defaults := c.Defaults()
@@ -95,3 +163,85 @@ func SetDefaultBindings(cmds ...bind.Command) {
 	}
 	bindings.Write()
 }
+
+// Conflicts returns the key bindings that are bound to more than one
+// of cmds, either in the key bindings file or in their defaults.
+// Only one command can take a default binding, so the others will
+// be missing it.  Bindings for commands that aren't in cmds are
+// ignored.
+func Conflicts(cmds ...bind.Command) []Conflict {
+	names := make(map[string]bool, len(cmds))
+	defaults := make(map[string][]string)
+	var defaultOrder []string
+	for _, c := range cmds {
+		names[c.Name()] = true
+		for _, d := range c.Defaults() {
+			key := strings.ToLower(d.String())
+			if _, ok := defaults[key]; !ok {
+				defaultOrder = append(defaultOrder, key)
+			}
+			defaults[key] = appendUnique(defaults[key], c.Name())
+		}
+	}
+	var conflicts []Conflict
+	for _, key := range defaultOrder {
+		if len(defaults[key]) > 1 {
+			conflicts = append(conflicts, Conflict{Binding: key, Commands: defaults[key]})
+		}
+	}
+
+	bound := make(map[string][]string)
+	prefixes := make(map[string][]string)
+	patterns := bindings.Keys()
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		name, _ := bindings.Get(pattern).(string)
+		if !names[name] {
+			continue
+		}
+		for _, chord := range parseChord(pattern) {
+			if chord.mirrored() {
+				continue
+			}
+			bound[chord.String()] = appendUnique(bound[chord.String()], name)
+			if len(chord) > 1 {
+				first := chord[:1].String()
+				prefixes[first] = appendUnique(prefixes[first], name)
+			}
+		}
+	}
+	var fileConflicts []Conflict
+	for binding, cmdNames := range bound {
+		for _, name := range prefixes[binding] {
+			cmdNames = appendUnique(cmdNames, name)
+		}
+		if len(cmdNames) > 1 {
+			fileConflicts = append(fileConflicts, Conflict{Binding: binding, Commands: cmdNames})
+		}
+	}
+	sort.Slice(fileConflicts, func(i, j int) bool {
+		return fileConflicts[i].Binding < fileConflicts[j].Binding
+	})
+	return append(conflicts, fileConflicts...)
+}
+
+// mirrored returns whether or not c uses the super modifier.  Those
+// chords are only created to mirror chords that use ctrl, so they
+// would always conflict when their ctrl chords conflict.
+func (c Chord) mirrored() bool {
+	for _, e := range c {
+		if e.Modifier&gxui.ModSuper != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(l []string, v string) []string {
+	for _, s := range l {
+		if s == v {
+			return l
+		}
+	}
+	return append(l, v)
+}
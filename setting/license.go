@@ -0,0 +1,86 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LicenseHeaderFilename is the file name to look for in projects.
+//
+// TODO: this belongs in the license header plugin, since not all
+// languages need it.
+const LicenseHeaderFilename = ".license-header"
+
+// License is the configuration for a project's license header.  The
+// header template may use the variables {{year}}, {{author}}, and
+// {{filename}}, which are replaced when the header is written to a
+// file.
+type License struct {
+	// Author replaces {{author}} in the template.
+	Author string
+
+	// Template is the path of the header template, relative to the
+	// project root.  It defaults to LicenseHeaderFilename.
+	Template string
+}
+
+// LicenseHeader returns p's license header template, without any of
+// its variables replaced.
+func (p Project) LicenseHeader() string {
+	name := p.Settings().License.Template
+	if name == "" {
+		name = LicenseHeaderFilename
+	}
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(p.Path, name)
+	}
+	f, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		log.Printf("Error opening license header file: %s", err)
+		return ""
+	}
+	defer f.Close()
+	header, err := ioutil.ReadAll(f)
+	if err != nil {
+		log.Printf("Error reading license header file: %s", err)
+		return ""
+	}
+	return string(header)
+}
+
+// LicenseHeaderFor returns p's license header for the file at path,
+// with the template's variables replaced and followed by an empty
+// line.  Headers are written as Go comments, so files that aren't Go
+// files don't get one.
+func (p Project) LicenseHeaderFor(path string) string {
+	if filepath.Ext(path) != ".go" {
+		return ""
+	}
+	header := strings.TrimSpace(p.LicenseHeader())
+	if header == "" {
+		return ""
+	}
+	return RenderLicense(header, p.Settings().License.Author, path, time.Now().Year()) + "\n\n"
+}
+
+// RenderLicense replaces the variables in template for the file at
+// path.
+func RenderLicense(template, author, path string, year int) string {
+	return strings.NewReplacer(
+		"{{year}}", strconv.Itoa(year),
+		"{{author}}", author,
+		"{{filename}}", filepath.Base(path),
+	).Replace(template)
+}
@@ -0,0 +1,67 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/nelsam/vidar/setting/dirs"
+)
+
+// ConfigDirFlag is the command line flag that points vidar at a
+// directory to keep all of its config files and data in, for portable
+// installs.  Config files are loaded as soon as this package is
+// initialized, so the flag is read straight from os.Args; it's only
+// registered with the command line parser so that it's accepted and
+// documented.
+const ConfigDirFlag = "config-dir"
+
+// locations are the directories that vidar keeps its config files and
+// data in.
+var locations = loadLocations()
+
+// loadLocations returns the directories from ConfigDirFlag, if it was
+// passed, or the platform's defaults.  Files in the directories that
+// older versions of vidar used are copied to the defaults the first
+// time they're used.
+func loadLocations() dirs.Dirs {
+	if dir, ok := dirs.FlagValue(os.Args[1:], ConfigDirFlag); ok {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			log.Printf("Error: Could not find config directory %s: %s", dir, err)
+			abs = dir
+		}
+		return dirs.Portable(abs)
+	}
+	d := dirs.Default(runtime.GOOS, os.Getenv)
+	migrate(App.ConfigHome(), d.Config)
+	migrate(App.DataHome(), d.Data)
+	return d
+}
+
+func migrate(from, to string) {
+	copied, err := dirs.Migrate(from, to)
+	if err != nil {
+		log.Printf("Error: Could not migrate %s: %s", from, err)
+		return
+	}
+	if copied {
+		log.Printf("Copied files from %s to %s", from, to)
+	}
+}
+
+// ConfigDir returns the directory that config files are kept in.
+func ConfigDir() string {
+	return locations.Config
+}
+
+// DataDir returns the directory that vidar's own files, like undo
+// history and recovery snapshots, are kept in.
+func DataDir() string {
+	return locations.Data
+}
@@ -0,0 +1,81 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"log"
+
+	"github.com/nelsam/vidar/setting/config"
+)
+
+const modalKeysFilename = "modalkeys"
+
+var (
+	modalKeys *config.Config
+
+	// defaultModalKeys are the key sequences for each action in
+	// modal editing.  Keys in config files are case insensitive, so
+	// the action names are used as keys instead of the sequences.
+	defaultModalKeys = map[string][]string{
+		"left":              {"h"},
+		"down":              {"j"},
+		"up":                {"k"},
+		"right":             {"l"},
+		"word-forward":      {"w"},
+		"word-backward":     {"b"},
+		"word-end":          {"e"},
+		"line-start":        {"0"},
+		"line-first-char":   {"^"},
+		"line-end":          {"$"},
+		"file-start":        {"gg"},
+		"file-end":          {"G"},
+		"delete":            {"d"},
+		"change":            {"c"},
+		"yank":              {"y"},
+		"inner":             {"i"},
+		"around":            {"a"},
+		"insert":            {"i"},
+		"append":            {"a"},
+		"insert-line-start": {"I"},
+		"append-line-end":   {"A"},
+		"open-below":        {"o"},
+		"open-above":        {"O"},
+		"visual":            {"v"},
+		"delete-char":       {"x"},
+		"paste-after":       {"p"},
+		"paste-before":      {"P"},
+		"undo":              {"u"},
+	}
+)
+
+func init() {
+	var err error
+	modalKeys, err = config.New(opener{}, modalKeysFilename, defaultConfigDir)
+	if err != nil {
+		log.Printf("Error reading modal key bindings: %s", err)
+	}
+	for action, keys := range defaultModalKeys {
+		modalKeys.SetDefault(action, keys)
+	}
+}
+
+// ModalKeys returns the key sequences for each action in modal
+// editing, keyed by action name.  The keys file will be written
+// with the defaults if it doesn't exist, so that it can be edited.
+func ModalKeys() map[string][]string {
+	keys := make(map[string][]string)
+	for _, action := range modalKeys.Keys() {
+		seqs, ok := modalKeys.Get(action).([]string)
+		if !ok {
+			log.Printf("Error parsing modal key bindings: %s must be a list of key sequences", action)
+			continue
+		}
+		keys[action] = seqs
+	}
+	if err := modalKeys.Write(); err != nil {
+		log.Printf("Error writing modal key bindings: %s", err)
+	}
+	return keys
+}
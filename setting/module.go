@@ -0,0 +1,113 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	modFilename   = "go.mod"
+	vendorModules = "vendor/modules.txt"
+)
+
+// Module is the go module that a project's code is in.
+type Module struct {
+	// Path is the module path from the go.mod file.
+	Path string
+
+	// Dir is the directory containing the go.mod file.
+	Dir string
+
+	// Vendored is set when the module's dependencies are vendored,
+	// in which case the go tool needs -mod=vendor.
+	Vendored bool
+}
+
+// Module finds the go.mod file for p, looking in p.Path and then each
+// of its parent directories.  The returned bool will be false if p is
+// not in a module, which means that p is a GOPATH project.
+func (p Project) Module() (Module, bool) {
+	if p.Path == "" {
+		return Module{}, false
+	}
+	dir := filepath.Clean(p.Path)
+	for {
+		if m, ok := readModule(dir); ok {
+			return m, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return Module{}, false
+		}
+		dir = parent
+	}
+}
+
+func readModule(dir string) (Module, bool) {
+	f, err := os.Open(filepath.Join(dir, modFilename))
+	if err != nil {
+		return Module{}, false
+	}
+	defer f.Close()
+	m := Module{Dir: dir}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+		path := strings.TrimSpace(strings.TrimPrefix(line, "module"))
+		if i := strings.Index(path, "//"); i >= 0 {
+			path = strings.TrimSpace(path[:i])
+		}
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			path = unquoted
+		}
+		m.Path = path
+		break
+	}
+	if _, err := os.Stat(filepath.Join(dir, vendorModules)); err == nil {
+		m.Vendored = true
+	}
+	return m, true
+}
+
+// GoEnviron returns p's environment for running go tools (goimports,
+// gocode, godef, etc).  GO111MODULE and GOFLAGS are set to match
+// whether or not p is in a module, so that the tools resolve imports
+// the same way that the go command would.  Values that are already
+// set, either in p.Env or in the editor's environment, are left
+// alone, except for the GOOS and GOARCH of p's build context.
+func (p Project) GoEnviron() []string {
+	environ := p.Environ()
+	m, ok := p.Module()
+	if !hasEnv(environ, "GO111MODULE") {
+		mode := "off"
+		if ok {
+			mode = "on"
+		}
+		environ = append(environ, "GO111MODULE="+mode)
+	}
+	if ok && m.Vendored && !hasEnv(environ, "GOFLAGS") {
+		environ = append(environ, "GOFLAGS=-mod=vendor")
+	}
+	return p.BuildContext().environ(environ)
+}
+
+func hasEnv(environ []string, key string) bool {
+	prefix := key + "="
+	for _, v := range environ {
+		if strings.HasPrefix(v, prefix) && v != prefix {
+			return true
+		}
+	}
+	return false
+}
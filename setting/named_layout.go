@@ -0,0 +1,61 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"log"
+	"sort"
+)
+
+// NamedLayout is a window layout that was saved under a name, so that
+// it can be switched back to later.
+type NamedLayout struct {
+	// Editors is the layout of the splits and the files that were
+	// open in them.
+	Editors Layout
+
+	// NavPane is the name of the navigator pane that was shown, or
+	// empty if the navigator's panes were hidden.
+	NavPane string
+}
+
+func namedLayouts() map[string]NamedLayout {
+	l, ok := sessions.Get(namedLayoutKey).(map[string]NamedLayout)
+	if !ok {
+		return nil
+	}
+	return l
+}
+
+// LayoutNames returns the names of every saved layout, sorted.
+func LayoutNames() []string {
+	var names []string
+	for name := range namedLayouts() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadLayout returns the layout that was saved as name.  The returned
+// bool will be false if there is no layout named name.
+func LoadLayout(name string) (NamedLayout, bool) {
+	l, ok := namedLayouts()[name]
+	return l, ok
+}
+
+// SaveLayout saves l as name, replacing any layout that was already
+// saved as name, and writes it to the session file.
+func SaveLayout(name string, l NamedLayout) {
+	layouts := namedLayouts()
+	if layouts == nil {
+		layouts = make(map[string]NamedLayout)
+	}
+	layouts[name] = l
+	sessions.Set(namedLayoutKey, layouts)
+	if err := sessions.Write(); err != nil {
+		log.Printf("Error updating session file: %s", err)
+	}
+}
@@ -5,33 +5,50 @@
 package setting
 
 import (
-	"log"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 )
 
-const pluginsDirname = "plugins"
+const (
+	pluginsDirname = "plugins"
+	pluginsKey     = "plugins"
+	pluginExt      = ".so"
+)
 
-func Plugins() []string {
-	pluginsPath := filepath.Join(App.DataHome(), pluginsDirname)
-	dir, err := os.Open(pluginsPath)
-	if err == os.ErrNotExist {
-		return nil
-	}
-	if err != nil {
-		log.Printf("Failed to read directory %s: %s", pluginsPath, err)
-		return nil
+// DefaultPluginsDir is the directory that plugins will be loaded
+// from if no plugins directory is found in the config files.
+var DefaultPluginsDir = filepath.Join(DataDir(), pluginsDirname)
+
+// PluginsDir returns the directory that plugins should be loaded
+// from.  Environment variables in the setting are expanded.
+func PluginsDir() string {
+	dir, ok := settings.Get(pluginsKey).(string)
+	if !ok || dir == "" {
+		return DefaultPluginsDir
 	}
+	return os.ExpandEnv(dir)
+}
 
-	finfos, err := dir.Readdir(-1)
+// Plugins returns the paths to all plugin (.so) files in the plugins
+// directory, sorted by name.  A missing plugins directory is not an
+// error.
+func Plugins() ([]string, error) {
+	dir := PluginsDir()
+	finfos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
 	if err != nil {
-		log.Printf("Failed to list directory contents at %s: %s", pluginsPath, err)
-		return nil
+		return nil, err
 	}
 
-	paths := make([]string, 0, len(finfos))
+	var paths []string
 	for _, finfo := range finfos {
-		paths = append(paths, filepath.Join(pluginsPath, finfo.Name()))
+		if finfo.IsDir() || filepath.Ext(finfo.Name()) != pluginExt {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, finfo.Name()))
 	}
-	return paths
+	return paths, nil
 }
@@ -0,0 +1,264 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/fsw"
+	"github.com/nelsam/vidar/setting/config"
+	"github.com/nelsam/vidar/setting/ignore"
+)
+
+const (
+	// ProjectConfigDirname is the name of the directory, in a
+	// project's root, that project-specific config files are loaded
+	// from.
+	ProjectConfigDirname = ".vidar"
+
+	envKey       = "env"
+	goimportsKey = "goimports"
+	ignoreKey    = "ignore"
+	tasksKey     = "tasks"
+	licenseKey   = "license"
+)
+
+// ProjectSettings are the settings that a project can override in the
+// settings file in its ProjectConfigDirname (e.g.
+// .vidar/settings.toml).  Fields that are left out of the file don't
+// override anything.
+type ProjectSettings struct {
+	// Fonts replaces the global fonts setting.
+	Fonts []Font
+
+	// Env is added to the project's environment, after the Env
+	// from the projects file.  It uses the same syntax.
+	Env map[string]string
+
+	// Goimports replaces the goimports table from the projects
+	// file, e.g. to turn off formatting on save.
+	Goimports *Goimports
+
+	// Ignore is a list of gitignore-style patterns for files and
+	// directories to leave out of the project tree, searches,
+	// indexes, and filesystem watches.  They're added after the
+	// global ignore patterns, so "!" patterns can re-include
+	// paths that the global patterns ignore.
+	Ignore []string
+
+	// Tasks are the commands that can be run in the project with
+	// run-task.
+	Tasks []Task
+
+	// License configures the license header template for new
+	// files, update-license, and relicense.
+	License License
+
+	// StructTags replaces the global structtags table, e.g. to
+	// use a different naming convention in one project.
+	StructTags *StructTags
+
+	// Commit replaces the global commit table, e.g. to use the
+	// commit message templates of one project.
+	Commit *Commit
+
+	// Todo replaces the global todo table, e.g. to list a
+	// project's own markers.
+	Todo *Todo
+}
+
+// Ignored returns whether or not the directory at rel, relative to
+// the project root, is matched by s.Ignore.
+func (s ProjectSettings) Ignored(rel string) bool {
+	return ignore.New(s.Ignore).Match(filepath.ToSlash(rel), true)
+}
+
+// projectConfig is the settings file for a single project, which is
+// reloaded whenever it changes.
+type projectConfig struct {
+	mu       sync.RWMutex
+	settings ProjectSettings
+	watcher  fsw.Watcher
+}
+
+var (
+	projectConfigsMu sync.Mutex
+	projectConfigs   = make(map[string]*projectConfig)
+
+	projectListenersMu sync.RWMutex
+	projectListeners   []func(root string)
+)
+
+// OnProjectSettingsChange registers f to be called whenever the
+// project settings file for a project is reloaded.  root is the
+// path of the project whose settings changed.  f is not called on
+// the UI goroutine.
+func OnProjectSettingsChange(f func(root string)) {
+	projectListenersMu.Lock()
+	defer projectListenersMu.Unlock()
+	projectListeners = append(projectListeners, f)
+}
+
+// LoadProjectSettings returns the project-specific settings for the
+// project at root.  The settings file is read the first time that a
+// project's settings are loaded, and then watched so that later
+// calls return its current contents.
+func LoadProjectSettings(root string) ProjectSettings {
+	if root == "" {
+		return ProjectSettings{}
+	}
+	root = filepath.Clean(root)
+	projectConfigsMu.Lock()
+	c, ok := projectConfigs[root]
+	if !ok {
+		c = newProjectConfig(root)
+		projectConfigs[root] = c
+	}
+	projectConfigsMu.Unlock()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.settings
+}
+
+func newProjectConfig(root string) *projectConfig {
+	dir := filepath.Join(root, ProjectConfigDirname)
+	c := &projectConfig{}
+	if s, err := readProjectSettings(dir); err == nil {
+		c.settings = s
+	} else if !os.IsNotExist(err) {
+		log.Printf("Error reading project settings in %s: %s", dir, err)
+	}
+	w, err := fsw.New()
+	if err != nil {
+		log.Printf("WARNING: could not watch project settings in %s: %s", dir, err)
+		return c
+	}
+	// Watching the project root, as well as the config directory,
+	// means that we see the config directory being created.
+	for _, d := range []string{root, dir} {
+		if err := w.Add(d); err != nil && !os.IsNotExist(err) {
+			log.Printf("WARNING: could not watch %s for project settings: %s", d, err)
+		}
+	}
+	c.watcher = w
+	go c.watch(root, dir)
+	return c
+}
+
+func (c *projectConfig) watch(root, dir string) {
+	for {
+		e, err := c.watcher.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			log.Printf("WARNING: error from project settings watcher: %s", err)
+			continue
+		}
+		if e.Path == dir {
+			if e.Op == fsw.Create {
+				if err := c.watcher.Add(dir); err != nil {
+					log.Printf("WARNING: could not watch %s for project settings: %s", dir, err)
+				}
+			}
+			c.reload(root, dir)
+			continue
+		}
+		if filepath.Dir(e.Path) != dir || !strings.HasPrefix(filepath.Base(e.Path), settingsFilename+".") {
+			continue
+		}
+		c.reload(root, dir)
+	}
+}
+
+// reload reads the settings in dir again and notifies listeners.
+// Files that can't be parsed are skipped, since they're usually in
+// the middle of being edited.
+func (c *projectConfig) reload(root, dir string) {
+	s, err := readProjectSettings(dir)
+	if os.IsNotExist(err) {
+		s, err = ProjectSettings{}, nil
+	}
+	if err != nil {
+		log.Printf("Error reloading project settings in %s: %s", dir, err)
+		return
+	}
+	c.mu.Lock()
+	c.settings = s
+	c.mu.Unlock()
+
+	projectListenersMu.RLock()
+	defer projectListenersMu.RUnlock()
+	for _, f := range projectListeners {
+		f(root)
+	}
+}
+
+func readProjectSettings(dir string) (ProjectSettings, error) {
+	c, err := config.New(opener{}, settingsFilename, dir)
+	if err != nil {
+		return ProjectSettings{}, err
+	}
+	if !c.Exists() {
+		return ProjectSettings{}, os.ErrNotExist
+	}
+	c.SetDefault("fonts", []Font(nil))
+	c.SetDefault(envKey, map[string]string(nil))
+	c.SetDefault(goimportsKey, (*Goimports)(nil))
+	c.SetDefault(ignoreKey, []string(nil))
+	c.SetDefault(tasksKey, []Task(nil))
+	c.SetDefault(licenseKey, License{})
+	c.SetDefault(structTagsKey, (*StructTags)(nil))
+	c.SetDefault(commitKey, (*Commit)(nil))
+	c.SetDefault(todoKey, (*Todo)(nil))
+
+	var s ProjectSettings
+	s.Fonts, _ = c.Get("fonts").([]Font)
+	s.Env, _ = c.Get(envKey).(map[string]string)
+	s.Goimports, _ = c.Get(goimportsKey).(*Goimports)
+	s.Ignore, _ = c.Get(ignoreKey).([]string)
+	s.Tasks, _ = c.Get(tasksKey).([]Task)
+	s.License, _ = c.Get(licenseKey).(License)
+	s.StructTags, _ = c.Get(structTagsKey).(*StructTags)
+	s.Commit, _ = c.Get(commitKey).(*Commit)
+	s.Todo, _ = c.Get(todoKey).(*Todo)
+	return s, nil
+}
+
+// Settings returns p's project-specific settings.
+func (p Project) Settings() ProjectSettings {
+	return LoadProjectSettings(p.Path)
+}
+
+// GoimportsConfig returns p's goimports config, from its project
+// settings if they override it or from the projects file otherwise.
+func (p Project) GoimportsConfig() Goimports {
+	if g := p.Settings().Goimports; g != nil {
+		return *g
+	}
+	return p.Goimports
+}
+
+// ProjectFont returns the most preferred font from p's project
+// settings at size.  If p's settings don't override the fonts, or
+// none of them can be loaded, the font from PrefFontSize is
+// returned.
+func ProjectFont(d gxui.Driver, p Project, size int) gxui.Font {
+	fonts := p.Settings().Fonts
+	if len(fonts) == 0 {
+		return PrefFontSize(d, size)
+	}
+	if f := firstFont(d, fonts, size); f != nil {
+		return f
+	}
+	return PrefFontSize(d, size)
+}
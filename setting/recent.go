@@ -0,0 +1,41 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import "log"
+
+const (
+	recentFilesKey = "recentfiles"
+
+	// MaxRecentFiles is the number of recently opened files that
+	// are remembered.
+	MaxRecentFiles = 50
+)
+
+// RecentFiles returns the paths of recently opened files, most
+// recently opened first.
+func RecentFiles() []string {
+	files, _ := sessions.Get(recentFilesKey).([]string)
+	return files
+}
+
+// AddRecentFile moves path to the front of the recently opened files
+// and writes them to the session file.
+func AddRecentFile(path string) {
+	files := []string{path}
+	for _, f := range RecentFiles() {
+		if f == path {
+			continue
+		}
+		if len(files) == MaxRecentFiles {
+			break
+		}
+		files = append(files, f)
+	}
+	sessions.Set(recentFilesKey, files)
+	if err := sessions.Write(); err != nil {
+		log.Printf("Error updating session file: %s", err)
+	}
+}
@@ -0,0 +1,13 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import "path/filepath"
+
+const recoveryDirname = "recovery"
+
+// RecoveryDir is the directory that snapshots of unsaved files are
+// written to, so that they can be recovered after a crash.
+var RecoveryDir = filepath.Join(DataDir(), recoveryDirname)
@@ -0,0 +1,123 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"log"
+	"os"
+
+	"github.com/nelsam/vidar/setting/config"
+)
+
+const (
+	sessionFilename = "session"
+
+	lastProjectKey = "lastproject"
+	layoutsKey     = "layouts"
+	namedLayoutKey = "namedlayouts"
+)
+
+var sessions *config.Config
+
+func init() {
+	var err error
+	sessions, err = config.New(opener{}, sessionFilename, defaultConfigDir)
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	if err != nil {
+		log.Printf("Error reading session: %s", err)
+	}
+	sessions.SetDefault(lastProjectKey, "")
+	sessions.SetDefault(layoutsKey, map[string]Layout(nil))
+	sessions.SetDefault(namedLayoutKey, map[string]NamedLayout(nil))
+	sessions.SetDefault(recentFilesKey, []string(nil))
+	sessions.SetDefault(clipboardHistoryKey, []string(nil))
+}
+
+// OpenFile is the state of a single file that was open when a
+// session was saved.
+type OpenFile struct {
+	Path    string
+	Carets  []int
+	ScrollX int
+	ScrollY int
+
+	// Folds holds the first line of each folded region.
+	Folds []int
+}
+
+// Layout is the state of a group of editors that was open when a
+// session was saved.  A Layout with Splits is a split editor, with
+// each split saved in order; otherwise it is a group of tabs.
+//
+// Weights holds the fraction of a split editor that each of its
+// splits takes up.  It may be empty for layouts saved before split
+// sizes were stored, in which case the splits are equal.
+type Layout struct {
+	Orientation string
+	Current     int
+	Files       []OpenFile
+	Splits      []Layout
+	Weights     []float64
+}
+
+// Empty returns whether or not l has any files open in it.
+func (l Layout) Empty() bool {
+	if len(l.Files) > 0 {
+		return false
+	}
+	for _, s := range l.Splits {
+		if !s.Empty() {
+			return false
+		}
+	}
+	return true
+}
+
+func layouts() map[string]Layout {
+	l, ok := sessions.Get(layoutsKey).(map[string]Layout)
+	if !ok {
+		return nil
+	}
+	return l
+}
+
+// LastProject returns the name of the project that was active the
+// last time a session was saved.
+func LastProject() string {
+	name, _ := sessions.Get(lastProjectKey).(string)
+	return name
+}
+
+// SessionLayout returns the editor layout that was saved for the
+// named project.  The returned bool will be false if no layout was
+// saved for the project.
+func SessionLayout(project string) (Layout, bool) {
+	l, ok := layouts()[project]
+	return l, ok
+}
+
+// SaveSession stores the editor layouts for each project, along
+// with the name of the currently active project, and writes them to
+// the session file.
+func SaveSession(current string, projectLayouts map[string]Layout) {
+	l := layouts()
+	if l == nil {
+		l = make(map[string]Layout)
+	}
+	for name, layout := range projectLayouts {
+		if layout.Empty() {
+			delete(l, name)
+			continue
+		}
+		l[name] = layout
+	}
+	sessions.Set(layoutsKey, l)
+	sessions.Set(lastProjectKey, current)
+	if err := sessions.Write(); err != nil {
+		log.Printf("Error updating session file: %s", err)
+	}
+}
@@ -14,11 +14,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/OpenPeeDeeP/xdg"
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/vidar/setting/config"
+	"github.com/nelsam/vidar/setting/dirs"
+	"github.com/nelsam/vidar/theme"
 	"golang.org/x/image/font/gofont/gomono"
 	"golang.org/x/image/font/gofont/gomonobold"
 	"golang.org/x/image/font/gofont/gomonobolditalic"
@@ -26,28 +29,58 @@ import (
 )
 
 const (
-	// LicenseHeaderFilename is the file name to look for in projects.
-	//
-	// TODO: this belongs in the license header plugin, since not all
-	// languages need it.
-	LicenseHeaderFilename = ".license-header"
-
 	// DefaultFontSize is the font size that will be used if no font
 	// size settings are found in the config files.
 	DefaultFontSize = 12
 
 	projectsFilename = "projects"
 	settingsFilename = "settings"
+
+	// DefaultPollInterval is the interval that polling filesystem
+	// watchers will use if no poll interval is found in the config
+	// files.
+	DefaultPollInterval = time.Second
+
+	// DefaultAutoSaveDelay is the idle time that auto-save will wait
+	// for if no delay is found in the config files.
+	DefaultAutoSaveDelay = 2 * time.Second
+
+	// DefaultZenWidth is the width, in columns, that the editor is
+	// limited to in zen mode if no width is found in the config files.
+	DefaultZenWidth = 100
+
+	lineNumbersKey  = "linenumbers"
+	pollIntervalKey = "pollinterval"
+	autoSaveKey     = "autosave"
+	modalKey        = "modal"
+	minimapKey      = "minimap"
+	themeKey        = "theme"
+	firstHunkKey    = "jumptofirsthunk"
+	fontSizeKey     = "fontsize"
+	smoothScrollKey = "smoothscroll"
+	pastEndKey      = "scrollpastend"
+	zenWidthKey     = "zenwidth"
+	breadcrumbsKey  = "breadcrumbs"
+	occurrencesKey  = "highlightoccurrences"
+	uiScaleKey      = "uiscale"
+
+	// DefaultTheme is the name of the theme that will be used if
+	// no theme is found in the config files.
+	DefaultTheme = theme.DefaultName
+
+	themesDirname    = "themes"
+	templatesDirname = "templates"
 )
 
 var (
-	// App is an XDG application config.  It's exported so that plugins can load their own config
-	// files from vidar's config directories.
+	// App is the XDG application config that older versions of vidar
+	// kept their files in.  Files are migrated from its directories
+	// to ConfigDir and DataDir on startup.
 	//
-	// TODO: we should unexport this and provide functions to access its methods.  Allowing plugins
-	// to assign to App is misleading and potentially dangerous.
-	App              = xdg.New("", "vidar")
-	defaultConfigDir = App.ConfigHome()
+	// Deprecated: plugins should load their config files from
+	// ConfigDir, which also follows ConfigDirFlag.
+	App              = xdg.New("", dirs.Name)
+	defaultConfigDir = ConfigDir()
 	projects         *config.Config
 	settings         *config.Config
 
@@ -76,6 +109,8 @@ func init() {
 		log.Printf("Error reading projects: %s", err)
 	}
 	projects.SetDefault("projects", []Project(nil))
+	projects.SetDefault(bookmarksKey, map[string][]Bookmark(nil))
+	projects.SetDefault(buildContextsKey, map[string]BuildContext(nil))
 
 	updateDeprecatedGopath(projects)
 
@@ -87,6 +122,31 @@ func init() {
 		log.Printf("Error reading settings: %s", err)
 	}
 	settings.SetDefault("fonts", []Font(nil))
+	settings.SetDefault(lineNumbersKey, true)
+	settings.SetDefault(pollIntervalKey, DefaultPollInterval.String())
+	settings.SetDefault(autoSaveKey, AutoSave{Delay: DefaultAutoSaveDelay.String()})
+	settings.SetDefault(modalKey, false)
+	settings.SetDefault(minimapKey, false)
+	settings.SetDefault(themeKey, DefaultTheme)
+	settings.SetDefault(firstHunkKey, true)
+	settings.SetDefault(fontSizeKey, 0)
+	settings.SetDefault(smoothScrollKey, true)
+	settings.SetDefault(pastEndKey, false)
+	settings.SetDefault(zenWidthKey, DefaultZenWidth)
+	settings.SetDefault(uiScaleKey, 0.0)
+	settings.SetDefault(breadcrumbsKey, true)
+	settings.SetDefault(occurrencesKey, true)
+	settings.SetDefault(currentLineKey, true)
+	settings.SetDefault(caretStyleKey, string(CaretBar))
+	settings.SetDefault(caretBlinkKey, DefaultCaretBlink.String())
+	settings.SetDefault(pluginsKey, DefaultPluginsDir)
+	settings.SetDefault(historyKey, History{Persist: true, MaxEdits: DefaultHistoryMaxEdits})
+	settings.SetDefault(clipboardKey, Clipboard{HistorySize: DefaultClipboardHistorySize})
+	settings.SetDefault(structTagsKey, DefaultStructTags)
+	settings.SetDefault(commitKey, Commit{Guides: DefaultCommitGuides})
+	settings.SetDefault(todoKey, Todo{Markers: DefaultTodoMarkers})
+	settings.SetDefault(indentKey, map[string]Indent(nil))
+	settings.SetDefault(ignoreKey, DefaultIgnore)
 }
 
 func updateDeprecatedGopath(c *config.Config) error {
@@ -111,6 +171,34 @@ func updateDeprecatedGopath(c *config.Config) error {
 	return nil
 }
 
+// AutoSave is the configuration for automatically saving files.
+type AutoSave struct {
+	// Enabled turns on saving files after Delay has passed without
+	// any edits.
+	Enabled bool
+
+	// Delay is the idle time to wait for before saving, parsed
+	// with time.ParseDuration.
+	Delay string
+
+	// OnFocusLost turns on saving files when a different file is
+	// focused.
+	OnFocusLost bool
+}
+
+// DelayDuration returns a.Delay as a time.Duration.
+func (a AutoSave) DelayDuration() time.Duration {
+	if a.Delay == "" {
+		return DefaultAutoSaveDelay
+	}
+	d, err := time.ParseDuration(a.Delay)
+	if err != nil {
+		log.Printf("Error parsing %s delay %q: %s", autoSaveKey, a.Delay, err)
+		return DefaultAutoSaveDelay
+	}
+	return d
+}
+
 type Font struct {
 	Name string
 	Size int
@@ -121,38 +209,46 @@ type Project struct {
 	Path string
 	Env  map[string]string
 
+	// Roots are more directories that are open alongside Path,
+	// which makes the project a workspace with several roots (e.g.
+	// a repository that is split across multiple checkouts).  Path
+	// is still the project's main root, which go tools and project
+	// settings use.
+	Roots []string `toml:",omitempty" json:",omitempty" yaml:",omitempty"`
+
+	// Goimports configures the goimports plugin for this project.
+	Goimports Goimports
+
 	// Gopath is deprecated.  It is now merged into Env.
 	// It's kept here for migration purposes.
 	Gopath string `toml:",omitempty" json:",omitempty" yaml:",omitempty`
 }
 
-func (p Project) LicenseHeader() string {
-	f, err := os.Open(filepath.Join(p.Path, LicenseHeaderFilename))
-	if os.IsNotExist(err) {
-		return ""
-	}
-	if err != nil {
-		log.Printf("Error opening license header file: %s", err)
-		return ""
-	}
-	defer f.Close()
-	header, err := ioutil.ReadAll(f)
-	if err != nil {
-		log.Printf("Error reading license header file: %s", err)
-		return ""
-	}
-	return string(header)
+// Goimports is the per-project configuration for goimports.
+type Goimports struct {
+	// Disabled turns off running goimports on save.
+	Disabled bool
+
+	// Local is passed to goimports' -local flag, which puts imports
+	// beginning with it after third-party imports.
+	Local string
 }
 
 func (p Project) String() string {
 	return p.Name
 }
 
+// Environ returns the environment for processes run in p: the
+// editor's environment, with p.Env and then the env from p's project
+// settings added.
 func (p Project) Environ() []string {
 	environ := os.Environ()
 	for k, v := range p.Env {
 		environ = addEnv(environ, k, v)
 	}
+	for k, v := range p.Settings().Env {
+		environ = addEnv(environ, k, v)
+	}
 	return environ
 }
 
@@ -253,6 +349,177 @@ func AddProject(project Project) {
 	}
 }
 
+// LineNumbers returns whether or not editors should display a
+// line number gutter.
+func LineNumbers() bool {
+	show, ok := settings.Get(lineNumbersKey).(bool)
+	if !ok {
+		return true
+	}
+	return show
+}
+
+// SetLineNumbers updates the line number setting and writes it to
+// the settings file.
+func SetLineNumbers(show bool) {
+	settings.Set(lineNumbersKey, show)
+	if err := settings.Write(); err != nil {
+		log.Printf("Error updating settings file: %s", err)
+	}
+}
+
+// Minimap returns whether or not editors should display a minimap.
+func Minimap() bool {
+	show, _ := settings.Get(minimapKey).(bool)
+	return show
+}
+
+// SetMinimap updates the minimap setting and writes it to the
+// settings file.
+func SetMinimap(show bool) {
+	settings.Set(minimapKey, show)
+	if err := settings.Write(); err != nil {
+		log.Printf("Error updating settings file: %s", err)
+	}
+}
+
+// SmoothScroll returns whether or not editors should animate
+// scrolling by the mouse wheel and by page up and page down, rather
+// than jumping straight to the new position.
+func SmoothScroll() bool {
+	smooth, ok := settings.Get(smoothScrollKey).(bool)
+	if !ok {
+		return true
+	}
+	return smooth
+}
+
+// ScrollPastEnd returns whether or not editors may be scrolled until
+// their last line is in the middle of the view.
+func ScrollPastEnd() bool {
+	pastEnd, _ := settings.Get(pastEndKey).(bool)
+	return pastEnd
+}
+
+// Breadcrumbs returns whether or not the package, type, and function
+// that the caret is in should be shown above go files.
+func Breadcrumbs() bool {
+	show, ok := settings.Get(breadcrumbsKey).(bool)
+	if !ok {
+		return true
+	}
+	return show
+}
+
+// HighlightOccurrences returns whether or not the other occurrences of
+// the identifier under the caret should be highlighted.
+func HighlightOccurrences() bool {
+	highlight, ok := settings.Get(occurrencesKey).(bool)
+	if !ok {
+		return true
+	}
+	return highlight
+}
+
+// ZenWidth returns the number of columns that the editor is limited
+// to in zen mode.
+func ZenWidth() int {
+	width, _ := settings.Get(zenWidthKey).(int)
+	if width <= 0 {
+		return DefaultZenWidth
+	}
+	return width
+}
+
+// UIScale returns the scale that the UI should be drawn at, or 0 if
+// the scale should be detected from the DPI of the monitor that each
+// window is on.
+func UIScale() float32 {
+	scale, _ := settings.Get(uiScaleKey).(float64)
+	if scale < 0 {
+		return 0
+	}
+	return float32(scale)
+}
+
+// SetUIScale updates the UI scale setting and writes it to the
+// settings file.  A scale of 0 goes back to detecting the scale.
+func SetUIScale(scale float32) {
+	settings.Set(uiScaleKey, float64(scale))
+	if err := settings.Write(); err != nil {
+		log.Printf("Error updating settings file: %s", err)
+	}
+}
+
+// Theme returns the name of the theme that the editor should use.
+func Theme() string {
+	name, ok := settings.Get(themeKey).(string)
+	if !ok || name == "" {
+		return DefaultTheme
+	}
+	return name
+}
+
+// SetTheme updates the theme setting and writes it to the settings
+// file.
+func SetTheme(name string) {
+	settings.Set(themeKey, name)
+	if err := settings.Write(); err != nil {
+		log.Printf("Error updating settings file: %s", err)
+	}
+}
+
+// ThemesDir returns the directory that theme files are loaded from.
+func ThemesDir() string {
+	return filepath.Join(defaultConfigDir, themesDirname)
+}
+
+// TemplatesDir returns the directory that project templates are
+// loaded from.
+func TemplatesDir() string {
+	return filepath.Join(defaultConfigDir, templatesDirname)
+}
+
+// JumpToFirstHunk returns whether or not files opened because they
+// were modified should be scrolled to their first change.
+func JumpToFirstHunk() bool {
+	jump, ok := settings.Get(firstHunkKey).(bool)
+	if !ok {
+		return true
+	}
+	return jump
+}
+
+// Modal returns whether or not vim-style modal editing is turned on.
+func Modal() bool {
+	modal, _ := settings.Get(modalKey).(bool)
+	return modal
+}
+
+// PollInterval returns the interval that polling filesystem watchers
+// should use to check for changes.
+func PollInterval() time.Duration {
+	v, ok := settings.Get(pollIntervalKey).(string)
+	if !ok {
+		return DefaultPollInterval
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("Error parsing %s setting %q: %s", pollIntervalKey, v, err)
+		return DefaultPollInterval
+	}
+	return d
+}
+
+// AutoSaveConfig returns the current auto-save settings.
+func AutoSaveConfig() AutoSave {
+	a, ok := settings.Get(autoSaveKey).(AutoSave)
+	if !ok {
+		return AutoSave{Delay: DefaultAutoSaveDelay.String()}
+	}
+	return a
+}
+
 func find(path, name string, extensions []string) (io.Reader, error) {
 	d, err := os.Open(path)
 	if err != nil {
@@ -323,30 +590,74 @@ func loadFont(font string) (io.Reader, error) {
 	return nil, os.ErrNotExist
 }
 
-// PrefFont returns the most preferred font found on the system.
+// FontSize returns the font size that was chosen while the editor was
+// running, or 0 if the sizes in the fonts setting should be used.
+func FontSize() int {
+	size, _ := settings.Get(fontSizeKey).(int)
+	if size < 0 {
+		return 0
+	}
+	return size
+}
+
+// SetFontSize updates the font size setting and writes it to the
+// settings file.  A size of 0 resets the font size to the sizes in the
+// fonts setting.
+func SetFontSize(size int) {
+	settings.Set(fontSizeKey, size)
+	if err := settings.Write(); err != nil {
+		log.Printf("Error updating settings file: %s", err)
+	}
+}
+
+// PrefFont returns the most preferred font found on the system, at
+// the size returned by FontSize.
 func PrefFont(d gxui.Driver) gxui.Font {
+	return PrefFontSize(d, FontSize())
+}
+
+// PrefFontSize returns the most preferred font found on the system
+// at size.  If size is 0, the size from the fonts setting is used.
+func PrefFontSize(d gxui.Driver, size int) gxui.Font {
 	fonts, ok := settings.Get("fonts").([]Font)
 	if !ok {
-		return parseDefaultFont(d)
+		return parseDefaultFont(d, size)
+	}
+	if f := firstFont(d, fonts, size); f != nil {
+		return f
 	}
+	return parseDefaultFont(d, size)
+}
+
+// firstFont returns the first font in fonts that can be loaded and
+// parsed, or nil if none of them can.  If size is 0, each font's
+// size is used.
+func firstFont(d gxui.Driver, fonts []Font, size int) gxui.Font {
 	for _, font := range fonts {
 		r, err := loadFont(font.Name)
 		if err != nil {
 			log.Printf("Failed to load font %s: %s", font.Name, err)
 			continue
 		}
-		f, err := parseFont(d, r, font.Size)
+		fontSize := font.Size
+		if size > 0 {
+			fontSize = size
+		}
+		f, err := parseFont(d, r, fontSize)
 		if err != nil {
 			log.Printf("Failed to parse font %s: %s", font.Name, err)
 			continue
 		}
 		return f
 	}
-	return parseDefaultFont(d)
+	return nil
 }
 
-func parseDefaultFont(d gxui.Driver) gxui.Font {
-	f, err := parseFont(d, bytes.NewBuffer(gomono.TTF), DefaultFontSize)
+func parseDefaultFont(d gxui.Driver, size int) gxui.Font {
+	if size <= 0 {
+		size = DefaultFontSize
+	}
+	f, err := parseFont(d, bytes.NewBuffer(gomono.TTF), size)
 	if err != nil {
 		// This is a well-tested font that should never fail to parse.
 		panic(fmt.Errorf("failed to parse default font: %s", err))
@@ -0,0 +1,56 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+const structTagsKey = "structtags"
+
+// DefaultStructTags is the configuration for generating struct tags
+// if none is found in the config files.
+var DefaultStructTags = StructTags{Keys: []string{"json"}, Case: "snake"}
+
+// StructTags is the configuration for generate-struct-tags.
+type StructTags struct {
+	// Keys are the tag keys that are added to each field, e.g.
+	// json, yaml, or db.
+	Keys []string
+
+	// Case is the naming convention for the names in the tags:
+	// snake (e.g. user_id) or camel (e.g. userID).
+	Case string
+
+	// OmitEmpty turns on adding the omitempty option to new tags.
+	OmitEmpty bool
+}
+
+// StructTagsConfig returns the global struct tag settings.
+func StructTagsConfig() StructTags {
+	t, ok := settings.Get(structTagsKey).(StructTags)
+	if !ok {
+		return DefaultStructTags
+	}
+	return t.withDefaults()
+}
+
+// StructTagsConfig returns p's struct tag settings, from its project
+// settings if they override them or from the global settings
+// otherwise.
+func (p Project) StructTagsConfig() StructTags {
+	if t := p.Settings().StructTags; t != nil {
+		return t.withDefaults()
+	}
+	return StructTagsConfig()
+}
+
+// withDefaults fills in the fields of t that were left out of the
+// config files from DefaultStructTags.
+func (t StructTags) withDefaults() StructTags {
+	if len(t.Keys) == 0 {
+		t.Keys = DefaultStructTags.Keys
+	}
+	if t.Case == "" {
+		t.Case = DefaultStructTags.Case
+	}
+	return t
+}
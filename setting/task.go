@@ -0,0 +1,22 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+// Task is a named command that is defined in a project's settings,
+// e.g. to build, run, or generate code for the project.
+type Task struct {
+	Name string
+
+	// Command is run with the user's shell (or cmd.exe on
+	// windows).  Environment variables in it are expanded
+	// first, including FILE (the current file) and PROJECT (the
+	// project's root).
+	Command string
+
+	// Dir is the directory to run Command in.  Relative paths
+	// are relative to the project's root, which is also the
+	// default.  Environment variables are expanded.
+	Dir string
+}
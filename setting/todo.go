@@ -0,0 +1,52 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+const todoKey = "todo"
+
+// DefaultTodoMarkers are the comment markers that the todo pane lists
+// if none are found in the config files.
+var DefaultTodoMarkers = []string{"TODO", "FIXME", "HACK"}
+
+// Todo is the configuration for the todo pane.
+type Todo struct {
+	// Markers are the words that start the comments which the
+	// todo pane lists.  They're case sensitive.
+	Markers []string
+
+	// Exclude is a list of gitignore-style patterns for files and
+	// directories whose comments are left out of the todo pane.
+	// They're added after the ignore patterns, which the todo
+	// pane also leaves out, so "!" patterns can't re-include
+	// ignored paths.
+	Exclude []string
+}
+
+// TodoConfig returns the global todo pane settings.
+func TodoConfig() Todo {
+	t, ok := settings.Get(todoKey).(Todo)
+	if !ok {
+		return Todo{Markers: DefaultTodoMarkers}
+	}
+	return t.withDefaults()
+}
+
+// TodoConfig returns p's todo pane settings, from its project settings
+// if they override them or from the global settings otherwise.
+func (p Project) TodoConfig() Todo {
+	if t := p.Settings().Todo; t != nil {
+		return t.withDefaults()
+	}
+	return TodoConfig()
+}
+
+// withDefaults fills in the markers if they were left out of the
+// config files.
+func (t Todo) withDefaults() Todo {
+	if len(t.Markers) == 0 {
+		t.Markers = DefaultTodoMarkers
+	}
+	return t
+}
@@ -0,0 +1,113 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nelsam/vidar/fsw"
+	"github.com/nelsam/vidar/setting/config"
+)
+
+// reloadDelay is how long a config file has to go without changing
+// before it's reloaded, so that it isn't read halfway through being
+// written.
+const reloadDelay = 100 * time.Millisecond
+
+var (
+	configListenersMu sync.RWMutex
+	configListeners   = make(map[string][]func())
+)
+
+// OnSettingsChange registers f to be called whenever the settings
+// file is changed outside of vidar and reloaded.  f is not called on
+// the UI goroutine.
+func OnSettingsChange(f func()) {
+	onConfigChange(settingsFilename, f)
+}
+
+// OnBindingsChange registers f to be called whenever the key
+// bindings file is changed outside of vidar and reloaded.  f is not
+// called on the UI goroutine.
+func OnBindingsChange(f func()) {
+	onConfigChange(keysFilename, f)
+}
+
+func onConfigChange(name string, f func()) {
+	configListenersMu.Lock()
+	defer configListenersMu.Unlock()
+	configListeners[name] = append(configListeners[name], f)
+}
+
+// WatchConfig watches the config directory and reloads the settings
+// and key bindings files whenever they change.  Changes that vidar
+// makes itself are skipped, since its settings already match them.
+func WatchConfig() error {
+	configs := make(map[string]*config.Config)
+	if settings != nil {
+		configs[settingsFilename] = settings
+	}
+	if bindings != nil {
+		configs[keysFilename] = bindings
+	}
+	w, err := fsw.New()
+	if err != nil {
+		return err
+	}
+	if err := w.Add(defaultConfigDir); err != nil {
+		w.Close()
+		return err
+	}
+	q := fsw.NewQueue(reloadDelay, func(name string) {
+		reloadConfig(name, configs[name])
+	})
+	go func() {
+		defer q.Close()
+		defer w.Close()
+		for {
+			e, err := w.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				log.Printf("WARNING: error from config watcher: %s", err)
+				continue
+			}
+			if filepath.Dir(e.Path) != filepath.Clean(defaultConfigDir) {
+				continue
+			}
+			base := filepath.Base(e.Path)
+			name := strings.TrimSuffix(base, filepath.Ext(base))
+			if _, ok := configs[name]; ok {
+				q.Push(name)
+			}
+		}
+	}()
+	return nil
+}
+
+// reloadConfig reloads c, which is the config file named name, and
+// notifies its listeners if it changed.  Files that can't be parsed
+// are skipped, since they're usually in the middle of being edited.
+func reloadConfig(name string, c *config.Config) {
+	changed, err := c.Reload()
+	if err != nil {
+		log.Printf("Error reloading %s: %s", c.Path(), err)
+		return
+	}
+	if !changed {
+		return
+	}
+	configListenersMu.RLock()
+	defer configListenersMu.RUnlock()
+	for _, f := range configListeners[name] {
+		f()
+	}
+}
@@ -0,0 +1,59 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package setting
+
+import (
+	"log"
+	"path/filepath"
+)
+
+// Dirs returns all of p's root directories: p.Path, followed by
+// p.Roots.  Empty and repeated roots are left out.
+func (p Project) Dirs() []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, d := range append([]string{p.Path}, p.Roots...) {
+		if d == "" {
+			continue
+		}
+		d = filepath.Clean(d)
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+		dirs = append(dirs, d)
+	}
+	return dirs
+}
+
+// RootFor returns the root directory of p that contains path.  If
+// more than one root contains path, the deepest one is returned.
+func (p Project) RootFor(path string) (string, bool) {
+	var root string
+	for _, d := range p.Dirs() {
+		if contains(d, path) && len(d) > len(root) {
+			root = d
+		}
+	}
+	return root, root != ""
+}
+
+// UpdateProject replaces the project with the same name as project
+// and writes the projects file.  It does nothing if there is no
+// such project.
+func UpdateProject(project Project) {
+	projs := append([]Project(nil), Projects()...)
+	for i, p := range projs {
+		if p.Name != project.Name {
+			continue
+		}
+		projs[i] = project
+		projects.Set("projects", projs)
+		if err := projects.Write(); err != nil {
+			log.Printf("Error updating projects file: %s", err)
+		}
+		return
+	}
+}
@@ -0,0 +1,59 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package suggestion
+
+import (
+	"bufio"
+	"bytes"
+	"log"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// importsTTL is how long the import paths listed for a directory are
+// reused before they're listed again.
+const importsTTL = time.Minute
+
+var (
+	importsMu sync.Mutex
+	imports   = make(map[string]importList)
+)
+
+type importList struct {
+	listed time.Time
+	paths  []Suggestion
+}
+
+// ImportPaths returns a suggestion for each package in the standard
+// library and in the module graph of the module that dir is in.
+func ImportPaths(environ []string, dir string) []Suggestion {
+	importsMu.Lock()
+	defer importsMu.Unlock()
+	if l, ok := imports[dir]; ok && time.Since(l.listed) < importsTTL {
+		return l.paths
+	}
+	cmd := exec.Command("go", "list", "-e", "-f", "{{.ImportPath}}", "std", "all")
+	cmd.Dir = dir
+	cmd.Env = environ
+	out, err := cmd.Output()
+	if err != nil && len(out) == 0 {
+		log.Printf("Failed to list import paths in %s: %s", dir, err)
+		return nil
+	}
+	var paths []Suggestion
+	seen := make(map[string]bool)
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		p := s.Text()
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, Suggestion{Name: p, Signature: "package"})
+	}
+	imports[dir] = importList{listed: time.Now(), paths: paths}
+	return paths
+}
@@ -0,0 +1,132 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package literal finds the go string literal that the caret is in,
+// so that it can be completed as an import path or a file path.
+package literal
+
+import (
+	"go/scanner"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Literal is the part of a string literal before the caret.
+type Literal struct {
+	// Start is the rune index of the start of the literal's
+	// contents, just after its opening quote.
+	Start int
+
+	// Text is the literal's contents, up to the caret.
+	Text string
+
+	// Import is whether or not the literal is the path of an import
+	// spec.
+	Import bool
+}
+
+// At returns the string literal in the go source src which contains
+// the rune index pos, if pos is in one.
+func At(src []rune, pos int) (Literal, bool) {
+	if pos > len(src) {
+		return Literal{}, false
+	}
+	prefix := []byte(string(src[:pos]))
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(prefix))
+	var s scanner.Scanner
+	s.Init(file, prefix, nil, 0)
+
+	var (
+		prev          token.Token
+		inImport      bool
+		inImportBlock bool
+	)
+	for {
+		p, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return Literal{}, false
+		}
+		offset := file.Offset(p)
+		if tok == token.STRING && offset+len(lit) == len(prefix) && unterminated(lit) {
+			start := utf8.RuneCount(prefix[:offset+1])
+			return Literal{
+				Start:  start,
+				Text:   lit[1:],
+				Import: inImport || inImportBlock,
+			}, true
+		}
+		switch tok {
+		case token.IMPORT:
+			inImport = true
+		case token.LPAREN:
+			if prev == token.IMPORT {
+				inImport, inImportBlock = false, true
+			}
+		case token.RPAREN:
+			inImportBlock = false
+		case token.SEMICOLON:
+			inImport = false
+		}
+		prev = tok
+	}
+}
+
+// unterminated returns whether or not the string literal lit is
+// missing its closing quote.
+func unterminated(lit string) bool {
+	if len(lit) < 2 {
+		return true
+	}
+	return lit[len(lit)-1] != lit[0] || (lit[0] == '"' && escaped(lit[:len(lit)-1]))
+}
+
+// escaped returns whether or not the rune after s would be escaped by
+// a backslash at the end of s.
+func escaped(s string) bool {
+	n := 0
+	for n < len(s) && s[len(s)-1-n] == '\\' {
+		n++
+	}
+	return n%2 == 1
+}
+
+// LooksLikePath returns whether or not the contents of a string
+// literal look like a file path.
+func LooksLikePath(text string) bool {
+	return strings.Contains(text, "/") || strings.HasPrefix(text, ".")
+}
+
+// Paths returns the names of the entries in the directory that the
+// partial path text is in, along with the byte index in text that
+// the names should replace from.  Relative paths are relative to
+// root.  Directory names end in a slash, and hidden entries are only
+// included if the name being completed starts with a dot.
+func Paths(root, text string) (int, []string) {
+	dir, base := path.Split(text)
+	full := filepath.FromSlash(dir)
+	if !filepath.IsAbs(full) {
+		full = filepath.Join(root, full)
+	}
+	infos, err := ioutil.ReadDir(full)
+	if err != nil {
+		return len(dir), nil
+	}
+	var names []string
+	for _, info := range infos {
+		name := info.Name()
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".") {
+			continue
+		}
+		if info.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	return len(dir), names
+}
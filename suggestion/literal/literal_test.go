@@ -0,0 +1,145 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package literal_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/suggestion/literal"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal   = matchers.Equal
+	beTrue  = matchers.BeTrue
+	beFalse = matchers.BeFalse
+)
+
+// at returns the literal at the '|' in src.
+func at(src string) (literal.Literal, bool) {
+	pos := strings.Index(src, "|")
+	runes := []rune(src[:pos] + src[pos+1:])
+	return literal.At(runes, len([]rune(src[:pos])))
+}
+
+func TestAt(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it finds import paths in import blocks", func(expect expect.Expectation) {
+		l, ok := at("package foo\n\nimport (\n\t\"fmt\"\n\tx \"github.com/nel|\"\n)\n")
+		expect(ok).To(beTrue())
+		expect(l.Import).To(beTrue())
+		expect(l.Text).To(equal("github.com/nel"))
+	})
+
+	o.Spec("it finds single import specs", func(expect expect.Expectation) {
+		l, ok := at("package foo\n\nimport \"o|\"\n")
+		expect(ok).To(beTrue())
+		expect(l.Import).To(beTrue())
+		expect(l.Text).To(equal("o"))
+	})
+
+	o.Spec("it finds other strings", func(expect expect.Expectation) {
+		l, ok := at("package foo\n\nimport \"os\"\n\nfunc f() {\n\tos.Open(\"ü/test|data/a\")\n}\n")
+		expect(ok).To(beTrue())
+		expect(l.Import).To(beFalse())
+		expect(l.Text).To(equal("ü/test"))
+		expect(l.Start).To(equal(strings.Index("package foo\n\nimport \"os\"\n\nfunc f() {\n\tos.Open(\"", "os.Open(\"") + len("os.Open(\"")))
+	})
+
+	o.Spec("it finds raw strings", func(expect expect.Expectation) {
+		l, ok := at("package foo\n\nvar x = `a/\\|`")
+		expect(ok).To(beTrue())
+		expect(l.Text).To(equal(`a/\`))
+	})
+
+	o.Spec("it ignores escaped quotes", func(expect expect.Expectation) {
+		l, ok := at(`package foo; var x = "a\"b|"`)
+		expect(ok).To(beTrue())
+		expect(l.Text).To(equal(`a\"b`))
+	})
+
+	o.Spec("it doesn't find anything outside of strings", func(expect expect.Expectation) {
+		_, ok := at(`package foo; var x = "a"|`)
+		expect(ok).To(beFalse())
+		_, ok = at("package foo; // \"a|\n")
+		expect(ok).To(beFalse())
+		_, ok = at(`package foo; var x = 'a|'`)
+		expect(ok).To(beFalse())
+	})
+}
+
+func TestPaths(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, string) {
+		dir, err := ioutil.TempDir("", "literal")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range []string{"sub/a.txt", "sub/.hidden", "sub/deeper/b.txt"} {
+			p = filepath.Join(dir, filepath.FromSlash(p))
+			if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(p, nil, 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return expect.New(t), dir
+	})
+
+	o.AfterEach(func(expect expect.Expectation, dir string) {
+		os.RemoveAll(dir)
+	})
+
+	o.Spec("it lists the directory of the path", func(expect expect.Expectation, dir string) {
+		start, names := literal.Paths(dir, "sub/a")
+		expect(start).To(equal(len("sub/")))
+		expect(names).To(equal([]string{"a.txt", "deeper/"}))
+	})
+
+	o.Spec("it includes hidden files when asked for them", func(expect expect.Expectation, dir string) {
+		_, names := literal.Paths(dir, "./sub/.")
+		expect(names).To(equal([]string{".hidden", "a.txt", "deeper/"}))
+	})
+
+	o.Spec("it lists absolute paths", func(expect expect.Expectation, dir string) {
+		_, names := literal.Paths("/nowhere", filepath.ToSlash(dir)+"/sub/deeper/")
+		expect(names).To(equal([]string{"b.txt"}))
+	})
+
+	o.Spec("it finds nothing in missing directories", func(expect expect.Expectation, dir string) {
+		_, names := literal.Paths(dir, "missing/")
+		expect(names).To(equal([]string(nil)))
+	})
+}
+
+func TestLooksLikePath(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it matches strings with slashes or leading dots", func(expect expect.Expectation) {
+		expect(literal.LooksLikePath("testdata/")).To(beTrue())
+		expect(literal.LooksLikePath("./")).To(beTrue())
+		expect(literal.LooksLikePath("hello, world")).To(beFalse())
+	})
+}
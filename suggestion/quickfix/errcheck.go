@@ -0,0 +1,127 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package quickfix
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// ErrCheck returns the edits that insert an error check at pos, a
+// rune offset in src.  The check returns err along with the zero
+// values of the enclosing function's other results.  If the caret's
+// line is blank, the check replaces it; otherwise, it's added after
+// the line.
+func ErrCheck(src []rune, pos int) ([]Edit, error) {
+	if pos < 0 || pos > len(src) {
+		return nil, ErrNoFunc
+	}
+	s, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	typ, _ := s.funcAt(len(string(src[:pos])))
+	if typ == nil {
+		return nil, ErrNoFunc
+	}
+	types := results(typ)
+	errIdx := -1
+	for i, t := range types {
+		if isError(t) {
+			errIdx = i
+		}
+	}
+	if errIdx < 0 {
+		return nil, ErrNoErrorResult
+	}
+	decls := s.typeDecls()
+	values := make([]string, 0, len(types))
+	for i, t := range types {
+		if i == errIdx {
+			values = append(values, "err")
+			continue
+		}
+		values = append(values, s.zero(t, decls, nil))
+	}
+
+	start, end := lineBounds(src, pos)
+	line := src[start:end]
+	indent := indentation(line)
+	if blank(line) && indent == "" {
+		if prev := prevLine(src, start); prev >= 0 {
+			_, prevEnd := lineBounds(src, prev)
+			indent = indentation(src[prev:prevEnd])
+			if strings.HasSuffix(strings.TrimSpace(string(src[prev:prevEnd])), "{") {
+				indent += "\t"
+			}
+		}
+	}
+	check := indent + "if err != nil {\n" +
+		indent + "\treturn " + strings.Join(values, ", ") + "\n" +
+		indent + "}"
+	if blank(line) {
+		return []Edit{{Start: start, End: end, Text: check}}, nil
+	}
+	return []Edit{{Start: end, End: end, Text: "\n" + check}}, nil
+}
+
+// typeDecls returns the types declared in s, by name.
+func (s *source) typeDecls() map[string]ast.Expr {
+	decls := make(map[string]ast.Expr)
+	ast.Inspect(s.ast, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok {
+			decls[spec.Name.Name] = spec.Type
+		}
+		return true
+	})
+	return decls
+}
+
+// zero returns the zero value of typ.  Named types are looked up in
+// decls; names that aren't declared in the file are assumed to be
+// structs.  seen holds the names that are being resolved, to stop
+// at recursive declarations.
+func (s *source) zero(typ ast.Expr, decls map[string]ast.Expr, seen map[string]bool) string {
+	switch t := typ.(type) {
+	case *ast.ParenExpr:
+		return s.zero(t.X, decls, seen)
+	case *ast.Ident:
+		switch t.Name {
+		case "bool":
+			return "false"
+		case "string":
+			return `""`
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+			"float32", "float64", "complex64", "complex128",
+			"byte", "rune":
+			return "0"
+		case "error", "any":
+			return "nil"
+		}
+		decl, ok := decls[t.Name]
+		if !ok || seen[t.Name] {
+			return t.Name + "{}"
+		}
+		if seen == nil {
+			seen = make(map[string]bool)
+		}
+		seen[t.Name] = true
+		zero := s.zero(decl, decls, seen)
+		if strings.HasSuffix(zero, "{}") {
+			// Composite literals need the named type, not the
+			// type that it's declared as.
+			return t.Name + "{}"
+		}
+		return zero
+	case *ast.StarExpr, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.InterfaceType:
+		return "nil"
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "nil"
+		}
+	}
+	return s.code(typ) + "{}"
+}
@@ -0,0 +1,275 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package quickfix contains small fixes for go source that can be
+// applied at a caret.
+package quickfix
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// The names of the commands that apply each fix.
+const (
+	ErrCheckName  = "insert-err-check"
+	WrapErrorName = "wrap-error"
+)
+
+var (
+	// ErrNoFunc is returned when the caret isn't in the body of a
+	// function.
+	ErrNoFunc = errors.New("no function found at the caret")
+
+	// ErrNoErrorResult is returned when an error check is requested
+	// in a function that doesn't return an error.
+	ErrNoErrorResult = errors.New("the enclosing function doesn't return an error")
+
+	// ErrNoReturn is returned when there is no returned error at the
+	// caret to wrap.
+	ErrNoReturn = errors.New("no returned error found at the caret")
+
+	// ErrWrapped is returned when the returned error is already
+	// created with fmt.Errorf.
+	ErrWrapped = errors.New("the returned error is already wrapped")
+)
+
+// errWord matches lines that mention err.
+var errWord = regexp.MustCompile(`\berr\b`)
+
+// Edit is a change to go source, in runes.
+type Edit struct {
+	Start, End int
+	Text       string
+}
+
+// Fix is a fix that can be applied to go source.
+type Fix struct {
+	// Name is the name of the command that applies the fix.
+	Name string
+
+	// Description is a short explanation of what the fix does.
+	Description string
+
+	Edits []Edit
+}
+
+// At returns the fixes that make sense with the caret at pos in src.
+// The word from start to pos is the partial word that is being
+// completed.  Fixes that insert code replace it, while fixes that
+// change the code around the caret keep it.
+func At(src []rune, start, pos int) []Fix {
+	if start < 0 || start > pos || pos > len(src) {
+		return nil
+	}
+	var fixes []Fix
+	text := append(append([]rune(nil), src[:start]...), src[pos:]...)
+	if mentionsErr(text, start) {
+		if edits, err := ErrCheck(text, start); err == nil {
+			fixes = append(fixes, Fix{
+				Name:        ErrCheckName,
+				Description: "insert if err != nil { return ... }",
+				Edits:       removePartial(edits, start, pos),
+			})
+		}
+	}
+	if edits, err := WrapError(src, pos); err == nil {
+		fixes = append(fixes, Fix{
+			Name:        WrapErrorName,
+			Description: "wrap the returned error with fmt.Errorf",
+			Edits:       edits,
+		})
+	}
+	if edits, err := RemoveUnused(src, pos); err == nil {
+		fixes = append(fixes, Fix{
+			Name:        RemoveUnusedName,
+			Description: "remove the unused import or variable",
+			Edits:       edits,
+		})
+	}
+	return fixes
+}
+
+// mentionsErr returns whether or not err is mentioned on the line at
+// pos or the last non-blank line before it.
+func mentionsErr(src []rune, pos int) bool {
+	start, end := lineBounds(src, pos)
+	if errWord.MatchString(string(src[start:end])) {
+		return true
+	}
+	prev := prevLine(src, start)
+	return prev >= 0 && errWord.MatchString(string(src[prev:start]))
+}
+
+// removePartial moves edits that were made to source without the
+// partial word from start to end so that they apply to the source
+// with it, and makes sure that the partial word is removed.
+func removePartial(edits []Edit, start, end int) []Edit {
+	n := end - start
+	if n == 0 {
+		return edits
+	}
+	covered := false
+	moved := make([]Edit, 0, len(edits)+1)
+	for _, e := range edits {
+		switch {
+		case e.Start >= start:
+			e.Start += n
+			e.End += n
+		case e.End >= start:
+			e.End += n
+			covered = true
+		}
+		moved = append(moved, e)
+	}
+	if !covered {
+		moved = append(moved, Edit{Start: start, End: end})
+		sort.Slice(moved, func(i, j int) bool {
+			return moved[i].Start < moved[j].Start
+		})
+	}
+	return moved
+}
+
+// source is parsed go source.
+type source struct {
+	text string
+	file *token.File
+	ast  *ast.File
+}
+
+// parse parses src.  Incomplete source is allowed, as long as the
+// parser can make sense of the function around the caret.
+func parse(src []rune) (*source, error) {
+	text := string(src)
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", text, 0)
+	if f == nil {
+		return nil, err
+	}
+	return &source{text: text, file: fset.File(f.Pos()), ast: f}, nil
+}
+
+// offset returns the byte offset of p in s.
+func (s *source) offset(p token.Pos) int {
+	return s.file.Offset(p)
+}
+
+// contains returns whether or not n contains the byte offset, counting
+// its end.
+func (s *source) contains(n ast.Node, offset int) bool {
+	return s.offset(n.Pos()) <= offset && offset <= s.offset(n.End())
+}
+
+// code returns the source text of n.
+func (s *source) code(n ast.Node) string {
+	return s.text[s.offset(n.Pos()):s.offset(n.End())]
+}
+
+// runes returns the rune offset of the byte offset.
+func (s *source) runes(offset int) int {
+	return utf8.RuneCountInString(s.text[:offset])
+}
+
+// funcAt returns the type of the innermost function whose body
+// contains offset, along with the function declaration that it's
+// part of.  The declaration is nil for function literals outside of
+// declarations.
+func (s *source) funcAt(offset int) (*ast.FuncType, *ast.FuncDecl) {
+	var (
+		typ  *ast.FuncType
+		decl *ast.FuncDecl
+	)
+	ast.Inspect(s.ast, func(n ast.Node) bool {
+		if n == nil || !s.contains(n, offset) {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Body != nil && s.contains(n.Body, offset) {
+				typ, decl = n.Type, n
+			}
+		case *ast.FuncLit:
+			if s.contains(n.Body, offset) {
+				typ = n.Type
+			}
+		}
+		return true
+	})
+	return typ, decl
+}
+
+// results returns the result types of typ, with one entry per result
+// even when names share a type.
+func results(typ *ast.FuncType) []ast.Expr {
+	if typ.Results == nil {
+		return nil
+	}
+	var types []ast.Expr
+	for _, field := range typ.Results.List {
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			types = append(types, field.Type)
+		}
+	}
+	return types
+}
+
+// isError returns whether or not expr is the error type.
+func isError(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+	return ok && id.Name == "error"
+}
+
+// lineBounds returns the rune offsets of the start and end of the line
+// that pos is on, not counting the newline.
+func lineBounds(src []rune, pos int) (start, end int) {
+	start, end = pos, pos
+	for start > 0 && src[start-1] != '\n' {
+		start--
+	}
+	for end < len(src) && src[end] != '\n' {
+		end++
+	}
+	return start, end
+}
+
+// prevLine returns the start of the last non-blank line before the
+// line that starts at lineStart, or -1 if there isn't one.
+func prevLine(src []rune, lineStart int) int {
+	for lineStart > 0 {
+		start, end := lineBounds(src, lineStart-1)
+		if !blank(src[start:end]) {
+			return start
+		}
+		lineStart = start
+	}
+	return -1
+}
+
+func blank(line []rune) bool {
+	for _, r := range line {
+		if r != ' ' && r != '\t' && r != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// indentation returns the leading whitespace of line.
+func indentation(line []rune) string {
+	i := 0
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	return string(line[:i])
+}
@@ -0,0 +1,284 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package quickfix_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/suggestion/quickfix"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal        = matchers.Equal
+	haveLen      = matchers.HaveLen
+	haveOccurred = matchers.HaveOccurred
+	not          = matchers.Not
+)
+
+// apply applies edits to src.
+func apply(src []rune, edits []quickfix.Edit) string {
+	for i := len(edits) - 1; i >= 0; i-- {
+		e := edits[i]
+		src = append(src[:e.Start:e.Start], append([]rune(e.Text), src[e.End:]...)...)
+	}
+	return string(src)
+}
+
+// run runs fix at the '|' in src and returns the result of applying
+// its edits.
+func run(src string, fix func([]rune, int) ([]quickfix.Edit, error)) (string, error) {
+	pos := strings.Index(src, "|")
+	runes := []rune(src[:pos] + src[pos+1:])
+	edits, err := fix(runes, len([]rune(src[:pos])))
+	return apply(runes, edits), err
+}
+
+func TestErrCheck(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it fills a blank line with zero values for the results", func(expect expect.Expectation) {
+		out, err := run("package foo\n\ntype T struct{}\n\ntype ID int\n\nfunc f() (*T, T, ID, []byte, string, bool, error) {\n\tx, err := g()\n\t|\n}\n", quickfix.ErrCheck)
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\ntype T struct{}\n\ntype ID int\n\nfunc f() (*T, T, ID, []byte, string, bool, error) {\n\tx, err := g()\n\tif err != nil {\n\t\treturn nil, T{}, 0, nil, \"\", false, err\n\t}\n}\n"))
+	})
+
+	o.Spec("it adds the check after a line with code", func(expect expect.Expectation) {
+		out, err := run("package foo\n\nfunc f() (a, b int, err error) {\n\tx, err :=| g()\n\treturn x, x, nil\n}\n", quickfix.ErrCheck)
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\nfunc f() (a, b int, err error) {\n\tx, err := g()\n\tif err != nil {\n\t\treturn 0, 0, err\n\t}\n\treturn x, x, nil\n}\n"))
+	})
+
+	o.Spec("it indents unindented blank lines", func(expect expect.Expectation) {
+		out, err := run("package foo\n\nfunc f() error {\n|\n}\n", quickfix.ErrCheck)
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\nfunc f() error {\n\tif err != nil {\n\t\treturn err\n\t}\n}\n"))
+	})
+
+	o.Spec("it uses the innermost function literal", func(expect expect.Expectation) {
+		out, err := run("package foo\n\nfunc f() (int, error) {\n\tg := func() (time.Time, error) {\n\t\t|\n\t}\n}\n", quickfix.ErrCheck)
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\nfunc f() (int, error) {\n\tg := func() (time.Time, error) {\n\t\tif err != nil {\n\t\t\treturn time.Time{}, err\n\t\t}\n\t}\n}\n"))
+	})
+
+	o.Spec("it fails in functions that don't return errors", func(expect expect.Expectation) {
+		_, err := run("package foo\n\nfunc f() int {\n\t|\n}\n", quickfix.ErrCheck)
+		expect(err).To(equal(quickfix.ErrNoErrorResult))
+	})
+
+	o.Spec("it fails outside of functions", func(expect expect.Expectation) {
+		_, err := run("package foo\n\nvar x| = 1\n", quickfix.ErrCheck)
+		expect(err).To(equal(quickfix.ErrNoFunc))
+	})
+}
+
+func TestWrapError(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it wraps the last result and adds the fmt import", func(expect expect.Expectation) {
+		out, err := run("package foo\n\nimport (\n\t\"os\"\n)\n\nfunc (t *T) Load() (*os.File, error) {\n\tf, err := os.Open(\"x\")\n\tret|urn nil, err\n}\n", quickfix.WrapError)
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc (t *T) Load() (*os.File, error) {\n\tf, err := os.Open(\"x\")\n\treturn nil, fmt.Errorf(\"T.Load: %w\", err)\n}\n"))
+	})
+
+	o.Spec("it wraps the result under the caret with the existing import", func(expect expect.Expectation) {
+		out, err := run("package foo\n\nimport f \"fmt\"\n\nfunc g() (error, int) {\n\treturn e|rr, 0\n}\n", quickfix.WrapError)
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\nimport f \"fmt\"\n\nfunc g() (error, int) {\n\treturn f.Errorf(\"g: %w\", err), 0\n}\n"))
+	})
+
+	o.Spec("it adds an import to files without any", func(expect expect.Expectation) {
+		out, err := run("package foo\n\nfunc g() error {\n\treturn err|\n}\n", quickfix.WrapError)
+		expect(err).To(not(haveOccurred()))
+		expect(out).To(equal("package foo\n\nimport \"fmt\"\n\nfunc g() error {\n\treturn fmt.Errorf(\"g: %w\", err)\n}\n"))
+	})
+
+	o.Spec("it doesn't wrap nil or wrapped errors", func(expect expect.Expectation) {
+		_, err := run("package foo\n\nfunc g() error {\n\treturn nil|\n}\n", quickfix.WrapError)
+		expect(err).To(equal(quickfix.ErrNoReturn))
+
+		_, err = run("package foo\n\nfunc g() error {\n\treturn fmt.Errorf(\"g: %w\", err)|\n}\n", quickfix.WrapError)
+		expect(err).To(equal(quickfix.ErrWrapped))
+	})
+}
+
+func TestAt(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it offers an error check after err is assigned and removes the partial word", func(expect expect.Expectation) {
+		src := []rune("package foo\n\nfunc f() error {\n\terr := g()\n\tife\n}\n")
+		pos := strings.Index(string(src), "ife") + len("ife")
+		fixes := quickfix.At(src, pos-len("ife"), pos)
+		expect(fixes).To(haveLen(1))
+		expect(fixes[0].Name).To(equal(quickfix.ErrCheckName))
+		expect(apply(src, fixes[0].Edits)).To(equal("package foo\n\nfunc f() error {\n\terr := g()\n\tif err != nil {\n\t\treturn err\n\t}\n}\n"))
+	})
+
+	o.Spec("it offers to wrap returned errors", func(expect expect.Expectation) {
+		src := []rune("package foo\n\nimport \"fmt\"\n\nfunc f() error {\n\treturn err\n}\n")
+		pos := strings.Index(string(src), "return") + len("return")
+		fixes := quickfix.At(src, pos, pos)
+		expect(fixes).To(haveLen(2))
+		expect(fixes[1].Name).To(equal(quickfix.WrapErrorName))
+		expect(apply(src, fixes[1].Edits)).To(equal("package foo\n\nimport \"fmt\"\n\nfunc f() error {\n\treturn fmt.Errorf(\"f: %w\", err)\n}\n"))
+	})
+
+	o.Spec("it keeps the partial word when wrapping errors", func(expect expect.Expectation) {
+		src := []rune("package foo\n\nimport \"fmt\"\n\nfunc f() error {\n\treturn err\n}\n")
+		pos := strings.Index(string(src), "err\n") + len("err")
+		fixes := quickfix.At(src, pos-len("err"), pos)
+		expect(fixes).To(haveLen(1))
+		expect(fixes[0].Name).To(equal(quickfix.WrapErrorName))
+		expect(apply(src, fixes[0].Edits)).To(equal("package foo\n\nimport \"fmt\"\n\nfunc f() error {\n\treturn fmt.Errorf(\"f: %w\", err)\n}\n"))
+	})
+
+	o.Spec("it offers nothing where err isn't used", func(expect expect.Expectation) {
+		src := []rune("package foo\n\nfunc f() error {\n\tx := 1\n\t\n}\n")
+		pos := strings.Index(string(src), "\t\n")
+		expect(quickfix.At(src, pos, pos)).To(haveLen(0))
+	})
+
+	o.Spec("it offers to remove unused variables", func(expect expect.Expectation) {
+		src := []rune("package foo\n\nfunc f() {\n\tx := 1\n}\n")
+		pos := strings.Index(string(src), "x")
+		fixes := quickfix.At(src, pos, pos)
+		expect(fixes).To(haveLen(1))
+		expect(fixes[0].Name).To(equal(quickfix.RemoveUnusedName))
+		expect(apply(src, fixes[0].Edits)).To(equal("package foo\n\nfunc f() {\n}\n"))
+	})
+}
+
+func TestFindUnused(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it finds unused imports and variables", func(expect expect.Expectation) {
+		src := "package foo\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc f() {\n\tx, y := 1, 2\n\tfmt.Println(y)\n}\n"
+		unused := quickfix.FindUnused([]rune(src))
+		expect(unused).To(haveLen(2))
+		expect(unused[0].Kind).To(equal(quickfix.UnusedImport))
+		expect(unused[0].Name).To(equal("os"))
+		expect(src[unused[0].Start:unused[0].End]).To(equal(`"os"`))
+		expect(unused[0].Message()).To(equal(`"os" is imported but not used`))
+		expect(unused[1].Kind).To(equal(quickfix.UnusedVar))
+		expect(src[unused[1].Start:unused[1].End]).To(equal("x"))
+		expect(unused[1].Message()).To(equal("x is declared but not used"))
+	})
+
+	o.Spec("it counts packages from the rest of the project as used", func(expect expect.Expectation) {
+		src := "package foo\n\nimport \"github.com/nelsam/gxui/math\"\n\nvar v = math.Point{X: undefined}\n"
+		expect(quickfix.FindUnused([]rune(src))).To(haveLen(0))
+	})
+
+	o.Spec("it leaves imports that may be named differently alone", func(expect expect.Expectation) {
+		src := "package foo\n\nimport \"gopkg.in/yaml.v2\"\n\nvar v, _ = yaml.Marshal(nil)\n"
+		expect(quickfix.FindUnused([]rune(src))).To(haveLen(0))
+
+		src = "package foo\n\nimport \"gopkg.in/yaml.v2\"\n"
+		expect(quickfix.FindUnused([]rune(src))).To(haveLen(1))
+	})
+
+	o.Spec("it ignores sources that can't be parsed", func(expect expect.Expectation) {
+		expect(quickfix.FindUnused([]rune("package foo\n\nfunc f() {\n\tx := \n}\n"))).To(haveLen(0))
+	})
+}
+
+func TestRemoveUnused(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	for _, test := range []struct {
+		name, src, out string
+	}{
+		{
+			name: "it removes imports from a group",
+			src:  "package foo\n\nimport (\n\t\"fmt\"\n\t\"o|s\"\n)\n\nvar _ = fmt.Sprint\n",
+			out:  "package foo\n\nimport (\n\t\"fmt\"\n)\n\nvar _ = fmt.Sprint\n",
+		},
+		{
+			name: "it removes single imports",
+			src:  "package foo\n\nimport |\"os\"\n\nfunc f() {}\n",
+			out:  "package foo\n\n\nfunc f() {}\n",
+		},
+		{
+			name: "it removes assignments without side effects",
+			src:  "package foo\n\nfunc f() {\n\t|x := []int{1}\n\treturn\n}\n",
+			out:  "package foo\n\nfunc f() {\n\treturn\n}\n",
+		},
+		{
+			name: "it keeps calls",
+			src:  "package foo\n\nfunc f() {\n\tx| := g()\n}\n",
+			out:  "package foo\n\nfunc f() {\n\t_ = g()\n}\n",
+		},
+		{
+			name: "it blanks one of several names",
+			src:  "package foo\n\nfunc f() {\n\tx, |y := g()\n\tprintln(x)\n}\n",
+			out:  "package foo\n\nfunc f() {\n\tx, _ := g()\n\tprintln(x)\n}\n",
+		},
+		{
+			name: "it stops declaring when every name is blank",
+			src:  "package foo\n\nfunc f() {\n\t_, |y := g()\n}\n",
+			out:  "package foo\n\nfunc f() {\n\t_, _ = g()\n}\n",
+		},
+		{
+			name: "it removes var declarations",
+			src:  "package foo\n\nfunc f() {\n\tvar |x int\n}\n",
+			out:  "package foo\n\nfunc f() {\n}\n",
+		},
+		{
+			name: "it removes range keys",
+			src:  "package foo\n\nfunc f(xs []int) {\n\tfor |i := range xs {\n\t}\n}\n",
+			out:  "package foo\n\nfunc f(xs []int) {\n\tfor range xs {\n\t}\n}\n",
+		},
+		{
+			name: "it removes range values",
+			src:  "package foo\n\nfunc f(xs []int) {\n\tfor i, |v := range xs {\n\t\tprintln(i)\n\t}\n}\n",
+			out:  "package foo\n\nfunc f(xs []int) {\n\tfor i := range xs {\n\t\tprintln(i)\n\t}\n}\n",
+		},
+		{
+			name: "it removes type switch variables",
+			src:  "package foo\n\nfunc f(v interface{}) {\n\tswitch |x := v.(type) {\n\tcase int:\n\t}\n}\n",
+			out:  "package foo\n\nfunc f(v interface{}) {\n\tswitch v.(type) {\n\tcase int:\n\t}\n}\n",
+		},
+	} {
+		test := test
+		o.Spec(test.name, func(expect expect.Expectation) {
+			out, err := run(test.src, quickfix.RemoveUnused)
+			expect(err).To(not(haveOccurred()))
+			expect(out).To(equal(test.out))
+		})
+	}
+
+	o.Spec("it fails away from unused names", func(expect expect.Expectation) {
+		_, err := run("package foo\n\nfunc f() {\n\tx := 1\n\t|println()\n}\n", quickfix.RemoveUnused)
+		expect(err).To(equal(quickfix.ErrNotUnused))
+	})
+}
@@ -0,0 +1,349 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package quickfix
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RemoveUnusedName is the name of the command that removes an unused
+// import or variable.
+const RemoveUnusedName = "remove-unused"
+
+// ErrNotUnused is returned when there is no unused import or variable
+// at the caret.
+var ErrNotUnused = errors.New("no unused import or variable found at the caret")
+
+var (
+	unusedImport = regexp.MustCompile(`imported (and|but) not used`)
+	unusedVar    = regexp.MustCompile(`declared (and|but) not used`)
+	versionElem  = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+// UnusedKind is the kind of thing that is unused.
+type UnusedKind int
+
+const (
+	UnusedImport UnusedKind = iota
+	UnusedVar
+)
+
+// Unused is an import or local variable that the compiler would
+// reject because it's never used.
+type Unused struct {
+	Kind UnusedKind
+
+	// Name is the path of an import or the name of a variable.
+	Name string
+
+	// Start and End are the rune offsets of the import spec or the
+	// variable's name.
+	Start, End int
+
+	// Edits remove the import or variable.
+	Edits []Edit
+}
+
+// Message describes u, e.g. for a diagnostic.
+func (u Unused) Message() string {
+	if u.Kind == UnusedImport {
+		return fmt.Sprintf("%s is imported but not used", strconv.Quote(u.Name))
+	}
+	return fmt.Sprintf("%s is declared but not used", u.Name)
+}
+
+// FindUnused type checks src, a go file, and returns its unused
+// imports and local variables in order.  Imports and the rest of the
+// package are unknown, so src is checked on its own; imports whose
+// package name can't be guessed from their path are assumed to be
+// used if anything refers to an unknown package.  Nothing is returned
+// if src can't be parsed.
+func FindUnused(src []rune) []Unused {
+	text := string(src)
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", text, 0)
+	if err != nil {
+		return nil
+	}
+	s := &source{text: text, file: fset.File(f.Pos()), ast: f}
+
+	var errs []types.Error
+	conf := types.Config{
+		Importer: guessImporter{},
+		Error: func(err error) {
+			if e, ok := err.(types.Error); ok {
+				errs = append(errs, e)
+			}
+		},
+	}
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	conf.Check(f.Name.Name, fset, []*ast.File{f}, info)
+
+	unknown := unknownPackages(f, info)
+	var found []Unused
+	for _, e := range errs {
+		offset := s.offset(e.Pos)
+		switch {
+		case unusedImport.MatchString(e.Msg):
+			if u, ok := s.unusedImport(offset, unknown); ok {
+				found = append(found, u)
+			}
+		case unusedVar.MatchString(e.Msg):
+			if u, ok := s.unusedVar(offset); ok {
+				found = append(found, u)
+			}
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Start < found[j].Start
+	})
+	return found
+}
+
+// RemoveUnused returns the edits that remove the unused import or
+// variable at pos, a rune offset in src.
+func RemoveUnused(src []rune, pos int) ([]Edit, error) {
+	if pos < 0 || pos > len(src) {
+		return nil, ErrNotUnused
+	}
+	for _, u := range FindUnused(src) {
+		if u.Start <= pos && pos <= u.End {
+			return u.Edits, nil
+		}
+	}
+	return nil, ErrNotUnused
+}
+
+// unknownPackages returns whether or not anything in f is selected
+// from a name that isn't declared, e.g. a package that is imported
+// under a different name than its path suggests.
+func unknownPackages(f *ast.File, info *types.Info) bool {
+	unknown := false
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return !unknown
+		}
+		if id, ok := sel.X.(*ast.Ident); ok && info.Uses[id] == nil && info.Defs[id] == nil {
+			unknown = true
+		}
+		return !unknown
+	})
+	return unknown
+}
+
+// unusedImport returns the unused import whose spec is at offset.
+func (s *source) unusedImport(offset int, unknown bool) (Unused, bool) {
+	for _, decl := range s.ast.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			imp := spec.(*ast.ImportSpec)
+			if !s.contains(imp, offset) {
+				continue
+			}
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil || imp.Name == nil && unknown && !guessable(path) {
+				return Unused{}, false
+			}
+			var remove ast.Node = imp
+			if len(gen.Specs) == 1 {
+				remove = gen
+			}
+			return Unused{
+				Kind:  UnusedImport,
+				Name:  path,
+				Start: s.runes(s.offset(imp.Pos())),
+				End:   s.runes(s.offset(imp.End())),
+				Edits: []Edit{s.removeLines(remove)},
+			}, true
+		}
+	}
+	return Unused{}, false
+}
+
+// unusedVar returns the unused variable whose name is at offset.
+func (s *source) unusedVar(offset int) (Unused, bool) {
+	var (
+		id             *ast.Ident
+		stack, parents []ast.Node
+	)
+	ast.Inspect(s.ast, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return false
+		}
+		if id != nil || !s.contains(n, offset) {
+			return false
+		}
+		if i, ok := n.(*ast.Ident); ok && s.offset(i.Pos()) == offset {
+			id = i
+			parents = append([]ast.Node(nil), stack...)
+			return false
+		}
+		stack = append(stack, n)
+		return true
+	})
+	if id == nil || len(parents) == 0 {
+		return Unused{}, false
+	}
+	return Unused{
+		Kind:  UnusedVar,
+		Name:  id.Name,
+		Start: s.runes(s.offset(id.Pos())),
+		End:   s.runes(s.offset(id.End())),
+		Edits: s.removeVar(id, parents),
+	}, true
+}
+
+// removeVar returns the edits that remove the variable named by id,
+// whose parents in the syntax tree are parents (outermost first).
+func (s *source) removeVar(id *ast.Ident, parents []ast.Node) []Edit {
+	blank := []Edit{s.replace(id.Pos(), id.End(), "_")}
+	switch p := parents[len(parents)-1].(type) {
+	case *ast.AssignStmt:
+		if len(parents) > 1 {
+			if sw, ok := parents[len(parents)-2].(*ast.TypeSwitchStmt); ok && sw.Assign == p {
+				// switch x := y.(type) becomes switch y.(type).
+				return []Edit{s.replace(p.Lhs[0].Pos(), p.Rhs[0].Pos(), "")}
+			}
+		}
+		if len(p.Lhs) == 1 {
+			if pure(p.Rhs...) {
+				return []Edit{s.removeLines(p)}
+			}
+			return []Edit{s.replace(p.Lhs[0].Pos(), p.TokPos+token.Pos(len(p.Tok.String())), "_ =")}
+		}
+		for _, lhs := range p.Lhs {
+			if l, ok := lhs.(*ast.Ident); ok && l != id && l.Name != "_" {
+				return blank
+			}
+		}
+		// Every other name is blank, so nothing is declared any more.
+		return append(blank, s.replace(p.TokPos, p.TokPos+token.Pos(len(p.Tok.String())), "="))
+	case *ast.ValueSpec:
+		if len(p.Names) > 1 || !pure(p.Values...) {
+			return blank
+		}
+		var remove ast.Node = p
+		if len(parents) > 1 {
+			if gen, ok := parents[len(parents)-2].(*ast.GenDecl); ok && len(gen.Specs) == 1 {
+				remove = gen
+				if len(parents) > 2 {
+					if decl, ok := parents[len(parents)-3].(*ast.DeclStmt); ok {
+						remove = decl
+					}
+				}
+			}
+		}
+		return []Edit{s.removeLines(remove)}
+	case *ast.RangeStmt:
+		if p.Key == id {
+			if p.Value == nil || isBlank(p.Value) {
+				// for i := range x becomes for range x.
+				return []Edit{s.replace(p.Key.Pos(), p.Range, "")}
+			}
+			return blank
+		}
+		if isBlank(p.Key) {
+			return []Edit{s.replace(p.Key.Pos(), p.Range, "")}
+		}
+		return []Edit{s.replace(p.Key.End(), p.Value.End(), "")}
+	}
+	return blank
+}
+
+// replace returns an edit that replaces the source from start to end
+// with text.
+func (s *source) replace(start, end token.Pos, text string) Edit {
+	return Edit{Start: s.runes(s.offset(start)), End: s.runes(s.offset(end)), Text: text}
+}
+
+// removeLines returns an edit that removes n.  If n is the only thing
+// on its lines, the lines are removed along with it.
+func (s *source) removeLines(n ast.Node) Edit {
+	start, end := s.offset(n.Pos()), s.offset(n.End())
+	lineStart := strings.LastIndexByte(s.text[:start], '\n') + 1
+	lineEnd := len(s.text)
+	if i := strings.IndexByte(s.text[end:], '\n'); i >= 0 {
+		lineEnd = end + i + 1
+	}
+	if strings.TrimSpace(s.text[lineStart:start]) == "" && strings.TrimSpace(s.text[end:lineEnd]) == "" {
+		start, end = lineStart, lineEnd
+	}
+	return Edit{Start: s.runes(start), End: s.runes(end)}
+}
+
+// pure returns whether or not exprs can be removed without changing
+// what the program does, i.e. they don't call anything or receive
+// from channels.  Conversions are treated as calls.
+func pure(exprs ...ast.Expr) bool {
+	for _, e := range exprs {
+		ok := true
+		ast.Inspect(e, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				ok = false
+			case *ast.UnaryExpr:
+				if n.Op == token.ARROW {
+					ok = false
+				}
+			case *ast.FuncLit:
+				return false
+			}
+			return ok
+		})
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func isBlank(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == "_"
+}
+
+// guessName returns the likely name of the package at path: its last
+// element, skipping major version elements like v2.
+func guessName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && versionElem.MatchString(name) {
+		name = elems[len(elems)-2]
+	}
+	return name
+}
+
+// guessable returns whether or not the name of the package at path is
+// most likely the one that guessName returns.
+func guessable(path string) bool {
+	return token.IsIdentifier(guessName(path)) && !strings.HasPrefix(path, "gopkg.in/")
+}
+
+// guessImporter imports every package as an empty package, named by
+// guessName.
+type guessImporter struct{}
+
+func (guessImporter) Import(path string) (*types.Package, error) {
+	pkg := types.NewPackage(path, guessName(path))
+	pkg.MarkComplete()
+	return pkg, nil
+}
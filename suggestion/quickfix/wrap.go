@@ -0,0 +1,129 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package quickfix
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+)
+
+// WrapError returns the edits that wrap the error returned by the
+// return statement at pos, a rune offset in src, with fmt.Errorf and
+// %w.  The message is prefixed with the name of the enclosing
+// function.  If the caret is on one of the returned values, that
+// value is wrapped; otherwise, the last one is.  The fmt import is
+// added if the file doesn't have it.
+func WrapError(src []rune, pos int) ([]Edit, error) {
+	if pos < 0 || pos > len(src) {
+		return nil, ErrNoReturn
+	}
+	s, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	offset := len(string(src[:pos]))
+	ret := s.returnAt(offset)
+	if ret == nil || len(ret.Results) == 0 {
+		return nil, ErrNoReturn
+	}
+	value := ret.Results[len(ret.Results)-1]
+	for _, r := range ret.Results {
+		if s.contains(r, offset) {
+			value = r
+			break
+		}
+	}
+	switch v := value.(type) {
+	case *ast.Ident:
+		if v.Name == "nil" {
+			return nil, ErrNoReturn
+		}
+	case *ast.BasicLit, *ast.CompositeLit, *ast.FuncLit:
+		return nil, ErrNoReturn
+	case *ast.CallExpr:
+		if s.code(v.Fun) == "fmt.Errorf" {
+			return nil, ErrWrapped
+		}
+	}
+
+	fmtName, importEdit := s.fmtImport()
+	msg := "%w"
+	if _, decl := s.funcAt(offset); decl != nil {
+		msg = funcName(decl) + ": " + msg
+	}
+	edits := []Edit{{
+		Start: s.runes(s.offset(value.Pos())),
+		End:   s.runes(s.offset(value.End())),
+		Text:  fmtName + ".Errorf(" + strconv.Quote(msg) + ", " + s.code(value) + ")",
+	}}
+	if importEdit != nil {
+		edits = append([]Edit{*importEdit}, edits...)
+	}
+	return edits, nil
+}
+
+// returnAt returns the innermost return statement that contains
+// offset.
+func (s *source) returnAt(offset int) *ast.ReturnStmt {
+	var found *ast.ReturnStmt
+	ast.Inspect(s.ast, func(n ast.Node) bool {
+		if n == nil || !s.contains(n, offset) {
+			return false
+		}
+		if ret, ok := n.(*ast.ReturnStmt); ok {
+			found = ret
+		}
+		return true
+	})
+	return found
+}
+
+// fmtImport returns the name that fmt is imported as in s.  If s
+// doesn't import fmt, the edit that adds the import is returned as
+// well.
+func (s *source) fmtImport() (string, *Edit) {
+	for _, imp := range s.ast.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err != nil || path != "fmt" {
+			continue
+		}
+		if imp.Name == nil {
+			return "fmt", nil
+		}
+		if imp.Name.Name != "_" && imp.Name.Name != "." {
+			return imp.Name.Name, nil
+		}
+	}
+	for _, d := range s.ast.Decls {
+		gen, ok := d.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if gen.Lparen.IsValid() {
+			at := s.runes(s.offset(gen.Lparen) + 1)
+			return "fmt", &Edit{Start: at, End: at, Text: "\n\t\"fmt\""}
+		}
+		at := s.runes(s.offset(gen.Pos()))
+		return "fmt", &Edit{Start: at, End: at, Text: "import \"fmt\"\n"}
+	}
+	at := s.runes(s.offset(s.ast.Name.End()))
+	return "fmt", &Edit{Start: at, End: at, Text: "\n\nimport \"fmt\""}
+}
+
+// funcName returns the name of decl, prefixed with its receiver's
+// type for methods.
+func funcName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+	recv := decl.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if id, ok := recv.(*ast.Ident); ok {
+		return id.Name + "." + decl.Name.Name
+	}
+	return decl.Name.Name
+}
@@ -0,0 +1,55 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package suggestion
+
+import "sync"
+
+const (
+	// maxRecent is the number of suggestions that Recent
+	// remembers.
+	maxRecent = 100
+
+	// recentWeight is the score bonus for the most recently used
+	// suggestion.  Older suggestions get less of a bonus.
+	recentWeight = 5
+)
+
+// Recent tracks the names of the suggestions that were used most
+// recently, so that they can be ranked higher the next time they
+// match.  The zero value is ready to use.
+type Recent struct {
+	mu    sync.Mutex
+	names []string
+}
+
+// Use records that the suggestion called name was used.
+func (r *Recent) Use(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, n := range r.names {
+		if n == name {
+			r.names = append(r.names[:i], r.names[i+1:]...)
+			break
+		}
+	}
+	r.names = append(r.names, name)
+	if len(r.names) > maxRecent {
+		r.names = r.names[len(r.names)-maxRecent:]
+	}
+}
+
+// Bonus returns the amount that name's score should be reduced by,
+// which is highest for the most recently used name and zero for names
+// that haven't been used.
+func (r *Recent) Bonus(name string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.names) - 1; i >= 0; i-- {
+		if r.names[i] == name {
+			return recentWeight * float64(i+1) / float64(len(r.names))
+		}
+	}
+	return 0
+}
@@ -10,6 +10,10 @@ import "fmt"
 type Suggestion struct {
 	Name      string
 	Signature string
+
+	// Doc is the suggestion's documentation, if the source of
+	// the suggestion provides it.
+	Doc string
 }
 
 // String handles displaying the suggestion.
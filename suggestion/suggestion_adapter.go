@@ -26,6 +26,10 @@ type Adapter struct {
 	suggestions []Suggestion
 	scores      []float64
 	end         int
+
+	// Recent, if set, is used to rank the suggestions that were
+	// used recently above others that match about as well.
+	Recent *Recent
 }
 
 func (a *Adapter) Set(pos int, suggestions ...Suggestion) {
@@ -43,10 +47,14 @@ func (a *Adapter) Pos() int {
 func (a *Adapter) Sort(partial []rune) (longest int) {
 	a.scores = make([]float64, len(a.suggestions))
 	for i, suggestion := range a.suggestions {
-		a.scores[i] = scoring.Score([]rune(suggestion.Name), partial)
-		if a.scores[i] != math.MaxFloat64 {
-			longest = len([]rune(suggestion.String()))
+		a.scores[i] = scoring.Fuzzy([]rune(suggestion.Name), partial)
+		if a.scores[i] == math.MaxFloat64 {
+			continue
+		}
+		if a.Recent != nil {
+			a.scores[i] -= a.Recent.Bonus(suggestion.Name)
 		}
+		longest = len([]rune(suggestion.String()))
 	}
 
 	a.end = len(a.suggestions)
@@ -0,0 +1,66 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package suggestion
+
+import "unicode"
+
+// minWordLength is the length of the shortest word that Words
+// suggests.  Shorter words are quicker to type than to pick.
+const minWordLength = 3
+
+// Words returns a suggestion for each distinct word in text and in
+// others, which should be the text of other open files of the same
+// type.  The word that starts at start in text is left out, since it
+// is the one being completed.
+func Words(text []rune, start int, others ...[]rune) []Suggestion {
+	seen := make(map[string]bool)
+	var words []Suggestion
+	add := func(t []rune, skip int) {
+		for i := 0; i < len(t); {
+			if !wordPart(t[i]) {
+				i++
+				continue
+			}
+			end := i
+			for end < len(t) && wordPart(t[end]) {
+				end++
+			}
+			w := string(t[i:end])
+			if i != skip && end-i >= minWordLength && !unicode.IsDigit(t[i]) && !seen[w] {
+				seen[w] = true
+				words = append(words, Suggestion{Name: w, Signature: "word"})
+			}
+			i = end
+		}
+	}
+	add(text, start)
+	for _, o := range others {
+		add(o, -1)
+	}
+	return words
+}
+
+func wordPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+// Merge returns the suggestions in lists, leaving out any suggestion
+// with the same name as one in an earlier list.  Language-aware
+// suggestions should come first, since they have more details than
+// e.g. the suggestions from Words.
+func Merge(lists ...[]Suggestion) []Suggestion {
+	seen := make(map[string]bool)
+	var merged []Suggestion
+	for _, l := range lists {
+		for _, s := range l {
+			if seen[s.Name] {
+				continue
+			}
+			seen[s.Name] = true
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,68 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package syntax
+
+import (
+	"go/ast"
+	"go/token"
+	"unicode/utf8"
+
+	"github.com/nelsam/vidar/commander/input"
+)
+
+// Folds returns the regions of the source passed to s.Parse that can
+// be folded: function bodies, composite literals, import blocks, and
+// comments.  Regions that are too short to hide any lines are left
+// out.
+func (s *Syntax) Folds() []input.Fold {
+	return s.folds
+}
+
+func (s *Syntax) addFolds(f *ast.File, source string) {
+	for _, c := range f.Comments {
+		end := s.fileSet.Position(c.End()).Offset
+		if end < len(source) && source[end] == '\n' {
+			// Comments hide their last line, too, so they end at the
+			// start of the next line.
+			end++
+		}
+		s.addFold(input.FoldComment, c.Pos(), s.fileSet.File(c.Pos()).Pos(end), source)
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch src := n.(type) {
+		case *ast.FuncDecl:
+			if src.Body != nil {
+				s.addFold(input.FoldFunc, src.Body.Lbrace, src.Body.Rbrace, source)
+			}
+		case *ast.FuncLit:
+			s.addFold(input.FoldBlock, src.Body.Lbrace, src.Body.Rbrace, source)
+		case *ast.CompositeLit:
+			s.addFold(input.FoldComposite, src.Lbrace, src.Rbrace, source)
+		case *ast.GenDecl:
+			if src.Tok == token.IMPORT && src.Lparen.IsValid() {
+				s.addFold(input.FoldImports, src.Lparen, src.Rparen, source)
+			}
+		}
+		return true
+	})
+}
+
+func (s *Syntax) addFold(kind input.FoldKind, start, end token.Pos, source string) {
+	if !start.IsValid() || !end.IsValid() {
+		return
+	}
+	startPos, endPos := s.fileSet.Position(start), s.fileSet.Position(end)
+	if endPos.Line-startPos.Line < 2 {
+		return
+	}
+	runeEnd := s.runePos(endPos.Offset)
+	if runeEnd < 0 {
+		runeEnd = utf8.RuneCountInString(source)
+	}
+	s.folds = append(s.folds, input.Fold{
+		Span: input.Span{Start: s.runePos(startPos.Offset), End: runeEnd},
+		Kind: kind,
+	})
+}
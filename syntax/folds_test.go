@@ -0,0 +1,85 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package syntax_test
+
+import (
+	"testing"
+
+	"github.com/apoydence/onpar"
+	"github.com/apoydence/onpar/expect"
+	. "github.com/apoydence/onpar/matchers"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/syntax"
+)
+
+func TestFolds(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	const src = `package foo
+
+import (
+	"fmt"
+	"os"
+)
+
+// Þing is a
+// thing.
+type Þing struct{ µ int }
+
+func main() {
+	t := Þing{
+		µ: 1,
+	}
+	go func() { fmt.Println(t) }()
+	os.Exit(0)
+}
+`
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, []input.Fold) {
+		expect := expect.New(t)
+
+		s := syntax.New()
+		err := s.Parse(src)
+		expect(err).To(BeNil())
+		return expect, s.Folds()
+	})
+
+	o.Spec("it finds each region that spans enough lines to fold", func(expect expect.Expectation, folds []input.Fold) {
+		expect(folds).To(HaveLen(4))
+	})
+
+	o.Spec("it folds comments through their last line", func(expect expect.Expectation, folds []input.Fold) {
+		fold := findFold(input.FoldComment, folds)
+		runes := []rune(src)
+		expect(string(runes[fold.Start:fold.End])).To(Equal("// Þing is a\n// thing.\n"))
+	})
+
+	o.Spec("it folds import blocks between their parens", func(expect expect.Expectation, folds []input.Fold) {
+		fold := findFold(input.FoldImports, folds)
+		runes := []rune(src)
+		expect(string(runes[fold.Start])).To(Equal("("))
+		expect(string(runes[fold.End])).To(Equal(")"))
+	})
+
+	o.Spec("it folds function bodies and composite literals between their braces", func(expect expect.Expectation, folds []input.Fold) {
+		runes := []rune(src)
+		fn := findFold(input.FoldFunc, folds)
+		expect(string(runes[fn.Start : fn.Start+len("{\n\tt")])).To(Equal("{\n\tt"))
+		expect(fn.End).To(Equal(len(runes) - len("}\n")))
+
+		lit := findFold(input.FoldComposite, folds)
+		expect(string(runes[lit.Start : lit.End+1])).To(Equal("{\n\t\tµ: 1,\n\t}"))
+	})
+}
+
+func findFold(kind input.FoldKind, folds []input.Fold) input.Fold {
+	for _, f := range folds {
+		if f.Kind == kind {
+			return f
+		}
+	}
+	return input.Fold{Span: input.Span{Start: -1, End: -1}}
+}
@@ -21,6 +21,7 @@ type Syntax struct {
 	fileSet     *token.FileSet
 	layers      map[theme.LanguageConstruct]*input.SyntaxLayer
 	runeOffsets []int
+	folds       []input.Fold
 }
 
 // New constructs a new *Syntax value with theme as its Theme field.
@@ -47,6 +48,7 @@ func (s *Syntax) Parse(source string) error {
 	s.fileSet = token.NewFileSet()
 	s.scope = theme.ScopePair
 	s.layers = make(map[theme.LanguageConstruct]*input.SyntaxLayer)
+	s.folds = nil
 	f, err := parser.ParseFile(s.fileSet, "", source, parser.ParseComments)
 
 	// Parse everything we can before returning the error.
@@ -65,6 +67,7 @@ func (s *Syntax) Parse(source string) error {
 	for _, unresolved := range f.Unresolved {
 		s.addUnresolved(unresolved)
 	}
+	s.addFolds(f, source)
 	return err
 }
 
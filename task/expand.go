@@ -0,0 +1,30 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package task
+
+import (
+	"os"
+	"strings"
+)
+
+// Expand replaces $VAR and ${VAR} in s with the value of VAR.  Values
+// are looked up in vars first, then in environ (a list of key=value
+// pairs, where later pairs override earlier ones).  Unknown variables
+// are replaced with an empty string, like in a shell.
+func Expand(s string, environ []string, vars map[string]string) string {
+	env := make(map[string]string, len(environ))
+	for _, e := range environ {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 2 {
+			env[kv[0]] = kv[1]
+		}
+	}
+	return os.Expand(s, func(name string) string {
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return env[name]
+	})
+}
@@ -0,0 +1,47 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package task_test
+
+import (
+	"testing"
+
+	"github.com/nelsam/vidar/task"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+type Expectation = expect.Expectation
+
+var Equal = matchers.Equal
+
+func TestExpand(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it expands variables from the environment", func(expect Expectation) {
+		environ := []string{"GOOS=linux", "FLAGS=-v", "FLAGS=-race"}
+		expect(task.Expand("GOOS=$GOOS go test ${FLAGS} ./...", environ, nil)).To(Equal("GOOS=linux go test -race ./..."))
+	})
+
+	o.Spec("it prefers vars over the environment", func(expect Expectation) {
+		environ := []string{"FILE=/etc/passwd"}
+		vars := map[string]string{"FILE": "/src/main.go", "PROJECT": "/src"}
+		expect(task.Expand("go run $FILE in $PROJECT", environ, vars)).To(Equal("go run /src/main.go in /src"))
+	})
+
+	o.Spec("it replaces unknown variables with nothing", func(expect Expectation) {
+		expect(task.Expand("echo [$NOPE]", nil, nil)).To(Equal("echo []"))
+	})
+
+	o.Spec("it keeps values that contain an equals sign", func(expect Expectation) {
+		environ := []string{"GOFLAGS=-tags=integration"}
+		expect(task.Expand("$GOFLAGS", environ, nil)).To(Equal("-tags=integration"))
+	})
+}
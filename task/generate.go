@@ -0,0 +1,147 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package task
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// A ProjectEditor is the editor for the current project.
+type ProjectEditor interface {
+	Project() setting.Project
+	CurrentFile() string
+	OpenEditors() []input.Editor
+}
+
+// A Refresher is a type (e.g. the project tree) which shows the
+// contents of directories, and can read them again on request.
+type Refresher interface {
+	Refresh(dir string)
+}
+
+// A DiskReloader is an editor which can load its file again if it has
+// changed on disk.
+type DiskReloader interface {
+	ReloadIfChanged()
+}
+
+// Generate is a command which runs go generate for the package of the
+// current file, or for the directory given to SetPath, in the task
+// pane.  Once it's done, open files and the project tree are
+// refreshed to show the generated code.
+type Generate struct {
+	status.General
+
+	runner *runner
+	next   string
+
+	paneler   Paneler
+	editor    ProjectEditor
+	refresher Refresher
+}
+
+func NewGenerate(theme gxui.Theme, r *runner) *Generate {
+	g := &Generate{runner: r}
+	g.Theme = theme
+	return g
+}
+
+func (g *Generate) Name() string {
+	return "go-generate"
+}
+
+func (g *Generate) Menu() string {
+	return "Golang"
+}
+
+func (g *Generate) Defaults() []fmt.Stringer {
+	return nil
+}
+
+// SetPath sets the file or directory that the next run of g generates
+// code for, in place of the current file.
+func (g *Generate) SetPath(path string) {
+	g.next = path
+}
+
+func (g *Generate) Reset() {
+	g.Clear()
+	g.paneler = nil
+	g.editor = nil
+	g.refresher = nil
+}
+
+func (g *Generate) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Paneler:
+		g.paneler = src
+	case ProjectEditor:
+		g.editor = src
+	case Refresher:
+		g.refresher = src
+	}
+	if g.paneler == nil || g.editor == nil {
+		return bind.Waiting
+	}
+	return bind.Done
+}
+
+func (g *Generate) Exec() error {
+	path := g.next
+	g.next = ""
+	if path == "" {
+		path = g.editor.CurrentFile()
+	}
+	if path == "" {
+		g.Warn = "No file or directory to generate code for"
+		return nil
+	}
+	dir := path
+	if finfo, err := os.Stat(path); err != nil || !finfo.IsDir() {
+		dir = filepath.Dir(path)
+	}
+	rn := run{
+		task:    setting.Task{Name: "go generate", Command: "go generate", Dir: dir},
+		project: g.editor.Project(),
+		file:    path,
+		golang:  true,
+		done:    g.refresh(dir),
+	}
+	if err := g.runner.start(g.paneler, rn); err != nil {
+		g.Err = fmt.Sprintf("Could not run go generate: %s", err)
+		return err
+	}
+	g.Info = fmt.Sprintf("Running go generate in %s", dir)
+	return nil
+}
+
+// refresh returns a function that reloads the open files in dir (or
+// its subdirectories) and the project tree's view of dir.
+func (g *Generate) refresh(dir string) func() {
+	editor, refresher := g.editor, g.refresher
+	return func() {
+		if refresher != nil {
+			refresher.Refresh(dir)
+		}
+		prefix := dir + string(filepath.Separator)
+		for _, e := range editor.OpenEditors() {
+			r, ok := e.(DiskReloader)
+			if !ok || !strings.HasPrefix(e.Filepath(), prefix) {
+				continue
+			}
+			// Reading files shouldn't block the UI.
+			go r.ReloadIfChanged()
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/themes/basic"
+)
+
+// maxOutput is the number of bytes of output that a Pane keeps.
+// Older output is dropped once a task writes more than this.
+const maxOutput = 1 << 20
+
+// Pane is a gxui control that runs a task and displays its output as
+// it's written.  Only one task runs in a Pane at a time.
+type Pane struct {
+	mixins.TextBox
+
+	driver gxui.Driver
+
+	lock   sync.Mutex
+	output bytes.Buffer
+	cmd    *exec.Cmd
+
+	// pending is set while an update is waiting to run on the UI
+	// goroutine, so that a burst of output only causes one update.
+	pending int32
+}
+
+// NewPane creates an empty Pane.
+func NewPane(driver gxui.Driver, theme *basic.Theme) *Pane {
+	p := &Pane{driver: driver}
+	p.TextBox.Init(p, driver, theme, theme.DefaultMonospaceFont())
+	p.SetTextColor(theme.TextBoxDefaultStyle.FontColor)
+	p.SetMargin(math.Spacing{L: 3, T: 3, R: 3, B: 3})
+	p.SetPadding(math.Spacing{L: 3, T: 3, R: 3, B: 3})
+	p.SetBackgroundBrush(theme.TextBoxDefaultStyle.Brush)
+	p.SetDesiredWidth(math.MaxSize.W)
+	p.SetMultiline(true)
+	return p
+}
+
+// Run starts command in dir with the shell, killing the task that is
+// already running in p, if any.  Output from the last task is
+// cleared.
+func (p *Pane) Run(name, command, dir string, environ []string) error {
+	return p.RunThen(name, command, dir, environ, nil)
+}
+
+// RunThen is like Run, but calls done on the UI goroutine once
+// command exits.  done isn't called if command is killed to make way
+// for another task.
+func (p *Pane) RunThen(name, command, dir string, environ []string, done func()) error {
+	cmd := shellCommand(command)
+	cmd.Dir = dir
+	cmd.Env = environ
+	w := &paneWriter{pane: p, cmd: cmd}
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	p.lock.Lock()
+	if p.cmd != nil {
+		p.cmd.Process.Kill()
+	}
+	p.cmd = cmd
+	p.output.Reset()
+	fmt.Fprintf(&p.output, "[%s] $ %s\n", name, command)
+	err := cmd.Start()
+	if err != nil {
+		p.cmd = nil
+		fmt.Fprintf(&p.output, "[could not start: %s]\n", err)
+	}
+	p.lock.Unlock()
+	p.refresh()
+	if err != nil {
+		return err
+	}
+	go p.wait(cmd, time.Now(), done)
+	return nil
+}
+
+// Running returns whether a task is running in p.
+func (p *Pane) Running() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.cmd != nil
+}
+
+// Stop kills the task that is running in p, if any.
+func (p *Pane) Stop() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.cmd != nil {
+		p.cmd.Process.Kill()
+	}
+}
+
+func (p *Pane) wait(cmd *exec.Cmd, start time.Time, done func()) {
+	err := cmd.Wait()
+	elapsed := time.Since(start).Round(10 * time.Millisecond)
+
+	p.lock.Lock()
+	defer p.refresh()
+	defer p.lock.Unlock()
+	if p.cmd != cmd {
+		// Another task has replaced this one.
+		return
+	}
+	p.cmd = nil
+	if done != nil {
+		p.driver.Call(done)
+	}
+	if err != nil {
+		fmt.Fprintf(&p.output, "\n[%s after %s]\n", err, elapsed)
+		return
+	}
+	fmt.Fprintf(&p.output, "\n[done in %s]\n", elapsed)
+}
+
+// write adds b to p's output if cmd is still the task running in p.
+func (p *Pane) write(cmd *exec.Cmd, b []byte) {
+	p.lock.Lock()
+	if p.cmd != cmd {
+		p.lock.Unlock()
+		return
+	}
+	p.output.Write(b)
+	if extra := p.output.Len() - maxOutput; extra > 0 {
+		p.output.Next(extra)
+	}
+	p.lock.Unlock()
+	p.refresh()
+}
+
+func (p *Pane) refresh() {
+	if !atomic.CompareAndSwapInt32(&p.pending, 0, 1) {
+		return
+	}
+	p.driver.Call(func() {
+		atomic.StoreInt32(&p.pending, 0)
+		p.update()
+	})
+}
+
+func (p *Pane) update() {
+	p.lock.Lock()
+	text := p.output.String()
+	p.lock.Unlock()
+
+	p.SetText(text)
+	end := len(p.TextRunes())
+	p.Controller().SetCaret(end)
+	p.ScrollToRune(end)
+}
+
+// KeyStroke ignores typed text, since tasks don't read input.
+func (p *Pane) KeyStroke(gxui.KeyStrokeEvent) bool {
+	return true
+}
+
+// KeyPress handles the keys that move around in p's output, and
+// ignores the rest so that the output can't be edited.
+func (p *Pane) KeyPress(event gxui.KeyboardEvent) bool {
+	switch event.Key {
+	case gxui.KeyLeft, gxui.KeyRight, gxui.KeyUp, gxui.KeyDown,
+		gxui.KeyHome, gxui.KeyEnd, gxui.KeyPageUp, gxui.KeyPageDown:
+		return p.TextBox.KeyPress(event)
+	}
+	return event.Modifier == 0 || event.Modifier == gxui.ModShift
+}
+
+// Elements returns nil, since none of p's children are useful to
+// commands.
+func (p *Pane) Elements() []interface{} {
+	return nil
+}
+
+// paneWriter is the stdout and stderr of a task.
+type paneWriter struct {
+	pane *Pane
+	cmd  *exec.Cmd
+}
+
+func (w *paneWriter) Write(b []byte) (int, error) {
+	w.pane.write(w.cmd, b)
+	return len(b), nil
+}
@@ -0,0 +1,259 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package task
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/command/fs"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/scoring"
+	"github.com/nelsam/vidar/setting"
+)
+
+var matchColor = gxui.Color{R: 0.4, G: 0.8, B: 0.3, A: 1}
+
+// Run is a command which runs one of the tasks in the current
+// project's settings.  Typing filters the task names, and the first
+// match is run.
+type Run struct {
+	status.General
+
+	theme  *basic.Theme
+	runner *runner
+
+	filter  gxui.TextBox
+	matches gxui.LinearLayout
+	input   <-chan gxui.Focusable
+
+	project setting.Project
+	file    string
+	tasks   []setting.Task
+	choice  string
+
+	paneler Paneler
+}
+
+func NewRun(theme *basic.Theme, r *runner) *Run {
+	t := &Run{
+		theme:   theme,
+		runner:  r,
+		filter:  theme.CreateTextBox(),
+		matches: theme.CreateLinearLayout(),
+	}
+	t.Theme = theme
+	t.filter.SetDesiredWidth(math.MaxSize.W)
+	t.filter.OnTextChanged(func([]gxui.TextBoxEdit) {
+		t.update()
+	})
+	t.matches.SetDirection(gxui.LeftToRight)
+	return t
+}
+
+func (t *Run) Name() string {
+	return "run-task"
+}
+
+func (t *Run) Menu() string {
+	return "Project"
+}
+
+func (t *Run) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Key: gxui.KeyF5,
+	}}
+}
+
+func (t *Run) Start(control gxui.Control) gxui.Control {
+	t.project = fs.CurrentProject(control)
+	t.file = fs.CurrentFile(control)
+	t.tasks = t.project.Settings().Tasks
+	t.filter.SetText("")
+	t.update()
+
+	input := make(chan gxui.Focusable, 1)
+	input <- t.filter
+	close(input)
+	t.input = input
+	return t.matches
+}
+
+func (t *Run) Next() gxui.Focusable {
+	return <-t.input
+}
+
+// update displays the tasks that match the current filter, in order
+// of how well they match.
+func (t *Run) update() {
+	names := make([]string, 0, len(t.tasks))
+	for _, task := range t.tasks {
+		names = append(names, task.Name)
+	}
+	if partial := t.filter.Text(); partial != "" {
+		names = scoring.Sort(names, partial)
+	}
+	t.choice = ""
+	t.matches.RemoveAll()
+	for i, n := range names {
+		l := t.theme.CreateLabel()
+		l.SetMargin(math.Spacing{L: 5, R: 5})
+		l.SetText(n)
+		if i == 0 {
+			t.choice = n
+			l.SetColor(matchColor)
+		}
+		t.matches.AddChild(l)
+	}
+}
+
+func (t *Run) Reset() {
+	t.Clear()
+	t.paneler = nil
+}
+
+func (t *Run) Store(elem interface{}) bind.Status {
+	paneler, ok := elem.(Paneler)
+	if !ok {
+		return bind.Waiting
+	}
+	t.paneler = paneler
+	return bind.Done
+}
+
+func (t *Run) Exec() error {
+	if len(t.tasks) == 0 {
+		t.Warn = fmt.Sprintf("No tasks are defined in the settings for %s", t.project.Name)
+		return nil
+	}
+	if t.choice == "" {
+		t.Err = "no tasks match"
+		return fmt.Errorf("run-task: %s", t.Err)
+	}
+	for _, task := range t.tasks {
+		if task.Name != t.choice {
+			continue
+		}
+		if err := t.runner.start(t.paneler, run{task: task, project: t.project, file: t.file}); err != nil {
+			t.Err = fmt.Sprintf("Could not run %s: %s", task.Name, err)
+			return err
+		}
+		t.Info = fmt.Sprintf("Running %s", task.Name)
+		return nil
+	}
+	return nil
+}
+
+// Rerun is a command which runs the last task that was run again,
+// with the same project and file.
+type Rerun struct {
+	status.General
+
+	runner  *runner
+	paneler Paneler
+}
+
+func NewRerun(theme gxui.Theme, r *runner) *Rerun {
+	t := &Rerun{runner: r}
+	t.Theme = theme
+	return t
+}
+
+func (t *Rerun) Name() string {
+	return "rerun-last-task"
+}
+
+func (t *Rerun) Menu() string {
+	return "Project"
+}
+
+func (t *Rerun) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModShift,
+		Key:      gxui.KeyF5,
+	}}
+}
+
+func (t *Rerun) Reset() {
+	t.Clear()
+	t.paneler = nil
+}
+
+func (t *Rerun) Store(elem interface{}) bind.Status {
+	paneler, ok := elem.(Paneler)
+	if !ok {
+		return bind.Waiting
+	}
+	t.paneler = paneler
+	return bind.Done
+}
+
+func (t *Rerun) Exec() error {
+	last := t.runner.last
+	if last == nil {
+		t.Warn = "No task has been run yet"
+		return nil
+	}
+	if err := t.runner.start(t.paneler, *last); err != nil {
+		t.Err = fmt.Sprintf("Could not run %s: %s", last.task.Name, err)
+		return err
+	}
+	t.Info = fmt.Sprintf("Running %s", last.task.Name)
+	return nil
+}
+
+// TogglePane is a command which shows or hides the output of the last
+// task that was run.
+type TogglePane struct {
+	status.General
+
+	runner  *runner
+	paneler Paneler
+}
+
+func NewTogglePane(theme gxui.Theme, r *runner) *TogglePane {
+	t := &TogglePane{runner: r}
+	t.Theme = theme
+	return t
+}
+
+func (t *TogglePane) Name() string {
+	return "toggle-task-output"
+}
+
+func (t *TogglePane) Menu() string {
+	return "View"
+}
+
+func (t *TogglePane) Defaults() []fmt.Stringer {
+	return nil
+}
+
+func (t *TogglePane) Reset() {
+	t.Clear()
+	t.paneler = nil
+}
+
+func (t *TogglePane) Store(elem interface{}) bind.Status {
+	paneler, ok := elem.(Paneler)
+	if !ok {
+		return bind.Waiting
+	}
+	t.paneler = paneler
+	return bind.Done
+}
+
+func (t *TogglePane) Exec() error {
+	pane := t.runner.outputPane()
+	if t.paneler.HasPanel(pane) {
+		t.paneler.HidePanel(pane)
+		return nil
+	}
+	t.paneler.ShowPanel(pane)
+	return nil
+}
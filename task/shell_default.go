@@ -0,0 +1,23 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+//go:build !windows
+// +build !windows
+
+package task
+
+import (
+	"os"
+	"os/exec"
+)
+
+// shellCommand returns a command that runs command with the user's
+// shell.
+func shellCommand(command string) *exec.Cmd {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	return exec.Command(shell, "-c", command)
+}
@@ -0,0 +1,19 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package task
+
+import (
+	"os"
+	"os/exec"
+)
+
+// shellCommand returns a command that runs command with cmd.exe.
+func shellCommand(command string) *exec.Cmd {
+	shell := os.Getenv("COMSPEC")
+	if shell == "" {
+		shell = "cmd.exe"
+	}
+	return exec.Command(shell, "/C", command)
+}
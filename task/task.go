@@ -0,0 +1,91 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package task runs the tasks (e.g. build, run, or generate) that
+// are defined in a project's settings, and shows their output in a
+// pane below the editor.
+package task
+
+import (
+	"path/filepath"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/plugin/command"
+	"github.com/nelsam/vidar/setting"
+)
+
+// Bindables returns the slice of bind.Bindable types that is
+// implemented by this package.
+func Bindables(_ command.Commander, driver gxui.Driver, theme *basic.Theme) []bind.Bindable {
+	r := &runner{driver: driver, theme: theme}
+	return []bind.Bindable{
+		NewRun(theme, r),
+		NewRerun(theme, r),
+		NewTogglePane(theme, r),
+		NewGenerate(theme, r),
+	}
+}
+
+// A Paneler is a type that can display panels below the editor.
+type Paneler interface {
+	ShowPanel(gxui.Control)
+	HidePanel(gxui.Control)
+	HasPanel(gxui.Control) bool
+}
+
+// run is a task that was run, along with what it was run for.
+type run struct {
+	task    setting.Task
+	project setting.Project
+	file    string
+
+	// golang runs the task with the project's go environment
+	// (e.g. GO111MODULE) rather than its plain environment.
+	golang bool
+
+	// done is called on the UI goroutine after the task exits.  It
+	// may be nil.
+	done func()
+}
+
+// runner holds the pane that tasks are run in and the last task that
+// was run, which are shared by this package's commands.
+type runner struct {
+	driver gxui.Driver
+	theme  *basic.Theme
+	pane   *Pane
+	last   *run
+}
+
+func (r *runner) outputPane() *Pane {
+	if r.pane == nil {
+		r.pane = NewPane(r.driver, r.theme)
+	}
+	return r.pane
+}
+
+// start runs the task in rn, showing its output in paneler.
+func (r *runner) start(paneler Paneler, rn run) error {
+	r.last = &rn
+	environ := rn.project.Environ()
+	if rn.golang {
+		environ = rn.project.GoEnviron()
+	}
+	vars := map[string]string{
+		"FILE":    rn.file,
+		"PROJECT": rn.project.Path,
+	}
+	dir := Expand(rn.task.Dir, environ, vars)
+	switch {
+	case dir == "":
+		dir = rn.project.Path
+	case !filepath.IsAbs(dir):
+		dir = filepath.Join(rn.project.Path, dir)
+	}
+	pane := r.outputPane()
+	paneler.ShowPanel(pane)
+	return pane.RunThen(rn.task.Name, Expand(rn.task.Command, environ, vars), dir, environ, rn.done)
+}
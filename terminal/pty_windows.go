@@ -0,0 +1,29 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package terminal
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// TODO: use ConPTY on windows.
+var errUnsupported = errors.New("terminals are not yet supported on windows")
+
+func defaultShell() string {
+	if shell := os.Getenv("COMSPEC"); shell != "" {
+		return shell
+	}
+	return "cmd.exe"
+}
+
+func startPTY(cmd *exec.Cmd, rows, cols int) (*os.File, error) {
+	return nil, errUnsupported
+}
+
+func resizePTY(f *os.File, rows, cols int) error {
+	return errUnsupported
+}
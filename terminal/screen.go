@@ -0,0 +1,430 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package terminal
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxScrollback is the number of lines that a Screen will keep
+// after they have scrolled off the top of the screen.
+const MaxScrollback = 1000
+
+type parseState int
+
+const (
+	stateText parseState = iota
+	stateEscape
+	stateCSI
+	stateOSC
+	stateOSCEscape
+	stateCharset
+)
+
+// Screen is a very small terminal emulator.  It understands enough
+// of the VT100 control sequences to display the output of a shell
+// and most line-oriented programs.  Anything it doesn't understand
+// (colors, alternate screens, etc) is ignored.
+type Screen struct {
+	rows, cols int
+
+	scrollback [][]rune
+	lines      [][]rune
+	row, col   int
+
+	savedRow, savedCol int
+
+	state   parseState
+	params  []byte
+	partial []byte
+}
+
+// NewScreen returns a Screen with the requested size.
+func NewScreen(rows, cols int) *Screen {
+	s := &Screen{}
+	s.Resize(rows, cols)
+	return s
+}
+
+// Size returns the number of rows and columns in s.
+func (s *Screen) Size() (rows, cols int) {
+	return s.rows, s.cols
+}
+
+// Resize changes the size of s.  Lines that no longer fit on the
+// screen are moved to the scrollback.
+func (s *Screen) Resize(rows, cols int) {
+	if rows < 1 {
+		rows = 1
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	s.rows, s.cols = rows, cols
+	for len(s.lines) < rows {
+		s.lines = append(s.lines, nil)
+	}
+	if len(s.lines) > rows {
+		// Keep the cursor on the screen, preferring to drop blank
+		// lines from the bottom.
+		if extra := s.row + 1 - rows; extra > 0 {
+			s.pushScrollback(s.lines[:extra]...)
+			s.lines = s.lines[extra:]
+			s.row -= extra
+		}
+		s.lines = s.lines[:rows]
+	}
+	for i, l := range s.lines {
+		if len(l) > cols {
+			s.lines[i] = l[:cols]
+		}
+	}
+	s.clamp()
+}
+
+// Cursor returns the row and column of the cursor, relative to the
+// top of the screen.
+func (s *Screen) Cursor() (row, col int) {
+	return s.row, s.col
+}
+
+// Lines returns the lines in s's scrollback followed by the lines on
+// the screen, with trailing blank lines removed.
+func (s *Screen) Lines() []string {
+	var lines []string
+	for _, l := range s.scrollback {
+		lines = append(lines, strings.TrimRight(string(l), " "))
+	}
+	last := len(s.lines)
+	for last > s.row+1 && len(s.lines[last-1]) == 0 {
+		last--
+	}
+	for _, l := range s.lines[:last] {
+		lines = append(lines, strings.TrimRight(string(l), " "))
+	}
+	return lines
+}
+
+// String returns the text displayed by s, including its scrollback.
+func (s *Screen) String() string {
+	return strings.Join(s.Lines(), "\n")
+}
+
+// CursorOffset returns the rune offset of the cursor in the value
+// returned by s.String().
+func (s *Screen) CursorOffset() int {
+	offset := 0
+	lines := s.Lines()
+	row := len(s.scrollback) + s.row
+	for i := 0; i < row && i < len(lines); i++ {
+		offset += utf8.RuneCountInString(lines[i]) + 1
+	}
+	col := s.col
+	if row < len(lines) {
+		if l := utf8.RuneCountInString(lines[row]); col > l {
+			col = l
+		}
+	}
+	return offset + col
+}
+
+// Write parses p as terminal output and updates s.
+func (s *Screen) Write(p []byte) (int, error) {
+	b := p
+	if len(s.partial) > 0 {
+		b = append(s.partial, p...)
+		s.partial = nil
+	}
+	for len(b) > 0 {
+		if !utf8.FullRune(b) {
+			s.partial = append(s.partial, b...)
+			break
+		}
+		r, size := utf8.DecodeRune(b)
+		b = b[size:]
+		s.handle(r)
+	}
+	return len(p), nil
+}
+
+func (s *Screen) handle(r rune) {
+	switch s.state {
+	case stateEscape:
+		s.escape(r)
+		return
+	case stateCSI:
+		if r >= 0x40 && r <= 0x7e {
+			s.csi(r)
+			s.state = stateText
+			return
+		}
+		s.params = append(s.params, byte(r))
+		return
+	case stateOSC:
+		switch r {
+		case '\a':
+			s.state = stateText
+		case 0x1b:
+			s.state = stateOSCEscape
+		}
+		return
+	case stateOSCEscape:
+		s.state = stateText
+		return
+	case stateCharset:
+		s.state = stateText
+		return
+	}
+
+	switch r {
+	case 0x1b:
+		s.state = stateEscape
+	case '\r':
+		s.col = 0
+	case '\n', '\v', '\f':
+		s.lineFeed()
+	case '\b':
+		if s.col > 0 {
+			s.col--
+		}
+	case '\t':
+		s.col = (s.col/8 + 1) * 8
+		if s.col >= s.cols {
+			s.col = s.cols - 1
+		}
+	case '\a', 0:
+	default:
+		if r < ' ' || r == 0x7f {
+			return
+		}
+		s.put(r)
+	}
+}
+
+func (s *Screen) escape(r rune) {
+	s.state = stateText
+	switch r {
+	case '[':
+		s.state = stateCSI
+		s.params = s.params[:0]
+	case ']':
+		s.state = stateOSC
+	case '(', ')':
+		s.state = stateCharset
+	case '7':
+		s.savedRow, s.savedCol = s.row, s.col
+	case '8':
+		s.row, s.col = s.savedRow, s.savedCol
+		s.clamp()
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.col = 0
+		s.lineFeed()
+	case 'M':
+		if s.row > 0 {
+			s.row--
+			return
+		}
+		s.lines = append([][]rune{nil}, s.lines[:len(s.lines)-1]...)
+	case 'c':
+		s.lines = make([][]rune, s.rows)
+		s.row, s.col = 0, 0
+	}
+}
+
+func (s *Screen) csi(final rune) {
+	raw := string(s.params)
+	if strings.HasPrefix(raw, "?") || strings.HasPrefix(raw, ">") {
+		// Private modes (cursor visibility, bracketed paste, etc)
+		// don't affect the text we display.
+		return
+	}
+	params := parseParams(raw)
+	arg := func(i, def int) int {
+		if i >= len(params) || params[i] == 0 {
+			return def
+		}
+		return params[i]
+	}
+	switch final {
+	case 'A':
+		s.row -= arg(0, 1)
+	case 'B', 'e':
+		s.row += arg(0, 1)
+	case 'C', 'a':
+		s.col += arg(0, 1)
+	case 'D':
+		s.col -= arg(0, 1)
+	case 'E':
+		s.row += arg(0, 1)
+		s.col = 0
+	case 'F':
+		s.row -= arg(0, 1)
+		s.col = 0
+	case 'G', '`':
+		s.col = arg(0, 1) - 1
+	case 'd':
+		s.row = arg(0, 1) - 1
+	case 'H', 'f':
+		s.row = arg(0, 1) - 1
+		s.col = arg(1, 1) - 1
+	case 'J':
+		s.eraseDisplay(arg(0, 0))
+	case 'K':
+		s.eraseLine(arg(0, 0))
+	case 'P':
+		s.deleteChars(arg(0, 1))
+	case '@':
+		s.insertChars(arg(0, 1))
+	case 'X':
+		s.eraseChars(arg(0, 1))
+	case 's':
+		s.savedRow, s.savedCol = s.row, s.col
+	case 'u':
+		s.row, s.col = s.savedRow, s.savedCol
+	}
+	s.clamp()
+}
+
+func parseParams(raw string) []int {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ";")
+	params := make([]int, 0, len(parts))
+	for _, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			v = 0
+		}
+		params = append(params, v)
+	}
+	return params
+}
+
+func (s *Screen) clamp() {
+	if s.row < 0 {
+		s.row = 0
+	}
+	if s.row >= s.rows {
+		s.row = s.rows - 1
+	}
+	if s.col < 0 {
+		s.col = 0
+	}
+	if s.col >= s.cols {
+		s.col = s.cols - 1
+	}
+}
+
+func (s *Screen) put(r rune) {
+	if s.col >= s.cols {
+		s.col = 0
+		s.lineFeed()
+	}
+	line := s.lines[s.row]
+	for len(line) <= s.col {
+		line = append(line, ' ')
+	}
+	line[s.col] = r
+	s.lines[s.row] = line
+	s.col++
+}
+
+func (s *Screen) lineFeed() {
+	if s.row < s.rows-1 {
+		s.row++
+		return
+	}
+	s.scroll()
+}
+
+func (s *Screen) scroll() {
+	s.pushScrollback(s.lines[0])
+	s.lines = append(s.lines[1:], nil)
+}
+
+func (s *Screen) pushScrollback(lines ...[]rune) {
+	s.scrollback = append(s.scrollback, lines...)
+	if len(s.scrollback) > MaxScrollback {
+		s.scrollback = s.scrollback[len(s.scrollback)-MaxScrollback:]
+	}
+}
+
+func (s *Screen) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		s.eraseLine(0)
+		for i := s.row + 1; i < len(s.lines); i++ {
+			s.lines[i] = nil
+		}
+	case 1:
+		s.eraseLine(1)
+		for i := 0; i < s.row; i++ {
+			s.lines[i] = nil
+		}
+	case 2, 3:
+		for i := range s.lines {
+			s.lines[i] = nil
+		}
+		if mode == 3 {
+			s.scrollback = nil
+		}
+	}
+}
+
+func (s *Screen) eraseLine(mode int) {
+	line := s.lines[s.row]
+	switch mode {
+	case 0:
+		if s.col < len(line) {
+			s.lines[s.row] = line[:s.col]
+		}
+	case 1:
+		for i := 0; i <= s.col && i < len(line); i++ {
+			line[i] = ' '
+		}
+	case 2:
+		s.lines[s.row] = nil
+	}
+}
+
+func (s *Screen) deleteChars(n int) {
+	line := s.lines[s.row]
+	if s.col >= len(line) {
+		return
+	}
+	end := s.col + n
+	if end > len(line) {
+		end = len(line)
+	}
+	s.lines[s.row] = append(line[:s.col], line[end:]...)
+}
+
+func (s *Screen) insertChars(n int) {
+	line := s.lines[s.row]
+	if s.col >= len(line) {
+		return
+	}
+	blank := make([]rune, n)
+	for i := range blank {
+		blank[i] = ' '
+	}
+	line = append(line[:s.col], append(blank, line[s.col:]...)...)
+	if len(line) > s.cols {
+		line = line[:s.cols]
+	}
+	s.lines[s.row] = line
+}
+
+func (s *Screen) eraseChars(n int) {
+	line := s.lines[s.row]
+	for i := s.col; i < s.col+n && i < len(line); i++ {
+		line[i] = ' '
+	}
+}
@@ -0,0 +1,83 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package terminal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nelsam/vidar/terminal"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var equal = matchers.Equal
+
+func TestScreen(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (expect.Expectation, *terminal.Screen) {
+		return expect.New(t), terminal.NewScreen(3, 10)
+	})
+
+	o.Spec("it writes plain text", func(expect expect.Expectation, s *terminal.Screen) {
+		fmt.Fprint(s, "foo\r\nbar")
+		expect(s.String()).To(equal("foo\nbar"))
+		row, col := s.Cursor()
+		expect(row).To(equal(1))
+		expect(col).To(equal(3))
+		expect(s.CursorOffset()).To(equal(7))
+	})
+
+	o.Spec("it wraps long lines", func(expect expect.Expectation, s *terminal.Screen) {
+		fmt.Fprint(s, "0123456789abc")
+		expect(s.String()).To(equal("0123456789\nabc"))
+	})
+
+	o.Spec("it moves lines to the scrollback", func(expect expect.Expectation, s *terminal.Screen) {
+		fmt.Fprint(s, "a\r\nb\r\nc\r\nd")
+		expect(s.String()).To(equal("a\nb\nc\nd"))
+		row, _ := s.Cursor()
+		expect(row).To(equal(2))
+	})
+
+	o.Spec("it handles multi-byte runes split across writes", func(expect expect.Expectation, s *terminal.Screen) {
+		b := []byte("λx")
+		s.Write(b[:1])
+		s.Write(b[1:])
+		expect(s.String()).To(equal("λx"))
+	})
+
+	o.Spec("it handles backspaces and line erasure", func(expect expect.Expectation, s *terminal.Screen) {
+		fmt.Fprint(s, "foobar\b\b\b\x1b[K")
+		expect(s.String()).To(equal("foo"))
+	})
+
+	o.Spec("it moves the cursor", func(expect expect.Expectation, s *terminal.Screen) {
+		fmt.Fprint(s, "foo\x1b[2;5Hx\x1b[1;2Hy")
+		expect(s.String()).To(equal("fyo\n    x"))
+	})
+
+	o.Spec("it clears the screen", func(expect expect.Expectation, s *terminal.Screen) {
+		fmt.Fprint(s, "foo\r\nbar\x1b[H\x1b[2J$ ")
+		expect(s.String()).To(equal("$"))
+		expect(s.CursorOffset()).To(equal(1))
+	})
+
+	o.Spec("it ignores colors and titles", func(expect expect.Expectation, s *terminal.Screen) {
+		fmt.Fprint(s, "\x1b]0;title\a\x1b[1;32mok\x1b[0m\x1b[?25l")
+		expect(s.String()).To(equal("ok"))
+	})
+
+	o.Spec("it keeps the cursor's line when resized", func(expect expect.Expectation, s *terminal.Screen) {
+		fmt.Fprint(s, "a\r\nb\r\nc")
+		s.Resize(2, 10)
+		expect(s.String()).To(equal("a\nb\nc"))
+		row, _ := s.Cursor()
+		expect(row).To(equal(1))
+	})
+}
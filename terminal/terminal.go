@@ -0,0 +1,304 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package terminal
+
+import (
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/math"
+	"github.com/nelsam/gxui/mixins"
+	"github.com/nelsam/gxui/themes/basic"
+)
+
+const (
+	defaultRows = 24
+	defaultCols = 80
+)
+
+// Terminal is a gxui control that runs a shell in a pty and displays
+// its output.
+type Terminal struct {
+	mixins.TextBox
+
+	driver gxui.Driver
+	theme  *basic.Theme
+	font   gxui.Font
+
+	lock   sync.Mutex
+	screen *Screen
+	pty    *os.File
+	cmd    *exec.Cmd
+	exited bool
+
+	// pending is set while an update is waiting to run on the UI
+	// goroutine, so that a burst of output only causes one update.
+	pending int32
+}
+
+// New creates a Terminal.  The shell will not be started until Start
+// is called.
+func New(driver gxui.Driver, theme *basic.Theme) *Terminal {
+	t := &Terminal{
+		driver: driver,
+		theme:  theme,
+		font:   theme.DefaultMonospaceFont(),
+		screen: NewScreen(defaultRows, defaultCols),
+	}
+	t.TextBox.Init(t, driver, theme, t.font)
+	t.SetTextColor(theme.TextBoxDefaultStyle.FontColor)
+	t.SetMargin(math.Spacing{L: 3, T: 3, R: 3, B: 3})
+	t.SetPadding(math.Spacing{L: 3, T: 3, R: 3, B: 3})
+	t.SetBackgroundBrush(theme.TextBoxDefaultStyle.Brush)
+	t.SetDesiredWidth(math.MaxSize.W)
+	t.SetMultiline(true)
+	return t
+}
+
+// Start starts the user's shell in dir, using environ as its
+// environment.
+func (t *Terminal) Start(dir string, environ []string) error {
+	cmd := exec.Command(defaultShell())
+	cmd.Dir = dir
+	// environ is copied so that appending to it can't write to the
+	// caller's backing array.
+	cmd.Env = append(append([]string(nil), environ...), "TERM=vt100")
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	rows, cols := t.screen.Size()
+	f, err := startPTY(cmd, rows, cols)
+	if err != nil {
+		return err
+	}
+	t.pty = f
+	t.cmd = cmd
+	go t.read(f)
+	go t.wait(cmd)
+	return nil
+}
+
+// Exited returns whether or not the shell has exited.
+func (t *Terminal) Exited() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.exited
+}
+
+// Close kills the shell and closes its pty.
+func (t *Terminal) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.cmd != nil && !t.exited {
+		t.cmd.Process.Kill()
+	}
+	if t.pty == nil {
+		return nil
+	}
+	return t.pty.Close()
+}
+
+func (t *Terminal) read(f *os.File) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			t.lock.Lock()
+			t.screen.Write(buf[:n])
+			t.lock.Unlock()
+			t.refresh()
+		}
+		if err != nil {
+			// Linux returns EIO from the pty once the shell exits, so
+			// we can't tell the difference between an exit and a real
+			// error here.  wait will report the exit.
+			return
+		}
+	}
+}
+
+func (t *Terminal) wait(cmd *exec.Cmd) {
+	err := cmd.Wait()
+	t.lock.Lock()
+	t.exited = true
+	msg := "\r\n[process exited]"
+	if err != nil {
+		msg = "\r\n[process exited: " + err.Error() + "]"
+	}
+	t.screen.Write([]byte(msg))
+	t.lock.Unlock()
+	t.refresh()
+}
+
+func (t *Terminal) refresh() {
+	if !atomic.CompareAndSwapInt32(&t.pending, 0, 1) {
+		return
+	}
+	t.driver.Call(func() {
+		atomic.StoreInt32(&t.pending, 0)
+		t.update()
+	})
+}
+
+func (t *Terminal) update() {
+	t.lock.Lock()
+	text := t.screen.String()
+	caret := t.screen.CursorOffset()
+	t.lock.Unlock()
+
+	t.SetText(text)
+	t.Controller().SetCaret(caret)
+	t.ScrollToRune(caret)
+}
+
+func (t *Terminal) send(s string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.pty == nil || t.exited {
+		return
+	}
+	if _, err := t.pty.Write([]byte(s)); err != nil {
+		log.Printf("Error writing to terminal: %s", err)
+	}
+}
+
+// SetSize resizes t's pty to match the number of lines and columns
+// that fit in size.
+func (t *Terminal) SetSize(size math.Size) {
+	t.TextBox.SetSize(size)
+	glyph := t.font.GlyphMaxSize()
+	if glyph.W == 0 || glyph.H == 0 {
+		return
+	}
+	inner := size.Contract(t.Padding()).Contract(t.Margin())
+	t.resize(inner.H/glyph.H, inner.W/glyph.W)
+}
+
+func (t *Terminal) resize(rows, cols int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if r, c := t.screen.Size(); r == rows && c == cols {
+		return
+	}
+	t.screen.Resize(rows, cols)
+	if t.pty == nil || t.exited {
+		return
+	}
+	rows, cols = t.screen.Size()
+	if err := resizePTY(t.pty, rows, cols); err != nil {
+		log.Printf("Error resizing terminal: %s", err)
+	}
+}
+
+// Elements returns nil, since none of t's children are useful to
+// commands.
+func (t *Terminal) Elements() []interface{} {
+	return nil
+}
+
+func (t *Terminal) KeyPress(event gxui.KeyboardEvent) bool {
+	seq, ok := keySequence(event)
+	if !ok {
+		return false
+	}
+	t.send(seq)
+	return true
+}
+
+func (t *Terminal) KeyStroke(event gxui.KeyStrokeEvent) bool {
+	if event.Modifier&^gxui.ModShift != 0 {
+		return false
+	}
+	t.send(string(event.Character))
+	return true
+}
+
+func (t *Terminal) Paint(c gxui.Canvas) {
+	t.TextBox.Paint(c)
+
+	if t.HasFocus() {
+		r := t.Size().Rect()
+		c.DrawRoundedRect(r, 3, 3, 3, 3, t.theme.FocusedStyle.Pen, t.theme.FocusedStyle.Brush)
+	}
+}
+
+// controlKeys maps the letter keys to the control characters that
+// Ctrl+A through Ctrl+Z send, 0x01 through 0x1a.
+var controlKeys = map[gxui.KeyboardKey]byte{
+	gxui.KeyA: 0x01,
+	gxui.KeyB: 0x02,
+	gxui.KeyC: 0x03,
+	gxui.KeyD: 0x04,
+	gxui.KeyE: 0x05,
+	gxui.KeyF: 0x06,
+	gxui.KeyG: 0x07,
+	gxui.KeyH: 0x08,
+	gxui.KeyI: 0x09,
+	gxui.KeyJ: 0x0a,
+	gxui.KeyK: 0x0b,
+	gxui.KeyL: 0x0c,
+	gxui.KeyM: 0x0d,
+	gxui.KeyN: 0x0e,
+	gxui.KeyO: 0x0f,
+	gxui.KeyP: 0x10,
+	gxui.KeyQ: 0x11,
+	gxui.KeyR: 0x12,
+	gxui.KeyS: 0x13,
+	gxui.KeyT: 0x14,
+	gxui.KeyU: 0x15,
+	gxui.KeyV: 0x16,
+	gxui.KeyW: 0x17,
+	gxui.KeyX: 0x18,
+	gxui.KeyY: 0x19,
+	gxui.KeyZ: 0x1a,
+}
+
+// keySequence returns the bytes that a terminal would send for
+// event.  Key events with modifiers other than a lone ctrl or shift
+// are left for the commander to handle, so that key bindings still
+// work while the terminal is focused.
+func keySequence(event gxui.KeyboardEvent) (string, bool) {
+	if event.Modifier == gxui.ModControl {
+		if b, ok := controlKeys[event.Key]; ok {
+			return string(rune(b)), true
+		}
+	}
+	if event.Modifier&^gxui.ModShift != 0 {
+		return "", false
+	}
+	switch event.Key {
+	case gxui.KeyEnter:
+		return "\r", true
+	case gxui.KeyBackspace:
+		return "\x7f", true
+	case gxui.KeyTab:
+		return "\t", true
+	case gxui.KeyEscape:
+		return "\x1b", true
+	case gxui.KeyUp:
+		return "\x1b[A", true
+	case gxui.KeyDown:
+		return "\x1b[B", true
+	case gxui.KeyRight:
+		return "\x1b[C", true
+	case gxui.KeyLeft:
+		return "\x1b[D", true
+	case gxui.KeyHome:
+		return "\x1b[H", true
+	case gxui.KeyEnd:
+		return "\x1b[F", true
+	case gxui.KeyDelete:
+		return "\x1b[3~", true
+	case gxui.KeyPageUp:
+		return "\x1b[5~", true
+	case gxui.KeyPageDown:
+		return "\x1b[6~", true
+	}
+	return "", false
+}
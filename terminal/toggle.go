@@ -0,0 +1,117 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package terminal
+
+import (
+	"fmt"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/gxui/themes/basic"
+	"github.com/nelsam/vidar/commander/bind"
+	"github.com/nelsam/vidar/commander/input"
+	"github.com/nelsam/vidar/plugin/status"
+	"github.com/nelsam/vidar/setting"
+)
+
+// A Paneler is a type that can display panels below the editor.
+type Paneler interface {
+	ShowPanel(gxui.Control)
+	HidePanel(gxui.Control)
+	HasPanel(gxui.Control) bool
+}
+
+// A Projecter is a type that knows which project is currently open.
+type Projecter interface {
+	Project() setting.Project
+}
+
+// Toggle is a bind.MultiOp that shows or hides a terminal below the
+// editor.  The terminal's shell is started in the current project's
+// directory the first time it is shown, and keeps running while it
+// is hidden.
+type Toggle struct {
+	status.General
+
+	driver gxui.Driver
+	theme  *basic.Theme
+	term   *Terminal
+
+	paneler Paneler
+	proj    Projecter
+	editor  input.Editor
+}
+
+// NewToggle returns a *Toggle that will create its terminal using
+// driver and theme.
+func NewToggle(driver gxui.Driver, theme *basic.Theme) *Toggle {
+	return &Toggle{
+		driver: driver,
+		theme:  theme,
+	}
+}
+
+func (t *Toggle) Name() string {
+	return "toggle-terminal"
+}
+
+func (t *Toggle) Menu() string {
+	return "View"
+}
+
+func (t *Toggle) Defaults() []fmt.Stringer {
+	return []fmt.Stringer{gxui.KeyboardEvent{
+		Modifier: gxui.ModControl | gxui.ModShift,
+		Key:      gxui.KeyT,
+	}}
+}
+
+func (t *Toggle) Reset() {
+	t.General.Clear()
+	t.paneler = nil
+	t.proj = nil
+	t.editor = nil
+}
+
+func (t *Toggle) Store(elem interface{}) bind.Status {
+	switch src := elem.(type) {
+	case Paneler:
+		t.paneler = src
+	case Projecter:
+		t.proj = src
+	case input.Editor:
+		t.editor = src
+	}
+	if t.paneler == nil || t.proj == nil {
+		return bind.Waiting
+	}
+	if t.editor == nil {
+		// The editor is only used to return focus when the
+		// terminal is hidden, so we can run without it.
+		return bind.Executing
+	}
+	return bind.Done
+}
+
+func (t *Toggle) Exec() error {
+	if t.term != nil && t.paneler.HasPanel(t.term) {
+		t.paneler.HidePanel(t.term)
+		if t.editor != nil {
+			gxui.SetFocus(t.editor.(gxui.Focusable))
+		}
+		return nil
+	}
+	if t.term == nil || t.term.Exited() {
+		term := New(t.driver, t.theme)
+		proj := t.proj.Project()
+		if err := term.Start(proj.Path, proj.Environ()); err != nil {
+			t.Err = fmt.Sprintf("Could not start terminal: %s", err)
+			return err
+		}
+		t.term = term
+	}
+	t.paneler.ShowPanel(t.term)
+	gxui.SetFocus(t.term)
+	return nil
+}
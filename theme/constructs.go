@@ -21,6 +21,15 @@ const (
 
 	Bad
 
+	// Occurrence is used for the other occurrences of the identifier
+	// under the caret.  It comes before Match so that find matches
+	// are drawn over it.
+	Occurrence
+
+	// Match is used for the matches of a search (e.g. from the find
+	// command) while the search is open.
+	Match
+
 	// ScopePair is a much higher value to provide extra space
 	// for other language constructs (e.g. for languages that
 	// have constructs that Go doesn't).  Because ScopePairs are
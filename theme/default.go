@@ -6,6 +6,32 @@ package theme
 
 var Default = Theme{
 	Rainbow: DefaultRainbow,
+	Diagnostics: DiagnosticColors{
+		Error: Color{
+			R: 0.9,
+			G: 0,
+			B: 0.2,
+			A: 1,
+		},
+		Warning: Color{
+			R: 0.9,
+			G: 0.6,
+			B: 0,
+			A: 1,
+		},
+		Info: Color{
+			R: 0,
+			G: 0.6,
+			B: 0.8,
+			A: 1,
+		},
+		Hint: Color{
+			R: 0.6,
+			G: 0.6,
+			B: 0.6,
+			A: 1,
+		},
+	},
 	Constructs: ConstructHighlights{
 		Bad: Highlight{
 			Foreground: Color{
@@ -21,6 +47,26 @@ var Default = Theme{
 				A: 1,
 			},
 		},
+		Match: Highlight{
+			Foreground: Color{
+				R: 0.1,
+				G: 0.1,
+				B: 0.1,
+				A: 1,
+			},
+			Background: Color{
+				R: 0.9,
+				G: 0.7,
+				B: 0.2,
+				A: 1,
+			},
+		},
+		Occurrence: Highlight{Background: Color{
+			R: 0.3,
+			G: 0.3,
+			B: 0.4,
+			A: 1,
+		}},
 		Ident: Highlight{Foreground: Color{
 			R: 0.9,
 			G: 0.9,
@@ -76,4 +122,24 @@ var Default = Theme{
 			A: 1.0,
 		}},
 	},
+	UI: UIColors{
+		CurrentLine: Color{
+			R: 1,
+			G: 1,
+			B: 1,
+			A: 0.05,
+		},
+		Caret: Color{
+			R: 0.9,
+			G: 0.9,
+			B: 0.9,
+			A: 1,
+		},
+		InactiveCaret: Color{
+			R: 0.5,
+			G: 0.5,
+			B: 0.5,
+			A: 1,
+		},
+	},
 }
@@ -0,0 +1,258 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package theme
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nelsam/vidar/setting/config"
+)
+
+// DefaultName is the name of the Default theme.  It can be
+// overridden by a theme file of the same name.
+const DefaultName = "default"
+
+// constructNames are the keys used for each LanguageConstruct in
+// theme files.
+var constructNames = map[string]LanguageConstruct{
+	"keyword":    Keyword,
+	"builtin":    Builtin,
+	"func":       Func,
+	"type":       Type,
+	"ident":      Ident,
+	"string":     String,
+	"num":        Num,
+	"nil":        Nil,
+	"comment":    Comment,
+	"bad":        Bad,
+	"match":      Match,
+	"occurrence": Occurrence,
+}
+
+// extensions are the file extensions that theme files may use.
+var extensions = []string{".toml", ".yaml", ".yml", ".json"}
+
+type opener struct{}
+
+func (opener) Open(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+func (opener) Create(path string) (io.WriteCloser, error) {
+	return os.Create(path)
+}
+
+// fileHighlight is a Highlight as it is written in theme files.
+// Colors are hex strings, e.g. "#0099cc" or "#0099ccff".
+type fileHighlight struct {
+	Foreground, Background string
+}
+
+type fileRainbow struct {
+	Min, Max fileHighlight
+	Palette  []fileHighlight
+	Depths   []fileHighlight
+}
+
+type fileDiagnostics struct {
+	Error, Warning, Info, Hint string
+}
+
+type fileUI struct {
+	Background, Foreground string
+	CurrentLine            string
+	Caret, InactiveCaret   string
+}
+
+// fileColor is a color value from a theme file and the Color that it
+// should be parsed into.
+type fileColor struct {
+	name  string
+	value string
+	dst   *Color
+}
+
+// Names returns the names of the themes in dir, sorted.  DefaultName
+// is always included.
+func Names(dir string) []string {
+	names := []string{DefaultName}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return names
+	}
+	seen := map[string]bool{DefaultName: true}
+	for _, i := range infos {
+		if i.IsDir() || !themeFile(i.Name()) {
+			continue
+		}
+		name := strings.TrimSuffix(i.Name(), filepath.Ext(i.Name()))
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// IsFile returns whether or not path is the file for the theme
+// named name.
+func IsFile(path, name string) bool {
+	base := filepath.Base(path)
+	return themeFile(base) && strings.TrimSuffix(base, filepath.Ext(base)) == name
+}
+
+func themeFile(name string) bool {
+	ext := filepath.Ext(name)
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Load loads the theme named name from dir.  Any colors that the
+// theme file doesn't set are taken from Default.  If there is no
+// file for DefaultName, Default is returned.
+func Load(dir, name string) (Theme, error) {
+	t := Default.copy()
+	c, err := config.New(opener{}, name, dir)
+	if err != nil {
+		return t, err
+	}
+	if len(c.Keys()) == 0 {
+		if name == DefaultName {
+			return t, nil
+		}
+		return t, fmt.Errorf("theme %s not found in %s", name, dir)
+	}
+	c.SetDefault("constructs", map[string]fileHighlight(nil))
+	c.SetDefault("rainbow", fileRainbow{})
+	c.SetDefault("diagnostics", fileDiagnostics{})
+	c.SetDefault("ui", fileUI{})
+
+	constructs, _ := c.Get("constructs").(map[string]fileHighlight)
+	for k, h := range constructs {
+		construct, ok := constructNames[strings.ToLower(k)]
+		if !ok {
+			return t, fmt.Errorf("theme %s: unknown construct %s", name, k)
+		}
+		if t.Constructs[construct], err = h.apply(t.Constructs[construct]); err != nil {
+			return t, fmt.Errorf("theme %s: construct %s: %s", name, k, err)
+		}
+	}
+
+	rainbow, _ := c.Get("rainbow").(fileRainbow)
+	if t.Rainbow.Range.Min, err = rainbow.Min.apply(t.Rainbow.Range.Min); err != nil {
+		return t, fmt.Errorf("theme %s: rainbow min: %s", name, err)
+	}
+	if t.Rainbow.Range.Max, err = rainbow.Max.apply(t.Rainbow.Range.Max); err != nil {
+		return t, fmt.Errorf("theme %s: rainbow max: %s", name, err)
+	}
+	if len(rainbow.Palette) > 0 {
+		t.Rainbow.Available = make([]Highlight, 0, len(rainbow.Palette))
+		for i, h := range rainbow.Palette {
+			highlight, err := h.apply(Highlight{})
+			if err != nil {
+				return t, fmt.Errorf("theme %s: rainbow palette %d: %s", name, i, err)
+			}
+			t.Rainbow.Available = append(t.Rainbow.Available, highlight)
+		}
+	}
+	if len(rainbow.Depths) > 0 {
+		t.Rainbow.Depths = make([]Highlight, 0, len(rainbow.Depths))
+		for i, h := range rainbow.Depths {
+			highlight, err := h.apply(Highlight{})
+			if err != nil {
+				return t, fmt.Errorf("theme %s: rainbow depth %d: %s", name, i, err)
+			}
+			t.Rainbow.Depths = append(t.Rainbow.Depths, highlight)
+		}
+	}
+
+	diag, _ := c.Get("diagnostics").(fileDiagnostics)
+	ui, _ := c.Get("ui").(fileUI)
+	colors := []fileColor{
+		{"diagnostics error", diag.Error, &t.Diagnostics.Error},
+		{"diagnostics warning", diag.Warning, &t.Diagnostics.Warning},
+		{"diagnostics info", diag.Info, &t.Diagnostics.Info},
+		{"diagnostics hint", diag.Hint, &t.Diagnostics.Hint},
+		{"ui background", ui.Background, &t.UI.Background},
+		{"ui foreground", ui.Foreground, &t.UI.Foreground},
+		{"ui currentline", ui.CurrentLine, &t.UI.CurrentLine},
+		{"ui caret", ui.Caret, &t.UI.Caret},
+		{"ui inactivecaret", ui.InactiveCaret, &t.UI.InactiveCaret},
+	}
+	for _, fc := range colors {
+		if fc.value == "" {
+			continue
+		}
+		if *fc.dst, err = ParseColor(fc.value); err != nil {
+			return t, fmt.Errorf("theme %s: %s: %s", name, fc.name, err)
+		}
+	}
+	return t, nil
+}
+
+// apply returns base with any colors that are set in h replaced.
+func (h fileHighlight) apply(base Highlight) (Highlight, error) {
+	var err error
+	if h.Foreground != "" {
+		if base.Foreground, err = ParseColor(h.Foreground); err != nil {
+			return base, err
+		}
+	}
+	if h.Background != "" {
+		if base.Background, err = ParseColor(h.Background); err != nil {
+			return base, err
+		}
+	}
+	return base, nil
+}
+
+// ParseColor parses a hex color string in the format "#rrggbb" or
+// "#rrggbbaa".  Colors without an alpha value are opaque.
+func ParseColor(s string) (Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return Color{}, fmt.Errorf("color %q is not in #rrggbb or #rrggbbaa format", s)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("color %q is not valid hex: %s", s, err)
+	}
+	return Color{
+		R: float32(v>>24&0xff) / 0xff,
+		G: float32(v>>16&0xff) / 0xff,
+		B: float32(v>>8&0xff) / 0xff,
+		A: float32(v&0xff) / 0xff,
+	}, nil
+}
+
+// copy returns a copy of t that doesn't share any maps or slices
+// with t.
+func (t Theme) copy() Theme {
+	c := t
+	c.Constructs = make(ConstructHighlights, len(t.Constructs))
+	for k, v := range t.Constructs {
+		c.Constructs[k] = v
+	}
+	c.Rainbow.Available = append([]Highlight(nil), t.Rainbow.Available...)
+	c.Rainbow.Depths = append([]Highlight(nil), t.Rainbow.Depths...)
+	c.Rainbow.inUse = nil
+	return c
+}
@@ -0,0 +1,146 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package theme_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nelsam/vidar/theme"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+type Expectation = expect.Expectation
+
+var (
+	Not          = matchers.Not
+	HaveOccurred = matchers.HaveOccurred
+	Equal        = matchers.Equal
+	HaveLen      = matchers.HaveLen
+)
+
+func TestLoad(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) (Expectation, string) {
+		dir, err := ioutil.TempDir("", "vidar-themes")
+		if err != nil {
+			t.Fatalf("Could not create temp dir: %s", err)
+		}
+		return expect.New(t), dir
+	})
+
+	o.AfterEach(func(expect Expectation, dir string) {
+		os.RemoveAll(dir)
+	})
+
+	write := func(expect Expectation, path, contents string) {
+		err := ioutil.WriteFile(path, []byte(contents), 0600)
+		expect(err).To(Not(HaveOccurred()))
+	}
+
+	o.Spec("it returns the default theme when there is no default theme file", func(expect Expectation, dir string) {
+		t, err := theme.Load(dir, theme.DefaultName)
+		expect(err).To(Not(HaveOccurred()))
+		expect(t).To(Equal(theme.Default))
+	})
+
+	o.Spec("it errors for missing themes", func(expect Expectation, dir string) {
+		_, err := theme.Load(dir, "missing")
+		expect(err).To(HaveOccurred())
+	})
+
+	o.Spec("it overrides default colors with colors from the theme file", func(expect Expectation, dir string) {
+		write(expect, filepath.Join(dir, "light.toml"), `
+[constructs.keyword]
+foreground = "#ff0000"
+
+[constructs.Comment]
+foreground = "#00ff0080"
+background = "#000000"
+
+[rainbow]
+palette = [{foreground = "#0000ff"}]
+
+[diagnostics]
+error = "#ffffff"
+
+[ui]
+background = "#ffffff"
+currentline = "#00000010"
+`)
+		t, err := theme.Load(dir, "light")
+		expect(err).To(Not(HaveOccurred()))
+
+		expect(t.Constructs[theme.Keyword].Foreground).To(Equal(theme.Color{R: 1, A: 1}))
+		expect(t.Constructs[theme.Comment]).To(Equal(theme.Highlight{
+			Foreground: theme.Color{G: 1, A: float32(0x80) / 0xff},
+			Background: theme.Color{A: 1},
+		}))
+		expect(t.Constructs[theme.String]).To(Equal(theme.Default.Constructs[theme.String]))
+
+		expect(t.Rainbow.Available).To(HaveLen(1))
+		expect(t.Rainbow.Available[0].Foreground).To(Equal(theme.Color{B: 1, A: 1}))
+		expect(t.Rainbow.Range).To(Equal(theme.Default.Rainbow.Range))
+
+		expect(t.Diagnostics.Error).To(Equal(theme.Color{R: 1, G: 1, B: 1, A: 1}))
+		expect(t.Diagnostics.Warning).To(Equal(theme.Default.Diagnostics.Warning))
+		expect(t.UI.Background).To(Equal(theme.Color{R: 1, G: 1, B: 1, A: 1}))
+		expect(t.UI.Foreground).To(Equal(theme.Color{}))
+		expect(t.UI.CurrentLine).To(Equal(theme.Color{A: float32(0x10) / 0xff}))
+		expect(t.UI.Caret).To(Equal(theme.Default.UI.Caret))
+	})
+
+	o.Spec("it loads rainbow colors for each nesting depth", func(expect Expectation, dir string) {
+		write(expect, filepath.Join(dir, "depths.toml"), `
+[rainbow]
+depths = [{foreground = "#ff0000"}, {foreground = "#00ff00"}]
+`)
+		t, err := theme.Load(dir, "depths")
+		expect(err).To(Not(HaveOccurred()))
+
+		expect(t.Rainbow.Depths).To(HaveLen(2))
+		h, ok := t.Rainbow.Depth(theme.ScopePair + 1)
+		expect(ok).To(Equal(true))
+		expect(h.Foreground).To(Equal(theme.Color{G: 1, A: 1}))
+		h, ok = t.Rainbow.Depth(theme.ScopePair + 2)
+		expect(ok).To(Equal(true))
+		expect(h.Foreground).To(Equal(theme.Color{R: 1, A: 1}))
+		_, ok = t.Rainbow.Depth(theme.Keyword)
+		expect(ok).To(Equal(false))
+	})
+
+	o.Spec("it doesn't modify the default theme", func(expect Expectation, dir string) {
+		write(expect, filepath.Join(dir, "light.json"), `{"constructs": {"keyword": {"foreground": "#ff0000"}}}`)
+		before := theme.Default.Constructs[theme.Keyword]
+		_, err := theme.Load(dir, "light")
+		expect(err).To(Not(HaveOccurred()))
+		expect(theme.Default.Constructs[theme.Keyword]).To(Equal(before))
+	})
+
+	o.Spec("it errors for invalid colors", func(expect Expectation, dir string) {
+		write(expect, filepath.Join(dir, "bad.toml"), "[ui]\nbackground = \"white\"\n")
+		_, err := theme.Load(dir, "bad")
+		expect(err).To(HaveOccurred())
+	})
+
+	o.Spec("it errors for unknown constructs", func(expect Expectation, dir string) {
+		write(expect, filepath.Join(dir, "bad.toml"), "[constructs.bogus]\nforeground = \"#ffffff\"\n")
+		_, err := theme.Load(dir, "bad")
+		expect(err).To(HaveOccurred())
+	})
+
+	o.Spec("it lists theme names", func(expect Expectation, dir string) {
+		write(expect, filepath.Join(dir, "b.yaml"), "")
+		write(expect, filepath.Join(dir, "a.toml"), "")
+		write(expect, filepath.Join(dir, "notes.txt"), "")
+		expect(theme.Names(dir)).To(Equal([]string{theme.DefaultName, "a", "b"}))
+	})
+}
@@ -105,6 +105,21 @@ func (c HighlightRange) New() Highlight {
 type Rainbow struct {
 	Range            HighlightRange
 	Available, inUse []Highlight
+
+	// Depths, if set, are the highlights for scope pairs at each
+	// nesting depth, starting with ScopePair.  Depths past the end
+	// of Depths start over at the beginning.
+	Depths []Highlight
+}
+
+// Depth returns the highlight for construct from r.Depths.  It
+// returns false if construct isn't a scope pair or r.Depths is
+// empty.
+func (r *Rainbow) Depth(construct LanguageConstruct) (Highlight, bool) {
+	if construct < ScopePair || len(r.Depths) == 0 {
+		return Highlight{}, false
+	}
+	return r.Depths[int(construct-ScopePair)%len(r.Depths)], true
 }
 
 func (r *Rainbow) Reset() {
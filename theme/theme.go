@@ -25,4 +25,33 @@ type Theme struct {
 	//
 	// See the gosyntax plugin for an example.
 	Rainbow Rainbow
+
+	// Diagnostics are the colors used to mark problems in the
+	// text.
+	Diagnostics DiagnosticColors
+
+	// UI holds the colors used for the editor itself, rather than
+	// its text.
+	UI UIColors
+}
+
+// DiagnosticColors are the colors used for each severity of
+// diagnostic.
+type DiagnosticColors struct {
+	Error, Warning, Info, Hint Color
+}
+
+// UIColors are the colors used for editor backgrounds and plain text.
+// Background and Foreground are taken from the gxui theme instead if
+// they're left as their zero value.
+type UIColors struct {
+	Background, Foreground Color
+
+	// CurrentLine is the background of the lines that carets are
+	// on.
+	CurrentLine Color
+
+	// Caret is the color of the carets in the focused editor, and
+	// InactiveCaret is the color of the carets in other editors.
+	Caret, InactiveCaret Color
 }
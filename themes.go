@@ -0,0 +1,73 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/nelsam/gxui"
+	"github.com/nelsam/vidar/fsw"
+	"github.com/nelsam/vidar/setting"
+	"github.com/nelsam/vidar/theme"
+)
+
+// syntaxThemer is a type that can change its syntax theme.
+type syntaxThemer interface {
+	SetSyntaxTheme(theme.Theme)
+}
+
+// loadTheme loads the theme that is chosen in the settings, falling
+// back to theme.Default if it can't be loaded.
+func loadTheme() theme.Theme {
+	name := setting.Theme()
+	t, err := theme.Load(setting.ThemesDir(), name)
+	if err != nil {
+		log.Printf("Error loading theme %s: %s", name, err)
+		return theme.Default
+	}
+	return t
+}
+
+// watchThemes reloads the current theme and applies it to target
+// whenever its file changes.
+func watchThemes(driver gxui.Driver, target syntaxThemer) {
+	dir := setting.ThemesDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.Printf("Error creating themes directory %s: %s", dir, err)
+		return
+	}
+	watcher, err := fsw.New()
+	if err != nil {
+		log.Printf("Error creating theme watcher: %s", err)
+		return
+	}
+	if err := watcher.Add(dir); err != nil {
+		log.Printf("Error watching themes directory %s: %s", dir, err)
+		return
+	}
+	go func() {
+		defer watcher.Close()
+		for {
+			ev, err := watcher.Next()
+			if err != nil {
+				log.Printf("Error from theme watcher: %s", err)
+				return
+			}
+			name := setting.Theme()
+			if !theme.IsFile(ev.Path, name) {
+				continue
+			}
+			t, err := theme.Load(dir, name)
+			if err != nil {
+				log.Printf("Error reloading theme %s: %s", name, err)
+				continue
+			}
+			driver.Call(func() {
+				target.SetSyntaxTheme(t)
+			})
+		}
+	}()
+}
@@ -0,0 +1,112 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+// Package todo finds comments that start with markers like TODO and
+// FIXME.
+package todo
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineLen is the longest line that a Scanner reads.  Files with
+// longer lines are most likely generated.
+const maxLineLen = 1 << 20
+
+// commentStart matches the tokens that start a comment in the
+// languages vidar is usually used for, along with the continuation
+// lines of block comments.
+const commentStart = `(?://+|#+|/\*+|<!--|--|;+|^\s*\*+)`
+
+// Item is a marked comment.
+type Item struct {
+	// Line and Col are the zero-based line and column (in runes)
+	// of the marker.
+	Line, Col int
+
+	// Marker is the marker that starts the comment, e.g. TODO.
+	Marker string
+
+	// Owner is the text in parentheses after the marker, if any,
+	// e.g. the name in TODO(name).
+	Owner string
+
+	// Text is the rest of the comment.
+	Text string
+}
+
+// Scanner finds marked comments.
+type Scanner struct {
+	re *regexp.Regexp
+}
+
+// New returns a Scanner which finds comments that start with any of
+// markers.  Markers are case sensitive and must be followed by a
+// colon, parentheses, whitespace, or the end of the line, so TODO
+// doesn't match TODOS.
+func New(markers []string) *Scanner {
+	var quoted []string
+	for _, m := range markers {
+		if m = strings.TrimSpace(m); m != "" {
+			quoted = append(quoted, regexp.QuoteMeta(m))
+		}
+	}
+	if len(quoted) == 0 {
+		return &Scanner{}
+	}
+	expr := commentStart + `\s*(` + strings.Join(quoted, "|") + `)(?:\(([^)]*)\))?(?::|\s|$)\s*(.*)`
+	return &Scanner{re: regexp.MustCompile(expr)}
+}
+
+// Scan returns the marked comments in the text read from r.  If the
+// text looks like a binary file, nothing is returned.
+func (s *Scanner) Scan(r io.Reader) []Item {
+	if s.re == nil {
+		return nil
+	}
+	var items []Item
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineLen)
+	for line := 0; scanner.Scan(); line++ {
+		b := scanner.Bytes()
+		if bytes.IndexByte(b, 0) != -1 {
+			return nil
+		}
+		loc := s.re.FindSubmatchIndex(b)
+		if loc == nil {
+			continue
+		}
+		items = append(items, Item{
+			Line:   line,
+			Col:    utf8.RuneCount(b[:loc[2]]),
+			Marker: string(b[loc[2]:loc[3]]),
+			Owner:  group(b, loc, 2),
+			Text:   trimClose(group(b, loc, 3)),
+		})
+	}
+	return items
+}
+
+// group returns the text of the nth group in loc, or an empty string
+// if it didn't match.
+func group(b []byte, loc []int, n int) string {
+	if loc[2*n] < 0 {
+		return ""
+	}
+	return string(b[loc[2*n]:loc[2*n+1]])
+}
+
+// trimClose removes the end of a block comment from the end of text.
+func trimClose(text string) string {
+	text = strings.TrimSpace(text)
+	for _, end := range []string{"*/", "-->"} {
+		text = strings.TrimSpace(strings.TrimSuffix(text, end))
+	}
+	return text
+}
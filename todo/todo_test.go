@@ -0,0 +1,80 @@
+// This is free and unencumbered software released into the public
+// domain.  For more information, see <http://unlicense.org> or the
+// accompanying UNLICENSE file.
+
+package todo_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nelsam/vidar/todo"
+	"github.com/poy/onpar"
+	"github.com/poy/onpar/expect"
+	"github.com/poy/onpar/matchers"
+)
+
+var (
+	equal   = matchers.Equal
+	haveLen = matchers.HaveLen
+)
+
+func TestScanner(t *testing.T) {
+	o := onpar.New()
+	defer o.Run(t)
+
+	o.BeforeEach(func(t *testing.T) expect.Expectation {
+		return expect.New(t)
+	})
+
+	o.Spec("it finds marked comments", func(expect expect.Expectation) {
+		s := todo.New([]string{"TODO", "FIXME"})
+		items := s.Scan(strings.NewReader("package foo\n\n// TODO: write foo\nfunc foo() {} // FIXME handle errors\n"))
+		expect(items).To(equal([]todo.Item{
+			{Line: 2, Col: 3, Marker: "TODO", Text: "write foo"},
+			{Line: 3, Col: 17, Marker: "FIXME", Text: "handle errors"},
+		}))
+	})
+
+	o.Spec("it reads owners in parentheses", func(expect expect.Expectation) {
+		s := todo.New([]string{"TODO"})
+		items := s.Scan(strings.NewReader("# TODO(sam): remove this\n"))
+		expect(items).To(equal([]todo.Item{
+			{Line: 0, Col: 2, Marker: "TODO", Owner: "sam", Text: "remove this"},
+		}))
+	})
+
+	o.Spec("it finds markers in block comments", func(expect expect.Expectation) {
+		s := todo.New([]string{"HACK"})
+		items := s.Scan(strings.NewReader("/* HACK: one line */\n/*\n * HACK:\n */\n<!-- HACK: html -->\n"))
+		expect(items).To(haveLen(3))
+		expect(items[0].Text).To(equal("one line"))
+		expect(items[1].Line).To(equal(2))
+		expect(items[1].Text).To(equal(""))
+		expect(items[2].Text).To(equal("html"))
+	})
+
+	o.Spec("it ignores markers outside of comments and longer words", func(expect expect.Expectation) {
+		s := todo.New([]string{"TODO"})
+		items := s.Scan(strings.NewReader("TODO := 1\n// TODOS are tracked elsewhere\n// todo: lower case\n"))
+		expect(items).To(haveLen(0))
+	})
+
+	o.Spec("it supports markers with punctuation", func(expect expect.Expectation) {
+		s := todo.New([]string{"@todo", "XXX"})
+		items := s.Scan(strings.NewReader("; @todo check this\n-- XXX\n"))
+		expect(items).To(haveLen(2))
+		expect(items[0].Marker).To(equal("@todo"))
+		expect(items[1].Marker).To(equal("XXX"))
+	})
+
+	o.Spec("it finds nothing without markers", func(expect expect.Expectation) {
+		s := todo.New([]string{"", " "})
+		expect(s.Scan(strings.NewReader("// TODO: foo\n"))).To(haveLen(0))
+	})
+
+	o.Spec("it skips binary files", func(expect expect.Expectation) {
+		s := todo.New([]string{"TODO"})
+		expect(s.Scan(strings.NewReader("// TODO: foo\n\x00\x01\n"))).To(haveLen(0))
+	})
+}
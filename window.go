@@ -11,6 +11,11 @@ import (
 
 	"github.com/nelsam/gxui"
 	"github.com/nelsam/vidar/asset"
+	"github.com/nelsam/vidar/command/focus"
+	"github.com/nelsam/vidar/commander"
+	"github.com/nelsam/vidar/controller"
+	"github.com/nelsam/vidar/editor"
+	"github.com/nelsam/vidar/theme"
 )
 
 // icon returns the image.Image to be used as vidar's icon.
@@ -27,12 +32,20 @@ func icon() image.Image {
 // some child types.
 type window struct {
 	gxui.Window
-	child interface{}
+	theme  gxui.Theme
+	child  interface{}
+	editor syntaxThemer
+
+	app      *app
+	primary  bool
+	cmdr     *commander.Commander
+	projects *editor.MultiProjectEditor
 }
 
 func newWindow(t gxui.Theme) *window {
 	w := &window{
 		Window: t.CreateWindow(1600, 800, "Vidar Text Editor"),
+		theme:  t,
 	}
 	w.SetIcon(icon())
 	return w
@@ -41,3 +54,48 @@ func newWindow(t gxui.Theme) *window {
 func (w *window) Elements() []interface{} {
 	return []interface{}{w.child}
 }
+
+// SetSyntaxTheme applies t's background to the window and passes t
+// on to the editor.
+func (w *window) SetSyntaxTheme(t theme.Theme) {
+	bg := background
+	if t.UI.Background != (theme.Color{}) {
+		bg = gxui.Color(t.UI.Background)
+	}
+	w.SetBackgroundBrush(gxui.CreateBrush(bg))
+	if w.editor != nil {
+		w.editor.SetSyntaxTheme(t)
+	}
+}
+
+// SetFont makes font the default font for controls created later and
+// passes it on to every control in the window.
+func (w *window) SetFont(font gxui.Font) {
+	w.theme.SetDefaultMonospaceFont(font)
+	w.theme.SetDefaultFont(font)
+	for _, child := range w.Children() {
+		controller.SetFont(child.Control, font)
+	}
+}
+
+// OpenWindow opens another window, which starts out empty.
+func (w *window) OpenWindow() {
+	w.app.openWindow(false)
+}
+
+// OpenInOtherWindow opens path in the window after w, with the caret
+// at offset.  A new window is opened if w is the only one.
+func (w *window) OpenInOtherWindow(path string, offset int) {
+	target := w.app.after(w)
+	if target == nil {
+		target = w.app.openWindow(false)
+	}
+	opener := target.cmdr.Bindable("focus-location").(*focus.Location)
+	target.cmdr.Execute(opener.For(focus.Path(path), focus.Offset(offset)))
+}
+
+// SaveSession saves the session of the primary window, so that
+// quitting from any window keeps it.
+func (w *window) SaveSession() {
+	w.app.saveSession()
+}